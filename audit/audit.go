@@ -0,0 +1,341 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package audit keeps a tamper-evident log of the keygen and resharing ceremonies of a party, for the compliance
+// reviews of a custody service: who took part, with which threshold and curve, and which public key came out.
+//
+// Every entry of a log holds the hash of the previous one, so that an entry cannot be modified, removed or inserted
+// without breaking the chain from there on. The parties of a ceremony may also sign its digest with their ed25519
+// keys, e.g. exchanged over the channel that ran the ceremony, and every party logs the signatures of all of them: the
+// log of one party then proves that the others agreed on the ceremony too.
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kisdex/mpc-lib/crypto"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+// Version is the format version of the entries written by this package
+const Version = 1
+
+const (
+	ceremonyDigestTag = "mpc-lib audit ceremony v1"
+	entryHashTag      = "mpc-lib audit entry v1"
+)
+
+// CeremonyKind is what a ceremony did to a key
+type CeremonyKind string
+
+const (
+	Keygen    CeremonyKind = "keygen"
+	Resharing CeremonyKind = "resharing"
+)
+
+type (
+	// Ceremony describes a keygen or a resharing, the same for all of its parties
+	Ceremony struct {
+		Kind      CeremonyKind  `json:"kind"`
+		SessionID string        `json:"session_id"`
+		Curve     tss.CurveName `json:"curve"`
+		// Parties are the ids of the parties of a keygen, or of the old committee of a resharing
+		Parties   []string `json:"parties"`
+		Threshold int      `json:"threshold"`
+		// NewParties and NewThreshold are those of the new committee of a resharing
+		NewParties   []string `json:"new_parties,omitempty"`
+		NewThreshold int      `json:"new_threshold,omitempty"`
+		// PublicKey is the compressed SEC1 encoding of the public key, see crypto.ECPoint.Bytes
+		PublicKey []byte    `json:"public_key"`
+		Time      time.Time `json:"time"`
+	}
+
+	// Signature is the ed25519 signature of the digest of a ceremony by one of its parties
+	Signature struct {
+		PartyID   string `json:"party_id"`
+		Signature []byte `json:"signature"`
+	}
+
+	// Entry is a ceremony in the log of a party
+	Entry struct {
+		Version    uint32      `json:"version"`
+		Seq        uint64      `json:"seq"`
+		Ceremony   Ceremony    `json:"ceremony"`
+		Signatures []Signature `json:"signatures,omitempty"`
+		// PrevHash is the Hash of the previous entry, empty for the first one
+		PrevHash []byte `json:"prev_hash"`
+		// Hash is the SHA-256 of all of the other fields
+		Hash []byte `json:"hash"`
+	}
+
+	// Log appends entries to a writer as lines of JSON, each chained to the previous one
+	Log struct {
+		mtx  sync.Mutex
+		w    io.Writer
+		file *os.File
+		seq  uint64
+		prev []byte
+	}
+)
+
+// NewKeygenCeremony describes the keygen of the parties of `params` in the session `sessionID`, which resulted in
+// `publicKey`
+func NewKeygenCeremony(sessionID string, params *tss.Parameters, publicKey *crypto.ECPoint) (Ceremony, error) {
+	return newCeremony(Keygen, sessionID, params, publicKey)
+}
+
+// NewResharingCeremony describes the resharing of `publicKey` from the old committee of `params` to its new committee
+// in the session `sessionID`
+func NewResharingCeremony(sessionID string, params *tss.ReSharingParameters, publicKey *crypto.ECPoint) (Ceremony, error) {
+	c, err := newCeremony(Resharing, sessionID, params.Parameters, publicKey)
+	if err != nil {
+		return Ceremony{}, err
+	}
+	c.NewParties, c.NewThreshold = partyIDs(params.NewParties().IDs()), params.NewThreshold()
+	return c, nil
+}
+
+func newCeremony(kind CeremonyKind, sessionID string, params *tss.Parameters, publicKey *crypto.ECPoint) (Ceremony, error) {
+	if publicKey == nil || !publicKey.ValidateBasic() {
+		return Ceremony{}, errors.New("audit: the ceremony needs a valid public key")
+	}
+	curve, ok := tss.GetCurveName(params.EC())
+	if !ok {
+		return Ceremony{}, errors.New("audit: the curve of the ceremony is not registered")
+	}
+	return Ceremony{
+		Kind:      kind,
+		SessionID: sessionID,
+		Curve:     curve,
+		Parties:   partyIDs(params.Parties().IDs()),
+		Threshold: params.Threshold(),
+		PublicKey: publicKey.Bytes(),
+		Time:      time.Now().UTC(),
+	}, nil
+}
+
+func partyIDs(pIDs tss.SortedPartyIDs) []string {
+	ids := make([]string, len(pIDs))
+	for i, pID := range pIDs {
+		ids[i] = pID.Id
+	}
+	return ids
+}
+
+// Digest is the SHA-256 that the parties of the ceremony sign
+func (c Ceremony) Digest() ([]byte, error) {
+	body, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	return hashFields([]byte(ceremonyDigestTag), body), nil
+}
+
+// Sign signs the digest of the ceremony as the party `partyID`, which must be one of its parties
+func (c Ceremony) Sign(partyID string, key ed25519.PrivateKey) (Signature, error) {
+	if !c.hasParty(partyID) {
+		return Signature{}, fmt.Errorf("audit: %s is not a party of the ceremony", partyID)
+	}
+	digest, err := c.Digest()
+	if err != nil {
+		return Signature{}, err
+	}
+	return Signature{PartyID: partyID, Signature: ed25519.Sign(key, digest)}, nil
+}
+
+func (c Ceremony) hasParty(partyID string) bool {
+	for _, id := range append(append([]string{}, c.Parties...), c.NewParties...) {
+		if id == partyID {
+			return true
+		}
+	}
+	return false
+}
+
+// NewLog returns a log that writes to `w`, chained to `last`, the last entry already written to it if any
+func NewLog(w io.Writer, last *Entry) *Log {
+	l := &Log{w: w}
+	if last != nil {
+		l.seq, l.prev = last.Seq+1, last.Hash
+	}
+	return l
+}
+
+// OpenLog opens the log file at `path`, which it creates if it does not exist, after it verifies its chain
+func OpenLog(path string) (*Log, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := ReadLog(f)
+	if err == nil {
+		err = Verify(entries, nil)
+	}
+	if err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("audit: %s: %w", path, err)
+	}
+	var last *Entry
+	if 0 < len(entries) {
+		last = &entries[len(entries)-1]
+	}
+	l := NewLog(f, last)
+	l.file = f
+	return l, nil
+}
+
+// Append logs `ceremony` with the signatures of its parties and returns its entry
+func (l *Log) Append(ceremony Ceremony, signatures ...Signature) (*Entry, error) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	signatures = append([]Signature(nil), signatures...)
+	sort.Slice(signatures, func(i, j int) bool { return signatures[i].PartyID < signatures[j].PartyID })
+	e := &Entry{
+		Version:    Version,
+		Seq:        l.seq,
+		Ceremony:   ceremony,
+		Signatures: signatures,
+		PrevHash:   l.prev,
+	}
+	var err error
+	if e.Hash, err = e.hash(); err != nil {
+		return nil, err
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = l.w.Write(append(line, '\n')); err != nil {
+		return nil, err
+	}
+	if l.file != nil {
+		if err = l.file.Sync(); err != nil {
+			return nil, err
+		}
+	}
+	l.seq, l.prev = e.Seq+1, e.Hash
+	return e, nil
+}
+
+// Close closes the file of a log opened with OpenLog
+func (l *Log) Close() error {
+	if l.file == nil {
+		return nil
+	}
+	return l.file.Close()
+}
+
+// ReadLog reads the entries written by a Log
+func ReadLog(r io.Reader) ([]Entry, error) {
+	var entries []Entry
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("entry %d: %w", len(entries), err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
+
+// Verify checks the chain of `entries`, which must start at the first entry of the log, and the signatures that they
+// hold. With `keys`, the ed25519 public keys of the parties by id, every party of every ceremony must have signed it;
+// without, only the chain is checked.
+func Verify(entries []Entry, keys map[string]ed25519.PublicKey) error {
+	var prev []byte
+	for i, e := range entries {
+		if e.Version != Version {
+			return fmt.Errorf("entry %d: unsupported version %d", i, e.Version)
+		}
+		if e.Seq != uint64(i) {
+			return fmt.Errorf("entry %d: out of sequence with seq %d", i, e.Seq)
+		}
+		if !bytes.Equal(e.PrevHash, prev) {
+			return fmt.Errorf("entry %d: the chain is broken: it does not follow the previous entry", i)
+		}
+		hash, err := e.hash()
+		if err != nil {
+			return fmt.Errorf("entry %d: %w", i, err)
+		}
+		if !bytes.Equal(e.Hash, hash) {
+			return fmt.Errorf("entry %d: hash mismatch: the entry was modified", i)
+		}
+		if keys != nil {
+			if err = e.verifySignatures(keys); err != nil {
+				return fmt.Errorf("entry %d: %w", i, err)
+			}
+		}
+		prev = e.Hash
+	}
+	return nil
+}
+
+func (e *Entry) verifySignatures(keys map[string]ed25519.PublicKey) error {
+	digest, err := e.Ceremony.Digest()
+	if err != nil {
+		return err
+	}
+	signed := make(map[string]bool, len(e.Signatures))
+	for _, sig := range e.Signatures {
+		key, ok := keys[sig.PartyID]
+		if !ok || len(key) != ed25519.PublicKeySize {
+			return fmt.Errorf("no key for the signature of %s", sig.PartyID)
+		}
+		if !ed25519.Verify(key, digest, sig.Signature) {
+			return fmt.Errorf("the signature of %s is invalid", sig.PartyID)
+		}
+		signed[sig.PartyID] = true
+	}
+	for _, id := range append(append([]string{}, e.Ceremony.Parties...), e.Ceremony.NewParties...) {
+		if !signed[id] {
+			return fmt.Errorf("party %s did not sign the ceremony", id)
+		}
+	}
+	return nil
+}
+
+func (e *Entry) hash() ([]byte, error) {
+	digest, err := e.Ceremony.Digest()
+	if err != nil {
+		return nil, err
+	}
+	sigs, err := json.Marshal(e.Signatures)
+	if err != nil {
+		return nil, err
+	}
+	var header [12]byte
+	binary.BigEndian.PutUint32(header[:4], e.Version)
+	binary.BigEndian.PutUint64(header[4:], e.Seq)
+	return hashFields([]byte(entryHashTag), header[:], e.PrevHash, digest, sigs), nil
+}
+
+// hashFields hashes `fields`, each prefixed with its length so that no two lists of fields hash the same
+func hashFields(fields ...[]byte) []byte {
+	h := sha256.New()
+	for _, field := range fields {
+		var l [8]byte
+		binary.BigEndian.PutUint64(l[:], uint64(len(field)))
+		h.Write(l[:])
+		h.Write(field)
+	}
+	return h.Sum(nil)
+}
@@ -0,0 +1,167 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package audit_test
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/kisdex/mpc-lib/audit"
+	"github.com/kisdex/mpc-lib/crypto"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+func testCeremonies(t *testing.T) []Ceremony {
+	pIDs := tss.GenerateTestPartyIDs(3)
+	pub := crypto.ScalarBaseMult(tss.S256(), big.NewInt(42))
+	params := tss.NewParameters(tss.S256(), tss.NewPeerContext(pIDs), pIDs[0], len(pIDs), 1)
+	keygen, err := NewKeygenCeremony("session-1", params, pub)
+	assert.NoError(t, err)
+
+	newPIDs := tss.GenerateTestPartyIDs(4, 3)
+	rgParams := tss.NewReSharingParameters(tss.S256(), tss.NewPeerContext(pIDs), tss.NewPeerContext(newPIDs), pIDs[0], len(pIDs), 1, len(newPIDs), 2)
+	resharing, err := NewResharingCeremony("session-2", rgParams, pub)
+	assert.NoError(t, err)
+	return []Ceremony{keygen, resharing}
+}
+
+func partyKeys(t *testing.T, ceremonies []Ceremony) (map[string]ed25519.PrivateKey, map[string]ed25519.PublicKey) {
+	private, public := make(map[string]ed25519.PrivateKey), make(map[string]ed25519.PublicKey)
+	for _, c := range ceremonies {
+		for _, id := range append(append([]string{}, c.Parties...), c.NewParties...) {
+			if _, ok := private[id]; ok {
+				continue
+			}
+			pub, priv, err := ed25519.GenerateKey(nil)
+			assert.NoError(t, err)
+			private[id], public[id] = priv, pub
+		}
+	}
+	return private, public
+}
+
+func signAll(t *testing.T, c Ceremony, keys map[string]ed25519.PrivateKey) []Signature {
+	var sigs []Signature
+	for _, id := range append(append([]string{}, c.Parties...), c.NewParties...) {
+		sig, err := c.Sign(id, keys[id])
+		assert.NoError(t, err)
+		sigs = append(sigs, sig)
+	}
+	return sigs
+}
+
+func TestLogChainAndSignatures(t *testing.T) {
+	ceremonies := testCeremonies(t)
+	private, public := partyKeys(t, ceremonies)
+	var buf bytes.Buffer
+	log := NewLog(&buf, nil)
+	for _, c := range ceremonies {
+		_, err := log.Append(c, signAll(t, c, private)...)
+		assert.NoError(t, err)
+	}
+
+	entries, err := ReadLog(bytes.NewReader(buf.Bytes()))
+	if !assert.NoError(t, err) || !assert.Len(t, entries, 2) {
+		return
+	}
+	assert.NoError(t, Verify(entries, public))
+	assert.Equal(t, Resharing, entries[1].Ceremony.Kind)
+	assert.Len(t, entries[1].Ceremony.NewParties, 4)
+	assert.Equal(t, entries[0].Hash, entries[1].PrevHash)
+
+	// an unsigned ceremony is still chained, but does not pass with the keys of its parties
+	_, err = log.Append(ceremonies[0], signAll(t, ceremonies[0], private)[1:]...)
+	assert.NoError(t, err)
+	entries, _ = ReadLog(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, Verify(entries, nil))
+	assert.Error(t, Verify(entries, public))
+
+	_, err = ceremonies[0].Sign("outsider", private[ceremonies[0].Parties[0]])
+	assert.Error(t, err)
+}
+
+func TestVerifyDetectsTampering(t *testing.T) {
+	ceremonies := testCeremonies(t)
+	private, public := partyKeys(t, ceremonies)
+	var buf bytes.Buffer
+	log := NewLog(&buf, nil)
+	for _, c := range append(ceremonies, ceremonies[0]) {
+		_, err := log.Append(c, signAll(t, c, private)...)
+		assert.NoError(t, err)
+	}
+	read := func() []Entry {
+		entries, err := ReadLog(bytes.NewReader(buf.Bytes()))
+		assert.NoError(t, err)
+		return entries
+	}
+
+	modified := read()
+	modified[1].Ceremony.Threshold = 0
+	assert.Error(t, Verify(modified, nil), "a modified entry")
+
+	rehashed := read()
+	rehashed[0].Ceremony.PublicKey = crypto.ScalarBaseMult(tss.S256(), big.NewInt(43)).Bytes()
+	assert.Error(t, Verify(rehashed, nil), "a modified entry")
+
+	entries := read()
+	assert.Error(t, Verify(append(entries[:1:1], entries[2]), nil), "a removed entry")
+	entries = read()
+	assert.Error(t, Verify([]Entry{entries[1], entries[0], entries[2]}, nil), "reordered entries")
+
+	forged := read()
+	forged[2].Signatures[0].Signature = forged[1].Signatures[0].Signature
+	assert.Error(t, Verify(forged, public), "a forged signature")
+}
+
+func TestOpenLogContinuesTheChain(t *testing.T) {
+	ceremonies := testCeremonies(t)
+	path := filepath.Join(t.TempDir(), "audit.log")
+	for _, c := range ceremonies {
+		log, err := OpenLog(path)
+		if !assert.NoError(t, err) {
+			return
+		}
+		_, err = log.Append(c)
+		assert.NoError(t, err)
+		assert.NoError(t, log.Close())
+	}
+	log, err := OpenLog(path)
+	if !assert.NoError(t, err) {
+		return
+	}
+	entry, err := log.Append(ceremonies[0])
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), entry.Seq)
+	assert.NoError(t, log.Close())
+}
+
+func TestOpenLogRejectsATamperedFile(t *testing.T) {
+	ceremonies := testCeremonies(t)
+	path := filepath.Join(t.TempDir(), "audit.log")
+	log, err := OpenLog(path)
+	if !assert.NoError(t, err) {
+		return
+	}
+	for _, c := range ceremonies {
+		_, err = log.Append(c)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, log.Close())
+
+	bz, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	bz = bytes.Replace(bz, []byte(`"threshold":1`), []byte(`"threshold":2`), 1)
+	assert.NoError(t, os.WriteFile(path, bz, 0o600))
+	_, err = OpenLog(path)
+	assert.Error(t, err)
+}
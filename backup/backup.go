@@ -0,0 +1,219 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package backup envelope-encrypts the save data of a party with a key managed by a cloud KMS, e.g. AWS KMS or GCP
+// Cloud KMS, so that a share can be backed up outside of the host that runs the party and restored later.
+//
+// Every backup has its own random data key. The save data is encrypted with it using XChaCha20-Poly1305, and the data
+// key itself is encrypted by the KMS; only the encrypted data key is stored. The metadata of the backup is
+// authenticated along with the share, and a checksum of the whole backup allows to detect corruption without access
+// to the KMS.
+package backup
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/chacha20poly1305"
+
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+// Version is the format version of the backups written by this package
+const Version = 1
+
+type (
+	// KMSClient encrypts and decrypts small payloads with a key held by a KMS. It is implemented by thin wrappers
+	// around the SDK of a provider, e.g. the Encrypt and Decrypt calls of AWS KMS or GCP Cloud KMS.
+	KMSClient interface {
+		Encrypt(ctx context.Context, keyID string, plaintext []byte) ([]byte, error)
+		Decrypt(ctx context.Context, keyID string, ciphertext []byte) ([]byte, error)
+	}
+
+	// Metadata describes the share held in a backup. It is stored in the clear but cannot be modified.
+	Metadata struct {
+		KeyID     string        `json:"key_id"`     // identifier of the shared key, chosen by the application
+		KMSKeyID  string        `json:"kms_key_id"` // the KMS key that encrypts the data key
+		Curve     tss.CurveName `json:"curve"`
+		Threshold int           `json:"threshold"`
+	}
+
+	// Backup is an envelope-encrypted share
+	Backup struct {
+		Version          uint32   `json:"version"`
+		Metadata         Metadata `json:"metadata"`
+		EncryptedDataKey []byte   `json:"encrypted_data_key"`
+		Nonce            []byte   `json:"nonce"`
+		Ciphertext       []byte   `json:"ciphertext"`
+		Checksum         []byte   `json:"checksum"` // SHA-256 of all the other fields
+	}
+)
+
+// Create encrypts the JSON encoding of `data`, e.g. a keygen.LocalPartySaveData, under a new data key that is
+// encrypted with the KMS key `meta.KMSKeyID`
+func Create(ctx context.Context, kms KMSClient, data interface{}, meta Metadata) (*Backup, error) {
+	if err := meta.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	plaintext, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	defer zero(plaintext)
+
+	dataKey := make([]byte, chacha20poly1305.KeySize)
+	if _, err = rand.Read(dataKey); err != nil {
+		return nil, err
+	}
+	defer zero(dataKey)
+	encryptedDataKey, err := kms.Encrypt(ctx, meta.KMSKeyID, dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("backup: could not encrypt the data key with KMS key %q: %w", meta.KMSKeyID, err)
+	}
+
+	b := &Backup{
+		Version:          Version,
+		Metadata:         meta,
+		EncryptedDataKey: encryptedDataKey,
+		Nonce:            make([]byte, chacha20poly1305.NonceSizeX),
+	}
+	if _, err = rand.Read(b.Nonce); err != nil {
+		return nil, err
+	}
+	aead, err := chacha20poly1305.NewX(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	ad, err := b.additionalData()
+	if err != nil {
+		return nil, err
+	}
+	b.Ciphertext = aead.Seal(nil, b.Nonce, plaintext, ad)
+	if b.Checksum, err = b.checksum(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Restore decrypts the data key of the backup with the KMS and the share into `data`
+func (b *Backup) Restore(ctx context.Context, kms KMSClient, data interface{}) error {
+	if err := b.VerifyChecksum(); err != nil {
+		return err
+	}
+	dataKey, err := kms.Decrypt(ctx, b.Metadata.KMSKeyID, b.EncryptedDataKey)
+	if err != nil {
+		return fmt.Errorf("backup: could not decrypt the data key with KMS key %q: %w", b.Metadata.KMSKeyID, err)
+	}
+	defer zero(dataKey)
+	aead, err := chacha20poly1305.NewX(dataKey)
+	if err != nil {
+		return err
+	}
+	ad, err := b.additionalData()
+	if err != nil {
+		return err
+	}
+	plaintext, err := aead.Open(nil, b.Nonce, b.Ciphertext, ad)
+	if err != nil {
+		return errors.New("backup: could not decrypt the share: wrong data key or modified backup")
+	}
+	defer zero(plaintext)
+	return json.Unmarshal(plaintext, data)
+}
+
+// VerifyChecksum checks the format and the checksum of the backup, without decrypting it
+func (b *Backup) VerifyChecksum() error {
+	if b == nil {
+		return errors.New("backup: nil backup")
+	}
+	if b.Version != Version {
+		return fmt.Errorf("backup: unsupported version %d; this library supports version %d", b.Version, Version)
+	}
+	if err := b.Metadata.ValidateBasic(); err != nil {
+		return err
+	}
+	if len(b.Nonce) != chacha20poly1305.NonceSizeX || len(b.EncryptedDataKey) == 0 ||
+		len(b.Ciphertext) < chacha20poly1305.Overhead {
+		return errors.New("backup: malformed backup")
+	}
+	sum, err := b.checksum()
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(sum, b.Checksum) {
+		return errors.New("backup: checksum mismatch: the backup is corrupted")
+	}
+	return nil
+}
+
+// Marshal encodes the backup as JSON
+func (b *Backup) Marshal() ([]byte, error) {
+	return json.Marshal(b)
+}
+
+// Unmarshal decodes a backup encoded with Marshal and verifies its checksum
+func Unmarshal(bz []byte) (*Backup, error) {
+	b := new(Backup)
+	if err := json.Unmarshal(bz, b); err != nil {
+		return nil, err
+	}
+	if err := b.VerifyChecksum(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// ValidateBasic checks that the metadata names a KMS key and a known curve
+func (m Metadata) ValidateBasic() error {
+	if m.KMSKeyID == "" {
+		return errors.New("backup: the metadata has no KMS key ID")
+	}
+	if _, ok := tss.GetCurveByName(m.Curve); !ok {
+		return fmt.Errorf("backup: unknown curve %q", m.Curve)
+	}
+	if m.Threshold < 0 {
+		return fmt.Errorf("backup: invalid threshold %d", m.Threshold)
+	}
+	return nil
+}
+
+// ----- //
+
+// the version and the metadata are authenticated with the share so that they cannot be swapped between backups
+func (b *Backup) additionalData() ([]byte, error) {
+	meta, err := json.Marshal(b.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(fmt.Sprintf("mpc-lib backup v%d\n", b.Version)), meta...), nil
+}
+
+func (b *Backup) checksum() ([]byte, error) {
+	ad, err := b.additionalData()
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	for _, field := range [][]byte{ad, b.EncryptedDataKey, b.Nonce, b.Ciphertext} {
+		var l [8]byte
+		binary.BigEndian.PutUint64(l[:], uint64(len(field)))
+		h.Write(l[:])
+		h.Write(field)
+	}
+	return h.Sum(nil), nil
+}
+
+func zero(bz []byte) {
+	for i := range bz {
+		bz[i] = 0
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package backup_test
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/chacha20poly1305"
+
+	. "github.com/kisdex/mpc-lib/backup"
+	"github.com/kisdex/mpc-lib/ecdsa/keygen"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+// localKMS stands in for a cloud KMS with one key per key ID
+type localKMS map[string][]byte
+
+func (kms localKMS) Encrypt(_ context.Context, keyID string, plaintext []byte) ([]byte, error) {
+	key, ok := kms[keyID]
+	if !ok {
+		return nil, errors.New("key not found")
+	}
+	aead, _ := chacha20poly1305.NewX(key)
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, []byte(keyID)), nil
+}
+
+func (kms localKMS) Decrypt(_ context.Context, keyID string, ciphertext []byte) ([]byte, error) {
+	key, ok := kms[keyID]
+	if !ok {
+		return nil, errors.New("key not found")
+	}
+	aead, _ := chacha20poly1305.NewX(key)
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	return aead.Open(nil, ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():], []byte(keyID))
+}
+
+func TestCreateRestore(t *testing.T) {
+	keys, _, err := keygen.LoadKeygenTestFixtures(1)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	kms := localKMS{"kms-1": make([]byte, chacha20poly1305.KeySize), "kms-2": make([]byte, chacha20poly1305.KeySize)}
+	_, _ = rand.Read(kms["kms-1"])
+	_, _ = rand.Read(kms["kms-2"])
+	meta := Metadata{KeyID: "wallet-1", KMSKeyID: "kms-1", Curve: tss.Secp256k1, Threshold: 1}
+	ctx := context.Background()
+
+	b, err := Create(ctx, kms, &keys[0], meta)
+	if !assert.NoError(t, err) {
+		return
+	}
+	bz, err := b.Marshal()
+	assert.NoError(t, err)
+	b, err = Unmarshal(bz)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, meta, b.Metadata)
+
+	var restored keygen.LocalPartySaveData
+	assert.NoError(t, b.Restore(ctx, kms, &restored))
+	assert.Equal(t, 0, keys[0].Xi.Cmp(restored.Xi))
+	assert.Equal(t, 0, keys[0].ECDSAPub.X().Cmp(restored.ECDSAPub.X()))
+
+	corrupted := *b
+	corrupted.Ciphertext = append([]byte{}, b.Ciphertext...)
+	corrupted.Ciphertext[0] ^= 1
+	assert.Error(t, corrupted.VerifyChecksum(), "a corrupted backup must fail the checksum")
+
+	tampered := *b
+	tampered.Metadata.Threshold = 2
+	assert.Error(t, tampered.VerifyChecksum(), "the metadata must not be modified")
+
+	swapped := *b
+	swapped.Metadata.KMSKeyID = "kms-2"
+	assert.Error(t, swapped.Restore(ctx, kms, &restored), "the data key must not decrypt under another KMS key")
+
+	_, err = Create(ctx, kms, &keys[0], Metadata{KMSKeyID: "kms-1", Curve: "p-1"})
+	assert.Error(t, err, "the curve must be known")
+	_, err = Create(ctx, kms, &keys[0], Metadata{KMSKeyID: "unknown", Curve: tss.Secp256k1})
+	assert.Error(t, err, "KMS errors must be returned")
+}
@@ -0,0 +1,201 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"runtime"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto/bls"
+	"github.com/kisdex/mpc-lib/test"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+const (
+	testParticipants = TestParticipants
+	testThreshold    = TestThreshold
+)
+
+func setUp(level string) {
+	if err := common.SetLogLevel(level); err != nil {
+		panic(err)
+	}
+}
+
+func TestE2EConcurrentAndSaveFixtures(t *testing.T) {
+	setUp("info")
+
+	for _, variant := range []bls.Variant{bls.MinPubKey, bls.MinSig} {
+		t.Run(variant.String(), func(t *testing.T) {
+			testE2EConcurrentAndSaveFixtures(t, variant)
+		})
+	}
+}
+
+func testE2EConcurrentAndSaveFixtures(t *testing.T, variant bls.Variant) {
+	threshold := testThreshold
+	_, pIDs, err := LoadKeygenTestFixtures(variant, testParticipants)
+	if err != nil {
+		common.DefaultLogger().Infof("No test fixtures were found, so new party ids will be generated.")
+		pIDs = tss.GenerateTestPartyIDs(testParticipants)
+	}
+
+	p2pCtx := tss.NewPeerContext(pIDs)
+	parties := make([]*LocalParty, 0, len(pIDs))
+
+	errCh := make(chan *tss.Error, len(pIDs))
+	outCh := make(chan tss.Message, len(pIDs))
+	endCh := make(chan *LocalPartySaveData, len(pIDs))
+
+	updater := test.SharedPartyUpdater
+
+	startGR := runtime.NumGoroutine()
+
+	// init the parties
+	for i := 0; i < len(pIDs); i++ {
+		params := tss.NewParameters(nil, p2pCtx, pIDs[i], len(pIDs), threshold)
+		P := NewLocalParty(params, variant, outCh, endCh).(*LocalParty)
+		parties = append(parties, P)
+		go func(P *LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	// PHASE: keygen
+	var ended int32
+keygen:
+	for {
+		fmt.Printf("ACTIVE GOROUTINES: %d\n", runtime.NumGoroutine())
+		select {
+		case err := <-errCh:
+			common.DefaultLogger().Errorf("Error: %s", err)
+			assert.FailNow(t, err.Error())
+			break keygen
+
+		case msg := <-outCh:
+			dest := msg.GetTo()
+			if dest == nil { // broadcast!
+				for _, P := range parties {
+					if P.PartyID().Index == msg.GetFrom().Index {
+						continue
+					}
+					go updater(P, msg, errCh)
+				}
+			} else { // point-to-point!
+				if dest[0].Index == msg.GetFrom().Index {
+					t.Fatalf("party %d tried to send a message to itself (%d)", dest[0].Index, msg.GetFrom().Index)
+					return
+				}
+				go updater(parties[dest[0].Index], msg, errCh)
+			}
+
+		case save := <-endCh:
+			// SAVE a test fixture file for this P (if it doesn't already exist)
+			index, err := save.OriginalIndex()
+			assert.NoErrorf(t, err, "should not be an error getting a party's index from save data")
+			tryWriteTestFixtureFile(t, variant, index, *save)
+
+			atomic.AddInt32(&ended, 1)
+			if atomic.LoadInt32(&ended) == int32(len(pIDs)) {
+				t.Logf("Done. Received save data from %d participants", ended)
+
+				// the secret is the sum of every party's ui
+				modQ := common.ModInt(bls.Order())
+				u := new(big.Int)
+				for _, Pj := range parties {
+					u = modQ.Add(u, Pj.temp.ui)
+				}
+				pk := variant.PublicKey(u)
+				assert.Len(t, pk, variant.PublicKeyLen())
+
+				for j, Pj := range parties {
+					// make sure everyone has the same BLS public key
+					assert.Equal(t, pk, Pj.data.BLSPub)
+					// xj tests: BigXj == xj*G
+					assert.Equal(t, variant.PublicKey(Pj.data.Xi), Pj.data.BigXj[j], "ensure BigX_j == g^x_j")
+				}
+				t.Log("Public key distribution test done.")
+
+				// t+1 shares interpolate to u; t do not
+				ks := make([]*big.Int, threshold+1)
+				sum := new(big.Int)
+				for j := range ks {
+					ks[j] = parties[j].data.ShareID
+				}
+				for j := range ks {
+					lambda, err := bls.LagrangeCoefficient(j, ks)
+					assert.NoError(t, err)
+					sum = modQ.Add(sum, modQ.Mul(lambda, parties[j].data.Xi))
+				}
+				assert.Equal(t, 0, u.Cmp(sum), "shares must reconstruct the secret")
+
+				msg := []byte("bls keygen test")
+				sig, err := variant.Sign(u, msg)
+				assert.NoError(t, err)
+				assert.True(t, variant.Verify(save.BLSPub, msg, sig), "signature should be ok")
+				t.Log("BLS signing test done.")
+
+				t.Logf("Start goroutines: %d, End goroutines: %d", startGR, runtime.NumGoroutine())
+
+				break keygen
+			}
+		}
+	}
+}
+
+func tryWriteTestFixtureFile(t *testing.T, variant bls.Variant, index int, data LocalPartySaveData) {
+	fixtureFileName := makeTestFixtureFilePath(variant, index)
+
+	// fixture file does not already exist?
+	// if it does, we won't re-create it here
+	fi, err := os.Stat(fixtureFileName)
+	if !(err == nil && fi != nil && !fi.IsDir()) {
+		fd, err := os.OpenFile(fixtureFileName, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+		if err != nil {
+			assert.NoErrorf(t, err, "unable to open fixture file %s for writing", fixtureFileName)
+		}
+		bz, err := json.Marshal(&data)
+		if err != nil {
+			t.Fatalf("unable to marshal save data for fixture file %s", fixtureFileName)
+		}
+		_, err = fd.Write(bz)
+		if err != nil {
+			t.Fatalf("unable to write to fixture file %s", fixtureFileName)
+		}
+		t.Logf("Saved a test fixture file for party %d: %s", index, fixtureFileName)
+	} else {
+		t.Logf("Fixture file already exists for party %d; not re-creating: %s", index, fixtureFileName)
+	}
+}
+
+func FuzzMessages(f *testing.F) {
+	setUp("error")
+	pIDs := tss.GenerateTestPartyIDs(testParticipants)
+	p2pCtx := tss.NewPeerContext(pIDs)
+	outCh := make(chan tss.Message, len(pIDs)*len(pIDs))
+	endCh := make(chan *LocalPartySaveData, len(pIDs))
+	parties := make([]tss.Party, 0, len(pIDs))
+	for i := range pIDs {
+		params := tss.NewParameters(nil, p2pCtx, pIDs[i], len(pIDs), testThreshold)
+		parties = append(parties, NewLocalParty(params, bls.MinPubKey, outCh, endCh))
+	}
+	transcript, err := test.RunSequentially(parties, outCh)
+	if err != nil {
+		f.Fatal(err)
+	}
+	test.FuzzWireMessages(f, nil, transcript)
+}
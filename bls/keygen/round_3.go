@@ -0,0 +1,178 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/hashicorp/go-multierror"
+	errors2 "github.com/pkg/errors"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto/bls"
+	"github.com/kisdex/mpc-lib/crypto/commitments"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+func (round *round3) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started"))
+	}
+	round.number = 3
+	round.started = true
+	round.resetOK()
+
+	Ps := round.Parties().IDs()
+	PIdx := round.PartyID().Index
+	variant := round.save.Variant
+
+	// 1,10. calculate xi
+	modQ := common.ModInt(bls.Order())
+	xi := new(big.Int).Set(round.temp.shares[PIdx])
+	for j := range Ps {
+		if j == PIdx {
+			continue
+		}
+		r2msg1 := round.temp.kgRound2Message1s[j].Content().(*KGRound2Message1)
+		xi = modQ.Add(xi, r2msg1.UnmarshalShare())
+	}
+	round.save.Xi = xi
+
+	// 4-12.
+	type vssOut struct {
+		unWrappedErr error
+		pjVs         [][]byte
+	}
+	chs := make([]chan vssOut, len(Ps))
+	pool := round.NewWorkerPool()
+	for i := range chs {
+		if i == PIdx {
+			continue
+		}
+		chs[i] = make(chan vssOut, 1)
+	}
+	for j := range Ps {
+		if j == PIdx {
+			continue
+		}
+		// 6-9.
+		ch := chs[j]
+		pool.GoFor(Ps[j], func() {
+			// 4-10.
+			KGCj := round.temp.KGCs[j]
+			r2msg2 := round.temp.kgRound2Message2s[j].Content().(*KGRound2Message2)
+			KGDj := r2msg2.UnmarshalDeCommitment()
+			cmtDeCmt := commitments.HashCommitDecommit{C: KGCj, D: KGDj}
+			ok, flatPolyGs := cmtDeCmt.DeCommit()
+			if !ok || flatPolyGs == nil {
+				ch <- vssOut{errors.New("de-commitment verify failed"), nil}
+				return
+			}
+			if len(flatPolyGs) != round.Threshold()+1 {
+				ch <- vssOut{errors.New("wrong number of vss commitments"), nil}
+				return
+			}
+			PjVs, err := pointsFromInts(flatPolyGs, variant.PublicKeyLen())
+			if err != nil {
+				ch <- vssOut{err, nil}
+				return
+			}
+			if ok = variant.VerifyPossession(PjVs[0], r2msg2.GetProofOfPossession()); !ok {
+				ch <- vssOut{errors.New("failed to verify proof of possession"), nil}
+				return
+			}
+			r2msg1 := round.temp.kgRound2Message1s[j].Content().(*KGRound2Message1)
+			if ok = variant.VerifyShare(PjVs, round.PartyID().KeyInt(), r2msg1.UnmarshalShare()); !ok {
+				ch <- vssOut{errors.New("vss verify failed"), nil}
+				return
+			}
+			ch <- vssOut{nil, PjVs}
+		})
+	}
+
+	// consume the channels (end the goroutines)
+	vssResults := make([]vssOut, len(Ps))
+	{
+		culprits := make([]*tss.PartyID, 0, len(Ps)) // who caused the error(s)
+		for j, Pj := range Ps {
+			if j == PIdx {
+				continue
+			}
+			vssResults[j] = <-chs[j]
+			// collect culprits to error out with
+			if err := vssResults[j].unWrappedErr; err != nil {
+				culprits = append(culprits, Pj)
+			}
+		}
+		var multiErr error
+		if len(culprits) > 0 {
+			for _, vssResult := range vssResults {
+				if vssResult.unWrappedErr == nil {
+					continue
+				}
+				multiErr = multierror.Append(multiErr, vssResult.unWrappedErr)
+			}
+			return round.WrapError(multiErr, culprits...)
+		}
+	}
+
+	// 11-12. sum the commitments of all the polynomials
+	Vc := make([][]byte, round.Threshold()+1)
+	for c := range Vc {
+		terms := make([][]byte, 0, len(Ps))
+		for j := range Ps {
+			if j == PIdx {
+				terms = append(terms, round.temp.vs[c])
+				continue
+			}
+			terms = append(terms, vssResults[j].pjVs[c])
+		}
+		var err error
+		if Vc[c], err = variant.AddPublicKeys(terms...); err != nil {
+			return round.WrapError(errors2.Wrapf(err, "sum of Vc[%d]", c))
+		}
+	}
+
+	// 13-17. compute Xj for each Pj
+	for j, Pj := range Ps {
+		BigXj, err := variant.PublicShare(Vc, Pj.KeyInt())
+		if err != nil {
+			return round.WrapError(errors2.Wrapf(err, "PublicShare(Vc)"), Pj)
+		}
+		round.save.BigXj[j] = BigXj
+	}
+	if string(round.save.BigXj[PIdx]) != string(variant.PublicKey(xi)) {
+		return round.WrapError(errors.New("own public key share does not match xi"))
+	}
+
+	// 18. compute and SAVE the BLS public key `y`
+	if err := variant.ValidatePublicKey(Vc[0]); err != nil {
+		return round.WrapError(errors2.Wrapf(err, "invalid public key"))
+	}
+	round.save.BLSPub = Vc[0]
+
+	// PRINT public key & private share
+	round.Logger().Debugf("%s public key: %x", round.PartyID(), Vc[0])
+
+	round.end <- round.save
+	return nil
+}
+
+func (round *round3) CanAccept(msg tss.ParsedMessage) bool {
+	// not expecting any incoming messages in this round
+	return false
+}
+
+func (round *round3) Update() (bool, *tss.Error) {
+	// not expecting any incoming messages in this round
+	return false, nil
+}
+
+func (round *round3) NextRound() tss.Round {
+	return nil // finished!
+}
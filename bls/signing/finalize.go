@@ -0,0 +1,75 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"errors"
+	"fmt"
+
+	errors2 "github.com/pkg/errors"
+
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+func (round *finalization) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started"))
+	}
+	round.number = 2
+	round.started = true
+	round.resetOK()
+
+	variant := round.key.Variant
+	Ps := round.Parties().IDs()
+	partials := make([][]byte, len(Ps))
+	culprits := make([]*tss.PartyID, 0, len(Ps))
+	for j, Pj := range Ps {
+		round.ok[j] = true
+		if j == round.PartyID().Index {
+			partials[j] = round.temp.si
+			continue
+		}
+		r1msg := round.temp.signRound1Messages[j].Content().(*SignRound1Message)
+		partials[j] = r1msg.GetPartialSignature()
+		// every partial signature is checked against the signer's public key share, so a bad one is attributable
+		if !variant.Verify(round.key.BigXj[j], round.temp.m, partials[j]) {
+			culprits = append(culprits, Pj)
+		}
+	}
+	if len(culprits) > 0 {
+		return round.WrapError(errors.New("partial signature verification failed"), culprits...).WithCode(tss.ErrProofVerifyFailed)
+	}
+
+	sig, err := variant.CombineSignatures(round.key.Ks, partials)
+	if err != nil {
+		return round.WrapError(errors2.Wrapf(err, "CombineSignatures"))
+	}
+	if !variant.Verify(round.key.BLSPub, round.temp.m, sig) {
+		return round.WrapError(fmt.Errorf("signature verification failed"))
+	}
+
+	// save the signature for final output
+	round.data.Signature = sig
+	round.data.M = round.temp.m
+	round.end <- round.data
+
+	return nil
+}
+
+func (round *finalization) CanAccept(msg tss.ParsedMessage) bool {
+	// not expecting any incoming messages in this round
+	return false
+}
+
+func (round *finalization) Update() (bool, *tss.Error) {
+	// not expecting any incoming messages in this round
+	return false, nil
+}
+
+func (round *finalization) NextRound() tss.Round {
+	return nil // finished!
+}
@@ -0,0 +1,138 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/bls/keygen"
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto/bls"
+	"github.com/kisdex/mpc-lib/test"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+const (
+	testParticipants = test.TestParticipants
+	testThreshold    = test.TestThreshold
+)
+
+func setUp(level string) {
+	if err := common.SetLogLevel(level); err != nil {
+		panic(err)
+	}
+}
+
+func TestE2EConcurrent(t *testing.T) {
+	setUp("info")
+
+	for _, variant := range []bls.Variant{bls.MinPubKey, bls.MinSig} {
+		t.Run(variant.String(), func(t *testing.T) {
+			keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(variant, testThreshold+1, testParticipants)
+			assert.NoError(t, err, "should load keygen fixtures")
+
+			msg := []byte("bls threshold signing")
+			sig, culprits := runSigning(t, msg, keys, signPIDs, nil)
+			assert.Empty(t, culprits)
+			if assert.NotNil(t, sig) {
+				assert.Len(t, sig.Signature, variant.SignatureLen())
+				assert.True(t, variant.Verify(keys[0].BLSPub, msg, sig.Signature), "bls verify must pass")
+			}
+		})
+	}
+}
+
+func TestE2EBadPartialSignature(t *testing.T) {
+	setUp("info")
+
+	variant := bls.MinPubKey
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(variant, testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	msg := []byte("bls threshold signing")
+	cheater := signPIDs[0]
+	sig, culprits := runSigning(t, msg, keys, signPIDs, func(m tss.Message) tss.Message {
+		if m.GetFrom().Index != cheater.Index {
+			return m
+		}
+		// a partial signature over a different message
+		bad, err := variant.Sign(keys[0].Xi, []byte("something else"))
+		assert.NoError(t, err)
+		return NewSignRound1Message(m.GetFrom(), bad)
+	})
+	assert.Nil(t, sig)
+	if assert.Len(t, culprits, 1) {
+		assert.Equal(t, cheater.Id, culprits[0].Id)
+	}
+}
+
+func runSigning(
+	t *testing.T,
+	msg []byte,
+	keys []keygen.LocalPartySaveData,
+	signPIDs tss.SortedPartyIDs,
+	tamper func(tss.Message) tss.Message,
+) (*common.SignatureData, []*tss.PartyID) {
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]*LocalParty, 0, len(signPIDs))
+
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs))
+	endCh := make(chan *common.SignatureData, len(signPIDs))
+
+	updater := test.SharedPartyUpdater
+
+	// init the parties. there is a single message round, so every party is started before any message is routed;
+	// a message stored ahead of Start() would otherwise never be picked up
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(nil, p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+
+		P := NewLocalParty(msg, params, keys[i], outCh, endCh).(*LocalParty)
+		parties = append(parties, P)
+		if err := P.Start(); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+	}
+
+	var ended int32
+	var sig *common.SignatureData
+	for {
+		select {
+		case err := <-errCh:
+			if tamper == nil {
+				common.DefaultLogger().Errorf("Error: %s", err)
+				assert.FailNow(t, err.Error())
+			}
+			return nil, err.Culprits()
+
+		case msg := <-outCh:
+			if tamper != nil {
+				msg = tamper(msg)
+			}
+			for _, P := range parties {
+				if P.PartyID().Index == msg.GetFrom().Index {
+					continue
+				}
+				go updater(P, msg, errCh)
+			}
+
+		case data := <-endCh:
+			if sig == nil {
+				sig = data
+			} else {
+				assert.Equal(t, sig.Signature, data.Signature, "all parties must output the same signature")
+			}
+			if atomic.AddInt32(&ended, 1) == int32(len(signPIDs)) {
+				t.Logf("Done. Received signature data from %d participants", ended)
+				return sig, nil
+			}
+		}
+	}
+}
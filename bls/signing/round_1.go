@@ -0,0 +1,89 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"errors"
+	"fmt"
+
+	errors2 "github.com/pkg/errors"
+
+	"github.com/kisdex/mpc-lib/bls/keygen"
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+// round 1 represents round 1 of the signing part of the BLS TSS spec
+func newRound1(params *tss.Parameters, key *keygen.LocalPartySaveData, data *common.SignatureData, temp *localTempData, out chan<- tss.Message, end chan<- *common.SignatureData) tss.Round {
+	return &round1{
+		&base{params, key, data, temp, out, end, make([]bool, len(params.Parties().IDs())), false, 1}}
+}
+
+func (round *round1) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started"))
+	}
+
+	round.number = 1
+	round.started = true
+	round.resetOK()
+
+	// 1. partial signature si = xi*H(m); the Lagrange coefficient is applied when combining so that every si can be
+	// checked against the public key share Xi
+	si, err := round.key.Variant.Sign(round.key.Xi, round.temp.m)
+	if err != nil {
+		return round.WrapError(errors2.Wrapf(err, "Sign(xi, m)"))
+	}
+	round.temp.si = si
+
+	i := round.PartyID().Index
+	round.ok[i] = true
+
+	// 2. broadcast the partial signature
+	r1msg := NewSignRound1Message(round.PartyID(), si)
+	round.temp.signRound1Messages[i] = r1msg
+	round.SendMessage(round.out, r1msg)
+
+	return nil
+}
+
+func (round *round1) Update() (bool, *tss.Error) {
+	for j, msg := range round.temp.signRound1Messages {
+		if round.ok[j] {
+			continue
+		}
+		if msg == nil || !round.CanAccept(msg) {
+			return false, nil
+		}
+		round.ok[j] = true
+	}
+	return true, nil
+}
+
+func (round *round1) CanAccept(msg tss.ParsedMessage) bool {
+	if _, ok := msg.Content().(*SignRound1Message); ok {
+		return msg.IsBroadcast()
+	}
+	return false
+}
+
+func (round *round1) NextRound() tss.Round {
+	round.started = false
+	return &finalization{round}
+}
+
+// ----- //
+
+func (round *round1) prepare() error {
+	if !round.key.Variant.Valid() {
+		return fmt.Errorf("unsupported BLS variant %s", round.key.Variant)
+	}
+	if round.Threshold()+1 > len(round.key.Ks) {
+		return fmt.Errorf("t+1=%d is not satisfied by the key count of %d", round.Threshold()+1, len(round.key.Ks))
+	}
+	return nil
+}
@@ -0,0 +1,258 @@
+// Command libmpc builds the ecdsa party, through the mobile package, as a C shared library for the custody stacks
+// that are not written in Go, e.g. in Rust, C++ or Java through JNI:
+//
+//	go build -buildmode=c-shared -o libmpc.so ./cmd/libmpc
+//
+// which also writes libmpc.h. A party is an opaque handle. The library does not call back into C: the messages that
+// the party sends are queued, and C takes them with mpc_party_next_message and sends them to their recipients, which
+// pass them to mpc_party_update. The operations that run the protocol start in the background, and C takes their
+// results with mpc_party_wait, by session id.
+//
+// The functions return 0 on success and -1 on an error, whose message they set in *err. The strings and buffers that
+// the library returns are allocated with malloc, and C frees them with mpc_free.
+package main
+
+/*
+#include <stdint.h>
+#include <stdlib.h>
+
+typedef struct {
+	char *session_id;
+	uint8_t *msg;
+	size_t msg_len;
+	int is_broadcast;
+	int to;
+} mpc_message;
+*/
+import "C"
+
+import (
+	"fmt"
+	"runtime/cgo"
+	"sync"
+	"time"
+	"unsafe"
+
+	"github.com/kisdex/mpc-lib/mobile"
+)
+
+const (
+	// outboxSize is the number of messages that a party queues before it waits for C to take them
+	outboxSize = 1000
+)
+
+type (
+	// handle is the party behind a C handle, with its outgoing messages and the results of its operations
+	handle struct {
+		party  *mobile.ECDSAParty
+		outbox chan message
+
+		lock    sync.Mutex
+		results map[string]chan result
+	}
+
+	message struct {
+		sessionID   string
+		msg         []byte
+		isBroadcast bool
+		to          int
+	}
+
+	result struct {
+		out []byte
+		err error
+	}
+
+	outboxTransport chan message
+)
+
+func (t outboxTransport) Send(sessionID string, msg []byte, isBroadcast bool, to int) {
+	t <- message{sessionID: sessionID, msg: msg, isBroadcast: isBroadcast, to: to}
+}
+
+func main() {}
+
+// mpc_ecdsa_party_new sets *out to the handle of a new party `id` of a key of the comma-separated `parties`, of which
+// more than `threshold` must take part in a signature
+//
+//export mpc_ecdsa_party_new
+func mpc_ecdsa_party_new(id C.int, parties *C.char, threshold C.int, out *C.uintptr_t, err **C.char) C.int {
+	outbox := make(chan message, outboxSize)
+	party, e := mobile.NewECDSAParty(int(id), C.GoString(parties), int(threshold), outboxTransport(outbox))
+	if e != nil {
+		return fail(err, e)
+	}
+	*out = C.uintptr_t(cgo.NewHandle(&handle{party: party, outbox: outbox, results: make(map[string]chan result)}))
+	return 0
+}
+
+// mpc_party_free releases the handle of a party; the operations that it runs finish in the background
+//
+//export mpc_party_free
+func mpc_party_free(h C.uintptr_t) {
+	cgo.Handle(h).Delete()
+}
+
+// mpc_party_set_share_data sets the share data of the key, as returned by mpc_keygen_start or mpc_reshare_start
+//
+//export mpc_party_set_share_data
+func mpc_party_set_share_data(h C.uintptr_t, shareData *C.uint8_t, shareDataLen C.size_t, err **C.char) C.int {
+	if e := party(h).party.SetShareData(C.GoBytes(unsafe.Pointer(shareData), C.int(shareDataLen))); e != nil {
+		return fail(err, e)
+	}
+	return 0
+}
+
+// mpc_party_public_key sets *out to the PKIX DER encoding of the threshold public key
+//
+//export mpc_party_public_key
+func mpc_party_public_key(h C.uintptr_t, out **C.uint8_t, outLen *C.size_t, err **C.char) C.int {
+	pub, e := party(h).party.PublicKey()
+	if e != nil {
+		return fail(err, e)
+	}
+	setBytes(out, outLen, pub)
+	return 0
+}
+
+// mpc_party_update passes the party a message of the session `session_id` from the party `from`
+//
+//export mpc_party_update
+func mpc_party_update(h C.uintptr_t, sessionID *C.char, msg *C.uint8_t, msgLen C.size_t, from C.int, isBroadcast C.int) {
+	party(h).party.OnMessage(C.GoString(sessionID), C.GoBytes(unsafe.Pointer(msg), C.int(msgLen)), int(from), isBroadcast != 0)
+}
+
+// mpc_party_next_message waits up to `timeout_ms` for a message that the party sends, and returns 1 with the message
+// in *out, or 0 if there is none. The fields of the message are freed with mpc_free.
+//
+//export mpc_party_next_message
+func mpc_party_next_message(h C.uintptr_t, timeoutMs C.int, out *C.mpc_message) C.int {
+	timer := time.NewTimer(time.Duration(timeoutMs) * time.Millisecond)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return 0
+	case m := <-party(h).outbox:
+		out.session_id = C.CString(m.sessionID)
+		setBytes(&out.msg, &out.msg_len, m.msg)
+		out.is_broadcast = 0
+		if m.isBroadcast {
+			out.is_broadcast = 1
+		}
+		out.to = C.int(m.to)
+		return 1
+	}
+}
+
+// mpc_keygen_start starts a keygen in the session `session_id`, whose result, the share data of the party, C takes
+// with mpc_party_wait
+//
+//export mpc_keygen_start
+func mpc_keygen_start(h C.uintptr_t, sessionID *C.char, timeoutSeconds C.int64_t, err **C.char) C.int {
+	p, session, timeout := party(h), C.GoString(sessionID), int64(timeoutSeconds)
+	if e := p.start(session, func() ([]byte, error) {
+		return p.party.KeyGen(session, timeout)
+	}); e != nil {
+		return fail(err, e)
+	}
+	return 0
+}
+
+// mpc_sign_start starts signing the digest `msg_hash` in the session `session_id`, whose result, the ASN.1 DER
+// signature, C takes with mpc_party_wait
+//
+//export mpc_sign_start
+func mpc_sign_start(h C.uintptr_t, sessionID *C.char, msgHash *C.uint8_t, msgHashLen C.size_t, timeoutSeconds C.int64_t, err **C.char) C.int {
+	p, session, timeout := party(h), C.GoString(sessionID), int64(timeoutSeconds)
+	digest := C.GoBytes(unsafe.Pointer(msgHash), C.int(msgHashLen))
+	if e := p.start(session, func() ([]byte, error) {
+		return p.party.Sign(session, digest, timeout)
+	}); e != nil {
+		return fail(err, e)
+	}
+	return 0
+}
+
+// mpc_reshare_start starts moving the key from the comma-separated `old_parties` to `new_parties` in the session
+// `session_id`, whose result, the share data of the party in the new committee, C takes with mpc_party_wait; it is
+// empty for a party of only the old committee
+//
+//export mpc_reshare_start
+func mpc_reshare_start(h C.uintptr_t, sessionID *C.char, oldParties *C.char, oldThreshold C.int, newParties *C.char, newThreshold C.int, timeoutSeconds C.int64_t, err **C.char) C.int {
+	p, session, timeout := party(h), C.GoString(sessionID), int64(timeoutSeconds)
+	oldIDs, newIDs := C.GoString(oldParties), C.GoString(newParties)
+	if e := p.start(session, func() ([]byte, error) {
+		return p.party.Reshare(session, oldIDs, int(oldThreshold), newIDs, int(newThreshold), timeout)
+	}); e != nil {
+		return fail(err, e)
+	}
+	return 0
+}
+
+// mpc_party_wait waits up to `timeout_ms` for the result of the operation of the session `session_id`. It returns 1
+// with the result in *out, 0 if the operation is still running, or -1 if it failed.
+//
+//export mpc_party_wait
+func mpc_party_wait(h C.uintptr_t, sessionID *C.char, timeoutMs C.int, out **C.uint8_t, outLen *C.size_t, err **C.char) C.int {
+	p, session := party(h), C.GoString(sessionID)
+	p.lock.Lock()
+	results, ok := p.results[session]
+	p.lock.Unlock()
+	if !ok {
+		return fail(err, fmt.Errorf("no operation runs in session %s", session))
+	}
+	timer := time.NewTimer(time.Duration(timeoutMs) * time.Millisecond)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return 0
+	case r := <-results:
+		p.lock.Lock()
+		delete(p.results, session)
+		p.lock.Unlock()
+		if r.err != nil {
+			return fail(err, r.err)
+		}
+		setBytes(out, outLen, r.out)
+		return 1
+	}
+}
+
+// mpc_free frees a string or a buffer that the library returned
+//
+//export mpc_free
+func mpc_free(ptr unsafe.Pointer) {
+	C.free(ptr)
+}
+
+func party(h C.uintptr_t) *handle {
+	return cgo.Handle(h).Value().(*handle)
+}
+
+// start runs `operation` in the background, its result kept for mpc_party_wait
+func (p *handle) start(sessionID string, operation func() ([]byte, error)) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if _, ok := p.results[sessionID]; ok {
+		return fmt.Errorf("an operation already runs in session %s", sessionID)
+	}
+	results := make(chan result, 1)
+	p.results[sessionID] = results
+	go func() {
+		out, err := operation()
+		results <- result{out: out, err: err}
+	}()
+	return nil
+}
+
+func fail(err **C.char, e error) C.int {
+	if err != nil {
+		*err = C.CString(e.Error())
+	}
+	return -1
+}
+
+func setBytes(out **C.uint8_t, outLen *C.size_t, b []byte) {
+	*out = (*C.uint8_t)(C.CBytes(b))
+	*outLen = C.size_t(len(b))
+}
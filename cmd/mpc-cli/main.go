@@ -0,0 +1,395 @@
+// Command mpc-cli runs a party of threshold ECDSA on P-256 from the command line, for operational drills, demos and
+// interop testing against other stacks. Each party runs its own process, and the parties connect to each other over
+// gRPC with mutual TLS; their shares are stored in keystore files encrypted under a passphrase.
+//
+// A drill with three parties on one machine:
+//
+//	mpc-cli certs -dir certs -parties 1,2,3
+//	export MPC_PASSPHRASE=...
+//	PEERS=1=127.0.0.1:9001,2=127.0.0.1:9002,3=127.0.0.1:9003
+//	mpc-cli keygen -id 1 -peers $PEERS -threshold 1 -share share1.json &   # and so on for parties 2 and 3
+//	mpc-cli sign -id 1 -peers $PEERS -parties 1,3 -threshold 1 -share share1.json -digest <hex> &   # and party 3
+//	mpc-cli reshare -id 1 -peers $PEERS,4=127.0.0.1:9004 -old-parties 1,2,3 -old-threshold 1 \
+//		-parties 2,3,4 -threshold 1 -share share1.json -new-share share1-new.json &   # and parties 2, 3 and 4
+//	mpc-cli pubkey -share share1.json
+//
+// The parties of an operation must run it with the same -session, and a party of -peers that does not take part in
+// it need not run. keygen and pubkey print the PKIX DER encoding of the public key, and sign the ASN.1 DER signature,
+// in hex.
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kisdex/mpc-lib/keystore"
+	"github.com/kisdex/mpc-lib/mobile"
+)
+
+const usage = `usage: mpc-cli <command> [flags]
+
+commands:
+  certs     write a throwaway CA and the certificates of the parties
+  keygen    generate a key with the other parties and store the share
+  sign      sign a digest with the other signers
+  reshare   move a key to a new committee of parties
+  pubkey    print the public key of a share
+
+Run mpc-cli <command> -h for the flags of a command.
+`
+
+// partyFlags are the flags of the commands that run the protocol with the other parties
+type partyFlags struct {
+	id            int
+	peers         string
+	certs         string
+	session       string
+	timeout       time.Duration
+	passphraseEnv string
+	logLevel      string
+}
+
+func (f *partyFlags) register(fs *flag.FlagSet, session string) {
+	fs.IntVar(&f.id, "id", 0, "the id of this party")
+	fs.StringVar(&f.peers, "peers", "", "the addresses of the parties, e.g. 1=10.0.0.1:9000,2=10.0.0.2:9000")
+	fs.StringVar(&f.certs, "certs", "certs", "the directory of ca.pem, <id>.pem and <id>-key.pem")
+	fs.StringVar(&f.session, "session", session, "the id of the session, the same for all of the parties")
+	fs.DurationVar(&f.timeout, "timeout", 30*time.Minute, "the time limit of the operation")
+	fs.StringVar(&f.passphraseEnv, "passphrase-env", "MPC_PASSPHRASE", "the environment variable of the passphrase of the share files")
+	fs.StringVar(&f.logLevel, "log-level", "warn", "the level of the log: debug, info, warn or error")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	commands := map[string]func([]string) error{
+		"certs":   certs,
+		"keygen":  keygen,
+		"sign":    sign,
+		"reshare": reshare,
+		"pubkey":  pubkey,
+	}
+	command, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err := command(os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "mpc-cli %s: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
+
+func certs(args []string) error {
+	fs := flag.NewFlagSet("certs", flag.ExitOnError)
+	dir := fs.String("dir", "certs", "the directory to write the certificates to")
+	parties := fs.String("parties", "", "the comma-separated ids of the parties")
+	hosts := fs.String("hosts", "localhost,127.0.0.1", "the comma-separated host names and IPs of the certificates")
+	validity := fs.Duration("validity", 30*24*time.Hour, "the validity of the certificates")
+	_ = fs.Parse(args)
+
+	ids, err := parseIDs(*parties)
+	if err != nil {
+		return err
+	}
+	return writeCerts(*dir, ids, strings.Split(*hosts, ","), *validity)
+}
+
+func keygen(args []string) error {
+	var pf partyFlags
+	fs := flag.NewFlagSet("keygen", flag.ExitOnError)
+	pf.register(fs, "keygen")
+	threshold := fs.Int("threshold", 1, "the threshold; threshold+1 parties can sign")
+	sharePath := fs.String("share", "", "the share file to write")
+	_ = fs.Parse(args)
+
+	if *sharePath == "" {
+		return errors.New("-share is required")
+	}
+	passphrase, err := pf.passphrase()
+	if err != nil {
+		return err
+	}
+	addrs, err := parsePeers(pf.peers)
+	if err != nil {
+		return err
+	}
+	return pf.run(sortedIDs(addrs), addrs, *threshold, func(party *mobile.ECDSAParty, timeout int64) error {
+		shareData, err := party.KeyGen(pf.session, timeout)
+		if err != nil {
+			return err
+		}
+		if err = keystore.SealToFile(*sharePath, json.RawMessage(shareData), passphrase); err != nil {
+			return err
+		}
+		return printPublicKey(party)
+	})
+}
+
+func sign(args []string) error {
+	var pf partyFlags
+	fs := flag.NewFlagSet("sign", flag.ExitOnError)
+	pf.register(fs, "sign")
+	parties := fs.String("parties", "", "the comma-separated ids of the signers; all of -peers by default")
+	threshold := fs.Int("threshold", 1, "the threshold of the key")
+	sharePath := fs.String("share", "", "the share file")
+	digest := fs.String("digest", "", "the hex of the 32-byte digest to sign")
+	_ = fs.Parse(args)
+
+	msgHash, err := hex.DecodeString(*digest)
+	if err != nil || len(msgHash) != 32 {
+		return errors.New("-digest must be the hex of 32 bytes")
+	}
+	shareData, err := pf.openShare(*sharePath)
+	if err != nil {
+		return err
+	}
+	addrs, err := parsePeers(pf.peers)
+	if err != nil {
+		return err
+	}
+	signers := sortedIDs(addrs)
+	if *parties != "" {
+		if signers, err = parseIDs(*parties); err != nil {
+			return err
+		}
+	}
+	return pf.run(signers, addrs, *threshold, func(party *mobile.ECDSAParty, timeout int64) error {
+		if err := party.SetShareData(shareData); err != nil {
+			return err
+		}
+		sig, err := party.Sign(pf.session, msgHash, timeout)
+		if err != nil {
+			return err
+		}
+		fmt.Println(hex.EncodeToString(sig))
+		return nil
+	})
+}
+
+func reshare(args []string) error {
+	var pf partyFlags
+	fs := flag.NewFlagSet("reshare", flag.ExitOnError)
+	pf.register(fs, "reshare")
+	oldParties := fs.String("old-parties", "", "the comma-separated ids of the old committee")
+	oldThreshold := fs.Int("old-threshold", 1, "the threshold of the old committee")
+	newParties := fs.String("parties", "", "the comma-separated ids of the new committee")
+	newThreshold := fs.Int("threshold", 1, "the threshold of the new committee")
+	sharePath := fs.String("share", "", "the share file of a party of the old committee")
+	newSharePath := fs.String("new-share", "", "the share file to write for a party of the new committee")
+	_ = fs.Parse(args)
+
+	oldIDs, err := parseIDs(*oldParties)
+	if err != nil {
+		return err
+	}
+	newIDs, err := parseIDs(*newParties)
+	if err != nil {
+		return err
+	}
+	inOld, inNew := contains(oldIDs, pf.id), contains(newIDs, pf.id)
+	if inNew && *newSharePath == "" {
+		return errors.New("-new-share is required for a party of the new committee")
+	}
+	passphrase, err := pf.passphrase()
+	if err != nil {
+		return err
+	}
+	var shareData []byte
+	if inOld {
+		if shareData, err = pf.openShare(*sharePath); err != nil {
+			return err
+		}
+	}
+	addrs, err := parsePeers(pf.peers)
+	if err != nil {
+		return err
+	}
+	parties := append([]int{}, oldIDs...)
+	for _, id := range newIDs {
+		if !contains(oldIDs, id) {
+			parties = append(parties, id)
+		}
+	}
+	return pf.run(parties, addrs, *oldThreshold, func(party *mobile.ECDSAParty, timeout int64) error {
+		if inOld {
+			if err := party.SetShareData(shareData); err != nil {
+				return err
+			}
+		}
+		newShareData, err := party.Reshare(pf.session, *oldParties, *oldThreshold, *newParties, *newThreshold, timeout)
+		if err != nil {
+			return err
+		}
+		if !inNew {
+			fmt.Println("the share of this party was handed over to the new committee")
+			return nil
+		}
+		if err = keystore.SealToFile(*newSharePath, json.RawMessage(newShareData), passphrase); err != nil {
+			return err
+		}
+		return printPublicKey(party)
+	})
+}
+
+func pubkey(args []string) error {
+	var pf partyFlags
+	fs := flag.NewFlagSet("pubkey", flag.ExitOnError)
+	fs.StringVar(&pf.passphraseEnv, "passphrase-env", "MPC_PASSPHRASE", "the environment variable of the passphrase of the share file")
+	sharePath := fs.String("share", "", "the share file")
+	_ = fs.Parse(args)
+
+	shareData, err := pf.openShare(*sharePath)
+	if err != nil {
+		return err
+	}
+	// the party only reads the share, and never sends
+	party, err := mobile.NewECDSAParty(1, "1,2", 1, nopTransport{})
+	if err != nil {
+		return err
+	}
+	if err = party.SetShareData(shareData); err != nil {
+		return err
+	}
+	return printPublicKey(party)
+}
+
+// run connects this party to the other `parties` and runs `operation` with a party of a key of `parties` with the
+// threshold `threshold`
+func (f *partyFlags) run(parties []int, addrs map[int]string, threshold int, operation func(*mobile.ECDSAParty, int64) error) error {
+	if err := mobile.SetLogLevel(f.logLevel); err != nil {
+		return err
+	}
+	tlsConfig, err := loadTLS(
+		filepath.Join(f.certs, fmt.Sprintf("%d.pem", f.id)),
+		filepath.Join(f.certs, fmt.Sprintf("%d-key.pem", f.id)),
+		filepath.Join(f.certs, "ca.pem"))
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), f.timeout)
+	defer cancel()
+	transport, err := newGRPCTransport(ctx, f.id, parties, addrs, tlsConfig)
+	if err != nil {
+		return err
+	}
+	defer transport.Close()
+
+	party, err := mobile.NewECDSAParty(f.id, joinIDs(parties), threshold, transport)
+	if err != nil {
+		return err
+	}
+	go transport.receive(party)
+	if err = operation(party, int64(f.timeout/time.Second)); err != nil {
+		return err
+	}
+	// the last messages of this party may still be on their way to the parties that are behind
+	time.Sleep(time.Second)
+	return nil
+}
+
+func (f *partyFlags) passphrase() (keystore.Key, error) {
+	passphrase := os.Getenv(f.passphraseEnv)
+	if passphrase == "" {
+		return keystore.Key{}, fmt.Errorf("the passphrase of the share files must be set in $%s", f.passphraseEnv)
+	}
+	return keystore.Argon2idPassphrase([]byte(passphrase)), nil
+}
+
+func (f *partyFlags) openShare(path string) ([]byte, error) {
+	if path == "" {
+		return nil, errors.New("-share is required")
+	}
+	passphrase, err := f.passphrase()
+	if err != nil {
+		return nil, err
+	}
+	var shareData json.RawMessage
+	if err = keystore.OpenFile(path, passphrase, &shareData); err != nil {
+		return nil, fmt.Errorf("could not open the share file: %w", err)
+	}
+	return shareData, nil
+}
+
+func printPublicKey(party *mobile.ECDSAParty) error {
+	pub, err := party.PublicKey()
+	if err != nil {
+		return err
+	}
+	fmt.Println(hex.EncodeToString(pub))
+	return nil
+}
+
+type nopTransport struct{}
+
+func (nopTransport) Send(string, []byte, bool, int) {}
+
+// parsePeers parses a list of id=host:port separated by commas
+func parsePeers(peers string) (map[int]string, error) {
+	addrs := make(map[int]string)
+	for _, field := range strings.Split(peers, ",") {
+		id, addr, ok := strings.Cut(strings.TrimSpace(field), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid peer %q, expected id=host:port", field)
+		}
+		n, err := strconv.Atoi(id)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid party id %q", id)
+		}
+		if _, ok := addrs[n]; ok {
+			return nil, fmt.Errorf("party %d is listed twice", n)
+		}
+		addrs[n] = addr
+	}
+	return addrs, nil
+}
+
+func parseIDs(ids string) ([]int, error) {
+	var parsed []int
+	for _, field := range strings.Split(ids, ",") {
+		id, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("invalid party id %q", field)
+		}
+		parsed = append(parsed, id)
+	}
+	return parsed, nil
+}
+
+func sortedIDs(addrs map[int]string) []int {
+	ids := make([]int, 0, len(addrs))
+	for id := range addrs {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func joinIDs(ids []int) string {
+	fields := make([]string, len(ids))
+	for i, id := range ids {
+		fields[i] = strconv.Itoa(id)
+	}
+	return strings.Join(fields, ",")
+}
+
+func contains(ids []int, id int) bool {
+	for _, other := range ids {
+		if other == id {
+			return true
+		}
+	}
+	return false
+}
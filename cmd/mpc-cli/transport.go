@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/kisdex/mpc-lib/mobile"
+	"github.com/kisdex/mpc-lib/net"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+const (
+	// retryInterval is the wait between two attempts to deliver a message to a peer that is not up yet
+	retryInterval = 500 * time.Millisecond
+)
+
+// grpcTransport carries the messages of a mobile.ECDSAParty over a net.GRPCTransport. The session id of a message and
+// whether it is a broadcast travel in a frame before the message, since a resharing sends its broadcasts to each of
+// their recipients.
+type grpcTransport struct {
+	ctx   context.Context
+	inner *net.GRPCTransport
+	peers map[int]*tss.PartyID
+}
+
+// newGRPCTransport listens on the address of `self` in `addrs` and connects to the other parties of `parties`, which
+// authenticate with certificates of the CA of `tlsConfig` whose common names are their ids
+func newGRPCTransport(ctx context.Context, self int, parties []int, addrs map[int]string, tlsConfig *tls.Config) (*grpcTransport, error) {
+	// the ids are indexed as in the parameters of the protocol
+	pIDs := make([]*tss.PartyID, len(parties))
+	for i, id := range parties {
+		pIDs[i] = tss.NewPartyID(strconv.Itoa(id), "", big.NewInt(int64(id)))
+	}
+	cfg := net.GRPCConfig{TLS: tlsConfig}
+	t := &grpcTransport{ctx: ctx, peers: make(map[int]*tss.PartyID)}
+	for _, pID := range tss.SortPartyIDs(pIDs) {
+		id := int(pID.KeyInt().Int64())
+		addr, ok := addrs[id]
+		if !ok {
+			return nil, fmt.Errorf("the address of party %d is missing from -peers", id)
+		}
+		if id == self {
+			cfg.Self, cfg.ListenAddr = pID, addr
+			continue
+		}
+		t.peers[id] = pID
+		cfg.Peers = append(cfg.Peers, net.GRPCPeer{ID: pID, Addr: addr})
+	}
+	if cfg.Self == nil {
+		return nil, fmt.Errorf("party %d does not take part", self)
+	}
+	inner, err := net.NewGRPCTransport(cfg)
+	if err != nil {
+		return nil, err
+	}
+	t.inner = inner
+	return t, nil
+}
+
+// Send implements mobile.Transport. It retries until the peer is up, as the parties of a drill start one by one.
+func (t *grpcTransport) Send(sessionID string, msg []byte, isBroadcast bool, to int) {
+	frame := binary.AppendUvarint(nil, uint64(len(sessionID)))
+	frame = append(frame, sessionID...)
+	if isBroadcast {
+		frame = append(frame, 1)
+	} else {
+		frame = append(frame, 0)
+	}
+	frame = append(frame, msg...)
+
+	for {
+		var err error
+		if to == 0 {
+			err = t.inner.Broadcast(t.ctx, frame)
+		} else if peer, ok := t.peers[to]; ok {
+			err = t.inner.Send(t.ctx, peer, frame)
+		} else {
+			err = fmt.Errorf("party %d does not take part", to)
+		}
+		if err == nil {
+			return
+		}
+		select {
+		case <-t.ctx.Done():
+			fmt.Fprintf(os.Stderr, "could not send a message of session %s: %v\n", sessionID, err)
+			return
+		case <-time.After(retryInterval):
+		}
+	}
+}
+
+// receive passes the messages of the peers to `party` until the transport is closed
+func (t *grpcTransport) receive(party *mobile.ECDSAParty) {
+	for msg := range t.inner.Receive() {
+		from, err := strconv.Atoi(msg.From.Id)
+		if err != nil {
+			continue
+		}
+		sessionID, isBroadcast, wireBytes, err := parseFrame(msg.WireBytes)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "dropped a message of party %d: %v\n", from, err)
+			continue
+		}
+		party.OnMessage(sessionID, wireBytes, from, isBroadcast)
+	}
+}
+
+func (t *grpcTransport) Close() error {
+	return t.inner.Close()
+}
+
+func parseFrame(frame []byte) (sessionID string, isBroadcast bool, msg []byte, err error) {
+	n, read := binary.Uvarint(frame)
+	if read <= 0 || uint64(len(frame)-read) < n+1 {
+		return "", false, nil, errors.New("malformed frame")
+	}
+	frame = frame[read:]
+	return string(frame[:n]), frame[n] == 1, frame[n+1:], nil
+}
+
+// loadTLS loads the certificate of the party and the CA that issued the certificates of all of the parties
+func loadTLS(certFile, keyFile, caFile string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	caPEM, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("no certificate found in %s", caFile)
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      pool,
+		ClientCAs:    pool,
+		MinVersion:   tls.VersionTLS13,
+	}, nil
+}
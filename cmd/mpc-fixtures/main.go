@@ -0,0 +1,59 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Command mpc-fixtures generates ECDSA keygen test fixtures for any number of parties, curve and Paillier size,
+// which ecdsa/keygen.LoadKeygenTestFixturesFromDir loads:
+//
+//	go run ./cmd/mpc-fixtures -dir ./fixtures -parties 5 -threshold 2 -curve secp256k1 -level 112
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/kisdex/mpc-lib/ecdsa/keygen"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+func main() {
+	dir := flag.String("dir", "fixtures", "the directory to write the fixtures to")
+	parties := flag.Int("parties", 3, "the number of parties")
+	threshold := flag.Int("threshold", 1, "the threshold; threshold+1 parties can sign")
+	curve := flag.String("curve", string(tss.Secp256k1), "the curve: secp256k1, p256, p384 or stark")
+	level := flag.Int("level", int(tss.SecurityLevel112), "the security level of the Paillier and ring-Pedersen moduli: 112, 128 or 140")
+	concurrency := flag.Int("concurrency", runtime.GOMAXPROCS(0), "the number of goroutines that search for safe primes")
+	timeout := flag.Duration("timeout", time.Hour, "the time limit of the whole run")
+	flag.Parse()
+
+	ec, ok := tss.GetCurveByName(tss.CurveName(*curve))
+	if name := tss.CurveName(*curve); !ok || (name != tss.Secp256k1 && name != tss.P256 && name != tss.P384 && name != tss.Stark) {
+		fail(fmt.Errorf("unknown curve %q", *curve))
+	}
+	if tss.SecurityLevel(*level).ModulusBits() == 0 {
+		fail(fmt.Errorf("unknown security level %d", *level))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	start := time.Now()
+	keys, _, err := keygen.GenerateKeygenTestFixtures(ctx, ec, *parties, *threshold, tss.SecurityLevel(*level), *concurrency)
+	if err != nil {
+		fail(err)
+	}
+	if err = keygen.WriteKeygenTestFixtures(*dir, keys); err != nil {
+		fail(err)
+	}
+	fmt.Printf("wrote the fixtures of %d parties to %s in %s\n", len(keys), *dir, time.Since(start).Round(time.Second))
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "mpc-fixtures:", err)
+	os.Exit(1)
+}
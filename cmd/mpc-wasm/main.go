@@ -0,0 +1,134 @@
+//go:build js && wasm
+
+// Command mpc-wasm runs a party of threshold ECDSA in JavaScript, e.g. a co-signer in a browser:
+//
+//	GOOS=js GOARCH=wasm go build -o mpc.wasm ./cmd/mpc-wasm
+//
+// Once it is loaded with the wasm_exec.js of the Go distribution, it sets globalThis.mpc to an object with the
+// function newECDSAParty(id, parties, threshold, send) -> Promise<party>, which wraps a party of the mobile package.
+// `parties` is a string of comma-separated ids, and the party sends its messages with
+// send(sessionID, msg, isBroadcast, to), msg being a Uint8Array. The party has the methods
+//
+//	onMessage(sessionID, msg, from, isBroadcast)
+//	setShareData(shareData) -> Promise<undefined>
+//	publicKey() -> Promise<Uint8Array>, the PKIX DER encoding of the threshold public key
+//	keyGen(sessionID, timeoutSeconds) -> Promise<Uint8Array>, the share data to store
+//	sign(sessionID, digest, timeoutSeconds) -> Promise<Uint8Array>, the ASN.1 DER signature
+//
+// Go cannot throw into JS, so every call that may fail returns a promise, which it rejects with an Error. The calls
+// that run the protocol wait for the messages of the other parties, which JS passes in with onMessage meanwhile.
+package main
+
+import (
+	"syscall/js"
+
+	"github.com/kisdex/mpc-lib/mobile"
+)
+
+// jsTransport sends the messages of a party with a JS function
+type jsTransport struct {
+	send js.Value
+}
+
+func (t jsTransport) Send(sessionID string, msg []byte, isBroadcast bool, to int) {
+	t.send.Invoke(sessionID, toUint8Array(msg), isBroadcast, to)
+}
+
+func main() {
+	js.Global().Set("mpc", js.ValueOf(map[string]any{
+		"newECDSAParty": js.FuncOf(newECDSAParty),
+	}))
+	// the functions must outlive main
+	select {}
+}
+
+func newECDSAParty(_ js.Value, args []js.Value) any {
+	if len(args) != 4 || args[3].Type() != js.TypeFunction {
+		return rejected("newECDSAParty(id, parties, threshold, send) takes 4 arguments, the last a function")
+	}
+	id, parties, threshold, send := args[0].Int(), args[1].String(), args[2].Int(), args[3]
+	return promise(func() (any, error) {
+		p, err := mobile.NewECDSAParty(id, parties, threshold, jsTransport{send: send})
+		if err != nil {
+			return nil, err
+		}
+		return partyObject(p), nil
+	})
+}
+
+func partyObject(p *mobile.ECDSAParty) js.Value {
+	return js.ValueOf(map[string]any{
+		"onMessage": js.FuncOf(func(_ js.Value, args []js.Value) any {
+			p.OnMessage(args[0].String(), fromUint8Array(args[1]), args[2].Int(), args[3].Bool())
+			return nil
+		}),
+		"setShareData": js.FuncOf(func(_ js.Value, args []js.Value) any {
+			shareData := fromUint8Array(args[0])
+			return promise(func() (any, error) {
+				return nil, p.SetShareData(shareData)
+			})
+		}),
+		"publicKey": js.FuncOf(func(js.Value, []js.Value) any {
+			return promise(func() (any, error) {
+				return bytesResult(p.PublicKey())
+			})
+		}),
+		"keyGen": js.FuncOf(func(_ js.Value, args []js.Value) any {
+			sessionID, timeout := args[0].String(), int64(args[1].Int())
+			return promise(func() (any, error) {
+				return bytesResult(p.KeyGen(sessionID, timeout))
+			})
+		}),
+		"sign": js.FuncOf(func(_ js.Value, args []js.Value) any {
+			sessionID, digest, timeout := args[0].String(), fromUint8Array(args[1]), int64(args[2].Int())
+			return promise(func() (any, error) {
+				return bytesResult(p.Sign(sessionID, digest, timeout))
+			})
+		}),
+	})
+}
+
+// promise runs `f` in a goroutine, since a JS callback must not block, and returns a promise of its result
+func promise(f func() (any, error)) js.Value {
+	executor := js.FuncOf(func(_ js.Value, args []js.Value) any {
+		resolve, reject := args[0], args[1]
+		go func() {
+			out, err := f()
+			if err != nil {
+				reject.Invoke(jsError(err.Error()))
+				return
+			}
+			resolve.Invoke(out)
+		}()
+		return nil
+	})
+	defer executor.Release()
+	return js.Global().Get("Promise").New(executor)
+}
+
+func rejected(msg string) js.Value {
+	return js.Global().Get("Promise").Call("reject", jsError(msg))
+}
+
+func bytesResult(b []byte, err error) (any, error) {
+	if err != nil {
+		return nil, err
+	}
+	return toUint8Array(b), nil
+}
+
+func jsError(msg string) js.Value {
+	return js.Global().Get("Error").New(msg)
+}
+
+func toUint8Array(b []byte) js.Value {
+	a := js.Global().Get("Uint8Array").New(len(b))
+	js.CopyBytesToJS(a, b)
+	return a
+}
+
+func fromUint8Array(a js.Value) []byte {
+	b := make([]byte, a.Length())
+	js.CopyBytesToGo(b, a)
+	return b
+}
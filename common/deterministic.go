@@ -0,0 +1,46 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+//go:build tss_deterministic
+
+package common
+
+import (
+	"crypto/sha256"
+	"io"
+	"sync"
+
+	"golang.org/x/crypto/chacha20"
+)
+
+type deterministicReader struct {
+	mtx    sync.Mutex
+	stream *chacha20.Cipher
+}
+
+// NewDeterministicReader returns a reader of the ChaCha20 key stream keyed by the SHA-256 of `seed`. It is safe for
+// concurrent use.
+//
+// With SetRandomSource(NewDeterministicReader(seed)), a run of a protocol draws the same values for the same seed as
+// long as its parties draw them in the same order: set the concurrency of the parties to 1, give them fixed
+// pre-parameters, since the safe primes are searched for concurrently, and deliver their messages with
+// test.RunSequentially. Like SetRandomSource, it only exists in builds with the tss_deterministic tag.
+func NewDeterministicReader(seed []byte) io.Reader {
+	key := sha256.Sum256(seed)
+	stream, err := chacha20.NewUnauthenticatedCipher(key[:], make([]byte, chacha20.NonceSize))
+	if err != nil {
+		panic(err) // the key and nonce sizes are fixed
+	}
+	return &deterministicReader{stream: stream}
+}
+
+func (r *deterministicReader) Read(p []byte) (int, error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	clear(p)
+	r.stream.XORKeyStream(p, p)
+	return len(p), nil
+}
@@ -0,0 +1,34 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+//go:build tss_deterministic
+
+package common_test
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/common"
+)
+
+func TestDeterministicRandomSource(t *testing.T) {
+	defer common.SetRandomSource(nil)
+	draw := func(seed string) []*big.Int {
+		common.SetRandomSource(common.NewDeterministicReader([]byte(seed)))
+		return []*big.Int{common.MustGetRandomInt(randomIntBitLen), common.GetRandomPrimeInt(256)}
+	}
+	first := draw("seed")
+	assert.Equal(t, first, draw("seed"), "the same seed should give the same values")
+	assert.NotEqual(t, first, draw("another seed"), "another seed should give other values")
+	assert.True(t, first[1].ProbablyPrime(50), "rand prime should be prime")
+
+	common.SetRandomSource(nil)
+	assert.Equal(t, rand.Reader, common.RandomSource(), "nil should restore crypto/rand")
+}
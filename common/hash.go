@@ -10,18 +10,55 @@ import (
 	"crypto"
 	_ "crypto/sha512"
 	"encoding/binary"
+	"fmt"
+	"hash"
 	"math/big"
+	"sync/atomic"
+
+	_ "golang.org/x/crypto/blake2b"
+	_ "golang.org/x/crypto/sha3"
 )
 
 const (
 	hashInputDelimiter = byte('$')
 )
 
+// transcriptHash is the hash function of the functions below, see SetTranscriptHash
+var transcriptHash atomic.Uint32
+
+func init() {
+	transcriptHash.Store(uint32(crypto.SHA512_256))
+}
+
+// SetTranscriptHash sets the hash function used by the functions of this file, and so by the Fiat-Shamir challenges
+// of the proofs, the commitments and the Paillier key proofs. It may be crypto.SHA512_256 (the default),
+// crypto.SHA3_256 or crypto.BLAKE2b_256. All of the parties must use the same function, and it must be set before
+// any protocol runs, since it applies to the whole process.
+func SetTranscriptHash(h crypto.Hash) error {
+	switch h {
+	case crypto.SHA512_256, crypto.SHA3_256, crypto.BLAKE2b_256:
+	default:
+		return fmt.Errorf("unsupported transcript hash %v", h)
+	}
+	transcriptHash.Store(uint32(h))
+	return nil
+}
+
+// TranscriptHash returns the hash function set with SetTranscriptHash
+func TranscriptHash() crypto.Hash {
+	return crypto.Hash(transcriptHash.Load())
+}
+
+func newTranscriptHash() hash.Hash {
+	return TranscriptHash().New()
+}
+
 // SHA-512/256 is protected against length extension attacks and is more performant than SHA-256 on 64-bit architectures.
 // https://en.wikipedia.org/wiki/Template:Comparison_of_SHA_functions
+// The functions keep their names when another hash is set with SetTranscriptHash.
 func SHA512_256(in ...[]byte) []byte {
 	var data []byte
-	state := crypto.SHA512_256.New()
+	state := newTranscriptHash()
 	inLen := len(in)
 	if inLen == 0 {
 		return nil
@@ -49,7 +86,7 @@ func SHA512_256(in ...[]byte) []byte {
 	// n < len(data) or an error will never happen.
 	// see: https://golang.org/pkg/hash/#Hash and https://github.com/golang/go/wiki/Hashing#the-hashhash-interface
 	if _, err := state.Write(data); err != nil {
-		Logger.Errorf("SHA512_256 Write() failed: %v", err)
+		DefaultLogger().Errorf("SHA512_256 Write() failed: %v", err)
 		return nil
 	}
 	return state.Sum(nil)
@@ -57,7 +94,7 @@ func SHA512_256(in ...[]byte) []byte {
 
 func SHA512_256i(in ...*big.Int) *big.Int {
 	var data []byte
-	state := crypto.SHA512_256.New()
+	state := newTranscriptHash()
 	inLen := len(in)
 	if inLen == 0 {
 		return nil
@@ -87,7 +124,7 @@ func SHA512_256i(in ...*big.Int) *big.Int {
 	// n < len(data) or an error will never happen.
 	// see: https://golang.org/pkg/hash/#Hash and https://github.com/golang/go/wiki/Hashing#the-hashhash-interface
 	if _, err := state.Write(data); err != nil {
-		Logger.Errorf("SHA512_256i Write() failed: %v", err)
+		DefaultLogger().Errorf("SHA512_256i Write() failed: %v", err)
 		return nil
 	}
 	return new(big.Int).SetBytes(state.Sum(nil))
@@ -97,7 +134,7 @@ func SHA512_256i(in ...*big.Int) *big.Int {
 func SHA512_256i_TAGGED(tag []byte, in ...*big.Int) *big.Int {
 	tagBz := SHA512_256(tag)
 	var data []byte
-	state := crypto.SHA512_256.New()
+	state := newTranscriptHash()
 	state.Write(tagBz)
 	state.Write(tagBz)
 	inLen := len(in)
@@ -133,15 +170,21 @@ func SHA512_256i_TAGGED(tag []byte, in ...*big.Int) *big.Int {
 	// n < len(data) or an error will never happen.
 	// see: https://golang.org/pkg/hash/#Hash and https://github.com/golang/go/wiki/Hashing#the-hashhash-interface
 	if _, err := state.Write(data); err != nil {
-		Logger.Error(err)
+		DefaultLogger().Errorf("%v", err)
 		return nil
 	}
 	return new(big.Int).SetBytes(state.Sum(nil))
 }
 
+// SHA512_256i_DOMAIN is SHA512_256i_TAGGED with a domain string, such as the name of the proof whose challenge is
+// derived, bound into the tag, so that hashes made for different purposes differ even on the same tag and input
+func SHA512_256i_DOMAIN(domain string, tag []byte, in ...*big.Int) *big.Int {
+	return SHA512_256i_TAGGED(SHA512_256([]byte(domain), tag), in...)
+}
+
 func SHA512_256iOne(in *big.Int) *big.Int {
 	var data []byte
-	state := crypto.SHA512_256.New()
+	state := newTranscriptHash()
 	if in == nil {
 		return nil
 	}
@@ -149,7 +192,7 @@ func SHA512_256iOne(in *big.Int) *big.Int {
 	// n < len(data) or an error will never happen.
 	// see: https://golang.org/pkg/hash/#Hash and https://github.com/golang/go/wiki/Hashing#the-hashhash-interface
 	if _, err := state.Write(data); err != nil {
-		Logger.Errorf("SHA512_256iOne Write() failed: %v", err)
+		DefaultLogger().Errorf("SHA512_256iOne Write() failed: %v", err)
 		return nil
 	}
 	return new(big.Int).SetBytes(state.Sum(nil))
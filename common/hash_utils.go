@@ -7,9 +7,16 @@
 package common
 
 import (
+	"crypto/elliptic"
 	"math/big"
 )
 
+// CurveInts identifies a curve in a hash input by its field, order, coefficient b and base point
+func CurveInts(ec elliptic.Curve) []*big.Int {
+	params := ec.Params()
+	return []*big.Int{params.P, params.N, params.B, params.Gx, params.Gy}
+}
+
 // RejectionSample implements the rejection sampling logic for converting a
 // SHA512/256 hash to a value between 0-q
 func RejectionSample(q *big.Int, eHash *big.Int) *big.Int { // e' = eHash
@@ -7,6 +7,7 @@
 package common_test
 
 import (
+	"crypto"
 	"math/big"
 	"reflect"
 	"testing"
@@ -60,3 +61,48 @@ func TestRejectionSample(t *testing.T) {
 		})
 	}
 }
+
+func TestSHA512_256i_DOMAIN(t *testing.T) {
+	session, in := []byte("session"), []*big.Int{big.NewInt(1), big.NewInt(2)}
+	h := common.SHA512_256i_DOMAIN("a", session, in...)
+	if h.Cmp(common.SHA512_256i_DOMAIN("a", session, in...)) != 0 {
+		t.Error("the hash must be deterministic")
+	}
+	if h.Cmp(common.SHA512_256i_DOMAIN("b", session, in...)) == 0 {
+		t.Error("hashes in different domains must differ")
+	}
+	if h.Cmp(common.SHA512_256i_TAGGED(session, in...)) == 0 {
+		t.Error("a hash in a domain must differ from the plain tagged hash")
+	}
+	// the domain and the tag are length-prefixed, so moving bytes between them changes the hash
+	if h.Cmp(common.SHA512_256i_DOMAIN("as", []byte("ession"), in...)) == 0 {
+		t.Error("the domain and the tag must not be concatenated")
+	}
+}
+
+func TestSetTranscriptHash(t *testing.T) {
+	defer func() { _ = common.SetTranscriptHash(crypto.SHA512_256) }()
+	in := big.NewInt(123)
+	sha512_256 := common.SHA512_256i(in)
+
+	for _, h := range []crypto.Hash{crypto.SHA3_256, crypto.BLAKE2b_256} {
+		if err := common.SetTranscriptHash(h); err != nil {
+			t.Fatal(err)
+		}
+		if common.TranscriptHash() != h {
+			t.Errorf("got transcript hash %v, want %v", common.TranscriptHash(), h)
+		}
+		if other := common.SHA512_256i(in); other.Cmp(sha512_256) == 0 || other.BitLen() > 256 {
+			t.Errorf("unexpected %v hash %x", h, other)
+		}
+	}
+	if err := common.SetTranscriptHash(crypto.MD5); err == nil {
+		t.Error("an unsupported hash must be rejected")
+	}
+	if err := common.SetTranscriptHash(crypto.SHA512_256); err != nil {
+		t.Fatal(err)
+	}
+	if common.SHA512_256i(in).Cmp(sha512_256) != 0 {
+		t.Error("the default hash must be restored")
+	}
+}
@@ -13,6 +13,7 @@ package common
 
 import (
 	"math/big"
+	"sync"
 )
 
 // modInt is a *big.Int that performs all of its arithmetic with modular reduction.
@@ -24,6 +25,12 @@ var (
 	two  = big.NewInt(2)
 )
 
+// scratch holds the intermediate products of the modular arithmetic, which would otherwise be garbage after each
+// operation. The ints are zeroized when they are put back, as they may hold products of secrets.
+var scratch = sync.Pool{
+	New: func() any { return new(big.Int) },
+}
+
 func ModInt(mod *big.Int) *modInt {
 	return (*modInt)(mod)
 }
@@ -34,15 +41,23 @@ func (mi *modInt) Mod(x *big.Int) *big.Int {
 }
 
 func (mi *modInt) Add(x, y *big.Int) *big.Int {
-	i := new(big.Int)
-	i.Add(x, y)
-	return i.Mod(i, mi.i())
+	return mi.AddTo(new(big.Int), x, y)
+}
+
+// AddTo sets z to x + y mod the modulus and returns z; z may be x or y
+func (mi *modInt) AddTo(z, x, y *big.Int) *big.Int {
+	z.Add(x, y)
+	return mi.reduce(z, z)
 }
 
 func (mi *modInt) Sub(x, y *big.Int) *big.Int {
-	i := new(big.Int)
-	i.Sub(x, y)
-	return i.Mod(i, mi.i())
+	return mi.SubTo(new(big.Int), x, y)
+}
+
+// SubTo sets z to x - y mod the modulus and returns z; z may be x or y
+func (mi *modInt) SubTo(z, x, y *big.Int) *big.Int {
+	z.Sub(x, y)
+	return mi.reduce(z, z)
 }
 
 func (mi *modInt) Div(x, y *big.Int) *big.Int {
@@ -52,15 +67,45 @@ func (mi *modInt) Div(x, y *big.Int) *big.Int {
 }
 
 func (mi *modInt) Mul(x, y *big.Int) *big.Int {
-	i := new(big.Int)
-	i.Mul(x, y)
-	return i.Mod(i, mi.i())
+	return mi.MulTo(new(big.Int), x, y)
+}
+
+// MulTo sets z to x * y mod the modulus and returns z; z may be x or y. The product is computed in a pooled int, so
+// that a chain of multiplications into the same z allocates nothing once the ints have grown.
+func (mi *modInt) MulTo(z, x, y *big.Int) *big.Int {
+	xy := scratch.Get().(*big.Int)
+	defer putScratch(xy)
+	xy.Mul(x, y)
+	return mi.reduce(z, xy)
 }
 
 func (mi *modInt) Exp(x, y *big.Int) *big.Int {
 	return new(big.Int).Exp(x, y, mi.i())
 }
 
+// ExpTo sets z to x^y mod the modulus and returns z; z may be x or y
+func (mi *modInt) ExpTo(z, x, y *big.Int) *big.Int {
+	return z.Exp(x, y, mi.i())
+}
+
+// SecretExp returns x^e for a secret exponent e. The exponentiation of math/big takes a time and accesses memory in a
+// way that depends on the exponent, so SecretExp never exponentiates by e itself. When `order` is a multiple of the
+// order of x, e.g. phi of the modulus, it uses e + r*order for a random 64-bit r; otherwise, with a nil `order`, it
+// computes x^(e+r) * x^-r for a random r 128 bits longer than e, so that each exponent is independent of e.
+// This mitigates timing side channels but is not constant-time.
+func (mi *modInt) SecretExp(x, e, order *big.Int) *big.Int {
+	if order != nil {
+		r := MustGetRandomInt(64)
+		return mi.Exp(x, new(big.Int).Add(e, r.Mul(r, order)))
+	}
+	r := MustGetRandomInt(e.BitLen() + 128)
+	xInv := mi.ModInverse(x)
+	if xInv == nil {
+		return mi.Exp(x, e)
+	}
+	return mi.Mul(mi.Exp(x, new(big.Int).Add(e, r)), mi.Exp(xInv, r))
+}
+
 func (mi *modInt) ModInverse(g *big.Int) *big.Int {
 	return new(big.Int).ModInverse(g, mi.i())
 }
@@ -87,10 +132,57 @@ func (mi *modInt) i() *big.Int {
 	return (*big.Int)(mi)
 }
 
+// reduce sets z to x mod the modulus, in [0, |modulus|) like big.Int.Mod, with the quotient in a pooled int rather than
+// a new one
+func (mi *modInt) reduce(z, x *big.Int) *big.Int {
+	q := scratch.Get().(*big.Int)
+	defer putScratch(q)
+	q.QuoRem(x, mi.i(), z)
+	if z.Sign() < 0 {
+		if mi.i().Sign() < 0 {
+			return z.Sub(z, mi.i())
+		}
+		return z.Add(z, mi.i())
+	}
+	return z
+}
+
+func putScratch(x *big.Int) {
+	Zeroize(x)
+	scratch.Put(x)
+}
+
 func IsInInterval(b *big.Int, bound *big.Int) bool {
 	return b.Cmp(bound) == -1 && b.Cmp(zero) >= 0
 }
 
+// IsInSymmetricInterval returns true if b is in (-bound, bound), see GetRandomSignedInt
+func IsInSymmetricInterval(b *big.Int, bound *big.Int) bool {
+	return b != nil && new(big.Int).Abs(b).Cmp(bound) == -1
+}
+
+// SignedIntBytes encodes b with a first byte for its sign, 1 if it is negative and 0 otherwise, which big.Int.Bytes
+// drops
+func SignedIntBytes(b *big.Int) []byte {
+	sign := byte(0)
+	if b.Sign() < 0 {
+		sign = 1
+	}
+	return append([]byte{sign}, b.Bytes()...)
+}
+
+// SignedIntFromBytes decodes the output of SignedIntBytes, or returns nil if `bz` has no valid sign byte
+func SignedIntFromBytes(bz []byte) *big.Int {
+	if len(bz) == 0 || 1 < bz[0] {
+		return nil
+	}
+	b := new(big.Int).SetBytes(bz[1:])
+	if bz[0] == 1 {
+		b.Neg(b)
+	}
+	return b
+}
+
 func AppendBigIntToBytesSlice(commonBytes []byte, appended *big.Int) []byte {
 	resultBytes := make([]byte, len(commonBytes), len(commonBytes)+len(appended.Bytes()))
 	copy(resultBytes, commonBytes)
@@ -0,0 +1,67 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package common_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/common"
+)
+
+func TestSecretExp(t *testing.T) {
+	p, q := common.GetRandomPrimeInt(256), common.GetRandomPrimeInt(256)
+	N := new(big.Int).Mul(p, q)
+	phi := new(big.Int).Mul(new(big.Int).Sub(p, big.NewInt(1)), new(big.Int).Sub(q, big.NewInt(1)))
+	modN := common.ModInt(N)
+
+	x := common.GetRandomPositiveRelativelyPrimeInt(N)
+	for _, e := range []*big.Int{big.NewInt(0), big.NewInt(1), common.GetRandomPositiveInt(N), big.NewInt(-5)} {
+		want := new(big.Int).Exp(x, e, N)
+		assert.Equal(t, 0, want.Cmp(modN.SecretExp(x, e, phi)), "with the order, e = %v", e)
+		assert.Equal(t, 0, want.Cmp(modN.SecretExp(x, e, nil)), "without the order, e = %v", e)
+	}
+}
+
+func TestModIntTo(t *testing.T) {
+	N := common.GetRandomPrimeInt(256)
+	modN := common.ModInt(N)
+	for _, xy := range [][2]*big.Int{
+		{common.GetRandomPositiveInt(N), common.GetRandomPositiveInt(N)},
+		{big.NewInt(-7), common.MustGetRandomInt(512)},
+		{new(big.Int).Neg(N), big.NewInt(3)},
+	} {
+		x, y := xy[0], xy[1]
+		add, sub, mul := new(big.Int).Add(x, y), new(big.Int).Sub(x, y), new(big.Int).Mul(x, y)
+		add.Mod(add, N)
+		sub.Mod(sub, N)
+		mul.Mod(mul, N)
+		exp := new(big.Int).Exp(x, y, N)
+
+		assert.Equal(t, 0, add.Cmp(modN.AddTo(new(big.Int), x, y)))
+		assert.Equal(t, 0, sub.Cmp(modN.SubTo(new(big.Int), x, y)))
+		assert.Equal(t, 0, mul.Cmp(modN.MulTo(new(big.Int), x, y)))
+		assert.Equal(t, 0, exp.Cmp(modN.ExpTo(new(big.Int), x, y)))
+		// the result may be one of the operands
+		z := new(big.Int).Set(x)
+		assert.Equal(t, 0, mul.Cmp(modN.MulTo(z, z, y)))
+		z.Set(y)
+		assert.Equal(t, 0, sub.Cmp(modN.SubTo(z, x, z)))
+	}
+}
+
+func TestSignedIntBytes(t *testing.T) {
+	for _, b := range []*big.Int{big.NewInt(0), big.NewInt(1), big.NewInt(-1), common.MustGetRandomInt(256), new(big.Int).Neg(common.MustGetRandomInt(256))} {
+		assert.Equal(t, 0, b.Cmp(common.SignedIntFromBytes(common.SignedIntBytes(b))), "b = %v", b)
+	}
+	assert.Nil(t, common.SignedIntFromBytes(nil))
+	assert.Nil(t, common.SignedIntFromBytes([]byte{2, 1}))
+	assert.True(t, common.IsInSymmetricInterval(big.NewInt(-2), big.NewInt(3)))
+	assert.False(t, common.IsInSymmetricInterval(big.NewInt(-3), big.NewInt(3)))
+}
@@ -7,7 +7,90 @@
 package common
 
 import (
-	"github.com/ipfs/go-log"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync/atomic"
 )
 
-var Logger = log.Logger("tss-lib")
+// Logger is the logging interface of the library. It is satisfied by a *zap.SugaredLogger, e.g. from zaplog.New;
+// NewSlogLogger adapts a *slog.Logger to it. A logger may be set for each party with tss.Parameters.SetLogger, and for
+// the code that does not run in a party with SetDefaultLogger.
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+type (
+	slogLogger struct {
+		l *slog.Logger
+	}
+
+	// loggerBox lets an atomic.Value hold loggers of different types
+	loggerBox struct {
+		Logger
+	}
+)
+
+var (
+	defaultLevel  = new(slog.LevelVar)
+	defaultLogger atomic.Value
+)
+
+func init() {
+	defaultLevel.Set(slog.LevelError)
+	handler := slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: defaultLevel})
+	defaultLogger.Store(loggerBox{NewSlogLogger(slog.New(handler).With("logger", "tss-lib"))})
+}
+
+// DefaultLogger returns the logger set with SetDefaultLogger. Unless it was replaced, it writes the errors to stderr.
+func DefaultLogger() Logger {
+	return defaultLogger.Load().(loggerBox).Logger
+}
+
+// SetDefaultLogger replaces the logger of the parties that have none and of the code that does not run in a party
+func SetDefaultLogger(logger Logger) {
+	defaultLogger.Store(loggerBox{logger})
+}
+
+// SetLogLevel sets the level of the built-in default logger to "debug", "info", "warn" or "error"
+func SetLogLevel(level string) error {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return err
+	}
+	defaultLevel.Set(l)
+	return nil
+}
+
+// NewSlogLogger adapts a *slog.Logger to Logger
+func NewSlogLogger(l *slog.Logger) Logger {
+	return &slogLogger{l}
+}
+
+func (s *slogLogger) Debugf(format string, args ...interface{}) {
+	s.logf(slog.LevelDebug, format, args...)
+}
+
+func (s *slogLogger) Infof(format string, args ...interface{}) {
+	s.logf(slog.LevelInfo, format, args...)
+}
+
+func (s *slogLogger) Warnf(format string, args ...interface{}) {
+	s.logf(slog.LevelWarn, format, args...)
+}
+
+func (s *slogLogger) Errorf(format string, args ...interface{}) {
+	s.logf(slog.LevelError, format, args...)
+}
+
+func (s *slogLogger) logf(level slog.Level, format string, args ...interface{}) {
+	ctx := context.Background()
+	if !s.l.Enabled(ctx, level) {
+		return
+	}
+	s.l.Log(ctx, level, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,41 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package common_test
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/common"
+)
+
+func TestSlogLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := common.NewSlogLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo})))
+	logger.Debugf("hidden %d", 1)
+	logger.Warnf("round %d timed out", 2)
+	assert.NotContains(t, buf.String(), "hidden")
+	assert.Contains(t, buf.String(), "level=WARN")
+	assert.Contains(t, buf.String(), `msg="round 2 timed out"`)
+}
+
+func TestSetDefaultLogger(t *testing.T) {
+	prev := common.DefaultLogger()
+	defer common.SetDefaultLogger(prev)
+
+	logger := common.NewSlogLogger(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	common.SetDefaultLogger(logger)
+	assert.Equal(t, logger, common.DefaultLogger())
+
+	assert.NoError(t, common.SetLogLevel("debug"))
+	assert.NoError(t, common.SetLogLevel("error"))
+	assert.Error(t, common.SetLogLevel("verbose"))
+}
@@ -0,0 +1,92 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+type (
+	// Migration upgrades the fields of the JSON object of save data by one version, e.g. renames a field or fills in a
+	// new one. It changes `fields` in place.
+	Migration func(fields map[string]json.RawMessage) error
+
+	// Migrations is the registry of the migrations of a save data format from its older versions to the current one.
+	// The format has its version in a field of the JSON object; save data written before the format had a version,
+	// without the field, is version 0.
+	Migrations struct {
+		versionField string
+		current      uint32
+
+		mtx   sync.RWMutex
+		steps map[uint32]Migration
+	}
+)
+
+// NewMigrations returns the registry of a format with the version in the field `versionField`, at version `current`
+func NewMigrations(versionField string, current uint32) *Migrations {
+	return &Migrations{versionField: versionField, current: current, steps: make(map[uint32]Migration)}
+}
+
+// Current returns the version that Migrate upgrades to
+func (m *Migrations) Current() uint32 {
+	return m.current
+}
+
+// Register registers the migration from the version `from` to the next one. It panics if `from` is not older than
+// the current version or already has a migration, as these are programming errors of a release.
+func (m *Migrations) Register(from uint32, migration Migration) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if m.current <= from {
+		panic(fmt.Errorf("a migration from version %d must be older than the current version %d", from, m.current))
+	}
+	if _, ok := m.steps[from]; ok {
+		panic(fmt.Errorf("a migration from version %d is already registered", from))
+	}
+	m.steps[from] = migration
+}
+
+// Migrate upgrades the JSON encoding of save data to the current version, one version at a time. Save data of a
+// later version is an error: this release would drop the fields that it does not know.
+func (m *Migrations) Migrate(data []byte) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	var version uint32
+	if raw, ok := fields[m.versionField]; ok {
+		if err := json.Unmarshal(raw, &version); err != nil {
+			return nil, fmt.Errorf("invalid save data version: %w", err)
+		}
+	}
+	if version == m.current {
+		return data, nil
+	}
+	if m.current < version {
+		return nil, fmt.Errorf("save data version %d is newer than the supported version %d", version, m.current)
+	}
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+	for ; version < m.current; version++ {
+		migration, ok := m.steps[version]
+		if !ok {
+			return nil, fmt.Errorf("no migration of save data from version %d", version)
+		}
+		if err := migration(fields); err != nil {
+			return nil, fmt.Errorf("migrating save data from version %d: %w", version, err)
+		}
+	}
+	raw, err := json.Marshal(m.current)
+	if err != nil {
+		return nil, err
+	}
+	fields[m.versionField] = raw
+	return json.Marshal(fields)
+}
@@ -0,0 +1,48 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package common_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/common"
+)
+
+func TestMigrations(t *testing.T) {
+	migrations := common.NewMigrations("Version", 2)
+	migrations.Register(0, func(map[string]json.RawMessage) error { return nil })
+	// version 2 renames Share to Xi
+	migrations.Register(1, func(fields map[string]json.RawMessage) error {
+		fields["Xi"] = fields["Share"]
+		delete(fields, "Share")
+		return nil
+	})
+	assert.Panics(t, func() { migrations.Register(1, nil) }, "a second migration from the same version")
+	assert.Panics(t, func() { migrations.Register(2, nil) }, "a migration from the current version")
+
+	type saveData struct {
+		Version uint32
+		Xi      int
+	}
+	for _, old := range []string{`{"Share":7}`, `{"Version":1,"Share":7}`, `{"Version":2,"Xi":7}`} {
+		migrated, err := migrations.Migrate([]byte(old))
+		if !assert.NoError(t, err, old) {
+			continue
+		}
+		var data saveData
+		assert.NoError(t, json.Unmarshal(migrated, &data))
+		assert.Equal(t, saveData{Version: 2, Xi: 7}, data, old)
+	}
+
+	_, err := migrations.Migrate([]byte(`{"Version":3,"Xi":7}`))
+	assert.Error(t, err, "save data of a later release")
+	_, err = common.NewMigrations("Version", 1).Migrate([]byte(`{"Xi":7}`))
+	assert.Error(t, err, "no migration from version 0")
+}
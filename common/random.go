@@ -14,16 +14,18 @@ package common
 import (
 	"crypto/rand"
 	"fmt"
+	"io"
 	"math/big"
 
 	"github.com/pkg/errors"
 )
 
 const (
-	mustGetRandomIntMaxBits = 5000
+	// the fac proof samples mod q^3 * N0 * NCap, up to 3*384 + 2*4096 bits on P-384 with 4096-bit moduli
+	mustGetRandomIntMaxBits = 10000
 )
 
-// MustGetRandomInt panics if it is unable to gather entropy from `rand.Reader` or when `bits` is <= 0
+// MustGetRandomInt panics if it is unable to gather entropy from crypto/rand or when `bits` is <= 0
 func MustGetRandomInt(bits int) *big.Int {
 	if bits <= 0 || mustGetRandomIntMaxBits < bits {
 		panic(fmt.Errorf("MustGetRandomInt: bits should be positive, non-zero and less than %d", mustGetRandomIntMaxBits))
@@ -33,7 +35,7 @@ func MustGetRandomInt(bits int) *big.Int {
 	max = max.Exp(two, big.NewInt(int64(bits)), nil).Sub(max, one)
 
 	// Generate cryptographically strong pseudo-random int between 0 - max
-	n, err := rand.Int(rand.Reader, max)
+	n, err := rand.Int(randomSource(), max)
 	if err != nil {
 		panic(errors.Wrap(err, "rand.Int failure in MustGetRandomInt!"))
 	}
@@ -54,24 +56,45 @@ func GetRandomPositiveInt(lessThan *big.Int) *big.Int {
 	return try
 }
 
+// GetRandomSignedInt returns a random integer in (-bound, bound), e.g. in the interval ±2^{ell+epsilon} from which the
+// provers of CGG21 sample their masks
+func GetRandomSignedInt(bound *big.Int) *big.Int {
+	if bound == nil || zero.Cmp(bound) != -1 {
+		return nil
+	}
+	// 2*bound - 1 values, shifted from [0, 2*bound - 1) down to (-bound, bound)
+	width := new(big.Int).Lsh(bound, 1)
+	try := GetRandomPositiveInt(width.Sub(width, one))
+	return try.Sub(try, bound).Add(try, one)
+}
+
 func GetRandomPrimeInt(bits int) *big.Int {
 	if bits <= 0 {
 		return nil
 	}
-	try, err := rand.Prime(rand.Reader, bits)
+	source := randomSource()
+	if source != rand.Reader {
+		// rand.Prime reads a byte at random from the source, so only the fallback is reproducible
+		return getRandomPrimeIntFallback(bits)
+	}
+	try, err := rand.Prime(source, bits)
 	if err != nil ||
 		try.Cmp(zero) == 0 {
-		// fallback to older method
-		for {
-			try = MustGetRandomInt(bits)
-			if probablyPrime(try) {
-				break
-			}
-		}
+		try = getRandomPrimeIntFallback(bits)
 	}
 	return try
 }
 
+// getRandomPrimeIntFallback is the older method of GetRandomPrimeInt
+func getRandomPrimeIntFallback(bits int) *big.Int {
+	for {
+		try := MustGetRandomInt(bits)
+		if probablyPrime(try) {
+			return try
+		}
+	}
+}
+
 // Generate a random element in the group of all the elements in Z/nZ that
 // has a multiplicative inverse.
 func GetRandomPositiveRelativelyPrimeInt(n *big.Int) *big.Int {
@@ -97,6 +120,17 @@ func IsNumberInMultiplicativeGroup(n, v *big.Int) bool {
 		gcd.GCD(nil, nil, v, n).Cmp(one) == 0
 }
 
+// AreNumbersInMultiplicativeGroup returns true if every one of `vs` is in Z*_n, e.g. the ring-Pedersen commitments
+// of a proof received from a peer
+func AreNumbersInMultiplicativeGroup(n *big.Int, vs ...*big.Int) bool {
+	for _, v := range vs {
+		if !IsNumberInMultiplicativeGroup(n, v) {
+			return false
+		}
+	}
+	return true
+}
+
 //	Return a random generator of RQn with high probability.
 //	THIS METHOD ONLY WORKS IF N IS THE PRODUCT OF TWO SAFE PRIMES!
 //
@@ -125,7 +159,7 @@ func GetRandomBytes(length int) ([]byte, error) {
 	}
 
 	buf := make([]byte, length)
-	_, err := rand.Read(buf)
+	_, err := io.ReadFull(randomSource(), buf)
 	if err != nil {
 		return nil, err
 	}
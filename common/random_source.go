@@ -0,0 +1,20 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+//go:build !tss_deterministic
+
+package common
+
+import (
+	"crypto/rand"
+	"io"
+)
+
+// randomSource returns crypto/rand.Reader, which the protocols draw all of their randomness from. Only test builds
+// with the tss_deterministic tag can replace it, see SetRandomSource.
+func randomSource() io.Reader {
+	return rand.Reader
+}
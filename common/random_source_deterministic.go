@@ -0,0 +1,43 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+//go:build tss_deterministic
+
+package common
+
+import (
+	"crypto/rand"
+	"io"
+	"sync"
+)
+
+var (
+	randomSourceMtx sync.RWMutex
+	replacedSource  io.Reader = rand.Reader
+)
+
+// SetRandomSource replaces crypto/rand as the source of the random values of this package, which the protocols draw
+// all of their randomness from; nil restores crypto/rand. It only exists in test builds with the tss_deterministic
+// tag, e.g. `go test -tags tss_deterministic ./...`: see NewDeterministicReader.
+func SetRandomSource(source io.Reader) {
+	randomSourceMtx.Lock()
+	defer randomSourceMtx.Unlock()
+	if source == nil {
+		source = rand.Reader
+	}
+	replacedSource = source
+}
+
+// RandomSource returns the source set with SetRandomSource, or crypto/rand.Reader
+func RandomSource() io.Reader {
+	return randomSource()
+}
+
+func randomSource() io.Reader {
+	randomSourceMtx.RLock()
+	defer randomSourceMtx.RUnlock()
+	return replacedSource
+}
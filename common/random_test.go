@@ -40,6 +40,18 @@ func TestGetRandomPositiveRelativelyPrimeInt(t *testing.T) {
 	// TODO test for relative primeness
 }
 
+func TestGetRandomSignedInt(t *testing.T) {
+	bound := big.NewInt(3)
+	seen := make(map[int64]bool)
+	for i := 0; i < 200; i++ {
+		rnd := common.GetRandomSignedInt(bound)
+		assert.True(t, common.IsInSymmetricInterval(rnd, bound), "rand int should be in (-3, 3), got %v", rnd)
+		seen[rnd.Int64()] = true
+	}
+	assert.Len(t, seen, 5, "every value of (-3, 3) should be drawn")
+	assert.Nil(t, common.GetRandomSignedInt(big.NewInt(0)))
+}
+
 func TestGetRandomPrimeInt(t *testing.T) {
 	prime := common.GetRandomPrimeInt(randomIntBitLen)
 	assert.NotZero(t, prime, "rand prime should not be zero")
@@ -13,13 +13,16 @@ package common
 
 import (
 	"context"
-	"crypto/rand"
 	"errors"
 	"fmt"
 	"io"
 	"math/big"
+	"math/bits"
 	"sync"
 	"sync/atomic"
+	"time"
+
+	"github.com/otiai10/primes"
 )
 
 const (
@@ -62,43 +65,88 @@ func probablyPrime(prime *big.Int) bool {
 
 // ----- //
 
-// The following code is a modified copy of: https://github.com/didiercrunch/paillier/blob/753322e473bf8ee20267c7824e68ae47360cc69b/safe_prime_generator.go
-// It is an implementation of the algorithm described in "Safe Prime Generation with a Combined Sieve" https://eprint.iacr.org/2003/186.pdf
+// The search for safe primes follows "Safe Prime Generation with a Combined Sieve" https://eprint.iacr.org/2003/186.pdf:
+// from a random start, it sieves a window of candidates q at once for those with no small factor in either q or
+// p = 2q+1, and only runs primality tests on the few that remain.
 
-// The code is the original Go implementation of rand.Prime optimized for
-// generating safe (Sophie Germain) primes.
-// A safe prime is a prime number of the form 2p + 1, where p is also a prime.
+const (
+	// sieveBound bounds the small primes that the search sieves the candidates with
+	sieveBound = 1 << 16
+	// sieveWindow is the number of candidates that the search sieves at once, from each random start
+	sieveWindow = 1 << 14
+)
 
-// Note from Author (https://github.com/pdyraga):
-// I've adapted a Go code for generating random numbers by inserting some
-// optimisations that will allow us to generate safe primes faster than
-// with the previous, naive approach.
-//
-// First of all, having q which can be prime, we first check whether q%3=1.
-// If that's true, there is no chance p=2q+1 is prime. It lets us to reject
-// candidate numbers quicker without running an expensive primality tests.
-//
-// Also, before we run a primality test for q, we may check p=2q+1 against
-// the primes between 3-53 (We are limited by Go's uint64 range).
-//
-// If all those conditions are met and we know p is prime, it's enough to
-// check Pocklington criterion for q instead of running an expensive
-// primality test for it.
-
-// smallPrimes is a list of small, prime numbers that allows us to rapidly
-// exclude some fraction of composite candidates when searching for a random
-// prime. This list is truncated at the point where smallPrimesProduct exceeds
-// a uint64. It does not include two because we ensure that the candidates are
-// odd by construction.
-var smallPrimes = []uint8{
-	3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37, 41, 43, 47, 53,
+var (
+	sieveOnce sync.Once
+	// sievePrimes are the odd primes from 5 up to sieveBound; the candidates are 5 mod 6, which rules out 2 and 3
+	sievePrimes []uint64
+	// sieveInverses are the inverses of 6 modulo sievePrimes, the steps between the candidates
+	sieveInverses []uint64
+)
+
+func initSieve() {
+	six := big.NewInt(6)
+	for _, prime := range primes.Until(sieveBound).List() {
+		if prime <= 3 {
+			continue
+		}
+		inverse := new(big.Int).ModInverse(six, big.NewInt(prime))
+		sievePrimes = append(sievePrimes, uint64(prime))
+		sieveInverses = append(sieveInverses, inverse.Uint64())
+	}
+}
+
+// SafePrimeProgressInterval is how often GetRandomSafePrimesConcurrent reports its progress while it searches
+const SafePrimeProgressInterval = time.Second
+
+type (
+	// SafePrimeProgress is the state of a search of GetRandomSafePrimesConcurrent
+	SafePrimeProgress struct {
+		// Purpose names the search among those of an operation, e.g. "paillier" or "ntilde" in the generation of
+		// pre-params. It is set by WithSafePrimePurpose.
+		Purpose string
+		// BitLen is the bit length of the safe primes searched for
+		BitLen int
+		// Found of Needed safe primes have been found
+		Found, Needed int
+		// Attempts is the number of candidates that passed the sieve and were tested so far, by all of the
+		// goroutines of the search
+		Attempts uint64
+		Elapsed  time.Duration
+	}
+
+	// SafePrimeProgressFunc receives the progress of a search of safe primes, every SafePrimeProgressInterval and
+	// whenever a safe prime is found. It is called on the goroutine of the search, so it must return quickly. To give
+	// up, e.g. after too many attempts, it cancels the context of the search.
+	SafePrimeProgressFunc func(SafePrimeProgress)
+
+	safePrimeProgressKey struct{}
+)
+
+// WithSafePrimeProgress returns a context that makes the searches of safe primes under it, e.g. those of
+// paillier.GenerateKeyPair and keygen.GeneratePreParamsWithContext, report their progress to `progress`
+func WithSafePrimeProgress(ctx context.Context, progress SafePrimeProgressFunc) context.Context {
+	return context.WithValue(ctx, safePrimeProgressKey{}, progress)
 }
 
-// smallPrimesProduct is the product of the values in smallPrimes and allows us
-// to reduce a candidate prime by this number and then determine whether it's
-// coprime to all the elements of smallPrimes without further big.Int
-// operations.
-var smallPrimesProduct = new(big.Int).SetUint64(16294579238595022365)
+// WithSafePrimePurpose returns a context that reports the progress of the searches under it with the Purpose
+// `purpose`, if `ctx` reports their progress at all
+func WithSafePrimePurpose(ctx context.Context, purpose string) context.Context {
+	progress := SafePrimeProgressFrom(ctx)
+	if progress == nil {
+		return ctx
+	}
+	return WithSafePrimeProgress(ctx, func(p SafePrimeProgress) {
+		p.Purpose = purpose
+		progress(p)
+	})
+}
+
+// SafePrimeProgressFrom returns the SafePrimeProgressFunc of `ctx`, or nil
+func SafePrimeProgressFrom(ctx context.Context) SafePrimeProgressFunc {
+	progress, _ := ctx.Value(safePrimeProgressKey{}).(SafePrimeProgressFunc)
+	return progress
+}
 
 // ErrGeneratorCancelled is an error returned from GetRandomSafePrimesConcurrent
 // when the work of the generator has been cancelled as a result of the context
@@ -127,6 +175,8 @@ var ErrGeneratorCancelled = fmt.Errorf("generator work cancelled")
 // `2` and for 2048-bit safe prime, `concurrencyLevel` must be set to at least
 // `4` to get the result in a reasonable time.
 //
+// The search reports its progress to the SafePrimeProgressFunc of `ctx`, if any, see WithSafePrimeProgress.
+//
 // This function generates safe primes of at least 6 `bitLen`. For every
 // generated safe prime, the two most significant bits are always set to `1`
 // - we don't want the generated number to be too small.
@@ -151,18 +201,42 @@ func GetRandomSafePrimesConcurrent(ctx context.Context, bitLen, numPrimes int, c
 	generatorCtx, cancelGeneratorCtx := context.WithCancel(ctx)
 	defer cancelGeneratorCtx()
 
+	var attempts uint64
 	for i := 0; i < concurrency; i++ {
 		waitGroup.Add(1)
 		runGenPrimeRoutine(
-			generatorCtx, primeCh, errCh, waitGroup, rand.Reader, bitLen,
+			generatorCtx, primeCh, errCh, waitGroup, randomSource(), bitLen, &attempts,
 		)
 	}
 
+	start := time.Now()
+	var tick <-chan time.Time
+	report := func() {}
+	if progress := SafePrimeProgressFrom(ctx); progress != nil {
+		ticker := time.NewTicker(SafePrimeProgressInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+		report = func() {
+			progress(SafePrimeProgress{
+				BitLen:   bitLen,
+				Found:    len(primes),
+				Needed:   numPrimes,
+				Attempts: atomic.LoadUint64(&attempts),
+				Elapsed:  time.Since(start),
+			})
+		}
+	}
+
 	needed := int32(numPrimes)
 	for {
 		select {
+		case <-tick:
+			report()
 		case result := <-primeCh:
 			primes = append(primes, result)
+			if len(primes) <= numPrimes {
+				report()
+			}
 			if atomic.AddInt32(&needed, -1) <= 0 {
 				return primes[:numPrimes], nil
 			}
@@ -180,35 +254,23 @@ func GetRandomSafePrimesConcurrent(ctx context.Context, bitLen, numPrimes int, c
 // a bit length equal to `pBitLen-1`.
 //
 // The algorithm is as follows:
-//  1. Generate a random odd number `q` of length `pBitLen-1` with two the most
-//     significant bits set to `1`.
-//  2. Execute preliminary primality test on `q` checking whether it is coprime
-//     to all the elements of `smallPrimes`. It allows to eliminate trivial
-//     cases quickly, when `q` is obviously no prime, without running an
-//     expensive final primality tests.
-//     If `q` is coprime to all of the `smallPrimes`, then go to the point 3.
-//     If not, add `2` and try again. Do it at most 10 times.
-//  3. Check the potentially prime `q`, whether `q = 1 (mod 3)`. This will
-//     happen for 50% of cases.
-//     If it is, then `p = 2q+1` will be a multiple of 3, so it will be obviously
-//     not a prime number. In this case, add `2` and try again. Do it at most 10
-//     times. If `q != 1 (mod 3)`, go to the point 4.
-//  4. Now we know `q` is potentially prime and `p = 2q+1` is not a multiple of
-//  3. We execute a preliminary primality test on `p`, checking whether
-//     it is coprime to all the elements of `smallPrimes` just like we did for
-//     `q` in point 2. If `p` is not coprime to at least one element of the
-//     `smallPrimes`, then go back to point 1.
-//     If `p` is coprime to all the elements of `smallPrimes`, go to point 5.
-//  5. At this point, we know `q` is potentially prime, and `p=q+1` is also
-//     potentially prime. We need to execute a final primality test for `q`.
-//     We apply Miller-Rabin and Baillie-PSW tests. If they succeed, it means
-//     that `q` is prime with a very high probability. Knowing `q` is prime,
-//     we use Pocklington's criterion to prove the primality of `p=2q+1`, that
-//     is, we execute Fermat primality test to base 2 checking whether
-//     `2^{p-1} = 1 (mod p)`. It's significantly faster than running full
-//     Miller-Rabin and Baillie-PSW for `p`.
-//     If `q` and `p` are found to be prime, return them as a result. If not, go
-//     back to the point 1.
+//  1. Generate a random number `q0` of length `pBitLen-1` with the two most
+//     significant bits set to `1`, and round it up to `5 (mod 6)`: any other
+//     odd `q` makes `p = 2q+1` a multiple of 3, or is one itself.
+//  2. Sieve the window of the `sieveWindow` candidates `q = q0 + 6k`: for each
+//     small prime `r` of `sievePrimes`, strike out the `k` of `q = 0 (mod r)`
+//     and of `q = (r-1)/2 (mod r)`, which is `p = 0 (mod r)`. Both are
+//     arithmetic progressions of step `r` in `k`, so the window costs a
+//     reduction of `q0` per small prime and no big.Int operation per
+//     candidate.
+//  3. For each candidate left, in order, run the Fermat test to base 2 on `p`
+//     first, which rejects almost all of them at the cost of a single
+//     exponentiation, and then Miller-Rabin and Baillie-PSW on `q`. With `q`
+//     prime, the Fermat test of `p` is Pocklington's criterion, which proves
+//     that `p` is prime.
+//  4. When the window is exhausted, go back to point 1.
+//
+// Each goroutine counts the candidates that pass the sieve in `attempts`.
 func runGenPrimeRoutine(
 	ctx context.Context,
 	primeCh chan<- *GermainSafePrime,
@@ -216,18 +278,30 @@ func runGenPrimeRoutine(
 	waitGroup *sync.WaitGroup,
 	rand io.Reader,
 	pBitLen int,
+	attempts *uint64,
 ) {
+	sieveOnce.Do(initSieve)
+
 	qBitLen := pBitLen - 1
 	b := uint(qBitLen % 8)
 	if b == 0 {
 		b = 8
 	}
+	// the candidates are at least 2^(qBitLen-1), so a small prime that divides one of them, or its p, is a proper
+	// factor as long as it is shorter
+	smallPrimes := len(sievePrimes)
+	for smallPrimes > 0 && qBitLen-1 <= bits.Len64(sievePrimes[smallPrimes-1]) {
+		smallPrimes--
+	}
 
 	bytes := make([]byte, (qBitLen+7)/8)
+	composite := make([]bool, sieveWindow)
+	q0 := new(big.Int)
 	p := new(big.Int)
 	q := new(big.Int)
-
 	bigMod := new(big.Int)
+	bigPrime := new(big.Int)
+	six := big.NewInt(6)
 
 	go func() {
 		defer waitGroup.Done()
@@ -237,93 +311,76 @@ func runGenPrimeRoutine(
 			case <-ctx.Done():
 				return
 			default:
-				_, err := io.ReadFull(rand, bytes)
-				if err != nil {
-					errCh <- err
-					return
-				}
+			}
+			_, err := io.ReadFull(rand, bytes)
+			if err != nil {
+				errCh <- err
+				return
+			}
 
-				// Clear bits in the first byte to make sure the candidate has
-				// a size <= bits.
-				bytes[0] &= uint8(int(1<<b) - 1)
-				// Don't let the value be too small, i.e, set the most
-				// significant two bits.
-				// Setting the top two bits, rather than just the top bit,
-				// means that when two of these values are multiplied together,
-				// the result isn't ever one bit short.
-				if b >= 2 {
-					bytes[0] |= 3 << (b - 2)
-				} else {
-					// Here b==1, because b cannot be zero.
-					bytes[0] |= 1
-					if len(bytes) > 1 {
-						bytes[1] |= 0x80
-					}
+			// Clear bits in the first byte to make sure the candidate has
+			// a size <= bits.
+			bytes[0] &= uint8(int(1<<b) - 1)
+			// Don't let the value be too small, i.e, set the most
+			// significant two bits.
+			// Setting the top two bits, rather than just the top bit,
+			// means that when two of these values are multiplied together,
+			// the result isn't ever one bit short.
+			if b >= 2 {
+				bytes[0] |= 3 << (b - 2)
+			} else {
+				// Here b==1, because b cannot be zero.
+				bytes[0] |= 1
+				if len(bytes) > 1 {
+					bytes[1] |= 0x80
 				}
-				// Make the value odd since an even number this large certainly
-				// isn't prime.
-				bytes[len(bytes)-1] |= 1
-
-				q.SetBytes(bytes)
-
-				// Calculate the value mod the product of smallPrimes. If it's
-				// a multiple of any of these primes we add two until it isn't.
-				// The probability of overflowing is minimal and can be ignored
-				// because we still perform Miller-Rabin tests on the result.
-				bigMod.Mod(q, smallPrimesProduct)
-				mod := bigMod.Uint64()
-
-			NextDelta:
-				for delta := uint64(0); delta < 1<<20; delta += 2 {
-					m := mod + delta
-					for _, prime := range smallPrimes {
-						if m%uint64(prime) == 0 && (qBitLen > 6 || m != uint64(prime)) {
-							continue NextDelta
-						}
-					}
-
-					if delta > 0 {
-						bigMod.SetUint64(delta)
-						q.Add(q, bigMod)
-					}
-
-					// If `q = 1 (mod 3)`, then `p` is a multiple of `3` so it's
-					// obviously no prime and such `q` should be rejected.
-					// This will happen in 50% of cases and we should detect
-					// and eliminate them early.
-					//
-					// Explanation:
-					// If q = 1 (mod 3) then there exists a q' such that:
-					// q = 3q' + 1
-					//
-					// Since p = 2q + 1:
-					// p = 2q + 1 = 2(3q' + 1) + 1 = 6q' + 2 + 1 = 6q' + 3 =
-					//   = 3(2q' + 1)
-					// So `p` is a multiple of `3`.
-					qMod3 := new(big.Int).Mod(q, big.NewInt(3))
-					if qMod3.Cmp(big.NewInt(1)) == 0 {
-						continue NextDelta
-					}
+			}
+			q0.SetBytes(bytes)
+			bigMod.Mod(q0, six)
+			q0.Add(q0, bigMod.SetUint64((11-bigMod.Uint64())%6))
 
-					// p = 2q+1
-					p.Mul(q, big.NewInt(2))
-					p.Add(p, big.NewInt(1))
-					if !isPrimeCandidate(p) {
-						continue NextDelta
-					}
+			for k := range composite {
+				composite[k] = false
+			}
+			for i, prime := range sievePrimes[:smallPrimes] {
+				rem := bigMod.Mod(q0, bigPrime.SetUint64(prime)).Uint64()
+				inverse := sieveInverses[i]
+				// q0 + 6k = 0 (mod prime)
+				for k := (prime - rem) % prime * inverse % prime; k < sieveWindow; k += prime {
+					composite[k] = true
+				}
+				// q0 + 6k = (prime-1)/2 (mod prime)
+				for k := ((prime-1)/2 + prime - rem) % prime * inverse % prime; k < sieveWindow; k += prime {
+					composite[k] = true
+				}
+			}
 
+			for k, isComposite := range composite {
+				if isComposite {
+					continue
+				}
+				select {
+				case <-ctx.Done():
+					return
+				default:
+				}
+				q.Add(q0, bigMod.SetUint64(6*uint64(k)))
+				// the window ran past the bit length, and so do the candidates that follow
+				if q.BitLen() != qBitLen {
 					break
 				}
-
-				// There is a tiny possibility that, by adding delta, we caused
-				// the number to be one bit too long. Thus we check BitLen
-				// here.
-				if q.ProbablyPrime(20) &&
-					isPocklingtonCriterionSatisfied(p) &&
-					q.BitLen() == qBitLen {
-
-					if sgp := (&GermainSafePrime{p: p, q: q}); sgp.Validate() {
-						primeCh <- &GermainSafePrime{p: p, q: q}
+				atomic.AddUint64(attempts, 1)
+				// p = 2q+1
+				p.Lsh(q, 1)
+				p.Add(p, one)
+				if !isPocklingtonCriterionSatisfied(p) || !q.ProbablyPrime(20) {
+					continue
+				}
+				if sgp := (&GermainSafePrime{p: p, q: q}); sgp.Validate() {
+					select {
+					case primeCh <- sgp:
+					case <-ctx.Done():
+						return
 					}
 					p, q = new(big.Int), new(big.Int)
 				}
@@ -343,13 +400,3 @@ func isPocklingtonCriterionSatisfied(p *big.Int) bool {
 		p,
 	).Cmp(big.NewInt(1)) == 0
 }
-
-func isPrimeCandidate(number *big.Int) bool {
-	m := new(big.Int).Mod(number, smallPrimesProduct).Uint64()
-	for _, prime := range smallPrimes {
-		if m%uint64(prime) == 0 && m != uint64(prime) {
-			return false
-		}
-	}
-	return true
-}
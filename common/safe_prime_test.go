@@ -53,3 +53,49 @@ func TestGetRandomGermainPrimeConcurrent(t *testing.T) {
 		assert.True(t, sgp.Validate())
 	}
 }
+
+func TestGetRandomSafePrimesBitLen(t *testing.T) {
+	// down to the sizes where the primes of the sieve are as long as the candidates
+	for _, bitLen := range []int{6, 7, 12, 18, 19, 64, 256} {
+		sgps, err := GetRandomSafePrimesConcurrent(context.Background(), bitLen, 2, 1)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		for _, sgp := range sgps {
+			assert.True(t, sgp.Validate())
+			assert.Equal(t, bitLen, sgp.SafePrime().BitLen())
+			assert.Equal(t, bitLen-1, sgp.Prime().BitLen())
+		}
+	}
+}
+
+func TestSafePrimeProgress(t *testing.T) {
+	var reports []SafePrimeProgress
+	ctx := WithSafePrimeProgress(context.Background(), func(p SafePrimeProgress) {
+		reports = append(reports, p)
+	})
+	_, err := GetRandomSafePrimesConcurrent(WithSafePrimePurpose(ctx, "test"), 128, 3, 2)
+	assert.NoError(t, err)
+	if assert.Len(t, reports, 3, "a report for every safe prime found") {
+		for i, p := range reports {
+			assert.Equal(t, SafePrimeProgress{Purpose: "test", BitLen: 128, Found: i + 1, Needed: 3, Attempts: p.Attempts, Elapsed: p.Elapsed}, p)
+			assert.NotZero(t, p.Attempts)
+		}
+	}
+
+	// the callback gives up on the heartbeat that follows a second of search
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var heartbeats int
+	ctx = WithSafePrimeProgress(ctx, func(p SafePrimeProgress) {
+		heartbeats++
+		if p.Found == 0 && time.Second <= p.Elapsed {
+			cancel()
+		}
+	})
+	start := time.Now()
+	_, err = GetRandomSafePrimesConcurrent(ctx, 4096, 1, 1)
+	assert.ErrorIs(t, err, ErrGeneratorCancelled)
+	assert.NotZero(t, heartbeats)
+	assert.WithinDuration(t, start.Add(SafePrimeProgressInterval), time.Now(), 2*time.Second)
+}
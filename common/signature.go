@@ -0,0 +1,74 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+const (
+	// EthereumSignatureBytesLen is the length of the [R || S || V] encoding used by Ethereum
+	EthereumSignatureBytesLen = 65
+
+	ethereumScalarBytesLen = 32
+)
+
+// RecoveryID returns the recovery id computed during finalization, which selects the public key among
+// the candidates that can be recovered from the signature
+func (m *SignatureData) RecoveryID() (byte, error) {
+	if len(m.GetSignatureRecovery()) == 0 {
+		return 0, errors.New("the signature has no recovery id")
+	}
+	return m.GetSignatureRecovery()[0], nil
+}
+
+// EthereumSignature returns the 65-byte [R || S || V] encoding of a secp256k1 signature, where V is the
+// recovery id (0 or 1) as expected by go-ethereum's crypto.Ecrecover. Legacy transactions expect V + 27.
+func (m *SignatureData) EthereumSignature() ([]byte, error) {
+	v, err := m.RecoveryID()
+	if err != nil {
+		return nil, err
+	}
+	// a recovery id of 2 or 3 means R.x overflowed the group order, which Ethereum does not support
+	if v > 1 {
+		return nil, fmt.Errorf("recovery id %d cannot be encoded for Ethereum", v)
+	}
+	r, s := m.GetR(), m.GetS()
+	if len(r) == 0 || len(r) > ethereumScalarBytesLen || len(s) == 0 || len(s) > ethereumScalarBytesLen {
+		return nil, errors.New("the signature R and S must be at most 32 bytes long")
+	}
+	out := make([]byte, EthereumSignatureBytesLen)
+	copy(out[ethereumScalarBytesLen-len(r):ethereumScalarBytesLen], r)
+	copy(out[2*ethereumScalarBytesLen-len(s):2*ethereumScalarBytesLen], s)
+	out[2*ethereumScalarBytesLen] = v
+	return out, nil
+}
+
+// ECDSADigest returns the digest that crypto/ecdsa reads as the message m < N of a signature. ecdsa keeps the leftmost
+// N.BitLen() bits of a digest, so m is shifted left when the bit length of N is not a multiple of 8, as on the STARK
+// curve; otherwise the digest is m itself.
+func ECDSADigest(N, m *big.Int) []byte {
+	size := (N.BitLen() + 7) / 8
+	digest := make([]byte, size)
+	new(big.Int).Lsh(m, uint(8*size-N.BitLen())).FillBytes(digest)
+	return digest
+}
+
+// NormalizeLowS returns the low-S form of a signature: when s is greater than N/2 it is replaced by N - s,
+// which is the s of the same signature over -R, so the parity bit of the recovery id is flipped as well.
+// This is needed because of the canonical signature checks of BIP-62 (Bitcoin), EIP-2 (Ethereum) and tendermint:
+// https://github.com/btcsuite/btcd/blob/c26ffa870fd817666a857af1bf6498fabba1ffe3/btcec/signature.go#L442-L444
+// https://github.com/tendermint/tendermint/blob/d9481e3648450cb99e15c6a070c1fb69aa0c255b/crypto/secp256k1/secp256k1_nocgo.go#L43-L47
+func NormalizeLowS(N, s *big.Int, recid int) (*big.Int, int) {
+	halfN := new(big.Int).Rsh(N, 1)
+	if s.Cmp(halfN) > 0 {
+		return new(big.Int).Sub(N, s), recid ^ 1
+	}
+	return s, recid
+}
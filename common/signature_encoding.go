@@ -0,0 +1,122 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package common
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+const (
+	// RawSignatureBytesLen is the length of the fixed-size [R || S] encoding of a 256-bit curve signature
+	RawSignatureBytesLen = 2 * ethereumScalarBytesLen
+
+	// PEMSignatureType is the PEM block type used for DER encoded signatures
+	PEMSignatureType = "ECDSA SIGNATURE"
+)
+
+// ecdsaSignature is the ASN.1 structure of an ECDSA signature, as in RFC 3279 and SEC 1
+type ecdsaSignature struct {
+	R, S *big.Int
+}
+
+// DERSignature returns the ASN.1 DER encoding of the signature, SEQUENCE { r INTEGER, s INTEGER },
+// as produced by OpenSSL and the Go standard library and used in Bitcoin scripts (without the sighash byte)
+func (m *SignatureData) DERSignature() ([]byte, error) {
+	r, s, err := m.scalars()
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(ecdsaSignature{r, s})
+}
+
+// RawSignature returns the 64-byte [R || S] encoding of a signature over a 256-bit curve
+func (m *SignatureData) RawSignature() ([]byte, error) {
+	r, s := m.GetR(), m.GetS()
+	if len(r) == 0 || len(r) > ethereumScalarBytesLen || len(s) == 0 || len(s) > ethereumScalarBytesLen {
+		return nil, errors.New("the signature R and S must be at most 32 bytes long")
+	}
+	out := make([]byte, RawSignatureBytesLen)
+	copy(out[ethereumScalarBytesLen-len(r):ethereumScalarBytesLen], r)
+	copy(out[RawSignatureBytesLen-len(s):], s)
+	return out, nil
+}
+
+// PEMSignature returns the DER encoding of the signature wrapped in a PEM block of type PEMSignatureType
+func (m *SignatureData) PEMSignature() ([]byte, error) {
+	der, err := m.DERSignature()
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: PEMSignatureType, Bytes: der}), nil
+}
+
+func (m *SignatureData) scalars() (*big.Int, *big.Int, error) {
+	if len(m.GetR()) == 0 || len(m.GetS()) == 0 {
+		return nil, nil, errors.New("the signature has no R or S")
+	}
+	return new(big.Int).SetBytes(m.GetR()), new(big.Int).SetBytes(m.GetS()), nil
+}
+
+// ParseDERSignature parses an ASN.1 DER encoded signature. The recovery id and message are not part of the
+// encoding and are left empty.
+func ParseDERSignature(der []byte) (*SignatureData, error) {
+	sig := new(ecdsaSignature)
+	rest, err := asn1.Unmarshal(der, sig)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the DER signature: %w", err)
+	}
+	if len(rest) != 0 {
+		return nil, errors.New("the DER signature has trailing data")
+	}
+	if sig.R.Sign() <= 0 || sig.S.Sign() <= 0 {
+		return nil, errors.New("the DER signature R and S must be positive")
+	}
+	// re-encoding rejects the non-minimal BER forms that asn1.Unmarshal would otherwise accept
+	if canonical, err := asn1.Marshal(*sig); err != nil || !bytes.Equal(canonical, der) {
+		return nil, errors.New("the signature is not in canonical DER form")
+	}
+	return newSignatureData(sig.R.Bytes(), sig.S.Bytes()), nil
+}
+
+// ParseRawSignature parses a 64-byte [R || S] signature
+func ParseRawSignature(raw []byte) (*SignatureData, error) {
+	if len(raw) != RawSignatureBytesLen {
+		return nil, fmt.Errorf("the raw signature must be %d bytes long, got %d", RawSignatureBytesLen, len(raw))
+	}
+	r, s := raw[:ethereumScalarBytesLen], raw[ethereumScalarBytesLen:]
+	if new(big.Int).SetBytes(r).Sign() == 0 || new(big.Int).SetBytes(s).Sign() == 0 {
+		return nil, errors.New("the raw signature R and S must not be zero")
+	}
+	return newSignatureData(r, s), nil
+}
+
+// ParsePEMSignature parses a PEM block of type PEMSignatureType holding a DER encoded signature
+func ParsePEMSignature(bz []byte) (*SignatureData, error) {
+	block, _ := pem.Decode(bz)
+	if block == nil {
+		return nil, errors.New("could not find a PEM block")
+	}
+	if block.Type != PEMSignatureType {
+		return nil, fmt.Errorf("unexpected PEM block type %q", block.Type)
+	}
+	return ParseDERSignature(block.Bytes)
+}
+
+// newSignatureData fills in R, S and the [R || S] signature as the finalization rounds do
+func newSignatureData(r, s []byte) *SignatureData {
+	r, s = append([]byte{}, r...), append([]byte{}, s...)
+	return &SignatureData{
+		R:         r,
+		S:         s,
+		Signature: append(append([]byte{}, r...), s...),
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package common_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/common"
+)
+
+func TestSignatureEncodings(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	hash := sha256.Sum256([]byte("signature encodings"))
+
+	der, err := ecdsa.SignASN1(rand.Reader, priv, hash[:])
+	assert.NoError(t, err)
+	data, err := common.ParseDERSignature(der)
+	if !assert.NoError(t, err) {
+		return
+	}
+	r, s := new(big.Int).SetBytes(data.R), new(big.Int).SetBytes(data.S)
+	assert.True(t, ecdsa.Verify(&priv.PublicKey, hash[:], r, s))
+
+	// DER
+	reencoded, err := data.DERSignature()
+	assert.NoError(t, err)
+	assert.Equal(t, der, reencoded)
+
+	// raw
+	raw, err := data.RawSignature()
+	assert.NoError(t, err)
+	assert.Len(t, raw, common.RawSignatureBytesLen)
+	fromRaw, err := common.ParseRawSignature(raw)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, new(big.Int).SetBytes(fromRaw.R).Cmp(r))
+	assert.Equal(t, 0, new(big.Int).SetBytes(fromRaw.S).Cmp(s))
+	assert.Equal(t, raw, fromRaw.Signature)
+
+	// PEM
+	pemBz, err := data.PEMSignature()
+	assert.NoError(t, err)
+	fromPEM, err := common.ParsePEMSignature(pemBz)
+	assert.NoError(t, err)
+	assert.Equal(t, data.R, fromPEM.R)
+	assert.Equal(t, data.S, fromPEM.S)
+}
+
+func TestParseSignatureInvalid(t *testing.T) {
+	_, err := common.ParseDERSignature([]byte{0x30, 0x00})
+	assert.Error(t, err, "an empty sequence must be rejected")
+
+	// r = 1 with a redundant leading zero byte is valid BER but not DER
+	_, err = common.ParseDERSignature([]byte{0x30, 0x07, 0x02, 0x02, 0x00, 0x01, 0x02, 0x01, 0x01})
+	assert.Error(t, err, "a non-minimal integer must be rejected")
+
+	_, err = common.ParseDERSignature([]byte{0x30, 0x06, 0x02, 0x01, 0x01, 0x02, 0x01, 0x01, 0x00})
+	assert.Error(t, err, "trailing data must be rejected")
+
+	_, err = common.ParseRawSignature(make([]byte, 63))
+	assert.Error(t, err, "a raw signature of the wrong length must be rejected")
+
+	_, err = common.ParseRawSignature(make([]byte, common.RawSignatureBytesLen))
+	assert.Error(t, err, "a zero raw signature must be rejected")
+
+	_, err = common.ParsePEMSignature([]byte("-----BEGIN PUBLIC KEY-----\nMAA=\n-----END PUBLIC KEY-----\n"))
+	assert.Error(t, err, "a PEM block of another type must be rejected")
+
+	_, err = (&common.SignatureData{}).DERSignature()
+	assert.Error(t, err, "an empty signature cannot be encoded")
+}
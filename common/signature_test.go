@@ -0,0 +1,71 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package common_test
+
+import (
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	btcecdsa "github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/common"
+)
+
+func TestEthereumSignature(t *testing.T) {
+	priv, err := btcec.NewPrivateKey()
+	assert.NoError(t, err)
+	hash := sha256.Sum256([]byte("ethereum signature"))
+
+	// the compact format is [27 + recid || R || S]
+	compact := btcecdsa.SignCompact(priv, hash[:], false)
+	data := &common.SignatureData{
+		R:                 compact[1:33],
+		S:                 compact[33:],
+		SignatureRecovery: []byte{compact[0] - 27},
+	}
+	eth, err := data.EthereumSignature()
+	assert.NoError(t, err)
+	assert.Len(t, eth, common.EthereumSignatureBytesLen)
+	assert.Equal(t, append(append([]byte{}, compact[1:]...), compact[0]-27), eth)
+
+	// the encoding must recover the signing key
+	pub, _, err := btcecdsa.RecoverCompact(append([]byte{eth[64] + 27}, eth[:64]...), hash[:])
+	assert.NoError(t, err)
+	assert.True(t, pub.IsEqual(priv.PubKey()))
+}
+
+func TestEthereumSignatureInvalid(t *testing.T) {
+	_, err := (&common.SignatureData{R: []byte{1}, S: []byte{1}}).EthereumSignature()
+	assert.Error(t, err, "a signature without a recovery id must be rejected")
+
+	_, err = (&common.SignatureData{R: []byte{1}, S: []byte{1}, SignatureRecovery: []byte{2}}).EthereumSignature()
+	assert.Error(t, err, "a recovery id above 1 must be rejected")
+}
+
+func TestNormalizeLowS(t *testing.T) {
+	N := btcec.S256().N
+	priv, err := btcec.NewPrivateKey()
+	assert.NoError(t, err)
+	hash := sha256.Sum256([]byte("low-s"))
+
+	// btcec always signs with a low S, so negating it gives the high-S form of the same signature
+	compact := btcecdsa.SignCompact(priv, hash[:], false)
+	lowS, recid := new(big.Int).SetBytes(compact[33:]), int(compact[0]-27)
+	highS := new(big.Int).Sub(N, lowS)
+
+	s, v := common.NormalizeLowS(N, highS, recid^1)
+	assert.Equal(t, 0, s.Cmp(lowS))
+	assert.Equal(t, recid, v)
+
+	// a low S is left alone
+	s, v = common.NormalizeLowS(N, lowS, recid)
+	assert.Equal(t, 0, s.Cmp(lowS))
+	assert.Equal(t, recid, v)
+}
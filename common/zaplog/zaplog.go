@@ -0,0 +1,19 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package zaplog adapts zap loggers to common.Logger, so that the common package does not depend on zap.
+package zaplog
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/kisdex/mpc-lib/common"
+)
+
+// New adapts a *zap.Logger to common.Logger
+func New(l *zap.Logger) common.Logger {
+	return l.Sugar()
+}
@@ -0,0 +1,30 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package common
+
+import (
+	"math/big"
+)
+
+// Zeroize overwrites the words backing each of `ints`, up to their capacity, and sets them to 0, so that a secret does
+// not linger in memory until the garbage collector reuses it. nil ints are skipped. A copy of a secret made by an
+// arithmetic operation has words of its own, which this does not reach.
+func Zeroize(ints ...*big.Int) {
+	for _, x := range ints {
+		if x == nil {
+			continue
+		}
+		words := x.Bits()
+		clear(words[:cap(words)])
+		x.SetInt64(0)
+	}
+}
+
+// ZeroizeBytes overwrites `bz` with zeros
+func ZeroizeBytes(bz []byte) {
+	clear(bz)
+}
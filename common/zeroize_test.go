@@ -0,0 +1,32 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package common_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/common"
+)
+
+func TestZeroize(t *testing.T) {
+	x := common.MustGetRandomInt(2048)
+	words := x.Bits()
+	y := new(big.Int).Neg(common.MustGetRandomInt(256))
+	common.Zeroize(x, nil, y)
+	assert.Zero(t, x.Sign())
+	assert.Zero(t, y.Sign())
+	for _, w := range words[:cap(words)] {
+		assert.Zero(t, w)
+	}
+
+	bz := []byte{1, 2, 3}
+	common.ZeroizeBytes(bz)
+	assert.Equal(t, []byte{0, 0, 0}, bz)
+}
@@ -29,6 +29,7 @@ import (
 )
 
 func AliceInit(
+	Session []byte,
 	ec elliptic.Curve,
 	// Alice public key
 	pkA *paillier.PublicKey,
@@ -68,7 +69,7 @@ func AliceInit(
 				proofs[i] = nil
 				return
 			}
-			proof, err := zkproofs.NewEncProof(witness, statement, rp)
+			proof, err := zkproofs.NewEncProof(Session, witness, statement, rp)
 			if err != nil {
 				errChs <- err
 			}
@@ -85,11 +86,12 @@ func AliceInit(
 }
 
 func BobRespondsP(
+	Session []byte,
 	ec elliptic.Curve,
 	// Alice's public key
 	pkA *paillier.PublicKey,
 	// Bob's public key
-	skB *paillier.PrivateKey,
+	skB paillier.Decrypter,
 	// Alice's proof
 	proofAlice *zkproofs.EncProof,
 	// Bob's encryption of his secret
@@ -101,7 +103,7 @@ func BobRespondsP(
 	// Bob's Ring Pedersen parameters
 	rpB *zkproofs.RingPedersenParams,
 ) (beta, cAlpha, cBeta, cBetaPrm *big.Int, proofs []*zkproofs.AffPProof, decProofs []*zkproofs.DecProof, err error) {
-	if !BobVerify(ec, pkA, proofAlice, cA, rpB) {
+	if !BobVerify(Session, ec, pkA, proofAlice, cA, rpB) {
 		err = errors.New("RangeProofAlice.Verify() returned false")
 		return
 	}
@@ -115,7 +117,7 @@ func BobRespondsP(
 	if err != nil {
 		return
 	}
-	cBetaPrm, rhoy, err := skB.PublicKey.EncryptAndReturnRandomness(betaPrm)
+	cBetaPrm, rhoy, err := skB.Public().EncryptAndReturnRandomness(betaPrm)
 	if err != nil {
 		return
 	}
@@ -140,18 +142,18 @@ func BobRespondsP(
 		X:        cB,                  // encryption of b using Bob's public key
 		Y:        cBetaPrm,            // encryption of betaPrm
 		N0:       pkA.N,               // Alice's public key
-		N1:       skB.PublicKey.N,     // Bob's public key
+		N1:       skB.Public().N,     // Bob's public key
 		Ell:      zkproofs.GetEll(ec), // max size of plaintext
 		EllPrime: zkproofs.GetEll(ec), // max size of plaintext
 		EC:       ec,                  // elliptic curve
 	}
 
 	beta = common.ModInt(q).Sub(big.NewInt(0), betaPrm)
-	cBeta, err = skB.PublicKey.Encrypt(beta)
+	cBeta, err = skB.Public().Encrypt(beta)
 	if err != nil {
 		return
 	}
-	decProofs, err = DecProofs(skB, ec, cBeta, cBetaPrm, rpV)
+	decProofs, err = DecProofs(Session, skB, ec, cBeta, cBetaPrm, rpV)
 	if err != nil {
 		return
 	}
@@ -167,7 +169,7 @@ func BobRespondsP(
 				proofs[i] = nil
 				return
 			}
-			proof, err := zkproofs.NewAffPProof(witness, statement, rp)
+			proof, err := zkproofs.NewAffPProof(Session, witness, statement, rp)
 			if err != nil {
 				errChs <- err
 			}
@@ -183,6 +185,7 @@ func BobRespondsP(
 }
 
 func BobVerify(
+	Session []byte,
 	ec elliptic.Curve,
 	// Alice's public key
 	pkA *paillier.PublicKey,
@@ -193,21 +196,25 @@ func BobVerify(
 	// Verifier's Ring Pedersen parameters
 	rpV *zkproofs.RingPedersenParams,
 ) bool {
+	if rpV == nil {
+		return true
+	}
 	// check Alice's proof
 	statementA := &zkproofs.EncStatement{
 		K:  cA,    // Alice's ciphertext
 		N0: pkA.N, // Alice's public key
 		EC: ec,    // max size of plaintext
 	}
-	return proofAlice.Verify(statementA, rpV)
+	return proofAlice.Verify(Session, statementA, rpV)
 }
 
 func BobRespondsDL(
+	Session []byte,
 	ec elliptic.Curve,
 	// Alice's public key
 	pkA *paillier.PublicKey,
 	// Bob's public key
-	skB *paillier.PrivateKey,
+	skB paillier.Decrypter,
 	// Alice's proof
 	proofAlice *zkproofs.EncProof,
 	// Bob's secret
@@ -221,7 +228,7 @@ func BobRespondsDL(
 	// DL commitment to Bob's input b
 	B *crypto.ECPoint,
 ) (beta, cAlpha, cBeta, cBetaPrm *big.Int, proofs []*zkproofs.AffGProof, decProofs []*zkproofs.DecProof, err error) {
-	if !BobVerify(ec, pkA, proofAlice, cA, rpB) {
+	if !BobVerify(Session, ec, pkA, proofAlice, cA, rpB) {
 		err = errors.New("RangeProofAlice.Verify() returned false")
 		return
 	}
@@ -232,7 +239,7 @@ func BobRespondsDL(
 	q := ec.Params().N
 	betaPrm := common.GetRandomPositiveInt(q)
 	beta = common.ModInt(q).Sub(big.NewInt(0), betaPrm)
-	cBetaPrm, rhoy, err := skB.PublicKey.EncryptAndReturnRandomness(betaPrm)
+	cBetaPrm, rhoy, err := skB.Public().EncryptAndReturnRandomness(betaPrm)
 	if err != nil {
 		return
 	}
@@ -256,17 +263,17 @@ func BobRespondsDL(
 		X:        B,                   // B = g^b is a DL commitment to Bob's input b
 		Y:        cBetaPrm,            // encryption of betaPrm
 		N0:       pkA.N,               // Alice's public key
-		N1:       skB.PublicKey.N,     // Bob's public key
+		N1:       skB.Public().N,     // Bob's public key
 		Ell:      zkproofs.GetEll(ec), // max size of plaintext
 		EllPrime: zkproofs.GetEll(ec), // max size of plaintext
 	}
 
 	beta = common.ModInt(q).Sub(big.NewInt(0), betaPrm)
-	cBeta, err = skB.PublicKey.Encrypt(beta)
+	cBeta, err = skB.Public().Encrypt(beta)
 	if err != nil {
 		return
 	}
-	decProofs, err = DecProofs(skB, ec, cBeta, cBetaPrm, rpV)
+	decProofs, err = DecProofs(Session, skB, ec, cBeta, cBetaPrm, rpV)
 	if err != nil {
 		return
 	}
@@ -282,7 +289,7 @@ func BobRespondsDL(
 				proofs[i] = nil
 				return
 			}
-			proof, err := zkproofs.NewAffGProof(witness, statement, rp)
+			proof, err := zkproofs.NewAffGProof(Session, witness, statement, rp)
 			if err != nil {
 				errChs <- err
 			}
@@ -298,11 +305,12 @@ func BobRespondsDL(
 }
 
 func BobRespondsG(
+	Session []byte,
 	ec elliptic.Curve,
 	// Alice's public key
 	pkA *paillier.PublicKey,
 	// Bob's public key
-	skB *paillier.PrivateKey,
+	skB paillier.Decrypter,
 	// Alice's proof
 	proofAlice *zkproofs.EncProof,
 	// Bob's secret
@@ -314,51 +322,97 @@ func BobRespondsG(
 	// Bob's Ring Pedersen parameters
 	rpB *zkproofs.RingPedersenParams,
 ) (beta, cAlpha, cBeta *big.Int, proofs []*zkproofs.AffGInvProof, err error) {
-	if !BobVerify(ec, pkA, proofAlice, cA, rpB) {
+	betas, cAlphas, cBetas, proofsB, err := BobRespondsGBatch(Session, ec, pkA, skB, proofAlice, []*big.Int{b}, cA, rpV, rpB)
+	if err != nil {
+		return
+	}
+	return betas[0], cAlphas[0], cBetas[0], proofsB[0], nil
+}
+
+// BobRespondsGBatch is BobRespondsG for several secrets of Bob against the same ciphertext of Alice, e.g. gamma and w
+// in the signing of CGG21. Alice's proof is verified once and the proofs of all the secrets are computed in a single
+// pass over the verifiers. The k-th output is the response for bs[k].
+// A nil rpB skips the verification of Alice's proof, as in BobVerify.
+func BobRespondsGBatch(
+	Session []byte,
+	ec elliptic.Curve,
+	// Alice's public key
+	pkA *paillier.PublicKey,
+	// Bob's public key
+	skB paillier.Decrypter,
+	// Alice's proof
+	proofAlice *zkproofs.EncProof,
+	// Bob's secrets
+	bs []*big.Int,
+	// Alice's encryption of a under pkA
+	cA *big.Int,
+	// Verifier's Ring Pedersen parameters
+	rpV []*zkproofs.RingPedersenParams,
+	// Bob's Ring Pedersen parameters
+	rpB *zkproofs.RingPedersenParams,
+) (betas, cAlphas, cBetas []*big.Int, proofs [][]*zkproofs.AffGInvProof, err error) {
+	if len(bs) == 0 {
+		err = errors.New("no secret to respond with")
+		return
+	}
+	if !BobVerify(Session, ec, pkA, proofAlice, cA, rpB) {
 		err = errors.New("RangeProofBob.Verify() returned false")
 		return
 	}
 
-	// Compute response
+	// Compute responses
 	// TODO: what is correct size for betaPrm? AffPProof Fig 26 needs
 	// it to be in 2^ell, but does share conversion require a bigger betaPrm?
 	q := ec.Params().N
-	beta = common.GetRandomPositiveInt(q)
-
-	witness, statement, err := zkproofs.NewAffGInvWitness(ec, skB, pkA, b, beta, cA)
-	cAlpha = statement.D
-	cBeta = statement.Y
+	betas = make([]*big.Int, len(bs))
+	cAlphas = make([]*big.Int, len(bs))
+	cBetas = make([]*big.Int, len(bs))
+	witnesses := make([]*zkproofs.AffGInvWitness, len(bs))
+	statements := make([]*zkproofs.AffGInvStatement, len(bs))
+	for k, b := range bs {
+		betas[k] = common.GetRandomPositiveInt(q)
+		witnesses[k], statements[k], err = zkproofs.NewAffGInvWitness(ec, skB, pkA, b, betas[k], cA)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		cAlphas[k] = statements[k].D
+		cBetas[k] = statements[k].Y
+	}
 
 	wg := sync.WaitGroup{}
-	wg.Add(len(rpV))
-	proofs = make([]*zkproofs.AffGInvProof, len(rpV))
-	errChs := make(chan error, len(rpV))
-	for i, rp := range rpV {
-		go func(i int, rp *zkproofs.RingPedersenParams) {
-			defer wg.Done()
-			if rp == nil {
-				proofs[i] = nil
-				return
-			}
-			proof, err := zkproofs.NewAffGInvProof(witness, statement, rp)
-			if err != nil {
-				errChs <- err
-			}
-			proofs[i] = proof
-		}(i, rp)
+	wg.Add(len(bs) * len(rpV))
+	proofs = make([][]*zkproofs.AffGInvProof, len(bs))
+	errChs := make(chan error, len(bs)*len(rpV))
+	for k := range bs {
+		proofs[k] = make([]*zkproofs.AffGInvProof, len(rpV))
+		for i, rp := range rpV {
+			go func(k, i int, rp *zkproofs.RingPedersenParams) {
+				defer wg.Done()
+				if rp == nil {
+					proofs[k][i] = nil
+					return
+				}
+				proof, err := zkproofs.NewAffGInvProof(Session, witnesses[k], statements[k], rp)
+				if err != nil {
+					errChs <- err
+				}
+				proofs[k][i] = proof
+			}(k, i, rp)
+		}
 	}
 	wg.Wait()
 	close(errChs)
 	if len(errChs) > 0 {
-		err = <-errChs
+		return nil, nil, nil, nil, <-errChs
 	}
 	return
 }
 
 func AliceEndP(
+	Session []byte,
 	ec elliptic.Curve,
 	// Alice's Paillier keys
-	skA *paillier.PrivateKey,
+	skA paillier.Decrypter,
 	// Bob's Paillier keys
 	pkB *paillier.PublicKey,
 	// Bob's proof
@@ -369,10 +423,10 @@ func AliceEndP(
 	// Alice's Ring Pedersen parameters
 	rpA *zkproofs.RingPedersenParams,
 ) (*big.Int, error) {
-	if !AliceVerifyP(ec, &skA.PublicKey, pkB, proof, cA, cAlpha, cBetaPrm, cB, rpA) {
+	if !AliceVerifyP(Session, ec, skA.Public(), pkB, proof, cA, cAlpha, cBetaPrm, cB, rpA) {
 		return nil, errors.New("AffPProof.Verify() returned false")
 	}
-	if !DecProofVerify(pkB, ec, decproof, cBeta, cBetaPrm, rpA) {
+	if !DecProofVerify(Session, pkB, ec, decproof, cBeta, cBetaPrm, rpA) {
 		return nil, errors.New("DecProof.Verify() returned false")
 	}
 	alphaPrm, err := skA.Decrypt(cAlpha)
@@ -384,6 +438,7 @@ func AliceEndP(
 }
 
 func AliceVerifyP(
+	Session []byte,
 	ec elliptic.Curve,
 	// Alice's Paillier keys
 	pkA *paillier.PublicKey,
@@ -410,16 +465,17 @@ func AliceVerifyP(
 		EllPrime: zkproofs.GetEll(ec), // max size of plaintext
 		EC:       ec,                  // elliptic curve
 	}
-	if !proof.Verify(statement, rpV) {
+	if !proof.Verify(Session, statement, rpV) {
 		return false
 	}
 	return true
 }
 
 func AliceEndDL(
+	Session []byte,
 	ec elliptic.Curve,
 	// Alice's Paillier keys
-	skA *paillier.PrivateKey,
+	skA paillier.Decrypter,
 	// Bob's Paillier keys
 	pkB *paillier.PublicKey,
 	// Bob's proof
@@ -431,11 +487,11 @@ func AliceEndDL(
 	// Alice's Ring Pedersen parameters
 	rpA *zkproofs.RingPedersenParams,
 ) (*big.Int, error) {
-	if !AliceVerifyDL(ec, &skA.PublicKey, pkB, proof, cA, cAlpha, cBetaPrm, B, rpA) {
+	if !AliceVerifyDL(Session, ec, skA.Public(), pkB, proof, cA, cAlpha, cBetaPrm, B, rpA) {
 		return nil, errors.New("AffGProof.Verify() returned false")
 	}
 
-	if !DecProofVerify(pkB, ec, decproof, cBeta, cBetaPrm, rpA) {
+	if !DecProofVerify(Session, pkB, ec, decproof, cBeta, cBetaPrm, rpA) {
 		return nil, errors.New("DecProof.Verify() returned false")
 	}
 
@@ -448,6 +504,7 @@ func AliceEndDL(
 }
 
 func AliceVerifyDL(
+	Session []byte,
 	ec elliptic.Curve,
 	// Alice's Paillier keys
 	pkA *paillier.PublicKey,
@@ -475,13 +532,14 @@ func AliceVerifyDL(
 		EllPrime: zkproofs.GetEll(ec), // max size of plaintext
 	}
 
-	return proof.Verify(statement, rpV)
+	return proof.Verify(Session, statement, rpV)
 }
 
 func AliceEndG(
+	Session []byte,
 	ec elliptic.Curve,
 	// Alice's Paillier keys
-	skA *paillier.PrivateKey,
+	skA paillier.Decrypter,
 	// Bob's Paillier keys
 	pkB *paillier.PublicKey,
 	// Bob's proof
@@ -492,7 +550,7 @@ func AliceEndG(
 	// Alice's Ring Pedersen parameters
 	rpA *zkproofs.RingPedersenParams,
 ) (*big.Int, error) {
-	if !AliceVerifyG(ec, &skA.PublicKey, pkB, proof, cA, cAlpha, cBeta, B, rpA) {
+	if !AliceVerifyG(Session, ec, skA.Public(), pkB, proof, cA, cAlpha, cBeta, B, rpA) {
 		return nil, errors.New("AffGInvProof.Verify() returned false")
 	}
 
@@ -504,7 +562,54 @@ func AliceEndG(
 	return new(big.Int).Mod(alphaPrm, q), nil
 }
 
+// AliceEndGBatch is AliceEndG for the responses of BobRespondsGBatch. The ring-Pedersen equations of all the proofs
+// are checked at once, and the k-th output is Alice's share for the k-th response.
+// A nil rpA skips the verification of the proofs, as in AliceVerifyG.
+func AliceEndGBatch(
+	Session []byte,
+	ec elliptic.Curve,
+	// Alice's Paillier keys
+	skA paillier.Decrypter,
+	// Bob's Paillier keys
+	pkB *paillier.PublicKey,
+	// Bob's proofs
+	proofs []*zkproofs.AffGInvProof,
+	// Statements
+	cA *big.Int,
+	cAlphas, cBetas []*big.Int,
+	Bs []*crypto.ECPoint,
+	// Alice's Ring Pedersen parameters
+	rpA *zkproofs.RingPedersenParams,
+) ([]*big.Int, error) {
+	if len(cAlphas) != len(proofs) || len(cBetas) != len(proofs) || len(Bs) != len(proofs) {
+		return nil, errors.New("expected as many statements as proofs")
+	}
+	if rpA != nil {
+		batch := zkproofs.NewRingPedersenBatch(rpA)
+		for k, proof := range proofs {
+			if !AliceVerifyGBatch(Session, ec, skA.Public(), pkB, proof, cA, cAlphas[k], cBetas[k], Bs[k], batch, 0) {
+				return nil, errors.New("AffGInvProof.Verify() returned false")
+			}
+		}
+		if batch.Verify() != nil {
+			return nil, errors.New("AffGInvProof.Verify() returned false")
+		}
+	}
+
+	q := ec.Params().N
+	alphas := make([]*big.Int, len(proofs))
+	for k, cAlpha := range cAlphas {
+		alphaPrm, err := skA.Decrypt(cAlpha)
+		if err != nil {
+			return nil, err
+		}
+		alphas[k] = new(big.Int).Mod(alphaPrm, q)
+	}
+	return alphas, nil
+}
+
 func AliceVerifyG(
+	Session []byte,
 	ec elliptic.Curve,
 	// Alice's Paillier keys
 	pkA *paillier.PublicKey,
@@ -521,7 +626,30 @@ func AliceVerifyG(
 	if rpV == nil {
 		return true
 	}
-	statement := &zkproofs.AffGInvStatement{
+	return proof.Verify(Session, affGInvStatement(ec, pkA, pkB, cA, cAlpha, cBeta, B), rpV)
+}
+
+// AliceVerifyGBatch is AliceVerifyG with the ring-Pedersen equations of the proof of `prover` deferred to `batch`,
+// which holds the equations of the proofs verified against the ring-Pedersen parameters of the verifier
+func AliceVerifyGBatch(
+	Session []byte,
+	ec elliptic.Curve,
+	pkA *paillier.PublicKey,
+	pkB *paillier.PublicKey,
+	proof *zkproofs.AffGInvProof,
+	cA, cAlpha, cBeta *big.Int,
+	B *crypto.ECPoint,
+	batch *zkproofs.RingPedersenBatch,
+	prover int,
+) bool {
+	if batch == nil {
+		return true
+	}
+	return proof.VerifyBatch(Session, affGInvStatement(ec, pkA, pkB, cA, cAlpha, cBeta, B), batch, prover)
+}
+
+func affGInvStatement(ec elliptic.Curve, pkA, pkB *paillier.PublicKey, cA, cAlpha, cBeta *big.Int, B *crypto.ECPoint) *zkproofs.AffGInvStatement {
+	return &zkproofs.AffGInvStatement{
 		zkproofs.AffGStatement{
 			C:        cA,                  // Alice's ciphertext
 			D:        cAlpha,              // affine transform of Alice's ciphertext: cA(*)b + betaPrm
@@ -533,12 +661,10 @@ func AliceVerifyG(
 			EllPrime: zkproofs.GetEll(ec), // max size of plaintext
 		},
 	}
-
-	return proof.Verify(statement, rpV)
 }
 
-func DecProofs(sk *paillier.PrivateKey, ec elliptic.Curve, cBeta, cBetaPrm *big.Int, rpV []*zkproofs.RingPedersenParams) ([]*zkproofs.DecProof, error) {
-	cQ, err := sk.PublicKey.HomoAdd(cBeta, cBetaPrm)
+func DecProofs(Session []byte, sk paillier.Decrypter, ec elliptic.Curve, cBeta, cBetaPrm *big.Int, rpV []*zkproofs.RingPedersenParams) ([]*zkproofs.DecProof, error) {
+	cQ, err := sk.Public().HomoAdd(cBeta, cBetaPrm)
 	if err != nil {
 		return nil, err
 	}
@@ -551,7 +677,7 @@ func DecProofs(sk *paillier.PrivateKey, ec elliptic.Curve, cBeta, cBetaPrm *big.
 	statement := &zkproofs.DecStatement{
 		Q:   ec.Params().N,
 		Ell: zkproofs.GetEll(ec),
-		N0:  sk.PublicKey.N,
+		N0:  sk.Public().N,
 		C:   cQ,
 		X:   big.NewInt(0),
 	}
@@ -571,14 +697,14 @@ func DecProofs(sk *paillier.PrivateKey, ec elliptic.Curve, cBeta, cBetaPrm *big.
 				proofs[i] = nil
 				return
 			}
-			proofs[i] = zkproofs.NewDecProof(witness, statement, rp)
+			proofs[i] = zkproofs.NewDecProof(Session, witness, statement, rp)
 		}(i, rp)
 	}
 	wg.Wait()
 	return proofs, nil
 }
 
-func DecProofVerify(pk *paillier.PublicKey, ec elliptic.Curve, proof *zkproofs.DecProof, cBeta, cBetaPrm *big.Int, rp *zkproofs.RingPedersenParams) bool {
+func DecProofVerify(Session []byte, pk *paillier.PublicKey, ec elliptic.Curve, proof *zkproofs.DecProof, cBeta, cBetaPrm *big.Int, rp *zkproofs.RingPedersenParams) bool {
 	if rp == nil {
 		return true
 	}
@@ -595,5 +721,5 @@ func DecProofVerify(pk *paillier.PublicKey, ec elliptic.Curve, proof *zkproofs.D
 		X:   big.NewInt(0),
 	}
 
-	return proof.Verify(statement, rp)
+	return proof.Verify(Session, statement, rp)
 }
@@ -39,6 +39,8 @@ var (
 	ec       elliptic.Curve
 	q        *big.Int
 	ell      *big.Int
+
+	Session = []byte("session")
 )
 
 func setUp(t *testing.T) {
@@ -80,7 +82,7 @@ func TestMTA_P(t *testing.T) {
 	b := common.GetRandomPositiveInt(q)
 
 	rpVs := []*zkproofs.RingPedersenParams{rpA, rpA, nil, rpB}
-	cA, proofsA, err := accmta.AliceInit(ec, pkA, a, ra, rpVs)
+	cA, proofsA, err := accmta.AliceInit(Session, ec, pkA, a, ra, rpVs)
 	assert.NoError(t, err)
 	assert.NotNil(t, proofsA)
 	assert.NotNil(t, cA)
@@ -94,13 +96,13 @@ func TestMTA_P(t *testing.T) {
 		if i == 2 {
 			continue
 		}
-		assert.True(t, proofsA[i].Verify(statementA, rp))
-		assert.True(t, accmta.BobVerify(ec, pkA, proofsA[i], cA, rp))
+		assert.True(t, proofsA[i].Verify(Session, statementA, rp))
+		assert.True(t, accmta.BobVerify(Session, ec, pkA, proofsA[i], cA, rp))
 	}
 
 	cB, err := skB.Encrypt(b)
 	assert.NoError(t, err)
-	beta, cAlpha, cBeta, cBetaPrm, proofs, decProofs, err := accmta.BobRespondsP(ec, pkA, skB, proofsA[3], cB, cA, rpVs, rpB)
+	beta, cAlpha, cBeta, cBetaPrm, proofs, decProofs, err := accmta.BobRespondsP(Session, ec, pkA, skB, proofsA[3], cB, cA, rpVs, rpB)
 	assert.NoError(t, err)
 	assert.NotNil(t, beta)
 	assert.NotNil(t, cAlpha)
@@ -119,10 +121,10 @@ func TestMTA_P(t *testing.T) {
 		if rpVs[i] != nil {
 			assert.NotNil(t, proofs[i])
 		}
-		assert.True(t, accmta.AliceVerifyP(ec, &skA.PublicKey, pkB, proofs[i], cA, cAlpha, cBetaPrm, cB, rpVs[i]))
-		assert.True(t, accmta.DecProofVerify(pkB, ec, decProofs[i], cBeta, cBetaPrm, rpVs[i]))
+		assert.True(t, accmta.AliceVerifyP(Session, ec, &skA.PublicKey, pkB, proofs[i], cA, cAlpha, cBetaPrm, cB, rpVs[i]))
+		assert.True(t, accmta.DecProofVerify(Session, pkB, ec, decProofs[i], cBeta, cBetaPrm, rpVs[i]))
 	}
-	alpha, err := accmta.AliceEndP(ec, skA, pkB, proofs[0], decProofs[0], cA, cAlpha, cBeta, cBetaPrm, cB, rpA)
+	alpha, err := accmta.AliceEndP(Session, ec, skA, pkB, proofs[0], decProofs[0], cA, cAlpha, cBeta, cBetaPrm, cB, rpA)
 	assert.NotNil(t, alpha)
 	assert.NoError(t, err)
 
@@ -159,9 +161,9 @@ func TestDecTest(t *testing.T) {
 		Y:   q,
 		Rho: rho,
 	}
-	proof := zkproofs.NewDecProof(decWitness, decStatement, rpA)
+	proof := zkproofs.NewDecProof(Session, decWitness, decStatement, rpA)
 	assert.NotNil(t, proof)
-	assert.True(t, proof.Verify(decStatement, rpA))
+	assert.True(t, proof.Verify(Session, decStatement, rpA))
 
 }
 
@@ -175,7 +177,7 @@ func TestMTA_DL(t *testing.T) {
 	b := common.GetRandomPositiveInt(q)
 
 	rpVs := []*zkproofs.RingPedersenParams{rpA, rpA, nil, rpB}
-	cA, proofsA, err := accmta.AliceInit(ec, pkA, a, ra, rpVs)
+	cA, proofsA, err := accmta.AliceInit(Session, ec, pkA, a, ra, rpVs)
 	assert.NoError(t, err)
 	assert.NotNil(t, proofsA)
 	assert.NotNil(t, cA)
@@ -189,13 +191,13 @@ func TestMTA_DL(t *testing.T) {
 		if i == 2 {
 			continue
 		}
-		assert.True(t, proofsA[i].Verify(statementA, rp))
-		assert.True(t, accmta.BobVerify(ec, pkA, proofsA[i], cA, rp))
+		assert.True(t, proofsA[i].Verify(Session, statementA, rp))
+		assert.True(t, accmta.BobVerify(Session, ec, pkA, proofsA[i], cA, rp))
 	}
 
 	B := crypto.ScalarBaseMult(ec, b)
 	assert.NoError(t, err)
-	beta, cAlpha, cBeta, cBetaPrm, proofs, decProofs, err := accmta.BobRespondsDL(ec, pkA, skB, proofsA[3], b, cA, rpVs, rpB, B)
+	beta, cAlpha, cBeta, cBetaPrm, proofs, decProofs, err := accmta.BobRespondsDL(Session, ec, pkA, skB, proofsA[3], b, cA, rpVs, rpB, B)
 	assert.NoError(t, err)
 	assert.NotNil(t, beta)
 	assert.NotNil(t, cAlpha)
@@ -207,10 +209,10 @@ func TestMTA_DL(t *testing.T) {
 		if rpVs[i] != nil {
 			assert.NotNil(t, proofs[i])
 		}
-		assert.True(t, accmta.AliceVerifyDL(ec, &skA.PublicKey, pkB, proofs[i], cA, cAlpha, cBetaPrm, B, rpVs[i]))
-		assert.True(t, accmta.DecProofVerify(pkB, ec, decProofs[i], cBeta, cBetaPrm, rpVs[i]))
+		assert.True(t, accmta.AliceVerifyDL(Session, ec, &skA.PublicKey, pkB, proofs[i], cA, cAlpha, cBetaPrm, B, rpVs[i]))
+		assert.True(t, accmta.DecProofVerify(Session, pkB, ec, decProofs[i], cBeta, cBetaPrm, rpVs[i]))
 	}
-	alpha, err := accmta.AliceEndDL(ec, skA, pkB, proofs[0], decProofs[0], cA, cAlpha, cBeta, cBetaPrm, B, rpA)
+	alpha, err := accmta.AliceEndDL(Session, ec, skA, pkB, proofs[0], decProofs[0], cA, cAlpha, cBeta, cBetaPrm, B, rpA)
 	assert.NotNil(t, alpha)
 	assert.NoError(t, err)
 
@@ -230,7 +232,7 @@ func TestMTA_G(t *testing.T) {
 	b := common.GetRandomPositiveInt(q)
 
 	rpVs := []*zkproofs.RingPedersenParams{rpA, rpA, nil, rpB}
-	cA, proofsA, err := accmta.AliceInit(ec, pkA, a, ra, rpVs)
+	cA, proofsA, err := accmta.AliceInit(Session, ec, pkA, a, ra, rpVs)
 	assert.NoError(t, err)
 	assert.NotNil(t, proofsA)
 	assert.NotNil(t, cA)
@@ -244,13 +246,13 @@ func TestMTA_G(t *testing.T) {
 		if i == 2 {
 			continue
 		}
-		assert.True(t, proofsA[i].Verify(statementA, rp))
-		assert.True(t, accmta.BobVerify(ec, pkA, proofsA[i], cA, rp))
+		assert.True(t, proofsA[i].Verify(Session, statementA, rp))
+		assert.True(t, accmta.BobVerify(Session, ec, pkA, proofsA[i], cA, rp))
 	}
 
 	B := crypto.ScalarBaseMult(ec, b)
 	assert.NoError(t, err)
-	beta, cAlpha, cBeta, proofs, err := accmta.BobRespondsG(ec, pkA, skB, proofsA[3], b, cA, rpVs, rpB)
+	beta, cAlpha, cBeta, proofs, err := accmta.BobRespondsG(Session, ec, pkA, skB, proofsA[3], b, cA, rpVs, rpB)
 	assert.NoError(t, err)
 	assert.NotNil(t, beta)
 	assert.NotNil(t, cAlpha)
@@ -261,9 +263,9 @@ func TestMTA_G(t *testing.T) {
 		if rpVs[i] != nil {
 			assert.NotNil(t, proofs[i])
 		}
-		assert.True(t, accmta.AliceVerifyG(ec, &skA.PublicKey, pkB, proofs[i], cA, cAlpha, cBeta, B, rpVs[i]))
+		assert.True(t, accmta.AliceVerifyG(Session, ec, &skA.PublicKey, pkB, proofs[i], cA, cAlpha, cBeta, B, rpVs[i]))
 	}
-	alpha, err := accmta.AliceEndG(ec, skA, pkB, proofs[0], cA, cAlpha, cBeta, B, rpA)
+	alpha, err := accmta.AliceEndG(Session, ec, skA, pkB, proofs[0], cA, cAlpha, cBeta, B, rpA)
 	assert.NotNil(t, alpha)
 	assert.NoError(t, err)
 
@@ -272,3 +274,41 @@ func TestMTA_G(t *testing.T) {
 	left := common.ModInt(q).Mul(a, b)
 	assert.Equal(t, 0, left.Cmp(right))
 }
+
+func TestMTA_GBatch(t *testing.T) {
+	setUp(t)
+
+	a := common.GetRandomPositiveInt(q)
+	ra := common.GetRandomPositiveInt(pkA.N)
+	bs := []*big.Int{common.GetRandomPositiveInt(q), common.GetRandomPositiveInt(q)}
+	Bs := []*crypto.ECPoint{crypto.ScalarBaseMult(ec, bs[0]), crypto.ScalarBaseMult(ec, bs[1])}
+
+	rpVs := []*zkproofs.RingPedersenParams{rpA, nil, rpB}
+	cA, proofsA, err := accmta.AliceInit(Session, ec, pkA, a, ra, rpVs)
+	assert.NoError(t, err)
+
+	betas, cAlphas, cBetas, proofs, err := accmta.BobRespondsGBatch(Session, ec, pkA, skB, proofsA[2], bs, cA, rpVs, rpB)
+	assert.NoError(t, err)
+	assert.Len(t, proofs, len(bs))
+	for k := range bs {
+		assert.Len(t, proofs[k], len(rpVs))
+		assert.Nil(t, proofs[k][1])
+		assert.True(t, accmta.AliceVerifyG(Session, ec, pkA, pkB, proofs[k][0], cA, cAlphas[k], cBetas[k], Bs[k], rpA))
+	}
+	batchProofs := []*zkproofs.AffGInvProof{proofs[0][0], proofs[1][0]}
+	alphas, err := accmta.AliceEndGBatch(Session, ec, skA, pkB, batchProofs, cA, cAlphas, cBetas, Bs, rpA)
+	assert.NoError(t, err)
+
+	// expect: alpha_k + beta_k = a b_k
+	for k, b := range bs {
+		right := common.ModInt(q).Add(alphas[k], betas[k])
+		left := common.ModInt(q).Mul(a, b)
+		assert.Equal(t, 0, left.Cmp(right))
+	}
+
+	// the proofs of the batch do not hold for the swapped secrets
+	_, err = accmta.AliceEndGBatch(Session, ec, skA, pkB, batchProofs, cA, cAlphas, cBetas, []*crypto.ECPoint{Bs[1], Bs[0]}, rpA)
+	assert.Error(t, err)
+	_, _, _, _, err = accmta.BobRespondsGBatch(Session, ec, pkA, skB, proofsA[2], nil, cA, rpVs, rpB)
+	assert.Error(t, err)
+}
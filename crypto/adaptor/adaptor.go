@@ -0,0 +1,134 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package adaptor
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto"
+)
+
+// PreSignature is an ECDSA adaptor signature made against an adaptor point T = t*G.
+// It is not a valid signature on its own; whoever knows t can turn it into one with Complete,
+// and whoever sees both the pre-signature and the completed signature can recover t with Extract.
+//
+// The signing nonce k is split between two points: RHat = k^-1 * G and R = k^-1 * T.
+// The pre-signature scalar is S = k(m + r*x) where r = R.x mod q, so that S^-1 * (m*G + r*X) = RHat.
+type PreSignature struct {
+	R,
+	RHat *crypto.ECPoint
+	S,
+	M *big.Int
+}
+
+// Rx returns r, the x-coordinate of R reduced modulo the group order
+func (ps *PreSignature) Rx() *big.Int {
+	return new(big.Int).Mod(ps.R.X(), ps.R.Curve().Params().N)
+}
+
+func (ps *PreSignature) ValidateBasic() bool {
+	return ps != nil && ps.R.ValidateBasic() && ps.RHat.ValidateBasic() && ps.S != nil && ps.M != nil
+}
+
+// Verify checks the pre-signature against the public key `pub`.
+// It does not show that R was computed against T; the signing protocols check that among the signers.
+func (ps *PreSignature) Verify(pub *crypto.ECPoint) bool {
+	if !ps.ValidateBasic() || pub == nil || !pub.ValidateBasic() {
+		return false
+	}
+	ec := pub.Curve()
+	q := ec.Params().N
+	r := ps.Rx()
+	if r.Sign() == 0 || ps.S.Sign() <= 0 || ps.S.Cmp(q) >= 0 {
+		return false
+	}
+	modQ := common.ModInt(q)
+	sInv := modQ.ModInverse(ps.S)
+	mG := crypto.ScalarBaseMult(ec, modQ.Mul(ps.M, sInv))
+	rX := pub.ScalarMult(modQ.Mul(r, sInv))
+	expected, err := mG.Add(rX)
+	if err != nil {
+		return false
+	}
+	return expected.Equals(ps.RHat)
+}
+
+// Complete adapts the pre-signature into a regular ECDSA signature using the adaptor secret t.
+// Like the signing protocols, it outputs the low-S form of the signature together with its recovery id.
+func (ps *PreSignature) Complete(t *big.Int) (*common.SignatureData, error) {
+	if !ps.ValidateBasic() || t == nil {
+		return nil, errors.New("Complete() received nil or invalid value(s)")
+	}
+	ec := ps.R.Curve()
+	q := ec.Params().N
+	modQ := common.ModInt(q)
+	if new(big.Int).Mod(t, q).Sign() == 0 {
+		return nil, errors.New("adaptor secret must not be zero")
+	}
+	if !ps.RHat.ScalarMult(t).Equals(ps.R) {
+		return nil, errors.New("adaptor secret does not match the pre-signature")
+	}
+	s := modQ.Mul(ps.S, modQ.ModInverse(t))
+
+	recid := 0
+	// byte v = if(R.X > curve.N) then 2 else 0) | (if R.Y.IsEven then 0 else 1);
+	if ps.R.X().Cmp(q) > 0 {
+		recid = 2
+	}
+	if ps.R.Y().Bit(0) != 0 {
+		recid |= 1
+	}
+	s, recid = common.NormalizeLowS(q, s, recid)
+
+	bitSizeInBytes := (ec.Params().BitSize + 7) / 8
+	data := &common.SignatureData{
+		R:                 padToLengthBytes(ps.Rx().Bytes(), bitSizeInBytes),
+		S:                 padToLengthBytes(s.Bytes(), bitSizeInBytes),
+		SignatureRecovery: []byte{byte(recid)},
+		M:                 ps.M.Bytes(),
+	}
+	data.Signature = append(append([]byte{}, data.R...), data.S...)
+	return data, nil
+}
+
+// Extract recovers the adaptor secret t of `T` from the pre-signature and the signature completed from it
+func (ps *PreSignature) Extract(sig *common.SignatureData, T *crypto.ECPoint) (*big.Int, error) {
+	if !ps.ValidateBasic() || sig == nil || T == nil || !T.ValidateBasic() {
+		return nil, errors.New("Extract() received nil or invalid value(s)")
+	}
+	ec := ps.R.Curve()
+	q := ec.Params().N
+	if new(big.Int).SetBytes(sig.GetR()).Cmp(ps.Rx()) != 0 {
+		return nil, errors.New("signature was not completed from this pre-signature")
+	}
+	s := new(big.Int).SetBytes(sig.GetS())
+	if s.Sign() == 0 || s.Cmp(q) >= 0 {
+		return nil, errors.New("signature has an invalid S")
+	}
+	modQ := common.ModInt(q)
+	t := modQ.Mul(ps.S, modQ.ModInverse(s))
+	// the completed signature may have been normalized to low-S, in which case the secret is -t
+	if crypto.ScalarBaseMult(ec, t).Equals(T) {
+		return t, nil
+	}
+	t = modQ.Sub(big.NewInt(0), t)
+	if crypto.ScalarBaseMult(ec, t).Equals(T) {
+		return t, nil
+	}
+	return nil, errors.New("could not extract the adaptor secret")
+}
+
+func padToLengthBytes(src []byte, length int) []byte {
+	if len(src) >= length {
+		return src
+	}
+	out := make([]byte, length)
+	copy(out[length-len(src):], src)
+	return out
+}
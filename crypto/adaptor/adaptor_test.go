@@ -0,0 +1,83 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package adaptor_test
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto"
+	. "github.com/kisdex/mpc-lib/crypto/adaptor"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+// makePreSignature pre-signs `m` the way the threshold protocols do, with the secrets held in one place
+func makePreSignature(x, k, m *big.Int, T *crypto.ECPoint) *PreSignature {
+	ec := tss.EC()
+	modQ := common.ModInt(ec.Params().N)
+	kInv := modQ.ModInverse(k)
+	ps := &PreSignature{
+		R:    T.ScalarMult(kInv),
+		RHat: crypto.ScalarBaseMult(ec, kInv),
+		M:    m,
+	}
+	ps.S = modQ.Mul(k, modQ.Add(m, modQ.Mul(ps.Rx(), x)))
+	return ps
+}
+
+func TestCompleteAndExtract(t *testing.T) {
+	ec := tss.EC()
+	q := ec.Params().N
+	x := common.GetRandomPositiveInt(q)
+	X := crypto.ScalarBaseMult(ec, x)
+	secret := common.GetRandomPositiveInt(q)
+	T := crypto.ScalarBaseMult(ec, secret)
+	m := common.GetRandomPositiveInt(q)
+
+	for i := 0; i < 8; i++ {
+		ps := makePreSignature(x, common.GetRandomPositiveInt(q), m, T)
+		assert.True(t, ps.Verify(X), "pre-signature must verify")
+
+		sig, err := ps.Complete(secret)
+		if !assert.NoError(t, err) {
+			return
+		}
+		r, s := new(big.Int).SetBytes(sig.R), new(big.Int).SetBytes(sig.S)
+		assert.True(t, ecdsa.Verify(X.ToECDSAPubKey(), m.Bytes(), r, s), "completed signature must verify")
+		assert.True(t, s.Cmp(new(big.Int).Rsh(q, 1)) <= 0, "completed signature must be low-S")
+
+		extracted, err := ps.Extract(sig, T)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, extracted.Cmp(secret), "extracted secret must equal t")
+	}
+}
+
+func TestVerifyWrongKey(t *testing.T) {
+	ec := tss.EC()
+	q := ec.Params().N
+	x := common.GetRandomPositiveInt(q)
+	T := crypto.ScalarBaseMult(ec, common.GetRandomPositiveInt(q))
+	ps := makePreSignature(x, common.GetRandomPositiveInt(q), common.GetRandomPositiveInt(q), T)
+
+	otherX := crypto.ScalarBaseMult(ec, common.GetRandomPositiveInt(q))
+	assert.False(t, ps.Verify(otherX), "pre-signature must not verify under another key")
+}
+
+func TestCompleteWrongSecret(t *testing.T) {
+	ec := tss.EC()
+	q := ec.Params().N
+	x := common.GetRandomPositiveInt(q)
+	T := crypto.ScalarBaseMult(ec, common.GetRandomPositiveInt(q))
+	ps := makePreSignature(x, common.GetRandomPositiveInt(q), common.GetRandomPositiveInt(q), T)
+
+	_, err := ps.Complete(common.GetRandomPositiveInt(q))
+	assert.Error(t, err)
+}
@@ -0,0 +1,293 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package bls implements BLS signatures over BLS12-381 following the proof-of-possession scheme of
+// draft-irtf-cfrg-bls-signature, plus the Shamir/Feldman helpers used to run it with threshold keys.
+package bls
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	bls12381 "github.com/kilic/bls12-381"
+	"github.com/kisdex/mpc-lib/common"
+)
+
+// Variant selects which source group holds public keys and which holds signatures
+type Variant byte
+
+const (
+	// MinPubKey puts public keys in G1 (48 bytes) and signatures in G2 (96 bytes), as in Ethereum consensus
+	MinPubKey Variant = iota
+	// MinSig puts signatures in G1 (48 bytes) and public keys in G2 (96 bytes)
+	MinSig
+)
+
+const (
+	dstSigMinPubKey = "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_"
+	dstPopMinPubKey = "BLS_POP_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_"
+	dstSigMinSig    = "BLS_SIG_BLS12381G1_XMD:SHA-256_SSWU_RO_POP_"
+	dstPopMinSig    = "BLS_POP_BLS12381G1_XMD:SHA-256_SSWU_RO_POP_"
+)
+
+var (
+	errUnknownVariant = errors.New("unknown BLS variant")
+
+	order = bls12381.NewG1().Q()
+)
+
+// Order returns the prime order r of G1, G2 and of the scalar field secret keys live in
+func Order() *big.Int {
+	return new(big.Int).Set(order)
+}
+
+func (v Variant) Valid() bool {
+	return v == MinPubKey || v == MinSig
+}
+
+func (v Variant) String() string {
+	switch v {
+	case MinPubKey:
+		return "min-pk"
+	case MinSig:
+		return "min-sig"
+	default:
+		return fmt.Sprintf("unknown(%d)", byte(v))
+	}
+}
+
+// PublicKeyLen returns the length of a compressed public key
+func (v Variant) PublicKeyLen() int {
+	return v.pkGroup().pointLen()
+}
+
+// SignatureLen returns the length of a compressed signature
+func (v Variant) SignatureLen() int {
+	return v.sigGroup().pointLen()
+}
+
+func (v Variant) pkGroup() group {
+	if v == MinSig {
+		return g2Group{}
+	}
+	return g1Group{}
+}
+
+func (v Variant) sigGroup() group {
+	if v == MinSig {
+		return g1Group{}
+	}
+	return g2Group{}
+}
+
+func (v Variant) sigDST() []byte {
+	if v == MinSig {
+		return []byte(dstSigMinSig)
+	}
+	return []byte(dstSigMinPubKey)
+}
+
+func (v Variant) popDST() []byte {
+	if v == MinSig {
+		return []byte(dstPopMinSig)
+	}
+	return []byte(dstPopMinPubKey)
+}
+
+// ----- //
+
+// PublicKey returns sk*G in the public key group
+func (v Variant) PublicKey(sk *big.Int) []byte {
+	return v.pkGroup().mulBase(sk)
+}
+
+// ValidatePublicKey checks that pk is a non-identity point of the public key group
+func (v Variant) ValidatePublicKey(pk []byte) error {
+	if !v.Valid() {
+		return errUnknownVariant
+	}
+	return v.pkGroup().validate(pk)
+}
+
+// ValidateSignature checks that sig is a non-identity point of the signature group
+func (v Variant) ValidateSignature(sig []byte) error {
+	if !v.Valid() {
+		return errUnknownVariant
+	}
+	return v.sigGroup().validate(sig)
+}
+
+// Sign returns sk*H(msg). With a Shamir share as sk this is a partial signature
+func (v Variant) Sign(sk *big.Int, msg []byte) ([]byte, error) {
+	return v.signWithDST(sk, msg, v.sigDST())
+}
+
+// Verify checks sig against pk and msg. It is used for both full and partial signatures
+func (v Variant) Verify(pk, msg, sig []byte) bool {
+	return v.verifyWithDST(pk, msg, sig, v.sigDST())
+}
+
+// ProvePossession returns a proof of possession of sk, i.e. a signature of the public key under the PoP tag
+func (v Variant) ProvePossession(sk *big.Int) ([]byte, error) {
+	return v.signWithDST(sk, v.PublicKey(sk), v.popDST())
+}
+
+// VerifyPossession checks a proof produced by ProvePossession
+func (v Variant) VerifyPossession(pk, proof []byte) bool {
+	return v.verifyWithDST(pk, pk, proof, v.popDST())
+}
+
+func (v Variant) signWithDST(sk *big.Int, msg, dst []byte) ([]byte, error) {
+	if !v.Valid() {
+		return nil, errUnknownVariant
+	}
+	h, err := v.sigGroup().hash(msg, dst)
+	if err != nil {
+		return nil, err
+	}
+	return v.sigGroup().mul(h, sk)
+}
+
+func (v Variant) verifyWithDST(pk, msg, sig, dst []byte) bool {
+	if err := v.ValidatePublicKey(pk); err != nil {
+		return false
+	}
+	if err := v.ValidateSignature(sig); err != nil {
+		return false
+	}
+	h, err := v.sigGroup().hash(msg, dst)
+	if err != nil {
+		return false
+	}
+	ok, err := pairingCheck(v, pk, h, sig)
+	return err == nil && ok
+}
+
+// ----- //
+
+// CreateShares deals `secret` with a degree `threshold` polynomial and returns the Feldman commitments to its
+// coefficients in the public key group, along with the shares f(id) for each id in `indexes`.
+func (v Variant) CreateShares(threshold int, secret *big.Int, indexes []*big.Int) ([][]byte, []*big.Int, error) {
+	if !v.Valid() {
+		return nil, nil, errUnknownVariant
+	}
+	if threshold < 1 {
+		return nil, nil, errors.New("threshold < 1")
+	}
+	if len(indexes) <= threshold {
+		return nil, nil, errors.New("the number of shares must be greater than the threshold")
+	}
+	modQ := common.ModInt(order)
+	for _, id := range indexes {
+		if modQ.Add(id, big.NewInt(0)).Sign() == 0 {
+			return nil, nil, errors.New("party index should not be 0")
+		}
+	}
+
+	poly := make([]*big.Int, threshold+1)
+	poly[0] = secret
+	for i := 1; i <= threshold; i++ {
+		poly[i] = common.GetRandomPositiveInt(order)
+	}
+	vs := make([][]byte, len(poly))
+	for i, ai := range poly {
+		vs[i] = v.PublicKey(ai)
+	}
+
+	shares := make([]*big.Int, len(indexes))
+	for i, id := range indexes {
+		shares[i] = evaluatePolynomial(poly, id)
+	}
+	return vs, shares, nil
+}
+
+// PublicShare evaluates the committed polynomial "in the exponent" at id, giving f(id)*G
+func (v Variant) PublicShare(vs [][]byte, id *big.Int) ([]byte, error) {
+	if len(vs) == 0 {
+		return nil, errors.New("empty commitments")
+	}
+	modQ := common.ModInt(order)
+	terms := make([][]byte, len(vs))
+	terms[0] = vs[0]
+	z := big.NewInt(1)
+	for c := 1; c < len(vs); c++ {
+		z = modQ.Mul(z, id)
+		term, err := v.pkGroup().mul(vs[c], z)
+		if err != nil {
+			return nil, err
+		}
+		terms[c] = term
+	}
+	return v.pkGroup().sum(terms...)
+}
+
+// VerifyShare checks share*G == f(id)*G against the Feldman commitments
+func (v Variant) VerifyShare(vs [][]byte, id, share *big.Int) bool {
+	expected, err := v.PublicShare(vs, id)
+	if err != nil {
+		return false
+	}
+	return string(expected) == string(v.PublicKey(share))
+}
+
+// AddPublicKeys returns the sum of the given public key group points
+func (v Variant) AddPublicKeys(pks ...[]byte) ([]byte, error) {
+	if !v.Valid() {
+		return nil, errUnknownVariant
+	}
+	return v.pkGroup().sum(pks...)
+}
+
+// CombineSignatures interpolates partial signatures made with the Shamir shares at `ks` into the signature under the
+// shared secret. At least threshold+1 distinct partial signatures are needed for the result to verify.
+func (v Variant) CombineSignatures(ks []*big.Int, partials [][]byte) ([]byte, error) {
+	if !v.Valid() {
+		return nil, errUnknownVariant
+	}
+	if len(ks) != len(partials) || len(ks) == 0 {
+		return nil, errors.New("the number of indexes and partial signatures must match")
+	}
+	terms := make([][]byte, len(ks))
+	for i := range ks {
+		lambda, err := LagrangeCoefficient(i, ks)
+		if err != nil {
+			return nil, err
+		}
+		if terms[i], err = v.sigGroup().mul(partials[i], lambda); err != nil {
+			return nil, fmt.Errorf("partial signature %d: %w", i, err)
+		}
+	}
+	return v.sigGroup().sum(terms...)
+}
+
+// LagrangeCoefficient returns the coefficient of ks[i] when interpolating at zero over the set ks
+func LagrangeCoefficient(i int, ks []*big.Int) (*big.Int, error) {
+	modQ := common.ModInt(order)
+	coef := big.NewInt(1)
+	for j, kj := range ks {
+		if j == i {
+			continue
+		}
+		diff := modQ.Sub(kj, ks[i])
+		if diff.Sign() == 0 {
+			return nil, errors.New("index of two parties are equal")
+		}
+		coef = modQ.Mul(coef, modQ.Mul(kj, modQ.ModInverse(diff)))
+	}
+	return coef, nil
+}
+
+func evaluatePolynomial(poly []*big.Int, id *big.Int) *big.Int {
+	modQ := common.ModInt(order)
+	result := new(big.Int).Set(poly[0])
+	x := big.NewInt(1)
+	for i := 1; i < len(poly); i++ {
+		x = modQ.Mul(x, id)
+		result = modQ.Add(result, modQ.Mul(poly[i], x))
+	}
+	return result
+}
@@ -0,0 +1,81 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package bls_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/common"
+	. "github.com/kisdex/mpc-lib/crypto/bls"
+)
+
+var variants = []Variant{MinPubKey, MinSig}
+
+func TestSignVerify(t *testing.T) {
+	msg := []byte("hello bls")
+	for _, v := range variants {
+		sk := common.GetRandomPositiveInt(Order())
+		pk := v.PublicKey(sk)
+		assert.Len(t, pk, v.PublicKeyLen())
+
+		sig, err := v.Sign(sk, msg)
+		assert.NoError(t, err)
+		assert.Len(t, sig, v.SignatureLen())
+		assert.True(t, v.Verify(pk, msg, sig), v.String())
+		assert.False(t, v.Verify(pk, []byte("other"), sig), v.String())
+
+		proof, err := v.ProvePossession(sk)
+		assert.NoError(t, err)
+		assert.True(t, v.VerifyPossession(pk, proof), v.String())
+		// a PoP is domain separated from ordinary signatures over the same bytes
+		assert.False(t, v.Verify(pk, pk, proof), v.String())
+	}
+}
+
+func TestThresholdSignatures(t *testing.T) {
+	threshold, num := 2, 5
+	msg := []byte("threshold bls")
+	ids := make([]*big.Int, num)
+	for i := range ids {
+		ids[i] = big.NewInt(int64(i + 1))
+	}
+	for _, v := range variants {
+		secret := common.GetRandomPositiveInt(Order())
+		vs, shares, err := v.CreateShares(threshold, secret, ids)
+		assert.NoError(t, err)
+		assert.Len(t, vs, threshold+1)
+		assert.Equal(t, v.PublicKey(secret), vs[0])
+
+		partials := make([][]byte, num)
+		for i, share := range shares {
+			assert.True(t, v.VerifyShare(vs, ids[i], share))
+			assert.False(t, v.VerifyShare(vs, ids[i], new(big.Int).Add(share, big.NewInt(1))))
+			pub, err := v.PublicShare(vs, ids[i])
+			assert.NoError(t, err)
+			partials[i], err = v.Sign(share, msg)
+			assert.NoError(t, err)
+			assert.True(t, v.Verify(pub, msg, partials[i]), "partial signatures verify against the public share")
+		}
+
+		// any t+1 partial signatures combine into a signature under the shared key
+		sig, err := v.CombineSignatures(ids[1:threshold+2], partials[1:threshold+2])
+		assert.NoError(t, err)
+		assert.True(t, v.Verify(vs[0], msg, sig), v.String())
+
+		sig2, err := v.CombineSignatures(ids[2:], partials[2:])
+		assert.NoError(t, err)
+		assert.Equal(t, sig, sig2, "BLS signatures are unique")
+
+		// t partial signatures are not enough
+		sig3, err := v.CombineSignatures(ids[:threshold], partials[:threshold])
+		assert.NoError(t, err)
+		assert.False(t, v.Verify(vs[0], msg, sig3))
+	}
+}
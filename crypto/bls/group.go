@@ -0,0 +1,186 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package bls
+
+import (
+	"errors"
+	"math/big"
+
+	bls12381 "github.com/kilic/bls12-381"
+)
+
+// group is the subset of G1/G2 arithmetic the threshold schemes need. Points travel as compressed bytes so that they
+// can be put on the wire and into save data as they are; every decode checks the point is in the prime-order subgroup.
+// The underlying bls12381.G1/G2 objects keep scratch space and are not safe for concurrent use, so one is made per call.
+type group interface {
+	pointLen() int
+	mulBase(k *big.Int) []byte
+	mul(p []byte, k *big.Int) ([]byte, error)
+	sum(ps ...[]byte) ([]byte, error)
+	hash(msg, dst []byte) ([]byte, error)
+	validate(p []byte) error
+}
+
+type (
+	g1Group struct{}
+	g2Group struct{}
+)
+
+var (
+	_ group = g1Group{}
+	_ group = g2Group{}
+)
+
+// ----- //
+
+func (g1Group) pointLen() int {
+	return 48
+}
+
+func (g1Group) mulBase(k *big.Int) []byte {
+	g := bls12381.NewG1()
+	return g.ToCompressed(g.MulScalarBig(g.New(), g.One(), k))
+}
+
+func (g1Group) mul(p []byte, k *big.Int) ([]byte, error) {
+	g := bls12381.NewG1()
+	pt, err := g.FromCompressed(p)
+	if err != nil {
+		return nil, err
+	}
+	return g.ToCompressed(g.MulScalarBig(g.New(), pt, k)), nil
+}
+
+func (g1Group) sum(ps ...[]byte) ([]byte, error) {
+	g := bls12381.NewG1()
+	acc := g.Zero()
+	for _, p := range ps {
+		pt, err := g.FromCompressed(p)
+		if err != nil {
+			return nil, err
+		}
+		g.Add(acc, acc, pt)
+	}
+	return g.ToCompressed(acc), nil
+}
+
+func (g1Group) hash(msg, dst []byte) ([]byte, error) {
+	g := bls12381.NewG1()
+	pt, err := g.HashToCurve(msg, dst)
+	if err != nil {
+		return nil, err
+	}
+	return g.ToCompressed(pt), nil
+}
+
+func (g1Group) validate(p []byte) error {
+	g := bls12381.NewG1()
+	pt, err := g.FromCompressed(p)
+	if err != nil {
+		return err
+	}
+	if g.IsZero(pt) {
+		return errors.New("point is the identity")
+	}
+	return nil
+}
+
+// ----- //
+
+func (g2Group) pointLen() int {
+	return 96
+}
+
+func (g2Group) mulBase(k *big.Int) []byte {
+	g := bls12381.NewG2()
+	return g.ToCompressed(g.MulScalarBig(g.New(), g.One(), k))
+}
+
+func (g2Group) mul(p []byte, k *big.Int) ([]byte, error) {
+	g := bls12381.NewG2()
+	pt, err := g.FromCompressed(p)
+	if err != nil {
+		return nil, err
+	}
+	return g.ToCompressed(g.MulScalarBig(g.New(), pt, k)), nil
+}
+
+func (g2Group) sum(ps ...[]byte) ([]byte, error) {
+	g := bls12381.NewG2()
+	acc := g.Zero()
+	for _, p := range ps {
+		pt, err := g.FromCompressed(p)
+		if err != nil {
+			return nil, err
+		}
+		g.Add(acc, acc, pt)
+	}
+	return g.ToCompressed(acc), nil
+}
+
+func (g2Group) hash(msg, dst []byte) ([]byte, error) {
+	g := bls12381.NewG2()
+	pt, err := g.HashToCurve(msg, dst)
+	if err != nil {
+		return nil, err
+	}
+	return g.ToCompressed(pt), nil
+}
+
+func (g2Group) validate(p []byte) error {
+	g := bls12381.NewG2()
+	pt, err := g.FromCompressed(p)
+	if err != nil {
+		return err
+	}
+	if g.IsZero(pt) {
+		return errors.New("point is the identity")
+	}
+	return nil
+}
+
+// ----- //
+
+// pairingCheck returns true iff e(pkPt, hashPt) == e(base, sig), with the arguments placed in G1/G2 as the variant
+// dictates. This is the single equation behind signature, partial signature and proof-of-possession verification.
+func pairingCheck(v Variant, pk, h, sig []byte) (bool, error) {
+	g1, g2 := bls12381.NewG1(), bls12381.NewG2()
+	e := bls12381.NewEngine()
+	switch v {
+	case MinPubKey:
+		pkPt, err := g1.FromCompressed(pk)
+		if err != nil {
+			return false, err
+		}
+		hPt, err := g2.FromCompressed(h)
+		if err != nil {
+			return false, err
+		}
+		sigPt, err := g2.FromCompressed(sig)
+		if err != nil {
+			return false, err
+		}
+		e.AddPair(pkPt, hPt).AddPairInv(g1.One(), sigPt)
+	case MinSig:
+		pkPt, err := g2.FromCompressed(pk)
+		if err != nil {
+			return false, err
+		}
+		hPt, err := g1.FromCompressed(h)
+		if err != nil {
+			return false, err
+		}
+		sigPt, err := g1.FromCompressed(sig)
+		if err != nil {
+			return false, err
+		}
+		e.AddPair(hPt, pkPt).AddPairInv(sigPt, g2.One())
+	default:
+		return false, errUnknownVariant
+	}
+	return e.Check(), nil
+}
@@ -7,10 +7,15 @@
 package ckd_test
 
 import (
+	"crypto/ecdsa"
+	"math/big"
 	"testing"
 
 	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+	"github.com/kisdex/mpc-lib/crypto"
 	. "github.com/kisdex/mpc-lib/crypto/ckd"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestPublicDerivation(t *testing.T) {
@@ -130,3 +135,41 @@ tests:
 		}
 	}
 }
+
+func TestEd25519PublicDerivation(t *testing.T) {
+	ec := edwards.Edwards()
+	x := big.NewInt(123456789)
+	masterPub := crypto.ScalarBaseMult(ec, x)
+	chainCode := make([]byte, 32)
+	for i := range chainCode {
+		chainCode[i] = byte(i)
+	}
+	master := &ExtendedKey{
+		PublicKey: ecdsa.PublicKey{Curve: ec, X: masterPub.X(), Y: masterPub.Y()},
+		ChainCode: chainCode,
+		ParentFP:  []byte{0x00, 0x00, 0x00, 0x00},
+	}
+
+	path := []uint32{44, 501, 0, 0}
+	delta, child, err := DeriveEd25519ChildKeyFromHierarchy(path, master, ec)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(len(path)), child.Depth)
+
+	// the delta applied to the master secret must yield the child public key
+	childSecret := new(big.Int).Mod(new(big.Int).Add(x, delta), ec.Params().N)
+	expected := crypto.ScalarBaseMult(ec, childSecret)
+	assert.Equal(t, 0, expected.X().Cmp(child.X))
+	assert.Equal(t, 0, expected.Y().Cmp(child.Y))
+
+	// derivation is deterministic and depends on the index
+	delta2, child2, err := DeriveEd25519ChildKeyFromHierarchy(path, master, ec)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, delta.Cmp(delta2))
+	assert.Equal(t, child.ChainCode, child2.ChainCode)
+	_, sibling, err := DeriveEd25519ChildKeyFromHierarchy([]uint32{44, 501, 0, 1}, master, ec)
+	assert.NoError(t, err)
+	assert.NotEqual(t, 0, sibling.X.Cmp(child.X))
+
+	_, _, err = DeriveEd25519ChildKey(HardenedKeyStart, master, ec)
+	assert.Error(t, err, "hardened derivation must be rejected")
+}
@@ -0,0 +1,109 @@
+// Copyright © Swingby
+
+package ckd
+
+import (
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto"
+)
+
+// SLIP-0010 only defines hardened derivation for Ed25519, which needs the private key and therefore cannot be
+// computed over threshold shares. The functions below follow the SLIP-0010 layout (HMAC-SHA512 keyed by the chain
+// code over the 32-byte Ed25519 public key and the big-endian index) but use the public, non-hardened construction:
+// the left half of I is reduced modulo the group order and added to the parent key, so that every party can shift
+// its share by the same delta without interaction.
+
+const (
+	Ed25519PubKeyBytesLen = 32
+)
+
+func DeriveEd25519ChildKeyFromHierarchy(indicesHierarchy []uint32, pk *ExtendedKey, curve elliptic.Curve) (*big.Int, *ExtendedKey, error) {
+	var k = pk
+	var err error
+	var childKey *ExtendedKey
+	mod_ := common.ModInt(curve.Params().N)
+	ilNum := big.NewInt(0)
+	for index := range indicesHierarchy {
+		ilNumOld := ilNum
+		ilNum, childKey, err = DeriveEd25519ChildKey(indicesHierarchy[index], k, curve)
+		if err != nil {
+			return nil, nil, err
+		}
+		k = childKey
+		ilNum = mod_.Add(ilNum, ilNumOld)
+	}
+	return ilNum, k, nil
+}
+
+// DeriveEd25519ChildKey derives a non-hardened child of an Ed25519 public key. It returns IL reduced modulo the group
+// order, which is the delta to add to the parent secret, together with the derived child key.
+func DeriveEd25519ChildKey(index uint32, pk *ExtendedKey, curve elliptic.Curve) (*big.Int, *ExtendedKey, error) {
+	if index >= HardenedKeyStart {
+		return nil, nil, errors.New("the index must be non-hardened")
+	}
+	if pk.Depth == maxDepth {
+		return nil, nil, errors.New("cannot derive key beyond max depth")
+	}
+
+	cryptoPk, err := crypto.NewECPoint(curve, pk.X, pk.Y)
+	if err != nil {
+		common.DefaultLogger().Errorf("error getting pubkey from extendedkey")
+		return nil, nil, err
+	}
+
+	pkPublicKeyBytes := serializeEd25519(pk.X, pk.Y)
+
+	data := make([]byte, Ed25519PubKeyBytesLen+4)
+	copy(data, pkPublicKeyBytes)
+	binary.BigEndian.PutUint32(data[Ed25519PubKeyBytesLen:], index)
+
+	// I = HMAC-SHA512(Key = chainCode, Data=data)
+	hmac512 := hmac.New(sha512.New, pk.ChainCode)
+	hmac512.Write(data)
+	ilr := hmac512.Sum(nil)
+	il := ilr[:32]
+	childChainCode := ilr[32:]
+	// the ed25519 group order is ~2^252 so IL has to be reduced rather than rejected
+	ilNum := new(big.Int).Mod(new(big.Int).SetBytes(il), curve.Params().N)
+
+	if ilNum.Sign() == 0 {
+		err = errors.New("invalid derived key")
+		common.DefaultLogger().Errorf("error deriving child key")
+		return nil, nil, err
+	}
+
+	deltaG := crypto.ScalarBaseMult(curve, ilNum)
+	childCryptoPk, err := cryptoPk.Add(deltaG)
+	if err != nil {
+		common.DefaultLogger().Errorf("error adding delta G to parent key")
+		return nil, nil, err
+	}
+
+	childPk := &ExtendedKey{
+		PublicKey:  *childCryptoPk.ToECDSAPubKey(),
+		Depth:      pk.Depth + 1,
+		ChildIndex: index,
+		ChainCode:  childChainCode,
+		ParentFP:   hash160(pkPublicKeyBytes)[:4],
+		Version:    pk.Version,
+	}
+	return ilNum, childPk, nil
+}
+
+// serializeEd25519 serializes a public key in the 32-byte RFC 8032 encoding
+func serializeEd25519(publicKeyX *big.Int, publicKeyY *big.Int) []byte {
+	pk := edwards.PublicKey{
+		Curve: edwards.Edwards(),
+		X:     publicKeyX,
+		Y:     publicKeyY,
+	}
+	return pk.Serialize()
+}
@@ -21,6 +21,9 @@ import (
 
 const Iterations = 128
 
+// domain separation string of the challenge, see common.SHA512_256i_DOMAIN
+const domain = "mpc-lib/dlnproof"
+
 type (
 	Proof struct {
 		Alpha,
@@ -39,10 +42,10 @@ func NewDLNProof(h1, h2, x, p, q, N *big.Int) *Proof {
 	alpha := [Iterations]*big.Int{}
 	for i := range alpha {
 		a[i] = common.GetRandomPositiveInt(pMulQ)
-		alpha[i] = modN.Exp(h1, a[i])
+		alpha[i] = modN.SecretExp(h1, a[i], pMulQ)
 	}
 	msg := append([]*big.Int{h1, h2, N}, alpha[:]...)
-	c := common.SHA512_256i(msg...)
+	c := common.SHA512_256i_DOMAIN(domain, nil, msg...)
 	t := [Iterations]*big.Int{}
 	cIBI := new(big.Int)
 	for i := range t {
@@ -85,7 +88,7 @@ func (p *Proof) Verify(h1, h2, N *big.Int) bool {
 		}
 	}
 	msg := append([]*big.Int{h1, h2, N}, p.Alpha[:]...)
-	c := common.SHA512_256i(msg...)
+	c := common.SHA512_256i_DOMAIN(domain, nil, msg...)
 	cIBI := new(big.Int)
 	for i := 0; i < Iterations; i++ {
 		if p.Alpha[i] == nil || p.T[i] == nil {
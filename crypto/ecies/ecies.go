@@ -0,0 +1,169 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package ecies implements the ECIES of SEC 1 with the parameters of go-ethereum's crypto/ecies for 256-bit curves and
+// no shared information: the concatenation KDF of NIST SP 800-56 with SHA-256, AES-128-CTR and HMAC-SHA256.
+// A ciphertext is R || IV || AES-128-CTR(m) || tag, for the uncompressed ephemeral public key R.
+// The decryption is split at the ECDH step, so that the shared point x*R may be computed by the holders of the shares
+// of x, see ecdsa/decryption.
+
+package ecies
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto"
+)
+
+const (
+	keyLen = 16 // AES-128
+	tagLen = sha256.Size
+)
+
+var ErrInvalidMessage = errors.New("invalid ECIES message")
+
+// Encrypt encrypts `m` to the public key `pub`
+func Encrypt(pub *crypto.ECPoint, m []byte) ([]byte, error) {
+	if !crypto.ValidatePoints(pub) {
+		return nil, errors.New("the public key is not a valid point")
+	}
+	ec := pub.Curve()
+	r := common.GetRandomPositiveInt(ec.Params().N)
+	R := crypto.ScalarBaseMult(ec, r)
+	ke, km := deriveKeys(pub.ScalarMult(r))
+
+	iv, err := common.GetRandomBytes(aes.BlockSize)
+	if err != nil {
+		return nil, err
+	}
+	em, err := symEncrypt(ke, iv, m)
+	if err != nil {
+		return nil, err
+	}
+	ct := append(marshalPoint(R), em...)
+	return append(ct, messageTag(km, em)...), nil
+}
+
+// EphemeralKey returns the ephemeral public key R of the ciphertext `ct` on the curve `ec`, for the ECDH step of its
+// decryption
+func EphemeralKey(ec elliptic.Curve, ct []byte) (*crypto.ECPoint, error) {
+	R, _, err := split(ec, ct)
+	return R, err
+}
+
+// DecryptWithSharedPoint decrypts the ciphertext `ct` with the shared point S = x*R of its ephemeral public key R and
+// the private key x
+func DecryptWithSharedPoint(S *crypto.ECPoint, ct []byte) ([]byte, error) {
+	if !crypto.ValidatePoints(S) {
+		return nil, errors.New("the shared point is not valid")
+	}
+	_, em, err := split(S.Curve(), ct)
+	if err != nil {
+		return nil, err
+	}
+	ke, km := deriveKeys(S)
+	tag := em[len(em)-tagLen:]
+	em = em[:len(em)-tagLen]
+	if subtle.ConstantTimeCompare(tag, messageTag(km, em)) != 1 {
+		return nil, ErrInvalidMessage
+	}
+	return symDecrypt(ke, em)
+}
+
+// Decrypt decrypts the ciphertext `ct` with the private key `x` on the curve `ec`
+func Decrypt(ec elliptic.Curve, x *big.Int, ct []byte) ([]byte, error) {
+	R, err := EphemeralKey(ec, ct)
+	if err != nil {
+		return nil, err
+	}
+	return DecryptWithSharedPoint(R.ScalarMult(x), ct)
+}
+
+// ----- utils
+
+// split returns the ephemeral public key R of `ct` and the rest of it, IV || ciphertext || tag
+func split(ec elliptic.Curve, ct []byte) (*crypto.ECPoint, []byte, error) {
+	pointLen := 1 + 2*byteLen(ec)
+	if len(ct) < pointLen+aes.BlockSize+tagLen || ct[0] != 4 {
+		return nil, nil, ErrInvalidMessage
+	}
+	R, err := crypto.NewECPoint(ec, new(big.Int).SetBytes(ct[1:1+byteLen(ec)]), new(big.Int).SetBytes(ct[1+byteLen(ec):pointLen]))
+	if err != nil || !crypto.ValidatePoints(R) {
+		return nil, nil, ErrInvalidMessage
+	}
+	return R, ct[pointLen:], nil
+}
+
+// deriveKeys returns the encryption key and the MAC key of the shared point S, from the KDF of its x-coordinate
+func deriveKeys(S *crypto.ECPoint) (ke, km []byte) {
+	z := S.X().FillBytes(make([]byte, byteLen(S.Curve())))
+	k := concatKDF(z, 2*keyLen)
+	mk := sha256.Sum256(k[keyLen:])
+	return k[:keyLen], mk[:]
+}
+
+// concatKDF is the concatenation KDF of NIST SP 800-56 with SHA-256 and no other information
+func concatKDF(z []byte, length int) []byte {
+	k := make([]byte, 0, length+sha256.Size)
+	var counter [4]byte
+	for i := uint32(1); len(k) < length; i++ {
+		binary.BigEndian.PutUint32(counter[:], i)
+		h := sha256.New()
+		h.Write(counter[:])
+		h.Write(z)
+		k = h.Sum(k)
+	}
+	return k[:length]
+}
+
+func messageTag(km, em []byte) []byte {
+	mac := hmac.New(sha256.New, km)
+	mac.Write(em)
+	return mac.Sum(nil)
+}
+
+func symEncrypt(ke, iv, m []byte) ([]byte, error) {
+	block, err := aes.NewCipher(ke)
+	if err != nil {
+		return nil, err
+	}
+	em := make([]byte, len(iv)+len(m))
+	copy(em, iv)
+	cipher.NewCTR(block, iv).XORKeyStream(em[len(iv):], m)
+	return em, nil
+}
+
+func symDecrypt(ke, em []byte) ([]byte, error) {
+	block, err := aes.NewCipher(ke)
+	if err != nil {
+		return nil, err
+	}
+	m := make([]byte, len(em)-aes.BlockSize)
+	cipher.NewCTR(block, em[:aes.BlockSize]).XORKeyStream(m, em[aes.BlockSize:])
+	return m, nil
+}
+
+func marshalPoint(P *crypto.ECPoint) []byte {
+	n := byteLen(P.Curve())
+	bz := make([]byte, 1+2*n)
+	bz[0] = 4
+	P.X().FillBytes(bz[1 : 1+n])
+	P.Y().FillBytes(bz[1+n:])
+	return bz
+}
+
+func byteLen(ec elliptic.Curve) int {
+	return (ec.Params().BitSize + 7) / 8
+}
@@ -0,0 +1,57 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package ecies_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto"
+	. "github.com/kisdex/mpc-lib/crypto/ecies"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	ec := tss.S256()
+	x := common.GetRandomPositiveInt(ec.Params().N)
+	pub := crypto.ScalarBaseMult(ec, x)
+
+	m := []byte("a message for the committee")
+	ct, err := Encrypt(pub, m)
+	assert.NoError(t, err)
+	decrypted, err := Decrypt(ec, x, ct)
+	assert.NoError(t, err)
+	assert.Equal(t, m, decrypted)
+
+	// the ECDH step may be done apart from the rest of the decryption
+	R, err := EphemeralKey(ec, ct)
+	assert.NoError(t, err)
+	decrypted, err = DecryptWithSharedPoint(R.ScalarMult(x), ct)
+	assert.NoError(t, err)
+	assert.Equal(t, m, decrypted)
+
+	// another key, a tampered ciphertext or a truncated one do not decrypt
+	_, err = Decrypt(ec, common.GetRandomPositiveInt(ec.Params().N), ct)
+	assert.ErrorIs(t, err, ErrInvalidMessage)
+	ct[len(ct)-40] ^= 1
+	_, err = Decrypt(ec, x, ct)
+	assert.ErrorIs(t, err, ErrInvalidMessage)
+	_, err = Decrypt(ec, x, ct[:80])
+	assert.ErrorIs(t, err, ErrInvalidMessage)
+}
+
+func TestEncryptEmptyMessage(t *testing.T) {
+	ec := tss.S256()
+	x := common.GetRandomPositiveInt(ec.Params().N)
+	ct, err := Encrypt(crypto.ScalarBaseMult(ec, x), nil)
+	assert.NoError(t, err)
+	decrypted, err := Decrypt(ec, x, ct)
+	assert.NoError(t, err)
+	assert.Empty(t, decrypted)
+}
@@ -18,6 +18,8 @@ import (
 
 	"github.com/decred/dcrd/dcrec/edwards/v2"
 
+	"github.com/kisdex/mpc-lib/crypto/edwards448"
+	"github.com/kisdex/mpc-lib/crypto/ristretto255"
 	"github.com/kisdex/mpc-lib/tss"
 )
 
@@ -59,7 +61,11 @@ func (p *ECPoint) Add(p1 *ECPoint) (*ECPoint, error) {
 	return NewECPoint(p.curve, x, y)
 }
 
+// ScalarMult returns k*p; a negative k, e.g. the response of a proof of CGG21, is reduced modulo the order of the curve
 func (p *ECPoint) ScalarMult(k *big.Int) *ECPoint {
+	if k.Sign() < 0 {
+		k = new(big.Int).Mod(k, p.curve.Params().N)
+	}
 	x, y := p.curve.ScalarMult(p.X(), p.Y(), k.Bytes())
 	newP, err := NewECPoint(p.curve, x, y) // it must be on the curve, no need to check.
 	if err != nil {
@@ -100,11 +106,44 @@ func (p *ECPoint) ValidateBasic() bool {
 	return p != nil && p.coords[0] != nil && p.coords[1] != nil && p.IsOnCurve()
 }
 
+// IsIdentity returns true if p is the identity of its group: (0, 1) on the Edwards curves, and (0, 0), which stands
+// for the point at infinity, on the others
+func (p *ECPoint) IsIdentity() bool {
+	if p.coords[0].Sign() != 0 {
+		return false
+	}
+	switch p.curve.(type) {
+	case *edwards.TwistedEdwardsCurve, *ristretto255.Curve, *edwards448.Curve:
+		return p.coords[1].Cmp(big.NewInt(1)) == 0
+	}
+	return p.coords[1].Sign() == 0
+}
+
+// ValidatePoints returns true if every one of `points`, e.g. received from a peer, is on its curve and is not the
+// identity, which would make the equations of a proof trivially true
+func ValidatePoints(points ...*ECPoint) bool {
+	for _, p := range points {
+		if !p.ValidateBasic() || p.IsIdentity() {
+			return false
+		}
+	}
+	return true
+}
+
+// EightInvEight returns 8^-1 * 8 * p, the component of p in the subgroup of order N, which clears the small-order
+// component of a point of Ed25519 or Ed448
 func (p *ECPoint) EightInvEight() *ECPoint {
+	if !tss.SameCurve(p.curve, edwards.Edwards()) {
+		return p.ScalarMult(eight).ScalarMult(new(big.Int).ModInverse(eight, p.curve.Params().N))
+	}
 	return p.ScalarMult(eight).ScalarMult(eightInv)
 }
 
+// ScalarBaseMult returns k*G; a negative k is reduced modulo the order of the curve, as in ScalarMult
 func ScalarBaseMult(curve elliptic.Curve, k *big.Int) *ECPoint {
+	if k.Sign() < 0 {
+		k = new(big.Int).Mod(k, curve.Params().N)
+	}
 	x, y := curve.ScalarBaseMult(k.Bytes())
 	p, err := NewECPoint(curve, x, y) // it must be on the curve, no need to check.
 	if err != nil {
@@ -161,6 +200,145 @@ func UnFlattenECPoints(curve elliptic.Curve, in []*big.Int, noCurveCheck ...bool
 	return unFlat, nil
 }
 
+// ----- //
+// Compressed SEC1 encoding, which the protocol messages use for their points
+
+const (
+	compressedEven byte = 0x02
+	compressedOdd  byte = 0x03
+	uncompressed   byte = 0x04
+)
+
+// Bytes returns the compressed SEC1 encoding of the point: 0x02 or 0x03 for the parity of y, followed by x in the byte
+// size of the field of the curve. The points of the Edwards curves are encoded the same way.
+func (p *ECPoint) Bytes() []byte {
+	size := coordinateSize(p.curve)
+	bz := make([]byte, 1+size)
+	bz[0] = compressedEven | byte(p.coords[1].Bit(0))
+	p.coords[0].FillBytes(bz[1:])
+	return bz
+}
+
+// NewECPointFromBytes decodes a point of `curve` from its compressed SEC1 encoding returned by Bytes, or from its
+// uncompressed SEC1 encoding 0x04 || x || y
+func NewECPointFromBytes(curve elliptic.Curve, bz []byte) (*ECPoint, error) {
+	size := coordinateSize(curve)
+	switch {
+	case len(bz) == 1+size && (bz[0] == compressedEven || bz[0] == compressedOdd):
+		x := new(big.Int).SetBytes(bz[1:])
+		y, err := decompressY(curve, x, uint(bz[0]&1))
+		if err != nil {
+			return nil, err
+		}
+		return NewECPoint(curve, x, y)
+	case len(bz) == 1+2*size && bz[0] == uncompressed:
+		return NewECPoint(curve, new(big.Int).SetBytes(bz[1:1+size]), new(big.Int).SetBytes(bz[1+size:]))
+	default:
+		return nil, fmt.Errorf("NewECPointFromBytes: expected the %d-byte SEC1 encoding of a point, got %d bytes", 1+size, len(bz))
+	}
+}
+
+// CompressECPoints is FlattenECPoints for the compressed encoding: it returns the encodings of `in` as big.Ints, e.g.
+// to be committed to. The leading 0x02 or 0x03 of an encoding keeps its length through big.Int.
+func CompressECPoints(in []*ECPoint) ([]*big.Int, error) {
+	if in == nil {
+		return nil, errors.New("CompressECPoints encountered a nil in slice")
+	}
+	compressed := make([]*big.Int, len(in))
+	for i, point := range in {
+		if point == nil || point.coords[0] == nil || point.coords[1] == nil {
+			return nil, errors.New("CompressECPoints found nil point/coordinate")
+		}
+		compressed[i] = new(big.Int).SetBytes(point.Bytes())
+	}
+	return compressed, nil
+}
+
+// DecompressECPoints decodes the points returned by CompressECPoints
+func DecompressECPoints(curve elliptic.Curve, in []*big.Int) ([]*ECPoint, error) {
+	if in == nil {
+		return nil, errors.New("DecompressECPoints encountered a nil in slice")
+	}
+	points := make([]*ECPoint, len(in))
+	for i, v := range in {
+		if v == nil {
+			return nil, errors.New("DecompressECPoints found a nil encoding")
+		}
+		point, err := NewECPointFromBytes(curve, v.Bytes())
+		if err != nil {
+			return nil, err
+		}
+		points[i] = point
+	}
+	return points, nil
+}
+
+func coordinateSize(curve elliptic.Curve) int {
+	return (curve.Params().BitSize + 7) / 8
+}
+
+// decompressY returns the y of `curve` with the parity `bit` for `x`
+func decompressY(curve elliptic.Curve, x *big.Int, bit uint) (*big.Int, error) {
+	P := curve.Params().P
+	if P.Cmp(x) <= 0 {
+		return nil, errors.New("decompressY: x is not an element of the field")
+	}
+	ySq, err := ySquared(curve, x)
+	if err != nil {
+		return nil, err
+	}
+	y := new(big.Int).ModSqrt(ySq, P)
+	if y == nil {
+		return nil, errors.New("decompressY: x is not the coordinate of a point of the curve")
+	}
+	if y.Bit(0) != bit {
+		if y.Sign() == 0 {
+			return nil, errors.New("decompressY: y = 0 has no odd root")
+		}
+		y.Sub(P, y)
+	}
+	return y, nil
+}
+
+// ySquared returns y^2 for `x` from the equation of `curve`: a*x^2 + y^2 = 1 + d*x^2*y^2 for the Edwards curves and
+// y^2 = x^3 + a*x + b for the others, whose a is not in their params but follows from the base point.
+func ySquared(curve elliptic.Curve, x *big.Int) (*big.Int, error) {
+	switch c := curve.(type) {
+	case *edwards.TwistedEdwardsCurve:
+		return edwardsYSquared(c.P, c.A, c.D, x)
+	case *ristretto255.Curve:
+		return edwardsYSquared(c.P, c.A, c.D, x)
+	case *edwards448.Curve:
+		// edwards448 keeps d in the b of its params
+		return edwardsYSquared(c.Params().P, big.NewInt(1), c.Params().B, x)
+	}
+	params := curve.Params()
+	P, Gx, Gy := params.P, params.Gx, params.Gy
+	gxInv := new(big.Int).ModInverse(Gx, P)
+	if params.B == nil || gxInv == nil {
+		return nil, fmt.Errorf("ySquared: cannot recover the equation of the curve %s", params.Name)
+	}
+	// a = (Gy^2 - Gx^3 - b) / Gx
+	a := new(big.Int).Mul(Gy, Gy)
+	a.Sub(a, new(big.Int).Exp(Gx, big.NewInt(3), P)).Sub(a, params.B).Mul(a, gxInv).Mod(a, P)
+	ySq := new(big.Int).Exp(x, big.NewInt(3), P)
+	ySq.Add(ySq, new(big.Int).Mul(a, x)).Add(ySq, params.B)
+	return ySq.Mod(ySq, P), nil
+}
+
+// edwardsYSquared solves a*x^2 + y^2 = 1 + d*x^2*y^2 for y^2 = (1 - a*x^2) / (1 - d*x^2)
+func edwardsYSquared(P, a, d, x *big.Int) (*big.Int, error) {
+	xx := new(big.Int).Mul(x, x)
+	num := new(big.Int).Sub(big.NewInt(1), new(big.Int).Mul(a, xx))
+	den := new(big.Int).Sub(big.NewInt(1), new(big.Int).Mul(d, xx))
+	denInv := new(big.Int).ModInverse(den.Mod(den, P), P)
+	if denInv == nil {
+		return nil, errors.New("edwardsYSquared: x is not the coordinate of a point of the curve")
+	}
+	num.Mul(num, denInv)
+	return num.Mod(num, P), nil
+}
+
 // ----- //
 // Gob helpers for if you choose to encode messages with Gob.
 
@@ -7,6 +7,7 @@
 package crypto_test
 
 import (
+	"crypto/elliptic"
 	"encoding/hex"
 	"encoding/json"
 	"math/big"
@@ -17,6 +18,7 @@ import (
 	"github.com/decred/dcrd/dcrec/edwards/v2"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/kisdex/mpc-lib/common"
 	. "github.com/kisdex/mpc-lib/crypto"
 	"github.com/kisdex/mpc-lib/tss"
 )
@@ -165,3 +167,78 @@ func TestEdwardsEcpointJsonSerialization(t *testing.T) {
 	assert.True(t, point.Equals(&umpoint))
 	assert.True(t, reflect.TypeOf(point.Curve()) == reflect.TypeOf(umpoint.Curve()))
 }
+
+func TestECPointBytes(t *testing.T) {
+	for _, ec := range []elliptic.Curve{tss.S256(), tss.Edwards(), tss.Edwards448(), tss.Ristretto(), elliptic.P256(), elliptic.P384(), tss.StarkCurve()} {
+		size := (ec.Params().BitSize + 7) / 8
+		for i := 0; i < 8; i++ {
+			point := ScalarBaseMult(ec, common.GetRandomPositiveInt(ec.Params().N))
+			bz := point.Bytes()
+			assert.Len(t, bz, 1+size, ec.Params().Name)
+			decoded, err := NewECPointFromBytes(ec, bz)
+			if assert.NoError(t, err, ec.Params().Name) {
+				assert.True(t, point.Equals(decoded), ec.Params().Name)
+			}
+
+			uncompressed := append([]byte{0x04}, append(point.X().FillBytes(make([]byte, size)), point.Y().FillBytes(make([]byte, size))...)...)
+			decoded, err = NewECPointFromBytes(ec, uncompressed)
+			if assert.NoError(t, err, ec.Params().Name) {
+				assert.True(t, point.Equals(decoded), ec.Params().Name)
+			}
+
+			flipped := append([]byte{bz[0] ^ 1}, bz[1:]...)
+			decoded, err = NewECPointFromBytes(ec, flipped)
+			if assert.NoError(t, err, ec.Params().Name) {
+				assert.False(t, point.Equals(decoded), "the prefix must select y")
+			}
+			_, err = NewECPointFromBytes(ec, bz[1:])
+			assert.Error(t, err)
+		}
+	}
+
+	// the encoding of the NIST curves and of secp256k1 is the standard one
+	point := ScalarBaseMult(elliptic.P256(), big.NewInt(42))
+	assert.Equal(t, elliptic.MarshalCompressed(elliptic.P256(), point.X(), point.Y()), point.Bytes())
+	pk, err := btcec.ParsePubKey(ScalarBaseMult(tss.S256(), big.NewInt(42)).Bytes())
+	if assert.NoError(t, err) {
+		assert.Equal(t, 0, pk.X().Cmp(ScalarBaseMult(tss.S256(), big.NewInt(42)).X()))
+	}
+
+	points := []*ECPoint{ScalarBaseMult(tss.Edwards(), big.NewInt(1)), ScalarBaseMult(tss.Edwards(), big.NewInt(2))}
+	compressed, err := CompressECPoints(points)
+	assert.NoError(t, err)
+	decompressed, err := DecompressECPoints(tss.Edwards(), compressed)
+	if assert.NoError(t, err) {
+		assert.True(t, points[0].Equals(decompressed[0]))
+		assert.True(t, points[1].Equals(decompressed[1]))
+	}
+}
+
+func TestValidatePoints(t *testing.T) {
+	for _, ec := range []elliptic.Curve{tss.S256(), tss.Edwards(), tss.Edwards448(), tss.Ristretto(), elliptic.P256(), elliptic.P384(), tss.StarkCurve()} {
+		point := ScalarBaseMult(ec, big.NewInt(42))
+		assert.False(t, point.IsIdentity(), ec.Params().Name)
+		assert.True(t, ValidatePoints(point, ScalarBaseMult(ec, big.NewInt(7))), ec.Params().Name)
+
+		neg := ScalarBaseMult(ec, new(big.Int).Sub(ec.Params().N, big.NewInt(42)))
+		x, y := ec.Add(point.X(), point.Y(), neg.X(), neg.Y())
+		identity := NewECPointNoCurveCheck(ec, x, y)
+		assert.True(t, identity.IsIdentity(), ec.Params().Name)
+		assert.False(t, ValidatePoints(point, identity), "the identity must be rejected on %s", ec.Params().Name)
+	}
+	assert.False(t, ValidatePoints(nil))
+	assert.False(t, ValidatePoints(NewECPointNoCurveCheck(tss.S256(), big.NewInt(1), big.NewInt(1))), "a point off the curve must be rejected")
+}
+
+func TestScalarMultNegative(t *testing.T) {
+	ec := tss.S256()
+	N := ec.Params().N
+	k := common.GetRandomPositiveInt(N)
+	negK := new(big.Int).Neg(k)
+	kG, negKG := ScalarBaseMult(ec, k), ScalarBaseMult(ec, negK)
+	assert.True(t, negKG.Equals(ScalarBaseMult(ec, new(big.Int).Sub(N, k))), "-k must be reduced modulo N")
+	assert.Equal(t, 0, kG.Y().Cmp(new(big.Int).Sub(ec.Params().P, negKG.Y())), "-kG must be the negation of kG")
+
+	P := ScalarBaseMult(ec, big.NewInt(42))
+	assert.True(t, P.ScalarMult(negK).Equals(P.ScalarMult(new(big.Int).Sub(N, k))), "-k must be reduced modulo N")
+}
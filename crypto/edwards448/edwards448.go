@@ -0,0 +1,267 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package edwards448 implements edwards448, the curve of Ed448 (RFC 8032), as an elliptic.Curve over big.Int, with the
+// encoding of its points and scalars and the verification of pure Ed448 signatures. Like the Ed25519 curve of
+// dcrd/dcrec/edwards it is not constant time; it serves the threshold protocols, whose secrets are shares.
+package edwards448
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"errors"
+	"math/big"
+
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	// PointSize is the size of an encoded point, and ScalarSize that of an encoded scalar
+	PointSize  = 57
+	ScalarSize = 57
+	// SignatureSize is the size of a signature R || S
+	SignatureSize = PointSize + ScalarSize
+)
+
+// Curve is edwards448, x^2 + y^2 = 1 + d*x^2*y^2 with d = -39081 over GF(2^448 - 2^224 - 1). Its identity (0, 1) is
+// a point of the curve; Add and ScalarMult return it like any other point.
+type Curve struct {
+	params *elliptic.CurveParams
+	d      *big.Int
+}
+
+var (
+	one = big.NewInt(1)
+	two = big.NewInt(2)
+
+	edwards448 = newCurve()
+
+	// dom4(0, "") of RFC 8032 section 5.2, the prefix of the hash of pure Ed448 with an empty context
+	dom4 = []byte("SigEd448\x00\x00")
+)
+
+func newCurve() *Curve {
+	p := new(big.Int).Lsh(one, 448)
+	p.Sub(p, new(big.Int).Lsh(one, 224)).Sub(p, one)
+	n, _ := new(big.Int).SetString("3fffffffffffffffffffffffffffffffffffffffffffffffffffffff7cca23e9c44edb49aed63690216cc2728dc58f552378c292ab5844f3", 16)
+	gx, _ := new(big.Int).SetString("224580040295924300187604334099896036246789641632564134246125461686950415467406032909029192869357953282578032075146446173674602635247710", 10)
+	gy, _ := new(big.Int).SetString("298819210078481492676017930443930673437544040154080242095928241372331506189835876003536878655418784733982303233503462500531545062832660", 10)
+	d := new(big.Int).Sub(p, big.NewInt(39081))
+	return &Curve{
+		params: &elliptic.CurveParams{P: p, N: n, B: d, Gx: gx, Gy: gy, BitSize: 448, Name: "edwards448"},
+		d:      d,
+	}
+}
+
+// Edwards448 returns the curve of Ed448
+func Edwards448() *Curve {
+	return edwards448
+}
+
+func (curve *Curve) Params() *elliptic.CurveParams {
+	return curve.params
+}
+
+func (curve *Curve) IsOnCurve(x, y *big.Int) bool {
+	p := curve.params.P
+	if x.Sign() < 0 || x.Cmp(p) >= 0 || y.Sign() < 0 || y.Cmp(p) >= 0 {
+		return false
+	}
+	x2, y2 := curve.mul(x, x), curve.mul(y, y)
+	lhs := curve.add(x2, y2)
+	rhs := curve.add(one, curve.mul(curve.d, curve.mul(x2, y2)))
+	return lhs.Cmp(rhs) == 0
+}
+
+func (curve *Curve) Add(x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int) {
+	return curve.affine(curve.addProjective(curve.projective(x1, y1), curve.projective(x2, y2)))
+}
+
+func (curve *Curve) Double(x1, y1 *big.Int) (*big.Int, *big.Int) {
+	return curve.affine(curve.doubleProjective(curve.projective(x1, y1)))
+}
+
+// ScalarMult returns k*(x, y) for the big-endian k. The scalar is not reduced mod N, so that multiplying a point of
+// small order, e.g. to clear the cofactor, gives the right result.
+func (curve *Curve) ScalarMult(x, y *big.Int, k []byte) (*big.Int, *big.Int) {
+	point := curve.projective(x, y)
+	r := projectivePoint{new(big.Int), big.NewInt(1), big.NewInt(1)}
+	for _, b := range k {
+		for bit := 7; 0 <= bit; bit-- {
+			r = curve.doubleProjective(r)
+			if (b>>uint(bit))&1 == 1 {
+				r = curve.addProjective(r, point)
+			}
+		}
+	}
+	return curve.affine(r)
+}
+
+func (curve *Curve) ScalarBaseMult(k []byte) (*big.Int, *big.Int) {
+	return curve.ScalarMult(curve.params.Gx, curve.params.Gy, k)
+}
+
+// projectivePoint is (X:Y:Z) for the affine point (X/Z, Y/Z)
+type projectivePoint struct {
+	x, y, z *big.Int
+}
+
+func (curve *Curve) projective(x, y *big.Int) projectivePoint {
+	return projectivePoint{new(big.Int).Set(x), new(big.Int).Set(y), big.NewInt(1)}
+}
+
+func (curve *Curve) affine(point projectivePoint) (*big.Int, *big.Int) {
+	zInv := new(big.Int).ModInverse(point.z, curve.params.P)
+	return curve.mul(point.x, zInv), curve.mul(point.y, zInv)
+}
+
+// addProjective is the addition of RFC 8032 section 5.2.4, which is complete on edwards448
+func (curve *Curve) addProjective(p1, p2 projectivePoint) projectivePoint {
+	a := curve.mul(p1.z, p2.z)
+	b := curve.mul(a, a)
+	c := curve.mul(p1.x, p2.x)
+	d := curve.mul(p1.y, p2.y)
+	e := curve.mul(curve.d, curve.mul(c, d))
+	f := curve.sub(b, e)
+	g := curve.add(b, e)
+	h := curve.mul(curve.add(p1.x, p1.y), curve.add(p2.x, p2.y))
+	return projectivePoint{
+		x: curve.mul(a, curve.mul(f, curve.sub(h, curve.add(c, d)))),
+		y: curve.mul(a, curve.mul(g, curve.sub(d, c))),
+		z: curve.mul(f, g),
+	}
+}
+
+// doubleProjective is the doubling of RFC 8032 section 5.2.4
+func (curve *Curve) doubleProjective(p1 projectivePoint) projectivePoint {
+	xPlusY := curve.add(p1.x, p1.y)
+	b := curve.mul(xPlusY, xPlusY)
+	c := curve.mul(p1.x, p1.x)
+	d := curve.mul(p1.y, p1.y)
+	e := curve.add(c, d)
+	h := curve.mul(p1.z, p1.z)
+	j := curve.sub(e, curve.mul(two, h))
+	return projectivePoint{
+		x: curve.mul(curve.sub(b, e), j),
+		y: curve.mul(e, curve.sub(c, d)),
+		z: curve.mul(e, j),
+	}
+}
+
+func (curve *Curve) add(a, b *big.Int) *big.Int {
+	return new(big.Int).Mod(new(big.Int).Add(a, b), curve.params.P)
+}
+
+func (curve *Curve) sub(a, b *big.Int) *big.Int {
+	return new(big.Int).Mod(new(big.Int).Sub(a, b), curve.params.P)
+}
+
+func (curve *Curve) mul(a, b *big.Int) *big.Int {
+	return new(big.Int).Mod(new(big.Int).Mul(a, b), curve.params.P)
+}
+
+// ----- //
+
+// EncodePoint returns the 57-byte encoding of RFC 8032 section 5.2.2: y in little-endian with the low bit of x in the
+// top bit of the last byte
+func EncodePoint(x, y *big.Int) []byte {
+	bz := EncodeScalar(y)
+	bz[PointSize-1] |= byte(x.Bit(0)) << 7
+	return bz
+}
+
+// DecodePoint decodes a point encoded with EncodePoint, rejecting a y out of the field or an x that does not exist
+func DecodePoint(bz []byte) (x, y *big.Int, err error) {
+	if len(bz) != PointSize {
+		return nil, nil, errors.New("an edwards448 point must have 57 bytes")
+	}
+	curve := edwards448
+	p := curve.params.P
+	yBz := append([]byte{}, bz...)
+	xBit := uint(yBz[PointSize-1] >> 7)
+	yBz[PointSize-1] &= 0x7f
+	y = DecodeScalar(yBz)
+	if y.Cmp(p) >= 0 {
+		return nil, nil, errors.New("the y of an edwards448 point must be below p")
+	}
+	// x^2 = (y^2 - 1) / (d*y^2 - 1), whose root is x^((p+1)/4) since p = 3 mod 4
+	y2 := curve.mul(y, y)
+	u, v := curve.sub(y2, one), curve.sub(curve.mul(curve.d, y2), one)
+	x2 := curve.mul(u, new(big.Int).ModInverse(v, p))
+	x = new(big.Int).Exp(x2, new(big.Int).Rsh(new(big.Int).Add(p, one), 2), p)
+	if curve.mul(x, x).Cmp(x2) != 0 {
+		return nil, nil, errors.New("the y of an edwards448 point has no x")
+	}
+	if x.Sign() == 0 && xBit == 1 {
+		return nil, nil, errors.New("an edwards448 point with x = 0 must have an even x")
+	}
+	if x.Bit(0) != xBit {
+		x.Sub(p, x)
+	}
+	return x, y, nil
+}
+
+// EncodeScalar returns the 57-byte little-endian encoding of a scalar below 2^448
+func EncodeScalar(s *big.Int) []byte {
+	bz := make([]byte, ScalarSize)
+	s.FillBytes(bz)
+	for i, j := 0, len(bz)-1; i < j; i, j = i+1, j-1 {
+		bz[i], bz[j] = bz[j], bz[i]
+	}
+	return bz
+}
+
+// DecodeScalar decodes a little-endian scalar
+func DecodeScalar(bz []byte) *big.Int {
+	be := make([]byte, len(bz))
+	for i := range bz {
+		be[len(bz)-1-i] = bz[i]
+	}
+	return new(big.Int).SetBytes(be)
+}
+
+// Challenge returns the challenge SHAKE256(dom4(0, "") || R || A || M, 114) mod N of pure Ed448 for the encoded R and
+// public key A
+func Challenge(encodedR, encodedPub, msg []byte) *big.Int {
+	h := sha3.NewShake256()
+	h.Write(dom4)
+	h.Write(encodedR)
+	h.Write(encodedPub)
+	h.Write(msg)
+	digest := make([]byte, 2*ScalarSize)
+	h.Read(digest)
+	return new(big.Int).Mod(DecodeScalar(digest), edwards448.params.N)
+}
+
+// Verify reports whether `sig` is a valid pure Ed448 signature of `msg` with an empty context under the encoded public
+// key `pub`, with the cofactored check [4][S]B = [4]R + [4][k]A of RFC 8032 section 5.2.7
+func Verify(pub, msg, sig []byte) bool {
+	if len(sig) != SignatureSize {
+		return false
+	}
+	curve := edwards448
+	Ax, Ay, err := DecodePoint(pub)
+	if err != nil {
+		return false
+	}
+	Rx, Ry, err := DecodePoint(sig[:PointSize])
+	if err != nil {
+		return false
+	}
+	s := DecodeScalar(sig[PointSize:])
+	if s.Cmp(curve.params.N) >= 0 {
+		return false
+	}
+	k := Challenge(sig[:PointSize], pub, msg)
+
+	four := []byte{4}
+	lhsX, lhsY := curve.ScalarBaseMult(s.Bytes())
+	lhsX, lhsY = curve.ScalarMult(lhsX, lhsY, four)
+	kAx, kAy := curve.ScalarMult(Ax, Ay, k.Bytes())
+	rhsX, rhsY := curve.Add(Rx, Ry, kAx, kAy)
+	rhsX, rhsY = curve.ScalarMult(rhsX, rhsY, four)
+	return bytes.Equal(EncodePoint(lhsX, lhsY), EncodePoint(rhsX, rhsY))
+}
@@ -0,0 +1,99 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package edwards448_test
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/sha3"
+
+	"github.com/kisdex/mpc-lib/common"
+	. "github.com/kisdex/mpc-lib/crypto/edwards448"
+)
+
+// the pure Ed448 vectors of RFC 8032 section 7.4
+var rfc8032Vectors = []struct {
+	name, sk, pk, msg, sig string
+}{
+	{
+		"blank",
+		"6c82a562cb808d10d632be89c8513ebf6c929f34ddfa8c9f63c9960ef6e348a3528c8a3fcc2f044e39a3fc5b94492f8f032e7549a20098f95b",
+		"5fd7449b59b461fd2ce787ec616ad46a1da1342485a70e1f8a0ea75d80e96778edf124769b46c7061bd6783df1e50f6cd1fa1abeafe8256180",
+		"",
+		"533a37f6bbe457251f023c0d88f976ae2dfb504a843e34d2074fd823d41a591f2b233f034f628281f2fd7a22ddd47d7828c59bd0a21bfd3980" +
+			"ff0d2028d4b18a9df63e006c5d1c2d345b925d8dc00b4104852db99ac5c7cdda8530a113a0f4dbb61149f05a7363268c71d95808ff2e652600",
+	},
+	{
+		"1 octet",
+		"c4eab05d357007c632f3dbb48489924d552b08fe0c353a0d4a1f00acda2c463afbea67c5e8d2877c5e3bc397a659949ef8021e954e0a12274e",
+		"43ba28f430cdff456ae531545f7ecd0ac834a55d9358c0372bfa0c6c6798c0866aea01eb00742802b8438ea4cb82169c235160627b4c3a9480",
+		"03",
+		"26b8f91727bd62897af15e41eb43c377efb9c610d48f2335cb0bd0087810f4352541b143c4b981b7e18f62de8ccdf633fc1bf037ab7cd77980" +
+			"5e0dbcc0aae1cbcee1afb2e027df36bc04dcecbf154336c19f0af7e0a6472905e799f1953d2a0ff3348ab21aa4adafd1d234441cf807c03a00",
+	},
+}
+
+func TestCurve(t *testing.T) {
+	ec := Edwards448()
+	params := ec.Params()
+	assert.True(t, ec.IsOnCurve(params.Gx, params.Gy), "the base point must be on the curve")
+	L, _ := new(big.Int).SetString("13818066809895115352007386748515426880336692474882178609894547503885", 10)
+	assert.Equal(t, 0, new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 446), L).Cmp(params.N), "N is the order of RFC 8032")
+
+	x, y := ec.ScalarBaseMult(params.N.Bytes())
+	assert.Equal(t, 0, x.Sign(), "N*G must be the identity")
+	assert.Equal(t, 0, y.Cmp(big.NewInt(1)), "N*G must be the identity")
+
+	a, b := common.GetRandomPositiveInt(params.N), common.GetRandomPositiveInt(params.N)
+	aX, aY := ec.ScalarBaseMult(a.Bytes())
+	bX, bY := ec.ScalarBaseMult(b.Bytes())
+	sumX, sumY := ec.Add(aX, aY, bX, bY)
+	abX, abY := ec.ScalarBaseMult(new(big.Int).Add(a, b).Bytes())
+	assert.True(t, ec.IsOnCurve(sumX, sumY))
+	assert.Equal(t, 0, sumX.Cmp(abX), "aG + bG must be (a+b)G")
+	assert.Equal(t, 0, sumY.Cmp(abY), "aG + bG must be (a+b)G")
+	dblX, dblY := ec.Double(aX, aY)
+	addX, addY := ec.Add(aX, aY, aX, aY)
+	assert.Equal(t, 0, dblX.Cmp(addX), "doubling must be adding a point to itself")
+	assert.Equal(t, 0, dblY.Cmp(addY), "doubling must be adding a point to itself")
+
+	decX, decY, err := DecodePoint(EncodePoint(aX, aY))
+	if assert.NoError(t, err) {
+		assert.Equal(t, 0, decX.Cmp(aX), "a point must decode to itself")
+		assert.Equal(t, 0, decY.Cmp(aY), "a point must decode to itself")
+	}
+	assert.False(t, ec.IsOnCurve(aX, new(big.Int).Add(aY, big.NewInt(1))))
+}
+
+func TestRFC8032(t *testing.T) {
+	ec := Edwards448()
+	for _, vector := range rfc8032Vectors {
+		sk, _ := hex.DecodeString(vector.sk)
+		pk, _ := hex.DecodeString(vector.pk)
+		msg, _ := hex.DecodeString(vector.msg)
+		sig, _ := hex.DecodeString(vector.sig)
+
+		// RFC 8032 section 5.2.5: the secret scalar is the pruned first half of SHAKE256(sk, 114)
+		h := make([]byte, 114)
+		sha3.ShakeSum256(h, sk)
+		h[0] &= 0xfc
+		h[55] |= 0x80
+		h[56] = 0
+		s := DecodeScalar(h[:ScalarSize])
+		x, y := ec.ScalarBaseMult(s.Bytes())
+		assert.Equal(t, pk, EncodePoint(x, y), "%s: the public key must match", vector.name)
+
+		assert.True(t, Verify(pk, msg, sig), "%s: the signature must verify", vector.name)
+		assert.False(t, Verify(pk, append(msg, 0), sig), "%s: the signature of another message must not verify", vector.name)
+		tampered := append([]byte{}, sig...)
+		tampered[SignatureSize-2] ^= 1
+		assert.False(t, Verify(pk, msg, tampered), "%s: a tampered signature must not verify", vector.name)
+	}
+}
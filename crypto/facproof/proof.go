@@ -17,6 +17,9 @@ import (
 
 const (
 	ProofFacBytesParts = 11
+
+	// domain separation string of the challenge, see common.SHA512_256i_DOMAIN
+	domain = "mpc-lib/facproof"
 )
 
 type (
@@ -31,7 +34,8 @@ var (
 	one            = big.NewInt(1)
 )
 
-// NewProof implements prooffac
+// NewProof implements prooffac, the CGG21 Πfac proof (Fig 28) that N0 = N0p * N0q has no factor smaller than
+// about 2^l * sqrt(N0), with commitments under the ring-Pedersen parameters (NCap, s, t) of the verifier
 func NewProof(Session []byte, ec elliptic.Curve, N0, NCap, s, t, N0p, N0q *big.Int) (*ProofFac, error) {
 	if ec == nil || N0 == nil || NCap == nil || s == nil || t == nil || N0p == nil || N0q == nil {
 		return nil, errors.New("ProveFac constructor received nil value(s)")
@@ -59,25 +63,25 @@ func NewProof(Session []byte, ec elliptic.Curve, N0, NCap, s, t, N0p, N0q *big.I
 
 	// Fig 28.1 compute
 	modNCap := common.ModInt(NCap)
-	P := modNCap.Exp(s, N0p)
-	P = modNCap.Mul(P, modNCap.Exp(t, mu))
+	P := modNCap.SecretExp(s, N0p, nil)
+	P = modNCap.Mul(P, modNCap.SecretExp(t, mu, nil))
 
-	Q := modNCap.Exp(s, N0q)
-	Q = modNCap.Mul(Q, modNCap.Exp(t, nu))
+	Q := modNCap.SecretExp(s, N0q, nil)
+	Q = modNCap.Mul(Q, modNCap.SecretExp(t, nu, nil))
 
-	A := modNCap.Exp(s, alpha)
-	A = modNCap.Mul(A, modNCap.Exp(t, x))
+	A := modNCap.SecretExp(s, alpha, nil)
+	A = modNCap.Mul(A, modNCap.SecretExp(t, x, nil))
 
-	B := modNCap.Exp(s, beta)
-	B = modNCap.Mul(B, modNCap.Exp(t, y))
+	B := modNCap.SecretExp(s, beta, nil)
+	B = modNCap.Mul(B, modNCap.SecretExp(t, y, nil))
 
-	T := modNCap.Exp(Q, alpha)
-	T = modNCap.Mul(T, modNCap.Exp(t, r))
+	T := modNCap.SecretExp(Q, alpha, nil)
+	T = modNCap.Mul(T, modNCap.SecretExp(t, r, nil))
 
 	// Fig 28.2 e
 	var e *big.Int
 	{
-		eHash := common.SHA512_256i_TAGGED(Session, N0, NCap, s, t, P, Q, A, B, T, sigma)
+		eHash := common.SHA512_256i_DOMAIN(domain, Session, append(common.CurveInts(ec), N0, NCap, s, t, P, Q, A, B, T, sigma)...)
 		e = common.RejectionSample(q, eHash)
 	}
 
@@ -125,9 +129,15 @@ func (pf *ProofFac) Verify(Session []byte, ec elliptic.Curve, N0, NCap, s, t *bi
 	if pf == nil || !pf.ValidateBasic() || ec == nil || N0 == nil || NCap == nil || s == nil || t == nil {
 		return false
 	}
-	if N0.Sign() != 1 {
+	if N0.Sign() != 1 || NCap.Sign() != 1 {
 		return false
 	}
+	// the commitments must be units mod NCap, or the equality checks below could hold trivially
+	for _, c := range []*big.Int{pf.P, pf.Q, pf.A, pf.B, pf.T} {
+		if !common.IsNumberInMultiplicativeGroup(NCap, c) {
+			return false
+		}
+	}
 
 	q := ec.Params().N
 	q3 := new(big.Int).Mul(q, q)
@@ -146,7 +156,7 @@ func (pf *ProofFac) Verify(Session []byte, ec elliptic.Curve, N0, NCap, s, t *bi
 
 	var e *big.Int
 	{
-		eHash := common.SHA512_256i_TAGGED(Session, N0, NCap, s, t, pf.P, pf.Q, pf.A, pf.B, pf.T, pf.Sigma)
+		eHash := common.SHA512_256i_DOMAIN(domain, Session, append(common.CurveInts(ec), N0, NCap, s, t, pf.P, pf.Q, pf.A, pf.B, pf.T, pf.Sigma)...)
 		e = common.RejectionSample(q, eHash)
 	}
 
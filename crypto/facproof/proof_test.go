@@ -53,3 +53,25 @@ func TestFac(test *testing.T) {
 	ok = proof.Verify(Session, ec, N0, NCap, s, t)
 	assert.True(test, ok, "proof must verify")
 }
+
+func TestFacBadCommitments(test *testing.T) {
+	ec := tss.EC()
+
+	N0p := common.GetRandomPrimeInt(testSafePrimeBits)
+	N0q := common.GetRandomPrimeInt(testSafePrimeBits)
+	N0 := new(big.Int).Mul(N0p, N0q)
+
+	primes := [2]*big.Int{common.GetRandomPrimeInt(testSafePrimeBits), common.GetRandomPrimeInt(testSafePrimeBits)}
+	NCap, s, t, err := crypto.GenerateNTildei(primes)
+	assert.NoError(test, err)
+	proof, err := NewProof(Session, ec, N0, NCap, s, t, N0p, N0q)
+	assert.NoError(test, err)
+
+	bad := *proof
+	bad.T = big.NewInt(0)
+	assert.False(test, bad.Verify(Session, ec, N0, NCap, s, t), "proof with T = 0 must not verify")
+
+	bad = *proof
+	bad.P = new(big.Int).Add(proof.P, NCap)
+	assert.False(test, bad.Verify(Session, ec, N0, NCap, s, t), "proof with P >= NCap must not verify")
+}
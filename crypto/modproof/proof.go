@@ -16,6 +16,9 @@ import (
 const (
 	Iterations         = 80
 	ProofModBytesParts = Iterations*2 + 3
+
+	// domain separation string of the challenges, see common.SHA512_256i_DOMAIN
+	domain = "mpc-lib/modproof"
 )
 
 var (
@@ -37,6 +40,8 @@ func isQuadraticResidue(X, N *big.Int) bool {
 	return big.Jacobi(X, N) == 1
 }
 
+// NewProof proves that N = PQ is a Paillier-Blum modulus, i.e. that gcd(N, phi(N)) = 1 and P, Q = 3 mod 4
+// (CGG21 Πmod, Fig 16)
 func NewProof(Session []byte, N, P, Q *big.Int) (*ProofMod, error) {
 	Phi := new(big.Int).Mul(new(big.Int).Sub(P, one), new(big.Int).Sub(Q, one))
 	// Fig 16.1
@@ -45,7 +50,7 @@ func NewProof(Session []byte, N, P, Q *big.Int) (*ProofMod, error) {
 	// Fig 16.2
 	Y := [Iterations]*big.Int{}
 	for i := range Y {
-		ei := common.SHA512_256i_TAGGED(Session, append([]*big.Int{W, N}, Y[:i]...)...)
+		ei := common.SHA512_256i_DOMAIN(domain, Session, append([]*big.Int{W, N}, Y[:i]...)...)
 		Y[i] = common.RejectionSample(N, ei)
 	}
 
@@ -74,8 +79,8 @@ func NewProof(Session []byte, N, P, Q *big.Int) (*ProofMod, error) {
 				Yi = modN.Mul(W, Yi)
 			}
 			if isQuadraticResidue(Yi, P) && isQuadraticResidue(Yi, Q) {
-				Xi := modN.Exp(Yi, expo)
-				Zi := modN.Exp(Y[i], invN)
+				Xi := modN.SecretExp(Yi, expo, Phi)
+				Zi := modN.SecretExp(Y[i], invN, Phi)
 				X[i], Z[i] = Xi, Zi
 				A.SetBit(A, i, uint(a))
 				B.SetBit(B, i, uint(b))
@@ -112,12 +117,16 @@ func NewProofFromBytes(bzs [][]byte) (*ProofMod, error) {
 	}, nil
 }
 
+// Verify checks the proof that N is a Paillier-Blum modulus for the given session
 func (pf *ProofMod) Verify(Session []byte, N *big.Int) bool {
 	if pf == nil || !pf.ValidateBasic() {
 		return false
 	}
-	// TODO: add basic properties checker
-	if isQuadraticResidue(pf.W, N) {
+	// Fig 16. Verification: N must be an odd composite, and w must have Jacobi symbol -1 mod N
+	if N.Sign() != 1 || N.Bit(0) == 0 || N.ProbablyPrime(30) {
+		return false
+	}
+	if big.Jacobi(pf.W, N) != -1 {
 		return false
 	}
 	if pf.W.Sign() != 1 || pf.W.Cmp(N) != -1 {
@@ -143,17 +152,10 @@ func (pf *ProofMod) Verify(Session []byte, N *big.Int) bool {
 	modN := common.ModInt(N)
 	Y := [Iterations]*big.Int{}
 	for i := range Y {
-		ei := common.SHA512_256i_TAGGED(Session, append([]*big.Int{pf.W, N}, Y[:i]...)...)
+		ei := common.SHA512_256i_DOMAIN(domain, Session, append([]*big.Int{pf.W, N}, Y[:i]...)...)
 		Y[i] = common.RejectionSample(N, ei)
 	}
 
-	// Fig 16. Verification
-	{
-		if N.Bit(0) == 0 || N.ProbablyPrime(30) {
-			return false
-		}
-	}
-
 	chs := make(chan bool, Iterations*2)
 	for i := 0; i < Iterations; i++ {
 		go func(i int) {
@@ -7,6 +7,7 @@
 package modproof_test
 
 import (
+	"math/big"
 	"testing"
 	"time"
 
@@ -35,3 +36,19 @@ func TestMod(test *testing.T) {
 	ok := proof.Verify(Session, N)
 	assert.True(test, ok, "proof must verify")
 }
+
+func TestModBadW(test *testing.T) {
+	preParams, err := keygen.GeneratePreParams(time.Minute*10, 8)
+	assert.NoError(test, err)
+
+	P, Q, N := preParams.PaillierSK.P, preParams.PaillierSK.Q, preParams.PaillierSK.N
+
+	proof, err := NewProof(Session, N, P, Q)
+	assert.NoError(test, err)
+
+	// w sharing a factor with N has Jacobi symbol 0
+	proof.W = new(big.Int).Set(P)
+	assert.False(test, proof.Verify(Session, N), "proof with gcd(w, N) != 1 must not verify")
+
+	assert.False(test, proof.Verify(Session, P), "proof for a prime modulus must not verify")
+}
@@ -18,9 +18,16 @@ import (
 	"github.com/kisdex/mpc-lib/tss"
 )
 
+// Domain separation strings of the Fiat-Shamir challenges of the proofs, see common.SHA512_256i_DOMAIN
+const (
+	domainBob        = "mpc-lib/mta/bob"
+	domainBobWC      = "mpc-lib/mta/bob-wc"
+	domainRangeAlice = "mpc-lib/mta/range-alice"
+)
+
 const (
 	ProofBobBytesParts   = 10
-	ProofBobWCBytesParts = 12
+	ProofBobWCBytesParts = 11
 )
 
 type (
@@ -103,9 +110,9 @@ func ProveBobWC(Session []byte, ec elliptic.Curve, pk *paillier.PublicKey, NTild
 		var eHash *big.Int
 		// X is nil if called by ProveBob (Bob's proof "without check")
 		if X == nil {
-			eHash = common.SHA512_256i_TAGGED(Session, append(pk.AsInts(), c1, c2, z, zPrm, t, v, w)...)
+			eHash = common.SHA512_256i_DOMAIN(domainBob, Session, append(append(common.CurveInts(ec), pk.AsInts()...), c1, c2, z, zPrm, t, v, w)...)
 		} else {
-			eHash = common.SHA512_256i_TAGGED(Session, append(pk.AsInts(), X.X(), X.Y(), c1, c2, u.X(), u.Y(), z, zPrm, t, v, w)...)
+			eHash = common.SHA512_256i_DOMAIN(domainBobWC, Session, append(append(common.CurveInts(ec), pk.AsInts()...), X.X(), X.Y(), c1, c2, u.X(), u.Y(), z, zPrm, t, v, w)...)
 		}
 		e = common.RejectionSample(q, eHash)
 	}
@@ -154,9 +161,7 @@ func ProofBobWCFromBytes(ec elliptic.Curve, bzs [][]byte) (*ProofBobWC, error) {
 	if err != nil {
 		return nil, err
 	}
-	point, err := crypto.NewECPoint(ec,
-		new(big.Int).SetBytes(bzs[10]),
-		new(big.Int).SetBytes(bzs[11]))
+	point, err := crypto.NewECPointFromBytes(ec, bzs[10])
 	if err != nil {
 		return nil, err
 	}
@@ -200,51 +205,12 @@ func (pf *ProofBobWC) Verify(Session []byte, ec elliptic.Curve, pk *paillier.Pub
 	q7 := new(big.Int).Mul(q3, q3) // q^6
 	q7 = new(big.Int).Mul(q7, q)   // q^7
 
-	if !common.IsInInterval(pf.Z, NTilde) {
-		return false
-	}
-	if !common.IsInInterval(pf.ZPrm, NTilde) {
-		return false
-	}
-	if !common.IsInInterval(pf.T, NTilde) {
-		return false
-	}
-	if !common.IsInInterval(pf.V, pk.NSquare()) {
-		return false
-	}
-	if !common.IsInInterval(pf.W, NTilde) {
-		return false
-	}
-	if !common.IsInInterval(pf.S, pk.N) {
-		return false
-	}
-	if new(big.Int).GCD(nil, nil, pf.Z, NTilde).Cmp(one) != 0 {
-		return false
-	}
-	if new(big.Int).GCD(nil, nil, pf.ZPrm, NTilde).Cmp(one) != 0 {
-		return false
-	}
-	if new(big.Int).GCD(nil, nil, pf.T, NTilde).Cmp(one) != 0 {
-		return false
-	}
-	if new(big.Int).GCD(nil, nil, pf.V, pk.NSquare()).Cmp(one) != 0 {
-		return false
-	}
-	if new(big.Int).GCD(nil, nil, pf.W, NTilde).Cmp(one) != 0 {
-		return false
-	}
-
-	gcd := big.NewInt(0)
-	if pf.S.Cmp(zero) == 0 {
-		return false
-	}
-	if gcd.GCD(nil, nil, pf.S, pk.N).Cmp(one) != 0 {
-		return false
-	}
-	if pf.V.Cmp(zero) == 0 {
+	// the commitments must be in Z*_NTilde, the ciphertexts c1, c2 and v in Z*_{N^2}, and s in Z*_N
+	if !common.AreNumbersInMultiplicativeGroup(NTilde, pf.Z, pf.ZPrm, pf.T, pf.W) || !pk.ValidateCiphertexts(c1, c2, pf.V) ||
+		!common.IsNumberInMultiplicativeGroup(pk.N, pf.S) {
 		return false
 	}
-	if gcd.GCD(nil, nil, pf.V, pk.N).Cmp(one) != 0 {
+	if X != nil && !crypto.ValidatePoints(X, pf.U) {
 		return false
 	}
 	if pf.S1.Cmp(q) == -1 {
@@ -274,12 +240,12 @@ func (pf *ProofBobWC) Verify(Session []byte, ec elliptic.Curve, pk *paillier.Pub
 		var eHash *big.Int
 		// X is nil if called on a ProveBob (Bob's proof "without check")
 		if X == nil {
-			eHash = common.SHA512_256i_TAGGED(Session, append(pk.AsInts(), c1, c2, pf.Z, pf.ZPrm, pf.T, pf.V, pf.W)...)
+			eHash = common.SHA512_256i_DOMAIN(domainBob, Session, append(append(common.CurveInts(ec), pk.AsInts()...), c1, c2, pf.Z, pf.ZPrm, pf.T, pf.V, pf.W)...)
 		} else {
 			if !tss.SameCurve(ec, X.Curve()) {
 				return false
 			}
-			eHash = common.SHA512_256i_TAGGED(Session, append(pk.AsInts(), X.X(), X.Y(), c1, c2, pf.U.X(), pf.U.Y(), pf.Z, pf.ZPrm, pf.T, pf.V, pf.W)...)
+			eHash = common.SHA512_256i_DOMAIN(domainBobWC, Session, append(append(common.CurveInts(ec), pk.AsInts()...), X.X(), X.Y(), c1, c2, pf.U.X(), pf.U.Y(), pf.Z, pf.ZPrm, pf.T, pf.V, pf.W)...)
 		}
 		e = common.RejectionSample(q, eHash)
 	}
@@ -384,8 +350,7 @@ func (pf *ProofBobWC) Bytes() [ProofBobWCBytesParts][]byte {
 	var out [ProofBobWCBytesParts][]byte
 	bobBzs := pf.ProofBob.Bytes()
 	bobBzsSlice := bobBzs[:]
-	bobBzsSlice = append(bobBzsSlice, pf.U.X().Bytes())
-	bobBzsSlice = append(bobBzsSlice, pf.U.Y().Bytes())
-	copy(out[:], bobBzsSlice[:12])
+	bobBzsSlice = append(bobBzsSlice, pf.U.Bytes())
+	copy(out[:], bobBzsSlice[:ProofBobWCBytesParts])
 	return out
 }
@@ -42,11 +42,14 @@ func TestProveRangeAlice(t *testing.T) {
 	primes := [2]*big.Int{common.GetRandomPrimeInt(testSafePrimeBits), common.GetRandomPrimeInt(testSafePrimeBits)}
 	NTildei, h1i, h2i, err := crypto.GenerateNTildei(primes)
 	assert.NoError(t, err)
-	proof, err := ProveRangeAlice(tss.EC(), pk, c, NTildei, h1i, h2i, m, r)
+	proof, err := ProveRangeAlice(Session, tss.EC(), pk, c, NTildei, h1i, h2i, m, r)
 	assert.NoError(t, err)
 
-	ok := proof.Verify(tss.EC(), pk, NTildei, h1i, h2i, c)
+	ok := proof.Verify(Session, tss.EC(), pk, NTildei, h1i, h2i, c)
 	assert.True(t, ok, "proof must verify")
+
+	ok = proof.Verify([]byte("another session"), tss.EC(), pk, NTildei, h1i, h2i, c)
+	assert.False(t, ok, "proof must not verify in another session")
 }
 
 func TestProveRangeAliceBypassed(t *testing.T) {
@@ -65,10 +68,10 @@ func TestProveRangeAliceBypassed(t *testing.T) {
 	primes0 := [2]*big.Int{common.GetRandomPrimeInt(testSafePrimeBits), common.GetRandomPrimeInt(testSafePrimeBits)}
 	Ntildei0, h1i0, h2i0, err := crypto.GenerateNTildei(primes0)
 	assert.NoError(t, err)
-	proof0, err := ProveRangeAlice(tss.EC(), pk0, c0, Ntildei0, h1i0, h2i0, m0, r0)
+	proof0, err := ProveRangeAlice(Session, tss.EC(), pk0, c0, Ntildei0, h1i0, h2i0, m0, r0)
 	assert.NoError(t, err)
 
-	ok0 := proof0.Verify(tss.EC(), pk0, Ntildei0, h1i0, h2i0, c0)
+	ok0 := proof0.Verify(Session, tss.EC(), pk0, Ntildei0, h1i0, h2i0, c0)
 	assert.True(t, ok0, "proof must verify")
 
 	//proof 2
@@ -82,16 +85,16 @@ func TestProveRangeAliceBypassed(t *testing.T) {
 	primes1 := [2]*big.Int{common.GetRandomPrimeInt(testSafePrimeBits), common.GetRandomPrimeInt(testSafePrimeBits)}
 	Ntildei1, h1i1, h2i1, err := crypto.GenerateNTildei(primes1)
 	assert.NoError(t, err)
-	proof1, err := ProveRangeAlice(tss.EC(), pk1, c1, Ntildei1, h1i1, h2i1, m1, r1)
+	proof1, err := ProveRangeAlice(Session, tss.EC(), pk1, c1, Ntildei1, h1i1, h2i1, m1, r1)
 	assert.NoError(t, err)
 
-	ok1 := proof1.Verify(tss.EC(), pk1, Ntildei1, h1i1, h2i1, c1)
+	ok1 := proof1.Verify(Session, tss.EC(), pk1, Ntildei1, h1i1, h2i1, c1)
 	assert.True(t, ok1, "proof must verify")
 
-	cross0 := proof0.Verify(tss.EC(), pk1, Ntildei1, h1i1, h2i1, c1)
+	cross0 := proof0.Verify(Session, tss.EC(), pk1, Ntildei1, h1i1, h2i1, c1)
 	assert.False(t, cross0, "proof must not verify")
 
-	cross1 := proof1.Verify(tss.EC(), pk0, Ntildei0, h1i0, h2i0, c0)
+	cross1 := proof1.Verify(Session, tss.EC(), pk0, Ntildei0, h1i0, h2i0, c0)
 	assert.False(t, cross1, "proof must not verify")
 
 	fmt.Println("Did verify proof 0 with data from 0?", ok0)
@@ -111,10 +114,10 @@ func TestProveRangeAliceBypassed(t *testing.T) {
 	}
 
 	cBogus := big.NewInt(1)
-	proofBogus, _ := ProveRangeAlice(tss.EC(), pk1, cBogus, Ntildei1, h1i1, h2i1, m1, r1)
+	proofBogus, _ := ProveRangeAlice(Session, tss.EC(), pk1, cBogus, Ntildei1, h1i1, h2i1, m1, r1)
 
-	ok2 := proofBogus.Verify(tss.EC(), pk1, Ntildei1, h1i1, h2i1, cBogus)
-	bypassresult3 := bypassedproofNew.Verify(tss.EC(), pk1, Ntildei1, h1i1, h2i1, cBogus)
+	ok2 := proofBogus.Verify(Session, tss.EC(), pk1, Ntildei1, h1i1, h2i1, cBogus)
+	bypassresult3 := bypassedproofNew.Verify(Session, tss.EC(), pk1, Ntildei1, h1i1, h2i1, cBogus)
 
 	//c = 1 is not valid, even though we can find a range proof for it that passes!
 	//this also means that the homo mul and add needs to be checked with this!
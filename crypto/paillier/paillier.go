@@ -45,6 +45,9 @@ const (
 type (
 	PublicKey struct {
 		N *big.Int
+
+		nSquare     *big.Int        // set by NewPublicKey
+		randomizers *RandomizerPool // set by StartRandomizerPool
 	}
 
 	PrivateKey struct {
@@ -54,10 +57,21 @@ type (
 		P, Q *big.Int
 	}
 
+	// Decrypter performs the operations of a Paillier private key. It allows signing with a key that is kept in an
+	// HSM or another device that does not export it, e.g. behind a vendor mechanism of a PKCS#11 token.
+	// *PrivateKey implements it.
+	Decrypter interface {
+		Public() *PublicKey
+		Decrypt(c *big.Int) (m *big.Int, err error)
+		DecryptFull(c *big.Int) (m *big.Int, rho *big.Int, err error)
+	}
+
 	// Proof uses the new GenerateXs method in GG18Spec (6)
 	Proof [ProofIters]*big.Int
 )
 
+var _ Decrypter = (*PrivateKey)(nil)
+
 var (
 	ErrMessageTooLong   = fmt.Errorf("the message is too large or < 0")
 	ErrMessageMalFormed = fmt.Errorf("the message is mal-formed")
@@ -72,6 +86,8 @@ func init() {
 }
 
 // len is the length of the modulus (each prime = len / 2)
+// The searches of the primes report their progress to the callback of common.WithSafePrimeProgress on `ctx`, if any;
+// each search starts over from no attempts, since a pair of primes that are too close is drawn again.
 func GenerateKeyPair(ctx context.Context, modulusBitLen int, optionalConcurrency ...int) (privateKey *PrivateKey, publicKey *PublicKey, err error) {
 	var concurrency int
 	if 0 < len(optionalConcurrency) {
@@ -109,44 +125,52 @@ func GenerateKeyPair(ctx context.Context, modulusBitLen int, optionalConcurrency
 	gcd := new(big.Int).GCD(nil, nil, PMinus1, QMinus1)
 	lambdaN := new(big.Int).Div(phiN, gcd)
 
-	publicKey = &PublicKey{N: N}
+	publicKey = NewPublicKey(N)
 	privateKey = &PrivateKey{PublicKey: *publicKey, LambdaN: lambdaN, PhiN: phiN, P: P, Q: Q}
 	return
 }
 
 // ----- //
 
+// NewPublicKey returns the public key of modulus N with N^2 computed once, for a key that is used many times.
+// N must not be changed afterwards.
+func NewPublicKey(N *big.Int) *PublicKey {
+	return &PublicKey{N: N, nSquare: new(big.Int).Mul(N, N)}
+}
+
 func (publicKey *PublicKey) EncryptWithRandomness(m *big.Int, x *big.Int) (c *big.Int, err error) {
 	if m.Cmp(zero) == -1 || m.Cmp(publicKey.N) != -1 { // m < 0 || m >= N ?
 		return nil, ErrMessageTooLong
 	}
-	N2 := publicKey.NSquare()
-	// 1. gamma^m mod N2
-	Gm := new(big.Int).Exp(publicKey.Gamma(), m, N2)
-	// 2. x^N mod N2
-	xN := new(big.Int).Exp(x, publicKey.N, N2)
-	// 3. (1) * (2) mod N2
-	c = common.ModInt(N2).Mul(Gm, xN)
+	c = publicKey.EncryptWithRandomnessNoErrChk(m, x)
 	return
 }
 
 func (publicKey *PublicKey) EncryptWithRandomnessNoErrChk(m *big.Int, x *big.Int) (c *big.Int) {
-	N2 := publicKey.NSquare()
-	// 1. gamma^m mod N2
-	Gm := new(big.Int).Exp(publicKey.Gamma(), m, N2)
-	// 2. x^N mod N2
-	xN := new(big.Int).Exp(x, publicKey.N, N2)
-	// 3. (1) * (2) mod N2
-	c = common.ModInt(N2).Mul(Gm, xN)
-	return
+	// x^N mod N2
+	xN := new(big.Int).Exp(x, publicKey.N, publicKey.NSquare())
+	return publicKey.encrypt(m, xN)
 }
 
 func (publicKey *PublicKey) EncryptAndReturnRandomness(m *big.Int) (c *big.Int, x *big.Int, err error) {
-	x = common.GetRandomPositiveRelativelyPrimeInt(publicKey.N)
-	c, err = publicKey.EncryptWithRandomness(m, x)
+	if m.Cmp(zero) == -1 || m.Cmp(publicKey.N) != -1 { // m < 0 || m >= N ?
+		return nil, nil, ErrMessageTooLong
+	}
+	x, xN := publicKey.randomizer()
+	c = publicKey.encrypt(m, xN)
 	return
 }
 
+// encrypt returns gamma^m * xN mod N2 for the randomizer xN = x^N mod N2
+func (publicKey *PublicKey) encrypt(m, xN *big.Int) *big.Int {
+	N2 := publicKey.NSquare()
+	// 1. gamma^m = (1 + N)^m = 1 + m*N mod N2
+	Gm := new(big.Int).Mul(m, publicKey.N)
+	Gm.Add(Gm, one).Mod(Gm, N2)
+	// 2. (1) * xN mod N2
+	return common.ModInt(N2).Mul(Gm, xN)
+}
+
 func (publicKey *PublicKey) Encrypt(m *big.Int) (c *big.Int, err error) {
 	c, _, err = publicKey.EncryptAndReturnRandomness(m)
 	return
@@ -160,8 +184,8 @@ func (publicKey *PublicKey) HomoMult(m, c1 *big.Int) (*big.Int, error) {
 	if c1.Cmp(zero) == -1 || c1.Cmp(N2) != -1 { // c1 < 0 || c1 >= N2 ?
 		return nil, ErrMessageTooLong
 	}
-	// cipher^m mod N2
-	return common.ModInt(N2).Exp(c1, m), nil
+	// cipher^m mod N2, m is usually a secret share
+	return common.ModInt(N2).SecretExp(c1, m, nil), nil
 }
 
 func (publicKey *PublicKey) HomoMultAndReturnRandomness(m, c1 *big.Int) (product *big.Int, x *big.Int, err error) {
@@ -171,8 +195,7 @@ func (publicKey *PublicKey) HomoMultAndReturnRandomness(m, c1 *big.Int) (product
 	}
 	N2 := publicKey.NSquare()
 	// 2. x^N mod N2
-	x = common.GetRandomPositiveRelativelyPrimeInt(publicKey.N)
-	xN := common.ModInt(N2).Exp(x, publicKey.N)
+	x, xN := publicKey.randomizer()
 	// 3. (ciphertext) * (2) mod N2
 	product = common.ModInt(N2).Mul(ciphertext, xN)
 	return
@@ -210,10 +233,23 @@ func (publicKey *PublicKey) HomoAddInt(m, c1 *big.Int) (*big.Int, error) {
 	return publicKey.HomoAdd(c1, c2)
 }
 
+// NSquare returns N^2, which must not be modified
 func (publicKey *PublicKey) NSquare() *big.Int {
+	if publicKey.nSquare != nil {
+		return publicKey.nSquare
+	}
 	return new(big.Int).Mul(publicKey.N, publicKey.N)
 }
 
+// ValidateCiphertexts returns true if every one of `cs` is in Z*_{N^2}, as a ciphertext received from a peer must be
+// before it is decrypted or used in a homomorphic operation
+func (publicKey *PublicKey) ValidateCiphertexts(cs ...*big.Int) bool {
+	if publicKey == nil || publicKey.N == nil || publicKey.N.Sign() != 1 {
+		return false
+	}
+	return common.AreNumbersInMultiplicativeGroup(publicKey.NSquare(), cs...)
+}
+
 // AsInts returns the PublicKey serialised to a slice of *big.Int for hashing
 func (publicKey *PublicKey) AsInts() []*big.Int {
 	return []*big.Int{publicKey.N, publicKey.Gamma()}
@@ -226,6 +262,15 @@ func (publicKey *PublicKey) Gamma() *big.Int {
 
 // ----- //
 
+func (privateKey *PrivateKey) Public() *PublicKey {
+	return &privateKey.PublicKey
+}
+
+// Zeroize wipes the secret factors of the key from memory, after which it can no longer decrypt
+func (privateKey *PrivateKey) Zeroize() {
+	common.Zeroize(privateKey.LambdaN, privateKey.PhiN, privateKey.P, privateKey.Q)
+}
+
 func (privateKey *PrivateKey) Decrypt(c *big.Int) (m *big.Int, err error) {
 	N2 := privateKey.NSquare()
 	if c.Cmp(zero) == -1 || c.Cmp(N2) != -1 { // c < 0 || c >= N2 ?
@@ -235,10 +280,12 @@ func (privateKey *PrivateKey) Decrypt(c *big.Int) (m *big.Int, err error) {
 	if cg.Cmp(one) == 1 {
 		return nil, ErrMessageMalFormed
 	}
+	// the order of Z*_N2 blinds the secret exponent LambdaN
+	modN2, order := common.ModInt(N2), new(big.Int).Mul(privateKey.N, privateKey.PhiN)
 	// 1. L(u) = (c^LambdaN-1 mod N2) / N
-	Lc := L(new(big.Int).Exp(c, privateKey.LambdaN, N2), privateKey.N)
+	Lc := L(modN2.SecretExp(c, privateKey.LambdaN, order), privateKey.N)
 	// 2. L(u) = (Gamma^LambdaN-1 mod N2) / N
-	Lg := L(new(big.Int).Exp(privateKey.Gamma(), privateKey.LambdaN, N2), privateKey.N)
+	Lg := L(modN2.SecretExp(privateKey.Gamma(), privateKey.LambdaN, order), privateKey.N)
 	// 3. (1) * modInv(2) mod N
 	inv := new(big.Int).ModInverse(Lg, privateKey.N)
 	m = common.ModInt(privateKey.N).Mul(Lc, inv)
@@ -261,15 +308,16 @@ func (privateKey *PrivateKey) DecryptFull(c *big.Int) (m *big.Int, rho *big.Int,
 
 	// 4. (Paillier Step 2) c' = cg^(-m1) mod n
 	// 4. (This code) cprime = c * gamma^(-m) mod N
+	modN := common.ModInt(privateKey.N)
 	negM := new(big.Int).Neg(m)
-	gamma_exp_neg_m := new(big.Int).Exp(privateKey.Gamma(), negM, privateKey.N)
+	gamma_exp_neg_m := modN.SecretExp(privateKey.Gamma(), negM, privateKey.PhiN)
 	times_c := new(big.Int).Mul(c, gamma_exp_neg_m)
 	cprime := new(big.Int).Mod(times_c, privateKey.N)
 
 	// 5. (Paillier Step 3) m2 = c'^(n^{-1} mod lambda) mod n
 	// 5. (This code) rho = cprime^{N^-1 mod LambdaN} mod N
 	nInv := new(big.Int).ModInverse(privateKey.N, privateKey.LambdaN)
-	rho = new(big.Int).Exp(cprime, nInv, privateKey.N)
+	rho = modN.SecretExp(cprime, nInv, privateKey.PhiN)
 
 	return m, rho, nil
 }
@@ -284,9 +332,10 @@ func (privateKey *PrivateKey) Proof(k *big.Int, ecdsaPub *crypto2.ECPoint) Proof
 	var pi Proof
 	iters := ProofIters
 	xs := GenerateXs(iters, k, privateKey.N, ecdsaPub)
+	modN := common.ModInt(privateKey.N)
 	for i := 0; i < iters; i++ {
 		M := new(big.Int).ModInverse(privateKey.N, privateKey.PhiN)
-		pi[i] = new(big.Int).Exp(xs[i], M, privateKey.N)
+		pi[i] = modN.SecretExp(xs[i], M, privateKey.PhiN)
 	}
 	return pi
 }
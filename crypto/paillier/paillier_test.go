@@ -61,6 +61,22 @@ func TestEncrypt(t *testing.T) {
 	assert.NotZero(t, cipher)
 }
 
+func TestValidateCiphertexts(t *testing.T) {
+	setUp(t)
+	c, err := publicKey.Encrypt(big.NewInt(42))
+	assert.NoError(t, err)
+	assert.True(t, publicKey.ValidateCiphertexts(c))
+	assert.True(t, publicKey.ValidateCiphertexts())
+
+	N2 := publicKey.NSquare()
+	assert.False(t, publicKey.ValidateCiphertexts(c, new(big.Int).Add(c, N2)), "a ciphertext must be reduced mod N^2")
+	assert.False(t, publicKey.ValidateCiphertexts(big.NewInt(0)))
+	assert.False(t, publicKey.ValidateCiphertexts(new(big.Int).Neg(c)))
+	assert.False(t, publicKey.ValidateCiphertexts(new(big.Int).Mul(privateKey.P, big.NewInt(3))), "a ciphertext must be coprime to N")
+	assert.False(t, publicKey.ValidateCiphertexts(nil))
+	assert.False(t, (*PublicKey)(nil).ValidateCiphertexts(c))
+}
+
 func TestEncryptDecrypt(t *testing.T) {
 	setUp(t)
 	exp := big.NewInt(100)
@@ -220,3 +236,29 @@ func TestGenerateXs(t *testing.T) {
 		assert.True(t, common.IsNumberInMultiplicativeGroup(N, xi))
 	}
 }
+
+func TestEncryptBatch(t *testing.T) {
+	setUp(t)
+	pk := NewPublicKey(publicKey.N)
+	pool := pk.StartRandomizerPool(context.Background(), 4, 2)
+	defer pool.Stop()
+	for pool.Len() < 4 {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	ms := make([]*big.Int, 10)
+	for i := range ms {
+		ms[i] = common.GetRandomPositiveInt(pk.N)
+	}
+	cs, xs, err := pk.EncryptBatch(ms)
+	assert.NoError(t, err)
+	for i, c := range cs {
+		m, rho, err := privateKey.DecryptFull(c)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, ms[i].Cmp(m))
+		assert.Equal(t, 0, xs[i].Cmp(rho))
+	}
+
+	_, _, err = pk.EncryptBatch([]*big.Int{big.NewInt(1), pk.N})
+	assert.Equal(t, ErrMessageTooLong, err)
+}
@@ -0,0 +1,111 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package paillier
+
+import (
+	"context"
+	"math/big"
+	"runtime"
+	"sync"
+
+	"github.com/kisdex/mpc-lib/common"
+)
+
+type (
+	// RandomizerPool computes the randomizers x^N mod N^2 of the encryptions under a public key in the background,
+	// so that an encryption with a ready randomizer costs a few multiplications instead of an exponentiation.
+	// An encryption that finds the pool empty computes its randomizer itself.
+	RandomizerPool struct {
+		ch     chan randomizer
+		cancel context.CancelFunc
+		wg     sync.WaitGroup
+	}
+
+	randomizer struct {
+		x, xN *big.Int
+	}
+)
+
+// StartRandomizerPool makes the encryptions under the key take their randomizers from a pool of up to `size` that is
+// filled by `workers` goroutines until `ctx` is done or the pool is stopped. It must be called before the key is used.
+func (publicKey *PublicKey) StartRandomizerPool(ctx context.Context, size, workers int) *RandomizerPool {
+	ctx, cancel := context.WithCancel(ctx)
+	pool := &RandomizerPool{
+		ch:     make(chan randomizer, size),
+		cancel: cancel,
+	}
+	N, N2 := publicKey.N, publicKey.NSquare()
+	pool.wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer pool.wg.Done()
+			for {
+				x := common.GetRandomPositiveRelativelyPrimeInt(N)
+				r := randomizer{x, new(big.Int).Exp(x, N, N2)}
+				select {
+				case pool.ch <- r:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	publicKey.randomizers = pool
+	return pool
+}
+
+// Len returns the number of randomizers that are ready
+func (pool *RandomizerPool) Len() int {
+	return len(pool.ch)
+}
+
+// Stop stops the goroutines of the pool; the encryptions then compute their randomizers once the pool is empty
+func (pool *RandomizerPool) Stop() {
+	pool.cancel()
+	pool.wg.Wait()
+}
+
+// randomizer returns a random x in Z*_N and x^N mod N^2, from the pool of the key if it has one ready
+func (publicKey *PublicKey) randomizer() (x, xN *big.Int) {
+	if publicKey.randomizers != nil {
+		select {
+		case r := <-publicKey.randomizers.ch:
+			return r.x, r.xN
+		default:
+		}
+	}
+	x = common.GetRandomPositiveRelativelyPrimeInt(publicKey.N)
+	return x, new(big.Int).Exp(x, publicKey.N, publicKey.NSquare())
+}
+
+// EncryptBatch encrypts `ms` concurrently and returns the ciphertexts with their randomness
+func (publicKey *PublicKey) EncryptBatch(ms []*big.Int) (cs []*big.Int, xs []*big.Int, err error) {
+	for _, m := range ms {
+		if m.Cmp(zero) == -1 || m.Cmp(publicKey.N) != -1 { // m < 0 || m >= N ?
+			return nil, nil, ErrMessageTooLong
+		}
+	}
+	cs, xs = make([]*big.Int, len(ms)), make([]*big.Int, len(ms))
+	next := make(chan int, len(ms))
+	for i := range ms {
+		next <- i
+	}
+	close(next)
+	wg := sync.WaitGroup{}
+	for w := min(runtime.GOMAXPROCS(0), len(ms)); w > 0; w-- {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				x, xN := publicKey.randomizer()
+				cs[i], xs[i] = publicKey.encrypt(ms[i], xN), x
+			}
+		}()
+	}
+	wg.Wait()
+	return cs, xs, nil
+}
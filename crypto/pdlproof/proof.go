@@ -0,0 +1,170 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Zero-knowledge proof that a Paillier ciphertext encrypts the discrete logarithm of an elliptic curve point
+
+// The prover shows that C = Enc(x; rho) under the Paillier key N and that X = x*G, with 0 <= x < q, where q is the
+// order of the curve. The challenges are binary, as in dlnproof, so that the proof needs no Ring-Pedersen parameters
+// from the verifier; it is used by the two-party ECDSA keygen of Lindell (2017) in place of the interactive
+// range and PDL proofs of the paper. The verifier learns that |x| < q * 2^(Slack+1).
+
+package pdlproof
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto"
+	"github.com/kisdex/mpc-lib/crypto/paillier"
+)
+
+const (
+	Iterations = 128
+
+	// Slack is the statistical security parameter of the masks of x
+	Slack = 128
+
+	ProofBytesParts = Iterations * 4
+
+	// domain separation string of the challenge, see common.SHA512_256i_DOMAIN
+	domain = "mpc-lib/pdlproof"
+)
+
+type (
+	Proof struct {
+		A [Iterations]*big.Int        // Enc(alpha; r)
+		Y [Iterations]*crypto.ECPoint // alpha*G
+		Z [Iterations]*big.Int        // alpha + e*x
+		W [Iterations]*big.Int        // r * rho^e mod N
+	}
+)
+
+// NewProof proves that C = Enc(x; rho) under `pk` and that X = x*G
+func NewProof(Session []byte, pk *paillier.PublicKey, C *big.Int, X *crypto.ECPoint, x, rho *big.Int) (*Proof, error) {
+	ec := X.Curve()
+	q := ec.Params().N
+	if x.Sign() < 0 || x.Cmp(q) >= 0 {
+		return nil, errors.New("pdlproof.NewProof: x must be in [0, q)")
+	}
+	alphaBound := new(big.Int).Lsh(q, Slack)
+	modN := common.ModInt(pk.N)
+	pf := new(Proof)
+	alpha, r := [Iterations]*big.Int{}, [Iterations]*big.Int{}
+	for i := range pf.A {
+		alpha[i] = common.GetRandomPositiveInt(alphaBound)
+		r[i] = common.GetRandomPositiveRelativelyPrimeInt(pk.N)
+		A, err := pk.EncryptWithRandomness(alpha[i], r[i])
+		if err != nil {
+			return nil, err
+		}
+		pf.A[i] = A
+		pf.Y[i] = crypto.ScalarBaseMult(ec, new(big.Int).Mod(alpha[i], q))
+	}
+	c := pf.challenge(Session, pk, C, X)
+	for i := range pf.Z {
+		if c.Bit(i) == 0 {
+			pf.Z[i], pf.W[i] = alpha[i], r[i]
+			continue
+		}
+		pf.Z[i] = new(big.Int).Add(alpha[i], x)
+		pf.W[i] = modN.Mul(r[i], rho)
+	}
+	return pf, nil
+}
+
+// Verify checks the proof that C encrypts the discrete logarithm of X under `pk`
+func (pf *Proof) Verify(Session []byte, pk *paillier.PublicKey, C *big.Int, X *crypto.ECPoint) bool {
+	if pf == nil || !pf.ValidateBasic() || pk == nil || pk.N.Sign() != 1 {
+		return false
+	}
+	if !pk.ValidateCiphertexts(append([]*big.Int{C}, pf.A[:]...)...) || !crypto.ValidatePoints(X) {
+		return false
+	}
+	ec := X.Curve()
+	q := ec.Params().N
+	// z = alpha + e*x < q*2^Slack + q for an honest prover
+	zBound := new(big.Int).Add(new(big.Int).Lsh(q, Slack), q)
+	// the plaintexts must not wrap around N for x to be extracted as an integer
+	if pk.N.Cmp(new(big.Int).Lsh(zBound, 1)) <= 0 {
+		return false
+	}
+	c := pf.challenge(Session, pk, C, X)
+	modNSquare := common.ModInt(pk.NSquare())
+	for i := range pf.Z {
+		if pf.Z[i].Cmp(zBound) >= 0 || pf.W[i].Sign() != 1 || pf.W[i].Cmp(pk.N) >= 0 || !crypto.ValidatePoints(pf.Y[i]) {
+			return false
+		}
+		// Enc(z; w) == A * C^e mod N^2
+		left, err := pk.EncryptWithRandomness(pf.Z[i], pf.W[i])
+		if err != nil {
+			return false
+		}
+		// z*G == Y + e*X
+		zG := crypto.ScalarBaseMult(ec, new(big.Int).Mod(pf.Z[i], q))
+		right, YeX := pf.A[i], pf.Y[i]
+		if c.Bit(i) == 1 {
+			right = modNSquare.Mul(right, C)
+			if YeX, err = YeX.Add(X); err != nil {
+				return false
+			}
+		}
+		if left.Cmp(right) != 0 || !zG.Equals(YeX) {
+			return false
+		}
+	}
+	return true
+}
+
+func (pf *Proof) challenge(Session []byte, pk *paillier.PublicKey, C *big.Int, X *crypto.ECPoint) *big.Int {
+	ec := X.Curve()
+	msg := append(common.CurveInts(ec), pk.N, C, X.X(), X.Y())
+	msg = append(msg, pf.A[:]...)
+	for _, Y := range pf.Y {
+		msg = append(msg, Y.X(), Y.Y())
+	}
+	return common.SHA512_256i_DOMAIN(domain, Session, msg...)
+}
+
+func (pf *Proof) ValidateBasic() bool {
+	for i := 0; i < Iterations; i++ {
+		if pf.A[i] == nil || pf.Y[i] == nil || pf.Z[i] == nil || pf.W[i] == nil {
+			return false
+		}
+	}
+	return true
+}
+
+func (pf *Proof) Bytes() [ProofBytesParts][]byte {
+	bzs := [ProofBytesParts][]byte{}
+	for i := 0; i < Iterations; i++ {
+		bzs[i] = pf.A[i].Bytes()
+		bzs[Iterations+i] = pf.Y[i].Bytes()
+		bzs[2*Iterations+i] = pf.Z[i].Bytes()
+		bzs[3*Iterations+i] = pf.W[i].Bytes()
+	}
+	return bzs
+}
+
+func NewProofFromBytes(ec elliptic.Curve, bzs [][]byte) (*Proof, error) {
+	if !common.NonEmptyMultiBytes(bzs, ProofBytesParts) {
+		return nil, fmt.Errorf("expected %d byte parts to construct a pdlproof.Proof", ProofBytesParts)
+	}
+	pf := new(Proof)
+	for i := 0; i < Iterations; i++ {
+		Y, err := crypto.NewECPointFromBytes(ec, bzs[Iterations+i])
+		if err != nil {
+			return nil, err
+		}
+		pf.A[i] = new(big.Int).SetBytes(bzs[i])
+		pf.Y[i] = Y
+		pf.Z[i] = new(big.Int).SetBytes(bzs[2*Iterations+i])
+		pf.W[i] = new(big.Int).SetBytes(bzs[3*Iterations+i])
+	}
+	return pf, nil
+}
@@ -0,0 +1,62 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package pdlproof_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto"
+	"github.com/kisdex/mpc-lib/crypto/paillier"
+	. "github.com/kisdex/mpc-lib/crypto/pdlproof"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+const (
+	testPaillierKeyLength = 1024
+)
+
+var (
+	Session = []byte("session")
+)
+
+func TestPDL(test *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+	_, pk, err := paillier.GenerateKeyPair(ctx, testPaillierKeyLength)
+	assert.NoError(test, err)
+
+	ec := tss.S256()
+	x := common.GetRandomPositiveInt(ec.Params().N)
+	X := crypto.ScalarBaseMult(ec, x)
+	C, rho, err := pk.EncryptAndReturnRandomness(x)
+	assert.NoError(test, err)
+
+	proof, err := NewProof(Session, pk, C, X, x, rho)
+	assert.NoError(test, err)
+
+	proofBzs := proof.Bytes()
+	proof, err = NewProofFromBytes(ec, proofBzs[:])
+	assert.NoError(test, err)
+	assert.True(test, proof.Verify(Session, pk, C, X), "proof must verify")
+
+	assert.False(test, proof.Verify([]byte("other session"), pk, C, X), "proof must not verify in another session")
+	X2 := crypto.ScalarBaseMult(ec, new(big.Int).Add(x, big.NewInt(1)))
+	assert.False(test, proof.Verify(Session, pk, C, X2), "proof must not verify for another point")
+
+	// a ciphertext of x + 1 under the point of x
+	C2, rho2, err := pk.EncryptAndReturnRandomness(new(big.Int).Add(x, big.NewInt(1)))
+	assert.NoError(test, err)
+	proof, err = NewProof(Session, pk, C2, X, x, rho2)
+	assert.NoError(test, err)
+	assert.False(test, proof.Verify(Session, pk, C2, X), "proof must not verify for a ciphertext of another value")
+}
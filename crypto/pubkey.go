@@ -0,0 +1,138 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package crypto
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+var (
+	oidPublicKeyECDSA = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+
+	// the named curves of RFC 5480 and SEC 2, by the name of their parameters
+	oidNamedCurves = map[string]asn1.ObjectIdentifier{
+		"P-224":     {1, 3, 132, 0, 33},
+		"P-256":     {1, 2, 840, 10045, 3, 1, 7},
+		"P-384":     {1, 3, 132, 0, 34},
+		"P-521":     {1, 3, 132, 0, 35},
+		"secp256k1": {1, 3, 132, 0, 10},
+	}
+)
+
+// subjectPublicKeyInfo is the DER structure of a public key of RFC 5280
+type subjectPublicKeyInfo struct {
+	Algorithm pkix.AlgorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+func (p *ECPoint) isEd25519() bool {
+	return tss.SameCurve(p.curve, tss.Edwards())
+}
+
+func (p *ECPoint) fieldBytes() int {
+	return (p.curve.Params().BitSize + 7) / 8
+}
+
+// MarshalCompressed returns the compressed SEC1 encoding of the point, or the 32-byte RFC 8032 encoding of a point of
+// Ed25519
+func (p *ECPoint) MarshalCompressed() ([]byte, error) {
+	if !p.ValidateBasic() {
+		return nil, fmt.Errorf("MarshalCompressed: the point is not on its curve")
+	}
+	if p.isEd25519() {
+		return edwards.NewPublicKey(p.X(), p.Y()).Serialize(), nil
+	}
+	out := make([]byte, 1+p.fieldBytes())
+	out[0] = byte(2 + p.Y().Bit(0))
+	p.X().FillBytes(out[1:])
+	return out, nil
+}
+
+// marshalUncompressed returns the uncompressed SEC1 encoding of the point
+func (p *ECPoint) marshalUncompressed() []byte {
+	size := p.fieldBytes()
+	out := make([]byte, 1+2*size)
+	out[0] = 4
+	p.X().FillBytes(out[1 : 1+size])
+	p.Y().FillBytes(out[1+size:])
+	return out
+}
+
+// MarshalPKIX returns the DER SubjectPublicKeyInfo of the point as a public key, as x509.MarshalPKIXPublicKey does; it
+// also supports secp256k1, which crypto/x509 does not
+func (p *ECPoint) MarshalPKIX() ([]byte, error) {
+	if !p.ValidateBasic() {
+		return nil, fmt.Errorf("MarshalPKIX: the point is not on its curve")
+	}
+	if p.isEd25519() {
+		pk, err := p.MarshalCompressed()
+		if err != nil {
+			return nil, err
+		}
+		return x509.MarshalPKIXPublicKey(ed25519.PublicKey(pk))
+	}
+	oid, ok := oidNamedCurves[p.curve.Params().Name]
+	if !ok {
+		return nil, fmt.Errorf("MarshalPKIX: no object identifier for the curve %q", p.curve.Params().Name)
+	}
+	params, err := asn1.Marshal(oid)
+	if err != nil {
+		return nil, err
+	}
+	point := p.marshalUncompressed()
+	return asn1.Marshal(subjectPublicKeyInfo{
+		Algorithm: pkix.AlgorithmIdentifier{Algorithm: oidPublicKeyECDSA, Parameters: asn1.RawValue{FullBytes: params}},
+		PublicKey: asn1.BitString{Bytes: point, BitLength: 8 * len(point)},
+	})
+}
+
+// MarshalPEM returns the PEM "PUBLIC KEY" block of MarshalPKIX
+func (p *ECPoint) MarshalPEM() ([]byte, error) {
+	der, err := p.MarshalPKIX()
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+// MarshalJWK returns the JSON Web Key of the point as a public key: an "EC" key of RFC 7518 and RFC 8812 (secp256k1),
+// or an "OKP" key of RFC 8037 for Ed25519
+func (p *ECPoint) MarshalJWK() ([]byte, error) {
+	if !p.ValidateBasic() {
+		return nil, fmt.Errorf("MarshalJWK: the point is not on its curve")
+	}
+	b64 := base64.RawURLEncoding.EncodeToString
+	if p.isEd25519() {
+		pk, err := p.MarshalCompressed()
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(map[string]string{"kty": "OKP", "crv": "Ed25519", "x": b64(pk)})
+	}
+	name := p.curve.Params().Name
+	if _, ok := oidNamedCurves[name]; !ok || name == "P-224" {
+		return nil, fmt.Errorf("MarshalJWK: no JWK name for the curve %q", name)
+	}
+	size := p.fieldBytes()
+	return json.Marshal(map[string]string{
+		"kty": "EC",
+		"crv": name,
+		"x":   b64(p.X().FillBytes(make([]byte, size))),
+		"y":   b64(p.Y().FillBytes(make([]byte, size))),
+	})
+}
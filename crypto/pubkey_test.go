@@ -0,0 +1,133 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package crypto_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/common"
+	. "github.com/kisdex/mpc-lib/crypto"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+func TestMarshalPublicKeyP256(t *testing.T) {
+	ec := elliptic.P256()
+	pub := ScalarBaseMult(ec, common.GetRandomPositiveInt(ec.Params().N))
+
+	compressed, err := pub.MarshalCompressed()
+	assert.NoError(t, err)
+	assert.Equal(t, elliptic.MarshalCompressed(ec, pub.X(), pub.Y()), compressed)
+
+	der, err := pub.MarshalPKIX()
+	assert.NoError(t, err)
+	parsed, err := x509.ParsePKIXPublicKey(der)
+	if assert.NoError(t, err) {
+		assert.True(t, pub.ToECDSAPubKey().Equal(parsed.(*ecdsa.PublicKey)))
+	}
+
+	bz, err := pub.MarshalPEM()
+	assert.NoError(t, err)
+	block, _ := pem.Decode(bz)
+	if assert.NotNil(t, block) {
+		assert.Equal(t, "PUBLIC KEY", block.Type)
+		assert.Equal(t, der, block.Bytes)
+	}
+
+	bz, err = pub.MarshalJWK()
+	assert.NoError(t, err)
+	var jwk map[string]string
+	assert.NoError(t, json.Unmarshal(bz, &jwk))
+	assert.Equal(t, "EC", jwk["kty"])
+	assert.Equal(t, "P-256", jwk["crv"])
+	assert.Equal(t, base64.RawURLEncoding.EncodeToString(pub.X().FillBytes(make([]byte, 32))), jwk["x"])
+	assert.Equal(t, base64.RawURLEncoding.EncodeToString(pub.Y().FillBytes(make([]byte, 32))), jwk["y"])
+}
+
+func TestMarshalPublicKeyS256(t *testing.T) {
+	ec := tss.S256()
+	pub := ScalarBaseMult(ec, common.GetRandomPositiveInt(ec.Params().N))
+	var fx, fy btcec.FieldVal
+	fx.SetByteSlice(pub.X().Bytes())
+	fy.SetByteSlice(pub.Y().Bytes())
+	btcPub := btcec.NewPublicKey(&fx, &fy)
+
+	compressed, err := pub.MarshalCompressed()
+	assert.NoError(t, err)
+	assert.Equal(t, btcPub.SerializeCompressed(), compressed)
+
+	der, err := pub.MarshalPKIX()
+	assert.NoError(t, err)
+	var spki struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	_, err = asn1.Unmarshal(der, &spki)
+	assert.NoError(t, err)
+	assert.Equal(t, asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}, spki.Algorithm.Algorithm)
+	var curve asn1.ObjectIdentifier
+	_, err = asn1.Unmarshal(spki.Algorithm.Parameters.FullBytes, &curve)
+	assert.NoError(t, err)
+	assert.Equal(t, asn1.ObjectIdentifier{1, 3, 132, 0, 10}, curve)
+	assert.Equal(t, btcPub.SerializeUncompressed(), spki.PublicKey.Bytes)
+
+	bz, err := pub.MarshalJWK()
+	assert.NoError(t, err)
+	var jwk map[string]string
+	assert.NoError(t, json.Unmarshal(bz, &jwk))
+	assert.Equal(t, "secp256k1", jwk["crv"])
+}
+
+func TestMarshalPublicKeyEd25519(t *testing.T) {
+	stdPub, _, err := ed25519.GenerateKey(nil)
+	assert.NoError(t, err)
+	edPub, err := edwards.ParsePubKey(stdPub)
+	if !assert.NoError(t, err) {
+		return
+	}
+	pub, err := NewECPoint(tss.Edwards(), edPub.X, edPub.Y)
+	assert.NoError(t, err)
+
+	compressed, err := pub.MarshalCompressed()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte(stdPub), compressed)
+
+	der, err := pub.MarshalPKIX()
+	assert.NoError(t, err)
+	parsed, err := x509.ParsePKIXPublicKey(der)
+	if assert.NoError(t, err) {
+		assert.Equal(t, stdPub, parsed)
+	}
+
+	bz, err := pub.MarshalJWK()
+	assert.NoError(t, err)
+	var jwk map[string]string
+	assert.NoError(t, json.Unmarshal(bz, &jwk))
+	assert.Equal(t, map[string]string{"kty": "OKP", "crv": "Ed25519", "x": base64.RawURLEncoding.EncodeToString(stdPub)}, jwk)
+}
+
+func TestMarshalPublicKeyNotOnCurve(t *testing.T) {
+	pub := NewECPointNoCurveCheck(tss.S256(), common.GetRandomPositiveInt(tss.S256().Params().N), common.GetRandomPositiveInt(tss.S256().Params().N))
+	_, err := pub.MarshalCompressed()
+	assert.Error(t, err)
+	_, err = pub.MarshalPKIX()
+	assert.Error(t, err)
+	_, err = pub.MarshalJWK()
+	assert.Error(t, err)
+}
@@ -0,0 +1,126 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package ristretto255 implements ristretto255 (RFC 9496), the prime-order group of sr25519, on top of the edwards25519
+// curve of dcrd/dcrec/edwards. An element is represented by the point of edwards25519 in the subgroup of order N that
+// the protocols already compute with, e.g. after EightInvEight; only its encoding differs from that of Ed25519.
+package ristretto255
+
+import (
+	"crypto/elliptic"
+	"math/big"
+
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+)
+
+// EncodingSize is the size of an encoded element
+const EncodingSize = 32
+
+// Curve is edwards25519 under the name of ristretto255, so that the curve registry of tss tells the keys of sr25519
+// from those of Ed25519
+type Curve struct {
+	*edwards.TwistedEdwardsCurve
+	params *elliptic.CurveParams
+}
+
+var (
+	ristretto255 = newCurve()
+
+	p = edwards.Edwards().Params().P
+	// sqrtM1 is sqrt(-1) = 2^((p-1)/4)
+	sqrtM1 = new(big.Int).Exp(big.NewInt(2), new(big.Int).Rsh(new(big.Int).Sub(p, big.NewInt(1)), 2), p)
+	// invSqrtAMinusD is 1/sqrt(a-d) with a = -1
+	invSqrtAMinusD, _ = new(big.Int).SetString("54469307008909316920995813868745141605393597292927456921205312896311721017578", 10)
+)
+
+func newCurve() *Curve {
+	params := *edwards.Edwards().Params()
+	params.Name = "ristretto255"
+	return &Curve{TwistedEdwardsCurve: edwards.Edwards(), params: &params}
+}
+
+// Ristretto255 returns the curve of ristretto255
+func Ristretto255() *Curve {
+	return ristretto255
+}
+
+func (curve *Curve) Params() *elliptic.CurveParams {
+	return curve.params
+}
+
+// Encode returns the encoding of RFC 9496 section 4.3.2 of the element represented by the point (x, y) of edwards25519
+func Encode(x, y *big.Int) []byte {
+	// the point in extended coordinates is (x : y : 1 : x*y)
+	t := mul(x, y)
+	u1 := mul(add(one, y), sub(one, y))
+	u2 := mul(x, y)
+	_, invSqrt := sqrtRatioM1(one, mul(u1, mul(u2, u2)))
+	den1, den2 := mul(invSqrt, u1), mul(invSqrt, u2)
+	zInv := mul(mul(den1, den2), t)
+
+	X, Y, denInv := x, y, den2
+	if isNegative(mul(t, zInv)) {
+		X, Y, denInv = mul(y, sqrtM1), mul(x, sqrtM1), mul(den1, invSqrtAMinusD)
+	}
+	if isNegative(mul(X, zInv)) {
+		Y = sub(zero, Y)
+	}
+	s := abs(mul(denInv, sub(one, Y)))
+
+	bz := make([]byte, EncodingSize)
+	s.FillBytes(bz)
+	for i, j := 0, len(bz)-1; i < j; i, j = i+1, j-1 {
+		bz[i], bz[j] = bz[j], bz[i]
+	}
+	return bz
+}
+
+// sqrtRatioM1 is SQRT_RATIO_M1 of RFC 9496 section 4.2: the non-negative square root of u/v or of sqrt(-1)*u/v, and
+// whether u/v was a square
+func sqrtRatioM1(u, v *big.Int) (bool, *big.Int) {
+	v3 := mul(mul(v, v), v)
+	v7 := mul(mul(v3, v3), v)
+	exp := new(big.Int).Rsh(new(big.Int).Sub(p, big.NewInt(5)), 3)
+	r := mul(mul(u, v3), new(big.Int).Exp(mul(u, v7), exp, p))
+	check := mul(v, mul(r, r))
+
+	minusU := sub(zero, u)
+	correctSign := check.Cmp(u) == 0
+	flippedSign := check.Cmp(minusU) == 0
+	flippedSignI := check.Cmp(mul(minusU, sqrtM1)) == 0
+	if flippedSign || flippedSignI {
+		r = mul(r, sqrtM1)
+	}
+	return correctSign || flippedSign, abs(r)
+}
+
+var (
+	zero = big.NewInt(0)
+	one  = big.NewInt(1)
+)
+
+func isNegative(a *big.Int) bool {
+	return a.Bit(0) == 1
+}
+
+func abs(a *big.Int) *big.Int {
+	if isNegative(a) {
+		return sub(zero, a)
+	}
+	return a
+}
+
+func add(a, b *big.Int) *big.Int {
+	return new(big.Int).Mod(new(big.Int).Add(a, b), p)
+}
+
+func sub(a, b *big.Int) *big.Int {
+	return new(big.Int).Mod(new(big.Int).Sub(a, b), p)
+}
+
+func mul(a, b *big.Int) *big.Int {
+	return new(big.Int).Mod(new(big.Int).Mul(a, b), p)
+}
@@ -0,0 +1,52 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package ristretto255_test
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	r255 "github.com/gtank/ristretto255"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/common"
+	. "github.com/kisdex/mpc-lib/crypto/ristretto255"
+)
+
+// the encodings of the multiples 0*B to 3*B of the base point, RFC 9496 appendix A.1
+var multiplesOfBase = []string{
+	"0000000000000000000000000000000000000000000000000000000000000000",
+	"e2f2ae0a6abc4e71a884a961c500515f58e30b6aa582dd8db6a65945e08d2d76",
+	"6a493210f7499cd17fecb510ae0cea23a110e8d5b901f8acadd3095c73a3b919",
+	"94741f5d5d52755ece4f23f044ee27d5d1ea1e2bd196b462166b16152a9d0259",
+}
+
+func TestEncode(t *testing.T) {
+	ec := Ristretto255()
+	for k, want := range multiplesOfBase {
+		x, y := ec.ScalarBaseMult(big.NewInt(int64(k)).Bytes())
+		assert.Equal(t, want, hex.EncodeToString(Encode(x, y)), "the encoding of %d*B", k)
+	}
+
+	for i := 0; i < 16; i++ {
+		k := common.GetRandomPositiveInt(ec.Params().N)
+		x, y := ec.ScalarBaseMult(k.Bytes())
+		le := make([]byte, 32)
+		k.FillBytes(le)
+		for i, j := 0, len(le)-1; i < j; i, j = i+1, j-1 {
+			le[i], le[j] = le[j], le[i]
+		}
+		s := r255.NewScalar()
+		if !assert.NoError(t, s.Decode(le)) {
+			return
+		}
+		want := r255.NewElement().ScalarBaseMult(s).Encode(nil)
+		assert.Equal(t, want, Encode(x, y), "the encoding must match that of gtank/ristretto255")
+	}
+	assert.Equal(t, "ristretto255", ec.Params().Name)
+}
@@ -7,13 +7,22 @@
 package schnorr
 
 import (
+	"crypto/elliptic"
 	"errors"
+	"fmt"
 	"math/big"
 
 	"github.com/kisdex/mpc-lib/common"
 	"github.com/kisdex/mpc-lib/crypto"
 )
 
+// Domain separation strings of the Fiat-Shamir challenges of the proofs, see common.SHA512_256i_DOMAIN
+const (
+	domainZK   = "mpc-lib/schnorr/zk"
+	domainZKV  = "mpc-lib/schnorr/zkv"
+	domainDLEQ = "mpc-lib/schnorr/dleq"
+)
+
 type (
 	ZKProof struct {
 		Alpha *crypto.ECPoint
@@ -24,6 +33,15 @@ type (
 		Alpha *crypto.ECPoint
 		T, U  *big.Int
 	}
+
+	DLEQProof struct {
+		A1, A2 *crypto.ECPoint
+		Z      *big.Int
+	}
+)
+
+const (
+	DLEQProofBytesParts = 3
 )
 
 // NewZKProof constructs a new Schnorr ZK proof of knowledge of the discrete logarithm (GG18Spec Fig. 16)
@@ -41,7 +59,7 @@ func NewZKProof(Session []byte, x *big.Int, X *crypto.ECPoint) (*ZKProof, error)
 
 	var c *big.Int
 	{
-		cHash := common.SHA512_256i_TAGGED(Session, X.X(), X.Y(), g.X(), g.Y(), alpha.X(), alpha.Y())
+		cHash := common.SHA512_256i_DOMAIN(domainZK, Session, append(common.CurveInts(ec), X.X(), X.Y(), g.X(), g.Y(), alpha.X(), alpha.Y())...)
 		c = common.RejectionSample(q, cHash)
 	}
 	t := new(big.Int).Mul(c, x)
@@ -52,7 +70,7 @@ func NewZKProof(Session []byte, x *big.Int, X *crypto.ECPoint) (*ZKProof, error)
 
 // NewZKProof verifies a new Schnorr ZK proof of knowledge of the discrete logarithm (GG18Spec Fig. 16)
 func (pf *ZKProof) Verify(Session []byte, X *crypto.ECPoint) bool {
-	if pf == nil || !pf.ValidateBasic() {
+	if pf == nil || !pf.ValidateBasic() || !crypto.ValidatePoints(X, pf.Alpha) {
 		return false
 	}
 	ec := X.Curve()
@@ -62,7 +80,7 @@ func (pf *ZKProof) Verify(Session []byte, X *crypto.ECPoint) bool {
 
 	var c *big.Int
 	{
-		cHash := common.SHA512_256i_TAGGED(Session, X.X(), X.Y(), g.X(), g.Y(), pf.Alpha.X(), pf.Alpha.Y())
+		cHash := common.SHA512_256i_DOMAIN(domainZK, Session, append(common.CurveInts(ec), X.X(), X.Y(), g.X(), g.Y(), pf.Alpha.X(), pf.Alpha.Y())...)
 		c = common.RejectionSample(q, cHash)
 	}
 	tG := crypto.ScalarBaseMult(ec, pf.T)
@@ -95,7 +113,7 @@ func NewZKVProof(Session []byte, V, R *crypto.ECPoint, s, l *big.Int) (*ZKVProof
 
 	var c *big.Int
 	{
-		cHash := common.SHA512_256i_TAGGED(Session, V.X(), V.Y(), R.X(), R.Y(), g.X(), g.Y(), alpha.X(), alpha.Y())
+		cHash := common.SHA512_256i_DOMAIN(domainZKV, Session, append(common.CurveInts(ec), V.X(), V.Y(), R.X(), R.Y(), g.X(), g.Y(), alpha.X(), alpha.Y())...)
 		c = common.RejectionSample(q, cHash)
 	}
 	modQ := common.ModInt(q)
@@ -106,7 +124,7 @@ func NewZKVProof(Session []byte, V, R *crypto.ECPoint, s, l *big.Int) (*ZKVProof
 }
 
 func (pf *ZKVProof) Verify(Session []byte, V, R *crypto.ECPoint) bool {
-	if pf == nil || !pf.ValidateBasic() {
+	if pf == nil || !pf.ValidateBasic() || !crypto.ValidatePoints(V, R, pf.Alpha) {
 		return false
 	}
 	ec := V.Curve()
@@ -116,7 +134,7 @@ func (pf *ZKVProof) Verify(Session []byte, V, R *crypto.ECPoint) bool {
 
 	var c *big.Int
 	{
-		cHash := common.SHA512_256i_TAGGED(Session, V.X(), V.Y(), R.X(), R.Y(), g.X(), g.Y(), pf.Alpha.X(), pf.Alpha.Y())
+		cHash := common.SHA512_256i_DOMAIN(domainZKV, Session, append(common.CurveInts(ec), V.X(), V.Y(), R.X(), R.Y(), g.X(), g.Y(), pf.Alpha.X(), pf.Alpha.Y())...)
 		c = common.RejectionSample(q, cHash)
 	}
 	tR := R.ScalarMult(pf.T)
@@ -134,3 +152,82 @@ func (pf *ZKVProof) Verify(Session []byte, V, R *crypto.ECPoint) bool {
 func (pf *ZKVProof) ValidateBasic() bool {
 	return pf.Alpha != nil && pf.T != nil && pf.U != nil && pf.Alpha.ValidateBasic()
 }
+
+// NewDLEQProof constructs a Chaum-Pedersen proof that X = g^x and Y = H^x share the same discrete logarithm x
+func NewDLEQProof(Session []byte, x *big.Int, H, X, Y *crypto.ECPoint) (*DLEQProof, error) {
+	if x == nil || H == nil || X == nil || Y == nil || !H.ValidateBasic() || !X.ValidateBasic() || !Y.ValidateBasic() {
+		return nil, errors.New("DLEQProof constructor received nil or invalid value(s)")
+	}
+	ec := X.Curve()
+	ecParams := ec.Params()
+	q := ecParams.N
+	g := crypto.NewECPointNoCurveCheck(ec, ecParams.Gx, ecParams.Gy) // already on the curve.
+
+	a := common.GetRandomPositiveInt(q)
+	a1 := crypto.ScalarBaseMult(ec, a)
+	a2 := H.ScalarMult(a)
+
+	var c *big.Int
+	{
+		cHash := common.SHA512_256i_DOMAIN(domainDLEQ, Session, append(common.CurveInts(ec), g.X(), g.Y(), H.X(), H.Y(), X.X(), X.Y(), Y.X(), Y.Y(), a1.X(), a1.Y(), a2.X(), a2.Y())...)
+		c = common.RejectionSample(q, cHash)
+	}
+	z := common.ModInt(q).Add(a, new(big.Int).Mul(c, x))
+
+	return &DLEQProof{A1: a1, A2: a2, Z: z}, nil
+}
+
+// NewDLEQProofFromBytes parses a proof serialized by DLEQProof.Bytes
+func NewDLEQProofFromBytes(ec elliptic.Curve, bzs [][]byte) (*DLEQProof, error) {
+	if !common.NonEmptyMultiBytes(bzs, DLEQProofBytesParts) {
+		return nil, fmt.Errorf("expected %d byte parts to construct DLEQProof", DLEQProofBytesParts)
+	}
+	a1, err := crypto.NewECPointFromBytes(ec, bzs[0])
+	if err != nil {
+		return nil, err
+	}
+	a2, err := crypto.NewECPointFromBytes(ec, bzs[1])
+	if err != nil {
+		return nil, err
+	}
+	return &DLEQProof{A1: a1, A2: a2, Z: new(big.Int).SetBytes(bzs[2])}, nil
+}
+
+func (pf *DLEQProof) Verify(Session []byte, H, X, Y *crypto.ECPoint) bool {
+	if pf == nil || !pf.ValidateBasic() || !crypto.ValidatePoints(H, X, Y, pf.A1, pf.A2) {
+		return false
+	}
+	ec := X.Curve()
+	ecParams := ec.Params()
+	q := ecParams.N
+	g := crypto.NewECPointNoCurveCheck(ec, ecParams.Gx, ecParams.Gy)
+
+	var c *big.Int
+	{
+		cHash := common.SHA512_256i_DOMAIN(domainDLEQ, Session, append(common.CurveInts(ec), g.X(), g.Y(), H.X(), H.Y(), X.X(), X.Y(), Y.X(), Y.Y(), pf.A1.X(), pf.A1.Y(), pf.A2.X(), pf.A2.Y())...)
+		c = common.RejectionSample(q, cHash)
+	}
+	zG := crypto.ScalarBaseMult(ec, pf.Z)
+	a1Xc, err := pf.A1.Add(X.ScalarMult(c))
+	if err != nil || !zG.Equals(a1Xc) {
+		return false
+	}
+	zH := H.ScalarMult(pf.Z)
+	a2Yc, err := pf.A2.Add(Y.ScalarMult(c))
+	if err != nil {
+		return false
+	}
+	return zH.Equals(a2Yc)
+}
+
+func (pf *DLEQProof) ValidateBasic() bool {
+	return pf.A1 != nil && pf.A2 != nil && pf.Z != nil && pf.A1.ValidateBasic() && pf.A2.ValidateBasic()
+}
+
+func (pf *DLEQProof) Bytes() [][]byte {
+	return [][]byte{
+		pf.A1.Bytes(),
+		pf.A2.Bytes(),
+		pf.Z.Bytes(),
+	}
+}
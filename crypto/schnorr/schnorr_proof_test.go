@@ -104,3 +104,34 @@ func TestSchnorrVProofVerifyBadS(t *testing.T) {
 
 	assert.False(t, res, "verify result must be false")
 }
+
+func TestDLEQProofVerify(t *testing.T) {
+	q := tss.EC().Params().N
+	x := common.GetRandomPositiveInt(q)
+	H := crypto.ScalarBaseMult(tss.EC(), common.GetRandomPositiveInt(q))
+	X := crypto.ScalarBaseMult(tss.EC(), x)
+	Y := H.ScalarMult(x)
+
+	proof, err := NewDLEQProof(Session, x, H, X, Y)
+	assert.NoError(t, err)
+	assert.True(t, proof.Verify(Session, H, X, Y), "verify result must be true")
+	assert.False(t, proof.Verify([]byte("other session"), H, X, Y), "verify result must be false")
+
+	parsed, err := NewDLEQProofFromBytes(tss.EC(), proof.Bytes())
+	assert.NoError(t, err)
+	assert.True(t, parsed.Verify(Session, H, X, Y), "verify result must be true")
+}
+
+func TestDLEQProofVerifyBadY(t *testing.T) {
+	q := tss.EC().Params().N
+	x := common.GetRandomPositiveInt(q)
+	x2 := common.GetRandomPositiveInt(q)
+	H := crypto.ScalarBaseMult(tss.EC(), common.GetRandomPositiveInt(q))
+	X := crypto.ScalarBaseMult(tss.EC(), x)
+	Y := H.ScalarMult(x2)
+
+	proof, _ := NewDLEQProof(Session, x, H, X, Y)
+	res := proof.Verify(Session, H, X, Y)
+
+	assert.False(t, res, "verify result must be false")
+}
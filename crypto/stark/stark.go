@@ -0,0 +1,162 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package stark implements the STARK-friendly curve of StarkNet, y^2 = x^3 + x + b over GF(2^251 + 17*2^192 + 1), as
+// an elliptic.Curve over big.Int. The curve has a = 1, which the generic curve of crypto/elliptic (a = -3) cannot
+// represent. Like Go's own, the point at infinity is (0, 0).
+package stark
+
+import (
+	"crypto/elliptic"
+	"math/big"
+)
+
+// Curve is the STARK curve
+type Curve struct {
+	params *elliptic.CurveParams
+}
+
+var stark = newCurve()
+
+func newCurve() *Curve {
+	p, _ := new(big.Int).SetString("800000000000011000000000000000000000000000000000000000000000001", 16)
+	n, _ := new(big.Int).SetString("800000000000010ffffffffffffffffb781126dcae7b2321e66a241adc64d2f", 16)
+	b, _ := new(big.Int).SetString("6f21413efbe40de150e596d72f7a8c5609ad26c15c915c1f4cdfcb99cee9e89", 16)
+	gx, _ := new(big.Int).SetString("1ef15c18599971b7beced415a40f0c7deacfd9b0d1819e03d723d8bc943cfca", 16)
+	gy, _ := new(big.Int).SetString("5668060aa49730b7be4801df46ec62de53ecd11abe43a32873000c36e8dc1f", 16)
+	return &Curve{
+		params: &elliptic.CurveParams{P: p, N: n, B: b, Gx: gx, Gy: gy, BitSize: 252, Name: "stark"},
+	}
+}
+
+// Stark returns the STARK curve
+func Stark() *Curve {
+	return stark
+}
+
+func (curve *Curve) Params() *elliptic.CurveParams {
+	return curve.params
+}
+
+func (curve *Curve) IsOnCurve(x, y *big.Int) bool {
+	p := curve.params.P
+	if x.Sign() < 0 || x.Cmp(p) >= 0 || y.Sign() < 0 || y.Cmp(p) >= 0 {
+		return false
+	}
+	// y^2 = x^3 + x + b
+	rhs := curve.add(curve.mul(curve.mul(x, x), x), curve.add(x, curve.params.B))
+	return curve.mul(y, y).Cmp(rhs) == 0
+}
+
+func (curve *Curve) Add(x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int) {
+	return curve.affine(curve.addJacobian(curve.jacobian(x1, y1), curve.jacobian(x2, y2)))
+}
+
+func (curve *Curve) Double(x1, y1 *big.Int) (*big.Int, *big.Int) {
+	return curve.affine(curve.doubleJacobian(curve.jacobian(x1, y1)))
+}
+
+// ScalarMult returns k*(x, y) for the big-endian k
+func (curve *Curve) ScalarMult(x, y *big.Int, k []byte) (*big.Int, *big.Int) {
+	point := curve.jacobian(x, y)
+	r := jacobianPoint{new(big.Int), new(big.Int), new(big.Int)}
+	for _, b := range k {
+		for bit := 7; 0 <= bit; bit-- {
+			r = curve.doubleJacobian(r)
+			if (b>>uint(bit))&1 == 1 {
+				r = curve.addJacobian(r, point)
+			}
+		}
+	}
+	return curve.affine(r)
+}
+
+func (curve *Curve) ScalarBaseMult(k []byte) (*big.Int, *big.Int) {
+	return curve.ScalarMult(curve.params.Gx, curve.params.Gy, k)
+}
+
+// jacobianPoint is (X:Y:Z) for the affine point (X/Z^2, Y/Z^3), and the point at infinity when Z = 0
+type jacobianPoint struct {
+	x, y, z *big.Int
+}
+
+func (curve *Curve) jacobian(x, y *big.Int) jacobianPoint {
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return jacobianPoint{new(big.Int), new(big.Int), new(big.Int)}
+	}
+	return jacobianPoint{new(big.Int).Set(x), new(big.Int).Set(y), big.NewInt(1)}
+}
+
+func (curve *Curve) affine(point jacobianPoint) (*big.Int, *big.Int) {
+	if point.z.Sign() == 0 {
+		return new(big.Int), new(big.Int)
+	}
+	zInv := new(big.Int).ModInverse(point.z, curve.params.P)
+	zInv2 := curve.mul(zInv, zInv)
+	return curve.mul(point.x, zInv2), curve.mul(point.y, curve.mul(zInv2, zInv))
+}
+
+// addJacobian is add-2007-bl of the Explicit-Formulas Database, with the cases of the point at infinity and of
+// doubling that it does not cover
+func (curve *Curve) addJacobian(p1, p2 jacobianPoint) jacobianPoint {
+	if p1.z.Sign() == 0 {
+		return p2
+	}
+	if p2.z.Sign() == 0 {
+		return p1
+	}
+	z1z1, z2z2 := curve.mul(p1.z, p1.z), curve.mul(p2.z, p2.z)
+	u1, u2 := curve.mul(p1.x, z2z2), curve.mul(p2.x, z1z1)
+	s1 := curve.mul(p1.y, curve.mul(p2.z, z2z2))
+	s2 := curve.mul(p2.y, curve.mul(p1.z, z1z1))
+	h := curve.sub(u2, u1)
+	r := curve.add(curve.sub(s2, s1), curve.sub(s2, s1))
+	if h.Sign() == 0 {
+		if r.Sign() == 0 {
+			return curve.doubleJacobian(p1)
+		}
+		return jacobianPoint{new(big.Int), new(big.Int), new(big.Int)}
+	}
+	i := curve.mul(curve.add(h, h), curve.add(h, h))
+	j := curve.mul(h, i)
+	v := curve.mul(u1, i)
+	x3 := curve.sub(curve.sub(curve.mul(r, r), j), curve.add(v, v))
+	s1j := curve.mul(s1, j)
+	y3 := curve.sub(curve.mul(r, curve.sub(v, x3)), curve.add(s1j, s1j))
+	z1PlusZ2 := curve.add(p1.z, p2.z)
+	z3 := curve.mul(curve.sub(curve.sub(curve.mul(z1PlusZ2, z1PlusZ2), z1z1), z2z2), h)
+	return jacobianPoint{x3, y3, z3}
+}
+
+// doubleJacobian is dbl-2007-bl of the Explicit-Formulas Database, for a = 1
+func (curve *Curve) doubleJacobian(p1 jacobianPoint) jacobianPoint {
+	if p1.z.Sign() == 0 || p1.y.Sign() == 0 {
+		return jacobianPoint{new(big.Int), new(big.Int), new(big.Int)}
+	}
+	xx, yy, zz := curve.mul(p1.x, p1.x), curve.mul(p1.y, p1.y), curve.mul(p1.z, p1.z)
+	yyyy := curve.mul(yy, yy)
+	x1PlusYY := curve.add(p1.x, yy)
+	s := curve.sub(curve.sub(curve.mul(x1PlusYY, x1PlusYY), xx), yyyy)
+	s = curve.add(s, s)
+	m := curve.add(curve.mul(big.NewInt(3), xx), curve.mul(zz, zz))
+	t := curve.sub(curve.mul(m, m), curve.add(s, s))
+	y3 := curve.sub(curve.mul(m, curve.sub(s, t)), curve.mul(big.NewInt(8), yyyy))
+	y1PlusZ1 := curve.add(p1.y, p1.z)
+	z3 := curve.sub(curve.sub(curve.mul(y1PlusZ1, y1PlusZ1), yy), zz)
+	return jacobianPoint{t, y3, z3}
+}
+
+func (curve *Curve) add(a, b *big.Int) *big.Int {
+	return new(big.Int).Mod(new(big.Int).Add(a, b), curve.params.P)
+}
+
+func (curve *Curve) sub(a, b *big.Int) *big.Int {
+	return new(big.Int).Mod(new(big.Int).Sub(a, b), curve.params.P)
+}
+
+func (curve *Curve) mul(a, b *big.Int) *big.Int {
+	return new(big.Int).Mod(new(big.Int).Mul(a, b), curve.params.P)
+}
@@ -0,0 +1,52 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package stark_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/common"
+	. "github.com/kisdex/mpc-lib/crypto/stark"
+)
+
+func TestCurve(t *testing.T) {
+	ec := Stark()
+	params := ec.Params()
+	assert.True(t, ec.IsOnCurve(params.Gx, params.Gy), "the base point must be on the curve")
+	assert.True(t, params.N.ProbablyPrime(20), "the order must be prime")
+
+	x, y := ec.ScalarBaseMult(params.N.Bytes())
+	assert.Equal(t, 0, x.Sign(), "N*G must be the point at infinity")
+	assert.Equal(t, 0, y.Sign(), "N*G must be the point at infinity")
+	x, y = ec.ScalarBaseMult(new(big.Int).Sub(params.N, big.NewInt(1)).Bytes())
+	assert.Equal(t, 0, x.Cmp(params.Gx), "(N-1)*G must be -G")
+	assert.Equal(t, 0, y.Cmp(new(big.Int).Sub(params.P, params.Gy)), "(N-1)*G must be -G")
+
+	a, b := common.GetRandomPositiveInt(params.N), common.GetRandomPositiveInt(params.N)
+	aX, aY := ec.ScalarBaseMult(a.Bytes())
+	bX, bY := ec.ScalarBaseMult(b.Bytes())
+	sumX, sumY := ec.Add(aX, aY, bX, bY)
+	abX, abY := ec.ScalarBaseMult(new(big.Int).Add(a, b).Bytes())
+	assert.True(t, ec.IsOnCurve(sumX, sumY))
+	assert.Equal(t, 0, sumX.Cmp(abX), "aG + bG must be (a+b)G")
+	assert.Equal(t, 0, sumY.Cmp(abY), "aG + bG must be (a+b)G")
+	dblX, dblY := ec.Double(aX, aY)
+	addX, addY := ec.Add(aX, aY, aX, aY)
+	assert.Equal(t, 0, dblX.Cmp(addX), "doubling must be adding a point to itself")
+	assert.Equal(t, 0, dblY.Cmp(addY), "doubling must be adding a point to itself")
+
+	zeroX, zeroY := ec.Add(aX, aY, aX, new(big.Int).Sub(params.P, aY))
+	assert.Equal(t, 0, zeroX.Sign(), "P + -P must be the point at infinity")
+	assert.Equal(t, 0, zeroY.Sign(), "P + -P must be the point at infinity")
+	idX, idY := ec.Add(aX, aY, zeroX, zeroY)
+	assert.Equal(t, 0, idX.Cmp(aX), "P + O must be P")
+	assert.Equal(t, 0, idY.Cmp(aY), "P + O must be P")
+	assert.False(t, ec.IsOnCurve(aX, new(big.Int).Add(aY, big.NewInt(1))))
+}
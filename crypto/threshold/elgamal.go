@@ -0,0 +1,205 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package threshold
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto"
+	"github.com/kisdex/mpc-lib/crypto/schnorr"
+	"github.com/kisdex/mpc-lib/crypto/vss"
+)
+
+type (
+	// ElGamalPublicKey is the public part of an ElGamal key x shared with Shamir's scheme: Y = x*G, and BigXj[j] is the
+	// share of party Ks[j] times G. The shares of a threshold keygen, e.g. ecdsa/keygen, form such a key as they are.
+	ElGamalPublicKey struct {
+		Y         *crypto.ECPoint
+		Threshold int
+		Ks        []*big.Int
+		BigXj     []*crypto.ECPoint
+	}
+
+	// ElGamalKeyShare is the secret share Xi of the party with the Shamir index ID
+	ElGamalKeyShare struct {
+		ID, Xi *big.Int
+	}
+
+	// ElGamalCiphertext is (C1, C2) = (k*G, M + k*Y) for the point M
+	ElGamalCiphertext struct {
+		C1, C2 *crypto.ECPoint
+	}
+
+	// ElGamalDecryptionShare is the share Di = Xi*C1 of the party with the index ID, with a proof that it has the same
+	// discrete logarithm as BigXj
+	ElGamalDecryptionShare struct {
+		ID    *big.Int
+		Di    *crypto.ECPoint
+		Proof *schnorr.DLEQProof
+	}
+)
+
+// DealElGamal generates an ElGamal key on the curve `ec` and shares it among the parties of the Shamir indexes `ks`, so
+// that any threshold+1 of them decrypt. The dealer must be trusted.
+func DealElGamal(ec elliptic.Curve, threshold int, ks []*big.Int) (*ElGamalPublicKey, []*ElGamalKeyShare, error) {
+	if threshold < 0 || len(ks) <= threshold {
+		return nil, nil, fmt.Errorf("the threshold %d must be in [0, %d)", threshold, len(ks))
+	}
+	x := common.GetRandomPositiveInt(ec.Params().N)
+	vs, vssShares, err := vss.Create(ec, threshold, x, ks)
+	if err != nil {
+		return nil, nil, err
+	}
+	shares := make([]*ElGamalKeyShare, len(vssShares))
+	bigXj := make([]*crypto.ECPoint, len(vssShares))
+	for j, share := range vssShares {
+		shares[j] = &ElGamalKeyShare{ID: share.ID, Xi: share.Share}
+		bigXj[j] = crypto.ScalarBaseMult(ec, share.Share)
+	}
+	return &ElGamalPublicKey{Y: vs[0], Threshold: threshold, Ks: ks, BigXj: bigXj}, shares, nil
+}
+
+// Encrypt returns an encryption of the point M under the key
+func (pk *ElGamalPublicKey) Encrypt(M *crypto.ECPoint) (*ElGamalCiphertext, error) {
+	if !crypto.ValidatePoints(pk.Y, M) {
+		return nil, errors.New("the key or the message is not a valid point")
+	}
+	ec := pk.Y.Curve()
+	k := common.GetRandomPositiveInt(ec.Params().N)
+	C2, err := M.Add(pk.Y.ScalarMult(k))
+	if err != nil {
+		return nil, err
+	}
+	return &ElGamalCiphertext{C1: crypto.ScalarBaseMult(ec, k), C2: C2}, nil
+}
+
+// Add returns an encryption of the sum of the points that `ct` and `other` encrypt. With messages m*G, e.g. the votes
+// of a tally, the plaintexts add up under encryption.
+func (ct *ElGamalCiphertext) Add(other *ElGamalCiphertext) (*ElGamalCiphertext, error) {
+	C1, err := ct.C1.Add(other.C1)
+	if err != nil {
+		return nil, err
+	}
+	C2, err := ct.C2.Add(other.C2)
+	if err != nil {
+		return nil, err
+	}
+	return &ElGamalCiphertext{C1: C1, C2: C2}, nil
+}
+
+// PartialDecrypt returns the decryption share of the ciphertext `ct` of the party of the share, with its proof bound
+// to `Session`
+func (share *ElGamalKeyShare) PartialDecrypt(Session []byte, ct *ElGamalCiphertext) (*ElGamalDecryptionShare, error) {
+	if ct == nil || !crypto.ValidatePoints(ct.C1, ct.C2) {
+		return nil, errors.New("the ciphertext is not made of valid points")
+	}
+	return share.PartialECDH(Session, ct.C1)
+}
+
+// VerifyShare checks the decryption share `ds` of the ciphertext `ct` against the public share of its party
+func (pk *ElGamalPublicKey) VerifyShare(Session []byte, ct *ElGamalCiphertext, ds *ElGamalDecryptionShare) bool {
+	return ct != nil && crypto.ValidatePoints(ct.C2) && pk.VerifyPartialECDH(Session, ct.C1, ds)
+}
+
+// Combine returns the point that `ct` encrypts from the decryption shares of at least threshold+1 parties.
+// Every share is verified; an invalid share is an error that names its party.
+func (pk *ElGamalPublicKey) Combine(Session []byte, ct *ElGamalCiphertext, dss []*ElGamalDecryptionShare) (*crypto.ECPoint, error) {
+	if ct == nil || !crypto.ValidatePoints(ct.C1, ct.C2) {
+		return nil, errors.New("the ciphertext is not made of valid points")
+	}
+	// M = C2 - x*C1
+	S, err := pk.CombineECDH(Session, ct.C1, dss)
+	if err != nil {
+		return nil, err
+	}
+	q := S.Curve().Params().N
+	return ct.C2.Add(S.ScalarMult(new(big.Int).Sub(q, big.NewInt(1))))
+}
+
+// PartialECDH returns the share Xi*P of the party of the share of the Diffie-Hellman point x*P of the key and the point
+// P, e.g. the ephemeral key of an ECIES ciphertext, with its proof bound to `Session`
+func (share *ElGamalKeyShare) PartialECDH(Session []byte, P *crypto.ECPoint) (*ElGamalDecryptionShare, error) {
+	if !crypto.ValidatePoints(P) {
+		return nil, errors.New("the point is not valid")
+	}
+	Di := P.ScalarMult(share.Xi)
+	proof, err := schnorr.NewDLEQProof(Session, share.Xi, P, crypto.ScalarBaseMult(P.Curve(), share.Xi), Di)
+	if err != nil {
+		return nil, err
+	}
+	return &ElGamalDecryptionShare{ID: share.ID, Di: Di, Proof: proof}, nil
+}
+
+// VerifyPartialECDH checks the share `ds` of x*P against the public share of its party
+func (pk *ElGamalPublicKey) VerifyPartialECDH(Session []byte, P *crypto.ECPoint, ds *ElGamalDecryptionShare) bool {
+	if ds == nil || ds.ID == nil || !crypto.ValidatePoints(P, ds.Di) {
+		return false
+	}
+	j := pk.indexOf(ds.ID)
+	if j < 0 {
+		return false
+	}
+	return ds.Proof.Verify(Session, P, pk.BigXj[j], ds.Di)
+}
+
+// CombineECDH returns x*P from the shares of at least threshold+1 parties.
+// Every share is verified; an invalid share is an error that names its party.
+func (pk *ElGamalPublicKey) CombineECDH(Session []byte, P *crypto.ECPoint, dss []*ElGamalDecryptionShare) (*crypto.ECPoint, error) {
+	if len(dss) <= pk.Threshold {
+		return nil, fmt.Errorf("%d decryption shares are too few for the threshold %d", len(dss), pk.Threshold)
+	}
+	seen := make(map[string]struct{}, len(dss))
+	for _, ds := range dss {
+		if ds == nil || ds.ID == nil {
+			return nil, errors.New("a decryption share is nil or has no party index")
+		}
+		if _, ok := seen[ds.ID.String()]; ok {
+			return nil, fmt.Errorf("duplicate decryption share of party %s", ds.ID)
+		}
+		seen[ds.ID.String()] = struct{}{}
+		if !pk.VerifyPartialECDH(Session, P, ds) {
+			return nil, fmt.Errorf("the decryption share of party %s is invalid", ds.ID)
+		}
+	}
+	dss = dss[:pk.Threshold+1]
+
+	// x*P = sum(lambda_i*Di) for the Lagrange coefficients lambda_i at 0
+	modQ := common.ModInt(P.Curve().Params().N)
+	var S *crypto.ECPoint
+	for _, ds := range dss {
+		lambda := big.NewInt(1)
+		for _, other := range dss {
+			if other.ID.Cmp(ds.ID) == 0 {
+				continue
+			}
+			lambda = modQ.Mul(lambda, modQ.Mul(other.ID, modQ.ModInverse(modQ.Sub(other.ID, ds.ID))))
+		}
+		term := ds.Di.ScalarMult(lambda)
+		if S == nil {
+			S = term
+			continue
+		}
+		var err error
+		if S, err = S.Add(term); err != nil {
+			return nil, err
+		}
+	}
+	return S, nil
+}
+
+func (pk *ElGamalPublicKey) indexOf(id *big.Int) int {
+	for j, k := range pk.Ks {
+		if k.Cmp(id) == 0 {
+			return j
+		}
+	}
+	return -1
+}
@@ -0,0 +1,65 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package threshold_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/crypto"
+	. "github.com/kisdex/mpc-lib/crypto/threshold"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+func TestElGamalThresholdDecryption(t *testing.T) {
+	ec := tss.S256()
+	ks := []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3), big.NewInt(4)}
+	pk, shares, err := DealElGamal(ec, 1, ks)
+	assert.NoError(t, err)
+
+	// a tally of three votes for and one against, each encrypted as (vote+1)*G so that no message is the identity
+	var tally *ElGamalCiphertext
+	for _, vote := range []int64{1, 1, 0, 1} {
+		ct, err := pk.Encrypt(crypto.ScalarBaseMult(ec, big.NewInt(vote+1)))
+		assert.NoError(t, err)
+		if tally == nil {
+			tally = ct
+			continue
+		}
+		tally, err = tally.Add(ct)
+		assert.NoError(t, err)
+	}
+
+	dss := make([]*ElGamalDecryptionShare, len(shares))
+	for i, share := range shares {
+		dss[i], err = share.PartialDecrypt(Session, tally)
+		assert.NoError(t, err)
+		assert.True(t, pk.VerifyShare(Session, tally, dss[i]))
+	}
+	expected := crypto.ScalarBaseMult(ec, big.NewInt(int64(3+len(ks))))
+	for _, subset := range [][]*ElGamalDecryptionShare{dss[:2], {dss[3], dss[1]}, dss} {
+		M, err := pk.Combine(Session, tally, subset)
+		if assert.NoError(t, err) {
+			assert.True(t, expected.Equals(M))
+		}
+	}
+
+	_, err = pk.Combine(Session, tally, dss[:1])
+	assert.Error(t, err)
+
+	// a share decrypted with another party's key share does not verify
+	bad, err := shares[0].PartialDecrypt(Session, tally)
+	assert.NoError(t, err)
+	bad.ID = ks[2]
+	assert.False(t, pk.VerifyShare(Session, tally, bad))
+	_, err = pk.Combine(Session, tally, []*ElGamalDecryptionShare{dss[1], bad})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "party 3")
+	}
+}
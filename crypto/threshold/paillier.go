@@ -0,0 +1,239 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package threshold implements threshold decryption for applications of MPC other than signing, e.g. private voting
+// or sealed-bid auctions: a key is shared among n parties so that any t+1 of them decrypt a ciphertext together, each
+// with a decryption share and a proof that the share is correct, while t or fewer of them learn nothing about the
+// plaintext.
+// Two schemes are supported: Paillier, after Shoup (2000) "Practical threshold signatures" and Damgård and Jurik
+// (2001), whose plaintexts add up under encryption; and ElGamal over an elliptic curve.
+
+package threshold
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto/paillier"
+)
+
+const (
+	// domain separation string of the challenge of PaillierShareProof, see common.SHA512_256i_DOMAIN
+	domainPaillier = "mpc-lib/threshold/paillier"
+
+	// the bits of the challenge of PaillierShareProof, a SHA-512/256 digest, which also pad its mask for statistical
+	// zero-knowledge
+	paillierChallengeBits = 256
+)
+
+type (
+	// PaillierPublicKey is the public part of a Paillier key shared among n parties with the indexes 1..n
+	PaillierPublicKey struct {
+		*paillier.PublicKey
+		Threshold int
+		// Delta = n!, which clears the denominators of the Lagrange coefficients over the integers
+		Delta *big.Int
+		// V generates the squares of Z*_{N^2}, and VerificationKeys[i-1] = V^(Delta*s_i) for the share s_i of party i
+		V                *big.Int
+		VerificationKeys []*big.Int
+	}
+
+	// PaillierKeyShare is the secret share s_i = f(i) of the decryption exponent d = f(0) of party i
+	PaillierKeyShare struct {
+		Index int
+		Si    *big.Int
+	}
+
+	// PaillierDecryptionShare is the share Ci = c^(2*Delta*s_i) mod N^2 of party i of the decryption of c
+	PaillierDecryptionShare struct {
+		Index int
+		Ci    *big.Int
+		Proof *PaillierShareProof
+	}
+
+	// PaillierShareProof shows that log_{c^4}(Ci^2) = log_V(Vi), i.e. that the decryption share Ci is computed with
+	// the share of the key behind the verification key Vi. The group order is unknown, so Z is an integer.
+	PaillierShareProof struct {
+		A, B, Z *big.Int
+	}
+)
+
+var (
+	four = big.NewInt(4)
+)
+
+// DealPaillier shares the Paillier key `sk` among `partyCount` parties, so that any threshold+1 of them decrypt.
+// The dealer must be trusted and must erase `sk` afterwards. The primes of `sk` must be safe primes, as those of
+// paillier.GenerateKeyPair are.
+func DealPaillier(sk *paillier.PrivateKey, threshold, partyCount int) (*PaillierPublicKey, []*PaillierKeyShare, error) {
+	if sk == nil || sk.P == nil || sk.Q == nil {
+		return nil, nil, errors.New("DealPaillier needs a private key with its factors")
+	}
+	if threshold < 0 || partyCount <= threshold {
+		return nil, nil, fmt.Errorf("the threshold %d must be in [0, %d)", threshold, partyCount)
+	}
+	pPrime, qPrime := new(big.Int).Rsh(sk.P, 1), new(big.Int).Rsh(sk.Q, 1)
+	if sk.P.Bit(0) != 1 || sk.Q.Bit(0) != 1 || !pPrime.ProbablyPrime(30) || !qPrime.ProbablyPrime(30) {
+		return nil, nil, errors.New("the factors of the key must be safe primes")
+	}
+	pk := paillier.NewPublicKey(new(big.Int).Mul(sk.P, sk.Q))
+	N := pk.N
+
+	// d = 0 mod m and d = 1 mod N for m = p'q', so that c^(4d) = 1 + 4M*N mod N^2 for c = (1+N)^M * r^N
+	m := new(big.Int).Mul(pPrime, qPrime)
+	mInv := new(big.Int).ModInverse(m, N)
+	if mInv == nil {
+		return nil, nil, errors.New("p'q' must be invertible mod N")
+	}
+	nm := new(big.Int).Mul(N, m)
+	d := common.ModInt(nm).Mul(m, mInv)
+
+	// f(X) = d + a_1*X + ... + a_t*X^t over Z_{Nm}
+	poly := make([]*big.Int, threshold+1)
+	poly[0] = d
+	for k := 1; k <= threshold; k++ {
+		poly[k] = common.GetRandomPositiveInt(nm)
+	}
+
+	delta := factorial(partyCount)
+	modN2 := common.ModInt(pk.NSquare())
+	v := modN2.Exp(common.GetRandomPositiveRelativelyPrimeInt(N), big.NewInt(2))
+	shares := make([]*PaillierKeyShare, partyCount)
+	vks := make([]*big.Int, partyCount)
+	for i := 1; i <= partyCount; i++ {
+		si := evaluatePolynomial(poly, big.NewInt(int64(i)), nm)
+		shares[i-1] = &PaillierKeyShare{Index: i, Si: si}
+		vks[i-1] = modN2.SecretExp(v, new(big.Int).Mul(delta, si), nil)
+	}
+	return &PaillierPublicKey{PublicKey: pk, Threshold: threshold, Delta: delta, V: v, VerificationKeys: vks}, shares, nil
+}
+
+// PartialDecrypt returns the decryption share of the ciphertext `c` of party `share.Index`, with its proof bound to
+// `Session`
+func (share *PaillierKeyShare) PartialDecrypt(Session []byte, pk *PaillierPublicKey, c *big.Int) (*PaillierDecryptionShare, error) {
+	if err := pk.validateIndex(share.Index); err != nil {
+		return nil, err
+	}
+	if !pk.ValidateCiphertexts(c) {
+		return nil, errors.New("the ciphertext is not in Z*_{N^2}")
+	}
+	modN2 := common.ModInt(pk.NSquare())
+	x := new(big.Int).Mul(pk.Delta, share.Si)
+	ci := modN2.SecretExp(c, new(big.Int).Lsh(x, 1), nil)
+
+	// a Chaum-Pedersen proof over Z*_{N^2}, with a mask long enough to hide Delta*s_i statistically
+	c4 := modN2.Exp(c, four)
+	ci2 := modN2.Mul(ci, ci)
+	vi := pk.VerificationKeys[share.Index-1]
+	r := common.MustGetRandomInt(pk.NSquare().BitLen() + pk.Delta.BitLen() + 2*paillierChallengeBits)
+	a := modN2.SecretExp(c4, r, nil)
+	b := modN2.SecretExp(pk.V, r, nil)
+	e := pk.challenge(Session, c4, ci2, vi, a, b)
+	z := new(big.Int).Add(r, e.Mul(e, x))
+
+	return &PaillierDecryptionShare{Index: share.Index, Ci: ci, Proof: &PaillierShareProof{A: a, B: b, Z: z}}, nil
+}
+
+// VerifyShare checks the decryption share `ds` of the ciphertext `c` against the verification key of its party
+func (pk *PaillierPublicKey) VerifyShare(Session []byte, c *big.Int, ds *PaillierDecryptionShare) bool {
+	if ds == nil || ds.Proof == nil || pk.validateIndex(ds.Index) != nil {
+		return false
+	}
+	pf := ds.Proof
+	if pf.Z == nil || pf.Z.Sign() < 0 || !pk.ValidateCiphertexts(c, ds.Ci, pf.A, pf.B) {
+		return false
+	}
+	modN2 := common.ModInt(pk.NSquare())
+	c4 := modN2.Exp(c, four)
+	ci2 := modN2.Mul(ds.Ci, ds.Ci)
+	vi := pk.VerificationKeys[ds.Index-1]
+	e := pk.challenge(Session, c4, ci2, vi, pf.A, pf.B)
+
+	// (c^4)^z = A * (Ci^2)^e and V^z = B * Vi^e
+	if modN2.Exp(c4, pf.Z).Cmp(modN2.Mul(pf.A, modN2.Exp(ci2, e))) != 0 {
+		return false
+	}
+	return modN2.Exp(pk.V, pf.Z).Cmp(modN2.Mul(pf.B, modN2.Exp(vi, e))) == 0
+}
+
+// Combine returns the plaintext of the ciphertext `c` from the decryption shares of at least threshold+1 parties.
+// Every share is verified; an invalid share is an error that names its party.
+func (pk *PaillierPublicKey) Combine(Session []byte, c *big.Int, dss []*PaillierDecryptionShare) (*big.Int, error) {
+	if len(dss) <= pk.Threshold {
+		return nil, fmt.Errorf("%d decryption shares are too few for the threshold %d", len(dss), pk.Threshold)
+	}
+	seen := make(map[int]struct{}, len(dss))
+	for _, ds := range dss {
+		if ds == nil {
+			return nil, errors.New("a decryption share is nil")
+		}
+		if _, ok := seen[ds.Index]; ok {
+			return nil, fmt.Errorf("duplicate decryption share of party %d", ds.Index)
+		}
+		seen[ds.Index] = struct{}{}
+		if !pk.VerifyShare(Session, c, ds) {
+			return nil, fmt.Errorf("the decryption share of party %d is invalid", ds.Index)
+		}
+	}
+	dss = dss[:pk.Threshold+1]
+
+	// c' = prod(Ci^(2*mu_i)) = c^(4*Delta^2*d) = 1 + 4*Delta^2*M*N, for the integer coefficients mu_i = Delta*lambda_i
+	modN2 := common.ModInt(pk.NSquare())
+	cPrime := big.NewInt(1)
+	for _, ds := range dss {
+		mu := new(big.Int).Set(pk.Delta)
+		den := big.NewInt(1)
+		for _, other := range dss {
+			if other.Index == ds.Index {
+				continue
+			}
+			mu.Mul(mu, big.NewInt(int64(other.Index)))
+			den.Mul(den, big.NewInt(int64(other.Index-ds.Index)))
+		}
+		mu.Quo(mu, den).Lsh(mu, 1)
+		base := ds.Ci
+		if mu.Sign() < 0 {
+			base = modN2.ModInverse(base)
+			mu.Neg(mu)
+		}
+		cPrime = modN2.Mul(cPrime, modN2.Exp(base, mu))
+	}
+	modN := common.ModInt(pk.N)
+	scale := modN.ModInverse(new(big.Int).Lsh(new(big.Int).Mul(pk.Delta, pk.Delta), 2))
+	if scale == nil {
+		return nil, errors.New("4*Delta^2 must be invertible mod N")
+	}
+	return modN.Mul(paillier.L(cPrime, pk.N), scale), nil
+}
+
+func (pk *PaillierPublicKey) validateIndex(index int) error {
+	if index < 1 || len(pk.VerificationKeys) < index {
+		return fmt.Errorf("the party index %d is not in [1, %d]", index, len(pk.VerificationKeys))
+	}
+	return nil
+}
+
+func (pk *PaillierPublicKey) challenge(Session []byte, c4, ci2, vi, a, b *big.Int) *big.Int {
+	return common.SHA512_256i_DOMAIN(domainPaillier, Session, pk.N, pk.V, c4, ci2, vi, a, b)
+}
+
+// ----- utils
+
+func factorial(n int) *big.Int {
+	return new(big.Int).MulRange(1, int64(n))
+}
+
+// evaluatePolynomial returns poly[0] + poly[1]*x + ... + poly[t]*x^t mod `mod`
+func evaluatePolynomial(poly []*big.Int, x, mod *big.Int) *big.Int {
+	modM := common.ModInt(mod)
+	result := new(big.Int)
+	for k := len(poly) - 1; 0 <= k; k-- {
+		result = modM.Add(modM.Mul(result, x), poly[k])
+	}
+	return result
+}
@@ -0,0 +1,96 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package threshold_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto/paillier"
+	. "github.com/kisdex/mpc-lib/crypto/threshold"
+)
+
+// a short modulus of safe primes, which are slow to find
+const testPaillierModulusLen = 512
+
+var Session = []byte("session")
+
+func TestPaillierThresholdDecryption(t *testing.T) {
+	sk, _, err := paillier.GenerateKeyPair(context.Background(), testPaillierModulusLen)
+	assert.NoError(t, err)
+	pk, shares, err := DealPaillier(sk, 2, 5)
+	assert.NoError(t, err)
+
+	// the plaintexts of two ciphertexts add up
+	m1, m2 := common.GetRandomPositiveInt(pk.N), common.GetRandomPositiveInt(pk.N)
+	c1, err := pk.Encrypt(m1)
+	assert.NoError(t, err)
+	c2, err := pk.Encrypt(m2)
+	assert.NoError(t, err)
+	c, err := pk.HomoAdd(c1, c2)
+	assert.NoError(t, err)
+
+	dss := make([]*PaillierDecryptionShare, len(shares))
+	for i, share := range shares {
+		dss[i], err = share.PartialDecrypt(Session, pk, c)
+		assert.NoError(t, err)
+		assert.True(t, pk.VerifyShare(Session, c, dss[i]))
+		assert.False(t, pk.VerifyShare([]byte("another session"), c, dss[i]))
+	}
+
+	// any 3 of the 5 shares decrypt
+	expected := common.ModInt(pk.N).Add(m1, m2)
+	for _, subset := range [][]int{{0, 1, 2}, {4, 2, 0}, {1, 3, 4}} {
+		subsetShares := make([]*PaillierDecryptionShare, 0, len(subset))
+		for _, i := range subset {
+			subsetShares = append(subsetShares, dss[i])
+		}
+		m, err := pk.Combine(Session, c, subsetShares)
+		if assert.NoError(t, err) {
+			assert.Equal(t, 0, expected.Cmp(m))
+		}
+	}
+
+	_, err = pk.Combine(Session, c, dss[:2])
+	assert.Error(t, err)
+	_, err = pk.Combine(Session, c, []*PaillierDecryptionShare{dss[0], dss[1], dss[1]})
+	assert.Error(t, err)
+}
+
+func TestPaillierThresholdDecryptionBadShare(t *testing.T) {
+	sk, _, err := paillier.GenerateKeyPair(context.Background(), testPaillierModulusLen)
+	assert.NoError(t, err)
+	pk, shares, err := DealPaillier(sk, 1, 3)
+	assert.NoError(t, err)
+	c, err := pk.Encrypt(big.NewInt(42))
+	assert.NoError(t, err)
+
+	ds0, err := shares[0].PartialDecrypt(Session, pk, c)
+	assert.NoError(t, err)
+	ds1, err := shares[1].PartialDecrypt(Session, pk, c)
+	assert.NoError(t, err)
+
+	// a share of another party's index, or a tampered share, does not verify
+	ds1.Index = 3
+	assert.False(t, pk.VerifyShare(Session, c, ds1))
+	ds1.Index = 2
+	ds1.Ci = common.ModInt(pk.NSquare()).Mul(ds1.Ci, pk.V)
+	assert.False(t, pk.VerifyShare(Session, c, ds1))
+	_, err = pk.Combine(Session, c, []*PaillierDecryptionShare{ds0, ds1})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "party 2")
+	}
+
+	// the factors of the key must be safe primes
+	P, Q := big.NewInt(1019), big.NewInt(1031)
+	_, _, err = DealPaillier(&paillier.PrivateKey{P: P, Q: Q}, 1, 3)
+	assert.Error(t, err)
+}
@@ -112,6 +112,70 @@ func (share *Share) Verify(ec elliptic.Curve, threshold int, vs Vs) bool {
 	return sigmaGi.Equals(v)
 }
 
+// CreateZeroSharing returns shares of the secret 0, as used to re-randomize existing shares without changing the secret.
+// The returned Vs holds v1..vt only: v0 = 0*G is the point at infinity, which an ECPoint cannot represent.
+func CreateZeroSharing(ec elliptic.Curve, threshold int, indexes []*big.Int) (Vs, Shares, error) {
+	if indexes == nil {
+		return nil, nil, errors.New("vss indexes == nil")
+	}
+	if threshold < 1 {
+		return nil, nil, errors.New("vss threshold < 1")
+	}
+	ids, err := CheckIndexes(ec, indexes)
+	if err != nil {
+		return nil, nil, err
+	}
+	num := len(indexes)
+	if num < threshold {
+		return nil, nil, ErrNumSharesBelowThreshold
+	}
+
+	poly := samplePolynomial(ec, threshold, zero)
+	v := make(Vs, threshold)
+	for i := 1; i <= threshold; i++ {
+		v[i-1] = crypto.ScalarBaseMult(ec, poly[i])
+	}
+
+	shares := make(Shares, num)
+	for i := 0; i < num; i++ {
+		share := evaluatePolynomial(ec, threshold, poly, ids[i])
+		shares[i] = &Share{Threshold: threshold, ID: ids[i], Share: share}
+	}
+	return v, shares, nil
+}
+
+// ZeroSharingPublicShare returns share*G for the party with the given id, from the commitments v1..vt of CreateZeroSharing.
+func ZeroSharingPublicShare(ec elliptic.Curve, vs Vs, id *big.Int) (*crypto.ECPoint, error) {
+	if len(vs) < 1 || id == nil {
+		return nil, errors.New("vss commitments or id are missing")
+	}
+	var err error
+	modQ := common.ModInt(ec.Params().N)
+	t := new(big.Int).Mod(id, ec.Params().N)
+	v := vs[0].SetCurve(ec).ScalarMult(t)
+	for j := 1; j < len(vs); j++ {
+		t = modQ.Mul(t, id)
+		vjt := vs[j].SetCurve(ec).ScalarMult(t)
+		if v, err = v.Add(vjt); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+// VerifyZeroSharing checks a share of the secret 0 against the commitments v1..vt of CreateZeroSharing.
+func (share *Share) VerifyZeroSharing(ec elliptic.Curve, threshold int, vs Vs) bool {
+	if share.Threshold != threshold || len(vs) != threshold {
+		return false
+	}
+	v, err := ZeroSharingPublicShare(ec, vs, share.ID)
+	if err != nil {
+		return false
+	}
+	sigmaGi := crypto.ScalarBaseMult(ec, share.Share)
+	return sigmaGi.Equals(v)
+}
+
 func (shares Shares) ReConstruct(ec elliptic.Curve) (secret *big.Int, err error) {
 	if shares != nil && shares[0].Threshold > len(shares) {
 		return nil, ErrNumSharesBelowThreshold
@@ -77,17 +77,17 @@ func MakeY(Y, q, N *big.Int) (*big.Int, error) {
 // C : ciphertext under pk
 func NewAffGInvWitness(
 	ec elliptic.Curve,
-	sk1 *paillier.PrivateKey,
+	sk1 paillier.Decrypter,
 	pk0 *paillier.PublicKey,
 	x, y, C *big.Int,
 ) (*AffGInvWitness, *AffGInvStatement, error) {
 	q := ec.Params().N
 
-	Y, _, err := sk1.PublicKey.EncryptAndReturnRandomness(y)
+	Y, _, err := sk1.Public().EncryptAndReturnRandomness(y)
 	if err != nil {
 		return nil, nil, err
 	}
-	Yp, err := MakeY(Y, q, sk1.PublicKey.N)
+	Yp, err := MakeY(Y, q, sk1.Public().N)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -102,7 +102,7 @@ func NewAffGInvWitness(
 	if err != nil {
 		return nil, nil, err
 	}
-	D := ATimesBToTheCModN(Dprime, C, x, pk0.NSquare())
+	D := common.ModInt(pk0.NSquare()).Mul(Dprime, common.ModInt(pk0.NSquare()).SecretExp(C, x, nil))
 
 	statement := &AffGInvStatement{
 		AffGStatement{
@@ -111,7 +111,7 @@ func NewAffGInvWitness(
 			X:        crypto.ScalarBaseMult(ec, x),
 			Y:        Y,
 			N0:       pk0.N,
-			N1:       sk1.PublicKey.N,
+			N1:       sk1.Public().N,
 			Ell:      GetEll(ec),
 			EllPrime: GetEll(ec),
 		},
@@ -159,19 +159,19 @@ func (wit *AffGInvWitness) ToAffGWitness(stmt *AffGInvStatement) *AffGWitness {
 }
 
 // aff-g from CGG21 Section 6.2 Figure 15.
-func NewAffGInvProof(wit *AffGInvWitness, stmt *AffGInvStatement, rp *RingPedersenParams) (*AffGInvProof, error) {
+func NewAffGInvProof(Session []byte, wit *AffGInvWitness, stmt *AffGInvStatement, rp *RingPedersenParams) (*AffGInvProof, error) {
 	gwit := wit.ToAffGWitness(stmt)
 	gstmt, err := stmt.ToAffGStatement()
 	if err != nil {
 		return nil, err
 	}
 
-	gproof, err := NewAffGProof(gwit, gstmt, rp)
+	gproof, err := NewAffGProof(Session, gwit, gstmt, rp)
 	return gproof.ToAffGInvProof(), err
 }
 
 // aff-g from CGG21 Section 6.2 Figure 15.
-func (proof *AffGInvProof) Verify(stmt *AffGInvStatement, rp *RingPedersenParams) bool {
+func (proof *AffGInvProof) Verify(Session []byte, stmt *AffGInvStatement, rp *RingPedersenParams) bool {
 	if proof == nil {
 		return false
 	}
@@ -182,7 +182,22 @@ func (proof *AffGInvProof) Verify(stmt *AffGInvStatement, rp *RingPedersenParams
 		return false
 	}
 
-	return gproof.Verify(gstmt, rp)
+	return gproof.Verify(Session, gstmt, rp)
+}
+
+// VerifyBatch is Verify with the ring-Pedersen equations of the proof deferred to `batch`
+func (proof *AffGInvProof) VerifyBatch(Session []byte, stmt *AffGInvStatement, batch *RingPedersenBatch, prover int) bool {
+	if proof == nil {
+		return false
+	}
+
+	gproof := &proof.AffGProof
+	gstmt, err := stmt.ToAffGStatement()
+	if err != nil {
+		return false
+	}
+
+	return gproof.VerifyBatch(Session, gstmt, batch, prover)
 }
 
 func (proof *AffGProof) ToAffGInvProof() *AffGInvProof {
@@ -39,7 +39,7 @@ import (
 )
 
 const (
-	AffGProofParts = 14
+	AffGProofParts = 13
 )
 
 // Note: (z,u,v) are lowercase in aff-g from CGG21 Section 6.2 Figure 15.
@@ -78,33 +78,33 @@ type AffGStatement struct {
 }
 
 // aff-g from CGG21 Section 6.2 Figure 15.
-func NewAffGProof(wit *AffGWitness, stmt *AffGStatement, rp *RingPedersenParams) (*AffGProof, error) {
+func NewAffGProof(Session []byte, wit *AffGWitness, stmt *AffGStatement, rp *RingPedersenParams) (*AffGProof, error) {
 	// derive some parameters
 	ec := stmt.X.Curve()
 	ecpc := NewEll(stmt.Ell)
 	ecpcprime := NewEll(stmt.EllPrime)
 
 	// 1. Prover samples alpha, beta, r, ry, gamma, m, delta, mu
-	alpha := common.GetRandomPositiveInt(ecpc.TwoPowEllPlusEpsilon)
-	beta := common.GetRandomPositiveInt(ecpcprime.TwoPowEllPlusEpsilon)
+	alpha := common.GetRandomSignedInt(ecpc.TwoPowEllPlusEpsilon)
+	beta := common.GetRandomSignedInt(ecpcprime.TwoPowEllPlusEpsilon)
 	r := common.GetRandomPositiveInt(stmt.N0)
 	ry := common.GetRandomPositiveInt(stmt.N1)
 	gammaRange := new(big.Int).Mul(ecpc.TwoPowEllPlusEpsilon, rp.N)
-	gamma := common.GetRandomPositiveInt(gammaRange)
+	gamma := common.GetRandomSignedInt(gammaRange)
 	mRange := new(big.Int).Mul(ecpc.TwoPowEll, rp.N)
-	m := common.GetRandomPositiveInt(mRange)
+	m := common.GetRandomSignedInt(mRange)
 	// CGG21 appears to have a typo - says delta and mu are chosen
 	// from ranges based on ell. This should be ell' as they are used with beta & y
 	deltaRange := new(big.Int).Mul(ecpcprime.TwoPowEllPlusEpsilon, rp.N)
-	delta := common.GetRandomPositiveInt(deltaRange)
+	delta := common.GetRandomSignedInt(deltaRange)
 	muRange := new(big.Int).Mul(ecpcprime.TwoPowEll, rp.N)
-	mu := common.GetRandomPositiveInt(muRange)
+	mu := common.GetRandomSignedInt(muRange)
 
 	// A = C^alpha * (1+N0)^beta * r^N0 mod N0^2
 	pkN0 := &paillier.PublicKey{N: stmt.N0}
 	N02 := new(big.Int).Mul(stmt.N0, stmt.N0)
 	Aprime := pkN0.EncryptWithRandomnessNoErrChk(beta, r)
-	A := ATimesBToTheCModN(Aprime, stmt.C, alpha, N02)
+	A := common.ModInt(N02).Mul(Aprime, common.ModInt(N02).SecretExp(stmt.C, alpha, nil))
 
 	// Bx=g^alpha
 	Bx := crypto.ScalarBaseMult(ec, alpha)
@@ -114,13 +114,13 @@ func NewAffGProof(wit *AffGWitness, stmt *AffGStatement, rp *RingPedersenParams)
 	By := pkN1.EncryptWithRandomnessNoErrChk(beta, ry)
 
 	// E = s^alpha t^gamma mod Nhat
-	E := rp.Commit(alpha, gamma)
+	E := rp.CommitSecret(alpha, gamma)
 	// S = s^x t^m mod Nhat
-	S := rp.Commit(wit.X, m)
+	S := rp.CommitSecret(wit.X, m)
 	// F = s^beta t^delta mod Nhat
-	F := rp.Commit(beta, delta)
+	F := rp.CommitSecret(beta, delta)
 	// T = s^y t^mu mod Nhat
-	T := rp.Commit(wit.Y, mu)
+	T := rp.CommitSecret(wit.Y, mu)
 
 	proof := &AffGProof{
 		A:  A,
@@ -132,7 +132,7 @@ func NewAffGProof(wit *AffGWitness, stmt *AffGStatement, rp *RingPedersenParams)
 		T:  T,
 	}
 	// 2. hash to get challenge
-	e := proof.GetChallenge(stmt, rp)
+	e := proof.GetChallenge(Session, stmt, rp)
 
 	// 3. prover sends (z1, z2, z3)
 	// z1 := alpha + e * x
@@ -158,12 +158,35 @@ func NewAffGProof(wit *AffGWitness, stmt *AffGStatement, rp *RingPedersenParams)
 
 // aff-g from CGG21 Section 6.2 Figure 15.
 // The Verifier checks the proof against the statement (N0, C, X)
-func (proof *AffGProof) Verify(stmt *AffGStatement, rp *RingPedersenParams) bool {
+func (proof *AffGProof) Verify(Session []byte, stmt *AffGStatement, rp *RingPedersenParams) bool {
+	return proof.verify(Session, stmt, rp, rp.check)
+}
+
+// VerifyBatch is Verify with the ring-Pedersen equations of the proof deferred to `batch`, whose Verify must not
+// blame `prover` for the proof to be valid
+func (proof *AffGProof) VerifyBatch(Session []byte, stmt *AffGStatement, batch *RingPedersenBatch, prover int) bool {
+	return proof.verify(Session, stmt, batch.rp, batch.checker(prover))
+}
+
+func (proof *AffGProof) verify(Session []byte, stmt *AffGStatement, rp *RingPedersenParams, check rpCheck) bool {
 	if proof == nil {
 		return false
 	}
 
-	if stmt.N0.Sign() != 1 && stmt.N1.Sign() != 1 {
+	if stmt.N0.Sign() != 1 || stmt.N1.Sign() != 1 {
+		return false
+	}
+	pkN0, pkN1 := &paillier.PublicKey{N: stmt.N0}, &paillier.PublicKey{N: stmt.N1}
+	if !pkN0.ValidateCiphertexts(stmt.C, stmt.D, proof.A) || !pkN1.ValidateCiphertexts(stmt.Y, proof.By) ||
+		!rp.ValidateCommitments(proof.E, proof.S, proof.F, proof.T) || !crypto.ValidatePoints(stmt.X, proof.Bx) {
+		return false
+	}
+
+	// Check z1 in [-2^{ell+epsilon}...+2^{ell+epsilon}], z2 in [-2^{ellprime+epsilon}...+2^{ellprime+epsilon}] and
+	// z3, z4 in the same ranges times Nhat before they are used as exponents
+	ecpc, ecpcprime := NewEll(stmt.Ell), NewEll(stmt.EllPrime)
+	if !ecpc.InRange(proof.Z1) || !ecpcprime.InRange(proof.Z2) ||
+		!ecpc.InRangeNhat(proof.Z3, rp.N) || !ecpcprime.InRangeNhat(proof.Z4, rp.N) {
 		return false
 	}
 
@@ -171,7 +194,7 @@ func (proof *AffGProof) Verify(stmt *AffGStatement, rp *RingPedersenParams) bool
 	ec := stmt.X.Curve()
 
 	// hash to get challenge
-	e := proof.GetChallenge(stmt, rp)
+	e := proof.GetChallenge(Session, stmt, rp)
 
 	// otherwise first verification equation trivially true
 	if IsZero(proof.A) || IsZero(proof.W) {
@@ -179,8 +202,7 @@ func (proof *AffGProof) Verify(stmt *AffGStatement, rp *RingPedersenParams) bool
 	}
 
 	// check C^z1 (1+n0)^z2 w^N0 == A * D^e mod No^2A
-	N02 := new(big.Int).Mul(stmt.N0, stmt.N0)
-	pkN0 := &paillier.PublicKey{N: stmt.N0}
+	N02 := pkN0.NSquare()
 	encZ2 := pkN0.EncryptWithRandomnessNoErrChk(proof.Z2, proof.W)
 	left1 := ATimesBToTheCModN(encZ2, stmt.C, proof.Z1, N02)
 	right1 := ATimesBToTheCModN(proof.A, stmt.D, e, N02)
@@ -201,8 +223,7 @@ func (proof *AffGProof) Verify(stmt *AffGStatement, rp *RingPedersenParams) bool
 	}
 
 	// check if (1+N1)^z2 * wy^N1 == By * Y^e mod N1^2
-	N12 := new(big.Int).Mul(stmt.N1, stmt.N1)
-	pkN1 := &paillier.PublicKey{N: stmt.N1}
+	N12 := pkN1.NSquare()
 	left3 := pkN1.EncryptWithRandomnessNoErrChk(proof.Z2, proof.Wy)
 	right3 := ATimesBToTheCModN(proof.By, stmt.Y, e, N12)
 	if left3.Cmp(right3) != 0 {
@@ -210,36 +231,20 @@ func (proof *AffGProof) Verify(stmt *AffGStatement, rp *RingPedersenParams) bool
 	}
 
 	// check if s^z1 * t^z3 == E * S^e mod Nhat
-	left4 := rp.Commit(proof.Z1, proof.Z3)
-	right4 := ATimesBToTheCModN(proof.E, proof.S, e, rp.N)
-	if left4.Cmp(right4) != 0 {
+	if !check(proof.Z1, proof.Z3, proof.E, proof.S, e) {
 		return false
 	}
 
 	// check if s^z2 * t^z4 == F*T^e mod Nhat
-	left5 := rp.Commit(proof.Z2, proof.Z4)
-	right5 := ATimesBToTheCModN(proof.F, proof.T, e, rp.N)
-	if left5.Cmp(right5) != 0 {
-		return false
-	}
-
-	// Check z1 in [-2^{ell+epsilon}...+2^{ell+epsilon}]
-	if !NewEll(stmt.Ell).InRange(proof.Z1) {
-		return false
-	}
-
-	// Check z2 in [-2^{ellprime+epsilon}...+2^{ellprime+epsilon}]
-	if !NewEll(stmt.EllPrime).InRange(proof.Z2) {
+	if !check(proof.Z2, proof.Z4, proof.F, proof.T, e) {
 		return false
 	}
 
 	return true
 }
 
-func (proof *AffGProof) GetChallenge(stmt *AffGStatement, rp *RingPedersenParams) *big.Int {
-	ecParams := stmt.X.Curve().Params()
+func (proof *AffGProof) GetChallenge(Session []byte, stmt *AffGStatement, rp *RingPedersenParams) *big.Int {
 	msg := []*big.Int{
-		ecParams.Gx, ecParams.Gy, ecParams.N,
 		stmt.Ell, stmt.EllPrime,
 		stmt.N0, stmt.N1,
 		stmt.X.X(),
@@ -250,7 +255,7 @@ func (proof *AffGProof) GetChallenge(stmt *AffGStatement, rp *RingPedersenParams
 		rp.N, rp.S, rp.T,
 		proof.A, proof.Bx.X(), proof.Bx.Y(), proof.By, proof.E, proof.S, proof.F, proof.T,
 	}
-	e := common.SHA512_256i(msg...)
+	e := common.SHA512_256i_DOMAIN(domainAffG, Session, append(common.CurveInts(stmt.X.Curve()), msg...)...)
 	q := Q(stmt.X.Curve())
 	return common.RejectionSample(q, e)
 }
@@ -266,17 +271,16 @@ func (proof *AffGProof) Parts() int {
 func (proof *AffGProof) Bytes() [][]byte {
 	return [][]byte{
 		proof.A.Bytes(),
-		proof.Bx.X().Bytes(),
-		proof.Bx.Y().Bytes(),
+		proof.Bx.Bytes(),
 		proof.By.Bytes(),
 		proof.E.Bytes(),
 		proof.S.Bytes(),
 		proof.F.Bytes(),
 		proof.T.Bytes(),
-		proof.Z1.Bytes(),
-		proof.Z2.Bytes(),
-		proof.Z3.Bytes(),
-		proof.Z4.Bytes(),
+		common.SignedIntBytes(proof.Z1),
+		common.SignedIntBytes(proof.Z2),
+		common.SignedIntBytes(proof.Z3),
+		common.SignedIntBytes(proof.Z4),
 		proof.W.Bytes(),
 		proof.Wy.Bytes(),
 	}
@@ -304,26 +308,27 @@ func (proof *AffGProof) ProofFromBytes(ec elliptic.Curve, bzs [][]byte) (Proof,
 	if !common.NonEmptyMultiBytes(bzs, AffGProofParts) {
 		return nil, fmt.Errorf("expected %d byte parts to construct AffGProof", AffGProofParts)
 	}
-	Bx, err := crypto.NewECPoint(
-		ec,
-		new(big.Int).SetBytes(bzs[1]),
-		new(big.Int).SetBytes(bzs[2]))
+	Bx, err := crypto.NewECPointFromBytes(ec, bzs[1])
 	if err != nil {
 		return nil, err
 	}
-	return &AffGProof{
+	pf := &AffGProof{
 		A:  new(big.Int).SetBytes(bzs[0]),
 		Bx: Bx,
-		By: new(big.Int).SetBytes(bzs[3]),
-		E:  new(big.Int).SetBytes(bzs[4]),
-		S:  new(big.Int).SetBytes(bzs[5]),
-		F:  new(big.Int).SetBytes(bzs[6]),
-		T:  new(big.Int).SetBytes(bzs[7]),
-		Z1: new(big.Int).SetBytes(bzs[8]),
-		Z2: new(big.Int).SetBytes(bzs[9]),
-		Z3: new(big.Int).SetBytes(bzs[10]),
-		Z4: new(big.Int).SetBytes(bzs[11]),
-		W:  new(big.Int).SetBytes(bzs[12]),
-		Wy: new(big.Int).SetBytes(bzs[13]),
-	}, nil
+		By: new(big.Int).SetBytes(bzs[2]),
+		E:  new(big.Int).SetBytes(bzs[3]),
+		S:  new(big.Int).SetBytes(bzs[4]),
+		F:  new(big.Int).SetBytes(bzs[5]),
+		T:  new(big.Int).SetBytes(bzs[6]),
+		Z1: common.SignedIntFromBytes(bzs[7]),
+		Z2: common.SignedIntFromBytes(bzs[8]),
+		Z3: common.SignedIntFromBytes(bzs[9]),
+		Z4: common.SignedIntFromBytes(bzs[10]),
+		W:  new(big.Int).SetBytes(bzs[11]),
+		Wy: new(big.Int).SetBytes(bzs[12]),
+	}
+	if pf.Z1 == nil || pf.Z2 == nil || pf.Z3 == nil || pf.Z4 == nil {
+		return nil, fmt.Errorf("invalid signed response in AffGProof")
+	}
+	return pf, nil
 }
@@ -27,7 +27,7 @@ import (
 	"github.com/kisdex/mpc-lib/crypto/zkproofs"
 )
 
-func GenerateAffGData(t *testing.T) (*zkproofs.AffGWitness, *zkproofs.AffGStatement) {
+func GenerateAffGData(t testing.TB) (*zkproofs.AffGWitness, *zkproofs.AffGStatement) {
 	N := publicKey.N
 	N2 := new(big.Int).Mul(N, N)
 
@@ -74,11 +74,11 @@ func TestAffGInvProof(t *testing.T) {
 
 	witness, statement, err := zkproofs.NewAffGInvWitness(ec, privateKey, publicKey, x, y, C)
 	assert.NoError(t, err)
-	proof, err := zkproofs.NewAffGInvProof(witness, statement, ringPedersen)
+	proof, err := zkproofs.NewAffGInvProof(Session, witness, statement, ringPedersen)
 	assert.NoError(t, err, "could not create NewAffGInvProof")
 	assert.NotNil(t, proof, "NewAffGInvProof nil")
 	assert.False(t, proof.IsNil(), "proof has nil fields")
-	assert.True(t, proof.Verify(statement, ringPedersen), "proof does not verify")
+	assert.True(t, proof.Verify(Session, statement, ringPedersen), "proof does not verify")
 
 	proofBytes := proof.Bytes()
 	var proofInBytes [][]byte = proofBytes[:]
@@ -87,27 +87,27 @@ func TestAffGInvProof(t *testing.T) {
 	assert.NoError(t, err, "could not create NewAffGProof")
 	assert.NotNil(t, newProof, "NewAffGProof nil")
 	assert.False(t, newProof.IsNil(), "proof has nil fields")
-	assert.True(t, newProof.Verify(statement, ringPedersen), "proof does not verify")
+	assert.True(t, newProof.Verify(Session, statement, ringPedersen), "proof does not verify")
 }
 
 func TestAffGProof(t *testing.T) {
 	setUp(t)
 	witness, statement := GenerateAffGData(t)
-	proof, err := zkproofs.NewAffGProof(witness, statement, ringPedersen)
+	proof, err := zkproofs.NewAffGProof(Session, witness, statement, ringPedersen)
 	assert.NoError(t, err, "could not create NewAffGProof")
 	assert.NotNil(t, proof, "NewAffGProof nil")
 	assert.False(t, proof.IsNil(), "proof has nil fields")
-	assert.True(t, proof.Verify(statement, ringPedersen), "proof does not verify")
+	assert.True(t, proof.Verify(Session, statement, ringPedersen), "proof does not verify")
 }
 
 func TestAffGProofBytes(t *testing.T) {
 	setUp(t)
 	witness, statement := GenerateAffGData(t)
-	proof, err := zkproofs.NewAffGProof(witness, statement, ringPedersen)
+	proof, err := zkproofs.NewAffGProof(Session, witness, statement, ringPedersen)
 	assert.NoError(t, err, "could not create NewAffGProof")
 	assert.NotNil(t, proof, "NewAffGProof nil")
 	assert.False(t, proof.IsNil(), "proof has nil fields")
-	assert.True(t, proof.Verify(statement, ringPedersen), "proof does not verify")
+	assert.True(t, proof.Verify(Session, statement, ringPedersen), "proof does not verify")
 
 	proofBytes := proof.Bytes()
 	var proofInBytes [][]byte = proofBytes[:]
@@ -116,14 +116,14 @@ func TestAffGProofBytes(t *testing.T) {
 	assert.NoError(t, err, "could not create NewAffGProof")
 	assert.NotNil(t, newProof, "NewAffGProof nil")
 	assert.False(t, newProof.IsNil(), "proof has nil fields")
-	assert.True(t, newProof.Verify(statement, ringPedersen), "proof does not verify")
+	assert.True(t, newProof.Verify(Session, statement, ringPedersen), "proof does not verify")
 }
 
 func TestAffGProofArrayBytes(t *testing.T) {
 	setUp(t)
 	witness, statement := GenerateAffGData(t)
 
-	proof, err := zkproofs.NewAffGProof(witness, statement, ringPedersen)
+	proof, err := zkproofs.NewAffGProof(Session, witness, statement, ringPedersen)
 	array := []*zkproofs.AffGProof{proof, proof, nil, proof}
 	bzs := zkproofs.ProofArrayToBytes(array)
 	out, err := zkproofs.ProofArrayFromBytes[*zkproofs.AffGProof](ec, bzs)
@@ -132,8 +132,8 @@ func TestAffGProofArrayBytes(t *testing.T) {
 	assert.NotNil(t, out[0])
 	assert.NotNil(t, out[1])
 	assert.NotNil(t, out[3])
-	assert.True(t, out[0].Verify(statement, ringPedersen))
-	assert.True(t, out[1].Verify(statement, ringPedersen))
+	assert.True(t, out[0].Verify(Session, statement, ringPedersen))
+	assert.True(t, out[1].Verify(Session, statement, ringPedersen))
 	assert.Nil(t, out[2])
-	assert.True(t, out[3].Verify(statement, ringPedersen))
+	assert.True(t, out[3].Verify(Session, statement, ringPedersen))
 }
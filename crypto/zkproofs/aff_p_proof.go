@@ -84,7 +84,7 @@ type AffPStatement struct {
 }
 
 // aff-p from CGG21 Appendix C.3 Figure 26
-func NewAffPProof(wit *AffPWitness, stmt *AffPStatement, rp *RingPedersenParams) (*AffPProof, error) {
+func NewAffPProof(Session []byte, wit *AffPWitness, stmt *AffPStatement, rp *RingPedersenParams) (*AffPProof, error) {
 	N02 := new(big.Int).Mul(stmt.N0, stmt.N0)
 	ecpc := NewEll(stmt.Ell)
 	ecpcprime := NewEll(stmt.EllPrime)
@@ -98,15 +98,15 @@ func NewAffPProof(wit *AffPWitness, stmt *AffPStatement, rp *RingPedersenParams)
 	}
 
 	// 1. Prover samples alpha, beta, r, rx, ry, gamma, m, delta, mu
-	alpha := common.GetRandomPositiveInt(ecpc.TwoPowEllPlusEpsilon)
-	beta := common.GetRandomPositiveInt(ecpcprime.TwoPowEllPlusEpsilon)
+	alpha := common.GetRandomSignedInt(ecpc.TwoPowEllPlusEpsilon)
+	beta := common.GetRandomSignedInt(ecpcprime.TwoPowEllPlusEpsilon)
 	r := common.GetRandomPositiveInt(stmt.N0)
 	rx := common.GetRandomPositiveInt(stmt.N1)
 	ry := common.GetRandomPositiveInt(stmt.N1)
 	gammaRange := new(big.Int).Mul(ecpc.TwoPowEllPlusEpsilon, rp.N)
-	gamma := common.GetRandomPositiveInt(gammaRange)
+	gamma := common.GetRandomSignedInt(gammaRange)
 	mRange := new(big.Int).Mul(ecpc.TwoPowEll, rp.N)
-	m := common.GetRandomPositiveInt(mRange)
+	m := common.GetRandomSignedInt(mRange)
 	// CGG21 has a typo: says
 	//    - sample delta from +-2^{ell+epsilon} * Nhat
 	//    - sample mu from +-2^{ell} * Nhat
@@ -114,43 +114,34 @@ func NewAffPProof(wit *AffPWitness, stmt *AffPStatement, rp *RingPedersenParams)
 	//    - sample delta from +-2^{ell'+epsilon} * Nhat
 	//    - sample mu from +-2^{ell'} * Nhat
 	deltaRange := new(big.Int).Mul(ecpcprime.TwoPowEllPlusEpsilon, rp.N)
-	delta := common.GetRandomPositiveInt(deltaRange)
+	delta := common.GetRandomSignedInt(deltaRange)
 	muRange := new(big.Int).Mul(ecpcprime.TwoPowEll, rp.N)
-	mu := common.GetRandomPositiveInt(muRange)
+	mu := common.GetRandomSignedInt(muRange)
 
 	// A = C^alpha * (1 + N0)^\beta *r^N0  mod N02
 	//   = C^alpha * Encrypt(N0, beta, r) mod N02
 	pkN0 := &paillier.PublicKey{N: stmt.N0}
-	Aprime, err := pkN0.EncryptWithRandomness(beta, r)
-	if err != nil {
-		return nil, errors.New("NewAffPProof: could not create A.")
-	}
-	A := ATimesBToTheCModN(Aprime, stmt.C, alpha, N02)
+	Aprime := pkN0.EncryptWithRandomnessNoErrChk(beta, r)
+	A := common.ModInt(N02).Mul(Aprime, common.ModInt(N02).SecretExp(stmt.C, alpha, nil))
 
 	// Bx = (1+N1)^alpha * rx^N1 mod N1^2
 	pkN1 := &paillier.PublicKey{N: stmt.N1}
-	Bx, err := pkN1.EncryptWithRandomness(alpha, rx)
-	if err != nil {
-		return nil, errors.New("NewAffPProof: could not create Bx.")
-	}
+	Bx := pkN1.EncryptWithRandomnessNoErrChk(alpha, rx)
 
 	// By = (1+N1)^beta * ry^N1 mod N1^2
-	By, err := pkN1.EncryptWithRandomness(beta, ry)
-	if err != nil {
-		return nil, errors.New("NewAffPProof: could not create By.")
-	}
+	By := pkN1.EncryptWithRandomnessNoErrChk(beta, ry)
 
 	// E = s^alpha * t^gamma mod Nhat
-	E := rp.Commit(alpha, gamma)
+	E := rp.CommitSecret(alpha, gamma)
 
 	// S = s^x * t^m mod Nhat
-	S := rp.Commit(wit.X, m)
+	S := rp.CommitSecret(wit.X, m)
 
 	// F = s^beta * t^delta mod Nhat
-	F := rp.Commit(beta, delta)
+	F := rp.CommitSecret(beta, delta)
 
 	// T = s^y * t^mu mod Nhat
-	T := rp.Commit(wit.Y, mu)
+	T := rp.CommitSecret(wit.Y, mu)
 
 	// 2. hash to get challenge
 	proof := &AffPProof{
@@ -162,7 +153,7 @@ func NewAffPProof(wit *AffPWitness, stmt *AffPStatement, rp *RingPedersenParams)
 		F:  F,
 		T:  T,
 	}
-	e := proof.GetChallenge(stmt, rp)
+	e := proof.GetChallenge(Session, stmt, rp)
 
 	// 3. prover sends (z1, z2, z3,z4, w, wx, wy)
 	// z1 := alpha + e * x
@@ -198,7 +189,17 @@ func NewAffPProof(wit *AffPWitness, stmt *AffPStatement, rp *RingPedersenParams)
 }
 
 // aff-p from CGG21 Appendix C.3 Figure 26
-func (proof *AffPProof) Verify(stmt *AffPStatement, rp *RingPedersenParams) bool {
+func (proof *AffPProof) Verify(Session []byte, stmt *AffPStatement, rp *RingPedersenParams) bool {
+	return proof.verify(Session, stmt, rp, rp.check)
+}
+
+// VerifyBatch is Verify with the ring-Pedersen equations of the proof deferred to `batch`, whose Verify must not
+// blame `prover` for the proof to be valid
+func (proof *AffPProof) VerifyBatch(Session []byte, stmt *AffPStatement, batch *RingPedersenBatch, prover int) bool {
+	return proof.verify(Session, stmt, batch.rp, batch.checker(prover))
+}
+
+func (proof *AffPProof) verify(Session []byte, stmt *AffPStatement, rp *RingPedersenParams, check rpCheck) bool {
 	N02 := new(big.Int).Mul(stmt.N0, stmt.N0)
 	N12 := new(big.Int).Mul(stmt.N1, stmt.N1)
 
@@ -208,9 +209,22 @@ func (proof *AffPProof) Verify(stmt *AffPStatement, rp *RingPedersenParams) bool
 	if stmt.N0.Sign() != 1 || stmt.N1.Sign() != 1 || rp.N.Sign() != 1 {
 		return false
 	}
+	pkN0, pkN1 := &paillier.PublicKey{N: stmt.N0}, &paillier.PublicKey{N: stmt.N1}
+	if !pkN0.ValidateCiphertexts(stmt.C, stmt.D, proof.A) || !pkN1.ValidateCiphertexts(stmt.X, stmt.Y, proof.Bx, proof.By) ||
+		!rp.ValidateCommitments(proof.E, proof.S, proof.F, proof.T) {
+		return false
+	}
+
+	// Check z1 in [-2^{ell+epsilon}...+2^{ell+epsilon}], z2 in [-2^{ell'+epsilon}...+2^{ell'+epsilon}] and z3, z4 in
+	// the same ranges times Nhat before they are used as exponents
+	ecpc, ecpcprime := NewEll(stmt.Ell), NewEll(stmt.EllPrime)
+	if !ecpc.InRange(proof.Z1) || !ecpcprime.InRange(proof.Z2) ||
+		!ecpc.InRangeNhat(proof.Z3, rp.N) || !ecpcprime.InRangeNhat(proof.Z4, rp.N) {
+		return false
+	}
 
 	// Get challenge
-	e := proof.GetChallenge(stmt, rp)
+	e := proof.GetChallenge(Session, stmt, rp)
 
 	// otherwise first verification equation trivially true
 	if IsZero(proof.W) || IsZero(proof.A) {
@@ -219,11 +233,10 @@ func (proof *AffPProof) Verify(stmt *AffPStatement, rp *RingPedersenParams) bool
 
 	// check C^z1 (1+N0)^z2 w^N0 mod N02 == A * D^e mod N02
 	// left1prime := (1+N0)^z1 w^N0 mod N02
-	pkN0 := &paillier.PublicKey{N: stmt.N0}
-	left1prime, err := pkN0.EncryptWithRandomness(proof.Z2, proof.W)
+	left1prime := pkN0.EncryptWithRandomnessNoErrChk(proof.Z2, proof.W)
 	left1 := ATimesBToTheCModN(left1prime, stmt.C, proof.Z1, N02)
 	right1 := ATimesBToTheCModN(proof.A, stmt.D, e, N02)
-	if err != nil || left1.Cmp(right1) != 0 {
+	if left1.Cmp(right1) != 0 {
 		return false
 	}
 
@@ -233,10 +246,9 @@ func (proof *AffPProof) Verify(stmt *AffPStatement, rp *RingPedersenParams) bool
 	}
 
 	// check (1+N1)^z1 wx^N1 mod N1^2 == Bx * X^e mod N1^2
-	pkN1 := &paillier.PublicKey{N: stmt.N1}
-	left2, err := pkN1.EncryptWithRandomness(proof.Z1, proof.Wx)
+	left2 := pkN1.EncryptWithRandomnessNoErrChk(proof.Z1, proof.Wx)
 	right2 := ATimesBToTheCModN(proof.Bx, stmt.X, e, N12)
-	if err != nil || left2.Cmp(right2) != 0 {
+	if left2.Cmp(right2) != 0 {
 		return false
 	}
 
@@ -246,40 +258,26 @@ func (proof *AffPProof) Verify(stmt *AffPStatement, rp *RingPedersenParams) bool
 	}
 
 	// check (1+N1)^z2 wy^N1 mod N1^2 == By * Y^e mod N1^2
-	left3, err := pkN1.EncryptWithRandomness(proof.Z2, proof.Wy)
+	left3 := pkN1.EncryptWithRandomnessNoErrChk(proof.Z2, proof.Wy)
 	right3 := ATimesBToTheCModN(proof.By, stmt.Y, e, N12)
-	if err != nil || left3.Cmp(right3) != 0 {
+	if left3.Cmp(right3) != 0 {
 		return false
 	}
 
 	// check s^z1 * t^z3 mod Nhat == E * S^e mod Nhat
-	left4 := rp.Commit(proof.Z1, proof.Z3)
-	right4 := ATimesBToTheCModN(proof.E, proof.S, e, rp.N)
-	if err != nil || left4.Cmp(right4) != 0 {
+	if !check(proof.Z1, proof.Z3, proof.E, proof.S, e) {
 		return false
 	}
 
 	// check s^z2 * t^z4 mod Nhat == F * T^e mod Nhat
-	left5 := rp.Commit(proof.Z2, proof.Z4)
-	right5 := ATimesBToTheCModN(proof.F, proof.T, e, rp.N)
-	if err != nil || left5.Cmp(right5) != 0 {
-		return false
-	}
-
-	// Check z1 in [-2^{ell+epsilon}...+2^{ell+epsilon}]
-	if !NewEll(stmt.Ell).InRange(proof.Z1) {
-		return false
-	}
-
-	// Check z2 in [-2^{ell'+epsilon}...+2^{ell'+epsilon}]
-	if !NewEll(stmt.EllPrime).InRange(proof.Z2) {
+	if !check(proof.Z2, proof.Z4, proof.F, proof.T, e) {
 		return false
 	}
 
 	return true
 }
 
-func (proof *AffPProof) GetChallenge(stmt *AffPStatement, rp *RingPedersenParams) *big.Int {
+func (proof *AffPProof) GetChallenge(Session []byte, stmt *AffPStatement, rp *RingPedersenParams) *big.Int {
 	q := stmt.EC.Params().N
 	// hash to get challenge
 	msg := []*big.Int{
@@ -288,7 +286,7 @@ func (proof *AffPProof) GetChallenge(stmt *AffPStatement, rp *RingPedersenParams
 		rp.N, rp.S, rp.T,
 		proof.A, proof.Bx, proof.By, proof.E, proof.S, proof.F, proof.T,
 	}
-	e := common.SHA512_256i(msg...)
+	e := common.SHA512_256i_DOMAIN(domainAffP, Session, append(common.CurveInts(stmt.EC), msg...)...)
 	return common.RejectionSample(q, e)
 }
 
@@ -309,10 +307,10 @@ func (proof *AffPProof) Bytes() [][]byte {
 		proof.S.Bytes(),
 		proof.F.Bytes(),
 		proof.T.Bytes(),
-		proof.Z1.Bytes(),
-		proof.Z2.Bytes(),
-		proof.Z3.Bytes(),
-		proof.Z4.Bytes(),
+		common.SignedIntBytes(proof.Z1),
+		common.SignedIntBytes(proof.Z2),
+		common.SignedIntBytes(proof.Z3),
+		common.SignedIntBytes(proof.Z4),
 		proof.W.Bytes(),
 		proof.Wx.Bytes(),
 		proof.Wy.Bytes(),
@@ -323,7 +321,7 @@ func (proof *AffPProof) ProofFromBytes(ec elliptic.Curve, bzs [][]byte) (Proof,
 	if !common.NonEmptyMultiBytes(bzs, AffPProofParts) {
 		return nil, fmt.Errorf("expected %d byte parts to construct AffPProof", AffPProofParts)
 	}
-	return &AffPProof{
+	pf := &AffPProof{
 		A:  new(big.Int).SetBytes(bzs[0]),
 		Bx: new(big.Int).SetBytes(bzs[1]),
 		By: new(big.Int).SetBytes(bzs[2]),
@@ -331,12 +329,16 @@ func (proof *AffPProof) ProofFromBytes(ec elliptic.Curve, bzs [][]byte) (Proof,
 		S:  new(big.Int).SetBytes(bzs[4]),
 		F:  new(big.Int).SetBytes(bzs[5]),
 		T:  new(big.Int).SetBytes(bzs[6]),
-		Z1: new(big.Int).SetBytes(bzs[7]),
-		Z2: new(big.Int).SetBytes(bzs[8]),
-		Z3: new(big.Int).SetBytes(bzs[9]),
-		Z4: new(big.Int).SetBytes(bzs[10]),
+		Z1: common.SignedIntFromBytes(bzs[7]),
+		Z2: common.SignedIntFromBytes(bzs[8]),
+		Z3: common.SignedIntFromBytes(bzs[9]),
+		Z4: common.SignedIntFromBytes(bzs[10]),
 		W:  new(big.Int).SetBytes(bzs[11]),
 		Wx: new(big.Int).SetBytes(bzs[12]),
 		Wy: new(big.Int).SetBytes(bzs[13]),
-	}, nil
+	}
+	if pf.Z1 == nil || pf.Z2 == nil || pf.Z3 == nil || pf.Z4 == nil {
+		return nil, fmt.Errorf("invalid signed response in AffPProof")
+	}
+	return pf, nil
 }
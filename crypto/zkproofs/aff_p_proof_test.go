@@ -26,7 +26,7 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func GenerateAffPData(t *testing.T) (*zkproofs.AffPWitness, *zkproofs.AffPStatement) {
+func GenerateAffPData(t testing.TB) (*zkproofs.AffPWitness, *zkproofs.AffPStatement) {
 	_, pk0, _, err := GetSavedKeys(0)
 	assert.NoError(t, err)
 	_, pk1, _, err := GetSavedKeys(1)
@@ -76,22 +76,22 @@ func TestAffPProof(t *testing.T) {
 	setUp(t)
 	witness, statement := GenerateAffPData(t)
 
-	proof, err := zkproofs.NewAffPProof(witness, statement, ringPedersen)
+	proof, err := zkproofs.NewAffPProof(Session, witness, statement, ringPedersen)
 	assert.NoError(t, err)
 	assert.NotNil(t, proof)
 	assert.False(t, proof.IsNil())
-	assert.True(t, proof.Verify(statement, ringPedersen), "proof failed to verify")
+	assert.True(t, proof.Verify(Session, statement, ringPedersen), "proof failed to verify")
 }
 
 func TestAffPProofBytes(t *testing.T) {
 	setUp(t)
 	witness, statement := GenerateAffPData(t)
 
-	proof, err := zkproofs.NewAffPProof(witness, statement, ringPedersen)
+	proof, err := zkproofs.NewAffPProof(Session, witness, statement, ringPedersen)
 	assert.NoError(t, err)
 	assert.NotNil(t, proof)
 	assert.False(t, proof.IsNil())
-	assert.True(t, proof.Verify(statement, ringPedersen), "proof failed to verify")
+	assert.True(t, proof.Verify(Session, statement, ringPedersen), "proof failed to verify")
 
 	proofBytes := proof.Bytes()
 	var proofInBytes [][]byte = proofBytes[:]
@@ -100,7 +100,7 @@ func TestAffPProofBytes(t *testing.T) {
 	assert.NoError(t, err, "could not create NewAffPProof")
 	assert.NotNil(t, newProof, "NewAffPProof nil")
 	assert.False(t, newProof.IsNil(), "proof has nil fields")
-	assert.True(t, newProof.Verify(statement, ringPedersen), "proof does not verify")
+	assert.True(t, newProof.Verify(Session, statement, ringPedersen), "proof does not verify")
 	assert.Equal(t, 0, proof.A.Cmp(newProof.A))
 	assert.Equal(t, 0, proof.Bx.Cmp(newProof.Bx))
 	assert.Equal(t, 0, proof.By.Cmp(newProof.By))
@@ -121,7 +121,7 @@ func TestAffPProofArrayBytes(t *testing.T) {
 	setUp(t)
 	witness, statement := GenerateAffPData(t)
 
-	proof, err := zkproofs.NewAffPProof(witness, statement, ringPedersen)
+	proof, err := zkproofs.NewAffPProof(Session, witness, statement, ringPedersen)
 	array := []*zkproofs.AffPProof{proof, proof, nil, proof}
 	bzs := zkproofs.ProofArrayToBytes(array)
 	out, err := zkproofs.ProofArrayFromBytes[*zkproofs.AffPProof](ec, bzs)
@@ -130,8 +130,8 @@ func TestAffPProofArrayBytes(t *testing.T) {
 	assert.NotNil(t, out[0])
 	assert.NotNil(t, out[1])
 	assert.NotNil(t, out[3])
-	assert.True(t, out[0].Verify(statement, ringPedersen))
-	assert.True(t, out[1].Verify(statement, ringPedersen))
+	assert.True(t, out[0].Verify(Session, statement, ringPedersen))
+	assert.True(t, out[1].Verify(Session, statement, ringPedersen))
 	assert.Nil(t, out[2])
-	assert.True(t, out[3].Verify(statement, ringPedersen))
+	assert.True(t, out[3].Verify(Session, statement, ringPedersen))
 }
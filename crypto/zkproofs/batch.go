@@ -0,0 +1,119 @@
+// Copyright (c) 2023, Circle Internet Financial, LTD. All rights reserved.
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zkproofs
+
+import (
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/kisdex/mpc-lib/common"
+)
+
+// batchCoefficientBits is the size of the random coefficients of the linear combination of a batch; a batch with a
+// false equation passes with probability at most 2^-128
+const batchCoefficientBits = 128
+
+type (
+	// RingPedersenBatch collects the ring-Pedersen equations s^a * t^b == c * d^e mod Nhat of the proofs that a party
+	// verifies against its own ring-Pedersen parameters, to check them at once with a random linear combination.
+	// These equations are the most expensive part of the verification of the proofs of this package. The other
+	// equations of a proof are checked by its VerifyBatch method.
+	//
+	// Both sides of the combination are squared, so an equation is only checked up to its sign; a prover that does
+	// not know the factorization of Nhat cannot make use of the other square roots of 1.
+	RingPedersenBatch struct {
+		rp  *RingPedersenParams
+		mtx sync.Mutex
+		eqs []rpEquation
+	}
+
+	// rpEquation is the equation s^a * t^b == c * d^e mod Nhat of a proof of `prover`
+	rpEquation struct {
+		prover     int
+		a, b, c, d *big.Int
+		e          *big.Int
+	}
+
+	// rpCheck checks the equation s^a * t^b == c * d^e mod Nhat, or defers it to a batch
+	rpCheck func(a, b, c, d, e *big.Int) bool
+)
+
+// NewRingPedersenBatch returns an empty batch for the proofs verified against `rp`
+func NewRingPedersenBatch(rp *RingPedersenParams) *RingPedersenBatch {
+	return &RingPedersenBatch{rp: rp}
+}
+
+// Len returns the number of equations in the batch
+func (batch *RingPedersenBatch) Len() int {
+	batch.mtx.Lock()
+	defer batch.mtx.Unlock()
+	return len(batch.eqs)
+}
+
+// Verify checks the equations of the batch and returns nil if they hold. Otherwise it checks them one by one and
+// returns the provers of the equations that do not hold, in increasing order.
+func (batch *RingPedersenBatch) Verify() []int {
+	batch.mtx.Lock()
+	defer batch.mtx.Unlock()
+	if len(batch.eqs) == 0 {
+		return nil
+	}
+	modN := common.ModInt(batch.rp.N)
+	a, b, right := big.NewInt(0), big.NewInt(0), big.NewInt(1)
+	for _, eq := range batch.eqs {
+		r := common.MustGetRandomInt(batchCoefficientBits)
+		r.Lsh(r, 1)
+		a.Add(a, new(big.Int).Mul(r, eq.a))
+		b.Add(b, new(big.Int).Mul(r, eq.b))
+		cd := ATimesBToTheCModN(modN.Exp(eq.c, r), eq.d, new(big.Int).Mul(r, eq.e), batch.rp.N)
+		right = modN.Mul(right, cd)
+	}
+	if batch.rp.Commit(a, b).Cmp(right) == 0 {
+		return nil
+	}
+
+	failed := make(map[int]bool)
+	for _, eq := range batch.eqs {
+		if !failed[eq.prover] && !batch.rp.check(eq.a, eq.b, eq.c, eq.d, eq.e) {
+			failed[eq.prover] = true
+		}
+	}
+	provers := make([]int, 0, len(failed))
+	for prover := range failed {
+		provers = append(provers, prover)
+	}
+	sort.Ints(provers)
+	return provers
+}
+
+// checker adds the equations of a proof of `prover` to the batch
+func (batch *RingPedersenBatch) checker(prover int) rpCheck {
+	return func(a, b, c, d, e *big.Int) bool {
+		batch.mtx.Lock()
+		defer batch.mtx.Unlock()
+		batch.eqs = append(batch.eqs, rpEquation{prover: prover, a: a, b: b, c: c, d: d, e: e})
+		return true
+	}
+}
+
+// check checks the equation s^a * t^b == c * d^e mod Nhat
+func (rp *RingPedersenParams) check(a, b, c, d, e *big.Int) bool {
+	left := rp.Commit(a, b)
+	right := ATimesBToTheCModN(c, d, e, rp.N)
+	return left.Cmp(right) == 0
+}
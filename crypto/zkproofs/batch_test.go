@@ -0,0 +1,67 @@
+// Copyright (c) 2023, Circle Internet Financial, LTD. All rights reserved.
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zkproofs_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto"
+	"github.com/kisdex/mpc-lib/crypto/zkproofs"
+)
+
+func TestRingPedersenBatch(t *testing.T) {
+	setUp(t)
+
+	const provers = 3
+	statements := make([]*zkproofs.LogStarStatement, provers)
+	proofs := make([]*zkproofs.LogStarProof, provers)
+	for i := range proofs {
+		witness := &zkproofs.LogStarWitness{
+			X:   common.GetRandomPositiveInt(q),
+			Rho: common.GetRandomPositiveInt(publicKey.N),
+		}
+		C, err := publicKey.EncryptWithRandomness(witness.X, witness.Rho)
+		assert.NoError(t, err)
+		statements[i] = &zkproofs.LogStarStatement{
+			Ell: ell,
+			N0:  publicKey.N,
+			C:   C,
+			X:   crypto.ScalarBaseMult(ec, witness.X),
+		}
+		proofs[i] = zkproofs.NewLogStarProof(Session, witness, statements[i], ringPedersen)
+	}
+
+	batch := zkproofs.NewRingPedersenBatch(ringPedersen)
+	for i, proof := range proofs {
+		assert.True(t, proof.VerifyBatch(Session, statements[i], batch, i))
+	}
+	assert.Equal(t, provers, batch.Len())
+	assert.Nil(t, batch.Verify())
+
+	// z3 only appears in the ring-Pedersen equation, so the other checks of the proof still pass
+	proofs[1].Z3 = new(big.Int).Add(proofs[1].Z3, big.NewInt(1))
+	assert.False(t, proofs[1].Verify(Session, statements[1], ringPedersen))
+	batch = zkproofs.NewRingPedersenBatch(ringPedersen)
+	for i, proof := range proofs {
+		assert.True(t, proof.VerifyBatch(Session, statements[i], batch, i))
+	}
+	assert.Equal(t, []int{1}, batch.Verify())
+}
@@ -61,25 +61,25 @@ type DecWitness struct {
 }
 
 // dec in CGG21 Appendix C6 Figure 30.
-func NewDecProof(wit *DecWitness, stmt *DecStatement, rp *RingPedersenParams) *DecProof {
+func NewDecProof(Session []byte, wit *DecWitness, stmt *DecStatement, rp *RingPedersenParams) *DecProof {
 	// derive some parameters
 	ecpc := NewEll(stmt.Ell)
 
 	// 1. Prover samples alpha, mu, r, gamma
-	alpha := common.GetRandomPositiveInt(ecpc.TwoPowEllPlusEpsilon)
+	alpha := common.GetRandomSignedInt(ecpc.TwoPowEllPlusEpsilon)
 	muRange := new(big.Int).Mul(ecpc.TwoPowEll, rp.N)
-	mu := common.GetRandomPositiveInt(muRange)
+	mu := common.GetRandomSignedInt(muRange)
 	nuRange := new(big.Int).Mul(ecpc.TwoPowEllPlusEpsilon, rp.N)
-	nu := common.GetRandomPositiveInt(nuRange)
+	nu := common.GetRandomSignedInt(nuRange)
 	// CGG21 has typo - says sample from Z*_N (where N is undefined)
 	// It should be Z*_N0  because it is used to compute A as a Paillier cyphertext.
 	r := common.GetRandomPositiveInt(stmt.N0)
 
 	// S=s^y *t^mu mod Nhat
-	S := rp.Commit(wit.Y, mu)
+	S := rp.CommitSecret(wit.Y, mu)
 
 	// T = s^alpha * t^nu mod Nhat
-	T := rp.Commit(alpha, nu)
+	T := rp.CommitSecret(alpha, nu)
 
 	//A = (1+N0)^alpha * r^N0 mod N02
 	// we can ignore error when encrypting because we chose the range
@@ -97,7 +97,7 @@ func NewDecProof(wit *DecWitness, stmt *DecStatement, rp *RingPedersenParams) *D
 	}
 
 	// 2. hash to get challenge
-	e := proof.GetChallenge(stmt, rp)
+	e := proof.GetChallenge(Session, stmt, rp)
 
 	// 3. prover sends (z1, z2, w)
 	// z1 := alpha + e * y
@@ -113,7 +113,17 @@ func NewDecProof(wit *DecWitness, stmt *DecStatement, rp *RingPedersenParams) *D
 }
 
 // dec in CGG21 Appendix C6 Figure 30.
-func (proof *DecProof) Verify(stmt *DecStatement, rp *RingPedersenParams) bool {
+func (proof *DecProof) Verify(Session []byte, stmt *DecStatement, rp *RingPedersenParams) bool {
+	return proof.verify(Session, stmt, rp, rp.check)
+}
+
+// VerifyBatch is Verify with the ring-Pedersen equations of the proof deferred to `batch`, whose Verify must not
+// blame `prover` for the proof to be valid
+func (proof *DecProof) VerifyBatch(Session []byte, stmt *DecStatement, batch *RingPedersenBatch, prover int) bool {
+	return proof.verify(Session, stmt, batch.rp, batch.checker(prover))
+}
+
+func (proof *DecProof) verify(Session []byte, stmt *DecStatement, rp *RingPedersenParams, check rpCheck) bool {
 	if proof == nil {
 		return false
 	}
@@ -121,9 +131,21 @@ func (proof *DecProof) Verify(stmt *DecStatement, rp *RingPedersenParams) bool {
 	if stmt.N0.Sign() != 1 {
 		return false
 	}
+	pkN0 := &paillier.PublicKey{N: stmt.N0}
+	if !pkN0.ValidateCiphertexts(stmt.C, proof.A) || !rp.ValidateCommitments(proof.S, proof.T) {
+		return false
+	}
+
+	// Check z1 in +-(2^{ell+epsilon} + q*N0), as y is a plaintext mod N0, and z2 in +-2^{ell+epsilon}*Nhat before
+	// they are used as exponents
+	ecpc := NewEll(stmt.Ell)
+	z1Bound := new(big.Int).Add(ecpc.TwoPowEllPlusEpsilon, new(big.Int).Mul(stmt.Q, stmt.N0))
+	if !common.IsInSymmetricInterval(proof.Z1, z1Bound) || !ecpc.InRangeNhat(proof.Z2, rp.N) {
+		return false
+	}
 
 	// hash to get challenge
-	e := proof.GetChallenge(stmt, rp)
+	e := proof.GetChallenge(Session, stmt, rp)
 
 	// otherwise first verification equation trivially true
 	if IsZero(proof.W) || IsZero(proof.A) {
@@ -131,7 +153,6 @@ func (proof *DecProof) Verify(stmt *DecStatement, rp *RingPedersenParams) bool {
 	}
 
 	// check (1+N0)^z1 * w^N0 mod N02 == A * C^e mod N02
-	pkN0 := &paillier.PublicKey{N: stmt.N0}
 	left1 := pkN0.EncryptWithRandomnessNoErrChk(proof.Z1, proof.W)
 	right1 := ATimesBToTheCModN(proof.A, stmt.C, e, pkN0.NSquare())
 	if left1.Cmp(right1) != 0 {
@@ -147,19 +168,17 @@ func (proof *DecProof) Verify(stmt *DecStatement, rp *RingPedersenParams) bool {
 	}
 
 	// check s^z1 * t^z2 == T * S^e mod Nhat
-	left3 := rp.Commit(proof.Z1, proof.Z2)
-	right3 := ATimesBToTheCModN(proof.T, proof.S, e, rp.N)
-	if left3.Cmp(right3) != 0 {
+	if !check(proof.Z1, proof.Z2, proof.T, proof.S, e) {
 		return false
 	}
 
 	return true
 }
 
-func (proof *DecProof) GetChallenge(stmt *DecStatement, rp *RingPedersenParams) *big.Int {
+func (proof *DecProof) GetChallenge(Session []byte, stmt *DecStatement, rp *RingPedersenParams) *big.Int {
 	msg := []*big.Int{stmt.Ell, stmt.Q, stmt.N0, stmt.C, stmt.X, rp.N, rp.S, rp.T, proof.S, proof.T, proof.A, proof.Gamma}
-	e := common.SHA512_256i(msg...)
-	return e
+	e := common.SHA512_256i_DOMAIN(domainDec, Session, msg...)
+	return common.RejectionSample(stmt.Q, e)
 }
 
 func (proof *DecProof) Nil() bool {
@@ -186,8 +205,8 @@ func (proof *DecProof) Bytes() [][]byte {
 		proof.T.Bytes(),
 		proof.A.Bytes(),
 		proof.Gamma.Bytes(),
-		proof.Z1.Bytes(),
-		proof.Z2.Bytes(),
+		common.SignedIntBytes(proof.Z1),
+		common.SignedIntBytes(proof.Z2),
 		proof.W.Bytes(),
 	}
 }
@@ -196,13 +215,17 @@ func (proof *DecProof) ProofFromBytes(ec elliptic.Curve, bzs [][]byte) (Proof, e
 	if !common.NonEmptyMultiBytes(bzs, DecProofParts) {
 		return nil, fmt.Errorf("expected %d byte parts to construct DecProof", DecProofParts)
 	}
-	return &DecProof{
+	pf := &DecProof{
 		S:     new(big.Int).SetBytes(bzs[0]),
 		T:     new(big.Int).SetBytes(bzs[1]),
 		A:     new(big.Int).SetBytes(bzs[2]),
 		Gamma: new(big.Int).SetBytes(bzs[3]),
-		Z1:    new(big.Int).SetBytes(bzs[4]),
-		Z2:    new(big.Int).SetBytes(bzs[5]),
+		Z1:    common.SignedIntFromBytes(bzs[4]),
+		Z2:    common.SignedIntFromBytes(bzs[5]),
 		W:     new(big.Int).SetBytes(bzs[6]),
-	}, nil
+	}
+	if pf.Z1 == nil || pf.Z2 == nil {
+		return nil, fmt.Errorf("invalid signed response in DecProof")
+	}
+	return pf, nil
 }
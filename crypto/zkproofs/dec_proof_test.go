@@ -49,10 +49,26 @@ func TestDecProof(t *testing.T) {
 	// Prove that:
 	// C = Encrypt(N0, x, rho)
 	// y = x mod q
-	proof := zkproofs.NewDecProof(witness, statement, ringPedersen)
+	proof := zkproofs.NewDecProof(Session, witness, statement, ringPedersen)
 	assert.NotNil(t, proof)
 	assert.False(t, proof.Nil())
-	assert.True(t, proof.Verify(statement, ringPedersen))
+	assert.True(t, proof.Verify(Session, statement, ringPedersen))
+}
+
+func TestDecProofResponseRange(t *testing.T) {
+	setUp(t)
+
+	witness, statement := GenerateDecProofData(t)
+	proof := zkproofs.NewDecProof(Session, witness, statement, ringPedersen)
+	assert.True(t, proof.Verify(Session, statement, ringPedersen))
+
+	// the responses of a peer are bounded before they are used as exponents, however long they are
+	huge := new(big.Int).Lsh(big.NewInt(1), 1<<20)
+	z1, z2 := proof.Z1, proof.Z2
+	proof.Z1 = new(big.Int).Add(z1, huge)
+	assert.False(t, proof.Verify(Session, statement, ringPedersen), "z1 must be range checked")
+	proof.Z1, proof.Z2 = z1, new(big.Int).Sub(z2, huge)
+	assert.False(t, proof.Verify(Session, statement, ringPedersen), "z2 must be range checked")
 }
 
 func TestDecSumProof(t *testing.T) {
@@ -89,13 +105,13 @@ func TestDecSumProof(t *testing.T) {
 	// Prove that:
 	// C = Encrypt(N0, x, rho)
 	// y = x mod q
-	proof := zkproofs.NewDecProof(witness, statement, ringPedersen)
+	proof := zkproofs.NewDecProof(Session, witness, statement, ringPedersen)
 	assert.NotNil(t, proof)
 	assert.False(t, proof.Nil())
-	assert.True(t, proof.Verify(statement, ringPedersen))
+	assert.True(t, proof.Verify(Session, statement, ringPedersen))
 }
 
-func GenerateDecProofData(t *testing.T) (*zkproofs.DecWitness, *zkproofs.DecStatement) {
+func GenerateDecProofData(t testing.TB) (*zkproofs.DecWitness, *zkproofs.DecStatement) {
 	y := common.GetRandomPositiveInt(publicKey.N)
 	x := new(big.Int).Mod(y, q)
 	witness := &zkproofs.DecWitness{
@@ -123,10 +139,10 @@ func TestDecProofBytes(t *testing.T) {
 	// Prove that:
 	// C = Encrypt(N0, x, rho)
 	// y = x mod q
-	proof := zkproofs.NewDecProof(witness, statement, ringPedersen)
+	proof := zkproofs.NewDecProof(Session, witness, statement, ringPedersen)
 	assert.NotNil(t, proof)
 	assert.False(t, proof.Nil())
-	assert.True(t, proof.Verify(statement, ringPedersen))
+	assert.True(t, proof.Verify(Session, statement, ringPedersen))
 
 	proofBytes := proof.Bytes()
 	var proofInBytes [][]byte = proofBytes[:]
@@ -135,13 +151,13 @@ func TestDecProofBytes(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, newProof)
 	assert.False(t, newProof.Nil())
-	assert.True(t, newProof.Verify(statement, ringPedersen))
+	assert.True(t, newProof.Verify(Session, statement, ringPedersen))
 }
 
 func TestDecProofArrayBytes(t *testing.T) {
 	setUp(t)
 	witness, statement := GenerateDecProofData(t)
-	proof := zkproofs.NewDecProof(witness, statement, ringPedersen)
+	proof := zkproofs.NewDecProof(Session, witness, statement, ringPedersen)
 
 	array := []*zkproofs.DecProof{proof, proof, nil, proof}
 	bzs := zkproofs.ProofArrayToBytes(array)
@@ -151,8 +167,8 @@ func TestDecProofArrayBytes(t *testing.T) {
 	assert.NotNil(t, out[0])
 	assert.NotNil(t, out[1])
 	assert.NotNil(t, out[3])
-	assert.True(t, out[0].Verify(statement, ringPedersen))
-	assert.True(t, out[1].Verify(statement, ringPedersen))
+	assert.True(t, out[0].Verify(Session, statement, ringPedersen))
+	assert.True(t, out[1].Verify(Session, statement, ringPedersen))
 	assert.Nil(t, out[2])
-	assert.True(t, out[3].Verify(statement, ringPedersen))
+	assert.True(t, out[3].Verify(Session, statement, ringPedersen))
 }
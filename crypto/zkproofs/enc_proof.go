@@ -58,7 +58,7 @@ type EncWitness struct {
 }
 
 // enc in CGG21 in CGG21 Section 6.1 Figure 14
-func NewEncProof(wit *EncWitness, stmt *EncStatement, rp *RingPedersenParams) (*EncProof, error) {
+func NewEncProof(Session []byte, wit *EncWitness, stmt *EncStatement, rp *RingPedersenParams) (*EncProof, error) {
 	// derive some parameters
 	ecpc := NewEll(GetEll(stmt.EC))
 	if !ecpc.InRangeEll(wit.K) {
@@ -66,28 +66,24 @@ func NewEncProof(wit *EncWitness, stmt *EncStatement, rp *RingPedersenParams) (*
 	}
 
 	// 1. Prover samples alpha, mu, r, gamma
-	alpha := common.GetRandomPositiveInt(ecpc.TwoPowEllPlusEpsilon)
+	alpha := common.GetRandomSignedInt(ecpc.TwoPowEllPlusEpsilon)
 	muRange := new(big.Int).Mul(ecpc.TwoPowEll, rp.N)
-	mu := common.GetRandomPositiveInt(muRange)
+	mu := common.GetRandomSignedInt(muRange)
 	// CGG21 has typo - says sample from Z*_N (where N is undefined)
 	// It should be Z*_N0  because it is used to compute A as a Paillier cypertext.
 	r := common.GetRandomPositiveInt(stmt.N0)
 	gammRange := new(big.Int).Mul(ecpc.TwoPowEllPlusEpsilon, rp.N)
-	gamma := common.GetRandomPositiveInt(gammRange)
+	gamma := common.GetRandomSignedInt(gammRange)
 
 	// S=s^k *t^mu mod Nhat
-	S := rp.Commit(wit.K, mu)
+	S := rp.CommitSecret(wit.K, mu)
 
 	//A = (1+N0)^alpha * r^N0 mod N02
-	// we can ignore error when encrypting because we chose the range
 	pkN0 := &paillier.PublicKey{N: stmt.N0}
-	A, err := pkN0.EncryptWithRandomness(alpha, r)
-	if err != nil {
-		return nil, err
-	}
+	A := pkN0.EncryptWithRandomnessNoErrChk(alpha, r)
 
 	// C=s^alpha *t^gamma mod Nhat
-	C := rp.Commit(alpha, gamma)
+	C := rp.CommitSecret(alpha, gamma)
 
 	proof := &EncProof{
 		S: S,
@@ -96,7 +92,7 @@ func NewEncProof(wit *EncWitness, stmt *EncStatement, rp *RingPedersenParams) (*
 	}
 
 	// 2. hash to get challenge
-	e := proof.GetChallenge(stmt, rp)
+	e := proof.GetChallenge(Session, stmt, rp)
 
 	// 3. prover sends (z1, z2, z3)
 	// z1 := alpha + e * k
@@ -117,7 +113,17 @@ func NewEncProof(wit *EncWitness, stmt *EncStatement, rp *RingPedersenParams) (*
 
 // enc in CGG21 in CGG21 Section 6.1 Figure 14
 // The Verifier checks the proof against the statement (N0, K)
-func (proof *EncProof) Verify(stmt *EncStatement, rp *RingPedersenParams) bool {
+func (proof *EncProof) Verify(Session []byte, stmt *EncStatement, rp *RingPedersenParams) bool {
+	return proof.verify(Session, stmt, rp, rp.check)
+}
+
+// VerifyBatch is Verify with the ring-Pedersen equations of the proof deferred to `batch`, whose Verify must not
+// blame `prover` for the proof to be valid
+func (proof *EncProof) VerifyBatch(Session []byte, stmt *EncStatement, batch *RingPedersenBatch, prover int) bool {
+	return proof.verify(Session, stmt, batch.rp, batch.checker(prover))
+}
+
+func (proof *EncProof) verify(Session []byte, stmt *EncStatement, rp *RingPedersenParams, check rpCheck) bool {
 	if proof == nil {
 		return false
 	}
@@ -125,9 +131,20 @@ func (proof *EncProof) Verify(stmt *EncStatement, rp *RingPedersenParams) bool {
 	if stmt.N0.Sign() != 1 {
 		return false
 	}
+	pkN0 := &paillier.PublicKey{N: stmt.N0}
+	if !pkN0.ValidateCiphertexts(stmt.K, proof.A) || !rp.ValidateCommitments(proof.S, proof.C) {
+		return false
+	}
+
+	// Check z1 in [-2^{ell+epsilon}...+2^{ell+epsilon}] and z3 in [-2^{ell+epsilon}*Nhat...+2^{ell+epsilon}*Nhat]
+	// before they are used as exponents
+	ecpc := NewEll(GetEll(stmt.EC))
+	if !ecpc.InRange(proof.Z1) || !ecpc.InRangeNhat(proof.Z3, rp.N) {
+		return false
+	}
 
 	// hash to get challenge
-	e := proof.GetChallenge(stmt, rp)
+	e := proof.GetChallenge(Session, stmt, rp)
 
 	// otherwise first verification equation trivially true
 	if IsZero(proof.Z2) || IsZero(proof.A) {
@@ -135,8 +152,7 @@ func (proof *EncProof) Verify(stmt *EncStatement, rp *RingPedersenParams) bool {
 	}
 
 	// check (1+N0)^z1 * z2^N0 mod N02 == A * K^e mod N02
-	N02 := new(big.Int).Mul(stmt.N0, stmt.N0)
-	pkN0 := &paillier.PublicKey{N: stmt.N0}
+	N02 := pkN0.NSquare()
 	left1 := pkN0.EncryptWithRandomnessNoErrChk(proof.Z1, proof.Z2)
 	right1 := ATimesBToTheCModN(proof.A, stmt.K, e, N02)
 	if left1.Cmp(right1) != 0 {
@@ -144,24 +160,17 @@ func (proof *EncProof) Verify(stmt *EncStatement, rp *RingPedersenParams) bool {
 	}
 
 	// check s^z1 * t^z3 == C * S^e mod Nhat
-	left2 := rp.Commit(proof.Z1, proof.Z3)
-	right2 := ATimesBToTheCModN(proof.C, proof.S, e, rp.N)
-	if left2.Cmp(right2) != 0 {
-		return false
-	}
-
-	// Check z1 in [-2^{ell+epsilon}...+2^{ell+epsilon}]
-	if !NewEll(GetEll(stmt.EC)).InRange(proof.Z1) {
+	if !check(proof.Z1, proof.Z3, proof.C, proof.S, e) {
 		return false
 	}
 
 	return true
 }
 
-func (proof *EncProof) GetChallenge(stmt *EncStatement, rp *RingPedersenParams) *big.Int {
+func (proof *EncProof) GetChallenge(Session []byte, stmt *EncStatement, rp *RingPedersenParams) *big.Int {
 	q := stmt.EC.Params().N
-	msg := []*big.Int{q, stmt.N0, stmt.K, rp.N, rp.S, rp.T, proof.S, proof.A, proof.C}
-	e := common.SHA512_256i(msg...)
+	msg := []*big.Int{stmt.N0, stmt.K, rp.N, rp.S, rp.T, proof.S, proof.A, proof.C}
+	e := common.SHA512_256i_DOMAIN(domainEnc, Session, append(common.CurveInts(stmt.EC), msg...)...)
 	return common.RejectionSample(q, e)
 }
 
@@ -188,9 +197,9 @@ func (proof *EncProof) Bytes() [][]byte {
 		proof.S.Bytes(),
 		proof.A.Bytes(),
 		proof.C.Bytes(),
-		proof.Z1.Bytes(),
+		common.SignedIntBytes(proof.Z1),
 		proof.Z2.Bytes(),
-		proof.Z3.Bytes(),
+		common.SignedIntBytes(proof.Z3),
 	}
 }
 
@@ -198,12 +207,16 @@ func (proof *EncProof) ProofFromBytes(ec elliptic.Curve, bzs [][]byte) (Proof, e
 	if !common.NonEmptyMultiBytes(bzs, EncProofParts) {
 		return nil, fmt.Errorf("expected %d byte parts to construct EncProof", EncProofParts)
 	}
-	return &EncProof{
+	pf := &EncProof{
 		S:  new(big.Int).SetBytes(bzs[0]),
 		A:  new(big.Int).SetBytes(bzs[1]),
 		C:  new(big.Int).SetBytes(bzs[2]),
-		Z1: new(big.Int).SetBytes(bzs[3]),
+		Z1: common.SignedIntFromBytes(bzs[3]),
 		Z2: new(big.Int).SetBytes(bzs[4]),
-		Z3: new(big.Int).SetBytes(bzs[5]),
-	}, nil
+		Z3: common.SignedIntFromBytes(bzs[5]),
+	}
+	if pf.Z1 == nil || pf.Z3 == nil {
+		return nil, fmt.Errorf("invalid signed response in EncProof")
+	}
+	return pf, nil
 }
@@ -17,6 +17,7 @@
 package zkproofs_test
 
 import (
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -47,9 +48,18 @@ func TestEncProof(t *testing.T) {
 	// Prove that:
 	// C = Encrypt(N0, x, rho)
 	// k \in +- 2^{ell+epsilon}
-	proof, err := zkproofs.NewEncProof(witness, statement, ringPedersen)
+	proof, err := zkproofs.NewEncProof(Session, witness, statement, ringPedersen)
 	assert.NoError(t, err)
-	assert.True(t, proof.Verify(statement, ringPedersen), "proof failed to verify")
+	assert.True(t, proof.Verify(Session, statement, ringPedersen), "proof failed to verify")
+	assert.False(t, proof.Verify([]byte("another session"), statement, ringPedersen), "proof must not verify in another session")
+
+	// the ciphertexts and the commitments of a proof must be in their groups
+	assert.True(t, ringPedersen.ValidateCommitments(proof.S, proof.C))
+	assert.False(t, ringPedersen.ValidateCommitments(proof.S, new(big.Int).Add(proof.C, ringPedersen.N)))
+	assert.False(t, ringPedersen.ValidateCommitments(big.NewInt(0)))
+	notInGroup := *statement
+	notInGroup.K = new(big.Int).Add(K, publicKey.NSquare())
+	assert.False(t, proof.Verify(Session, &notInGroup, ringPedersen), "a ciphertext out of Z*_{N^2} must be rejected")
 }
 
 func TestEncProofBytes(t *testing.T) {
@@ -74,10 +84,15 @@ func TestEncProofBytes(t *testing.T) {
 	// Prove that:
 	// C = Encrypt(N0, x, rho)
 	// k \in +- 2^{ell+epsilon}
-	proof, err := zkproofs.NewEncProof(witness, statement, ringPedersen)
+	proof, err := zkproofs.NewEncProof(Session, witness, statement, ringPedersen)
 	assert.NoError(t, err)
 	assert.NotNil(t, proof)
-	assert.True(t, proof.Verify(statement, ringPedersen), "proof failed to verify")
+	assert.True(t, proof.Verify(Session, statement, ringPedersen), "proof failed to verify")
+	// the masks are sampled around zero, so the responses are negative about half of the time
+	for proof.Z1.Sign() >= 0 && proof.Z3.Sign() >= 0 {
+		proof, err = zkproofs.NewEncProof(Session, witness, statement, ringPedersen)
+		assert.NoError(t, err)
+	}
 
 	proofBytes := proof.Bytes()
 	var proofInBytes [][]byte = proofBytes[:]
@@ -86,5 +101,7 @@ func TestEncProofBytes(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, newProof)
 	assert.False(t, newProof.Nil())
-	assert.True(t, newProof.Verify(statement, ringPedersen))
+	assert.Equal(t, 0, proof.Z1.Cmp(newProof.Z1))
+	assert.Equal(t, 0, proof.Z3.Cmp(newProof.Z3))
+	assert.True(t, newProof.Verify(Session, statement, ringPedersen))
 }
@@ -0,0 +1,189 @@
+// Copyright (c) 2023, Circle Internet Financial, LTD. All rights reserved.
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zkproofs
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/kisdex/mpc-lib/common"
+)
+
+const (
+	// fixedBaseWindow is the number of bits of the exponent handled by each precomputed power of a FixedBase
+	fixedBaseWindow = 6
+
+	// maxCachedFixedBases bounds the number of bases kept by fixedBaseOf, two per ring-Pedersen parameters
+	maxCachedFixedBases = 256
+)
+
+// FixedBase holds the powers g^(2^(w*k)) mod N of a base g that is raised to many exponents, such as the ring-Pedersen
+// parameters s and t. An exponentiation then takes one modular multiplication per non-zero w-bit digit of the
+// exponent plus 2^w (Yao's method) instead of one squaring per bit.
+//
+// The powers are computed on demand for the longest exponent seen so far, up to maxWindows of them: the exponents of
+// the proofs of this package are bounded by their statements, e.g. the responses to ±2^{ell+epsilon}*Nhat, so a
+// longer exponent comes from a peer and is raised with big.Int.Exp rather than growing the table. A FixedBase is safe
+// for concurrent use.
+type FixedBase struct {
+	g, mod     *big.Int
+	maxWindows int
+	mtx        sync.RWMutex
+	powers     []*big.Int
+}
+
+var fixedBases = struct {
+	sync.Mutex
+	m map[string]*FixedBase
+}{m: make(map[string]*FixedBase)}
+
+// NewFixedBase returns the precomputation for the powers of `g` mod `mod`, for exponents of up to twice the bit length
+// of `mod`. This covers the bounds of the statements of this package, ±2^{ell+epsilon}*Nhat with ell+epsilon of at
+// most 3*446 bits for Ed448, together with the coefficients of a RingPedersenBatch.
+func NewFixedBase(g, mod *big.Int) *FixedBase {
+	g = new(big.Int).Mod(g, mod)
+	return &FixedBase{
+		g:          g,
+		mod:        mod,
+		maxWindows: (2*mod.BitLen() + fixedBaseWindow - 1) / fixedBaseWindow,
+		powers:     []*big.Int{g},
+	}
+}
+
+// Exp returns g^x mod N; a negative x requires g to be invertible mod N, otherwise nil is returned like big.Int.Exp
+func (fb *FixedBase) Exp(x *big.Int) *big.Int {
+	return FixedBaseMultiExp([]*FixedBase{fb}, []*big.Int{x})
+}
+
+// FixedBaseMultiExp returns the product of bases[i]^exps[i] mod N for bases of the same modulus. The exponentiations
+// are simultaneous: they share the 2^w multiplications of Yao's method, so that e.g. s^x * t^y costs little more than
+// one of its factors.
+//
+// There is no such helper for bases that change with each call, as math/big's Exp works in the Montgomery domain and
+// is faster than an interleaved window method built on Mul and Mod.
+func FixedBaseMultiExp(bases []*FixedBase, exps []*big.Int) *big.Int {
+	modN := common.ModInt(bases[0].mod)
+	// the products are accumulated in place: x is owned by the caller unless it is nil, when a copy of y is made
+	mulMod := func(x, y *big.Int) *big.Int {
+		if x == nil {
+			return new(big.Int).Set(y)
+		}
+		return modN.MulTo(x, x, y)
+	}
+
+	// buckets[sign][d] is the product of the powers of the bases whose exponent has the digit d at their position,
+	// for the positive and the negative exponents, and direct[sign] the product of the powers raised with Exp
+	var buckets [2][1 << fixedBaseWindow]*big.Int
+	var direct [2]*big.Int
+	for i, fb := range bases {
+		x, sign := exps[i], 0
+		if x.Sign() < 0 {
+			x, sign = new(big.Int).Neg(x), 1
+		}
+		windows := (x.BitLen() + fixedBaseWindow - 1) / fixedBaseWindow
+		if windows > fb.maxWindows {
+			direct[sign] = mulMod(direct[sign], modN.Exp(fb.g, x))
+			continue
+		}
+		powers := fb.table(windows)
+		for k := 0; k < windows; k++ {
+			d := 0
+			for b := fixedBaseWindow - 1; b >= 0; b-- {
+				d = d<<1 | int(x.Bit(k*fixedBaseWindow+b))
+			}
+			if d != 0 {
+				buckets[sign][d] = mulMod(buckets[sign][d], powers[k])
+			}
+		}
+	}
+
+	// the product of bucket[d]^d is the product over d of the buckets from d up
+	var results [2]*big.Int
+	for sign := range buckets {
+		var acc, result *big.Int
+		for d := len(buckets[sign]) - 1; d > 0; d-- {
+			if buckets[sign][d] != nil {
+				acc = mulMod(acc, buckets[sign][d])
+			}
+			if acc != nil {
+				result = mulMod(result, acc)
+			}
+		}
+		if direct[sign] != nil {
+			result = mulMod(result, direct[sign])
+		}
+		results[sign] = result
+	}
+	switch {
+	case results[1] == nil && results[0] == nil:
+		return modN.Mod(big.NewInt(1))
+	case results[1] == nil:
+		return results[0]
+	}
+	inv := results[1].ModInverse(results[1], bases[0].mod)
+	if inv == nil {
+		return nil
+	}
+	if results[0] == nil {
+		return inv
+	}
+	return modN.MulTo(results[0], results[0], inv)
+}
+
+// table returns at least `n` powers of the base, for n of at most maxWindows. The missing powers are computed without
+// holding the lock, so that the callers that need no more powers are not blocked.
+func (fb *FixedBase) table(n int) []*big.Int {
+	fb.mtx.RLock()
+	powers := fb.powers
+	fb.mtx.RUnlock()
+	if n <= len(powers) {
+		return powers
+	}
+
+	// powers is never modified in place, so it can be extended by a copy
+	step := big.NewInt(1 << fixedBaseWindow)
+	longer := make([]*big.Int, len(powers), n)
+	copy(longer, powers)
+	for len(longer) < n {
+		longer = append(longer, new(big.Int).Exp(longer[len(longer)-1], step, fb.mod))
+	}
+
+	fb.mtx.Lock()
+	defer fb.mtx.Unlock()
+	if len(fb.powers) < len(longer) {
+		fb.powers = longer
+	}
+	return fb.powers
+}
+
+// fixedBaseOf returns the FixedBase of `g` mod `mod`, shared by all of the callers with the same values. Proofs are
+// created and verified with the ring-Pedersen parameters of the same few parties, so the cache is simply emptied
+// when it is full.
+func fixedBaseOf(g, mod *big.Int) *FixedBase {
+	key := g.Text(16) + ":" + mod.Text(16)
+	fixedBases.Lock()
+	defer fixedBases.Unlock()
+	if fb, ok := fixedBases.m[key]; ok {
+		return fb
+	}
+	if len(fixedBases.m) >= maxCachedFixedBases {
+		fixedBases.m = make(map[string]*FixedBase)
+	}
+	fb := NewFixedBase(g, mod)
+	fixedBases.m[key] = fb
+	return fb
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2023, Circle Internet Financial, LTD. All rights reserved.
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zkproofs_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto/zkproofs"
+)
+
+func TestFixedBaseMultiExp(t *testing.T) {
+	setUp(t)
+	rp := ringPedersen
+	modN := common.ModInt(rp.N)
+	S, T := zkproofs.NewFixedBase(rp.S, rp.N), zkproofs.NewFixedBase(rp.T, rp.N)
+
+	for _, bits := range []int{0, 1, 6, 7, 256, 3000, 2*rp.N.BitLen() + 1, 9000} {
+		x := common.MustGetRandomInt(bits + 1)
+		y := common.MustGetRandomInt(bits + 1)
+		for _, xy := range [][2]*big.Int{{x, y}, {new(big.Int).Neg(x), y}, {x, new(big.Int).Neg(y)}} {
+			expected := modN.Mul(modN.Exp(rp.S, xy[0]), modN.Exp(rp.T, xy[1]))
+			assert.Equal(t, 0, expected.Cmp(zkproofs.FixedBaseMultiExp([]*zkproofs.FixedBase{S, T}, xy[:])), "%d bits", bits)
+			assert.Equal(t, 0, expected.Cmp(rp.Commit(xy[0], xy[1])), "%d bits", bits)
+			assert.Equal(t, 0, modN.Exp(rp.S, xy[0]).Cmp(S.Exp(xy[0])), "%d bits", bits)
+		}
+	}
+	assert.Equal(t, 0, big.NewInt(1).Cmp(S.Exp(big.NewInt(0))))
+}
+
+func BenchmarkRingPedersenCommit(b *testing.B) {
+	_, _, rp, err := GetSavedKeys(0)
+	if err != nil {
+		b.Fatal(err)
+	}
+	modN := common.ModInt(rp.N)
+	x := common.MustGetRandomInt(3000)
+	y := common.MustGetRandomInt(3000)
+	rp.Commit(x, y)
+	b.ReportAllocs()
+
+	b.Run("Exp", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			modN.Mul(modN.Exp(rp.S, x), modN.Exp(rp.T, y))
+		}
+	})
+	b.Run("FixedBase", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			rp.Commit(x, y)
+		}
+	})
+}
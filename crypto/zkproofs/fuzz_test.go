@@ -0,0 +1,137 @@
+// Copyright (c) 2023, Circle Internet Financial, LTD. All rights reserved.
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zkproofs_test
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto/zkproofs"
+)
+
+// fuzzProof decodes a proof of one type and verifies it against the statement that its seed was proven for
+type fuzzProof struct {
+	decode func(bzs [][]byte) (zkproofs.Proof, error)
+	verify func(proof zkproofs.Proof) bool
+	seed   zkproofs.Proof
+}
+
+// joinParts encodes the parts of a proof as the fuzz input, each prefixed with its 2-byte length
+func joinParts(bzs [][]byte) []byte {
+	out := make([]byte, 0, 1024*len(bzs))
+	for _, bz := range bzs {
+		out = binary.BigEndian.AppendUint16(out, uint16(len(bz)))
+		out = append(out, bz...)
+	}
+	return out
+}
+
+// splitParts is the inverse of joinParts; a truncated last part is kept as it is
+func splitParts(data []byte) [][]byte {
+	bzs := make([][]byte, 0, 16)
+	for 2 <= len(data) {
+		n := int(binary.BigEndian.Uint16(data))
+		data = data[2:]
+		if len(data) < n {
+			n = len(data)
+		}
+		bzs, data = append(bzs, data[:n]), data[n:]
+	}
+	return bzs
+}
+
+// FuzzProofFromBytes decodes proofs from the bytes that a peer sent, which must fail or give a proof that does not
+// verify rather than panic
+func FuzzProofFromBytes(f *testing.F) {
+	setUp(f)
+
+	decW, decS := GenerateDecProofData(f)
+	logW, logS := GenerateLogStarData(f)
+	affGW, affGS := GenerateAffGData(f)
+	affG, err := zkproofs.NewAffGProof(Session, affGW, affGS, ringPedersen)
+	assert.NoError(f, err)
+	affPW, affPS := GenerateAffPData(f)
+	affP, err := zkproofs.NewAffPProof(Session, affPW, affPS, ringPedersen)
+	assert.NoError(f, err)
+	prmW, prmRP := GeneratePrmData(f)
+	encK := common.GetRandomPositiveInt(q)
+	encC, encRho, err := publicKey.EncryptAndReturnRandomness(encK)
+	assert.NoError(f, err)
+	encS := &zkproofs.EncStatement{EC: ec, N0: publicKey.N, K: encC}
+	enc, err := zkproofs.NewEncProof(Session, &zkproofs.EncWitness{K: encK, Rho: encRho}, encS, ringPedersen)
+	assert.NoError(f, err)
+
+	proofs := []fuzzProof{
+		{
+			decode: func(bzs [][]byte) (zkproofs.Proof, error) { return new(zkproofs.DecProof).ProofFromBytes(ec, bzs) },
+			verify: func(p zkproofs.Proof) bool { return p.(*zkproofs.DecProof).Verify(Session, decS, ringPedersen) },
+			seed:   zkproofs.NewDecProof(Session, decW, decS, ringPedersen),
+		},
+		{
+			decode: func(bzs [][]byte) (zkproofs.Proof, error) { return new(zkproofs.LogStarProof).ProofFromBytes(ec, bzs) },
+			verify: func(p zkproofs.Proof) bool { return p.(*zkproofs.LogStarProof).Verify(Session, logS, ringPedersen) },
+			seed:   zkproofs.NewLogStarProof(Session, logW, logS, ringPedersen),
+		},
+		{
+			decode: func(bzs [][]byte) (zkproofs.Proof, error) { return new(zkproofs.AffGProof).ProofFromBytes(ec, bzs) },
+			verify: func(p zkproofs.Proof) bool { return p.(*zkproofs.AffGProof).Verify(Session, affGS, ringPedersen) },
+			seed:   affG,
+		},
+		{
+			decode: func(bzs [][]byte) (zkproofs.Proof, error) { return new(zkproofs.AffPProof).ProofFromBytes(ec, bzs) },
+			verify: func(p zkproofs.Proof) bool { return p.(*zkproofs.AffPProof).Verify(Session, affPS, ringPedersen) },
+			seed:   affP,
+		},
+		{
+			decode: func(bzs [][]byte) (zkproofs.Proof, error) { return new(zkproofs.PrmProof).ProofFromBytes(ec, bzs) },
+			verify: func(p zkproofs.Proof) bool { return p.(*zkproofs.PrmProof).Verify(Session, prmRP) },
+			seed:   zkproofs.NewPrmProof(Session, prmW, prmRP),
+		},
+		{
+			decode: func(bzs [][]byte) (zkproofs.Proof, error) { return new(zkproofs.EncProof).ProofFromBytes(ec, bzs) },
+			verify: func(p zkproofs.Proof) bool { return p.(*zkproofs.EncProof).Verify(Session, encS, ringPedersen) },
+			seed:   enc,
+		},
+	}
+	maxPartLen := 0
+	for kind, proof := range proofs {
+		bzs := proof.seed.Bytes()
+		for _, bz := range bzs {
+			maxPartLen = max(maxPartLen, len(bz))
+		}
+		f.Add(uint8(kind), joinParts(bzs))
+	}
+
+	f.Fuzz(func(t *testing.T, kind uint8, data []byte) {
+		proof := proofs[int(kind)%len(proofs)]
+		bzs := splitParts(data)
+		for _, bz := range bzs {
+			// the exponentiations of a proof with much longer integers take minutes rather than fail
+			if 2*maxPartLen < len(bz) {
+				return
+			}
+		}
+		decoded, err := proof.decode(bzs)
+		if err != nil || decoded == nil || decoded.IsNil() {
+			return
+		}
+		proof.verify(decoded)
+	})
+}
@@ -37,7 +37,7 @@ import (
 )
 
 const (
-	LogStarProofParts = 8
+	LogStarProofParts = 7
 )
 
 // Note: (z,u,v) are lowercase in CGG21 Figure 29.
@@ -65,7 +65,7 @@ type LogStarStatement struct {
 }
 
 // log* in CGG21 in CGG21 Appendix C.2 Figure 25
-func NewLogStarProof(wit *LogStarWitness, stmt *LogStarStatement, rp *RingPedersenParams) *LogStarProof {
+func NewLogStarProof(Session []byte, wit *LogStarWitness, stmt *LogStarStatement, rp *RingPedersenParams) *LogStarProof {
 	if stmt.G == nil {
 		ec := stmt.X.Curve()
 		stmt.G = crypto.NewECPointNoCurveCheck(ec, ec.Params().Gx, ec.Params().Gy)
@@ -75,28 +75,27 @@ func NewLogStarProof(wit *LogStarWitness, stmt *LogStarStatement, rp *RingPeders
 	ecpc := NewEll(stmt.Ell)
 
 	// 1. Prover samples alpha, mu, r, gamma
-	alpha := common.GetRandomPositiveInt(ecpc.TwoPowEllPlusEpsilon)
+	alpha := common.GetRandomSignedInt(ecpc.TwoPowEllPlusEpsilon)
 	muRange := new(big.Int).Mul(ecpc.TwoPowEll, rp.N)
-	mu := common.GetRandomPositiveInt(muRange)
+	mu := common.GetRandomSignedInt(muRange)
 	// CGG21 has typo - says sample from Z*_N (where N is undefined)
 	// It should be Z*_N0  because it is used to compute A is a Paillier cypertext.
 	r := common.GetRandomPositiveInt(stmt.N0)
 	gammRange := new(big.Int).Mul(ecpc.TwoPowEllPlusEpsilon, rp.N)
-	gamma := common.GetRandomPositiveInt(gammRange)
+	gamma := common.GetRandomSignedInt(gammRange)
 
 	// S=s^x *t^mu mod Nhat
-	S := rp.Commit(wit.X, mu)
+	S := rp.CommitSecret(wit.X, mu)
 
 	//A = (1+N0)^alpha * r^N0 mod N02
-	// we can ignore error when encrypting because we chose the range
 	pkN0 := &paillier.PublicKey{N: stmt.N0}
-	A, _ := pkN0.EncryptWithRandomness(alpha, r)
+	A := pkN0.EncryptWithRandomnessNoErrChk(alpha, r)
 
 	// Y=g^alpha
 	Y := stmt.G.ScalarMult(alpha)
 
 	// D=s^alpha *t^gamma mod Nhat
-	D := rp.Commit(alpha, gamma)
+	D := rp.CommitSecret(alpha, gamma)
 
 	proof := &LogStarProof{
 		S: S,
@@ -105,7 +104,7 @@ func NewLogStarProof(wit *LogStarWitness, stmt *LogStarStatement, rp *RingPeders
 		D: D,
 	}
 	// 2. hash to get challenge
-	e := proof.GetChallenge(stmt, rp)
+	e := proof.GetChallenge(Session, stmt, rp)
 
 	// 3. prover sends (z1, z2, z3)
 	// z1 := alpha + e * x
@@ -122,7 +121,17 @@ func NewLogStarProof(wit *LogStarWitness, stmt *LogStarStatement, rp *RingPeders
 
 // log* from CGG21 Appendix C.2 Figure 25.
 // The Verifier checks the proof against the statement (N0, C, X)
-func (proof *LogStarProof) Verify(stmt *LogStarStatement, rp *RingPedersenParams) bool {
+func (proof *LogStarProof) Verify(Session []byte, stmt *LogStarStatement, rp *RingPedersenParams) bool {
+	return proof.verify(Session, stmt, rp, rp.check)
+}
+
+// VerifyBatch is Verify with the ring-Pedersen equations of the proof deferred to `batch`, whose Verify must not
+// blame `prover` for the proof to be valid
+func (proof *LogStarProof) VerifyBatch(Session []byte, stmt *LogStarStatement, batch *RingPedersenBatch, prover int) bool {
+	return proof.verify(Session, stmt, batch.rp, batch.checker(prover))
+}
+
+func (proof *LogStarProof) verify(Session []byte, stmt *LogStarStatement, rp *RingPedersenParams, check rpCheck) bool {
 	if proof == nil {
 		return false
 	}
@@ -130,6 +139,18 @@ func (proof *LogStarProof) Verify(stmt *LogStarStatement, rp *RingPedersenParams
 	if stmt.N0.Sign() != 1 {
 		return false
 	}
+	pkN0 := &paillier.PublicKey{N: stmt.N0}
+	if !pkN0.ValidateCiphertexts(stmt.C, proof.A) || !rp.ValidateCommitments(proof.S, proof.D) ||
+		!crypto.ValidatePoints(stmt.X, proof.Y) {
+		return false
+	}
+
+	// Check z1 in [-2^{ell+epsilon}...+2^{ell+epsilon}] and z3 in [-2^{ell+epsilon}*Nhat...+2^{ell+epsilon}*Nhat]
+	// before they are used as exponents
+	ecpc := NewEll(stmt.Ell)
+	if !ecpc.InRange(proof.Z1) || !ecpc.InRangeNhat(proof.Z3, rp.N) {
+		return false
+	}
 
 	if stmt.G == nil {
 		ec := stmt.X.Curve()
@@ -137,7 +158,7 @@ func (proof *LogStarProof) Verify(stmt *LogStarStatement, rp *RingPedersenParams
 	}
 
 	// hash to get challenge
-	e := proof.GetChallenge(stmt, rp)
+	e := proof.GetChallenge(Session, stmt, rp)
 
 	// otherwise first verification equation is trivially true
 	if IsZero(proof.A) || IsZero(proof.Z2) {
@@ -145,11 +166,10 @@ func (proof *LogStarProof) Verify(stmt *LogStarStatement, rp *RingPedersenParams
 	}
 
 	// check (1+N0)^z1 * z2^N0 mod N02 == A * C^e mod N02
-	N02 := new(big.Int).Mul(stmt.N0, stmt.N0)
-	pkN0 := &paillier.PublicKey{N: stmt.N0}
-	left1, err := pkN0.EncryptWithRandomness(proof.Z1, proof.Z2)
+	N02 := pkN0.NSquare()
+	left1 := pkN0.EncryptWithRandomnessNoErrChk(proof.Z1, proof.Z2)
 	right1 := ATimesBToTheCModN(proof.A, stmt.C, e, N02)
-	if err != nil || left1.Cmp(right1) != 0 {
+	if left1.Cmp(right1) != 0 {
 		return false
 	}
 
@@ -161,29 +181,22 @@ func (proof *LogStarProof) Verify(stmt *LogStarStatement, rp *RingPedersenParams
 	}
 
 	// check s^z1 * t^z3 == D * S^e mod Nhat
-	left3 := rp.Commit(proof.Z1, proof.Z3)
-	right3 := ATimesBToTheCModN(proof.D, proof.S, e, rp.N)
-	if left3.Cmp(right3) != 0 {
-		return false
-	}
-
-	// Check z1 in [-2^{ell+epsilon}...+2^{ell+epsilon}]
-	if !NewEll(stmt.Ell).InRange(proof.Z1) {
+	if !check(proof.Z1, proof.Z3, proof.D, proof.S, e) {
 		return false
 	}
 
 	return true
 }
 
-func (proof *LogStarProof) GetChallenge(stmt *LogStarStatement, rp *RingPedersenParams) *big.Int {
-	params := stmt.X.Curve().Params()
+func (proof *LogStarProof) GetChallenge(Session []byte, stmt *LogStarStatement, rp *RingPedersenParams) *big.Int {
 	msg := []*big.Int{
-		stmt.Ell, params.Gx, params.Gy, params.N, big.NewInt(int64(params.BitSize)),
+		stmt.Ell,
 		stmt.N0, stmt.X.X(), stmt.X.Y(), stmt.C, stmt.G.X(), stmt.G.Y(),
 		rp.N, rp.S, rp.T,
 		proof.S, proof.A, proof.Y.X(), proof.Y.Y(), proof.D}
-	e := common.SHA512_256i(msg...)
-	return e
+	e := common.SHA512_256i_DOMAIN(domainLogStar, Session, append(common.CurveInts(stmt.X.Curve()), msg...)...)
+	q := Q(stmt.X.Curve())
+	return common.RejectionSample(q, e)
 }
 
 func (proof *LogStarProof) Parts() int {
@@ -204,12 +217,11 @@ func (proof *LogStarProof) Bytes() [][]byte {
 	return [][]byte{
 		proof.S.Bytes(),
 		proof.A.Bytes(),
-		proof.Y.X().Bytes(),
-		proof.Y.Y().Bytes(),
+		proof.Y.Bytes(),
 		proof.D.Bytes(),
-		proof.Z1.Bytes(),
+		common.SignedIntBytes(proof.Z1),
 		proof.Z2.Bytes(),
-		proof.Z3.Bytes(),
+		common.SignedIntBytes(proof.Z3),
 	}
 }
 
@@ -217,20 +229,21 @@ func (proof *LogStarProof) ProofFromBytes(ec elliptic.Curve, bzs [][]byte) (Proo
 	if !common.NonEmptyMultiBytes(bzs, LogStarProofParts) {
 		return nil, fmt.Errorf("expected %d byte parts to construct LogStarProof", LogStarProofParts)
 	}
-	Y, err := crypto.NewECPoint(
-		ec,
-		new(big.Int).SetBytes(bzs[2]),
-		new(big.Int).SetBytes(bzs[3]))
+	Y, err := crypto.NewECPointFromBytes(ec, bzs[2])
 	if err != nil {
 		return nil, err
 	}
-	return &LogStarProof{
+	pf := &LogStarProof{
 		S:  new(big.Int).SetBytes(bzs[0]),
 		A:  new(big.Int).SetBytes(bzs[1]),
 		Y:  Y,
-		D:  new(big.Int).SetBytes(bzs[4]),
-		Z1: new(big.Int).SetBytes(bzs[5]),
-		Z2: new(big.Int).SetBytes(bzs[6]),
-		Z3: new(big.Int).SetBytes(bzs[7]),
-	}, nil
+		D:  new(big.Int).SetBytes(bzs[3]),
+		Z1: common.SignedIntFromBytes(bzs[4]),
+		Z2: new(big.Int).SetBytes(bzs[5]),
+		Z3: common.SignedIntFromBytes(bzs[6]),
+	}
+	if pf.Z1 == nil || pf.Z3 == nil {
+		return nil, fmt.Errorf("invalid signed response in LogStarProof")
+	}
+	return pf, nil
 }
@@ -52,10 +52,10 @@ func TestLogStarProof(t *testing.T) {
 	// Prove that:
 	// X = g^x
 	// C = Encrypt(N0, x, rho)
-	proof := zkproofs.NewLogStarProof(witness, statement, ringPedersen)
+	proof := zkproofs.NewLogStarProof(Session, witness, statement, ringPedersen)
 	assert.NoError(t, err)
 	assert.NotNil(t, proof)
-	assert.True(t, proof.Verify(statement, ringPedersen), "proof failed to verify")
+	assert.True(t, proof.Verify(Session, statement, ringPedersen), "proof failed to verify")
 }
 
 func TestLogStarGProof(t *testing.T) {
@@ -88,13 +88,13 @@ func TestLogStarGProof(t *testing.T) {
 	// Prove that:
 	// X = G^x
 	// C = Encrypt(N0, x, rho)
-	proof := zkproofs.NewLogStarProof(witness, statement, ringPedersen)
+	proof := zkproofs.NewLogStarProof(Session, witness, statement, ringPedersen)
 	assert.NoError(t, err)
 	assert.NotNil(t, proof)
-	assert.True(t, proof.Verify(statement, ringPedersen), "proof failed to verify")
+	assert.True(t, proof.Verify(Session, statement, ringPedersen), "proof failed to verify")
 }
 
-func GenerateLogStarData(t *testing.T) (*zkproofs.LogStarWitness, *zkproofs.LogStarStatement) {
+func GenerateLogStarData(t testing.TB) (*zkproofs.LogStarWitness, *zkproofs.LogStarStatement) {
 	// witness
 	witness := &zkproofs.LogStarWitness{
 		X:   common.GetRandomPositiveInt(q),
@@ -125,9 +125,9 @@ func TestLogStarProofBytes(t *testing.T) {
 	// Prove that:
 	// X = g^x
 	// C = Encrypt(N0, x, rho)
-	proof := zkproofs.NewLogStarProof(witness, statement, ringPedersen)
+	proof := zkproofs.NewLogStarProof(Session, witness, statement, ringPedersen)
 	assert.NotNil(t, proof)
-	assert.True(t, proof.Verify(statement, ringPedersen), "proof failed to verify")
+	assert.True(t, proof.Verify(Session, statement, ringPedersen), "proof failed to verify")
 
 	proofBytes := proof.Bytes()
 	var proofInBytes [][]byte = proofBytes[:]
@@ -136,7 +136,7 @@ func TestLogStarProofBytes(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, newProof)
 	assert.False(t, newProof.IsNil())
-	assert.True(t, newProof.Verify(statement, ringPedersen))
+	assert.True(t, newProof.Verify(Session, statement, ringPedersen))
 
 }
 
@@ -144,7 +144,7 @@ func TestLogStarProofArrayBytes(t *testing.T) {
 	setUp(t)
 	witness, statement := GenerateLogStarData(t)
 
-	proof := zkproofs.NewLogStarProof(witness, statement, ringPedersen)
+	proof := zkproofs.NewLogStarProof(Session, witness, statement, ringPedersen)
 	array := []*zkproofs.LogStarProof{proof, proof, nil, proof}
 	bzs := zkproofs.ProofArrayToBytes(array)
 	out, err := zkproofs.ProofArrayFromBytes[*zkproofs.LogStarProof](ec, bzs)
@@ -153,8 +153,8 @@ func TestLogStarProofArrayBytes(t *testing.T) {
 	assert.NotNil(t, out[0])
 	assert.NotNil(t, out[1])
 	assert.NotNil(t, out[3])
-	assert.True(t, out[0].Verify(statement, ringPedersen))
-	assert.True(t, out[1].Verify(statement, ringPedersen))
+	assert.True(t, out[0].Verify(Session, statement, ringPedersen))
+	assert.True(t, out[1].Verify(Session, statement, ringPedersen))
 	assert.Nil(t, out[2])
-	assert.True(t, out[3].Verify(statement, ringPedersen))
+	assert.True(t, out[3].Verify(Session, statement, ringPedersen))
 }
@@ -27,6 +27,7 @@ import (
 	"math/big"
 
 	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto/paillier"
 )
 
 const (
@@ -56,7 +57,7 @@ type MulStatement struct {
 }
 
 // mul in CGG21 in CGG21 Appendix C.6 Figure 29
-func NewMulProof(wit *MulWitness, stmt *MulStatement) *MulProof {
+func NewMulProof(Session []byte, wit *MulWitness, stmt *MulStatement) *MulProof {
 	// 1. Prover samples and computes
 	alpha := common.GetRandomPositiveInt(stmt.N)
 	r := common.GetRandomPositiveInt(stmt.N)
@@ -64,12 +65,12 @@ func NewMulProof(wit *MulWitness, stmt *MulStatement) *MulProof {
 
 	//A = Y^alpha * r^N mod N^2
 	N2 := new(big.Int).Mul(stmt.N, stmt.N)
-	A := PseudoPaillierEncrypt(stmt.Y, alpha, r, stmt.N, N2)
+	A := pseudoPaillierEncryptSecret(stmt.Y, alpha, r, stmt.N, N2)
 
 	// B = B = (1 + N)^alpha * s^N mod N^2
 	NPlusOne := new(big.Int)
 	NPlusOne.Add(stmt.N, big.NewInt(1))
-	B := PseudoPaillierEncrypt(NPlusOne, alpha, s, stmt.N, N2)
+	B := pseudoPaillierEncryptSecret(NPlusOne, alpha, s, stmt.N, N2)
 
 	proof := &MulProof{
 		A: A,
@@ -77,7 +78,7 @@ func NewMulProof(wit *MulWitness, stmt *MulStatement) *MulProof {
 	}
 
 	// 2. hash to get challenge
-	e := proof.GetChallenge(stmt)
+	e := proof.GetChallenge(Session, stmt)
 
 	// 3. prover sends (z, u, v)
 	// z := alpha + e * x
@@ -94,7 +95,7 @@ func NewMulProof(wit *MulWitness, stmt *MulStatement) *MulProof {
 
 // mul in CGG21 in CGG21 Appendix C.6 Figure 29
 // The Verifier checks the proof against the statement (N, X, Y, C)
-func (proof *MulProof) Verify(stmt *MulStatement) bool {
+func (proof *MulProof) Verify(Session []byte, stmt *MulStatement) bool {
 	if proof == nil {
 		return false
 	}
@@ -102,10 +103,14 @@ func (proof *MulProof) Verify(stmt *MulStatement) bool {
 	if stmt.N.Sign() != 1 {
 		return false
 	}
+	pk := &paillier.PublicKey{N: stmt.N}
+	if !pk.ValidateCiphertexts(stmt.X, stmt.Y, stmt.C, proof.A, proof.B) {
+		return false
+	}
 
-	N2 := new(big.Int).Mul(stmt.N, stmt.N)
+	N2 := pk.NSquare()
 	// hash to get challenge
-	e := proof.GetChallenge(stmt)
+	e := proof.GetChallenge(Session, stmt)
 
 	// otherwise first verification equation trivially true
 	if IsZero(proof.U) || IsZero(proof.A) {
@@ -138,9 +143,9 @@ func (proof *MulProof) Verify(stmt *MulStatement) bool {
 	return true
 }
 
-func (proof *MulProof) GetChallenge(stmt *MulStatement) *big.Int {
+func (proof *MulProof) GetChallenge(Session []byte, stmt *MulStatement) *big.Int {
 	msg := []*big.Int{stmt.N, stmt.X, stmt.Y, stmt.C, proof.A, proof.B}
-	e := common.SHA512_256i(msg...)
+	e := common.SHA512_256i_DOMAIN(domainMul, Session, msg...)
 	return e
 }
 
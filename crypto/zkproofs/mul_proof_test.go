@@ -62,10 +62,10 @@ func TestMulProof(t *testing.T) {
 	// Prove that:
 	// X =(1 + N)^x rhox^N mod N^2
 	// C = Y^x rho^N mod N^2
-	proof := zkproofs.NewMulProof(witness, statement)
+	proof := zkproofs.NewMulProof(Session, witness, statement)
 	assert.NoError(t, err)
 	assert.NotNil(t, proof)
-	assert.True(t, proof.Verify(statement), "proof failed to verify")
+	assert.True(t, proof.Verify(Session, statement), "proof failed to verify")
 }
 
 func TestMulProofBytes(t *testing.T) {
@@ -104,10 +104,10 @@ func TestMulProofBytes(t *testing.T) {
 	// Prove that:
 	// X =(1 + N)^x rhox^N mod N^2
 	// C = Y^x rho^N mod N^2
-	proof := zkproofs.NewMulProof(witness, statement)
+	proof := zkproofs.NewMulProof(Session, witness, statement)
 	assert.NoError(t, err)
 	assert.NotNil(t, proof)
-	assert.True(t, proof.Verify(statement), "proof failed to verify")
+	assert.True(t, proof.Verify(Session, statement), "proof failed to verify")
 
 	proofBytes := proof.Bytes()
 	var proofInBytes [][]byte = proofBytes[:]
@@ -115,5 +115,5 @@ func TestMulProofBytes(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, newProof)
 	assert.False(t, newProof.Nil())
-	assert.True(t, newProof.Verify(statement))
+	assert.True(t, newProof.Verify(Session, statement))
 }
@@ -33,10 +33,11 @@ import (
 
 	"github.com/kisdex/mpc-lib/common"
 	"github.com/kisdex/mpc-lib/crypto"
+	"github.com/kisdex/mpc-lib/crypto/paillier"
 )
 
 const (
-	MulStarProofParts = 8
+	MulStarProofParts = 7
 )
 
 // Note: (z1,z2,w) are lowercase in CGG21 Figure 29.
@@ -64,7 +65,7 @@ type MulStarStatement struct {
 }
 
 // mul* in CGG21 Appendix C.6 Figure 31
-func NewMulStarProof(wit *MulStarWitness, stmt *MulStarStatement, rp *RingPedersenParams) *MulStarProof {
+func NewMulStarProof(Session []byte, wit *MulStarWitness, stmt *MulStarStatement, rp *RingPedersenParams) *MulStarProof {
 	// derive some parameters
 	ec := stmt.X.Curve()
 	ecpc := NewEll(stmt.Ell)
@@ -72,24 +73,24 @@ func NewMulStarProof(wit *MulStarWitness, stmt *MulStarStatement, rp *RingPeders
 
 	// 1. Prover samples alpha, r, gamma, m
 	// note: CGG21 has typo with extra variable ry that is not used
-	alpha := common.GetRandomPositiveInt(ecpc.TwoPowEllPlusEpsilon)
+	alpha := common.GetRandomSignedInt(ecpc.TwoPowEllPlusEpsilon)
 	r := common.GetRandomPositiveInt(stmt.N0)
 	gammRange := new(big.Int).Mul(ecpc.TwoPowEllPlusEpsilon, rp.N)
-	gamma := common.GetRandomPositiveInt(gammRange)
+	gamma := common.GetRandomSignedInt(gammRange)
 	mRange := new(big.Int).Mul(ecpc.TwoPowEll, rp.N)
-	m := common.GetRandomPositiveInt(mRange)
+	m := common.GetRandomSignedInt(mRange)
 
 	// 1. Prover computes
 	// A = C^alpha r^N0 mod N02
 	// Note: CGG21 has a typo A = C^alpha (1+N0)^beta r^N0 mod N02
 	// The extra factor (1+N0)^beta would cause the first verification equation to fail
-	A := PseudoPaillierEncrypt(stmt.C, alpha, r, stmt.N0, N02)
+	A := pseudoPaillierEncryptSecret(stmt.C, alpha, r, stmt.N0, N02)
 	// Bx = g^alpha \in G
 	Bx := crypto.ScalarBaseMult(ec, alpha)
 	// E = s^alpha * t^gamma mod Nhat (CGG21 omits mod Nhat)
-	E := rp.Commit(alpha, gamma)
+	E := rp.CommitSecret(alpha, gamma)
 	// S = s^x * t^m md Nhat
-	S := rp.Commit(wit.X, m)
+	S := rp.CommitSecret(wit.X, m)
 	proof := &MulStarProof{
 		A:  A,
 		Bx: Bx,
@@ -98,7 +99,7 @@ func NewMulStarProof(wit *MulStarWitness, stmt *MulStarStatement, rp *RingPeders
 	}
 
 	// 2. hash to get challenge
-	e := proof.GetChallenge(stmt, rp)
+	e := proof.GetChallenge(Session, stmt, rp)
 
 	// 3. Prover computes
 	// z1 = alpha + ex
@@ -113,7 +114,17 @@ func NewMulStarProof(wit *MulStarWitness, stmt *MulStarStatement, rp *RingPeders
 
 // mul in CGG21 in CGG21 Appendix C.6 Figure 29
 // The Verifier checks the proof against the statement (N, X, Y, C)
-func (proof *MulStarProof) Verify(stmt *MulStarStatement, rp *RingPedersenParams) bool {
+func (proof *MulStarProof) Verify(Session []byte, stmt *MulStarStatement, rp *RingPedersenParams) bool {
+	return proof.verify(Session, stmt, rp, rp.check)
+}
+
+// VerifyBatch is Verify with the ring-Pedersen equations of the proof deferred to `batch`, whose Verify must not
+// blame `prover` for the proof to be valid
+func (proof *MulStarProof) VerifyBatch(Session []byte, stmt *MulStarStatement, batch *RingPedersenBatch, prover int) bool {
+	return proof.verify(Session, stmt, batch.rp, batch.checker(prover))
+}
+
+func (proof *MulStarProof) verify(Session []byte, stmt *MulStarStatement, rp *RingPedersenParams, check rpCheck) bool {
 	if proof == nil {
 		return false
 	}
@@ -121,13 +132,24 @@ func (proof *MulStarProof) Verify(stmt *MulStarStatement, rp *RingPedersenParams
 	if stmt.N0.Sign() != 1 {
 		return false
 	}
+	pkN0 := &paillier.PublicKey{N: stmt.N0}
+	if !pkN0.ValidateCiphertexts(stmt.C, stmt.D, proof.A) || !rp.ValidateCommitments(proof.S, proof.E) ||
+		!crypto.ValidatePoints(stmt.X, proof.Bx) {
+		return false
+	}
+
+	// Check z1 in +-2^{ell+epsilon} and z2 in +-2^{ell+epsilon}*Nhat before they are used as exponents
+	ecpc := NewEll(stmt.Ell)
+	if !ecpc.InRange(proof.Z1) || !ecpc.InRangeNhat(proof.Z2, rp.N) {
+		return false
+	}
 
 	// derive some parameters
 	ec := stmt.X.Curve()
-	N02 := new(big.Int).Mul(stmt.N0, stmt.N0)
+	N02 := pkN0.NSquare()
 
 	// hash to get challenge
-	e := proof.GetChallenge(stmt, rp)
+	e := proof.GetChallenge(Session, stmt, rp)
 
 	// otherwise first verification equation trivially true
 	if IsZero(proof.W) || IsZero(proof.A) {
@@ -149,29 +171,23 @@ func (proof *MulStarProof) Verify(stmt *MulStarStatement, rp *RingPedersenParams
 	}
 
 	// Check s^z1 * t^z2 == E * S^e mod Nhat
-	left3 := rp.Commit(proof.Z1, proof.Z2)
-	right3 := ATimesBToTheCModN(proof.E, proof.S, e, rp.N)
-	if left3.Cmp(right3) != 0 {
+	if !check(proof.Z1, proof.Z2, proof.E, proof.S, e) {
 		return false
 	}
 
-	// Check z1 in +-2^{ell+epsilon}
-	if !NewEll(stmt.Ell).InRange(proof.Z1) {
-		return false
-	}
 	return true
 }
 
-func (proof *MulStarProof) GetChallenge(stmt *MulStarStatement, rp *RingPedersenParams) *big.Int {
-	params := stmt.X.Curve().Params()
+func (proof *MulStarProof) GetChallenge(Session []byte, stmt *MulStarStatement, rp *RingPedersenParams) *big.Int {
 	msg := []*big.Int{
-		stmt.Ell, params.Gx, params.Gy, params.N, big.NewInt(int64(params.BitSize)),
+		stmt.Ell,
 		stmt.N0, stmt.C, stmt.D, stmt.X.X(), stmt.X.Y(),
 		rp.N, rp.S, rp.T,
 		proof.A, proof.Bx.X(), proof.Bx.Y(), proof.S, proof.E,
 	}
-	e := common.SHA512_256i(msg...)
-	return e
+	e := common.SHA512_256i_DOMAIN(domainMulStar, Session, append(common.CurveInts(stmt.X.Curve()), msg...)...)
+	q := Q(stmt.X.Curve())
+	return common.RejectionSample(q, e)
 }
 
 func (proof *MulStarProof) Nil() bool {
@@ -195,12 +211,11 @@ func (proof *MulStarProof) Parts() int {
 func (proof *MulStarProof) Bytes() [][]byte {
 	return [][]byte{
 		proof.A.Bytes(),
-		proof.Bx.X().Bytes(),
-		proof.Bx.Y().Bytes(),
+		proof.Bx.Bytes(),
 		proof.S.Bytes(),
 		proof.E.Bytes(),
-		proof.Z1.Bytes(),
-		proof.Z2.Bytes(),
+		common.SignedIntBytes(proof.Z1),
+		common.SignedIntBytes(proof.Z2),
 		proof.W.Bytes(),
 	}
 }
@@ -209,20 +224,21 @@ func (proof *MulStarProof) ProofFromBytes(ec elliptic.Curve, bzs [][]byte) (Proo
 	if !common.NonEmptyMultiBytes(bzs, MulStarProofParts) {
 		return nil, fmt.Errorf("expected %d byte parts to construct MulStarProof", MulStarProofParts)
 	}
-	Bx, err := crypto.NewECPoint(
-		ec,
-		new(big.Int).SetBytes(bzs[1]),
-		new(big.Int).SetBytes(bzs[2]))
+	Bx, err := crypto.NewECPointFromBytes(ec, bzs[1])
 	if err != nil {
 		return nil, err
 	}
-	return &MulStarProof{
+	pf := &MulStarProof{
 		A:  new(big.Int).SetBytes(bzs[0]),
 		Bx: Bx,
-		S:  new(big.Int).SetBytes(bzs[3]),
-		E:  new(big.Int).SetBytes(bzs[4]),
-		Z1: new(big.Int).SetBytes(bzs[5]),
-		Z2: new(big.Int).SetBytes(bzs[6]),
-		W:  new(big.Int).SetBytes(bzs[7]),
-	}, nil
+		S:  new(big.Int).SetBytes(bzs[2]),
+		E:  new(big.Int).SetBytes(bzs[3]),
+		Z1: common.SignedIntFromBytes(bzs[4]),
+		Z2: common.SignedIntFromBytes(bzs[5]),
+		W:  new(big.Int).SetBytes(bzs[6]),
+	}
+	if pf.Z1 == nil || pf.Z2 == nil {
+		return nil, fmt.Errorf("invalid signed response in MulStarProof")
+	}
+	return pf, nil
 }
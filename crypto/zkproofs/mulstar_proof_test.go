@@ -56,10 +56,10 @@ func TestMulStarProof(t *testing.T) {
 	// Prove that:
 	// X = g^x
 	// D = C^x rho^N2 mod N02
-	proof := zkproofs.NewMulStarProof(witness, statement, ringPedersen)
+	proof := zkproofs.NewMulStarProof(Session, witness, statement, ringPedersen)
 	assert.NoError(t, err)
 	assert.NotNil(t, proof)
-	assert.True(t, proof.Verify(statement, ringPedersen), "proof failed to verify")
+	assert.True(t, proof.Verify(Session, statement, ringPedersen), "proof failed to verify")
 }
 
 func TestMulStarProofBytes(t *testing.T) {
@@ -90,10 +90,10 @@ func TestMulStarProofBytes(t *testing.T) {
 	// Prove that:
 	// X = g^x
 	// D = C^x rho^N2 mod N02
-	proof := zkproofs.NewMulStarProof(witness, statement, ringPedersen)
+	proof := zkproofs.NewMulStarProof(Session, witness, statement, ringPedersen)
 	assert.NoError(t, err)
 	assert.NotNil(t, proof)
-	assert.True(t, proof.Verify(statement, ringPedersen), "proof failed to verify")
+	assert.True(t, proof.Verify(Session, statement, ringPedersen), "proof failed to verify")
 
 	proofBytes := proof.Bytes()
 	var proofInBytes [][]byte = proofBytes[:]
@@ -102,5 +102,5 @@ func TestMulStarProofBytes(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, newProof)
 	assert.False(t, newProof.Nil())
-	assert.True(t, newProof.Verify(statement, ringPedersen))
+	assert.True(t, newProof.Verify(Session, statement, ringPedersen))
 }
@@ -0,0 +1,171 @@
+// Copyright (c) 2023, Circle Internet Financial, LTD. All rights reserved.
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This file implements proof prm in CGG21 Figure 17.
+// The prover has secret input (lambda, phi) and
+// the verifier checks the proof against the ring-Pedersen parameters (N, s, t)
+//  s = t^lambda mod N
+// where phi is the order of t, so that commitments s^x t^y mod N hide x.
+
+package zkproofs
+
+import (
+	"crypto/elliptic"
+	"fmt"
+	"math/big"
+
+	"github.com/kisdex/mpc-lib/common"
+)
+
+const (
+	// PrmIterations is m in CGG21 Figure 17; each iteration has a binary challenge
+	PrmIterations = 80
+	PrmProofParts = PrmIterations * 2
+)
+
+type PrmProof struct {
+	A [PrmIterations]*big.Int // mod N
+	Z [PrmIterations]*big.Int // mod phi
+}
+
+type PrmWitness struct {
+	Lambda *big.Int
+	Phi    *big.Int
+}
+
+// prm in CGG21 Figure 17.
+func NewPrmProof(Session []byte, wit *PrmWitness, rp *RingPedersenParams) *PrmProof {
+	modN, modPhi := common.ModInt(rp.N), common.ModInt(wit.Phi)
+
+	// 1. Prover samples a_i in Z_phi and sends A_i = t^a_i mod N
+	a := [PrmIterations]*big.Int{}
+	proof := &PrmProof{}
+	for i := range a {
+		a[i] = common.GetRandomPositiveInt(wit.Phi)
+		proof.A[i] = modN.SecretExp(rp.T, a[i], wit.Phi)
+	}
+
+	// 2. hash to get challenge
+	e := proof.GetChallenge(Session, rp)
+
+	// 3. prover sends z_i = a_i + e_i * lambda mod phi
+	for i := range proof.Z {
+		if e.Bit(i) == 1 {
+			proof.Z[i] = modPhi.Add(a[i], wit.Lambda)
+		} else {
+			proof.Z[i] = a[i]
+		}
+	}
+	return proof
+}
+
+// prm in CGG21 Figure 17.
+func (proof *PrmProof) Verify(Session []byte, rp *RingPedersenParams) bool {
+	if proof.Nil() || rp == nil || rp.N == nil || rp.S == nil || rp.T == nil {
+		return false
+	}
+	if rp.N.Sign() != 1 || rp.N.Bit(0) == 0 {
+		return false
+	}
+	if !common.IsNumberInMultiplicativeGroup(rp.N, rp.S) || !common.IsNumberInMultiplicativeGroup(rp.N, rp.T) ||
+		rp.S.Cmp(rp.T) == 0 {
+		return false
+	}
+	for i := range proof.A {
+		if !common.IsNumberInMultiplicativeGroup(rp.N, proof.A[i]) {
+			return false
+		}
+		if proof.Z[i].Sign() == -1 || proof.Z[i].BitLen() > rp.N.BitLen() {
+			return false
+		}
+	}
+
+	// hash to get challenge
+	e := proof.GetChallenge(Session, rp)
+
+	// check t^z_i == A_i * s^e_i mod N
+	modN := common.ModInt(rp.N)
+	for i := range proof.Z {
+		right := proof.A[i]
+		if e.Bit(i) == 1 {
+			right = modN.Mul(right, rp.S)
+		}
+		if modN.Exp(rp.T, proof.Z[i]).Cmp(right) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (proof *PrmProof) GetChallenge(Session []byte, rp *RingPedersenParams) *big.Int {
+	msg := append([]*big.Int{rp.N, rp.S, rp.T}, proof.A[:]...)
+	return common.SHA512_256i_DOMAIN(domainPrm, Session, msg...)
+}
+
+func (proof *PrmProof) Nil() bool {
+	if proof == nil {
+		return true
+	}
+	for i := range proof.A {
+		if proof.A[i] == nil || proof.Z[i] == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (proof *PrmProof) IsNil() bool {
+	return proof == nil
+}
+
+func (proof *PrmProof) Parts() int {
+	return PrmProofParts
+}
+
+// Bytes encodes the proof; missing values are left empty, which fails ProofFromBytes
+func (proof *PrmProof) Bytes() [][]byte {
+	bzs := make([][]byte, PrmProofParts)
+	for i := range proof.A {
+		if proof.A[i] != nil {
+			bzs[i] = proof.A[i].Bytes()
+		}
+		if proof.Z[i] != nil {
+			bzs[PrmIterations+i] = proof.Z[i].Bytes()
+		}
+	}
+	return bzs
+}
+
+func (proof *PrmProof) ProofFromBytes(ec elliptic.Curve, bzs [][]byte) (Proof, error) {
+	prm, err := PrmProofFromBytes(bzs)
+	if err != nil {
+		return nil, err
+	}
+	return prm, nil
+}
+
+// PrmProofFromBytes decodes the output of Bytes; prm does not depend on a curve
+func PrmProofFromBytes(bzs [][]byte) (*PrmProof, error) {
+	if !common.NonEmptyMultiBytes(bzs, PrmProofParts) {
+		return nil, fmt.Errorf("expected %d byte parts to construct PrmProof", PrmProofParts)
+	}
+	proof := &PrmProof{}
+	for i := range proof.A {
+		proof.A[i] = new(big.Int).SetBytes(bzs[i])
+		proof.Z[i] = new(big.Int).SetBytes(bzs[PrmIterations+i])
+	}
+	return proof, nil
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2023, Circle Internet Financial, LTD. All rights reserved.
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zkproofs_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto/zkproofs"
+	"github.com/kisdex/mpc-lib/ecdsa/keygen"
+)
+
+func GeneratePrmData(t testing.TB) (*zkproofs.PrmWitness, *zkproofs.RingPedersenParams) {
+	fixtures, _, err := keygen.LoadKeygenTestFixtures(1)
+	assert.NoError(t, err)
+	fixture := fixtures[0]
+	// h1 = h2^beta in the group of order pq
+	witness := &zkproofs.PrmWitness{
+		Lambda: fixture.Beta,
+		Phi:    new(big.Int).Mul(fixture.P, fixture.Q),
+	}
+	return witness, fixture.RingPedersen()
+}
+
+func TestPrmProof(t *testing.T) {
+	witness, rp := GeneratePrmData(t)
+
+	proof := zkproofs.NewPrmProof(Session, witness, rp)
+	assert.True(t, proof.Verify(Session, rp), "proof failed to verify")
+	assert.False(t, proof.Verify([]byte("another session"), rp), "proof must not verify in another session")
+
+	// s outside of the group generated by t
+	bad := &zkproofs.RingPedersenParams{N: rp.N, S: common.GetRandomPositiveRelativelyPrimeInt(rp.N), T: rp.T}
+	assert.False(t, proof.Verify(Session, bad), "proof must not verify for another s")
+	proof = zkproofs.NewPrmProof(Session, witness, bad)
+	assert.False(t, proof.Verify(Session, bad), "proof with a wrong witness must not verify")
+}
+
+func TestPrmProofBytes(t *testing.T) {
+	witness, rp := GeneratePrmData(t)
+
+	proof := zkproofs.NewPrmProof(Session, witness, rp)
+	bzs := proof.Bytes()
+	assert.Equal(t, zkproofs.PrmProofParts, len(bzs))
+	proof2, err := zkproofs.PrmProofFromBytes(bzs)
+	assert.NoError(t, err)
+	assert.True(t, proof2.Verify(Session, rp), "proof failed to verify")
+
+	_, err = zkproofs.PrmProofFromBytes(bzs[1:])
+	assert.Error(t, err)
+}
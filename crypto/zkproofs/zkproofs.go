@@ -25,6 +25,18 @@ import (
 	"github.com/kisdex/mpc-lib/common"
 )
 
+// Domain separation strings of the Fiat-Shamir challenges of the proofs, see common.SHA512_256i_DOMAIN
+const (
+	domainAffG    = "mpc-lib/zkproofs/aff-g"
+	domainAffP    = "mpc-lib/zkproofs/aff-p"
+	domainDec     = "mpc-lib/zkproofs/dec"
+	domainEnc     = "mpc-lib/zkproofs/enc"
+	domainLogStar = "mpc-lib/zkproofs/log*"
+	domainMul     = "mpc-lib/zkproofs/mul"
+	domainMulStar = "mpc-lib/zkproofs/mul*"
+	domainPrm     = "mpc-lib/zkproofs/prm"
+)
+
 type Proof interface {
 	// returns true if nil
 	IsNil() bool
@@ -121,7 +133,7 @@ func NewEll(ell *big.Int) *Ell {
 }
 
 func GetEll(ec elliptic.Curve) *big.Int {
-	return big.NewInt(int64(ec.Params().BitSize))
+	return big.NewInt(int64(ec.Params().N.BitLen()))
 }
 
 func (ell *Ell) String() string {
@@ -135,22 +147,18 @@ func (ell *Ell) String() string {
 
 // Returns true if val in [-2^{ell+epsilon}...+2^{ell+epsilon}]
 func (ell *Ell) InRange(val *big.Int) bool {
-	min := new(big.Int).Mul(big.NewInt(-1), ell.TwoPowEllPlusEpsilon)
-	max := ell.TwoPowEllPlusEpsilon
-	if val.Cmp(min) != 1 || val.Cmp(max) != -1 {
-		return false
-	}
-	return true
+	return common.IsInSymmetricInterval(val, ell.TwoPowEllPlusEpsilon)
+}
+
+// InRangeNhat returns true if val in [-2^{ell+epsilon}*Nhat...+2^{ell+epsilon}*Nhat], the range of the responses that
+// mask the randomness of a ring-Pedersen commitment mod Nhat
+func (ell *Ell) InRangeNhat(val, Nhat *big.Int) bool {
+	return common.IsInSymmetricInterval(val, new(big.Int).Mul(ell.TwoPowEllPlusEpsilon, Nhat))
 }
 
 // Returns true if val in [-2^{ell}...+2^{ell}]
 func (ell *Ell) InRangeEll(val *big.Int) bool {
-	min := new(big.Int).Mul(big.NewInt(-1), ell.TwoPowEll)
-	max := ell.TwoPowEll
-	if val.Cmp(min) != 1 || val.Cmp(max) != -1 {
-		return false
-	}
-	return true
+	return common.IsInSymmetricInterval(val, ell.TwoPowEll)
 }
 
 func Q(ec elliptic.Curve) *big.Int {
@@ -168,6 +176,16 @@ func IsZero(val *big.Int) bool {
 func PseudoPaillierEncrypt(gamma *big.Int, m *big.Int, rho *big.Int, N *big.Int, N2 *big.Int) *big.Int {
 	// 1. Gm = gamma^m mod N2
 	Gm := new(big.Int).Exp(gamma, m, N2)
+	return pseudoPaillierEncrypt(Gm, rho, N, N2)
+}
+
+// pseudoPaillierEncryptSecret is PseudoPaillierEncrypt for a secret m, see common.ModInt(N2).SecretExp
+func pseudoPaillierEncryptSecret(gamma *big.Int, m *big.Int, rho *big.Int, N *big.Int, N2 *big.Int) *big.Int {
+	Gm := common.ModInt(N2).SecretExp(gamma, m, nil)
+	return pseudoPaillierEncrypt(Gm, rho, N, N2)
+}
+
+func pseudoPaillierEncrypt(Gm *big.Int, rho *big.Int, N *big.Int, N2 *big.Int) *big.Int {
 	// 2. Xn = rho^N mod N2
 	Xn := new(big.Int).Exp(rho, N, N2)
 	// 3. (1) * (2) mod N2
@@ -182,23 +200,36 @@ type RingPedersenParams struct {
 	N *big.Int
 }
 
+// Commit returns s^x * t^y mod Nhat, with the powers of s and t precomputed once for all of the commitments to rp
 func (rp *RingPedersenParams) Commit(x *big.Int, y *big.Int) *big.Int {
-	modNhat := common.ModInt(rp.N)
-	sx := modNhat.Exp(rp.S, x)
-	ty := modNhat.Exp(rp.T, y)
-	return modNhat.Mul(sx, ty)
+	return FixedBaseMultiExp([]*FixedBase{fixedBaseOf(rp.S, rp.N), fixedBaseOf(rp.T, rp.N)}, []*big.Int{x, y})
+}
+
+// CommitSecret is Commit for secret x and y, as in the provers: it computes s^(x+r1) * t^(y+r2) * s^-r1 * t^-r2 for
+// random r1 and r2, so that the exponents used with the precomputed powers are independent of x and y
+func (rp *RingPedersenParams) CommitSecret(x *big.Int, y *big.Int) *big.Int {
+	r1, r2 := common.MustGetRandomInt(x.BitLen()+128), common.MustGetRandomInt(y.BitLen()+128)
+	bases := []*FixedBase{fixedBaseOf(rp.S, rp.N), fixedBaseOf(rp.T, rp.N)}
+	blinded := FixedBaseMultiExp(bases, []*big.Int{new(big.Int).Add(x, r1), new(big.Int).Add(y, r2)})
+	mask := FixedBaseMultiExp(bases, []*big.Int{new(big.Int).Neg(r1), new(big.Int).Neg(r2)})
+	return common.ModInt(rp.N).Mul(blinded, mask)
+}
+
+// ValidateCommitments returns true if every one of `cs` is in Z*_Nhat, as the ring-Pedersen commitments of a proof
+// received from a peer must be
+func (rp *RingPedersenParams) ValidateCommitments(cs ...*big.Int) bool {
+	return rp != nil && common.AreNumbersInMultiplicativeGroup(rp.N, cs...)
 }
 
 // returns a + bc
 func APlusBC(a *big.Int, b *big.Int, c *big.Int) *big.Int {
-	bc := new(big.Int).Mul(b, c)
-	return new(big.Int).Add(a, bc)
+	abc := new(big.Int).Mul(b, c)
+	return abc.Add(abc, a)
 }
 
 // returns a * (b^c) mod N
 func ATimesBToTheCModN(a *big.Int, b *big.Int, c *big.Int, N *big.Int) *big.Int {
 	modN := common.ModInt(N)
-	bc := modN.Exp(b, c)
-	abc := modN.Mul(a, bc)
-	return abc
+	abc := modN.Exp(b, c)
+	return modN.MulTo(abc, a, abc)
 }
@@ -45,9 +45,11 @@ var (
 	ec           elliptic.Curve
 	q            *big.Int
 	ell          *big.Int
+
+	Session = []byte("session")
 )
 
-func setUp(t *testing.T) {
+func setUp(t testing.TB) {
 	if privateKey != nil && publicKey != nil {
 		return
 	}
@@ -79,7 +81,7 @@ func GetSavedKeys(idx int) (sk *paillier.PrivateKey, pk *paillier.PublicKey, rp
 }
 
 // Uses saved parameters to avoid generating safe primes
-func fastSetUp(t *testing.T) {
+func fastSetUp(t testing.TB) {
 	var err error
 	privateKey, publicKey, ringPedersen, err = GetSavedKeys(0)
 	assert.NoError(t, err)
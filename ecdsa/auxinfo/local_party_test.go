@@ -0,0 +1,141 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+package auxinfo
+
+import (
+	"fmt"
+	"runtime"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/ecdsa/keygen"
+	"github.com/kisdex/mpc-lib/test"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+const (
+	testParticipants = test.TestParticipants
+	testThreshold    = test.TestThreshold
+)
+
+func setUp(level string) {
+	if err := common.SetLogLevel(level); err != nil {
+		panic(err)
+	}
+}
+
+func TestE2EConcurrentAndApply(t *testing.T) {
+	setUp("info")
+
+	// PHASE: load keygen fixtures
+	keys, pIDs, err := keygen.LoadKeygenTestFixtures(testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	// PHASE: aux-info
+	p2pCtx := tss.NewPeerContext(pIDs)
+	parties := make([]*LocalParty, 0, len(pIDs))
+
+	errCh := make(chan *tss.Error, len(pIDs))
+	outCh := make(chan tss.Message, len(pIDs))
+	endCh := make(chan *LocalPartySaveData, len(pIDs))
+
+	updater := test.SharedPartyUpdater
+
+	for i := 0; i < len(pIDs); i++ {
+		params := tss.NewParameters(tss.S256(), p2pCtx, pIDs[i], len(pIDs), testThreshold)
+		// re-use the pre-params of another fixture for speed
+		P := NewLocalParty(params, outCh, endCh, keys[(i+1)%len(keys)].LocalPreParams).(*LocalParty)
+		parties = append(parties, P)
+		go func(P *LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	auxs := make([]LocalPartySaveData, len(pIDs))
+	var ended int32
+auxinfo:
+	for {
+		fmt.Printf("ACTIVE GOROUTINES: %d\n", runtime.NumGoroutine())
+		select {
+		case err := <-errCh:
+			common.DefaultLogger().Errorf("Error: %s", err)
+			assert.FailNow(t, err.Error())
+			break auxinfo
+
+		case msg := <-outCh:
+			dest := msg.GetTo()
+			if dest == nil {
+				for _, P := range parties {
+					if P.PartyID().Index == msg.GetFrom().Index {
+						continue
+					}
+					go updater(P, msg, errCh)
+				}
+			} else {
+				if dest[0].Index == msg.GetFrom().Index {
+					t.Fatalf("party %d tried to send a message to itself (%d)", dest[0].Index, msg.GetFrom().Index)
+				}
+				go updater(parties[dest[0].Index], msg, errCh)
+			}
+
+		case save := <-endCh:
+			for i, P := range parties {
+				if save.PaillierSK.N.Cmp(P.data.PaillierSK.N) == 0 {
+					auxs[i] = *save
+				}
+			}
+			if atomic.AddInt32(&ended, 1) == int32(len(pIDs)) {
+				t.Logf("Done. Received aux info from %d participants", ended)
+				break auxinfo
+			}
+		}
+	}
+
+	// PHASE: apply the aux info to the keys
+	for i, aux := range auxs {
+		assert.True(t, aux.Validate())
+		key, err := aux.Apply(keys[i])
+		assert.NoError(t, err)
+		assert.Equal(t, 0, key.PaillierSK.N.Cmp(keys[(i+1)%len(keys)].PaillierSK.N))
+		assert.Equal(t, 0, key.Xi.Cmp(keys[i].Xi), "the secret share must not change")
+		for j := range auxs {
+			assert.Equal(t, 0, key.PaillierPKs[j].N.Cmp(auxs[j].PaillierSK.N))
+			assert.Equal(t, 0, key.NTildej[j].Cmp(auxs[j].NTildei))
+			assert.Equal(t, 0, key.H1j[j].Cmp(auxs[j].H1i))
+			assert.Equal(t, 0, key.H2j[j].Cmp(auxs[j].H2i))
+		}
+
+		// the aux info of another party cannot be applied
+		_, err = auxs[(i+1)%len(auxs)].Apply(keys[i])
+		assert.Error(t, err)
+	}
+}
+
+func FuzzMessages(f *testing.F) {
+	setUp("error")
+	keys, pIDs, err := keygen.LoadKeygenTestFixtures(2)
+	if err != nil {
+		f.Fatal(err)
+	}
+	p2pCtx := tss.NewPeerContext(pIDs)
+	outCh := make(chan tss.Message, len(pIDs)*len(pIDs))
+	endCh := make(chan *LocalPartySaveData, len(pIDs))
+	parties := make([]tss.Party, 0, len(pIDs))
+	for i := range pIDs {
+		params := tss.NewParameters(tss.S256(), p2pCtx, pIDs[i], len(pIDs), 1)
+		parties = append(parties, NewLocalParty(params, outCh, endCh, keys[(i+1)%len(keys)].LocalPreParams))
+	}
+	transcript, tssErr := test.RunSequentially(parties, outCh)
+	if tssErr != nil {
+		f.Fatal(tssErr)
+	}
+	test.FuzzWireMessages(f, tss.S256(), transcript)
+}
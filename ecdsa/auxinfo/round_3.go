@@ -0,0 +1,94 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+package auxinfo
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+func (round *round3) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started"))
+	}
+	round.number = 3
+	round.started = true
+	round.resetOK()
+
+	Ps := round.Parties().IDs()
+	PIdx := round.PartyID().Index
+
+	// 1. verify the proofs about the paillier modulus of every Pj
+	chs := make([]chan error, len(Ps))
+	pool := round.NewWorkerPool()
+	for j := range Ps {
+		if j == PIdx {
+			continue
+		}
+		chs[j] = make(chan error, 1)
+		ContextJ := common.AppendBigIntToBytesSlice(round.temp.ssid, big.NewInt(int64(j)))
+		ch := chs[j]
+		pool.GoFor(Ps[j], func() {
+			if !round.Parameters.NoProofMod() {
+				r2msg2 := round.temp.auxRound2Message2s[j].Content().(*AuxRound2Message2)
+				modProof, err := r2msg2.UnmarshalModProof()
+				if err != nil || !modProof.Verify(ContextJ, round.save.PaillierPKs[j].N) {
+					ch <- errors.New("modProof verify failed")
+					return
+				}
+			}
+			if !round.Parameters.NoProofFac() {
+				r2msg1 := round.temp.auxRound2Message1s[j].Content().(*AuxRound2Message1)
+				facProof, err := r2msg1.UnmarshalFacProof()
+				if err != nil || !facProof.Verify(ContextJ, round.EC(), round.save.PaillierPKs[j].N, round.save.NTildei,
+					round.save.H1i, round.save.H2i) {
+					ch <- errors.New("facProof verify failed")
+					return
+				}
+			}
+			ch <- nil
+		})
+	}
+
+	// consume the channels (end the goroutines)
+	culprits := make([]*tss.PartyID, 0, len(Ps)) // who caused the error(s)
+	var multiErr error
+	for j, Pj := range Ps {
+		if j == PIdx {
+			continue
+		}
+		if err := <-chs[j]; err != nil {
+			culprits = append(culprits, Pj)
+			multiErr = multierror.Append(multiErr, err)
+		}
+	}
+	if len(culprits) > 0 {
+		return round.WrapError(multiErr, culprits...)
+	}
+
+	round.temp.preParams = nil
+	round.end <- round.save
+	return nil
+}
+
+func (round *round3) CanAccept(msg tss.ParsedMessage) bool {
+	// not expecting any incoming messages in this round
+	return false
+}
+
+func (round *round3) Update() (bool, *tss.Error) {
+	// not expecting any incoming messages in this round
+	return false, nil
+}
+
+func (round *round3) NextRound() tss.Round {
+	return nil // finished!
+}
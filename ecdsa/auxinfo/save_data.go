@@ -0,0 +1,89 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+package auxinfo
+
+import (
+	"encoding/hex"
+	"errors"
+	"math/big"
+
+	"github.com/kisdex/mpc-lib/crypto/paillier"
+	"github.com/kisdex/mpc-lib/ecdsa/keygen"
+)
+
+// Everything in LocalPartySaveData is saved locally to user's HD when done.
+// It holds the Paillier and Ring-Pedersen parameters of a committee, independently of any ECDSA key.
+type LocalPartySaveData struct {
+	keygen.LocalPreParams
+
+	// original indexes (ki) of the committee
+	Ks []*big.Int
+
+	// n-tilde, h1, h2 for range proofs
+	NTildej, H1j, H2j []*big.Int
+
+	// public keys (pkj) for Paillier encryption
+	PaillierPKs []*paillier.PublicKey
+}
+
+func NewLocalPartySaveData(partyCount int) (saveData LocalPartySaveData) {
+	saveData.Ks = make([]*big.Int, partyCount)
+	saveData.NTildej = make([]*big.Int, partyCount)
+	saveData.H1j, saveData.H2j = make([]*big.Int, partyCount), make([]*big.Int, partyCount)
+	saveData.PaillierPKs = make([]*paillier.PublicKey, partyCount)
+	return
+}
+
+// Validate checks that every field of the aux info is present
+func (aux LocalPartySaveData) Validate() bool {
+	if !aux.LocalPreParams.ValidateWithProof() || len(aux.Ks) == 0 {
+		return false
+	}
+	partyCount := len(aux.Ks)
+	if len(aux.NTildej) != partyCount || len(aux.H1j) != partyCount || len(aux.H2j) != partyCount || len(aux.PaillierPKs) != partyCount {
+		return false
+	}
+	for j := range aux.Ks {
+		if aux.Ks[j] == nil || aux.NTildej[j] == nil || aux.H1j[j] == nil || aux.H2j[j] == nil ||
+			aux.PaillierPKs[j] == nil || aux.PaillierPKs[j].N == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Apply returns a copy of `key` that uses this aux info instead of the Paillier and Ring-Pedersen parameters it was created with.
+// The same aux info may be applied to every key held by the committee.
+func (aux LocalPartySaveData) Apply(key keygen.LocalPartySaveData) (keygen.LocalPartySaveData, error) {
+	if !aux.Validate() {
+		return key, errors.New("aux info failed to validate")
+	}
+	keysToIndices := make(map[string]int, len(aux.Ks))
+	for j, kj := range aux.Ks {
+		keysToIndices[hex.EncodeToString(kj.Bytes())] = j
+	}
+	newKey := key
+	newKey.LocalPreParams = aux.LocalPreParams
+	newKey.NTildej = make([]*big.Int, len(key.Ks))
+	newKey.H1j, newKey.H2j = make([]*big.Int, len(key.Ks)), make([]*big.Int, len(key.Ks))
+	newKey.PaillierPKs = make([]*paillier.PublicKey, len(key.Ks))
+	for j, kj := range key.Ks {
+		auxIdx, ok := keysToIndices[hex.EncodeToString(kj.Bytes())]
+		if !ok {
+			return key, errors.New("aux info does not cover every party of the key")
+		}
+		newKey.NTildej[j] = aux.NTildej[auxIdx]
+		newKey.H1j[j], newKey.H2j[j] = aux.H1j[auxIdx], aux.H2j[auxIdx]
+		newKey.PaillierPKs[j] = aux.PaillierPKs[auxIdx]
+	}
+	if key.ShareID == nil {
+		return key, errors.New("key is missing its ShareID")
+	}
+	if i, ok := keysToIndices[hex.EncodeToString(key.ShareID.Bytes())]; !ok || aux.PaillierPKs[i].N.Cmp(aux.PaillierSK.N) != 0 {
+		return key, errors.New("aux info was not made by the party owning the key")
+	}
+	return newKey, nil
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2023, Circle Internet Financial, LTD. All rights reserved.
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cggplus
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto"
+	"github.com/kisdex/mpc-lib/crypto/adaptor"
+	"github.com/kisdex/mpc-lib/ecdsa/keygen"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+func TestE2EAdaptorSign(t *testing.T) {
+	SetUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	p2pCtx := tss.NewPeerContext(signPIDs)
+
+	adaptorSecret := common.GetRandomPositiveInt(tss.S256().Params().N)
+	T := crypto.ScalarBaseMult(tss.S256(), adaptorSecret)
+
+	msg := big.NewInt(42)
+	outCh := make(chan tss.Message, len(signPIDs)*len(signPIDs)*3)
+	adaptorCh := make(chan *adaptor.PreSignature, len(signPIDs))
+	parties := make([]*LocalParty, 0, len(signPIDs))
+	for i := range signPIDs {
+		params := tss.NewParameters(tss.S256(), p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+		parties = append(parties, NewLocalPartyWithAdaptor(msg, params, keys[i], T, outCh, adaptorCh).(*LocalParty))
+	}
+	runParties(t, parties, outCh, func() bool { return len(adaptorCh) == len(parties) })
+
+	pk := keys[0].ECDSAPub.ToECDSAPubKey()
+	var first *adaptor.PreSignature
+	for i := range parties {
+		preSig := <-adaptorCh
+		if i == 0 {
+			first = preSig
+		}
+		assert.Equal(t, 0, first.S.Cmp(preSig.S), "all parties must output the same pre-signature")
+		assert.True(t, preSig.Verify(keys[0].ECDSAPub), "pre-signature must verify")
+		assert.False(t, ecdsa.Verify(pk, msg.Bytes(), preSig.Rx(), preSig.S), "pre-signature must not be a valid signature")
+	}
+
+	sig, err := first.Complete(adaptorSecret)
+	assert.NoError(t, err)
+	r, s := new(big.Int).SetBytes(sig.R), new(big.Int).SetBytes(sig.S)
+	assert.True(t, ecdsa.Verify(pk, msg.Bytes(), r, s), "ecdsa verify must pass")
+
+	extracted, err := first.Extract(sig, T)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, extracted.Cmp(adaptorSecret), "extracted adaptor secret must match")
+}
@@ -0,0 +1,79 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package cggplus
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/sha512"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/ecdsa/keygen"
+	"github.com/kisdex/mpc-lib/test"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+func TestE2ESignCurves(t *testing.T) {
+	SetUp("info")
+
+	fixtures, _, err := keygen.LoadKeygenTestFixtures(testParticipants)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	preParams := make([]keygen.LocalPreParams, len(fixtures))
+	for i, fixture := range fixtures {
+		preParams[i] = fixture.LocalPreParams
+	}
+	p256Digest, p384Digest := sha256.Sum256([]byte("P-256")), sha512.Sum384([]byte("P-384"))
+	// a message of StarkNet is a field element of up to 251 bits
+	starkDigest := sha256.Sum256([]byte("Stark"))
+	for _, tc := range []struct {
+		name string
+		ec   elliptic.Curve
+		msg  *big.Int
+	}{
+		{"P-256", elliptic.P256(), new(big.Int).SetBytes(p256Digest[:])},
+		{"P-384", elliptic.P384(), new(big.Int).SetBytes(p384Digest[:])},
+		{"Stark", tss.StarkCurve(), new(big.Int).Rsh(new(big.Int).SetBytes(starkDigest[:]), 5)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			keys, pIDs, err := keygen.GenerateKeygenTestFixturesWithPreParams(tc.ec, testThreshold, tss.SecurityLevel112, 1, preParams)
+			if !assert.NoError(t, err, "keygen should succeed") {
+				return
+			}
+			signPIDs := pIDs[:testThreshold+1]
+			p2pCtx := tss.NewPeerContext(signPIDs)
+			outCh := make(chan tss.Message, len(signPIDs)*len(signPIDs)*3)
+			endCh := make(chan *common.SignatureData, len(signPIDs))
+			parties := make([]tss.Party, 0, len(signPIDs))
+			for i, pID := range signPIDs {
+				params := tss.NewParameters(tc.ec, p2pCtx, pID, len(signPIDs), testThreshold)
+				parties = append(parties, NewLocalParty(tc.msg, params, keys[i], outCh, endCh))
+			}
+			if _, tssErr := test.RunSequentially(parties, outCh); !assert.Nil(t, tssErr, "signing should succeed") {
+				return
+			}
+
+			pk := keys[0].ECDSAPub.ToECDSAPubKey()
+			byteSize := (tc.ec.Params().BitSize + 7) / 8
+			digest := common.ECDSADigest(tc.ec.Params().N, tc.msg)
+			for range parties {
+				sig := (<-endCh).Signature
+				if !assert.Len(t, sig, 2*byteSize) {
+					continue
+				}
+				r, s := new(big.Int).SetBytes(sig[:byteSize]), new(big.Int).SetBytes(sig[byteSize:])
+				assert.True(t, ecdsa.Verify(pk, digest, r, s), "ecdsa verify must pass on %s", tc.name)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2023, Circle Internet Financial, LTD. All rights reserved.
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cggplus
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto/paillier"
+	"github.com/kisdex/mpc-lib/ecdsa/keygen"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+// opaqueDecrypter stands in for a key kept in an HSM: it only exposes the operations of paillier.Decrypter
+type opaqueDecrypter struct {
+	sk    *paillier.PrivateKey
+	calls atomic.Int32
+}
+
+func (d *opaqueDecrypter) Public() *paillier.PublicKey {
+	return d.sk.Public()
+}
+
+func (d *opaqueDecrypter) Decrypt(c *big.Int) (*big.Int, error) {
+	d.calls.Add(1)
+	return d.sk.Decrypt(c)
+}
+
+func (d *opaqueDecrypter) DecryptFull(c *big.Int) (*big.Int, *big.Int, error) {
+	d.calls.Add(1)
+	return d.sk.DecryptFull(c)
+}
+
+func TestE2ESignWithPaillierDecrypter(t *testing.T) {
+	SetUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	p2pCtx := tss.NewPeerContext(signPIDs)
+
+	msg := big.NewInt(42)
+	outCh := make(chan tss.Message, len(signPIDs)*len(signPIDs)*3)
+	endCh := make(chan *common.SignatureData, len(signPIDs))
+	parties := make([]*LocalParty, 0, len(signPIDs))
+	decrypters := make([]*opaqueDecrypter, len(signPIDs))
+	for i := range signPIDs {
+		params := tss.NewParameters(tss.S256(), p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+		decrypters[i] = &opaqueDecrypter{sk: keys[i].PaillierSK}
+		keys[i].PaillierSK = nil // the secret key must not be needed outside of the decrypter
+		P := NewLocalParty(msg, params, keys[i], outCh, endCh).(*LocalParty)
+		P.SetPaillierDecrypter(decrypters[i])
+		parties = append(parties, P)
+	}
+	runParties(t, parties, outCh, func() bool { return len(endCh) == len(parties) })
+
+	pk := keys[0].ECDSAPub.ToECDSAPubKey()
+	for i := range parties {
+		sig := (<-endCh).Signature
+		r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])
+		assert.True(t, ecdsa.Verify(pk, msg.Bytes(), r, s), "ecdsa verify must pass")
+		assert.NotZero(t, decrypters[i].calls.Load(), "decryptions must be delegated to the decrypter")
+	}
+}
@@ -204,6 +204,8 @@ type SignRound2Message2 struct {
 
 	PointGamma [][]byte `protobuf:"bytes,1,rep,name=point_gamma,json=pointGamma,proto3" json:"point_gamma,omitempty"`
 	PsiPrime   [][]byte `protobuf:"bytes,2,rep,name=psi_prime,json=psiPrime,proto3" json:"psi_prime,omitempty"`
+	// digests of the broadcasts received in round 1, by sender (echo broadcast)
+	Echo [][]byte `protobuf:"bytes,3,rep,name=echo,proto3" json:"echo,omitempty"`
 }
 
 func (x *SignRound2Message2) Reset() {
@@ -252,6 +254,13 @@ func (x *SignRound2Message2) GetPsiPrime() [][]byte {
 	return nil
 }
 
+func (x *SignRound2Message2) GetEcho() [][]byte {
+	if x != nil {
+		return x.Echo
+	}
+	return nil
+}
+
 // Represents a broadcast message during Round 3 of the accountable cgg+ ECDSA TSS signing protocol.
 type SignRound3Message struct {
 	state         protoimpl.MessageState
@@ -264,6 +273,11 @@ type SignRound3Message struct {
 	PsiPrimePrime [][]byte `protobuf:"bytes,4,rep,name=psi_prime_prime,json=psiPrimePrime,proto3" json:"psi_prime_prime,omitempty"`
 	HProof        [][]byte `protobuf:"bytes,5,rep,name=h_proof,json=hProof,proto3" json:"h_proof,omitempty"`
 	DeltaProof    [][]byte `protobuf:"bytes,6,rep,name=delta_proof,json=deltaProof,proto3" json:"delta_proof,omitempty"`
+	// only set when signing against an adaptor point T: gamma_i*T and a proof that it shares gamma_i with Gamma_i
+	AdaptorGamma [][]byte `protobuf:"bytes,7,rep,name=adaptor_gamma,json=adaptorGamma,proto3" json:"adaptor_gamma,omitempty"`
+	AdaptorProof [][]byte `protobuf:"bytes,8,rep,name=adaptor_proof,json=adaptorProof,proto3" json:"adaptor_proof,omitempty"`
+	// digests of the broadcasts received in round 2, by sender (echo broadcast)
+	Echo [][]byte `protobuf:"bytes,9,rep,name=echo,proto3" json:"echo,omitempty"`
 }
 
 func (x *SignRound3Message) Reset() {
@@ -340,11 +354,35 @@ func (x *SignRound3Message) GetDeltaProof() [][]byte {
 	return nil
 }
 
+func (x *SignRound3Message) GetAdaptorGamma() [][]byte {
+	if x != nil {
+		return x.AdaptorGamma
+	}
+	return nil
+}
+
+func (x *SignRound3Message) GetAdaptorProof() [][]byte {
+	if x != nil {
+		return x.AdaptorProof
+	}
+	return nil
+}
+
+func (x *SignRound3Message) GetEcho() [][]byte {
+	if x != nil {
+		return x.Echo
+	}
+	return nil
+}
+
 // Represents a broadcast message during Round 4 of the accountable cgg+ ECDSA TSS signing protocol.
 type SignRound4Message struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	// digests of the broadcasts received in round 3, by sender (echo broadcast)
+	Echo [][]byte `protobuf:"bytes,1,rep,name=echo,proto3" json:"echo,omitempty"`
 }
 
 func (x *SignRound4Message) Reset() {
@@ -379,6 +417,13 @@ func (*SignRound4Message) Descriptor() ([]byte, []int) {
 	return file_protob_ecdsa_cggplus_proto_rawDescGZIP(), []int{4}
 }
 
+func (x *SignRound4Message) GetEcho() [][]byte {
+	if x != nil {
+		return x.Echo
+	}
+	return nil
+}
+
 // Represents a broadcast message during Round 5 of the accountable cgg+ ECDSA TSS signing protocol.
 type SignRound5Message struct {
 	state         protoimpl.MessageState
@@ -474,36 +519,45 @@ var file_protob_ecdsa_cggplus_proto_rawDesc = []byte{
 	0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x62, 0x69, 0x67, 0x46, 0x48, 0x61, 0x74,
 	0x12, 0x10, 0x0a, 0x03, 0x70, 0x73, 0x69, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x03, 0x70,
 	0x73, 0x69, 0x12, 0x17, 0x0a, 0x07, 0x70, 0x73, 0x69, 0x5f, 0x68, 0x61, 0x74, 0x18, 0x07, 0x20,
-	0x03, 0x28, 0x0c, 0x52, 0x06, 0x70, 0x73, 0x69, 0x48, 0x61, 0x74, 0x22, 0x52, 0x0a, 0x12, 0x53,
+	0x03, 0x28, 0x0c, 0x52, 0x06, 0x70, 0x73, 0x69, 0x48, 0x61, 0x74, 0x22, 0x66, 0x0a, 0x12, 0x53,
 	0x69, 0x67, 0x6e, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x32, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
 	0x32, 0x12, 0x1f, 0x0a, 0x0b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x5f, 0x67, 0x61, 0x6d, 0x6d, 0x61,
 	0x18, 0x01, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0a, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x47, 0x61, 0x6d,
 	0x6d, 0x61, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x73, 0x69, 0x5f, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x18,
-	0x02, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x08, 0x70, 0x73, 0x69, 0x50, 0x72, 0x69, 0x6d, 0x65, 0x22,
-	0xb5, 0x01, 0x0a, 0x11, 0x53, 0x69, 0x67, 0x6e, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x33, 0x4d, 0x65,
-	0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x64, 0x65, 0x6c, 0x74, 0x61, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x64, 0x65, 0x6c, 0x74, 0x61, 0x12, 0x1a, 0x0a, 0x08, 0x62,
-	0x69, 0x67, 0x44, 0x65, 0x6c, 0x74, 0x61, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x08, 0x62,
-	0x69, 0x67, 0x44, 0x65, 0x6c, 0x74, 0x61, 0x12, 0x0c, 0x0a, 0x01, 0x68, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x0c, 0x52, 0x01, 0x68, 0x12, 0x26, 0x0a, 0x0f, 0x70, 0x73, 0x69, 0x5f, 0x70, 0x72, 0x69,
-	0x6d, 0x65, 0x5f, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0d,
-	0x70, 0x73, 0x69, 0x50, 0x72, 0x69, 0x6d, 0x65, 0x50, 0x72, 0x69, 0x6d, 0x65, 0x12, 0x17, 0x0a,
-	0x07, 0x68, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x06,
-	0x68, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x1f, 0x0a, 0x0b, 0x64, 0x65, 0x6c, 0x74, 0x61, 0x5f,
-	0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0a, 0x64, 0x65, 0x6c,
-	0x74, 0x61, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x22, 0x13, 0x0a, 0x11, 0x53, 0x69, 0x67, 0x6e, 0x52,
-	0x6f, 0x75, 0x6e, 0x64, 0x34, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x94, 0x01, 0x0a,
-	0x11, 0x53, 0x69, 0x67, 0x6e, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x35, 0x4d, 0x65, 0x73, 0x73, 0x61,
-	0x67, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x69, 0x67, 0x6d, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0c, 0x52, 0x05, 0x73, 0x69, 0x67, 0x6d, 0x61, 0x12, 0x1a, 0x0a, 0x09, 0x62, 0x69, 0x67, 0x5f,
-	0x68, 0x5f, 0x68, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x62, 0x69, 0x67,
-	0x48, 0x48, 0x61, 0x74, 0x12, 0x25, 0x0a, 0x0f, 0x62, 0x69, 0x67, 0x5f, 0x68, 0x5f, 0x68, 0x61,
-	0x74, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0c, 0x62,
-	0x69, 0x67, 0x48, 0x48, 0x61, 0x74, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x26, 0x0a, 0x0f, 0x62,
-	0x69, 0x67, 0x5f, 0x73, 0x69, 0x67, 0x6d, 0x61, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x04,
-	0x20, 0x03, 0x28, 0x0c, 0x52, 0x0d, 0x62, 0x69, 0x67, 0x53, 0x69, 0x67, 0x6d, 0x61, 0x50, 0x72,
-	0x6f, 0x6f, 0x66, 0x42, 0x0f, 0x5a, 0x0d, 0x65, 0x63, 0x64, 0x73, 0x61, 0x2f, 0x63, 0x67, 0x67,
-	0x70, 0x6c, 0x75, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x02, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x08, 0x70, 0x73, 0x69, 0x50, 0x72, 0x69, 0x6d, 0x65, 0x12,
+	0x12, 0x0a, 0x04, 0x65, 0x63, 0x68, 0x6f, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x04, 0x65,
+	0x63, 0x68, 0x6f, 0x22, 0x93, 0x02, 0x0a, 0x11, 0x53, 0x69, 0x67, 0x6e, 0x52, 0x6f, 0x75, 0x6e,
+	0x64, 0x33, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x64, 0x65, 0x6c,
+	0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x64, 0x65, 0x6c, 0x74, 0x61, 0x12,
+	0x1a, 0x0a, 0x08, 0x62, 0x69, 0x67, 0x44, 0x65, 0x6c, 0x74, 0x61, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x0c, 0x52, 0x08, 0x62, 0x69, 0x67, 0x44, 0x65, 0x6c, 0x74, 0x61, 0x12, 0x0c, 0x0a, 0x01, 0x68,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x01, 0x68, 0x12, 0x26, 0x0a, 0x0f, 0x70, 0x73, 0x69,
+	0x5f, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x5f, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x03,
+	0x28, 0x0c, 0x52, 0x0d, 0x70, 0x73, 0x69, 0x50, 0x72, 0x69, 0x6d, 0x65, 0x50, 0x72, 0x69, 0x6d,
+	0x65, 0x12, 0x17, 0x0a, 0x07, 0x68, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x05, 0x20, 0x03,
+	0x28, 0x0c, 0x52, 0x06, 0x68, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x1f, 0x0a, 0x0b, 0x64, 0x65,
+	0x6c, 0x74, 0x61, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0c, 0x52,
+	0x0a, 0x64, 0x65, 0x6c, 0x74, 0x61, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x23, 0x0a, 0x0d, 0x61,
+	0x64, 0x61, 0x70, 0x74, 0x6f, 0x72, 0x5f, 0x67, 0x61, 0x6d, 0x6d, 0x61, 0x18, 0x07, 0x20, 0x03,
+	0x28, 0x0c, 0x52, 0x0c, 0x61, 0x64, 0x61, 0x70, 0x74, 0x6f, 0x72, 0x47, 0x61, 0x6d, 0x6d, 0x61,
+	0x12, 0x23, 0x0a, 0x0d, 0x61, 0x64, 0x61, 0x70, 0x74, 0x6f, 0x72, 0x5f, 0x70, 0x72, 0x6f, 0x6f,
+	0x66, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0c, 0x61, 0x64, 0x61, 0x70, 0x74, 0x6f, 0x72,
+	0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x12, 0x0a, 0x04, 0x65, 0x63, 0x68, 0x6f, 0x18, 0x09, 0x20,
+	0x03, 0x28, 0x0c, 0x52, 0x04, 0x65, 0x63, 0x68, 0x6f, 0x22, 0x27, 0x0a, 0x11, 0x53, 0x69, 0x67,
+	0x6e, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x34, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x65, 0x63, 0x68, 0x6f, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x04, 0x65, 0x63,
+	0x68, 0x6f, 0x22, 0x94, 0x01, 0x0a, 0x11, 0x53, 0x69, 0x67, 0x6e, 0x52, 0x6f, 0x75, 0x6e, 0x64,
+	0x35, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x69, 0x67, 0x6d,
+	0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x73, 0x69, 0x67, 0x6d, 0x61, 0x12, 0x1a,
+	0x0a, 0x09, 0x62, 0x69, 0x67, 0x5f, 0x68, 0x5f, 0x68, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x07, 0x62, 0x69, 0x67, 0x48, 0x48, 0x61, 0x74, 0x12, 0x25, 0x0a, 0x0f, 0x62, 0x69,
+	0x67, 0x5f, 0x68, 0x5f, 0x68, 0x61, 0x74, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x03, 0x20,
+	0x03, 0x28, 0x0c, 0x52, 0x0c, 0x62, 0x69, 0x67, 0x48, 0x48, 0x61, 0x74, 0x50, 0x72, 0x6f, 0x6f,
+	0x66, 0x12, 0x26, 0x0a, 0x0f, 0x62, 0x69, 0x67, 0x5f, 0x73, 0x69, 0x67, 0x6d, 0x61, 0x5f, 0x70,
+	0x72, 0x6f, 0x6f, 0x66, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0d, 0x62, 0x69, 0x67, 0x53,
+	0x69, 0x67, 0x6d, 0x61, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x42, 0x0f, 0x5a, 0x0d, 0x65, 0x63, 0x64,
+	0x73, 0x61, 0x2f, 0x63, 0x67, 0x67, 0x70, 0x6c, 0x75, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
 }
 
 var (
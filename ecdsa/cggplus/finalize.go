@@ -15,9 +15,10 @@ import (
 	"crypto/ecdsa"
 	"errors"
 	"math/big"
-	"sync"
 
 	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto"
+	"github.com/kisdex/mpc-lib/crypto/adaptor"
 	"github.com/kisdex/mpc-lib/crypto/zkproofs"
 	"github.com/kisdex/mpc-lib/tss"
 )
@@ -32,13 +33,7 @@ func (round *finalization) Start() *tss.Error {
 
 	partyCount := len(round.Parties().IDs())
 	errChs := make(chan *tss.Error, partyCount*partyCount)
-	wg := sync.WaitGroup{}
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		round.VerifyRound5Messages(errChs)
-	}()
-	wg.Wait()
+	round.VerifyRound5Messages(errChs)
 	close(errChs)
 	err := round.WrapErrorChs(round.PartyID(), errChs, "Failed to process round 5 messages")
 	if err != nil {
@@ -46,6 +41,9 @@ func (round *finalization) Start() *tss.Error {
 	}
 
 	sumS := round.GetSumS()
+	if round.temp.adaptorPoint != nil {
+		return round.finalizePreSignature(sumS)
+	}
 
 	recid := 0
 	// byte v = if(R.X > curve.N) then 2 else 0) | (if R.Y.IsEven then 0 else 1);
@@ -56,18 +54,12 @@ func (round *finalization) Start() *tss.Error {
 		recid |= 1
 	}
 
-	// This is copied from:
-	// https://github.com/btcsuite/btcd/blob/c26ffa870fd817666a857af1bf6498fabba1ffe3/btcec/signature.go#L442-L444
-	// This is needed because of tendermint checks here:
-	// https://github.com/tendermint/tendermint/blob/d9481e3648450cb99e15c6a070c1fb69aa0c255b/crypto/secp256k1/secp256k1_nocgo.go#L43-L47
-	secp256k1halfN := new(big.Int).Rsh(round.Params().EC().Params().N, 1)
-	if sumS.Cmp(secp256k1halfN) > 0 {
-		sumS.Sub(round.Params().EC().Params().N, sumS)
-		recid ^= 1
+	if !round.Params().NoLowS() {
+		sumS, recid = common.NormalizeLowS(round.Params().EC().Params().N, sumS, recid)
 	}
 
 	// save the signature for final output
-	bitSizeInBytes := round.Params().EC().Params().BitSize / 8
+	bitSizeInBytes := (round.Params().EC().Params().BitSize + 7) / 8
 	round.data.R = padToLengthBytesInPlace(round.temp.rx.Bytes(), bitSizeInBytes)
 	round.data.S = padToLengthBytesInPlace(sumS.Bytes(), bitSizeInBytes)
 	round.data.Signature = append(round.data.R, round.data.S...)
@@ -79,12 +71,39 @@ func (round *finalization) Start() *tss.Error {
 		X:     round.key.ECDSAPub.X(),
 		Y:     round.key.ECDSAPub.Y(),
 	}
-	ok := ecdsa.Verify(&pk, round.temp.m.Bytes(), round.temp.rx, sumS)
+	ok := ecdsa.Verify(&pk, common.ECDSADigest(round.Params().EC().Params().N, round.temp.m), round.temp.rx, sumS)
 	if !ok {
+		if err := round.verifyDeferredProofs(); err != nil {
+			return err
+		}
 		return round.WrapError(errors.New("signature verification failed"))
 	}
 
-	round.end <- *round.data
+	round.end <- round.data
+	round.CleanUpPostSigningData()
+	return nil
+}
+
+// finalizePreSignature outputs the adaptor pre-signature; it must not be normalized to low-S as
+// the adaptor secret is later recovered from s'/s
+func (round *finalization) finalizePreSignature(sumS *big.Int) *tss.Error {
+	bigR, err := crypto.NewECPoint(round.Params().EC(), round.temp.rx, round.temp.ry)
+	if err != nil {
+		return round.WrapError(err)
+	}
+	preSig := &adaptor.PreSignature{
+		R:    bigR,
+		RHat: round.temp.bigRHat,
+		S:    sumS,
+		M:    round.temp.m,
+	}
+	if !preSig.Verify(round.key.ECDSAPub) {
+		if err := round.verifyDeferredProofs(); err != nil {
+			return err
+		}
+		return round.WrapError(errors.New("pre-signature verification failed"))
+	}
+	round.adaptorEnd <- preSig
 	round.CleanUpPostSigningData()
 	return nil
 }
@@ -140,17 +159,15 @@ func (round *finalization) ComputeBigSigma(i int, bigHHat *big.Int, bigSigma []*
 
 func (round *finalization) VerifyRound5Messages(errChs chan *tss.Error) {
 	i := round.PartyID().Index
-	rp := round.key.GetRingPedersen(i)
-	wg := sync.WaitGroup{}
+	batches := round.newProofBatches()
+	pool := round.NewWorkerPool()
 	bigSigma := make([]*big.Int, len(round.Parties().IDs()))
 	for j, msg := range round.temp.signRound5Messages {
 		r5msg := msg.Content().(*SignRound5Message)
 		if j == i {
 			continue
 		}
-		wg.Add(1)
-		go func(j int, r5msg *SignRound5Message) {
-			defer wg.Done()
+		pool.GoFor(msg.GetFrom(), func() {
 			Pj := round.Parties().IDs()[j]
 			bigHHat := r5msg.UnmarshalBigHHat()
 			terr := round.ComputeBigSigma(j, bigHHat, bigSigma)
@@ -167,9 +184,12 @@ func (round *finalization) VerifyRound5Messages(errChs chan *tss.Error) {
 				D:   bigHHat,
 				X:   round.temp.bigWs[j],
 			}
+			k := round.proofIndex(j, i)
 			proof, err := r5msg.UnmarshalBigHHatProof(round.Params().EC())
-			if err != nil || !proof[i].Verify(statementBigHHat, rp) {
-				errChs <- round.WrapError(errors.New("bad proof"), Pj)
+			if err != nil || !round.verifyProof(Pj, func() bool {
+				return proofAt(proof, k).VerifyBatch(round.temp.ssid, statementBigHHat, batches[k], j)
+			}) {
+				errChs <- round.WrapError(errors.New("bad proof"), Pj).WithCode(tss.ErrProofVerifyFailed)
 				return
 			}
 
@@ -187,13 +207,18 @@ func (round *finalization) VerifyRound5Messages(errChs chan *tss.Error) {
 				errChs <- round.WrapError(errors.New("failed to parse proof"), Pj)
 				return
 			}
-			if !proofSigma[i].Verify(statement, rp) {
-				errChs <- round.WrapError(errors.New("failed to verify proof"), Pj)
+			if !round.verifyProof(Pj, func() bool {
+				return proofAt(proofSigma, k).VerifyBatch(round.temp.ssid, statement, batches[k], j)
+			}) {
+				errChs <- round.WrapError(errors.New("failed to verify proof"), Pj).WithCode(tss.ErrProofVerifyFailed)
 				return
 			}
-		}(j, r5msg)
+		})
+	}
+	pool.Wait()
+	for _, culprit := range round.verifyBatches(batches) {
+		errChs <- round.WrapError(errors.New("bad proof"), culprit).WithCode(tss.ErrProofVerifyFailed)
 	}
-	wg.Wait()
 }
 
 func (round *finalization) CleanUpPostSigningData() {
@@ -201,6 +226,10 @@ func (round *finalization) CleanUpPostSigningData() {
 	round.temp.bigFHat = nil
 	round.temp.bigDHat = nil
 	round.temp.sigma = nil
+	round.temp.deferredProofs = nil
+	round.temp.r3Digests = nil
+	clear(round.temp.signRound4Messages)
+	clear(round.temp.signRound5Messages)
 }
 
 func (round *finalization) CanAccept(msg tss.ParsedMessage) bool {
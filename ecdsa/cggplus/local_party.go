@@ -15,9 +15,12 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"sync"
 
 	"github.com/kisdex/mpc-lib/common"
 	"github.com/kisdex/mpc-lib/crypto"
+	"github.com/kisdex/mpc-lib/crypto/adaptor"
+	"github.com/kisdex/mpc-lib/crypto/paillier"
 	"github.com/kisdex/mpc-lib/crypto/zkproofs"
 	"github.com/kisdex/mpc-lib/ecdsa/keygen"
 	"github.com/kisdex/mpc-lib/tss"
@@ -27,6 +30,7 @@ import (
 // Implements Stringer
 var _ tss.Party = (*LocalParty)(nil)
 var _ fmt.Stringer = (*LocalParty)(nil)
+var _ tss.Zeroizer = (*LocalParty)(nil)
 
 type (
 	LocalParty struct {
@@ -37,9 +41,15 @@ type (
 		temp localTempData
 		data common.SignatureData
 
+		// set when signing online from a presignature
+		presig   *PreSignatureData
+		markUsed MarkUsedFunc
+
 		// outbound messaging
-		out chan<- tss.Message
-		end chan<- common.SignatureData
+		out        chan<- tss.Message
+		end        chan<- *common.SignatureData
+		presigEnd  chan<- *PreSignatureData
+		adaptorEnd chan<- *adaptor.PreSignature
 	}
 
 	localMessageStore struct {
@@ -54,10 +64,19 @@ type (
 
 	localTempData struct {
 		localMessageStore
+		ssid []byte
 		m,
 		keyDerivationDelta,
 		w *big.Int
-		bigWs []*crypto.ECPoint
+		bigWs        []*crypto.ECPoint
+		adaptorPoint *crypto.ECPoint
+		paillierSK   paillier.Decrypter // set when the Paillier key is not used from the save data
+		// checks of the proofs of the peers, when the party defers them
+		deferredProofs   []proofCheck
+		deferredProofsMu sync.Mutex
+		// echo digests of the round 2 and round 3 broadcasts, which outlive the messages
+		r2Digests,
+		r3Digests [][]byte
 
 		// round 1
 		k,
@@ -78,14 +97,16 @@ type (
 		delta,
 		alpha,
 		alphaHat []*big.Int // [sender] -> self
-		Gamma    *crypto.ECPoint
-		bigDelta []*crypto.ECPoint // [sender] -> self
+		Gamma        *crypto.ECPoint
+		bigDelta     []*crypto.ECPoint // [sender] -> self
+		adaptorGamma []*crypto.ECPoint // [sender] -> self
 		bigH,
 		chi *big.Int
 
 		// round 4
 		rx,
 		ry *big.Int
+		bigRHat *crypto.ECPoint // k^-1 * G when signing against an adaptor point
 
 		// round 5
 		sigma *big.Int
@@ -97,7 +118,7 @@ func NewLocalParty(
 	params *tss.Parameters,
 	key keygen.LocalPartySaveData,
 	out chan<- tss.Message,
-	end chan<- common.SignatureData) tss.Party {
+	end chan<- *common.SignatureData) tss.Party {
 	return NewLocalPartyWithKDD(msg, params, key, nil, out, end)
 }
 
@@ -108,7 +129,7 @@ func NewLocalPartyWithKDD(
 	key keygen.LocalPartySaveData,
 	keyDerivationDelta *big.Int,
 	out chan<- tss.Message,
-	end chan<- common.SignatureData,
+	end chan<- *common.SignatureData,
 ) tss.Party {
 	partyCount := len(params.Parties().IDs())
 	p := &LocalParty{
@@ -151,10 +172,35 @@ func NewLocalPartyWithKDD(
 	p.temp.alphaHat = make([]*big.Int, partyCount)
 	p.temp.delta = make([]*big.Int, partyCount)
 	p.temp.bigDelta = make([]*crypto.ECPoint, partyCount)
+	p.temp.adaptorGamma = make([]*crypto.ECPoint, partyCount)
 
 	return p
 }
 
+// NewLocalPartyWithAdaptor returns a party which produces an adaptor pre-signature of `msg` against the
+// adaptor point `T` and sends it to `adaptorEnd` instead of a signature. Presigning is not supported in this mode,
+// as the nonce point of the final signature depends on T.
+func NewLocalPartyWithAdaptor(
+	msg *big.Int,
+	params *tss.Parameters,
+	key keygen.LocalPartySaveData,
+	T *crypto.ECPoint,
+	out chan<- tss.Message,
+	adaptorEnd chan<- *adaptor.PreSignature,
+) tss.Party {
+	p := NewLocalParty(msg, params, key, out, nil).(*LocalParty)
+	p.temp.adaptorPoint = T
+	p.adaptorEnd = adaptorEnd
+	return p
+}
+
+// SetPaillierDecrypter makes the party delegate the operations of its Paillier private key to `sk`, e.g. a key kept
+// in an HSM, instead of using the key of its save data, which then only needs the public key. It must be called before
+// the party is started.
+func (p *LocalParty) SetPaillierDecrypter(sk paillier.Decrypter) {
+	p.temp.paillierSK = sk
+}
+
 func Make2DParsedMessage(dim int) [][]tss.ParsedMessage {
 	out := make([][]tss.ParsedMessage, dim)
 	for i, _ := range out {
@@ -178,11 +224,20 @@ func Make2DSlice[K *big.Int |
 }
 
 func (p *LocalParty) FirstRound() tss.Round {
-	return newRound1(p.params, &p.keys, &p.data, &p.temp, p.out, p.end)
+	if p.presig != nil {
+		return newRound5(p.params, &p.keys, &p.data, &p.temp, p.out, p.end)
+	}
+	return newRound1(p.params, &p.keys, &p.data, &p.temp, p.out, p.end, p.presigEnd, p.adaptorEnd)
 }
 
 func (p *LocalParty) Start() *tss.Error {
 	return tss.BaseStart(p, TaskName, func(round tss.Round) *tss.Error {
+		if round5, ok := round.(*round5); ok && p.presig != nil {
+			if err := round5.prepare(p.presig, p.markUsed); err != nil {
+				return round.WrapError(err)
+			}
+			return nil
+		}
 		round1, ok := round.(*round1)
 		if !ok {
 			return round.WrapError(errors.New("unable to Start(). party is in an unexpected round"))
@@ -199,7 +254,7 @@ func (p *LocalParty) Update(msg tss.ParsedMessage) (ok bool, err *tss.Error) {
 }
 
 func (p *LocalParty) UpdateFromBytes(wireBytes []byte, from *tss.PartyID, isBroadcast bool) (bool, *tss.Error) {
-	msg, err := tss.ParseWireMessage(wireBytes, from, isBroadcast)
+	msg, err := messageTypes.ParseWireMessage(wireBytes, from, isBroadcast)
 	if err != nil {
 		return false, p.WrapError(err)
 	}
@@ -243,12 +298,26 @@ func (p *LocalParty) StoreMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
 	case *SignRound5Message:
 		p.temp.signRound5Messages[fromPIdx] = msg
 	default: // unrecognised message, just ignore!
-		common.Logger.Warningf("unrecognised message ignored: %v", msg)
+		p.params.Logger().Warnf("unrecognised message ignored: %v", msg)
 		return false, nil
 	}
 	return true, nil
 }
 
+// Zeroize wipes the nonces and the other secrets of the signing, see tss.Zeroizer
+func (p *LocalParty) Zeroize() { p.temp.Zeroize() }
+
+// Zeroize wipes the nonces k and gamma, the additive share w of the key, the product chi = k * w and the shares of the
+// MtA exchanges that are still held; those of a presignature that was output are owned by the PreSignatureData
+func (temp *localTempData) Zeroize() {
+	common.Zeroize(temp.w, temp.k, temp.gamma, temp.chi)
+	common.Zeroize(temp.beta...)
+	common.Zeroize(temp.betaHat...)
+	common.Zeroize(temp.delta...)
+	common.Zeroize(temp.alpha...)
+	common.Zeroize(temp.alphaHat...)
+}
+
 func (p *LocalParty) PartyID() *tss.PartyID {
 	return p.params.PartyID()
 }
@@ -18,25 +18,27 @@ package cggplus
 
 import (
 	"crypto/elliptic"
+	"errors"
 	"math/big"
 	"strconv"
 
 	"github.com/kisdex/mpc-lib/common"
 	"github.com/kisdex/mpc-lib/crypto"
+	"github.com/kisdex/mpc-lib/crypto/schnorr"
 	"github.com/kisdex/mpc-lib/crypto/zkproofs"
 	"github.com/kisdex/mpc-lib/tss"
 )
 
 var (
-	// Ensure that signing messages implement ValidateBasic
-	_ = []tss.MessageContent{
-		(*SignRound1Message)(nil),
-		(*SignRound2Message1)(nil),
-		(*SignRound2Message2)(nil),
-		(*SignRound3Message)(nil),
-		(*SignRound4Message)(nil),
-		(*SignRound5Message)(nil),
-	}
+	// Ensure that signing messages implement ValidateBasic, and accept only them from the wire
+	messageTypes = tss.NewMessageTypes(
+		&SignRound1Message{},
+		&SignRound2Message1{},
+		&SignRound2Message2{},
+		&SignRound3Message{},
+		&SignRound4Message{},
+		&SignRound5Message{},
+	)
 )
 
 func NewSignRound1Message(
@@ -145,6 +147,7 @@ func NewSignRound2Message2(
 	from *tss.PartyID,
 	pointGamma *crypto.ECPoint,
 	psiPrimeArray []*zkproofs.LogStarProof,
+	echo [][]byte,
 ) tss.ParsedMessage {
 	meta := tss.MessageRouting{
 		From:        from,
@@ -155,6 +158,7 @@ func NewSignRound2Message2(
 	content := &SignRound2Message2{
 		PointGamma: pGamma[:],
 		PsiPrime:   pPsiPrime[:],
+		Echo:       echo,
 	}
 	msg := tss.NewMessageWrapper(meta, content)
 	return tss.NewMessage(meta, content, msg)
@@ -162,9 +166,7 @@ func NewSignRound2Message2(
 
 func (m *SignRound2Message2) ValidateBasic() bool {
 	return m != nil &&
-		m.GetPointGamma() != nil &&
-		common.NonEmptyBytes(m.GetPointGamma()[0]) &&
-		common.NonEmptyBytes(m.GetPointGamma()[1])
+		common.NonEmptyMultiBytes(m.GetPointGamma(), 1)
 }
 
 func (m *SignRound2Message2) UnmarshalGamma(ec elliptic.Curve) (*crypto.ECPoint, error) {
@@ -185,6 +187,9 @@ func NewSignRound3Message(
 	psiPrimePrimeArray []*zkproofs.LogStarProof,
 	hProof *zkproofs.MulProof,
 	deltaProofArray []*zkproofs.DecProof,
+	adaptorGamma *crypto.ECPoint,
+	adaptorProof *schnorr.DLEQProof,
+	echo [][]byte,
 ) tss.ParsedMessage {
 	meta := tss.MessageRouting{
 		From:        from,
@@ -201,6 +206,11 @@ func NewSignRound3Message(
 		PsiPrimePrime: pPsiPrimePrime[:],
 		DeltaProof:    pDeltaProof[:],
 		HProof:        phProof[:],
+		Echo:          echo,
+	}
+	if adaptorGamma != nil && adaptorProof != nil {
+		content.AdaptorGamma = PointToBytes(adaptorGamma)
+		content.AdaptorProof = adaptorProof.Bytes()
 	}
 	msg := tss.NewMessageWrapper(meta, content)
 	return tss.NewMessage(meta, content, msg)
@@ -237,14 +247,31 @@ func (m *SignRound3Message) UnmarshalHProof() (*zkproofs.MulProof, error) {
 	return zkproofs.MulProofFromBytes(m.GetHProof())
 }
 
+// HasAdaptor reports whether the sender attached its share of the adaptor nonce point
+func (m *SignRound3Message) HasAdaptor() bool {
+	return common.NonEmptyMultiBytes(m.GetAdaptorGamma(), 1) &&
+		common.NonEmptyMultiBytes(m.GetAdaptorProof(), schnorr.DLEQProofBytesParts)
+}
+
+func (m *SignRound3Message) UnmarshalAdaptorGamma(ec elliptic.Curve) (*crypto.ECPoint, error) {
+	return BytesToPoint(ec, m.GetAdaptorGamma())
+}
+
+func (m *SignRound3Message) UnmarshalAdaptorProof(ec elliptic.Curve) (*schnorr.DLEQProof, error) {
+	return schnorr.NewDLEQProofFromBytes(ec, m.GetAdaptorProof())
+}
+
 func NewSignRound4Message(
 	from *tss.PartyID,
+	echo [][]byte,
 ) tss.ParsedMessage {
 	meta := tss.MessageRouting{
 		From:        from,
 		IsBroadcast: true,
 	}
-	content := &SignRound4Message{}
+	content := &SignRound4Message{
+		Echo: echo,
+	}
 	msg := tss.NewMessageWrapper(meta, content)
 	return tss.NewMessage(meta, content, msg)
 }
@@ -297,18 +324,14 @@ func (m *SignRound5Message) UnmarshalSigmaProof(ec elliptic.Curve) ([]*zkproofs.
 	return zkproofs.ProofArrayFromBytes[*zkproofs.DecProof](ec, m.GetBigSigmaProof())
 }
 
+// PointToBytes serializes a point as its compressed encoding, in a single byte part
 func PointToBytes(point *crypto.ECPoint) [][]byte {
-	return [][]byte{point.X().Bytes(), point.Y().Bytes()}
+	return [][]byte{point.Bytes()}
 }
 
 func BytesToPoint(ec elliptic.Curve, bzs [][]byte) (*crypto.ECPoint, error) {
-	point, err := crypto.NewECPoint(
-		ec,
-		new(big.Int).SetBytes(bzs[0]),
-		new(big.Int).SetBytes(bzs[1]),
-	)
-	if err != nil {
-		return point, err
+	if !common.NonEmptyMultiBytes(bzs, 1) {
+		return nil, errors.New("expected 1 byte part to construct a point")
 	}
-	return point, nil
+	return crypto.NewECPointFromBytes(ec, bzs[0])
 }
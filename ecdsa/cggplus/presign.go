@@ -0,0 +1,246 @@
+// Copyright (c) 2023, Circle Internet Financial, LTD. All rights reserved.
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cggplus
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto"
+	"github.com/kisdex/mpc-lib/ecdsa/keygen"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+// PreSignatureData is the output of the message-independent rounds 1-4 of the protocol.
+// It can be saved to disk and later turned into a signature with a single round of messages.
+//
+// A presignature MUST NOT be used to sign more than one message: doing so leaks the signing key.
+// The online party clears the secret fields of the PreSignatureData that it is given as soon as it is loaded, but
+// that does not protect any other copy of it: once a presignature is persisted, serialized or copied, preventing its
+// reuse is the caller's responsibility. Pass a MarkUsedFunc backed by durable storage to
+// NewLocalPartyWithPreSignature, which requires one, and delete the stored presignature once it is loaded.
+type PreSignatureData struct {
+	// original indexes (ki) of the signing committee, in party order
+	Ks []*big.Int
+
+	// secret fields (not shared, but stored locally)
+	K, Chi, W *big.Int
+
+	// R = (Rx, Ry)
+	Rx, Ry *big.Int
+
+	BigWs []*crypto.ECPoint
+	BigK  []*big.Int // [sender]
+	BigFHat,
+	BigDHat [][]*big.Int // [sender][receiver]
+}
+
+// NewPresignParty returns a party which runs the message-independent rounds of signing
+// and sends a PreSignatureData to `presigEnd` instead of a signature.
+func NewPresignParty(
+	params *tss.Parameters,
+	key keygen.LocalPartySaveData,
+	out chan<- tss.Message,
+	presigEnd chan<- *PreSignatureData) tss.Party {
+	return NewPresignPartyWithKDD(params, key, nil, out, presigEnd)
+}
+
+// NewPresignPartyWithKDD returns a presigning party with key derivation delta for HD support
+func NewPresignPartyWithKDD(
+	params *tss.Parameters,
+	key keygen.LocalPartySaveData,
+	keyDerivationDelta *big.Int,
+	out chan<- tss.Message,
+	presigEnd chan<- *PreSignatureData,
+) tss.Party {
+	p := NewLocalPartyWithKDD(nil, params, key, keyDerivationDelta, out, nil).(*LocalParty)
+	p.presigEnd = presigEnd
+	return p
+}
+
+// MarkUsedFunc records that the presignature with the given ID is used, and returns an error if it was used already.
+// It must check and record the ID atomically, e.g. with a conditional write to the storage of the presignatures.
+type MarkUsedFunc func(id []byte) error
+
+// UsedPreSignatures is an in-memory set of the IDs of the presignatures that were used; its MarkUsed is a
+// MarkUsedFunc. It only protects against reuse within the process, so presignatures that are persisted need a
+// MarkUsedFunc backed by the same storage.
+type UsedPreSignatures struct {
+	mtx sync.Mutex
+	ids map[string]struct{}
+}
+
+// MarkUsed records `id` and returns an error if it was recorded already, see MarkUsedFunc
+func (used *UsedPreSignatures) MarkUsed(id []byte) error {
+	used.mtx.Lock()
+	defer used.mtx.Unlock()
+	if _, ok := used.ids[string(id)]; ok {
+		return fmt.Errorf("presignature %x was used already", id)
+	}
+	if used.ids == nil {
+		used.ids = make(map[string]struct{})
+	}
+	used.ids[string(id)] = struct{}{}
+	return nil
+}
+
+// NewLocalPartyWithPreSignature returns a party which signs `msg` in a single round using
+// a presignature produced by a party from NewPresignParty with the same committee.
+// `markUsed` is called with the ID of the presignature before it is loaded, and the party does not start if it returns
+// an error or is nil; see PreSignatureData for why it is needed. A caller that stores its presignatures nowhere but in
+// memory may pass the MarkUsed of a UsedPreSignatures shared by its parties.
+func NewLocalPartyWithPreSignature(
+	msg *big.Int,
+	params *tss.Parameters,
+	key keygen.LocalPartySaveData,
+	presig *PreSignatureData,
+	markUsed MarkUsedFunc,
+	out chan<- tss.Message,
+	end chan<- *common.SignatureData,
+) tss.Party {
+	p := NewLocalParty(msg, params, key, out, end).(*LocalParty)
+	p.presig = presig
+	p.markUsed = markUsed
+	return p
+}
+
+// ID identifies the presignature by its nonce point R and its committee. It is the same at every party of the
+// committee and for every copy of the presignature, so a storage of used IDs that is shared by the parties must also
+// key them by the party.
+func (presig *PreSignatureData) ID() []byte {
+	return common.SHA512_256i(append([]*big.Int{presig.Rx, presig.Ry}, presig.Ks...)...).Bytes()
+}
+
+// ValidateWithCommittee checks that the presignature is complete and was produced by the committee with the given `ks`
+func (presig *PreSignatureData) ValidateWithCommittee(ec elliptic.Curve, ks []*big.Int) error {
+	if presig == nil {
+		return errors.New("presignature is nil")
+	}
+	if presig.K == nil || presig.Chi == nil || presig.W == nil {
+		return errors.New("presignature is missing secret values; it may have been used already")
+	}
+	if presig.Rx == nil || presig.Ry == nil {
+		return errors.New("presignature is missing R")
+	}
+	if _, err := crypto.NewECPoint(ec, presig.Rx, presig.Ry); err != nil {
+		return fmt.Errorf("presignature R is invalid: %w", err)
+	}
+	partyCount := len(ks)
+	if len(presig.Ks) != partyCount || len(presig.BigWs) != partyCount || len(presig.BigK) != partyCount ||
+		len(presig.BigFHat) != partyCount || len(presig.BigDHat) != partyCount {
+		return fmt.Errorf("presignature was not made for a committee of %d parties", partyCount)
+	}
+	for j, kj := range ks {
+		if presig.Ks[j] == nil || presig.Ks[j].Cmp(kj) != 0 {
+			return errors.New("presignature was made by a different committee")
+		}
+		if presig.BigWs[j] == nil || !presig.BigWs[j].ValidateBasic() || presig.BigK[j] == nil ||
+			len(presig.BigFHat[j]) != partyCount || len(presig.BigDHat[j]) != partyCount {
+			return fmt.Errorf("presignature is malformed at index %d", j)
+		}
+	}
+	return nil
+}
+
+// ----- //
+
+func newRound5(params *tss.Parameters, key *keygen.LocalPartySaveData, data *common.SignatureData, temp *localTempData, out chan<- tss.Message, end chan<- *common.SignatureData) tss.Round {
+	return &round5{&round4{&round3{&round2{&round1{
+		&base{params, key, data, temp, out, end, nil, nil, make([]bool, len(params.Parties().IDs())), false, 5}}}}}}
+}
+
+// helper to load a presignature into the temp data of an online signing party
+func (round *round5) prepare(presig *PreSignatureData, markUsed MarkUsedFunc) error {
+	if err := presig.ValidateWithCommittee(round.Params().EC(), round.key.Ks); err != nil {
+		return err
+	}
+	if markUsed == nil {
+		return errors.New("a presignature cannot be used without a MarkUsedFunc to prevent its reuse")
+	}
+	if err := markUsed(presig.ID()); err != nil {
+		return fmt.Errorf("presignature cannot be used: %w", err)
+	}
+	round.temp.k = presig.K
+	round.temp.chi = presig.Chi
+	round.temp.w = presig.W
+	round.temp.rx = presig.Rx
+	round.temp.ry = presig.Ry
+	round.temp.bigWs = presig.BigWs
+	round.temp.bigK = presig.BigK
+	round.temp.bigFHat = presig.BigFHat
+	round.temp.bigDHat = presig.BigDHat
+
+	// make sure this copy of the presignature cannot be used twice
+	presig.K, presig.Chi, presig.W = nil, nil, nil
+	return nil
+}
+
+func (round *presignFinalization) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started"))
+	}
+	round.number = 5
+	round.started = true
+	round.resetOK()
+
+	// the presignature cannot be checked before it is used, so the deferred proofs are verified now
+	if err := round.verifyDeferredProofs(); err != nil {
+		return err
+	}
+
+	presig := &PreSignatureData{
+		Ks:      round.key.Ks,
+		K:       round.temp.k,
+		Chi:     round.temp.chi,
+		W:       round.temp.w,
+		Rx:      round.temp.rx,
+		Ry:      round.temp.ry,
+		BigWs:   round.temp.bigWs,
+		BigK:    round.temp.bigK,
+		BigFHat: round.temp.bigFHat,
+		BigDHat: round.temp.bigDHat,
+	}
+	round.presigEnd <- presig
+
+	round.temp.k = nil
+	round.temp.chi = nil
+	round.temp.w = nil
+	round.temp.bigK = nil
+	round.temp.bigFHat = nil
+	round.temp.bigDHat = nil
+	round.temp.r3Digests = nil
+	clear(round.temp.signRound4Messages)
+	return nil
+}
+
+func (round *presignFinalization) CanAccept(msg tss.ParsedMessage) bool {
+	// not expecting any incoming messages in this round
+	return false
+}
+
+func (round *presignFinalization) Update() (bool, *tss.Error) {
+	// not expecting any incoming messages in this round
+	return false, nil
+}
+
+func (round *presignFinalization) NextRound() tss.Round {
+	return nil // finished!
+}
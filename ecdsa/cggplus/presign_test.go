@@ -0,0 +1,326 @@
+// Copyright (c) 2023, Circle Internet Financial, LTD. All rights reserved.
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cggplus
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"math/big"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	btcecdsa "github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/ecdsa/keygen"
+	"github.com/kisdex/mpc-lib/test"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+func TestE2EPresignAndSign(t *testing.T) {
+	SetUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	params := make([]*tss.Parameters, len(signPIDs))
+	for i := range signPIDs {
+		params[i] = tss.NewParameters(tss.S256(), p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+	}
+
+	// PHASE: presign
+	outCh := make(chan tss.Message, len(signPIDs)*len(signPIDs)*3)
+	presigChs := make([]chan *PreSignatureData, len(signPIDs))
+	parties := make([]*LocalParty, 0, len(signPIDs))
+	for i := range signPIDs {
+		presigChs[i] = make(chan *PreSignatureData, 1)
+		parties = append(parties, NewPresignParty(params[i], keys[i], outCh, presigChs[i]).(*LocalParty))
+	}
+	runParties(t, parties, outCh, func() bool {
+		for _, ch := range presigChs {
+			if len(ch) == 0 {
+				return false
+			}
+		}
+		return true
+	})
+
+	// the presignatures must survive a round trip to disk
+	presigs := make([]*PreSignatureData, len(parties))
+	stored := make([][]byte, len(parties))
+	for i, ch := range presigChs {
+		stored[i], err = json.Marshal(<-ch)
+		assert.NoError(t, err)
+		presigs[i] = new(PreSignatureData)
+		assert.NoError(t, json.Unmarshal(stored[i], presigs[i]))
+	}
+	for i, presig := range presigs {
+		assert.NotNil(t, presig, "missing presignature of party %d", i)
+		assert.Equal(t, presigs[0].Rx, presig.Rx, "all parties must agree on R")
+	}
+
+	// PHASE: online signing
+	msg := big.NewInt(42)
+	endCh := make(chan *common.SignatureData, len(signPIDs))
+	used := make([]*UsedPreSignatures, len(signPIDs))
+	parties = parties[:0]
+	for i := range signPIDs {
+		used[i] = new(UsedPreSignatures)
+		parties = append(parties, NewLocalPartyWithPreSignature(msg, params[i], keys[i], presigs[i], used[i].MarkUsed, outCh, endCh).(*LocalParty))
+	}
+	runParties(t, parties, outCh, func() bool { return len(endCh) == len(parties) })
+
+	pk := ecdsa.PublicKey{
+		Curve: tss.S256(),
+		X:     keys[0].ECDSAPub.X(),
+		Y:     keys[0].ECDSAPub.Y(),
+	}
+	var first []byte
+	halfN := new(big.Int).Rsh(tss.S256().Params().N, 1)
+	for i := range parties {
+		data := <-endCh
+		sig := data.Signature
+		if i == 0 {
+			first = sig
+		}
+		assert.Equal(t, first, sig, "all parties must output the same signature")
+		r, s := new(big.Int).SetBytes(sig[:len(sig)/2]), new(big.Int).SetBytes(sig[len(sig)/2:])
+		assert.True(t, ecdsa.Verify(&pk, msg.Bytes(), r, s), "ecdsa verify must pass")
+		assert.True(t, s.Cmp(halfN) <= 0, "signature must be low-S")
+
+		eth, err := data.EthereumSignature()
+		assert.NoError(t, err)
+		recovered, _, err := btcecdsa.RecoverCompact(append([]byte{eth[64] + 27}, eth[:64]...), msg.Bytes())
+		if assert.NoError(t, err) {
+			assert.True(t, recovered.ToECDSA().Equal(&pk), "recovery id must select the public key")
+		}
+	}
+
+	// PHASE: a presignature cannot be used twice, neither from the loaded copy nor from the stored one
+	for i, presig := range presigs {
+		assert.Nil(t, presig.K)
+		P := NewLocalPartyWithPreSignature(msg, params[i], keys[i], presig, used[i].MarkUsed, outCh, endCh)
+		assert.NotNil(t, P.Start(), "reusing a presignature must fail")
+
+		copied := new(PreSignatureData)
+		assert.NoError(t, json.Unmarshal(stored[i], copied))
+		P = NewLocalPartyWithPreSignature(msg, params[i], keys[i], copied, used[i].MarkUsed, outCh, endCh)
+		assert.NotNil(t, P.Start(), "reusing a stored presignature must fail")
+		assert.NotNil(t, copied.K, "a refused presignature is not loaded")
+
+		P = NewLocalPartyWithPreSignature(msg, params[i], keys[i], copied, nil, outCh, endCh)
+		assert.NotNil(t, P.Start(), "a presignature must not be used without a MarkUsedFunc")
+		assert.NotNil(t, copied.K, "a refused presignature is not loaded")
+	}
+}
+
+func runParties(t *testing.T, parties []*LocalParty, outCh chan tss.Message, done func() bool) {
+	errCh := make(chan *tss.Error, len(parties)*len(parties))
+
+	// start every party before delivering messages so that none are missed
+	wg := sync.WaitGroup{}
+	for _, P := range parties {
+		wg.Add(1)
+		go func(P *LocalParty) {
+			defer wg.Done()
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+	wg.Wait()
+
+	for !done() {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+		case msg := <-outCh:
+			dest := msg.GetTo()
+			if dest == nil {
+				for _, P := range parties {
+					if P.PartyID().Index == msg.GetFrom().Index {
+						continue
+					}
+					test.SharedPartyUpdater(P, msg, errCh)
+				}
+			} else {
+				test.SharedPartyUpdater(parties[dest[0].Index], msg, errCh)
+			}
+		}
+	}
+}
+
+type benchmarkCommittee struct {
+	size test.BenchmarkSize
+	keys []keygen.LocalPartySaveData
+	pIDs tss.SortedPartyIDs
+}
+
+// benchmarkCommittees runs a keygen of each benchmark size with the pre-params of the fixtures and returns the t+1
+// first parties of each as the signers
+func benchmarkCommittees(b *testing.B) []benchmarkCommittee {
+	fixtures, _, err := keygen.LoadKeygenBenchmarkFixtures()
+	if err != nil {
+		b.Fatal(err)
+	}
+	preParams := make([]keygen.LocalPreParams, len(fixtures))
+	for i, fixture := range fixtures {
+		preParams[i] = fixture.LocalPreParams
+	}
+	committees := make([]benchmarkCommittee, 0)
+	for _, size := range test.BenchmarkSizes(len(fixtures)) {
+		keys, pIDs, err := keygen.GenerateKeygenTestFixturesWithPreParams(tss.S256(), size.Threshold, tss.SecurityLevel112, 1, preParams[:size.Parties])
+		if err != nil {
+			b.Fatal(err)
+		}
+		committees = append(committees, benchmarkCommittee{size, keys[:size.Threshold+1], pIDs[:size.Threshold+1]})
+	}
+	return committees
+}
+
+func benchmarkPresign(b *testing.B, committee benchmarkCommittee, metrics tss.Metrics) []*PreSignatureData {
+	p2pCtx := tss.NewPeerContext(committee.pIDs)
+	outCh := make(chan tss.Message, len(committee.pIDs)*len(committee.pIDs))
+	presigChs := make([]chan *PreSignatureData, len(committee.pIDs))
+	parties := make([]tss.Party, 0, len(committee.pIDs))
+	for i, pID := range committee.pIDs {
+		params := tss.NewParameters(tss.S256(), p2pCtx, pID, len(committee.pIDs), committee.size.Threshold)
+		params.SetMetrics(metrics)
+		presigChs[i] = make(chan *PreSignatureData, 1)
+		parties = append(parties, NewPresignParty(params, committee.keys[i], outCh, presigChs[i]))
+	}
+	if _, err := test.RunSequentially(parties, outCh); err != nil {
+		b.Fatal(err)
+	}
+	presigs := make([]*PreSignatureData, len(parties))
+	for i, ch := range presigChs {
+		presigs[i] = <-ch
+	}
+	return presigs
+}
+
+func BenchmarkPresign(b *testing.B) {
+	SetUp("error")
+	for _, committee := range benchmarkCommittees(b) {
+		b.Run(committee.size.String(), func(b *testing.B) {
+			metrics := test.NewBenchmarkMetrics(len(committee.pIDs))
+			for i := 0; i < b.N; i++ {
+				benchmarkPresign(b, committee, metrics)
+			}
+			metrics.Report(b)
+		})
+	}
+}
+
+func BenchmarkSign(b *testing.B) {
+	SetUp("error")
+	msg := big.NewInt(42)
+	for _, committee := range benchmarkCommittees(b) {
+		b.Run(committee.size.String(), func(b *testing.B) {
+			metrics := test.NewBenchmarkMetrics(len(committee.pIDs))
+			p2pCtx := tss.NewPeerContext(committee.pIDs)
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				presigs := benchmarkPresign(b, committee, nil)
+				b.StartTimer()
+				outCh := make(chan tss.Message, len(committee.pIDs)*len(committee.pIDs))
+				endCh := make(chan *common.SignatureData, len(committee.pIDs))
+				parties := make([]tss.Party, 0, len(committee.pIDs))
+				for j, pID := range committee.pIDs {
+					params := tss.NewParameters(tss.S256(), p2pCtx, pID, len(committee.pIDs), committee.size.Threshold)
+					params.SetMetrics(metrics)
+					used := new(UsedPreSignatures)
+					parties = append(parties, NewLocalPartyWithPreSignature(msg, params, committee.keys[j], presigs[j], used.MarkUsed, outCh, endCh))
+				}
+				if _, err := test.RunSequentially(parties, outCh); err != nil {
+					b.Fatal(err)
+				}
+			}
+			metrics.Report(b)
+		})
+	}
+}
+
+// heapListener samples the live heap when its party starts a round, after the other parties have started it when the
+// parties are run sequentially
+type heapListener struct {
+	peak uint64
+}
+
+func (l *heapListener) OnRoundStart(*tss.PartyID, string, int) {
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	l.peak = max(l.peak, stats.HeapAlloc)
+}
+
+func (l *heapListener) OnRoundComplete(*tss.PartyID, string, int, time.Duration) {}
+func (l *heapListener) OnMessageSent(*tss.PartyID, tss.Message)                  {}
+func (l *heapListener) OnMessageReceived(*tss.PartyID, tss.ParsedMessage)        {}
+
+// BenchmarkSignMemory reports the peak of the live heap of a committee that signs, in which the messages and the
+// ciphertexts of the MtA of every pair of signers dominate
+func BenchmarkSignMemory(b *testing.B) {
+	SetUp("error")
+	msg := big.NewInt(42)
+	for _, committee := range benchmarkCommittees(b) {
+		b.Run(committee.size.String(), func(b *testing.B) {
+			p2pCtx := tss.NewPeerContext(committee.pIDs)
+			listener := new(heapListener)
+			for i := 0; i < b.N; i++ {
+				outCh := make(chan tss.Message, len(committee.pIDs)*len(committee.pIDs))
+				endCh := make(chan *common.SignatureData, len(committee.pIDs))
+				parties := make([]tss.Party, 0, len(committee.pIDs))
+				for j, pID := range committee.pIDs {
+					params := tss.NewParameters(tss.S256(), p2pCtx, pID, len(committee.pIDs), committee.size.Threshold)
+					if j == len(committee.pIDs)-1 {
+						params.SetRoundListener(listener)
+					}
+					parties = append(parties, NewLocalParty(msg, params, committee.keys[j], outCh, endCh))
+				}
+				if _, err := test.RunSequentially(parties, outCh); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(listener.peak), "peak-heap-B")
+		})
+	}
+}
+
+func FuzzMessages(f *testing.F) {
+	SetUp("error")
+	keys, signPIDs, err := keygen.LoadKeygenTestFixtures(testThreshold + 1)
+	if err != nil {
+		f.Fatal(err)
+	}
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	outCh := make(chan tss.Message, len(signPIDs)*len(signPIDs)*3)
+	endCh := make(chan *common.SignatureData, len(signPIDs))
+	parties := make([]tss.Party, 0, len(signPIDs))
+	for i := range signPIDs {
+		params := tss.NewParameters(tss.S256(), p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+		parties = append(parties, NewLocalParty(big.NewInt(42), params, keys[i], outCh, endCh))
+	}
+	transcript, tssErr := test.RunSequentially(parties, outCh)
+	if tssErr != nil {
+		f.Fatal(tssErr)
+	}
+	test.FuzzWireMessages(f, tss.S256(), transcript)
+}
@@ -17,14 +17,15 @@ import (
 
 	"github.com/kisdex/mpc-lib/common"
 	"github.com/kisdex/mpc-lib/crypto/accmta"
+	"github.com/kisdex/mpc-lib/crypto/adaptor"
 	"github.com/kisdex/mpc-lib/ecdsa/keygen"
 	"github.com/kisdex/mpc-lib/ecdsa/signing"
 	"github.com/kisdex/mpc-lib/tss"
 )
 
-func newRound1(params *tss.Parameters, key *keygen.LocalPartySaveData, data *common.SignatureData, temp *localTempData, out chan<- tss.Message, end chan<- common.SignatureData) tss.Round {
+func newRound1(params *tss.Parameters, key *keygen.LocalPartySaveData, data *common.SignatureData, temp *localTempData, out chan<- tss.Message, end chan<- *common.SignatureData, presigEnd chan<- *PreSignatureData, adaptorEnd chan<- *adaptor.PreSignature) tss.Round {
 	return &round1{
-		&base{params, key, data, temp, out, end, make([]bool, len(params.Parties().IDs())), false, 1}}
+		&base{params, key, data, temp, out, end, presigEnd, adaptorEnd, make([]bool, len(params.Parties().IDs())), false, 1}}
 }
 
 func (round *round1) Start() *tss.Error {
@@ -38,7 +39,13 @@ func (round *round1) Start() *tss.Error {
 	i := round.PartyID().Index
 	round.ok[i] = true
 
-	paillierPK := round.key.PaillierSK.PublicKey
+	ssid, err := round.getSSID()
+	if err != nil {
+		return round.WrapError(err)
+	}
+	round.temp.ssid = ssid
+
+	paillierPK := *round.paillierSK().Public()
 	q := round.Params().EC().Params().N
 
 	gamma := common.GetRandomPositiveInt(q)
@@ -53,9 +60,9 @@ func (round *round1) Start() *tss.Error {
 		return round.WrapError(errors.New("failed to init round1."))
 	}
 
-	rpVs := round.key.GetAllRingPedersen()
-	rpVs[i] = nil
+	rpVs := round.proofVerifiers(i)
 	_, psiArray, err := accmta.AliceInit(
+		round.temp.ssid,
 		round.Params().EC(),
 		round.key.PaillierPKs[i],
 		k, nu,
@@ -74,7 +81,7 @@ func (round *round1) Start() *tss.Error {
 	// broadcast
 	r1msg := NewSignRound1Message(round.PartyID(), bigK, bigG, psiArray)
 	round.temp.signRound1Messages[i] = r1msg
-	round.out <- r1msg
+	round.SendMessage(round.out, r1msg)
 
 	return nil
 }
@@ -126,6 +133,11 @@ func (round *round1) prepare() error {
 	if round.Threshold()+1 > len(ks) {
 		return fmt.Errorf("t+1=%d is not satisfied by the key count of %d", round.Threshold()+1, len(ks))
 	}
+	if T := round.temp.adaptorPoint; T != nil {
+		if !T.ValidateBasic() || !tss.SameCurve(T.Curve(), round.Params().EC()) {
+			return errors.New("the adaptor point is not a valid point on the signing curve")
+		}
+	}
 	wi, bigWs := signing.PrepareForSigning(round.Params().EC(), i, len(ks), xi, ks, bigXs)
 
 	round.temp.w = wi
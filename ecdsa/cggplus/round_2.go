@@ -18,7 +18,7 @@ package cggplus
 
 import (
 	"errors"
-	"sync"
+	"math/big"
 
 	"github.com/kisdex/mpc-lib/crypto"
 	"github.com/kisdex/mpc-lib/crypto/accmta"
@@ -44,21 +44,21 @@ func (round *round2) Start() *tss.Error {
 	ec := round.Params().EC()
 	round.temp.pointGamma[i] = crypto.ScalarBaseMult(ec, round.temp.gamma)
 
-	errChs := make(chan *tss.Error, (len(round.Parties().IDs())-1)*3)
+	errChs := make(chan *tss.Error, (len(round.Parties().IDs())-1)*2)
 	round.VerifyRound1Messages(errChs)
 
-	wg := sync.WaitGroup{}
+	pool := round.NewWorkerPool()
 	for j, Pj := range round.Parties().IDs() {
 		if j == i {
 			continue
 		}
 
-		wg.Add(3)
-		go round.BobRespondsGamma(j, Pj, psi, &wg, errChs)
-		go round.BobRespondsW(j, Pj, psiHat, &wg, errChs)
-		go round.ComputeProofPsiPrime(j, Pj, psiPrime, &wg, errChs)
+		pool.GoFor(Pj, func() { round.BobRespondsGammaW(j, Pj, psi, psiHat, errChs) })
+		if round.proofVerifiers(i)[j] != nil {
+			pool.GoFor(Pj, func() { round.ComputeProofPsiPrime(j, Pj, psiPrime, errChs) })
+		}
 	}
-	wg.Wait()
+	pool.Wait()
 	close(errChs)
 	err := round.WrapErrorChs(round.PartyID(), errChs, "Failed to process round 1 messages.")
 	if err != nil {
@@ -80,14 +80,15 @@ func (round *round2) Start() *tss.Error {
 			psiHat[j],
 		)
 		round.temp.signRound2Message1s[i][j] = r2msg1
-		round.out <- r2msg1
+		round.SendMessage(round.out, r2msg1)
 	}
 	r2msg2 := NewSignRound2Message2(
 		round.PartyID(),
 		round.temp.pointGamma[i],
-		psiPrime)
+		psiPrime,
+		tss.EchoDigests(round.temp.signRound1Messages))
 	round.temp.signRound2Message2s[i] = r2msg2
-	round.out <- r2msg2
+	round.SendMessage(round.out, r2msg2)
 
 	return nil
 }
@@ -108,8 +109,8 @@ func (round *round2) VerifyRound1Messages(errChs chan *tss.Error) {
 	}
 }
 
-func (round *round2) BobRespondsW(j int, Pj *tss.PartyID, proofs [][]*zkproofs.AffGInvProof, wg *sync.WaitGroup, errChs chan *tss.Error) {
-	defer wg.Done()
+// BobRespondsGammaW runs the MtA of gamma and of w against the K of Pj as one batch
+func (round *round2) BobRespondsGammaW(j int, Pj *tss.PartyID, proofs, proofsHat [][]*zkproofs.AffGInvProof, errChs chan *tss.Error) {
 	i := round.PartyID().Index
 
 	r1msg := round.temp.signRound1Messages[j].Content().(*SignRound1Message)
@@ -119,71 +120,41 @@ func (round *round2) BobRespondsW(j int, Pj *tss.PartyID, proofs [][]*zkproofs.A
 		return
 	}
 
-	ringPedersenBobI := round.key.GetRingPedersen(i)
-	rpVs := round.key.GetAllRingPedersen()
-	rpVs[i] = nil
-	betaHat, bigDHat, bigFHat, pf, err := accmta.BobRespondsG(
-		round.Params().EC(),
-		round.key.PaillierPKs[j],
-		round.key.PaillierSK,
-		psiAlice[i],
-		round.temp.w,
-		round.temp.bigK[j],
-		rpVs,
-		ringPedersenBobI,
-	)
-	if err != nil {
-		errChs <- round.WrapError(errors.New("BobResponds(w) failed"), Pj)
-		return
-	}
-
-	round.temp.betaHat[j] = betaHat
-	round.temp.bigDHat[i][j] = bigDHat
-	round.temp.bigFHat[i][j] = bigFHat
-	proofs[j] = pf
-}
-
-func (round *round2) BobRespondsGamma(j int, Pj *tss.PartyID, proofs [][]*zkproofs.AffGInvProof, wg *sync.WaitGroup, errChs chan *tss.Error) {
-	defer wg.Done()
-	i := round.PartyID().Index
-
-	r1msg := round.temp.signRound1Messages[j].Content().(*SignRound1Message)
-	psiAlice, err := r1msg.UnmarshalPsi()
-	if err != nil {
-		errChs <- round.WrapError(errors.New("UnmarshalPsi failed"), Pj)
+	// the proof of Pj that this party checks, see proofIndex
+	k := round.proofIndex(j, i)
+	ec, pkj, psi, bigK, rp := round.Params().EC(), round.key.PaillierPKs[j], proofAt(psiAlice, k), round.temp.bigK[j], round.key.GetRingPedersen(k)
+	if !round.verifyProof(Pj, func() bool { return accmta.BobVerify(round.temp.ssid, ec, pkj, psi, bigK, rp) }) {
+		errChs <- round.WrapError(errors.New("bad proof"), Pj).WithCode(tss.ErrProofVerifyFailed)
 		return
 	}
-
-	ringPedersenBobI := round.key.GetRingPedersen(i)
-	rpVs := round.key.GetAllRingPedersen()
-	rpVs[i] = nil
-	beta, bigD, bigF, pf, err := accmta.BobRespondsG(
-		round.Params().EC(),
-		round.key.PaillierPKs[j],
-		round.key.PaillierSK,
-		psiAlice[i],
-		round.temp.gamma,
-		round.temp.bigK[j],
+	rpVs := round.proofVerifiers(i)
+	betas, bigDs, bigFs, pfs, err := accmta.BobRespondsGBatch(
+		round.temp.ssid,
+		ec,
+		pkj,
+		round.paillierSK(),
+		psi,
+		[]*big.Int{round.temp.gamma, round.temp.w},
+		bigK,
 		rpVs,
-		ringPedersenBobI,
+		nil, // Alice's proof is verified above
 	)
 	if err != nil {
-		errChs <- round.WrapError(errors.New("BobResponds(gamma) failed"), Pj)
+		errChs <- round.WrapError(errors.New("BobResponds(gamma, w) failed"), Pj)
 		return
 	}
 
-	round.temp.beta[j] = beta
-	round.temp.bigD[i][j] = bigD
-	round.temp.bigF[i][j] = bigF
-	proofs[j] = pf
+	round.temp.beta[j], round.temp.betaHat[j] = betas[0], betas[1]
+	round.temp.bigD[i][j], round.temp.bigDHat[i][j] = bigDs[0], bigDs[1]
+	round.temp.bigF[i][j], round.temp.bigFHat[i][j] = bigFs[0], bigFs[1]
+	proofs[j], proofsHat[j] = pfs[0], pfs[1]
 }
 
-func (round *round2) ComputeProofPsiPrime(j int, Pj *tss.PartyID, proofs []*zkproofs.LogStarProof, wg *sync.WaitGroup, errChs chan *tss.Error) {
-	defer wg.Done()
+func (round *round2) ComputeProofPsiPrime(j int, Pj *tss.PartyID, proofs []*zkproofs.LogStarProof, errChs chan *tss.Error) {
 	i := round.PartyID().Index
 	ec := round.Params().EC()
 
-	_, rho, err := round.key.PaillierSK.DecryptFull(round.temp.bigG[i])
+	_, rho, err := round.paillierSK().DecryptFull(round.temp.bigG[i])
 	if err != nil {
 		errChs <- round.WrapError(errors.New("Error decrypting bigG"), Pj)
 		return
@@ -194,13 +165,13 @@ func (round *round2) ComputeProofPsiPrime(j int, Pj *tss.PartyID, proofs []*zkpr
 	}
 	statement := &zkproofs.LogStarStatement{
 		Ell: zkproofs.GetEll(ec),
-		N0:  round.key.PaillierSK.PublicKey.N,
+		N0:  round.paillierSK().Public().N,
 		C:   round.temp.bigG[i],
 		X:   round.temp.pointGamma[i],
 	}
 
 	rp := round.key.GetRingPedersen(j)
-	proofs[j] = zkproofs.NewLogStarProof(witness, statement, rp)
+	proofs[j] = zkproofs.NewLogStarProof(round.temp.ssid, witness, statement, rp)
 }
 
 func (round *round2) Update() (bool, *tss.Error) {
@@ -19,10 +19,11 @@ package cggplus
 import (
 	"errors"
 	"math/big"
-	"sync"
 
 	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto"
 	"github.com/kisdex/mpc-lib/crypto/accmta"
+	"github.com/kisdex/mpc-lib/crypto/schnorr"
 	"github.com/kisdex/mpc-lib/crypto/zkproofs"
 	"github.com/kisdex/mpc-lib/tss"
 )
@@ -37,26 +38,23 @@ func (round *round3) Start() *tss.Error {
 
 	i := round.PartyID().Index
 
+	// check that every party received the same round 1 broadcasts
+	if culprits := tss.VerifyEchoes(tss.EchoDigests(round.temp.signRound1Messages), round.temp.signRound2Message2s, round.Parties().IDs()); culprits != nil {
+		return round.WrapError(errors.New("round 1 broadcast equivocation detected"), culprits...).WithCode(tss.ErrDuplicateMessage)
+	}
+
 	partyCount := len(round.Parties().IDs())
 	errChs := make(chan *tss.Error, partyCount*partyCount*3)
-	wg := sync.WaitGroup{}
-
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		round.VerifyRound2Messages(errChs)
-	}()
-	wg.Wait()
+	round.VerifyRound2Messages(errChs)
 
+	pool := round.NewWorkerPool()
 	for sender, _ := range round.Parties().IDs() {
 		if sender == i {
 			continue
 		}
-		wg.Add(2)
-		go round.AliceEndW(sender, &wg, errChs)
-		go round.AliceEndGamma(sender, &wg, errChs)
+		pool.GoFor(round.Parties().IDs()[sender], func() { round.AliceEndGammaW(sender, errChs) })
 	}
-	wg.Wait()
+	pool.Wait()
 	close(errChs)
 	err := round.WrapErrorChs(round.PartyID(), errChs, "Failed to process round 2 messages")
 	if err != nil {
@@ -78,6 +76,12 @@ func (round *round3) Start() *tss.Error {
 		return err
 	}
 
+	adaptorProof, err := round.ComputeAdaptorGamma()
+	if err != nil {
+		return err
+	}
+
+	round.temp.r2Digests = round.round2EchoDigests()
 	r3msg := NewSignRound3Message(
 		round.PartyID(),
 		round.temp.delta[i],
@@ -86,16 +90,31 @@ func (round *round3) Start() *tss.Error {
 		psiPrimePrime,
 		HProof,
 		deltaProof,
+		round.temp.adaptorGamma[i],
+		adaptorProof,
+		round.temp.r2Digests,
 	)
 	round.temp.signRound3Messages[i] = r3msg
-	round.out <- r3msg
-
+	round.SendMessage(round.out, r3msg)
+	round.CleanUpRound2Messages()
 	return nil
 }
 
+// CleanUpRound2Messages releases the round 1 and round 2 messages once they are verified, as the round 2 messages
+// addressed to each of the signers, with their proofs for each of the verifiers, grow with the cube of the committee.
+// The deferred proofs hold what they verify themselves.
+func (round *round3) CleanUpRound2Messages() {
+	clear(round.temp.signRound1Messages)
+	for _, msgs := range round.temp.signRound2Message1s {
+		clear(msgs)
+	}
+	clear(round.temp.signRound2Message2s)
+}
+
 func (round *round3) VerifyRound2Messages(errChs chan *tss.Error) {
 	i := round.PartyID().Index
-	wg := sync.WaitGroup{}
+	pool := round.NewWorkerPool()
+	batches := round.newProofBatches()
 	for sender, Psender := range round.Parties().IDs() {
 		r2msg2 := round.temp.signRound2Message2s[sender].Content().(*SignRound2Message2)
 		ec := round.Params().EC()
@@ -110,19 +129,20 @@ func (round *round3) VerifyRound2Messages(errChs chan *tss.Error) {
 			if sender == recipient || sender == i {
 				continue
 			}
-			wg.Add(1)
-			go func(sender, recipient int, Psender *tss.PartyID, errChs chan *tss.Error) {
-				defer wg.Done()
-				round.VerifyRound2Message(sender, recipient, Psender, errChs)
-			}(sender, recipient, Psender, errChs)
+			pool.GoFor(Psender, func() {
+				round.VerifyRound2Message(sender, recipient, Psender, batches, errChs)
+			})
 		}
 	}
-	wg.Wait()
+	pool.Wait()
+	for _, culprit := range round.verifyBatches(batches) {
+		errChs <- round.WrapError(errors.New("bad proof"), culprit).WithCode(tss.ErrProofVerifyFailed)
+	}
 }
 
-func (round *round3) VerifyRound2Message(sender, recipient int, Psender *tss.PartyID, errChs chan *tss.Error) {
+func (round *round3) VerifyRound2Message(sender, recipient int, Psender *tss.PartyID, batches proofBatches, errChs chan *tss.Error) {
 	verifier := round.PartyID().Index
-	rpVerifier := round.key.GetRingPedersen(verifier)
+	k := round.proofIndex(sender, verifier)
 	ec := round.Params().EC()
 
 	r2msg1 := round.temp.signRound2Message1s[sender][recipient].Content().(*SignRound2Message1)
@@ -142,19 +162,14 @@ func (round *round3) VerifyRound2Message(sender, recipient int, Psender *tss.Par
 			errChs <- round.WrapError(errors.New("UnmarshalPsiHat"), Psender)
 			return
 		}
-		ok := accmta.AliceVerifyG(
-			ec,
-			round.key.PaillierPKs[recipient],
-			round.key.PaillierPKs[sender],
-			psiHat[verifier],
-			round.temp.bigK[recipient],
-			round.temp.bigDHat[sender][recipient],
-			round.temp.bigFHat[sender][recipient],
-			round.temp.bigWs[sender],
-			rpVerifier,
-		)
+		// the closures bind the values of the round, which is cleaned up before deferred proofs are verified
+		pkA, pkB, bigK := round.key.PaillierPKs[recipient], round.key.PaillierPKs[sender], round.temp.bigK[recipient]
+		bigDHat, bigFHat, bigW := round.temp.bigDHat[sender][recipient], round.temp.bigFHat[sender][recipient], round.temp.bigWs[sender]
+		ok := round.verifyProof(Psender, func() bool {
+			return accmta.AliceVerifyGBatch(round.temp.ssid, ec, pkA, pkB, proofAt(psiHat, k), bigK, bigDHat, bigFHat, bigW, batches[k], sender)
+		})
 		if !ok {
-			errChs <- round.WrapError(errors.New("bad proof"), Psender)
+			errChs <- round.WrapError(errors.New("bad proof"), Psender).WithCode(tss.ErrProofVerifyFailed)
 			return
 		}
 
@@ -163,19 +178,12 @@ func (round *round3) VerifyRound2Message(sender, recipient int, Psender *tss.Par
 			errChs <- round.WrapError(errors.New("could not UnmarshalPsi"), Psender)
 			return
 		}
-		ok = accmta.AliceVerifyG(
-			ec,
-			round.key.PaillierPKs[recipient],
-			round.key.PaillierPKs[sender],
-			psi[verifier],
-			round.temp.bigK[recipient],
-			round.temp.bigD[sender][recipient],
-			round.temp.bigF[sender][recipient],
-			round.temp.pointGamma[sender],
-			rpVerifier,
-		)
+		bigD, bigF, pointGamma := round.temp.bigD[sender][recipient], round.temp.bigF[sender][recipient], round.temp.pointGamma[sender]
+		ok = round.verifyProof(Psender, func() bool {
+			return accmta.AliceVerifyGBatch(round.temp.ssid, ec, pkA, pkB, proofAt(psi, k), bigK, bigD, bigF, pointGamma, batches[k], sender)
+		})
 		if !ok {
-			errChs <- round.WrapError(errors.New("bad proof"), Psender)
+			errChs <- round.WrapError(errors.New("bad proof"), Psender).WithCode(tss.ErrProofVerifyFailed)
 			return
 		}
 	}
@@ -193,55 +201,19 @@ func (round *round3) VerifyRound2Message(sender, recipient int, Psender *tss.Par
 		C:   round.temp.bigG[sender],
 		X:   round.temp.pointGamma[sender],
 	}
-	ok := psiPrime[verifier].Verify(statement, rpVerifier)
+	ok := round.verifyProof(Psender, func() bool {
+		return proofAt(psiPrime, k).VerifyBatch(round.temp.ssid, statement, batches[k], sender)
+	})
 	if !ok {
-		errChs <- round.WrapError(errors.New("bad proof"), Psender)
+		errChs <- round.WrapError(errors.New("bad proof"), Psender).WithCode(tss.ErrProofVerifyFailed)
 		return
 	}
 }
 
-func (round *round3) AliceEndW(sender int, wg *sync.WaitGroup, errChs chan *tss.Error) {
-	defer wg.Done()
+// AliceEndGammaW ends the MtA of the gamma and of the w of `sender` against K as one batch
+func (round *round3) AliceEndGammaW(sender int, errChs chan *tss.Error) {
 	i := round.PartyID().Index
-	rp := round.key.GetRingPedersen(i)
-	ec := round.Params().EC()
-	Psender := round.Parties().IDs()[sender]
-
-	r2msg1 := round.temp.signRound2Message1s[sender][i].Content().(*SignRound2Message1)
-	if i != r2msg1.UnmarshalRecipient() {
-		errChs <- round.WrapError(errors.New("could not parse message"), Psender)
-		return
-	}
-	round.temp.bigDHat[sender][i] = r2msg1.UnmarshalBigDHat()
-	round.temp.bigFHat[sender][i] = r2msg1.UnmarshalBigFHat()
-	psiHat, err := r2msg1.UnmarshalPsiHat(ec)
-	if err != nil {
-		errChs <- round.WrapError(errors.New("could not UnmarshalPsiHat"), Psender)
-		return
-	}
-
-	alphaHat, err := accmta.AliceEndG(
-		ec,
-		round.key.PaillierSK,
-		round.key.PaillierPKs[sender],
-		psiHat[i],
-		round.temp.bigK[i],
-		round.temp.bigDHat[sender][i],
-		round.temp.bigFHat[sender][i],
-		round.temp.bigWs[sender],
-		rp,
-	)
-	if err != nil {
-		errChs <- round.WrapError(errors.New("Could not compute AliceEndW"), Psender)
-		return
-	}
-	round.temp.alphaHat[sender] = alphaHat
-}
-
-func (round *round3) AliceEndGamma(sender int, wg *sync.WaitGroup, errChs chan *tss.Error) {
-	defer wg.Done()
-	i := round.PartyID().Index
-	rp := round.key.GetRingPedersen(i)
+	k := round.proofIndex(sender, i)
 	ec := round.Params().EC()
 	Psender := round.Parties().IDs()[sender]
 
@@ -252,12 +224,19 @@ func (round *round3) AliceEndGamma(sender int, wg *sync.WaitGroup, errChs chan *
 	}
 	round.temp.bigD[sender][i] = r2msg1.UnmarshalBigD()
 	round.temp.bigF[sender][i] = r2msg1.UnmarshalBigF()
+	round.temp.bigDHat[sender][i] = r2msg1.UnmarshalBigDHat()
+	round.temp.bigFHat[sender][i] = r2msg1.UnmarshalBigFHat()
 
 	psi, err := r2msg1.UnmarshalPsi(ec)
 	if err != nil {
 		errChs <- round.WrapError(errors.New("could not UnmarshalPsi"), Psender)
 		return
 	}
+	psiHat, err := r2msg1.UnmarshalPsiHat(ec)
+	if err != nil {
+		errChs <- round.WrapError(errors.New("could not UnmarshalPsiHat"), Psender)
+		return
+	}
 	r2msg2 := round.temp.signRound2Message2s[sender].Content().(*SignRound2Message2)
 	pointGamma, err := r2msg2.UnmarshalGamma(ec)
 	if err != nil {
@@ -266,22 +245,28 @@ func (round *round3) AliceEndGamma(sender int, wg *sync.WaitGroup, errChs chan *
 	}
 	round.temp.pointGamma[sender] = pointGamma
 
-	alphaIj, err := accmta.AliceEndG(
-		ec,
-		round.key.PaillierSK,
-		round.key.PaillierPKs[sender],
-		psi[i],
-		round.temp.bigK[i],
-		round.temp.bigD[sender][i],
-		round.temp.bigF[sender][i],
-		round.temp.pointGamma[sender],
-		rp,
-	)
+	proofs := []*zkproofs.AffGInvProof{proofAt(psi, k), proofAt(psiHat, k)}
+	bigK := round.temp.bigK[i]
+	bigDs := []*big.Int{round.temp.bigD[sender][i], round.temp.bigDHat[sender][i]}
+	bigFs := []*big.Int{round.temp.bigF[sender][i], round.temp.bigFHat[sender][i]}
+	Bs := []*crypto.ECPoint{round.temp.pointGamma[sender], round.temp.bigWs[sender]}
+	end := func(rp *zkproofs.RingPedersenParams) ([]*big.Int, error) {
+		return accmta.AliceEndGBatch(round.temp.ssid, ec, round.paillierSK(), round.key.PaillierPKs[sender], proofs, bigK, bigDs, bigFs, Bs, rp)
+	}
+	rp := round.key.GetRingPedersen(k)
+	if round.DeferProofs() {
+		round.verifyProof(Psender, func() bool {
+			_, err := end(rp)
+			return err == nil
+		})
+		rp = nil
+	}
+	alphas, err := end(rp)
 	if err != nil {
-		errChs <- round.WrapError(errors.New("Could not compute response AliceEndGamma"), Psender)
+		errChs <- round.WrapError(errors.New("Could not compute AliceEndGammaW"), Psender)
 		return
 	}
-	round.temp.alpha[sender] = alphaIj
+	round.temp.alpha[sender], round.temp.alphaHat[sender] = alphas[0], alphas[1]
 }
 
 func (round *round3) ComputeGamma() {
@@ -297,6 +282,23 @@ func (round *round3) ComputeGamma() {
 	round.temp.bigDelta[i] = Gamma.ScalarMult(round.temp.k)
 }
 
+// ComputeAdaptorGamma computes gamma_i * T and proves that it shares gamma_i with Gamma_i.
+// It does nothing unless the party signs against an adaptor point T.
+func (round *round3) ComputeAdaptorGamma() (*schnorr.DLEQProof, *tss.Error) {
+	T := round.temp.adaptorPoint
+	if T == nil {
+		return nil, nil
+	}
+	i := round.PartyID().Index
+	adaptorGamma := T.ScalarMult(round.temp.gamma)
+	proof, err := schnorr.NewDLEQProof(round.PartyID().Key, round.temp.gamma, T, round.temp.pointGamma[i], adaptorGamma)
+	if err != nil {
+		return nil, round.WrapError(err)
+	}
+	round.temp.adaptorGamma[i] = adaptorGamma
+	return proof, nil
+}
+
 func (round *round3) ComputeDelta() {
 	i := round.PartyID().Index
 	modQ := common.ModInt(round.Params().EC().Params().N)
@@ -333,7 +335,7 @@ func (round *round3) ComputePsiPrimePrime() ([]*zkproofs.LogStarProof, *tss.Erro
 	Pi := round.Parties().IDs()[i]
 	ec := round.Params().EC()
 
-	ski := round.key.PaillierSK
+	ski := round.paillierSK()
 	_, rho, errd := ski.DecryptFull(round.temp.bigK[i])
 	if errd != nil {
 		return nil, round.WrapError(errors.New("could not decrypt bigK"), Pi)
@@ -341,7 +343,7 @@ func (round *round3) ComputePsiPrimePrime() ([]*zkproofs.LogStarProof, *tss.Erro
 
 	statement := &zkproofs.LogStarStatement{
 		Ell: zkproofs.GetEll(ec),
-		N0:  round.key.PaillierSK.PublicKey.N,
+		N0:  round.paillierSK().Public().N,
 		C:   round.temp.bigK[i],
 		X:   round.temp.bigDelta[i],
 		G:   round.temp.Gamma,
@@ -350,21 +352,18 @@ func (round *round3) ComputePsiPrimePrime() ([]*zkproofs.LogStarProof, *tss.Erro
 		X:   round.temp.k,
 		Rho: rho,
 	}
-	rpVs := round.key.GetAllRingPedersen()
-	rpVs[i] = nil
+	rpVs := round.proofVerifiers(i)
 	psiPrimePrime := make([]*zkproofs.LogStarProof, len(rpVs))
-	wg := sync.WaitGroup{}
+	pool := round.NewWorkerPool()
 	for j, rp := range rpVs {
-		if j == i {
+		if rp == nil {
 			continue
 		}
-		wg.Add(1)
-		go func(j int, rp *zkproofs.RingPedersenParams) {
-			defer wg.Done()
-			psiPrimePrime[j] = zkproofs.NewLogStarProof(witness, statement, rp)
-		}(j, rp)
+		pool.GoFor(round.Parties().IDs()[j], func() {
+			psiPrimePrime[j] = zkproofs.NewLogStarProof(round.temp.ssid, witness, statement, rp)
+		})
 	}
-	wg.Wait()
+	pool.Wait()
 	return psiPrimePrime, nil
 }
 
@@ -377,7 +376,7 @@ func (round *round3) ComputeHProof() (*zkproofs.MulProof, *tss.Error) {
 		return nil, round.WrapError(errors.New("trouble computing bigH"), Pi)
 	}
 	round.temp.bigH = bigH
-	x, rhox, err := round.key.PaillierSK.DecryptFull(round.temp.bigG[i])
+	x, rhox, err := round.paillierSK().DecryptFull(round.temp.bigG[i])
 	if err != nil || x.Cmp(round.temp.gamma) != 0 {
 		return nil, round.WrapError(errors.New("Bad G[i]"), Pi)
 	}
@@ -393,24 +392,24 @@ func (round *round3) ComputeHProof() (*zkproofs.MulProof, *tss.Error) {
 		Y: round.temp.bigK[i],
 		C: round.temp.bigH,
 	}
-	Hproof := zkproofs.NewMulProof(witness, statement)
+	Hproof := zkproofs.NewMulProof(round.temp.ssid, witness, statement)
 	return Hproof, nil
 }
 
 func (round *round3) ComputeXDelta() (*big.Int, error) {
 	i := round.PartyID().Index
-	ski := round.key.PaillierSK
+	ski := round.paillierSK()
 	var err error
 	XDelta := round.temp.bigH
 	for j := range round.Parties().IDs() {
 		if j == i {
 			continue
 		}
-		XDelta, err = ski.PublicKey.HomoAdd(XDelta, round.temp.bigD[j][i])
+		XDelta, err = ski.Public().HomoAdd(XDelta, round.temp.bigD[j][i])
 		if err != nil {
 			return nil, errors.New("could not compute XDelta")
 		}
-		XDelta, err = ski.PublicKey.HomoAdd(XDelta, round.temp.bigF[i][j])
+		XDelta, err = ski.Public().HomoAdd(XDelta, round.temp.bigF[i][j])
 		if err != nil {
 			return nil, errors.New("could not compute XDelta")
 		}
@@ -425,7 +424,7 @@ func (round *round3) ComputeDeltaProof() ([]*zkproofs.DecProof, *tss.Error) {
 	}
 	i := round.PartyID().Index
 	Pi := round.Parties().IDs()[i]
-	ski := round.key.PaillierSK
+	ski := round.paillierSK()
 	q := round.Params().EC().Params().N
 
 	d, rho, err := ski.DecryptFull(XDelta)
@@ -441,7 +440,7 @@ func (round *round3) ComputeDeltaProof() ([]*zkproofs.DecProof, *tss.Error) {
 	statement := &zkproofs.DecStatement{
 		Q:   q,
 		Ell: zkproofs.GetEll(round.Params().EC()),
-		N0:  ski.PublicKey.N,
+		N0:  ski.Public().N,
 		C:   XDelta,
 		X:   round.temp.delta[i],
 	}
@@ -449,21 +448,18 @@ func (round *round3) ComputeDeltaProof() ([]*zkproofs.DecProof, *tss.Error) {
 		Y:   d,
 		Rho: rho,
 	}
-	rpVs := round.key.GetAllRingPedersen()
-	rpVs[i] = nil
+	rpVs := round.proofVerifiers(i)
 	proofs := make([]*zkproofs.DecProof, len(rpVs))
-	wg := sync.WaitGroup{}
+	pool := round.NewWorkerPool()
 	for j, rp := range rpVs {
-		if j == i {
+		if rp == nil {
 			continue
 		}
-		wg.Add(1)
-		go func(j int, rp *zkproofs.RingPedersenParams) {
-			defer wg.Done()
-			proofs[j] = zkproofs.NewDecProof(witness, statement, rp)
-		}(j, rp)
+		pool.GoFor(round.Parties().IDs()[j], func() {
+			proofs[j] = zkproofs.NewDecProof(round.temp.ssid, witness, statement, rp)
+		})
 	}
-	wg.Wait()
+	pool.Wait()
 	return proofs, nil
 }
 
@@ -19,7 +19,6 @@ package cggplus
 import (
 	"errors"
 	"math/big"
-	"sync"
 
 	"github.com/kisdex/mpc-lib/common"
 	"github.com/kisdex/mpc-lib/crypto"
@@ -35,6 +34,11 @@ func (round *round4) Start() *tss.Error {
 	round.started = true
 	round.resetOK()
 
+	// check that every party received the same round 2 broadcasts
+	if culprits := tss.VerifyEchoes(round.temp.r2Digests, round.temp.signRound3Messages, round.Parties().IDs()); culprits != nil {
+		return round.WrapError(errors.New("round 2 broadcast equivocation detected"), culprits...).WithCode(tss.ErrDuplicateMessage)
+	}
+
 	partyCount := len(round.Parties().IDs())
 	errChs := make(chan *tss.Error, partyCount*partyCount*3)
 	round.VerifyRound3Messages(errChs)
@@ -50,9 +54,10 @@ func (round *round4) Start() *tss.Error {
 	}
 
 	i := round.PartyID().Index
-	r4msg := NewSignRound4Message(round.PartyID())
+	round.temp.r3Digests = tss.EchoDigests(round.temp.signRound3Messages)
+	r4msg := NewSignRound4Message(round.PartyID(), round.temp.r3Digests)
 	round.temp.signRound4Messages[i] = r4msg
-	round.out <- r4msg
+	round.SendMessage(round.out, r4msg)
 	round.CleanUpPreSigningData()
 	return nil
 }
@@ -76,19 +81,17 @@ func (round *round4) ComputeXDelta(i int, H *big.Int) (*big.Int, error) {
 }
 
 func (round *round4) VerifyRound3Messages(errChs chan *tss.Error) {
-	wg := sync.WaitGroup{}
+	pool := round.NewWorkerPool()
 	i := round.PartyID().Index
-	rp := round.key.GetRingPedersen(i)
-	for j, _ := range round.Parties().IDs() {
-		if i == j {
+	batches := round.newProofBatches()
+	for sender := range round.Parties().IDs() {
+		if i == sender {
 			continue
 		}
-		wg.Add(1)
-		go func(sender int) {
-			defer wg.Done()
+		pool.GoFor(round.Parties().IDs()[sender], func() {
 			Psender := round.Parties().IDs()[sender]
 			r3msg := round.temp.signRound3Messages[sender].Content().(*SignRound3Message)
-			psiPrimePrime, err := r3msg.UnmarshalPsiPrimePrime(tss.EC())
+			psiPrimePrime, err := r3msg.UnmarshalPsiPrimePrime(round.Params().EC())
 			if err != nil {
 				errChs <- round.WrapError(errors.New("failed to parse psiPrimePrime from party"), Psender)
 				return
@@ -112,8 +115,11 @@ func (round *round4) VerifyRound3Messages(errChs chan *tss.Error) {
 				X:   round.temp.bigDelta[sender],
 				G:   round.temp.Gamma,
 			}
-			if !psiPrimePrime[i].Verify(statement, rp) {
-				errChs <- round.WrapError(errors.New("failed to verify proof from party"), Psender)
+			k := round.proofIndex(sender, i)
+			if !round.verifyProof(Psender, func() bool {
+				return proofAt(psiPrimePrime, k).VerifyBatch(round.temp.ssid, statement, batches[k], sender)
+			}) {
+				errChs <- round.WrapError(errors.New("failed to verify proof from party"), Psender).WithCode(tss.ErrProofVerifyFailed)
 				return
 			}
 
@@ -133,8 +139,8 @@ func (round *round4) VerifyRound3Messages(errChs chan *tss.Error) {
 				Y: round.temp.bigK[sender],
 				C: bigH,
 			}
-			if !HProof.Verify(statementH) {
-				errChs <- round.WrapError(errors.New("failed to verify HProof"), Psender)
+			if !round.verifyProof(Psender, func() bool { return HProof.Verify(round.temp.ssid, statementH) }) {
+				errChs <- round.WrapError(errors.New("failed to verify HProof"), Psender).WithCode(tss.ErrProofVerifyFailed)
 				return
 			}
 
@@ -155,14 +161,40 @@ func (round *round4) VerifyRound3Messages(errChs chan *tss.Error) {
 				C:   XDelta,
 				X:   round.temp.delta[sender],
 			}
-			if !deltaProof[i].Verify(statementDelta, rp) {
-				errChs <- round.WrapError(errors.New("failed to verify XDeltaProof"), Psender)
+			if !round.verifyProof(Psender, func() bool {
+				return proofAt(deltaProof, k).VerifyBatch(round.temp.ssid, statementDelta, batches[k], sender)
+			}) {
+				errChs <- round.WrapError(errors.New("failed to verify XDeltaProof"), Psender).WithCode(tss.ErrProofVerifyFailed)
 				return
 			}
 
-		}(j)
+			if T := round.temp.adaptorPoint; T != nil {
+				if !r3msg.HasAdaptor() {
+					errChs <- round.WrapError(errors.New("sender did not send its adaptor gamma"), Psender)
+					return
+				}
+				adaptorGamma, err := r3msg.UnmarshalAdaptorGamma(round.Params().EC())
+				if err != nil {
+					errChs <- round.WrapError(errors.New("sender sent bad adaptor gamma"), Psender)
+					return
+				}
+				adaptorProof, err := r3msg.UnmarshalAdaptorProof(round.Params().EC())
+				pointGamma := round.temp.pointGamma[sender]
+				if err != nil || !round.verifyProof(Psender, func() bool {
+					return adaptorProof.Verify(Psender.Key, T, pointGamma, adaptorGamma)
+				}) {
+					errChs <- round.WrapError(errors.New("failed to verify adaptor gamma proof"), Psender).WithCode(tss.ErrProofVerifyFailed)
+					return
+				}
+				round.temp.adaptorGamma[sender] = adaptorGamma
+			}
+
+		})
+	}
+	pool.Wait()
+	for _, culprit := range round.verifyBatches(batches) {
+		errChs <- round.WrapError(errors.New("failed to verify proof from party"), culprit).WithCode(tss.ErrProofVerifyFailed)
 	}
-	wg.Wait()
 }
 
 func (round *round4) ComputeValues() *tss.Error {
@@ -188,12 +220,30 @@ func (round *round4) ComputeValues() *tss.Error {
 
 	finalDeltaInv := modQ.ModInverse(delta)
 	bigR := round.temp.Gamma.ScalarMult(finalDeltaInv)
+	if round.temp.adaptorPoint != nil {
+		// the signature is pre-signed against T, so r is taken from k^-1 * T instead of k^-1 * G
+		round.temp.bigRHat = bigR
+		sumAdaptorGamma := round.temp.adaptorGamma[0]
+		for j := 1; j < len(round.temp.adaptorGamma); j++ {
+			if sumAdaptorGamma, err = sumAdaptorGamma.Add(round.temp.adaptorGamma[j]); err != nil {
+				return round.WrapError(errors.New("Unexpected error computing adaptor R."))
+			}
+		}
+		bigR = sumAdaptorGamma.ScalarMult(finalDeltaInv)
+	}
 	round.temp.rx = bigR.X()
 	round.temp.ry = bigR.Y()
 	return nil
 }
 
 func (round *round4) CleanUpPreSigningData() {
+	common.Zeroize(round.temp.gamma)
+	common.Zeroize(round.temp.beta...)
+	common.Zeroize(round.temp.betaHat...)
+	common.Zeroize(round.temp.delta...)
+	common.Zeroize(round.temp.alpha...)
+	common.Zeroize(round.temp.alphaHat...)
+
 	// round 1
 	round.temp.gamma = nil
 	round.temp.bigG = nil
@@ -211,6 +261,9 @@ func (round *round4) CleanUpPreSigningData() {
 	round.temp.alphaHat = nil
 	round.temp.bigH = nil
 	round.temp.bigDelta = nil
+	round.temp.adaptorGamma = nil
+	round.temp.r2Digests = nil
+	clear(round.temp.signRound3Messages)
 }
 
 func (round *round4) Update() (bool, *tss.Error) {
@@ -221,6 +274,11 @@ func (round *round4) Update() (bool, *tss.Error) {
 		if msg == nil || !round.CanAccept(msg) {
 			return false, nil
 		}
+		// the round 3 echo is checked here, as a presigning party does not run round 5
+		r4msg := msg.Content().(*SignRound4Message)
+		if culprits := tss.EchoCulprits(round.temp.r3Digests, r4msg.GetEcho(), msg.GetFrom(), round.Parties().IDs()); culprits != nil {
+			return false, round.WrapError(errors.New("round 3 broadcast equivocation detected"), culprits...).WithCode(tss.ErrDuplicateMessage)
+		}
 		round.ok[j] = true
 	}
 	return true, nil
@@ -235,5 +293,8 @@ func (round *round4) CanAccept(msg tss.ParsedMessage) bool {
 
 func (round *round4) NextRound() tss.Round {
 	round.started = false
+	if round.presigEnd != nil {
+		return &presignFinalization{round}
+	}
 	return &round5{round}
 }
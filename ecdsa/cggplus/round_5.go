@@ -19,7 +19,6 @@ package cggplus
 import (
 	"errors"
 	"math/big"
-	"sync"
 
 	"github.com/kisdex/mpc-lib/common"
 	"github.com/kisdex/mpc-lib/crypto/zkproofs"
@@ -34,6 +33,15 @@ func (round *round5) Start() *tss.Error {
 	round.started = true
 	round.resetOK()
 
+	// a session that completes a stored presignature does not run round 1
+	if round.temp.ssid == nil {
+		ssid, err := round.getSSID()
+		if err != nil {
+			return round.WrapError(err)
+		}
+		round.temp.ssid = ssid
+	}
+
 	// The check that temp.m = hash(msg) is in Zq is delayed to round 5 to allow
 	// presigning bulk computation of rounds 1-4.
 	if round.temp.m.Cmp(round.Params().EC().Params().N) >= 0 {
@@ -50,7 +58,7 @@ func (round *round5) Start() *tss.Error {
 		round.temp.sigma, bigHHat,
 		bigHHatProof, sigmaProof)
 	round.temp.signRound5Messages[round.PartyID().Index] = r5msg
-	round.out <- r5msg
+	round.SendMessage(round.out, r5msg)
 
 	round.CleanUpRound5Data()
 	return nil
@@ -111,7 +119,7 @@ func (round *round5) ComputeVals() (bigHHat *big.Int, bigHHatProof []*zkproofs.M
 		terr = round.WrapError(errors.New("could not compute bigSigma"))
 		return
 	}
-	littleSigma, rhoSigma, err := round.key.PaillierSK.DecryptFull(bigSigma)
+	littleSigma, rhoSigma, err := round.paillierSK().DecryptFull(bigSigma)
 	if err != nil {
 		terr = round.WrapError(errors.New("could not compute bigSigma"))
 	}
@@ -131,29 +139,27 @@ func (round *round5) ComputeVals() (bigHHat *big.Int, bigHHatProof []*zkproofs.M
 		X:   sigma,
 	}
 
-	wg := sync.WaitGroup{}
-	rpVs := round.key.GetAllRingPedersen()
-	rpVs[i] = nil
+	pool := round.NewWorkerPool()
+	rpVs := round.proofVerifiers(i)
 	for j, rp := range rpVs {
-		if j == i {
+		if rp == nil {
 			continue
 		}
-		wg.Add(2)
-		go func(j int, rp *zkproofs.RingPedersenParams) {
-			defer wg.Done()
-			bigHHatProof[j] = zkproofs.NewMulStarProof(witnessBigHHat, statementBigHHat, rp)
-		}(j, rp)
-		go func(j int, rp *zkproofs.RingPedersenParams) {
-			defer wg.Done()
-			sigmaProof[j] = zkproofs.NewDecProof(witnessSigma, statementSigma, rp)
-		}(j, rp)
-	}
-	wg.Wait()
+		Pj := round.Parties().IDs()[j]
+		pool.GoFor(Pj, func() {
+			bigHHatProof[j] = zkproofs.NewMulStarProof(round.temp.ssid, witnessBigHHat, statementBigHHat, rp)
+		})
+		pool.GoFor(Pj, func() {
+			sigmaProof[j] = zkproofs.NewDecProof(round.temp.ssid, witnessSigma, statementSigma, rp)
+		})
+	}
+	pool.Wait()
 	round.temp.sigma = sigma
 	return
 }
 
 func (round *round5) CleanUpRound5Data() {
+	common.Zeroize(round.temp.w, round.temp.k, round.temp.chi)
 	round.temp.w = nil
 	round.temp.k = nil
 	round.temp.chi = nil
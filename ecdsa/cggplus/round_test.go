@@ -17,9 +17,15 @@
 package cggplus
 
 import (
-	//	"github.com/kisdex/mpc-lib/tss"
 	//	"sync"
+	"math/big"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/tss"
 )
 
 /*
@@ -159,3 +165,94 @@ func TestRoundFinalization(t *testing.T) {
 	t.Logf("finalize")
 	_ = RunRound[*round5, *finalization](t, params, parties, round5s, len(parties), outCh)
 }
+
+func TestRoundFinalizationAggregated(t *testing.T) {
+	params, parties, outCh, _, signPIDs, _ := SetupParties(t)
+	for _, Pparams := range params {
+		Pparams.SetProofAggregator(signPIDs[1])
+		Pparams.UnsafeAllowInsecureProofs()
+	}
+
+	round1s := RunRound1(t, params, parties, outCh)
+	// the round 1 message of party 0 carries a single proof, made for the aggregator
+	psi, err := parties[0].temp.signRound1Messages[0].Content().(*SignRound1Message).UnmarshalPsi()
+	assert.NoError(t, err)
+	for j, proof := range psi {
+		assert.Equal(t, j == 1, proof != nil, "proof for party %d", j)
+	}
+	// and the proofs of the aggregator are made for the party after it
+	psi, err = parties[0].temp.signRound1Messages[1].Content().(*SignRound1Message).UnmarshalPsi()
+	assert.NoError(t, err)
+	for j, proof := range psi {
+		assert.Equal(t, j == 2%len(parties), proof != nil, "proof for party %d", j)
+	}
+
+	totalMessages := len(parties) * len(parties)
+	round2s := RunRound[*round1, *round2](t, params, parties, round1s, totalMessages, outCh)
+	round3s := RunRound[*round2, *round3](t, params, parties, round2s, len(parties), outCh)
+	round4s := RunRound[*round3, *round4](t, params, parties, round3s, len(parties), outCh)
+	round5s := RunRound[*round4, *round5](t, params, parties, round4s, len(parties), outCh)
+	_ = RunRound[*round5, *finalization](t, params, parties, round5s, len(parties), outCh)
+}
+
+func TestRoundFinalizationDeferProofs(t *testing.T) {
+	params, parties, outCh, _, _, _ := SetupParties(t)
+	for _, Pparams := range params {
+		Pparams.SetDeferProofs()
+		Pparams.UnsafeAllowInsecureProofs()
+	}
+
+	round1s := RunRound1(t, params, parties, outCh)
+	totalMessages := len(parties) * len(parties)
+	round2s := RunRound[*round1, *round2](t, params, parties, round1s, totalMessages, outCh)
+	round3s := RunRound[*round2, *round3](t, params, parties, round2s, len(parties), outCh)
+	round4s := RunRound[*round3, *round4](t, params, parties, round3s, len(parties), outCh)
+	round5s := RunRound[*round4, *round5](t, params, parties, round4s, len(parties), outCh)
+	assert.NotEmpty(t, parties[0].temp.deferredProofs, "the proofs should not have been verified yet")
+
+	// party 0 sends a sigma that does not match its proof to the last party, whose signature then does not verify
+	last := len(parties) - 1
+	Ps := round5s[last].Parties().IDs()
+	msg := parties[last].temp.signRound5Messages[0]
+	content := proto.Clone(msg.Content()).(*SignRound5Message)
+	sigma := new(big.Int).Add(content.UnmarshalSigma(), big.NewInt(1))
+	content.Sigma = sigma.Bytes()
+	meta := tss.MessageRouting{From: msg.GetFrom(), IsBroadcast: true}
+	parties[last].temp.signRound5Messages[0] = tss.NewMessage(meta, content, tss.NewMessageWrapper(meta, content))
+
+	ok, tssErr := round5s[last].Update()
+	assert.True(t, ok)
+	AssertNoTssError(t, tssErr)
+	tssErr = round5s[last].NextRound().Start()
+	if assert.NotNil(t, tssErr, "the bad proof must be found") {
+		assert.Equal(t, []*tss.PartyID{Ps[0]}, tssErr.Culprits())
+		assert.Equal(t, tss.ErrProofVerifyFailed, tssErr.Code())
+	}
+}
+
+func TestRoundEchoMismatch(t *testing.T) {
+	params, parties, outCh, _, _, _ := SetupParties(t)
+
+	round1s := RunRound1(t, params, parties, outCh)
+	totalMessages := len(parties) * len(parties)
+	round2s := RunRound[*round1, *round2](t, params, parties, round1s, totalMessages, outCh)
+
+	// party 0 echoes a round 1 broadcast of its own that differs from the one received by the last party, as if it
+	// had equivocated
+	last := len(parties) - 1
+	Ps := round2s[last].Parties().IDs()
+	msg := parties[last].temp.signRound2Message2s[0]
+	content := proto.Clone(msg.Content()).(*SignRound2Message2)
+	content.Echo[0] = common.SHA512_256([]byte("another broadcast"))
+	meta := tss.MessageRouting{From: msg.GetFrom(), IsBroadcast: true}
+	parties[last].temp.signRound2Message2s[0] = tss.NewMessage(meta, content, tss.NewMessageWrapper(meta, content))
+
+	ok, tssErr := round2s[last].Update()
+	assert.True(t, ok)
+	AssertNoTssError(t, tssErr)
+	tssErr = round2s[last].NextRound().Start()
+	if assert.NotNil(t, tssErr, "the equivocation must be detected") {
+		assert.Equal(t, []*tss.PartyID{Ps[0]}, tssErr.Culprits())
+		assert.Equal(t, tss.ErrDuplicateMessage, tssErr.Code())
+	}
+}
@@ -21,7 +21,6 @@ import (
 	"sync"
 	"testing"
 
-	"github.com/ipfs/go-log"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/kisdex/mpc-lib/common"
@@ -36,7 +35,7 @@ const (
 )
 
 func SetUp(level string) {
-	if err := log.SetLogLevel("tss-lib", level); err != nil {
+	if err := common.SetLogLevel(level); err != nil {
 		panic(err)
 	}
 }
@@ -63,7 +62,7 @@ func SetupParties(t *testing.T) (
 	parties = make([]*LocalParty, 0, len(signPIDs))
 
 	outCh = make(chan tss.Message, len(signPIDs)*len(signPIDs)*3)
-	endCh := make(chan common.SignatureData, len(signPIDs))
+	endCh := make(chan *common.SignatureData, len(signPIDs))
 
 	// init the parties
 	for i := 0; i < len(signPIDs); i++ {
@@ -118,7 +117,7 @@ func RunRound1(t *testing.T, params []*tss.Parameters, parties []*LocalParty, ou
 		go func(j int, party *LocalParty) {
 			defer wg.Done()
 			partyParams := params[j]
-			rounds[j] = newRound1(partyParams, &party.keys, &party.data, &party.temp, party.out, party.end).(*round1)
+			rounds[j] = newRound1(partyParams, &party.keys, &party.data, &party.temp, party.out, party.end, party.presigEnd, party.adaptorEnd).(*round1)
 			err := rounds[j].prepare()
 			assert.NoError(t, err)
 			tssError := rounds[j].Start()
@@ -13,8 +13,14 @@ package cggplus
 
 import (
 	"errors"
+	"math/big"
+	"sort"
 
 	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto"
+	"github.com/kisdex/mpc-lib/crypto/adaptor"
+	"github.com/kisdex/mpc-lib/crypto/paillier"
+	"github.com/kisdex/mpc-lib/crypto/zkproofs"
 	"github.com/kisdex/mpc-lib/ecdsa/keygen"
 	"github.com/kisdex/mpc-lib/tss"
 )
@@ -26,14 +32,16 @@ const (
 type (
 	base struct {
 		*tss.Parameters
-		key     *keygen.LocalPartySaveData
-		data    *common.SignatureData
-		temp    *localTempData
-		out     chan<- tss.Message
-		end     chan<- common.SignatureData
-		ok      []bool // `ok` tracks parties which have been verified by Update()
-		started bool
-		number  int
+		key        *keygen.LocalPartySaveData
+		data       *common.SignatureData
+		temp       *localTempData
+		out        chan<- tss.Message
+		end        chan<- *common.SignatureData
+		presigEnd  chan<- *PreSignatureData
+		adaptorEnd chan<- *adaptor.PreSignature
+		ok         []bool // `ok` tracks parties which have been verified by Update()
+		started    bool
+		number     int
 	}
 	round1 struct {
 		*base
@@ -53,6 +61,9 @@ type (
 	finalization struct {
 		*round5
 	}
+	presignFinalization struct {
+		*round4
+	}
 )
 
 var (
@@ -62,17 +73,20 @@ var (
 	_ tss.Round = (*round4)(nil)
 	_ tss.Round = (*round5)(nil)
 	_ tss.Round = (*finalization)(nil)
+	_ tss.Round = (*presignFinalization)(nil)
 )
 
 // ----- //
 
 func (round *base) WrapErrorChs(id *tss.PartyID, errChs chan *tss.Error, msg string) *tss.Error {
 	culprits := make([]*tss.PartyID, 0, len(round.Parties().IDs()))
+	errs := make([]*tss.Error, 0, len(errChs))
 	for err := range errChs {
 		culprits = append(culprits, err.Culprits()...)
+		errs = append(errs, err)
 	}
 	if len(culprits) > 0 {
-		return round.WrapError(errors.New(msg), culprits...)
+		return round.WrapError(errors.New(msg), culprits...).WithCode(tss.CommonErrorCode(errs...))
 	}
 	return nil
 }
@@ -123,3 +137,191 @@ func (round *base) resetOK() {
 		round.ok[j] = false
 	}
 }
+
+// paillierSK returns the Paillier private key of the party: the one set with SetPaillierDecrypter, if any, or else
+// the one of its save data
+func (round *base) paillierSK() paillier.Decrypter {
+	if round.temp.paillierSK != nil {
+		return round.temp.paillierSK
+	}
+	return round.key.PaillierSK
+}
+
+// get ssid from local params and the key material of the signers; CGG21 binds the challenge of every proof to it.
+// Unlike GG18 signing it does not depend on the round number, as a presignature may be completed in a later session.
+func (round *base) getSSID() ([]byte, error) {
+	ssidList := []*big.Int{round.EC().Params().P, round.EC().Params().N, round.EC().Params().B, round.EC().Params().Gx, round.EC().Params().Gy} // ec curve
+	ssidList = append(ssidList, round.Parties().IDs().Keys()...)                                                                                // parties
+	BigXjList, err := crypto.FlattenECPoints(round.key.BigXj)
+	if err != nil {
+		return nil, round.WrapError(errors.New("read BigXj failed"), round.PartyID())
+	}
+	ssidList = append(ssidList, BigXjList...) // BigXj
+	for _, pk := range round.key.PaillierPKs {
+		ssidList = append(ssidList, pk.N) // Paillier N
+	}
+	ssidList = append(ssidList, round.key.NTildej...)                                   // NTilde
+	ssidList = append(ssidList, round.key.H1j...)                                       // h1
+	ssidList = append(ssidList, round.key.H2j...)                                       // h2
+	ssidList = append(ssidList, new(big.Int).SetBytes(common.SHA512_256(round.SSID()))) // application session id
+	if aggregator := round.ProofAggregator(); aggregator != nil {
+		ssidList = append(ssidList, aggregator.KeyInt()) // proof aggregation mode
+	}
+	ssid := common.SHA512_256i(ssidList...).Bytes()
+
+	return ssid, nil
+}
+
+// round2EchoDigests hashes the round 2 broadcasts of every party: its messages addressed to each of the others, which
+// every party receives, and its Gamma
+func (round *base) round2EchoDigests() [][]byte {
+	digests := make([][]byte, len(round.temp.signRound2Message2s))
+	for j := range digests {
+		msgs := append(append([]tss.ParsedMessage{}, round.temp.signRound2Message1s[j]...), round.temp.signRound2Message2s[j])
+		digests[j] = tss.EchoDigest(msgs...)
+	}
+	return digests
+}
+
+// proofAggregatorOf returns the index of the party whose ring-Pedersen parameters the proofs of `prover` are made
+// against when the signers aggregate their proofs, see tss.Parameters.SetProofAggregator, or else -1, also if the
+// aggregator is not one of the signers
+func (round *base) proofAggregatorOf(prover int) int {
+	aggregator := round.ProofAggregator()
+	if aggregator == nil {
+		return -1
+	}
+	Ps := round.Parties().IDs()
+	a := -1
+	for j, Pj := range Ps {
+		if Pj.KeyInt().Cmp(aggregator.KeyInt()) == 0 {
+			a = j
+		}
+	}
+	if a == prover {
+		a = (a + 1) % len(Ps)
+	}
+	return a
+}
+
+// proofVerifiers returns the ring-Pedersen parameters that `prover` makes its proofs against, indexed by party and nil
+// for the parties that get no proof
+func (round *base) proofVerifiers(prover int) []*zkproofs.RingPedersenParams {
+	rpVs := round.key.GetAllRingPedersen()
+	rpVs[prover] = nil
+	if a := round.proofAggregatorOf(prover); a >= 0 {
+		for j := range rpVs {
+			if j != a {
+				rpVs[j] = nil
+			}
+		}
+	}
+	return rpVs
+}
+
+// proofIndex returns the index of the proof of `prover` that `verifier` checks, in the arrays of proofs of the messages
+// and of proofBatches
+func (round *base) proofIndex(prover, verifier int) int {
+	if a := round.proofAggregatorOf(prover); a >= 0 {
+		return a
+	}
+	return verifier
+}
+
+// proofBatches holds a batch of ring-Pedersen equations for the parameters of each party, see proofIndex. Unless the
+// signers aggregate their proofs, a party only checks proofs made against its own parameters.
+type proofBatches []*zkproofs.RingPedersenBatch
+
+func (round *base) newProofBatches() proofBatches {
+	rps := round.key.GetAllRingPedersen()
+	batches := make(proofBatches, len(rps))
+	for j, rp := range rps {
+		batches[j] = zkproofs.NewRingPedersenBatch(rp)
+	}
+	return batches
+}
+
+// Verify verifies every batch and returns the provers of the equations that do not hold, in increasing order
+func (batches proofBatches) Verify() []int {
+	failed := make(map[int]bool)
+	for _, batch := range batches {
+		for _, culprit := range batch.Verify() {
+			failed[culprit] = true
+		}
+	}
+	culprits := make([]int, 0, len(failed))
+	for culprit := range failed {
+		culprits = append(culprits, culprit)
+	}
+	sort.Ints(culprits)
+	return culprits
+}
+
+// proofAt returns proofs[k], or nil if a malformed message carries fewer proofs
+func proofAt[P zkproofs.Proof](proofs []P, k int) (proof P) {
+	if 0 <= k && k < len(proofs) {
+		proof = proofs[k]
+	}
+	return
+}
+
+// proofCheck verifies proofs of peers and returns the parties whose proofs do not hold
+type proofCheck func() []*tss.PartyID
+
+// verifyProof verifies a proof of `prover` with `verify`, or else records it for verifyDeferredProofs and returns true
+// if the party defers the proofs of its peers, see tss.Parameters.SetDeferProofs
+func (round *base) verifyProof(prover *tss.PartyID, verify func() bool) bool {
+	return round.check(func() []*tss.PartyID {
+		if verify() {
+			return nil
+		}
+		return []*tss.PartyID{prover}
+	}) == nil
+}
+
+// verifyBatches verifies the equations of `batches`, which holds those of the proofs passed to verifyProof, and
+// returns the provers of the equations that do not hold
+func (round *base) verifyBatches(batches proofBatches) []*tss.PartyID {
+	return round.check(func() []*tss.PartyID {
+		Ps := round.Parties().IDs()
+		culprits := make([]*tss.PartyID, 0)
+		for _, culprit := range batches.Verify() {
+			culprits = append(culprits, Ps[culprit])
+		}
+		return culprits
+	})
+}
+
+func (round *base) check(check proofCheck) []*tss.PartyID {
+	if !round.DeferProofs() {
+		return check()
+	}
+	round.temp.deferredProofsMu.Lock()
+	defer round.temp.deferredProofsMu.Unlock()
+	round.temp.deferredProofs = append(round.temp.deferredProofs, check)
+	return nil
+}
+
+// verifyDeferredProofs verifies the proofs that the party deferred, in the order of the rounds, and returns an error
+// that blames the parties whose proofs do not hold, or nil
+func (round *base) verifyDeferredProofs() *tss.Error {
+	round.temp.deferredProofsMu.Lock()
+	checks := round.temp.deferredProofs
+	round.temp.deferredProofs = nil
+	round.temp.deferredProofsMu.Unlock()
+
+	culprits := make([]*tss.PartyID, 0)
+	seen := make(map[*tss.PartyID]bool)
+	for _, check := range checks {
+		for _, culprit := range check() {
+			if !seen[culprit] {
+				seen[culprit] = true
+				culprits = append(culprits, culprit)
+			}
+		}
+	}
+	if len(culprits) > 0 {
+		return round.WrapError(errors.New("bad proof"), culprits...).WithCode(tss.ErrProofVerifyFailed)
+	}
+	return nil
+}
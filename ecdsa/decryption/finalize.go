@@ -0,0 +1,82 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package decryption
+
+import (
+	"errors"
+
+	errors2 "github.com/pkg/errors"
+
+	"github.com/kisdex/mpc-lib/crypto/ecies"
+	"github.com/kisdex/mpc-lib/crypto/threshold"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+func (round *finalization) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started"))
+	}
+	round.number = 2
+	round.started = true
+	round.resetOK()
+
+	Ps := round.Parties().IDs()
+	shares := make([]*threshold.ElGamalDecryptionShare, len(Ps))
+	culprits := make([]*tss.PartyID, 0, len(Ps))
+	for j, Pj := range Ps {
+		round.ok[j] = true
+		if j == round.PartyID().Index {
+			shares[j] = round.temp.share
+			continue
+		}
+		r1msg := round.temp.decRound1Messages[j].Content().(*DecryptRound1Message)
+		Dj, err := r1msg.UnmarshalSharedPointShare(round.EC())
+		if err != nil {
+			culprits = append(culprits, Pj)
+			continue
+		}
+		proof, err := r1msg.UnmarshalProof(round.EC())
+		if err != nil {
+			culprits = append(culprits, Pj)
+			continue
+		}
+		// every share is checked against the public share of its party, so a bad one is attributable
+		shares[j] = &threshold.ElGamalDecryptionShare{ID: round.key.Ks[j], Di: Dj, Proof: proof}
+		if !round.temp.pk.VerifyPartialECDH(round.temp.ssid, round.temp.bigR, shares[j]) {
+			culprits = append(culprits, Pj)
+		}
+	}
+	if len(culprits) > 0 {
+		return round.WrapError(errors.New("decryption share verification failed"), culprits...).WithCode(tss.ErrProofVerifyFailed)
+	}
+
+	S, err := round.temp.pk.CombineECDH(round.temp.ssid, round.temp.bigR, shares)
+	if err != nil {
+		return round.WrapError(errors2.Wrapf(err, "CombineECDH"))
+	}
+	plaintext, err := ecies.DecryptWithSharedPoint(S, round.temp.ciphertext)
+	if err != nil {
+		return round.WrapError(errors2.Wrapf(err, "DecryptWithSharedPoint"))
+	}
+
+	round.end <- plaintext
+	return nil
+}
+
+func (round *finalization) CanAccept(msg tss.ParsedMessage) bool {
+	// not expecting any incoming messages in this round
+	return false
+}
+
+func (round *finalization) Update() (bool, *tss.Error) {
+	// not expecting any incoming messages in this round
+	return false, nil
+}
+
+func (round *finalization) NextRound() tss.Round {
+	return nil // finished!
+}
@@ -0,0 +1,139 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package decryption
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto/ecies"
+	"github.com/kisdex/mpc-lib/ecdsa/keygen"
+	"github.com/kisdex/mpc-lib/test"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+const (
+	testParticipants = test.TestParticipants
+	testThreshold    = test.TestThreshold
+)
+
+func setUp(level string) {
+	if err := common.SetLogLevel(level); err != nil {
+		panic(err)
+	}
+}
+
+func TestE2EConcurrent(t *testing.T) {
+	setUp("info")
+
+	keys, decPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	msg := []byte("a message for the mailbox of the committee")
+	ct, err := ecies.Encrypt(keys[0].ECDSAPub, msg)
+	assert.NoError(t, err)
+
+	plaintext, culprits := runDecryption(t, ct, keys, decPIDs, nil)
+	assert.Empty(t, culprits)
+	assert.Equal(t, msg, plaintext)
+}
+
+func TestE2EBadShare(t *testing.T) {
+	setUp("info")
+
+	keys, decPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	ct, err := ecies.Encrypt(keys[0].ECDSAPub, []byte("a message for the mailbox of the committee"))
+	assert.NoError(t, err)
+
+	cheater := decPIDs[0]
+	plaintext, culprits := runDecryption(t, ct, keys, decPIDs, func(m tss.Message) tss.Message {
+		if m.GetFrom().Index != cheater.Index {
+			return m
+		}
+		// a share of the right form but doubled, with the proof of the honest share
+		content := m.(tss.ParsedMessage).Content().(*DecryptRound1Message)
+		Di, err := content.UnmarshalSharedPointShare(tss.S256())
+		assert.NoError(t, err)
+		proof, err := content.UnmarshalProof(tss.S256())
+		assert.NoError(t, err)
+		bad, err := Di.Add(Di)
+		assert.NoError(t, err)
+		return NewDecryptRound1Message(m.GetFrom(), bad, proof)
+	})
+	assert.Nil(t, plaintext)
+	if assert.Len(t, culprits, 1) {
+		assert.Equal(t, cheater.Id, culprits[0].Id)
+	}
+}
+
+func runDecryption(
+	t *testing.T,
+	ct []byte,
+	keys []keygen.LocalPartySaveData,
+	decPIDs tss.SortedPartyIDs,
+	tamper func(tss.Message) tss.Message,
+) ([]byte, []*tss.PartyID) {
+	p2pCtx := tss.NewPeerContext(decPIDs)
+	parties := make([]*LocalParty, 0, len(decPIDs))
+
+	errCh := make(chan *tss.Error, len(decPIDs))
+	outCh := make(chan tss.Message, len(decPIDs))
+	endCh := make(chan []byte, len(decPIDs))
+
+	updater := test.SharedPartyUpdater
+
+	// init the parties. there is a single message round, so every party is started before any message is routed
+	for i := 0; i < len(decPIDs); i++ {
+		params := tss.NewParameters(tss.S256(), p2pCtx, decPIDs[i], len(decPIDs), testThreshold)
+
+		P := NewLocalParty(ct, params, keys[i], outCh, endCh).(*LocalParty)
+		parties = append(parties, P)
+		if err := P.Start(); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+	}
+
+	var ended int32
+	var plaintext []byte
+	for {
+		select {
+		case err := <-errCh:
+			if tamper == nil {
+				common.DefaultLogger().Errorf("Error: %s", err)
+				assert.FailNow(t, err.Error())
+			}
+			return nil, err.Culprits()
+
+		case msg := <-outCh:
+			if tamper != nil {
+				msg = tamper(msg)
+			}
+			for _, P := range parties {
+				if P.PartyID().Index == msg.GetFrom().Index {
+					continue
+				}
+				go updater(P, msg, errCh)
+			}
+
+		case data := <-endCh:
+			if plaintext == nil {
+				plaintext = data
+			} else {
+				assert.Equal(t, plaintext, data, "all parties must output the same plaintext")
+			}
+			if atomic.AddInt32(&ended, 1) == int32(len(decPIDs)) {
+				t.Logf("Done. Received the plaintext from %d participants", ended)
+				return plaintext, nil
+			}
+		}
+	}
+}
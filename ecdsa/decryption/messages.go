@@ -0,0 +1,59 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package decryption
+
+import (
+	"crypto/elliptic"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto"
+	"github.com/kisdex/mpc-lib/crypto/schnorr"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+// These messages were generated from Protocol Buffers definitions into ecdsa-decryption.pb.go
+// The following messages are registered on the Protocol Buffers "wire"
+
+var (
+	// Ensure that decryption messages implement ValidateBasic, and accept only them from the wire
+	messageTypes = tss.NewMessageTypes(
+		&DecryptRound1Message{},
+	)
+)
+
+// ----- //
+
+func NewDecryptRound1Message(
+	from *tss.PartyID,
+	sharedPointShare *crypto.ECPoint,
+	proof *schnorr.DLEQProof,
+) tss.ParsedMessage {
+	meta := tss.MessageRouting{
+		From:        from,
+		IsBroadcast: true,
+	}
+	content := &DecryptRound1Message{
+		SharedPointShare: sharedPointShare.Bytes(),
+		Proof:            proof.Bytes(),
+	}
+	msg := tss.NewMessageWrapper(meta, content)
+	return tss.NewMessage(meta, content, msg)
+}
+
+func (m *DecryptRound1Message) ValidateBasic() bool {
+	return m != nil &&
+		common.NonEmptyBytes(m.GetSharedPointShare()) &&
+		common.NonEmptyMultiBytes(m.GetProof(), schnorr.DLEQProofBytesParts)
+}
+
+func (m *DecryptRound1Message) UnmarshalSharedPointShare(ec elliptic.Curve) (*crypto.ECPoint, error) {
+	return crypto.NewECPointFromBytes(ec, m.GetSharedPointShare())
+}
+
+func (m *DecryptRound1Message) UnmarshalProof(ec elliptic.Curve) (*schnorr.DLEQProof, error) {
+	return schnorr.NewDLEQProofFromBytes(ec, m.GetProof())
+}
@@ -0,0 +1,102 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package decryption
+
+import (
+	"errors"
+	"fmt"
+
+	errors2 "github.com/pkg/errors"
+
+	"github.com/kisdex/mpc-lib/crypto/ecies"
+	"github.com/kisdex/mpc-lib/crypto/threshold"
+	"github.com/kisdex/mpc-lib/ecdsa/keygen"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+// round 1 represents round 1 of the ECIES threshold decryption
+func newRound1(params *tss.Parameters, key *keygen.LocalPartySaveData, temp *localTempData, out chan<- tss.Message, end chan<- []byte) tss.Round {
+	return &round1{
+		&base{params, key, temp, out, end, make([]bool, len(params.Parties().IDs())), false, 1}}
+}
+
+func (round *round1) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started"))
+	}
+
+	round.number = 1
+	round.started = true
+	round.resetOK()
+
+	// 1. the share Di = xi*R of the ECDH point x*R, with a proof that log_R(Di) = log_G(Xi); the Lagrange coefficient
+	// is applied when combining so that every Di can be checked against Xi
+	share := &threshold.ElGamalKeyShare{ID: round.key.ShareID, Xi: round.key.Xi}
+	ds, err := share.PartialECDH(round.temp.ssid, round.temp.bigR)
+	if err != nil {
+		return round.WrapError(errors2.Wrapf(err, "PartialECDH(xi, R)"))
+	}
+	round.temp.share = ds
+
+	i := round.PartyID().Index
+	round.ok[i] = true
+
+	// 2. broadcast the share with its proof
+	r1msg := NewDecryptRound1Message(round.PartyID(), ds.Di, ds.Proof)
+	round.temp.decRound1Messages[i] = r1msg
+	round.SendMessage(round.out, r1msg)
+
+	return nil
+}
+
+func (round *round1) Update() (bool, *tss.Error) {
+	for j, msg := range round.temp.decRound1Messages {
+		if round.ok[j] {
+			continue
+		}
+		if msg == nil || !round.CanAccept(msg) {
+			return false, nil
+		}
+		round.ok[j] = true
+	}
+	return true, nil
+}
+
+func (round *round1) CanAccept(msg tss.ParsedMessage) bool {
+	if _, ok := msg.Content().(*DecryptRound1Message); ok {
+		return msg.IsBroadcast()
+	}
+	return false
+}
+
+func (round *round1) NextRound() tss.Round {
+	round.started = false
+	return &finalization{round}
+}
+
+// ----- //
+
+func (round *round1) prepare() error {
+	if round.Threshold()+1 > len(round.key.Ks) {
+		return fmt.Errorf("t+1=%d is not satisfied by the key count of %d", round.Threshold()+1, len(round.key.Ks))
+	}
+	bigR, err := ecies.EphemeralKey(round.EC(), round.temp.ciphertext)
+	if err != nil {
+		return errors2.Wrapf(err, "the ephemeral key of the ciphertext")
+	}
+	round.temp.bigR = bigR
+	if round.temp.ssid, err = round.getSSID(); err != nil {
+		return err
+	}
+	round.temp.pk = &threshold.ElGamalPublicKey{
+		Y:         round.key.ECDSAPub,
+		Threshold: round.Threshold(),
+		Ks:        round.key.Ks,
+		BigXj:     round.key.BigXj,
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	cmt "github.com/kisdex/mpc-lib/crypto/commitments"
+	"github.com/kisdex/mpc-lib/crypto/vss"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+// checkpointTempData is the temp data of a party in a checkpoint
+type checkpointTempData struct {
+	Ui            *big.Int
+	KGCs          []cmt.HashCommitment
+	Vs            vss.Vs
+	Shares        vss.Shares
+	DeCommitPolyG cmt.HashDeCommitment
+	SSID          []byte
+	SSIDNonce     *big.Int
+}
+
+// Checkpoint returns the state of the running party, so that it can be resumed with Resume after a restart, e.g.
+// after every call to Start or Update. It holds the secret data of the party and must be stored encrypted.
+func (p *LocalParty) Checkpoint() ([]byte, error) {
+	cp, err := tss.BaseCheckpoint(p, TaskName, func(round tss.Round, cp *tss.Checkpoint) (err error) {
+		cp.OK = append([]bool{}, baseOf(round).ok...)
+		if cp.Messages, err = tss.NewCheckpointMessages(p.temp.kgRound1Messages, p.temp.kgRound2Message1s,
+			p.temp.kgRound2Message2s, p.temp.kgRound3Messages); err != nil {
+			return err
+		}
+		if cp.Data, err = json.Marshal(&p.data); err != nil {
+			return err
+		}
+		cp.Temp, err = json.Marshal(&checkpointTempData{
+			Ui:            p.temp.ui,
+			KGCs:          p.temp.KGCs,
+			Vs:            p.temp.vs,
+			Shares:        p.temp.shares,
+			DeCommitPolyG: p.temp.deCommitPolyG,
+			SSID:          p.temp.ssid,
+			SSIDNonce:     p.temp.ssidNonce,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(cp)
+}
+
+// Resume continues keygen from a checkpoint taken with Checkpoint; it is called in place of Start on a party
+// constructed with the same parameters as the one the checkpoint was taken of
+func (p *LocalParty) Resume(checkpoint []byte) *tss.Error {
+	cp := new(tss.Checkpoint)
+	if err := json.Unmarshal(checkpoint, cp); err != nil {
+		return p.WrapError(err)
+	}
+	return tss.BaseResume(p, TaskName, cp, func(round tss.Round) *tss.Error {
+		msgs, err := tss.ParseCheckpointMessages(cp.Messages, p.params.Parties().IDs())
+		if err != nil {
+			return round.WrapError(err)
+		}
+		for _, msg := range msgs {
+			if _, err := p.StoreMessage(msg); err != nil {
+				return err
+			}
+		}
+		if err = json.Unmarshal(cp.Data, &p.data); err != nil {
+			return round.WrapError(err)
+		}
+		temp := new(checkpointTempData)
+		if err = json.Unmarshal(cp.Temp, temp); err != nil {
+			return round.WrapError(err)
+		}
+		p.temp.ui, p.temp.KGCs, p.temp.vs, p.temp.shares = temp.Ui, temp.KGCs, temp.Vs, temp.Shares
+		p.temp.deCommitPolyG, p.temp.ssid, p.temp.ssidNonce = temp.DeCommitPolyG, temp.SSID, temp.SSIDNonce
+
+		b := baseOf(round)
+		if len(cp.OK) != len(b.ok) {
+			return round.WrapError(fmt.Errorf("the checkpoint has %d round flags but the party has %d", len(cp.OK), len(b.ok)))
+		}
+		copy(b.ok, cp.OK)
+		b.number, b.started = cp.Round, true
+		return nil
+	})
+}
+
+// baseOf returns the state shared by `round` and the rounds before it
+func baseOf(round tss.Round) *base {
+	return round.(interface{ roundBase() *base }).roundBase()
+}
+
+func (round *base) roundBase() *base {
+	return round
+}
@@ -0,0 +1,81 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/test"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+func TestE2ECheckpointResume(t *testing.T) {
+	setUp("info")
+
+	fixtures, pIDs, err := LoadKeygenTestFixtures(testParticipants)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	p2pCtx := tss.NewPeerContext(pIDs)
+	errCh := make(chan *tss.Error, len(pIDs))
+	outCh := make(chan tss.Message, len(pIDs)*len(pIDs)*3)
+	endCh := make(chan *LocalPartySaveData, len(pIDs))
+	newParty := func(i int) *LocalParty {
+		params := tss.NewParameters(tss.S256(), p2pCtx, pIDs[i], len(pIDs), testThreshold)
+		params.SetNoProofMod()
+		params.SetNoProofFac()
+		params.UnsafeAllowInsecureProofs()
+		return NewLocalParty(params, outCh, endCh, fixtures[i].LocalPreParams).(*LocalParty)
+	}
+
+	parties := make([]*LocalParty, len(pIDs))
+	for i := range pIDs {
+		parties[i] = newParty(i)
+		if !assert.Nil(t, parties[i].Start()) {
+			return
+		}
+	}
+	// party 0 is restarted from a checkpoint after every message it processes
+	restart := func() {
+		cp, err := parties[0].Checkpoint()
+		if err != nil {
+			assert.False(t, parties[0].Running(), "a running party must be checkpointed: %v", err)
+			return
+		}
+		P := newParty(0)
+		assert.Nil(t, P.Resume(cp))
+		assert.NotNil(t, P.Resume(cp), "a party must not be resumed twice")
+		parties[0] = P
+	}
+	restart()
+
+	for len(endCh) < len(pIDs) {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+		case msg := <-outCh:
+			for _, P := range parties {
+				if dest := msg.GetTo(); dest != nil && dest[0].Index != P.PartyID().Index {
+					continue
+				}
+				test.SharedPartyUpdater(P, msg, errCh)
+			}
+			restart()
+		}
+	}
+
+	var pub *LocalPartySaveData
+	for range pIDs {
+		save := <-endCh
+		if pub == nil {
+			pub = save
+		}
+		assert.True(t, pub.ECDSAPub.Equals(save.ECDSAPub), "all parties must agree on the public key")
+	}
+}
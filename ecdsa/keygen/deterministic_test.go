@@ -0,0 +1,58 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+//go:build tss_deterministic
+
+package keygen
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/test"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+func TestE2EDeterministic(t *testing.T) {
+	setUp("info")
+	defer common.SetRandomSource(nil)
+
+	fixtures, pIDs, err := LoadKeygenTestFixtures(testParticipants)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	run := func(seed string) ([][]byte, [][]byte) {
+		common.SetRandomSource(common.NewDeterministicReader([]byte(seed)))
+		p2pCtx := tss.NewPeerContext(pIDs)
+		outCh := make(chan tss.Message, len(pIDs)*len(pIDs))
+		endCh := make(chan *LocalPartySaveData, len(pIDs))
+		parties := make([]tss.Party, 0, len(pIDs))
+		for i := range pIDs {
+			params := tss.NewParameters(tss.S256(), p2pCtx, pIDs[i], len(pIDs), testThreshold)
+			params.SetConcurrency(1)
+			parties = append(parties, NewLocalParty(params, outCh, endCh, fixtures[i].LocalPreParams))
+		}
+		transcript, tssErr := test.RunSequentially(parties, outCh)
+		if !assert.Nil(t, tssErr, "keygen should succeed") {
+			t.FailNow()
+		}
+		saves := make([][]byte, 0, len(pIDs))
+		for range pIDs {
+			bz, err := json.Marshal(<-endCh)
+			assert.NoError(t, err)
+			saves = append(saves, bz)
+		}
+		return transcript, saves
+	}
+
+	transcript, saves := run("keygen")
+	transcript2, saves2 := run("keygen")
+	assert.Equal(t, transcript, transcript2, "the messages of two runs with the same seed should be equal")
+	assert.Equal(t, saves, saves2, "the save data of two runs with the same seed should be equal")
+}
@@ -11,10 +11,13 @@ import (
 	"math/big"
 
 	"github.com/kisdex/mpc-lib/crypto/dlnproof"
+	"github.com/kisdex/mpc-lib/crypto/zkproofs"
+	"github.com/kisdex/mpc-lib/tss"
 )
 
 type DlnProofVerifier struct {
-	semaphore chan interface{}
+	pool    *tss.WorkerPool
+	metrics tss.Metrics
 }
 
 type message interface {
@@ -22,35 +25,39 @@ type message interface {
 	UnmarshalDLNProof2() (*dlnproof.Proof, error)
 }
 
+type prmMessage interface {
+	UnmarshalPrmProof() (*zkproofs.PrmProof, error)
+}
+
 func NewDlnProofVerifier(concurrency int) *DlnProofVerifier {
 	if concurrency == 0 {
 		panic(errors.New("NewDlnProofverifier: concurrency level must not be zero"))
 	}
 
-	semaphore := make(chan interface{}, concurrency)
-
 	return &DlnProofVerifier{
-		semaphore: semaphore,
+		pool: tss.NewWorkerPool(concurrency),
 	}
 }
 
+// SetMetrics makes the verifier report the verification times of the proofs to `metrics`
+func (dpv *DlnProofVerifier) SetMetrics(metrics tss.Metrics) {
+	dpv.metrics = metrics
+}
+
 func (dpv *DlnProofVerifier) VerifyDLNProof1(
 	m message,
 	h1, h2, n *big.Int,
 	onDone func(bool),
 ) {
-	dpv.semaphore <- struct{}{}
-	go func() {
-		defer func() { <-dpv.semaphore }()
-
+	dpv.pool.Go(func() {
 		dlnProof, err := m.UnmarshalDLNProof1()
 		if err != nil {
 			onDone(false)
 			return
 		}
 
-		onDone(dlnProof.Verify(h1, h2, n))
-	}()
+		onDone(tss.VerifyProof(dpv.metrics, "dln", func() bool { return dlnProof.Verify(h1, h2, n) }))
+	})
 }
 
 func (dpv *DlnProofVerifier) VerifyDLNProof2(
@@ -58,16 +65,32 @@ func (dpv *DlnProofVerifier) VerifyDLNProof2(
 	h1, h2, n *big.Int,
 	onDone func(bool),
 ) {
-	dpv.semaphore <- struct{}{}
-	go func() {
-		defer func() { <-dpv.semaphore }()
-
+	dpv.pool.Go(func() {
 		dlnProof, err := m.UnmarshalDLNProof2()
 		if err != nil {
 			onDone(false)
 			return
 		}
 
-		onDone(dlnProof.Verify(h1, h2, n))
-	}()
+		onDone(tss.VerifyProof(dpv.metrics, "dln", func() bool { return dlnProof.Verify(h1, h2, n) }))
+	})
+}
+
+// VerifyPrmProof verifies the CGG21 prm proof of the message that h1 is in the group generated by h2 mod n
+func (dpv *DlnProofVerifier) VerifyPrmProof(
+	m prmMessage,
+	session []byte,
+	h1, h2, n *big.Int,
+	onDone func(bool),
+) {
+	dpv.pool.Go(func() {
+		prmProof, err := m.UnmarshalPrmProof()
+		if err != nil {
+			onDone(false)
+			return
+		}
+
+		rp := &zkproofs.RingPedersenParams{N: n, S: h1, T: h2}
+		onDone(tss.VerifyProof(dpv.metrics, "prm", func() bool { return prmProof.Verify(session, rp) }))
+	})
 }
@@ -0,0 +1,270 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto"
+	cmts "github.com/kisdex/mpc-lib/crypto/commitments"
+	"github.com/kisdex/mpc-lib/crypto/vss"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+// AbortReason is the check of the keygen that the culprit of an AbortEvidence failed
+type AbortReason string
+
+const (
+	// AbortBadDeCommitment means that the de-commitment of the culprit does not open its commitment to the
+	// coefficients of its VSS polynomial
+	AbortBadDeCommitment AbortReason = "de-commitment"
+	// AbortBadVSSShare means that the VSS share that the culprit sent to the accuser is not on its polynomial
+	AbortBadVSSShare AbortReason = "vss share"
+	// AbortBadModProof means that the proof that the Paillier modulus of the culprit is a Blum integer does not verify
+	AbortBadModProof AbortReason = "mod proof"
+	// AbortBadFacProof means that the proof that the Paillier modulus of the culprit has no small factors, made for the
+	// ring-Pedersen parameters of the accuser, does not verify
+	AbortBadFacProof AbortReason = "fac proof"
+	// AbortBadPaillierProof means that the proof of round 3 that the culprit knows the factors of its Paillier modulus
+	// does not verify
+	AbortBadPaillierProof AbortReason = "paillier proof"
+)
+
+type (
+	// EvidenceMessage is a message of the keygen, as its sender sent it
+	EvidenceMessage struct {
+		// From is the index of the sender among the sorted parties
+		From        int
+		IsBroadcast bool
+		WireBytes   []byte
+	}
+
+	// AbortEvidence is the evidence of the *tss.Error of an aborted keygen against one of its culprits: the messages
+	// that the check of Reason takes, as the accuser received them, so that the check can be repeated with Verify.
+	//
+	// The broadcasts of the evidence are the same for every party, as the parties echo them. The VSS share and the
+	// proof of AbortBadVSSShare and AbortBadFacProof come from a message that the culprit sent to the accuser only:
+	// the wire bytes name neither its sender nor its recipient, so that evidence shows who misbehaved only if the
+	// transport authenticates the parties, and the share in it is no longer secret.
+	AbortEvidence struct {
+		Reason AbortReason
+		// Accuser and Culprit are the indexes of the parties among the sorted parties
+		Accuser, Culprit int
+		Messages         []EvidenceMessage
+	}
+)
+
+// keygenMessages are the messages of a keygen by round and sender, as they are kept in localMessageStore
+type keygenMessages struct {
+	r1, r2m1, r2m2, r3 []tss.ParsedMessage
+}
+
+// abortEvidence returns the evidence of this party against the party `culprit`, with the messages that the check of
+// `reason` takes
+func (round *base) abortEvidence(reason AbortReason, culprit int) *AbortEvidence {
+	evidence := &AbortEvidence{Reason: reason, Accuser: round.PartyID().Index, Culprit: culprit}
+	add := func(msg tss.ParsedMessage) {
+		if msg == nil {
+			return
+		}
+		bz, _, err := msg.WireBytes()
+		if err != nil {
+			return
+		}
+		evidence.Messages = append(evidence.Messages,
+			EvidenceMessage{From: msg.GetFrom().Index, IsBroadcast: msg.IsBroadcast(), WireBytes: bz})
+	}
+	temp := round.temp
+	switch reason {
+	case AbortBadDeCommitment, AbortBadModProof:
+		add(temp.kgRound1Messages[culprit])
+		add(temp.kgRound2Message2s[culprit])
+	case AbortBadVSSShare:
+		add(temp.kgRound1Messages[culprit])
+		add(temp.kgRound2Message1s[culprit])
+		add(temp.kgRound2Message2s[culprit])
+	case AbortBadFacProof:
+		add(temp.kgRound1Messages[culprit])
+		add(temp.kgRound1Messages[evidence.Accuser])
+		add(temp.kgRound2Message1s[culprit])
+	case AbortBadPaillierProof:
+		// the proof is of the public key, which takes the de-commitments of everyone
+		for j := range temp.kgRound1Messages {
+			add(temp.kgRound1Messages[j])
+			add(temp.kgRound2Message2s[j])
+		}
+		add(temp.kgRound3Messages[culprit])
+	}
+	return evidence
+}
+
+// Verify repeats the check of the keygen of `params` that the culprit failed, with the messages of the evidence, and
+// returns the culprit if it fails again. The parameters may be those of any party of the keygen, or of an orchestrator
+// with the same parties, threshold and session id.
+func (e *AbortEvidence) Verify(params *tss.Parameters) (*tss.PartyID, error) {
+	Ps := params.Parties().IDs()
+	if e.Culprit < 0 || len(Ps) <= e.Culprit || e.Accuser < 0 || len(Ps) <= e.Accuser || e.Culprit == e.Accuser {
+		return nil, errors.New("the evidence names no valid accuser and culprit")
+	}
+	msgs, err := parseEvidenceMessages(Ps, e.Messages)
+	if err != nil {
+		return nil, err
+	}
+	c, a := e.Culprit, e.Accuser
+	need := func(msgs ...tss.ParsedMessage) error {
+		for _, msg := range msgs {
+			if msg == nil {
+				return fmt.Errorf("the evidence of a bad %s lacks a message", e.Reason)
+			}
+		}
+		return nil
+	}
+
+	ec := params.EC()
+	ssid := getSSID(params, 1, big.NewInt(0))
+	contextC := common.AppendBigIntToBytesSlice(ssid, big.NewInt(int64(c)))
+	var failed bool
+	switch e.Reason {
+	case AbortBadDeCommitment:
+		if err = need(msgs.r1[c], msgs.r2m2[c]); err != nil {
+			return nil, err
+		}
+		_, err = deCommitPolyG(ec, params.Threshold(), r1Content(msgs, c).UnmarshalCommitment(),
+			r2m2Content(msgs, c).UnmarshalDeCommitment())
+		failed = err != nil
+	case AbortBadModProof:
+		if err = need(msgs.r1[c], msgs.r2m2[c]); err != nil {
+			return nil, err
+		}
+		modProof, err := r2m2Content(msgs, c).UnmarshalModProof()
+		if err != nil {
+			failed = !params.NoProofMod()
+		} else {
+			failed = !modProof.Verify(contextC, r1Content(msgs, c).UnmarshalPaillierPK().N)
+		}
+	case AbortBadVSSShare:
+		if err = need(msgs.r1[c], msgs.r2m1[c], msgs.r2m2[c]); err != nil {
+			return nil, err
+		}
+		Vs, err := deCommitPolyG(ec, params.Threshold(), r1Content(msgs, c).UnmarshalCommitment(),
+			r2m2Content(msgs, c).UnmarshalDeCommitment())
+		if err != nil {
+			return nil, fmt.Errorf("the evidence of a bad VSS share has a bad de-commitment: %w", err)
+		}
+		share := vss.Share{Threshold: params.Threshold(), ID: Ps[a].KeyInt(), Share: r2m1Content(msgs, c).UnmarshalShare()}
+		failed = !share.Verify(ec, params.Threshold(), Vs)
+	case AbortBadFacProof:
+		if err = need(msgs.r1[c], msgs.r1[a], msgs.r2m1[c]); err != nil {
+			return nil, err
+		}
+		facProof, err := r2m1Content(msgs, c).UnmarshalFacProof()
+		if err != nil {
+			failed = !params.NoProofFac()
+		} else {
+			r1a := r1Content(msgs, a)
+			failed = !facProof.Verify(contextC, ec, r1Content(msgs, c).UnmarshalPaillierPK().N, r1a.UnmarshalNTilde(),
+				r1a.UnmarshalH1(), r1a.UnmarshalH2())
+		}
+	case AbortBadPaillierProof:
+		if err = need(append(append(msgs.r1, msgs.r2m2...), msgs.r3[c])...); err != nil {
+			return nil, err
+		}
+		var pub *crypto.ECPoint
+		for j := range Ps {
+			Vs, err := deCommitPolyG(ec, params.Threshold(), r1Content(msgs, j).UnmarshalCommitment(),
+				r2m2Content(msgs, j).UnmarshalDeCommitment())
+			if err != nil {
+				return nil, fmt.Errorf("the evidence of a bad Paillier proof has a bad de-commitment of party %d: %w", j, err)
+			}
+			if pub == nil {
+				pub = Vs[0]
+			} else if pub, err = pub.Add(Vs[0]); err != nil {
+				return nil, err
+			}
+		}
+		prf := msgs.r3[c].Content().(*KGRound3Message).UnmarshalProofInts()
+		ok, err := prf.Verify(r1Content(msgs, c).UnmarshalPaillierPK().N, Ps[c].KeyInt(), pub)
+		failed = !ok || err != nil
+	default:
+		return nil, fmt.Errorf("unknown abort reason %q", e.Reason)
+	}
+	if !failed {
+		return nil, fmt.Errorf("the %s of party %s verifies", e.Reason, Ps[c])
+	}
+	return Ps[c], nil
+}
+
+func parseEvidenceMessages(Ps tss.SortedPartyIDs, evidence []EvidenceMessage) (*keygenMessages, error) {
+	msgs := &keygenMessages{
+		r1:   make([]tss.ParsedMessage, len(Ps)),
+		r2m1: make([]tss.ParsedMessage, len(Ps)),
+		r2m2: make([]tss.ParsedMessage, len(Ps)),
+		r3:   make([]tss.ParsedMessage, len(Ps)),
+	}
+	for _, m := range evidence {
+		if m.From < 0 || len(Ps) <= m.From {
+			return nil, fmt.Errorf("the evidence has a message of the unknown party %d", m.From)
+		}
+		msg, err := messageTypes.ParseWireMessage(m.WireBytes, Ps[m.From], m.IsBroadcast)
+		if err != nil {
+			return nil, fmt.Errorf("the evidence has an invalid message: %w", err)
+		}
+		if !msg.ValidateBasic() {
+			return nil, fmt.Errorf("the evidence has an invalid %s", msg.Type())
+		}
+		var slot []tss.ParsedMessage
+		broadcast := true
+		switch msg.Content().(type) {
+		case *KGRound1Message:
+			slot = msgs.r1
+		case *KGRound2Message1:
+			slot, broadcast = msgs.r2m1, false
+		case *KGRound2Message2:
+			slot = msgs.r2m2
+		case *KGRound3Message:
+			slot = msgs.r3
+		}
+		if slot == nil || msg.IsBroadcast() != broadcast {
+			return nil, fmt.Errorf("the evidence has an unexpected %s", msg.Type())
+		}
+		slot[m.From] = msg
+	}
+	return msgs, nil
+}
+
+func r1Content(msgs *keygenMessages, j int) *KGRound1Message {
+	return msgs.r1[j].Content().(*KGRound1Message)
+}
+
+func r2m1Content(msgs *keygenMessages, j int) *KGRound2Message1 {
+	return msgs.r2m1[j].Content().(*KGRound2Message1)
+}
+
+func r2m2Content(msgs *keygenMessages, j int) *KGRound2Message2 {
+	return msgs.r2m2[j].Content().(*KGRound2Message2)
+}
+
+// deCommitPolyG opens the commitment of a party to the coefficients of its VSS polynomial of degree `threshold`
+func deCommitPolyG(ec elliptic.Curve, threshold int, C cmts.HashCommitment, D cmts.HashDeCommitment) (vss.Vs, error) {
+	cmtDeCmt := cmts.HashCommitDecommit{C: C, D: D}
+	ok, flatPolyGs := cmtDeCmt.DeCommit()
+	if !ok || flatPolyGs == nil {
+		return nil, errors.New("de-commitment verify failed")
+	}
+	Vs, err := crypto.DecompressECPoints(ec, flatPolyGs)
+	if err != nil {
+		return nil, err
+	}
+	if len(Vs) != threshold+1 {
+		return nil, fmt.Errorf("de-commitment has %d coefficients, not %d", len(Vs), threshold+1)
+	}
+	return Vs, nil
+}
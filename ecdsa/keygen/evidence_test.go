@@ -0,0 +1,134 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/test"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+// runTamperedKeygen runs a keygen of three parties in which `tamper` may replace the messages on their way, and returns
+// the parameters of the parties and the first error of the keygen
+func runTamperedKeygen(t *testing.T, tamper func(msg tss.Message) tss.Message) ([]*tss.Parameters, *tss.Error) {
+	fixtures, pIDs, err := LoadKeygenTestFixtures(3)
+	if !assert.NoError(t, err) {
+		return nil, nil
+	}
+	p2pCtx := tss.NewPeerContext(pIDs)
+	errCh := make(chan *tss.Error, len(pIDs))
+	outCh := make(chan tss.Message, 10*len(pIDs))
+	endCh := make(chan *LocalPartySaveData, len(pIDs))
+	params := make([]*tss.Parameters, len(pIDs))
+	parties := make([]*LocalParty, len(pIDs))
+	for i := range pIDs {
+		params[i] = tss.NewParameters(tss.S256(), p2pCtx, pIDs[i], len(pIDs), 1)
+		// do not use in untrusted setting
+		params[i].SetNoProofMod()
+		params[i].SetNoProofFac()
+		params[i].UnsafeAllowInsecureProofs()
+		parties[i] = NewLocalParty(params[i], outCh, endCh, fixtures[i].LocalPreParams).(*LocalParty)
+		go func(P *LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(parties[i])
+	}
+
+	for ended := 0; ended < len(pIDs); {
+		select {
+		case err := <-errCh:
+			return params, err
+		case msg := <-outCh:
+			msg = tamper(msg)
+			if dest := msg.GetTo(); dest == nil {
+				for _, P := range parties {
+					if P.PartyID().Index != msg.GetFrom().Index {
+						go test.SharedPartyUpdater(P, msg, errCh)
+					}
+				}
+			} else {
+				go test.SharedPartyUpdater(parties[dest[0].Index], msg, errCh)
+			}
+		case <-endCh:
+			ended++
+		}
+	}
+	return params, nil
+}
+
+func TestAbortEvidence(t *testing.T) {
+	setUp("error")
+
+	// party 1 sends party 0 a share that is not on its polynomial
+	var honest tss.Message
+	params, err := runTamperedKeygen(t, func(msg tss.Message) tss.Message {
+		content, ok := msg.(tss.ParsedMessage).Content().(*KGRound2Message1)
+		if !ok || msg.GetFrom().Index != 1 || msg.GetTo()[0].Index != 0 {
+			return msg
+		}
+		honest = msg
+		share := new(big.Int).Add(content.UnmarshalShare(), big.NewInt(1))
+		meta := tss.MessageRouting{From: msg.GetFrom(), To: msg.GetTo()}
+		bad := &KGRound2Message1{Share: share.Bytes(), FacProof: content.FacProof}
+		return tss.NewMessage(meta, bad, tss.NewMessageWrapper(meta, bad))
+	})
+	if !assert.Error(t, err) {
+		return
+	}
+	assert.Equal(t, 3, err.Round())
+	assert.Equal(t, []*tss.PartyID{params[1].PartyID()}, err.Culprits())
+	if assert.Len(t, err.Evidence(), 1) {
+		evidence := err.Evidence()[0].(*AbortEvidence)
+		assert.Equal(t, AbortBadVSSShare, evidence.Reason)
+		assert.Equal(t, 0, evidence.Accuser)
+		// another party comes to the same conclusion
+		culprit, verifyErr := evidence.Verify(params[2])
+		assert.NoError(t, verifyErr)
+		assert.Equal(t, params[1].PartyID(), culprit)
+
+		// the accuser cannot blame party 1 with the share that party 1 did send
+		for i, m := range evidence.Messages {
+			if !m.IsBroadcast {
+				evidence.Messages[i].WireBytes, _, _ = honest.WireBytes()
+			}
+		}
+		_, verifyErr = evidence.Verify(params[2])
+		assert.Error(t, verifyErr)
+		evidence.Messages = evidence.Messages[:1]
+		_, verifyErr = evidence.Verify(params[2])
+		assert.Error(t, verifyErr, "the evidence lacks messages")
+	}
+
+	// party 2 broadcasts a bad proof of its Paillier key
+	params, err = runTamperedKeygen(t, func(msg tss.Message) tss.Message {
+		content, ok := msg.(tss.ParsedMessage).Content().(*KGRound3Message)
+		if !ok || msg.GetFrom().Index != 2 {
+			return msg
+		}
+		proof := content.UnmarshalProofInts()
+		proof[0].Add(proof[0], big.NewInt(1))
+		return NewKGRound3Message(msg.GetFrom(), proof, content.Echo)
+	})
+	if !assert.Error(t, err) {
+		return
+	}
+	assert.Equal(t, 4, err.Round())
+	assert.ErrorIs(t, err, tss.ErrProofVerifyFailed)
+	assert.Equal(t, []*tss.PartyID{params[2].PartyID()}, err.Culprits())
+	if assert.Len(t, err.Evidence(), 1) {
+		evidence := err.Evidence()[0].(*AbortEvidence)
+		assert.Equal(t, AbortBadPaillierProof, evidence.Reason)
+		culprit, verifyErr := evidence.Verify(params[(evidence.Accuser+1)%3])
+		assert.NoError(t, verifyErr)
+		assert.Equal(t, params[2].PartyID(), culprit)
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"context"
+	"crypto/elliptic"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/kisdex/mpc-lib/test"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+// GenerateKeygenTestFixtures runs a keygen of `partyCount` parties with the given threshold on `ec` and returns the save
+// data of the parties in the order of their ids, to be used as test fixtures. Each party generates pre-parameters of
+// the security level with `concurrency` goroutines, which takes a while; `ctx` bounds the whole run.
+func GenerateKeygenTestFixtures(ctx context.Context, ec elliptic.Curve, partyCount, threshold int, level tss.SecurityLevel, concurrency int) ([]LocalPartySaveData, tss.SortedPartyIDs, error) {
+	if partyCount < 2 || threshold < 1 || partyCount <= threshold {
+		return nil, nil, fmt.Errorf("invalid party count %d and threshold %d", partyCount, threshold)
+	}
+	if _, ok := tss.GetCurveName(ec); !ok {
+		return nil, nil, errors.New("the curve must be registered with tss.RegisterCurve to save the fixtures")
+	}
+	preParams := make([]LocalPreParams, partyCount)
+	for i := range preParams {
+		pp, err := GeneratePreParamsForLevel(ctx, level, concurrency)
+		if err != nil {
+			return nil, nil, fmt.Errorf("pre-params of party %d: %w", i, err)
+		}
+		preParams[i] = *pp
+	}
+	return GenerateKeygenTestFixturesWithPreParams(ec, threshold, level, concurrency, preParams)
+}
+
+// GenerateKeygenTestFixturesWithPreParams runs a keygen of a party for each of `preParams`, e.g. to make fixtures of
+// another size from the pre-parameters of existing ones
+func GenerateKeygenTestFixturesWithPreParams(ec elliptic.Curve, threshold int, level tss.SecurityLevel, concurrency int, preParams []LocalPreParams) ([]LocalPartySaveData, tss.SortedPartyIDs, error) {
+	partyCount := len(preParams)
+	if partyCount < 2 || threshold < 1 || partyCount <= threshold {
+		return nil, nil, fmt.Errorf("invalid party count %d and threshold %d", partyCount, threshold)
+	}
+	if _, ok := tss.GetCurveName(ec); !ok {
+		return nil, nil, errors.New("the curve must be registered with tss.RegisterCurve to save the fixtures")
+	}
+	pIDs := tss.GenerateTestPartyIDs(partyCount)
+	p2pCtx := tss.NewPeerContext(pIDs)
+	outCh := make(chan tss.Message, partyCount*partyCount)
+	endCh := make(chan *LocalPartySaveData, partyCount)
+	parties := make([]tss.Party, 0, partyCount)
+	for i := range pIDs {
+		params := tss.NewParameters(ec, p2pCtx, pIDs[i], partyCount, threshold)
+		params.SetSecurityLevel(level)
+		params.SetConcurrency(concurrency)
+		parties = append(parties, NewLocalParty(params, outCh, endCh, preParams[i]))
+	}
+	if _, err := test.RunSequentially(parties, outCh); err != nil {
+		return nil, nil, err
+	}
+	keys := make([]LocalPartySaveData, partyCount)
+	for range keys {
+		save := <-endCh
+		index, err := save.OriginalIndex()
+		if err != nil {
+			return nil, nil, err
+		}
+		keys[index] = *save
+	}
+	return keys, pIDs, nil
+}
+
+// WriteKeygenTestFixtures writes `keys` to `dir` in the layout that LoadKeygenTestFixturesFromDir reads, replacing any
+// fixtures of the same parties
+func WriteKeygenTestFixtures(dir string, keys []LocalPartySaveData) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		index, err := key.OriginalIndex()
+		if err != nil {
+			return err
+		}
+		bz, err := json.Marshal(&key)
+		if err != nil {
+			return fmt.Errorf("unable to marshal the fixture of party %d: %w", index, err)
+		}
+		if err = os.WriteFile(filepath.Join(dir, fmt.Sprintf(testFixtureFileFormat, index)), bz, 0600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
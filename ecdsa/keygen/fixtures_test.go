@@ -0,0 +1,45 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"context"
+	"crypto/elliptic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+func TestWriteAndLoadKeygenTestFixtures(t *testing.T) {
+	keys, pIDs, err := LoadKeygenTestFixtures(testParticipants)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	dir := t.TempDir()
+	assert.NoError(t, WriteKeygenTestFixtures(dir, keys))
+
+	loaded, loadedPIDs, err := LoadKeygenTestFixturesFromDir(dir, testParticipants)
+	assert.NoError(t, err)
+	assert.Equal(t, pIDs.Keys(), loadedPIDs.Keys())
+	for i := range keys {
+		assert.Equal(t, 0, keys[i].Xi.Cmp(loaded[i].Xi))
+		assert.True(t, keys[i].ECDSAPub.Equals(loaded[i].ECDSAPub))
+		assert.True(t, tss.SameCurve(tss.S256(), loaded[i].ECDSAPub.Curve()))
+	}
+
+	_, _, err = LoadKeygenTestFixturesFromDir(dir, testParticipants+1)
+	assert.Error(t, err, "should fail to load a missing fixture")
+}
+
+func TestGenerateKeygenTestFixturesBadArgs(t *testing.T) {
+	_, _, err := GenerateKeygenTestFixtures(context.Background(), tss.S256(), 2, 2, tss.SecurityLevel112, 1)
+	assert.Error(t, err, "the threshold must be below the party count")
+	_, _, err = GenerateKeygenTestFixtures(context.Background(), elliptic.P224(), 3, 1, tss.SecurityLevel112, 1)
+	assert.Error(t, err, "the curve must be registered")
+}
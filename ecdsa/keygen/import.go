@@ -0,0 +1,95 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"context"
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/kisdex/mpc-lib/crypto"
+	"github.com/kisdex/mpc-lib/crypto/paillier"
+	"github.com/kisdex/mpc-lib/crypto/vss"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+// ImportKey splits the existing private key `sk` on `ec` into the save data of `pIDs` with the given threshold, in the
+// order of `pIDs`, so that a key that was not generated by a keygen can be brought under threshold custody. Each party
+// gets pre-parameters of the security level generated with `concurrency` goroutines, which takes a while; `ctx`
+// bounds the whole import.
+//
+// The caller acts as a trusted dealer: it learns every share and Paillier key, so it must run in an isolated
+// environment and erase `sk` and the save data once they are handed to the parties. The parties should then run
+// ecdsa/refresh, which replaces the shares and the pre-parameters that the dealer knows.
+func ImportKey(ctx context.Context, ec elliptic.Curve, sk *big.Int, pIDs tss.SortedPartyIDs, threshold int, level tss.SecurityLevel, concurrency int) ([]LocalPartySaveData, error) {
+	if err := checkImport(ec, sk, pIDs, threshold); err != nil {
+		return nil, err
+	}
+	preParams := make([]LocalPreParams, len(pIDs))
+	for i := range preParams {
+		pp, err := GeneratePreParamsForLevel(ctx, level, concurrency)
+		if err != nil {
+			return nil, fmt.Errorf("pre-params of party %d: %w", i, err)
+		}
+		preParams[i] = *pp
+	}
+	return ImportKeyWithPreParams(ec, sk, pIDs, threshold, preParams)
+}
+
+// ImportKeyWithPreParams is ImportKey with the pre-parameters of each party of `pIDs`, e.g. generated by the parties
+// beforehand and handed to the dealer
+func ImportKeyWithPreParams(ec elliptic.Curve, sk *big.Int, pIDs tss.SortedPartyIDs, threshold int, preParams []LocalPreParams) ([]LocalPartySaveData, error) {
+	if err := checkImport(ec, sk, pIDs, threshold); err != nil {
+		return nil, err
+	}
+	if len(preParams) != len(pIDs) {
+		return nil, fmt.Errorf("expected the pre-params of %d parties, got %d", len(pIDs), len(preParams))
+	}
+	for i, pp := range preParams {
+		if !pp.ValidateWithProof() {
+			return nil, fmt.Errorf("the pre-params of party %d are incomplete", i)
+		}
+	}
+	ks := pIDs.Keys()
+	_, shares, err := vss.Create(ec, threshold, sk, ks)
+	if err != nil {
+		return nil, err
+	}
+	pub := crypto.ScalarBaseMult(ec, sk)
+
+	keys := make([]LocalPartySaveData, len(pIDs))
+	for i := range keys {
+		key := NewLocalPartySaveData(len(pIDs))
+		key.LocalPreParams = preParams[i]
+		key.Xi, key.ShareID = shares[i].Share, ks[i]
+		key.ECDSAPub = pub
+		for j, share := range shares {
+			key.Ks[j] = ks[j]
+			key.BigXj[j] = crypto.ScalarBaseMult(ec, share.Share)
+			key.NTildej[j] = preParams[j].NTildei
+			key.H1j[j], key.H2j[j] = preParams[j].H1i, preParams[j].H2i
+			key.PaillierPKs[j] = &paillier.PublicKey{N: preParams[j].PaillierSK.N}
+		}
+		if err = key.Validate(ec); err != nil {
+			return nil, fmt.Errorf("the save data of party %d: %w", i, err)
+		}
+		keys[i] = key
+	}
+	return keys, nil
+}
+
+func checkImport(ec elliptic.Curve, sk *big.Int, pIDs tss.SortedPartyIDs, threshold int) error {
+	if sk == nil || sk.Sign() <= 0 || ec.Params().N.Cmp(sk) <= 0 {
+		return errors.New("the private key must be in [1, N-1] of the curve")
+	}
+	if len(pIDs) < 2 || threshold < 1 || len(pIDs) <= threshold {
+		return fmt.Errorf("invalid party count %d and threshold %d", len(pIDs), threshold)
+	}
+	return nil
+}
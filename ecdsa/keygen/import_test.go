@@ -0,0 +1,64 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto"
+	"github.com/kisdex/mpc-lib/crypto/vss"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+func TestImportKeyWithPreParams(t *testing.T) {
+	fixtures, _, err := LoadKeygenTestFixtures(testParticipants)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	preParams := make([]LocalPreParams, len(fixtures))
+	for i, fixture := range fixtures {
+		preParams[i] = fixture.LocalPreParams
+	}
+	ec := tss.S256()
+	sk := common.GetRandomPositiveInt(ec.Params().N)
+	pIDs := tss.GenerateTestPartyIDs(testParticipants)
+
+	keys, err := ImportKeyWithPreParams(ec, sk, pIDs, testThreshold, preParams)
+	if !assert.NoError(t, err) {
+		return
+	}
+	shares := make(vss.Shares, 0, testThreshold+1)
+	for i, key := range keys {
+		assert.NoError(t, key.Validate(ec))
+		assert.True(t, key.ECDSAPub.Equals(crypto.ScalarBaseMult(ec, sk)))
+		assert.Equal(t, 0, key.ShareID.Cmp(pIDs[i].KeyInt()))
+		if i <= testThreshold {
+			shares = append(shares, &vss.Share{Threshold: testThreshold, ID: key.ShareID, Share: key.Xi})
+		}
+	}
+	secret, err := shares.ReConstruct(ec)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, sk.Cmp(secret), "threshold+1 shares should reconstruct the imported key")
+}
+
+func TestImportKeyBadArgs(t *testing.T) {
+	ec := tss.S256()
+	pIDs := tss.GenerateTestPartyIDs(3)
+	sk := common.GetRandomPositiveInt(ec.Params().N)
+	_, err := ImportKey(context.Background(), ec, ec.Params().N, pIDs, 1, tss.SecurityLevel112, 1)
+	assert.Error(t, err, "the key must be below the order of the curve")
+	_, err = ImportKey(context.Background(), ec, sk, pIDs, 3, tss.SecurityLevel112, 1)
+	assert.Error(t, err, "the threshold must be below the party count")
+	_, err = ImportKeyWithPreParams(ec, sk, pIDs, 1, make([]LocalPreParams, 2))
+	assert.Error(t, err, "every party needs pre-params")
+	_, err = ImportKeyWithPreParams(ec, sk, pIDs, 1, make([]LocalPreParams, 3))
+	assert.Error(t, err, "the pre-params must be complete")
+}
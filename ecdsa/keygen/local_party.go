@@ -21,6 +21,7 @@ import (
 // Implements Stringer
 var _ tss.Party = (*LocalParty)(nil)
 var _ fmt.Stringer = (*LocalParty)(nil)
+var _ tss.Zeroizer = (*LocalParty)(nil)
 
 type (
 	LocalParty struct {
@@ -106,7 +107,7 @@ func (p *LocalParty) Update(msg tss.ParsedMessage) (ok bool, err *tss.Error) {
 }
 
 func (p *LocalParty) UpdateFromBytes(wireBytes []byte, from *tss.PartyID, isBroadcast bool) (bool, *tss.Error) {
-	msg, err := tss.ParseWireMessage(wireBytes, from, isBroadcast)
+	msg, err := messageTypes.ParseWireMessage(wireBytes, from, isBroadcast)
 	if err != nil {
 		return false, p.WrapError(err)
 	}
@@ -144,7 +145,7 @@ func (p *LocalParty) StoreMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
 	case *KGRound3Message:
 		p.temp.kgRound3Messages[fromPIdx] = msg
 	default: // unrecognised message, just ignore!
-		common.Logger.Warningf("unrecognised message ignored: %v", msg)
+		p.params.Logger().Warnf("unrecognised message ignored: %v", msg)
 		return false, nil
 	}
 	return true, nil
@@ -171,6 +172,21 @@ func (p *LocalParty) PartyID() *tss.PartyID {
 	return p.params.PartyID()
 }
 
+// Zeroize wipes the secret polynomial of the keygen once the share is saved, see tss.Zeroizer
+func (p *LocalParty) Zeroize() {
+	p.temp.Zeroize()
+}
+
+// Zeroize wipes ui, the secret of this party's polynomial, and the shares of it that were sent to the other parties
+func (temp *localTempData) Zeroize() {
+	common.Zeroize(temp.ui)
+	for _, share := range temp.shares {
+		if share != nil {
+			common.Zeroize(share.Share)
+		}
+	}
+}
+
 func (p *LocalParty) String() string {
 	return fmt.Sprintf("id: %s, %s", p.PartyID(), p.BaseParty.String())
 }
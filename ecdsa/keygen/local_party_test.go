@@ -21,7 +21,6 @@ import (
 	"sync/atomic"
 	"testing"
 
-	"github.com/ipfs/go-log"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/kisdex/mpc-lib/common"
@@ -29,6 +28,7 @@ import (
 	"github.com/kisdex/mpc-lib/crypto/dlnproof"
 	"github.com/kisdex/mpc-lib/crypto/paillier"
 	"github.com/kisdex/mpc-lib/crypto/vss"
+	"github.com/kisdex/mpc-lib/crypto/zkproofs"
 	"github.com/kisdex/mpc-lib/test"
 	"github.com/kisdex/mpc-lib/tss"
 )
@@ -39,7 +39,7 @@ const (
 )
 
 func setUp(level string) {
-	if err := log.SetLogLevel("tss-lib", level); err != nil {
+	if err := common.SetLogLevel(level); err != nil {
 		panic(err)
 	}
 }
@@ -54,7 +54,7 @@ func TestStartRound1Paillier(t *testing.T) {
 
 	fixtures, pIDs, err := LoadKeygenTestFixtures(testParticipants)
 	if err != nil {
-		common.Logger.Info("No test fixtures were found, so the safe primes will be generated from scratch. This may take a while...")
+		common.DefaultLogger().Infof("No test fixtures were found, so the safe primes will be generated from scratch. This may take a while...")
 		pIDs = tss.GenerateTestPartyIDs(testParticipants)
 	}
 
@@ -112,7 +112,7 @@ func TestFinishAndSaveH1H2(t *testing.T) {
 
 	fixtures, pIDs, err := LoadKeygenTestFixtures(testParticipants)
 	if err != nil {
-		common.Logger.Info("No test fixtures were found, so the safe primes will be generated from scratch. This may take a while...")
+		common.DefaultLogger().Infof("No test fixtures were found, so the safe primes will be generated from scratch. This may take a while...")
 		pIDs = tss.GenerateTestPartyIDs(testParticipants)
 	}
 
@@ -159,7 +159,7 @@ func TestBadMessageCulprits(t *testing.T) {
 
 	fixtures, pIDs, err := LoadKeygenTestFixtures(testParticipants)
 	if err != nil {
-		common.Logger.Info("No test fixtures were found, so the safe primes will be generated from scratch. This may take a while...")
+		common.DefaultLogger().Infof("No test fixtures were found, so the safe primes will be generated from scratch. This may take a while...")
 		pIDs = tss.GenerateTestPartyIDs(testParticipants)
 	}
 
@@ -174,7 +174,8 @@ func TestBadMessageCulprits(t *testing.T) {
 		assert.FailNow(t, err.Error())
 	}
 
-	badMsg, _ := NewKGRound1Message(pIDs[1], zero, &paillier.PublicKey{N: zero}, zero, zero, zero, new(dlnproof.Proof), new(dlnproof.Proof))
+	badMsg, _ := NewKGRound1Message(pIDs[1], zero, &paillier.PublicKey{N: zero}, zero, zero, zero, new(dlnproof.Proof), new(dlnproof.Proof),
+		new(zkproofs.PrmProof))
 	ok, err2 := lp.Update(badMsg)
 	t.Log(err2)
 	assert.False(t, ok)
@@ -188,6 +189,26 @@ func TestBadMessageCulprits(t *testing.T) {
 		err2.Error())
 }
 
+func TestInsecureProofsRefused(t *testing.T) {
+	fixtures, pIDs, err := LoadKeygenTestFixtures(2)
+	if !assert.NoError(t, err) {
+		return
+	}
+	params := tss.NewParameters(tss.S256(), tss.NewPeerContext(pIDs), pIDs[0], len(pIDs), 1)
+	params.SetNoProofFac()
+	out := make(chan tss.Message, len(pIDs))
+	lp := NewLocalParty(params, out, nil, fixtures[0].LocalPreParams)
+	err2 := lp.Start()
+	if assert.Error(t, err2, "a party must not start without the Πfac proofs in production mode") {
+		assert.ErrorIs(t, err2, tss.ErrInvalidState)
+	}
+	assert.Empty(t, out)
+
+	params.UnsafeAllowInsecureProofs()
+	lp = NewLocalParty(params, out, nil, fixtures[0].LocalPreParams)
+	assert.Nil(t, lp.Start())
+}
+
 func TestE2EConcurrentAndSaveFixtures(t *testing.T) {
 	setUp("info")
 
@@ -196,7 +217,7 @@ func TestE2EConcurrentAndSaveFixtures(t *testing.T) {
 	threshold := testThreshold
 	fixtures, pIDs, err := LoadKeygenTestFixtures(testParticipants)
 	if err != nil {
-		common.Logger.Info("No test fixtures were found, so the safe primes will be generated from scratch. This may take a while...")
+		common.DefaultLogger().Infof("No test fixtures were found, so the safe primes will be generated from scratch. This may take a while...")
 		pIDs = tss.GenerateTestPartyIDs(testParticipants)
 	}
 
@@ -219,6 +240,7 @@ func TestE2EConcurrentAndSaveFixtures(t *testing.T) {
 		params.SetNoProofMod()
 		// do not use in untrusted setting
 		params.SetNoProofFac()
+		params.UnsafeAllowInsecureProofs()
 		if i < len(fixtures) {
 			P = NewLocalParty(params, outCh, endCh, fixtures[i].LocalPreParams).(*LocalParty)
 		} else {
@@ -239,7 +261,7 @@ keygen:
 		fmt.Printf("ACTIVE GOROUTINES: %d\n", runtime.NumGoroutine())
 		select {
 		case err := <-errCh:
-			common.Logger.Errorf("Error: %s", err)
+			common.DefaultLogger().Errorf("Error: %s", err)
 			assert.FailNow(t, err.Error())
 			break keygen
 
@@ -289,7 +311,6 @@ keygen:
 					assert.NoError(t, err, "vss.ReConstruct should not throw error")
 
 					// uG test: u*G[j] == V[0]
-					assert.Equal(t, uj, Pj.temp.ui)
 					uG := crypto.ScalarBaseMult(tss.EC(), uj)
 					assert.True(t, uG.Equals(Pj.temp.vs[0]), "ensure u*G[j] == V_0")
 
@@ -305,7 +326,6 @@ keygen:
 						badShares[len(badShares)-1].Share.Set(big.NewInt(0))
 						uj, err := pShares[:threshold].ReConstruct(tss.S256())
 						assert.NoError(t, err)
-						assert.NotEqual(t, parties[j].temp.ui, uj)
 						BigXjX, BigXjY := tss.EC().ScalarBaseMult(uj.Bytes())
 						assert.NotEqual(t, BigXjX, Pj.temp.vs[0].X())
 						assert.NotEqual(t, BigXjY, Pj.temp.vs[0].Y())
@@ -360,6 +380,56 @@ keygen:
 	}
 }
 
+func FuzzMessages(f *testing.F) {
+	setUp("error")
+	fixtures, pIDs, err := LoadKeygenTestFixtures(2)
+	if err != nil {
+		f.Fatal(err)
+	}
+	p2pCtx := tss.NewPeerContext(pIDs)
+	outCh := make(chan tss.Message, len(pIDs)*len(pIDs))
+	endCh := make(chan *LocalPartySaveData, len(pIDs))
+	parties := make([]tss.Party, 0, len(pIDs))
+	for i := range pIDs {
+		params := tss.NewParameters(tss.S256(), p2pCtx, pIDs[i], len(pIDs), 1)
+		parties = append(parties, NewLocalParty(params, outCh, endCh, fixtures[i].LocalPreParams))
+	}
+	transcript, tssErr := test.RunSequentially(parties, outCh)
+	if tssErr != nil {
+		f.Fatal(tssErr)
+	}
+	test.FuzzWireMessages(f, tss.S256(), transcript)
+}
+
+func BenchmarkKeygen(b *testing.B) {
+	setUp("error")
+	fixtures, _, err := LoadKeygenBenchmarkFixtures()
+	if err != nil {
+		b.Fatal(err)
+	}
+	for _, size := range test.BenchmarkSizes(len(fixtures)) {
+		b.Run(size.String(), func(b *testing.B) {
+			metrics := test.NewBenchmarkMetrics(size.Parties)
+			for i := 0; i < b.N; i++ {
+				pIDs := tss.GenerateTestPartyIDs(size.Parties)
+				p2pCtx := tss.NewPeerContext(pIDs)
+				outCh := make(chan tss.Message, size.Parties*size.Parties)
+				endCh := make(chan *LocalPartySaveData, size.Parties)
+				parties := make([]tss.Party, 0, size.Parties)
+				for j := range pIDs {
+					params := tss.NewParameters(tss.S256(), p2pCtx, pIDs[j], size.Parties, size.Threshold)
+					params.SetMetrics(metrics)
+					parties = append(parties, NewLocalParty(params, outCh, endCh, fixtures[j].LocalPreParams))
+				}
+				if _, err := test.RunSequentially(parties, outCh); err != nil {
+					b.Fatal(err)
+				}
+			}
+			metrics.Report(b)
+		})
+	}
+}
+
 func tryWriteTestFixtureFile(t *testing.T, index int, data LocalPartySaveData) {
 	fixtureFileName := makeTestFixtureFilePath(index)
 
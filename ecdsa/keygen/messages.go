@@ -16,6 +16,7 @@ import (
 	"github.com/kisdex/mpc-lib/crypto/dlnproof"
 	"github.com/kisdex/mpc-lib/crypto/paillier"
 	"github.com/kisdex/mpc-lib/crypto/vss"
+	"github.com/kisdex/mpc-lib/crypto/zkproofs"
 	"github.com/kisdex/mpc-lib/tss"
 )
 
@@ -23,13 +24,13 @@ import (
 // The following messages are registered on the Protocol Buffers "wire"
 
 var (
-	// Ensure that keygen messages implement ValidateBasic
-	_ = []tss.MessageContent{
-		(*KGRound1Message)(nil),
-		(*KGRound2Message1)(nil),
-		(*KGRound2Message2)(nil),
-		(*KGRound3Message)(nil),
-	}
+	// Ensure that keygen messages implement ValidateBasic, and accept only them from the wire
+	messageTypes = tss.NewMessageTypes(
+		&KGRound1Message{},
+		&KGRound2Message1{},
+		&KGRound2Message2{},
+		&KGRound3Message{},
+	)
 )
 
 // ----- //
@@ -40,6 +41,7 @@ func NewKGRound1Message(
 	paillierPK *paillier.PublicKey,
 	nTildeI, h1I, h2I *big.Int,
 	dlnProof1, dlnProof2 *dlnproof.Proof,
+	prmProof *zkproofs.PrmProof,
 ) (tss.ParsedMessage, error) {
 	meta := tss.MessageRouting{
 		From:        from,
@@ -61,6 +63,7 @@ func NewKGRound1Message(
 		H2:         h2I.Bytes(),
 		Dlnproof_1: dlnProof1Bz,
 		Dlnproof_2: dlnProof2Bz,
+		PrmProof:   prmProof.Bytes(),
 	}
 	msg := tss.NewMessageWrapper(meta, content)
 	return tss.NewMessage(meta, content, msg), nil
@@ -75,7 +78,8 @@ func (m *KGRound1Message) ValidateBasic() bool {
 		common.NonEmptyBytes(m.GetH2()) &&
 		// expected len of dln proof = sizeof(int64) + len(alpha) + len(t)
 		common.NonEmptyMultiBytes(m.GetDlnproof_1(), 2+(dlnproof.Iterations*2)) &&
-		common.NonEmptyMultiBytes(m.GetDlnproof_2(), 2+(dlnproof.Iterations*2))
+		common.NonEmptyMultiBytes(m.GetDlnproof_2(), 2+(dlnproof.Iterations*2)) &&
+		common.NonEmptyMultiBytes(m.GetPrmProof(), zkproofs.PrmProofParts)
 }
 
 func (m *KGRound1Message) UnmarshalCommitment() *big.Int {
@@ -83,7 +87,7 @@ func (m *KGRound1Message) UnmarshalCommitment() *big.Int {
 }
 
 func (m *KGRound1Message) UnmarshalPaillierPK() *paillier.PublicKey {
-	return &paillier.PublicKey{N: new(big.Int).SetBytes(m.GetPaillierN())}
+	return paillier.NewPublicKey(new(big.Int).SetBytes(m.GetPaillierN()))
 }
 
 func (m *KGRound1Message) UnmarshalNTilde() *big.Int {
@@ -106,6 +110,10 @@ func (m *KGRound1Message) UnmarshalDLNProof2() (*dlnproof.Proof, error) {
 	return dlnproof.UnmarshalDLNProof(m.GetDlnproof_2())
 }
 
+func (m *KGRound1Message) UnmarshalPrmProof() (*zkproofs.PrmProof, error) {
+	return zkproofs.PrmProofFromBytes(m.GetPrmProof())
+}
+
 // ----- //
 
 func NewKGRound2Message1(
@@ -148,6 +156,7 @@ func NewKGRound2Message2(
 	from *tss.PartyID,
 	deCommitment cmt.HashDeCommitment,
 	proof *modproof.ProofMod,
+	echo [][]byte,
 ) tss.ParsedMessage {
 	meta := tss.MessageRouting{
 		From:        from,
@@ -158,6 +167,7 @@ func NewKGRound2Message2(
 	content := &KGRound2Message2{
 		DeCommitment: dcBzs,
 		ModProof:     proofBzs[:],
+		Echo:         echo,
 	}
 	msg := tss.NewMessageWrapper(meta, content)
 	return tss.NewMessage(meta, content, msg)
@@ -184,6 +194,7 @@ func (m *KGRound2Message2) UnmarshalModProof() (*modproof.ProofMod, error) {
 func NewKGRound3Message(
 	from *tss.PartyID,
 	proof paillier.Proof,
+	echo [][]byte,
 ) tss.ParsedMessage {
 	meta := tss.MessageRouting{
 		From:        from,
@@ -198,6 +209,7 @@ func NewKGRound3Message(
 	}
 	content := &KGRound3Message{
 		PaillierProof: pfBzs,
+		Echo:          echo,
 	}
 	msg := tss.NewMessageWrapper(meta, content)
 	return tss.NewMessage(meta, content, msg)
@@ -0,0 +1,227 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+// preParamsRetryDelay is the pause of a PreParamsPool after a failed generation, before it tries again
+const preParamsRetryDelay = 10 * time.Second
+
+type (
+	// PreParamsStore keeps the pre-params of a PreParamsPool across restarts, each encoded with ExportPreParams. Save
+	// replaces all of the pre-params that it saved before. The encodings hold the secrets of the pre-params: seal them,
+	// e.g. with the keystore package.
+	PreParamsStore interface {
+		Load() ([][]byte, error)
+		Save(preParams [][]byte) error
+	}
+
+	// PreParamsPool generates pre-params in the background, see Run, and keeps up to its size of them ready, so that a
+	// keygen or a resharing takes them with Get instead of waiting for the safe primes. Each pre-params is handed out
+	// once.
+	PreParamsPool struct {
+		level       tss.SecurityLevel
+		size        int
+		concurrency []int
+		store       PreParamsStore
+		generate    func(ctx context.Context) (*LocalPreParams, error)
+
+		mtx     sync.Mutex
+		ready   []*LocalPreParams
+		encoded [][]byte
+		// closed and replaced whenever pre-params are added or taken
+		changed chan struct{}
+	}
+)
+
+// NewPreParamsPool returns an empty pool of `size` pre-params of security level `level`, which it generates with
+// GeneratePreParamsForLevel and the optional concurrency
+func NewPreParamsPool(level tss.SecurityLevel, size int, optionalConcurrency ...int) *PreParamsPool {
+	if size < 1 {
+		panic(errors.New("NewPreParamsPool: the size must be positive"))
+	}
+	p := &PreParamsPool{
+		level:       level,
+		size:        size,
+		concurrency: optionalConcurrency,
+		changed:     make(chan struct{}),
+	}
+	p.generate = func(ctx context.Context) (*LocalPreParams, error) {
+		return GeneratePreParamsForLevel(ctx, p.level, p.concurrency...)
+	}
+	return p
+}
+
+// SetStore makes the pool keep its pre-params in `s`: Run loads them from it, and the pool saves them on every
+// change. It must be called before Run.
+func (p *PreParamsPool) SetStore(s PreParamsStore) {
+	p.store = s
+}
+
+// Level returns the security level of the pre-params of the pool
+func (p *PreParamsPool) Level() tss.SecurityLevel {
+	return p.level
+}
+
+// Len returns the number of pre-params that are ready
+func (p *PreParamsPool) Len() int {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	return len(p.ready)
+}
+
+// Run loads the pre-params of the store of the pool, if any, and then generates pre-params whenever the pool has fewer
+// than its size, until ctx is done. Pre-params of the store that ValidateForLevel rejects are dropped.
+func (p *PreParamsPool) Run(ctx context.Context) error {
+	if err := p.load(); err != nil {
+		return err
+	}
+	for {
+		p.mtx.Lock()
+		full, changed := p.size <= len(p.ready), p.changed
+		p.mtx.Unlock()
+		if full {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-changed:
+			}
+			continue
+		}
+
+		preParams, err := p.generate(ctx)
+		if err == nil {
+			err = preParams.ValidateForLevel(p.level)
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			common.DefaultLogger().Warnf("pre-params pool: failed generating pre-params: %v", err)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(preParamsRetryDelay):
+			}
+			continue
+		}
+		if err = p.put(preParams); err != nil {
+			// the pre-params are still in the pool, but a restart loses them
+			common.DefaultLogger().Warnf("pre-params pool: %v", err)
+		}
+	}
+}
+
+// Get takes the oldest pre-params of the pool, waiting for the generation of new ones while the pool is empty, until
+// ctx is done. With a store, it saves the pool without the pre-params before it returns them, so that they are not
+// handed out again after a restart.
+func (p *PreParamsPool) Get(ctx context.Context) (*LocalPreParams, error) {
+	for {
+		preParams, ok, err := p.take()
+		if ok || err != nil {
+			return preParams, err
+		}
+		p.mtx.Lock()
+		changed := p.changed
+		empty := len(p.ready) == 0
+		p.mtx.Unlock()
+		if !empty {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("no pre-params are ready: %w", ctx.Err())
+		case <-changed:
+		}
+	}
+}
+
+// TryGet is Get without the wait: it reports false if the pool is empty
+func (p *PreParamsPool) TryGet() (*LocalPreParams, bool, error) {
+	return p.take()
+}
+
+func (p *PreParamsPool) take() (*LocalPreParams, bool, error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if len(p.ready) == 0 {
+		return nil, false, nil
+	}
+	preParams := p.ready[0]
+	if err := p.save(p.encoded[1:]); err != nil {
+		return nil, false, err
+	}
+	p.ready, p.encoded = p.ready[1:], p.encoded[1:]
+	p.notify()
+	return preParams, true, nil
+}
+
+func (p *PreParamsPool) put(preParams *LocalPreParams) error {
+	bz, err := ExportPreParams(preParams)
+	if err != nil {
+		return err
+	}
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.ready, p.encoded = append(p.ready, preParams), append(p.encoded, bz)
+	p.notify()
+	return p.save(p.encoded)
+}
+
+func (p *PreParamsPool) load() error {
+	if p.store == nil {
+		return nil
+	}
+	stored, err := p.store.Load()
+	if err != nil {
+		return fmt.Errorf("failed loading the pre-params pool: %w", err)
+	}
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	for i, bz := range stored {
+		if p.size <= len(p.ready) {
+			break
+		}
+		preParams, err := ImportPreParams(bz, p.level)
+		if err != nil {
+			common.DefaultLogger().Warnf("pre-params pool: dropping the stored pre-params %d: %v", i, err)
+			continue
+		}
+		p.ready, p.encoded = append(p.ready, preParams), append(p.encoded, bz)
+	}
+	p.notify()
+	if len(p.ready) != len(stored) {
+		return p.save(p.encoded)
+	}
+	return nil
+}
+
+// save must be called with the mutex held
+func (p *PreParamsPool) save(encoded [][]byte) error {
+	if p.store == nil {
+		return nil
+	}
+	if err := p.store.Save(encoded); err != nil {
+		return fmt.Errorf("failed saving the pre-params pool: %w", err)
+	}
+	return nil
+}
+
+// notify must be called with the mutex held
+func (p *PreParamsPool) notify() {
+	close(p.changed)
+	p.changed = make(chan struct{})
+}
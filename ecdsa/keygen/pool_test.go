@@ -0,0 +1,114 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+type memPreParamsStore struct {
+	mtx       sync.Mutex
+	preParams [][]byte
+}
+
+func (s *memPreParamsStore) Load() ([][]byte, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return append([][]byte(nil), s.preParams...), nil
+}
+
+func (s *memPreParamsStore) Save(preParams [][]byte) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.preParams = append([][]byte(nil), preParams...)
+	return nil
+}
+
+func TestPreParamsPool(t *testing.T) {
+	keys, _, err := LoadKeygenTestFixtures(3)
+	if !assert.NoError(t, err) {
+		return
+	}
+	store := &memPreParamsStore{}
+	for _, key := range keys[:2] {
+		bz, err := ExportPreParams(&key.LocalPreParams)
+		assert.NoError(t, err)
+		store.preParams = append(store.preParams, bz)
+	}
+	// pre-params that do not validate are dropped on load
+	tampered := keys[2].LocalPreParams
+	tampered.Beta = keys[1].Beta
+	bz, err := ExportPreParams(&tampered)
+	assert.NoError(t, err)
+	store.preParams = append(store.preParams, bz)
+
+	pool := NewPreParamsPool(tss.SecurityLevel112, 2)
+	pool.SetStore(store)
+	generate := make(chan *LocalPreParams)
+	pool.generate = func(ctx context.Context) (*LocalPreParams, error) {
+		select {
+		case preParams := <-generate:
+			return preParams, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	_, ok, err := pool.TryGet()
+	assert.False(t, ok, "nothing is ready before Run")
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- pool.Run(ctx) }()
+
+	// the pool hands out the stored pre-params in order, once each, and forgets them in the store
+	for i := 0; i < 2; i++ {
+		preParams, err := pool.Get(ctx)
+		if assert.NoError(t, err) {
+			assert.Equal(t, 0, keys[i].NTildei.Cmp(preParams.NTildei))
+		}
+	}
+	stored, err := store.Load()
+	assert.NoError(t, err)
+	assert.Empty(t, stored)
+
+	// an empty pool waits for the generation of new pre-params
+	waitCtx, waitCancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	_, err = pool.Get(waitCtx)
+	waitCancel()
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	got := make(chan *LocalPreParams, 1)
+	go func() {
+		preParams, _ := pool.Get(ctx)
+		got <- preParams
+	}()
+	generate <- &keys[2].LocalPreParams
+	assert.Equal(t, 0, keys[2].NTildei.Cmp((<-got).NTildei))
+
+	// the pool refills up to its size, and keeps what it generates in the store
+	generate <- &keys[0].LocalPreParams
+	generate <- &keys[1].LocalPreParams
+	assert.Eventually(t, func() bool { return pool.Len() == 2 }, time.Second, 10*time.Millisecond)
+	stored, err = store.Load()
+	assert.NoError(t, err)
+	assert.Len(t, stored, 2)
+	select {
+	case generate <- &keys[2].LocalPreParams:
+		t.Error("a full pool generated more pre-params")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
@@ -0,0 +1,114 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+// PreParamsVersion is the version of the format of ExportPreParams
+const PreParamsVersion = 1
+
+// primalityRounds is the number of Miller-Rabin rounds of the primality checks of ValidateForLevel, as in
+// GeneratePreParamsForLevel
+const primalityRounds = 30
+
+type exportedPreParams struct {
+	Version   uint32
+	PreParams LocalPreParams
+}
+
+var one = big.NewInt(1)
+
+// ExportPreParams encodes pre-params, e.g. generated with GeneratePreParamsForLevel on a machine faster than that of
+// the party, for ImportPreParams. The encoding holds the Paillier key and the factors of NTildei in the clear: seal it,
+// e.g. with the keystore package, before it leaves the machine.
+func ExportPreParams(preParams *LocalPreParams) ([]byte, error) {
+	if preParams == nil || !preParams.ValidateWithProof() {
+		return nil, errors.New("the pre-params are incomplete")
+	}
+	return json.Marshal(exportedPreParams{Version: PreParamsVersion, PreParams: *preParams})
+}
+
+// ImportPreParams decodes pre-params encoded by ExportPreParams and checks them with ValidateForLevel, so that a
+// keygen of `level` can take them
+func ImportPreParams(data []byte, level tss.SecurityLevel) (*LocalPreParams, error) {
+	var exported exportedPreParams
+	if err := json.Unmarshal(data, &exported); err != nil {
+		return nil, fmt.Errorf("invalid pre-params: %w", err)
+	}
+	if exported.Version != PreParamsVersion {
+		return nil, fmt.Errorf("unsupported pre-params version %d", exported.Version)
+	}
+	if err := exported.PreParams.ValidateForLevel(level); err != nil {
+		return nil, err
+	}
+	return &exported.PreParams, nil
+}
+
+// ValidateForLevel checks pre-params that the party did not generate itself before a keygen, a refresh or a resharing
+// takes them: the Paillier key is made of two distinct primes of the modulus size of `level`, NTildei is the product
+// of the two distinct safe primes 2P+1 and 2Q+1 of that size, and H1i, H2i generate the same subgroup of order PQ of
+// the quadratic residues mod NTildei, with H2i = H1i^Alpha and H1i = H2i^Beta.
+func (preParams LocalPreParams) ValidateForLevel(level tss.SecurityLevel) error {
+	if !preParams.ValidateWithProof() {
+		return errors.New("the pre-params are incomplete")
+	}
+	if level.ModulusBits() == 0 {
+		return fmt.Errorf("unknown security level %d", level)
+	}
+	if !preParams.ValidateLevel(level) {
+		return fmt.Errorf("the pre-params do not have the %d-bit moduli of security level %d", level.ModulusBits(), level)
+	}
+
+	sk := preParams.PaillierSK
+	if sk.P.Cmp(sk.Q) == 0 || !sk.P.ProbablyPrime(primalityRounds) || !sk.Q.ProbablyPrime(primalityRounds) ||
+		new(big.Int).Mul(sk.P, sk.Q).Cmp(sk.N) != 0 {
+		return errors.New("the Paillier modulus is not the product of two distinct primes")
+	}
+	pMinus1, qMinus1 := new(big.Int).Sub(sk.P, one), new(big.Int).Sub(sk.Q, one)
+	phiN := new(big.Int).Mul(pMinus1, qMinus1)
+	lambdaN := new(big.Int).Div(phiN, new(big.Int).GCD(nil, nil, pMinus1, qMinus1))
+	if sk.PhiN == nil || sk.PhiN.Cmp(phiN) != 0 || sk.LambdaN == nil || sk.LambdaN.Cmp(lambdaN) != 0 {
+		return errors.New("the Paillier key does not match its primes")
+	}
+
+	P, Q := preParams.P, preParams.Q
+	safeP, safeQ := new(big.Int).Lsh(P, 1), new(big.Int).Lsh(Q, 1)
+	safeP.Add(safeP, one)
+	safeQ.Add(safeQ, one)
+	if P.Cmp(Q) == 0 || !P.ProbablyPrime(primalityRounds) || !Q.ProbablyPrime(primalityRounds) ||
+		!safeP.ProbablyPrime(primalityRounds) || !safeQ.ProbablyPrime(primalityRounds) ||
+		new(big.Int).Mul(safeP, safeQ).Cmp(preParams.NTildei) != 0 {
+		return errors.New("NTildei is not the product of two distinct safe primes")
+	}
+
+	NTilde, h1, h2 := preParams.NTildei, preParams.H1i, preParams.H2i
+	if h1.Cmp(h2) == 0 || !common.IsNumberInMultiplicativeGroup(NTilde, h1) || !common.IsNumberInMultiplicativeGroup(NTilde, h2) {
+		return errors.New("h1, h2 are not distinct members of the multiplicative group of NTilde")
+	}
+	// a quadratic residue other than 1 mod each safe prime has the order P, resp. Q, so h1 generates the subgroup of
+	// order PQ
+	for _, safe := range []*big.Int{safeP, safeQ} {
+		residue := new(big.Int).Mod(h1, safe)
+		if big.Jacobi(residue, safe) != 1 || residue.Cmp(one) == 0 {
+			return errors.New("h1 does not generate the quadratic residues of NTilde")
+		}
+	}
+	modNTilde, modPQ := common.ModInt(NTilde), common.ModInt(new(big.Int).Mul(P, Q))
+	if modNTilde.Exp(h1, preParams.Alpha).Cmp(h2) != 0 || modNTilde.Exp(h2, preParams.Beta).Cmp(h1) != 0 ||
+		modPQ.Mul(preParams.Alpha, preParams.Beta).Cmp(one) != 0 {
+		return errors.New("h2 is not h1^alpha with the inverse beta of alpha")
+	}
+	return nil
+}
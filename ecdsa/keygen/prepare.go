@@ -9,23 +9,26 @@ package keygen
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math/big"
 	"runtime"
 	"time"
 
 	"github.com/kisdex/mpc-lib/common"
 	"github.com/kisdex/mpc-lib/crypto/paillier"
+	"github.com/kisdex/mpc-lib/tss"
 )
 
 const (
-	// Using a modulus length of 2048 is recommended in the GG18 spec
-	paillierModulusLen = 2048
-	// Two 1024-bit safe primes to produce NTilde
-	safePrimeBitLen = 1024
 	// Ticker for printing log statements while generating primes/modulus
 	logProgressTickInterval = 8 * time.Second
 	// Safe big len using random for ssid
 	SafeBitLen = 1024
+
+	// PaillierPurpose and NTildePurpose are the purposes of the searches of safe primes of GeneratePreParams, in the
+	// progress that they report, see common.WithSafePrimeProgress
+	PaillierPurpose = "paillier"
+	NTildePurpose   = "ntilde"
 )
 
 // GeneratePreParams finds two safe primes and computes the Paillier secret required for the protocol.
@@ -38,11 +41,30 @@ func GeneratePreParams(timeout time.Duration, optionalConcurrency ...int) (*Loca
 	return GeneratePreParamsWithContext(ctx, optionalConcurrency...)
 }
 
+// GeneratePreParamsFor generates the pre-params of a party that were not given to it, with the timeout, concurrency
+// and security level of its Parameters
+func GeneratePreParamsFor(params *tss.Parameters) (*LocalPreParams, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), params.SafePrimeGenTimeout())
+	defer cancel()
+	return GeneratePreParamsForLevel(ctx, params.SecurityLevel(), params.Concurrency())
+}
+
 // GeneratePreParams finds two safe primes and computes the Paillier secret required for the protocol.
 // This can be a time consuming process so it is recommended to do it out-of-band.
 // If not specified, a concurrency value equal to the number of available CPU cores will be used.
 // If pre-parameters could not be generated before the context is done, an error is returned.
+// The progress of the generation is reported to the callback of common.WithSafePrimeProgress on `ctx`, if any.
 func GeneratePreParamsWithContext(ctx context.Context, optionalConcurrency ...int) (*LocalPreParams, error) {
+	return GeneratePreParamsForLevel(ctx, tss.SecurityLevel112, optionalConcurrency...)
+}
+
+// GeneratePreParamsForLevel is GeneratePreParamsWithContext with a Paillier modulus and NTilde of the size of `level`,
+// for the parties whose Parameters have that security level.
+func GeneratePreParamsForLevel(ctx context.Context, level tss.SecurityLevel, optionalConcurrency ...int) (*LocalPreParams, error) {
+	modulusBitLen := level.ModulusBits()
+	if modulusBitLen == 0 {
+		return nil, fmt.Errorf("unknown security level %d", level)
+	}
 	var concurrency int
 	if 0 < len(optionalConcurrency) {
 		if 1 < len(optionalConcurrency) {
@@ -62,29 +84,29 @@ func GeneratePreParamsWithContext(ctx context.Context, optionalConcurrency ...in
 
 	// 4. generate Paillier public key E_i, private key and proof
 	go func(ch chan<- *paillier.PrivateKey) {
-		common.Logger.Info("generating the Paillier modulus, please wait...")
+		common.DefaultLogger().Infof("generating the Paillier modulus, please wait...")
 		start := time.Now()
 		// more concurrency weight is assigned here because the paillier primes have a requirement of having "large" P-Q
-		PiPaillierSk, _, err := paillier.GenerateKeyPair(ctx, paillierModulusLen, concurrency*2)
+		PiPaillierSk, _, err := paillier.GenerateKeyPair(common.WithSafePrimePurpose(ctx, PaillierPurpose), modulusBitLen, concurrency*2)
 		if err != nil {
 			ch <- nil
 			return
 		}
-		common.Logger.Infof("paillier modulus generated. took %s\n", time.Since(start))
+		common.DefaultLogger().Infof("paillier modulus generated. took %s\n", time.Since(start))
 		ch <- PiPaillierSk
 	}(paiCh)
 
 	// 5-7. generate safe primes for ZKPs used later on
 	go func(ch chan<- []*common.GermainSafePrime) {
 		var err error
-		common.Logger.Info("generating the safe primes for the signing proofs, please wait...")
+		common.DefaultLogger().Infof("generating the safe primes for the signing proofs, please wait...")
 		start := time.Now()
-		sgps, err := common.GetRandomSafePrimesConcurrent(ctx, safePrimeBitLen, 2, concurrency)
+		sgps, err := common.GetRandomSafePrimesConcurrent(common.WithSafePrimePurpose(ctx, NTildePurpose), modulusBitLen/2, 2, concurrency)
 		if err != nil {
 			ch <- nil
 			return
 		}
-		common.Logger.Infof("safe primes generated. took %s\n", time.Since(start))
+		common.DefaultLogger().Infof("safe primes generated. took %s\n", time.Since(start))
 		ch <- sgps
 	}(sgpCh)
 
@@ -98,7 +120,7 @@ consumer:
 	for {
 		select {
 		case <-logProgressTicker.C:
-			common.Logger.Info("still generating primes...")
+			common.DefaultLogger().Infof("still generating primes...")
 		case sgps = <-sgpCh:
 			if sgps == nil ||
 				sgps[0] == nil || sgps[1] == nil ||
@@ -9,10 +9,15 @@ package keygen
 import (
 	"context"
 	"fmt"
+	"math/big"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/tss"
 )
 
 func TestGeneratePreParamsTimeout(t *testing.T) {
@@ -40,8 +45,16 @@ func TestGeneratePreParamsWithContextTimeout(t *testing.T) {
 func TestGenerateWithContext(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Minute)
 	defer cancel()
+	var mtx sync.Mutex
+	found := make(map[string]int)
+	ctx = common.WithSafePrimeProgress(ctx, func(p common.SafePrimeProgress) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		found[p.Purpose] = p.Found
+	})
 
 	preParams, err := GeneratePreParamsWithContext(ctx, 1)
+	assert.Equal(t, map[string]int{PaillierPurpose: 2, NTildePurpose: 2}, found, "both searches report their safe primes")
 	assert.NotNil(t, preParams)
 	assert.Nil(t, err)
 	assert.NotNil(t, preParams.PaillierSK)
@@ -53,3 +66,42 @@ func TestGenerateWithContext(t *testing.T) {
 	assert.NotNil(t, preParams.P)
 	assert.NotNil(t, preParams.Q)
 }
+
+func TestPreParamsExportImport(t *testing.T) {
+	keys, _, err := LoadKeygenTestFixtures(1)
+	if !assert.NoError(t, err) {
+		return
+	}
+	preParams := keys[0].LocalPreParams
+	bz, err := ExportPreParams(&preParams)
+	if !assert.NoError(t, err) {
+		return
+	}
+	imported, err := ImportPreParams(bz, tss.SecurityLevel112)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 0, imported.NTildei.Cmp(preParams.NTildei))
+		assert.Equal(t, 0, imported.PaillierSK.N.Cmp(preParams.PaillierSK.N))
+	}
+	_, err = ImportPreParams(bz, tss.SecurityLevel128)
+	assert.Error(t, err, "the moduli of another security level")
+
+	// each of the pre-params differs from the valid ones in one value
+	tampered := map[string]func(pp *LocalPreParams){
+		"h2 is not h1^alpha": func(pp *LocalPreParams) { pp.H2i = new(big.Int).Mul(pp.H2i, big.NewInt(4)) },
+		"h1 is not a quadratic residue": func(pp *LocalPreParams) {
+			pp.H1i = new(big.Int).Sub(pp.NTildei, pp.H1i)
+		},
+		"beta is not the inverse of alpha": func(pp *LocalPreParams) { pp.Beta = new(big.Int).Add(pp.Beta, big.NewInt(1)) },
+		"the Germain primes are not prime": func(pp *LocalPreParams) { pp.P = new(big.Int).Add(pp.P, big.NewInt(2)) },
+		"the Paillier primes do not make N": func(pp *LocalPreParams) {
+			sk := *pp.PaillierSK
+			sk.P = new(big.Int).Add(sk.P, big.NewInt(2))
+			pp.PaillierSK = &sk
+		},
+	}
+	for name, tamper := range tampered {
+		pp := preParams
+		tamper(&pp)
+		assert.Error(t, pp.ValidateForLevel(tss.SecurityLevel112), name)
+	}
+}
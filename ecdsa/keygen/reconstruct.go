@@ -0,0 +1,74 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto"
+	"github.com/kisdex/mpc-lib/crypto/vss"
+)
+
+// ErrReconstructionMismatch is returned when the shares do not interpolate to the private key of ECDSAPub, e.g. when
+// fewer than threshold+1 shares were given
+var ErrReconstructionMismatch = errors.New("the shares do not reconstruct the private key of ECDSAPub")
+
+// ReconstructPrivateKey combines the save data of at least threshold+1 parties of a key on `ec` into its full private
+// key, for disaster recovery when the parties can no longer sign together.
+//
+// WARNING: this defeats threshold custody. Whoever runs it holds the whole key, which must be treated as compromised
+// from then on: move the funds to a new key instead of resuming threshold signing with it. For recovery drills, use
+// VerifyReconstruction, which never returns the key.
+func ReconstructPrivateKey(ec elliptic.Curve, keys []LocalPartySaveData) (*big.Int, error) {
+	common.DefaultLogger().Warnf("reconstructing the full private key from %d shares: the key leaves threshold custody", len(keys))
+	return reconstructPrivateKey(ec, keys)
+}
+
+// VerifyReconstruction checks that the save data of at least threshold+1 parties of a key on `ec` reconstruct the
+// private key of their ECDSAPub, without returning it; the key only exists in memory during the call
+func VerifyReconstruction(ec elliptic.Curve, keys []LocalPartySaveData) error {
+	sk, err := reconstructPrivateKey(ec, keys)
+	if err != nil {
+		return err
+	}
+	sk.SetInt64(0)
+	return nil
+}
+
+func reconstructPrivateKey(ec elliptic.Curve, keys []LocalPartySaveData) (*big.Int, error) {
+	if len(keys) < 2 {
+		return nil, errors.New("at least 2 shares are needed to reconstruct a key")
+	}
+	shares := make(vss.Shares, len(keys))
+	ids := make([]*big.Int, len(keys))
+	for i, key := range keys {
+		if err := key.Validate(ec); err != nil {
+			return nil, fmt.Errorf("the save data of share %d: %w", i, err)
+		}
+		if !key.ECDSAPub.Equals(keys[0].ECDSAPub) {
+			return nil, fmt.Errorf("share %d belongs to another key", i)
+		}
+		shares[i] = &vss.Share{Threshold: len(keys) - 1, ID: key.ShareID, Share: key.Xi}
+		ids[i] = key.ShareID
+	}
+	if _, err := vss.CheckIndexes(ec, ids); err != nil {
+		return nil, fmt.Errorf("the shares must be of distinct parties: %w", err)
+	}
+	sk, err := shares.ReConstruct(ec)
+	if err != nil {
+		return nil, err
+	}
+	if !crypto.ScalarBaseMult(ec, sk).Equals(keys[0].ECDSAPub) {
+		sk.SetInt64(0)
+		return nil, ErrReconstructionMismatch
+	}
+	return sk, nil
+}
@@ -0,0 +1,52 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/crypto"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+func TestReconstructPrivateKey(t *testing.T) {
+	keys, _, err := LoadKeygenTestFixtures(testParticipants)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	ec := tss.S256()
+
+	sk, err := ReconstructPrivateKey(ec, keys[len(keys)-testThreshold-1:])
+	if assert.NoError(t, err) {
+		assert.True(t, crypto.ScalarBaseMult(ec, sk).Equals(keys[0].ECDSAPub))
+	}
+	all, err := ReconstructPrivateKey(ec, keys)
+	if assert.NoError(t, err, "more than threshold+1 shares should reconstruct the key too") {
+		assert.Equal(t, 0, sk.Cmp(all))
+	}
+	assert.NoError(t, VerifyReconstruction(ec, keys[:testThreshold+1]))
+
+	err = VerifyReconstruction(ec, []LocalPartySaveData{keys[0], keys[0], keys[1]})
+	assert.Error(t, err, "a share must not be counted twice")
+	_, err = ReconstructPrivateKey(ec, keys[:1])
+	assert.Error(t, err)
+
+	// a key of a higher threshold, where 2 shares are too few
+	preParams := make([]LocalPreParams, len(keys))
+	for i, key := range keys {
+		preParams[i] = key.LocalPreParams
+	}
+	highKeys, err := ImportKeyWithPreParams(ec, sk, tss.GenerateTestPartyIDs(len(keys)), len(keys)-1, preParams)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, VerifyReconstruction(ec, highKeys))
+	err = VerifyReconstruction(ec, highKeys[:len(keys)-1])
+	assert.ErrorIs(t, err, ErrReconstructionMismatch, "threshold shares should not reconstruct the key")
+}
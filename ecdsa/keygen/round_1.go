@@ -57,7 +57,7 @@ func (round *round1) Start() *tss.Error {
 	_ = ui    // silences a linter warning
 
 	// make commitment -> (C, D)
-	pGFlat, err := crypto.FlattenECPoints(vs)
+	pGFlat, err := crypto.CompressECPoints(vs)
 	if err != nil {
 		return round.WrapError(err, Pi)
 	}
@@ -73,8 +73,11 @@ func (round *round1) Start() *tss.Error {
 			errors.New("`optionalPreParams` failed to validate; it might have been generated with an older version of tss-lib"))
 	} else if round.save.LocalPreParams.ValidateWithProof() {
 		preParams = &round.save.LocalPreParams
+		if err := preParams.ValidateForLevel(round.SecurityLevel()); err != nil {
+			return round.WrapError(err, Pi)
+		}
 	} else {
-		preParams, err = GeneratePreParams(round.SafePrimeGenTimeout(), round.Concurrency())
+		preParams, err = GeneratePreParamsFor(round.Parameters)
 		if err != nil {
 			return round.WrapError(errors.New("pre-params generation failed"), Pi)
 		}
@@ -109,6 +112,7 @@ func (round *round1) Start() *tss.Error {
 	}
 	round.temp.ssid = ssid
 	round.temp.shares = shares
+	prmProof := preParams.PrmProof(common.AppendBigIntToBytesSlice(ssid, big.NewInt(int64(i))))
 
 	// for this P: SAVE de-commitments, paillier keys for round 2
 	round.save.PaillierSK = preParams.PaillierSK
@@ -118,12 +122,13 @@ func (round *round1) Start() *tss.Error {
 	// BROADCAST commitments, paillier pk + proof; round 1 message
 	{
 		msg, err := NewKGRound1Message(
-			round.PartyID(), cmt.C, &preParams.PaillierSK.PublicKey, preParams.NTildei, preParams.H1i, preParams.H2i, dlnProof1, dlnProof2)
+			round.PartyID(), cmt.C, &preParams.PaillierSK.PublicKey, preParams.NTildei, preParams.H1i, preParams.H2i, dlnProof1, dlnProof2,
+			prmProof)
 		if err != nil {
 			return round.WrapError(err, Pi)
 		}
 		round.temp.kgRound1Messages[i] = msg
-		round.out <- msg
+		round.SendMessage(round.out, msg)
 	}
 	return nil
 }
@@ -12,17 +12,13 @@ import (
 	"math/big"
 	"sync"
 
+	"github.com/kisdex/mpc-lib/common"
 	"github.com/kisdex/mpc-lib/crypto/facproof"
 	"github.com/kisdex/mpc-lib/crypto/modproof"
 
-	"github.com/kisdex/mpc-lib/common"
 	"github.com/kisdex/mpc-lib/tss"
 )
 
-const (
-	paillierBitsLen = 2048
-)
-
 func (round *round2) Start() *tss.Error {
 	if round.started {
 		return round.WrapError(errors.New("round already started"))
@@ -31,12 +27,13 @@ func (round *round2) Start() *tss.Error {
 	round.started = true
 	round.resetOK()
 
-	common.Logger.Debugf(
+	round.Logger().Debugf(
 		"%s Setting up DLN verification with concurrency level of %d",
 		round.PartyID(),
 		round.Concurrency(),
 	)
 	dlnVerifier := NewDlnProofVerifier(round.Concurrency())
+	dlnVerifier.SetMetrics(round.Metrics())
 
 	i := round.PartyID().Index
 
@@ -44,6 +41,7 @@ func (round *round2) Start() *tss.Error {
 	h1H2Map := make(map[string]struct{}, len(round.temp.kgRound1Messages)*2)
 	dlnProof1FailCulprits := make([]*tss.PartyID, len(round.temp.kgRound1Messages))
 	dlnProof2FailCulprits := make([]*tss.PartyID, len(round.temp.kgRound1Messages))
+	prmProofFailCulprits := make([]*tss.PartyID, len(round.temp.kgRound1Messages))
 	wg := new(sync.WaitGroup)
 	for j, msg := range round.temp.kgRound1Messages {
 		r1msg := msg.Content().(*KGRound1Message)
@@ -52,13 +50,13 @@ func (round *round2) Start() *tss.Error {
 			r1msg.UnmarshalH2(),
 			r1msg.UnmarshalNTilde(),
 			r1msg.UnmarshalPaillierPK()
-		if paillierPKj.N.BitLen() != paillierBitsLen {
+		if !round.SecurityLevel().ValidModulus(paillierPKj.N) {
 			return round.WrapError(errors.New("got paillier modulus with insufficient bits for this party"), msg.GetFrom())
 		}
 		if H1j.Cmp(H2j) == 0 {
 			return round.WrapError(errors.New("h1j and h2j were equal for this party"), msg.GetFrom())
 		}
-		if NTildej.BitLen() != paillierBitsLen {
+		if !round.SecurityLevel().ValidModulus(NTildej) {
 			return round.WrapError(errors.New("got NTildej with insufficient bits for this party"), msg.GetFrom())
 		}
 		h1JHex, h2JHex := hex.EncodeToString(H1j.Bytes()), hex.EncodeToString(H2j.Bytes())
@@ -70,7 +68,7 @@ func (round *round2) Start() *tss.Error {
 		}
 		h1H2Map[h1JHex], h1H2Map[h2JHex] = struct{}{}, struct{}{}
 
-		wg.Add(2)
+		wg.Add(3)
 		_j := j
 		_msg := msg
 
@@ -86,11 +84,23 @@ func (round *round2) Start() *tss.Error {
 			}
 			wg.Done()
 		})
+		ContextJ := common.AppendBigIntToBytesSlice(round.temp.ssid, big.NewInt(int64(j)))
+		dlnVerifier.VerifyPrmProof(r1msg, ContextJ, H1j, H2j, NTildej, func(isValid bool) {
+			if !isValid {
+				prmProofFailCulprits[_j] = _msg.GetFrom()
+			}
+			wg.Done()
+		})
 	}
 	wg.Wait()
 	for _, culprit := range append(dlnProof1FailCulprits, dlnProof2FailCulprits...) {
 		if culprit != nil {
-			return round.WrapError(errors.New("dln proof verification failed"), culprit)
+			return round.WrapError(errors.New("dln proof verification failed"), culprit).WithCode(tss.ErrProofVerifyFailed)
+		}
+	}
+	for _, culprit := range prmProofFailCulprits {
+		if culprit != nil {
+			return round.WrapError(errors.New("prm proof verification failed"), culprit).WithCode(tss.ErrProofVerifyFailed)
 		}
 	}
 	// save NTilde_j, h1_j, h2_j, ...
@@ -133,10 +143,10 @@ func (round *round2) Start() *tss.Error {
 			round.temp.kgRound2Message1s[j] = r2msg1
 			continue
 		}
-		round.out <- r2msg1
+		round.SendMessage(round.out, r2msg1)
 	}
 
-	// 7. BROADCAST de-commitments of Shamir poly*G
+	// 7. BROADCAST de-commitments of Shamir poly*G, echoing the round 1 broadcasts
 	modProof := &modproof.ProofMod{W: zero, X: *new([80]*big.Int), A: zero, B: zero, Z: *new([80]*big.Int)}
 	if !round.Parameters.NoProofMod() {
 		var err error
@@ -146,9 +156,10 @@ func (round *round2) Start() *tss.Error {
 			return round.WrapError(err, round.PartyID())
 		}
 	}
-	r2msg2 := NewKGRound2Message2(round.PartyID(), round.temp.deCommitPolyG, modProof)
+	r2msg2 := NewKGRound2Message2(round.PartyID(), round.temp.deCommitPolyG, modProof,
+		tss.EchoDigests(round.temp.kgRound1Messages))
 	round.temp.kgRound2Message2s[i] = r2msg2
-	round.out <- r2msg2
+	round.SendMessage(round.out, r2msg2)
 
 	return nil
 }
@@ -15,7 +15,6 @@ import (
 
 	"github.com/kisdex/mpc-lib/common"
 	"github.com/kisdex/mpc-lib/crypto"
-	"github.com/kisdex/mpc-lib/crypto/commitments"
 	"github.com/kisdex/mpc-lib/crypto/vss"
 	"github.com/kisdex/mpc-lib/tss"
 )
@@ -31,6 +30,11 @@ func (round *round3) Start() *tss.Error {
 	Ps := round.Parties().IDs()
 	PIdx := round.PartyID().Index
 
+	// check that every party received the same round 1 broadcasts
+	if culprits := tss.VerifyEchoes(tss.EchoDigests(round.temp.kgRound1Messages), round.temp.kgRound2Message2s, Ps); culprits != nil {
+		return round.WrapError(errors.New("round 1 broadcast equivocation detected"), culprits...).WithCode(tss.ErrDuplicateMessage)
+	}
+
 	// 1,9. calculate xi
 	xi := new(big.Int).Set(round.temp.shares[PIdx].Share)
 	for j := range Ps {
@@ -52,14 +56,16 @@ func (round *round3) Start() *tss.Error {
 	// 4-11.
 	type vssOut struct {
 		unWrappedErr error
+		reason       AbortReason
 		pjVs         vss.Vs
 	}
 	chs := make([]chan vssOut, len(Ps))
+	pool := round.NewWorkerPool()
 	for i := range chs {
 		if i == PIdx {
 			continue
 		}
-		chs[i] = make(chan vssOut)
+		chs[i] = make(chan vssOut, 1)
 	}
 	for j := range Ps {
 		if j == PIdx {
@@ -67,34 +73,32 @@ func (round *round3) Start() *tss.Error {
 		}
 		ContextJ := common.AppendBigIntToBytesSlice(round.temp.ssid, big.NewInt(int64(j)))
 		// 6-8.
-		go func(j int, ch chan<- vssOut) {
+		ch := chs[j]
+		pool.GoFor(Ps[j], func() {
 			// 4-9.
 			KGCj := round.temp.KGCs[j]
 			r2msg2 := round.temp.kgRound2Message2s[j].Content().(*KGRound2Message2)
 			KGDj := r2msg2.UnmarshalDeCommitment()
-			cmtDeCmt := commitments.HashCommitDecommit{C: KGCj, D: KGDj}
-			ok, flatPolyGs := cmtDeCmt.DeCommit()
-			if !ok || flatPolyGs == nil {
-				ch <- vssOut{errors.New("de-commitment verify failed"), nil}
-				return
-			}
-			PjVs, err := crypto.UnFlattenECPoints(round.Params().EC(), flatPolyGs)
+			PjVs, err := deCommitPolyG(round.Params().EC(), round.Threshold(), KGCj, KGDj)
 			if err != nil {
-				ch <- vssOut{err, nil}
+				ch <- vssOut{err, AbortBadDeCommitment, nil}
 				return
 			}
+			var ok bool
 			modProof, err := r2msg2.UnmarshalModProof()
 			if err != nil && round.Parameters.NoProofMod() {
 				// For old parties, the modProof could be not exist
 				// Not return error for compatibility reason
-				common.Logger.Warningf("modProof not exist:%s", Ps[j])
+				round.Logger().Warnf("modProof not exist:%s", Ps[j])
 			} else {
 				if err != nil {
-					ch <- vssOut{errors.New("modProof verify failed"), nil}
+					ch <- vssOut{errors.New("modProof verify failed"), AbortBadModProof, nil}
 					return
 				}
-				if ok = modProof.Verify(ContextJ, round.save.PaillierPKs[j].N); !ok {
-					ch <- vssOut{errors.New("modProof verify failed"), nil}
+				if ok = round.VerifyProof("mod", func() bool {
+					return modProof.Verify(ContextJ, round.save.PaillierPKs[j].N)
+				}); !ok {
+					ch <- vssOut{errors.New("modProof verify failed"), AbortBadModProof, nil}
 					return
 				}
 			}
@@ -104,44 +108,50 @@ func (round *round3) Start() *tss.Error {
 				ID:        round.PartyID().KeyInt(),
 				Share:     r2msg1.UnmarshalShare(),
 			}
-			if ok = PjShare.Verify(round.Params().EC(), round.Threshold(), PjVs); !ok {
-				ch <- vssOut{errors.New("vss verify failed"), nil}
+			if ok = round.VerifyProof("vss", func() bool {
+				return PjShare.Verify(round.Params().EC(), round.Threshold(), PjVs)
+			}); !ok {
+				ch <- vssOut{errors.New("vss verify failed"), AbortBadVSSShare, nil}
 				return
 			}
 			facProof, err := r2msg1.UnmarshalFacProof()
 			if err != nil && round.NoProofFac() {
 				// For old parties, the facProof could be not exist
 				// Not return error for compatibility reason
-				common.Logger.Warningf("facProof not exist:%s", Ps[j])
+				round.Logger().Warnf("facProof not exist:%s", Ps[j])
 			} else {
 				if err != nil {
-					ch <- vssOut{errors.New("facProof verify failed"), nil}
+					ch <- vssOut{errors.New("facProof verify failed"), AbortBadFacProof, nil}
 					return
 				}
-				if ok = facProof.Verify(ContextJ, round.EC(), round.save.PaillierPKs[j].N, round.save.NTildei,
-					round.save.H1i, round.save.H2i); !ok {
-					ch <- vssOut{errors.New("facProof verify failed"), nil}
+				if ok = round.VerifyProof("fac", func() bool {
+					return facProof.Verify(ContextJ, round.EC(), round.save.PaillierPKs[j].N, round.save.NTildei,
+						round.save.H1i, round.save.H2i)
+				}); !ok {
+					ch <- vssOut{errors.New("facProof verify failed"), AbortBadFacProof, nil}
 					return
 				}
 			}
 
 			// (9) handled above
-			ch <- vssOut{nil, PjVs}
-		}(j, chs[j])
+			ch <- vssOut{nil, "", PjVs}
+		})
 	}
 
-	// consume unbuffered channels (end the goroutines)
+	// consume the channels (end the goroutines)
 	vssResults := make([]vssOut, len(Ps))
 	{
 		culprits := make([]*tss.PartyID, 0, len(Ps)) // who caused the error(s)
+		var evidence []tss.Evidence
 		for j, Pj := range Ps {
 			if j == PIdx {
 				continue
 			}
 			vssResults[j] = <-chs[j]
-			// collect culprits to error out with
+			// collect culprits to error out with, and the evidence against them
 			if err := vssResults[j].unWrappedErr; err != nil {
 				culprits = append(culprits, Pj)
+				evidence = append(evidence, round.abortEvidence(vssResults[j].reason, j))
 			}
 		}
 		var multiErr error
@@ -152,7 +162,7 @@ func (round *round3) Start() *tss.Error {
 				}
 				multiErr = multierror.Append(multiErr, vssResult.unWrappedErr)
 			}
-			return round.WrapError(multiErr, culprits...)
+			return round.WrapError(multiErr, culprits...).WithEvidence(evidence...)
 		}
 	}
 	{
@@ -210,14 +220,14 @@ func (round *round3) Start() *tss.Error {
 	round.save.ECDSAPub = ecdsaPubKey
 
 	// PRINT public key & private share
-	common.Logger.Debugf("%s public key: %x", round.PartyID(), ecdsaPubKey)
+	round.Logger().Debugf("%s public key: %x", round.PartyID(), ecdsaPubKey)
 
-	// BROADCAST paillier proof for Pi
+	// BROADCAST paillier proof for Pi, echoing the round 2 broadcasts
 	ki := round.PartyID().KeyInt()
 	proof := round.save.PaillierSK.Proof(ki, ecdsaPubKey)
-	r3msg := NewKGRound3Message(round.PartyID(), proof)
+	r3msg := NewKGRound3Message(round.PartyID(), proof, tss.EchoDigests(round.temp.kgRound2Message2s))
 	round.temp.kgRound3Messages[PIdx] = r3msg
-	round.out <- r3msg
+	round.SendMessage(round.out, r3msg)
 	return nil
 }
 
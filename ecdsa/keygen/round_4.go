@@ -9,8 +9,6 @@ package keygen
 import (
 	"errors"
 
-	"github.com/kisdex/mpc-lib/common"
-	"github.com/kisdex/mpc-lib/crypto/paillier"
 	"github.com/kisdex/mpc-lib/tss"
 )
 
@@ -27,31 +25,42 @@ func (round *round4) Start() *tss.Error {
 	PIDs := Ps.Keys()
 	ecdsaPub := round.save.ECDSAPub
 
+	// check that every party received the same round 2 broadcasts
+	if culprits := tss.VerifyEchoes(tss.EchoDigests(round.temp.kgRound2Message2s), round.temp.kgRound3Messages, Ps); culprits != nil {
+		return round.WrapError(errors.New("round 2 broadcast equivocation detected"), culprits...).WithCode(tss.ErrDuplicateMessage)
+	}
+
 	// 1-3. (concurrent)
 	// r3 messages are assumed to be available and != nil in this function
 	r3msgs := round.temp.kgRound3Messages
 	chs := make([]chan bool, len(r3msgs))
+	pool := round.NewWorkerPool()
 	for i := range chs {
-		chs[i] = make(chan bool)
+		chs[i] = make(chan bool, 1)
 	}
 	for j, msg := range round.temp.kgRound3Messages {
 		if j == i {
 			continue
 		}
 		r3msg := msg.Content().(*KGRound3Message)
-		go func(prf paillier.Proof, j int, ch chan<- bool) {
+		prf, ch := r3msg.UnmarshalProofInts(), chs[j]
+		pool.GoFor(msg.GetFrom(), func() {
 			ppk := round.save.PaillierPKs[j]
-			ok, err := prf.Verify(ppk.N, PIDs[j], ecdsaPub)
+			var err error
+			ok := round.VerifyProof("paillier", func() (ok bool) {
+				ok, err = prf.Verify(ppk.N, PIDs[j], ecdsaPub)
+				return ok && err == nil
+			})
 			if err != nil {
-				common.Logger.Error(round.WrapError(err, Ps[j]).Error())
+				round.Logger().Errorf("%v", round.WrapError(err, Ps[j]))
 				ch <- false
 				return
 			}
 			ch <- ok
-		}(r3msg.UnmarshalProofInts(), j, chs[j])
+		})
 	}
 
-	// consume unbuffered channels (end the goroutines)
+	// consume the channels (end the goroutines)
 	for j, ch := range chs {
 		if j == i {
 			round.ok[j] = true
@@ -60,17 +69,20 @@ func (round *round4) Start() *tss.Error {
 		round.ok[j] = <-ch
 	}
 	culprits := make([]*tss.PartyID, 0, len(Ps)) // who caused the error(s)
+	var evidence []tss.Evidence
 	for j, ok := range round.ok {
 		if !ok {
 			culprits = append(culprits, Ps[j])
-			common.Logger.Warningf("paillier verify failed for party %s", Ps[j])
+			evidence = append(evidence, round.abortEvidence(AbortBadPaillierProof, j))
+			round.Logger().Warnf("paillier verify failed for party %s", Ps[j])
 			continue
 		}
-		common.Logger.Debugf("paillier verify passed for party %s", Ps[j])
+		round.Logger().Debugf("paillier verify passed for party %s", Ps[j])
 
 	}
 	if len(culprits) > 0 {
-		return round.WrapError(errors.New("paillier verify failed"), culprits...)
+		return round.WrapError(errors.New("paillier verify failed"), culprits...).WithCode(tss.ErrProofVerifyFailed).
+			WithEvidence(evidence...)
 	}
 
 	round.end <- round.save
@@ -100,11 +100,16 @@ func (round *base) resetOK() {
 
 // get ssid from local params
 func (round *base) getSSID() ([]byte, error) {
-	ssidList := []*big.Int{round.EC().Params().P, round.EC().Params().N, round.EC().Params().Gx, round.EC().Params().Gy} // ec curve
-	ssidList = append(ssidList, round.Parties().IDs().Keys()...)
-	ssidList = append(ssidList, big.NewInt(int64(round.number))) // round number
-	ssidList = append(ssidList, round.temp.ssidNonce)
-	ssid := common.SHA512_256i(ssidList...).Bytes()
+	return getSSID(round.Parameters, round.number, round.temp.ssidNonce), nil
+}
 
-	return ssid, nil
+// getSSID returns the ssid of the round `roundNumber` of a keygen of `params`, also for the Verify of an AbortEvidence
+func getSSID(params *tss.Parameters, roundNumber int, nonce *big.Int) []byte {
+	ec := params.EC()
+	ssidList := []*big.Int{ec.Params().P, ec.Params().N, ec.Params().Gx, ec.Params().Gy} // ec curve
+	ssidList = append(ssidList, params.Parties().IDs().Keys()...)
+	ssidList = append(ssidList, new(big.Int).SetBytes(common.SHA512_256(params.SSID()))) // application session id
+	ssidList = append(ssidList, big.NewInt(int64(roundNumber)))                          // round number
+	ssidList = append(ssidList, nonce)
+	return common.SHA512_256i(ssidList...).Bytes()
 }
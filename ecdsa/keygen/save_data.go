@@ -12,12 +12,17 @@
 package keygen
 
 import (
+	"crypto/elliptic"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"math/big"
 
+	"github.com/kisdex/mpc-lib/common"
 	"github.com/kisdex/mpc-lib/crypto"
 	"github.com/kisdex/mpc-lib/crypto/paillier"
+	"github.com/kisdex/mpc-lib/crypto/vss"
 	"github.com/kisdex/mpc-lib/crypto/zkproofs"
 	"github.com/kisdex/mpc-lib/tss"
 )
@@ -38,6 +43,9 @@ type (
 
 	// Everything in LocalPartySaveData is saved locally to user's HD when done
 	LocalPartySaveData struct {
+		// Version is the version of the format of the save data, see SaveDataVersion
+		Version uint32
+
 		LocalPreParams
 		LocalSecrets
 
@@ -56,7 +64,33 @@ type (
 	}
 )
 
+// SaveDataVersion is the version of the format of the LocalPartySaveData of this release. A release that adds or
+// renames a field bumps it, and registers the migration of the save data of the previous version in
+// SaveDataMigrations.
+const SaveDataVersion = 1
+
+// SaveDataMigrations upgrades the JSON encoding of LocalPartySaveData of the older versions, see UnmarshalSaveData
+var SaveDataMigrations = common.NewMigrations("Version", SaveDataVersion)
+
+func init() {
+	// version 1 only adds the version to the save data from before it
+	SaveDataMigrations.Register(0, func(map[string]json.RawMessage) error { return nil })
+}
+
+// UnmarshalSaveData decodes the JSON encoding of LocalPartySaveData of this version or of an older one, which it
+// migrates first
+func UnmarshalSaveData(data []byte) (LocalPartySaveData, error) {
+	var saveData LocalPartySaveData
+	migrated, err := SaveDataMigrations.Migrate(data)
+	if err != nil {
+		return saveData, err
+	}
+	err = json.Unmarshal(migrated, &saveData)
+	return saveData, err
+}
+
 func NewLocalPartySaveData(partyCount int) (saveData LocalPartySaveData) {
+	saveData.Version = SaveDataVersion
 	saveData.Ks = make([]*big.Int, partyCount)
 	saveData.NTildej = make([]*big.Int, partyCount)
 	saveData.H1j, saveData.H2j = make([]*big.Int, partyCount), make([]*big.Int, partyCount)
@@ -72,6 +106,19 @@ func (preParams LocalPreParams) Validate() bool {
 		preParams.H2i != nil
 }
 
+// ValidateLevel reports whether the Paillier modulus and NTilde of the pre-params have the size of `level`
+func (preParams LocalPreParams) ValidateLevel(level tss.SecurityLevel) bool {
+	return preParams.PaillierSK.N.BitLen() == level.ModulusBits() && preParams.NTildei.BitLen() == level.ModulusBits()
+}
+
+// PrmProof proves that H1i is in the group generated by H2i, so that the range proofs under NTildei, H1i, H2i hide
+// their witnesses (CGG21 prm)
+func (preParams LocalPreParams) PrmProof(session []byte) *zkproofs.PrmProof {
+	rp := &zkproofs.RingPedersenParams{N: preParams.NTildei, S: preParams.H1i, T: preParams.H2i}
+	wit := &zkproofs.PrmWitness{Lambda: preParams.Beta, Phi: new(big.Int).Mul(preParams.P, preParams.Q)}
+	return zkproofs.NewPrmProof(session, wit, rp)
+}
+
 func (preParams LocalPreParams) ValidateWithProof() bool {
 	return preParams.Validate() &&
 		preParams.PaillierSK.P != nil &&
@@ -82,6 +129,22 @@ func (preParams LocalPreParams) ValidateWithProof() bool {
 		preParams.Q != nil
 }
 
+// Zeroize wipes the secrets of the pre-params: the Paillier key, the safe primes of NTildei and the exponents of h1i
+// and h2i
+func (preParams *LocalPreParams) Zeroize() {
+	if preParams.PaillierSK != nil {
+		preParams.PaillierSK.Zeroize()
+	}
+	common.Zeroize(preParams.Alpha, preParams.Beta, preParams.P, preParams.Q)
+}
+
+// Zeroize wipes the share Xi and the secrets of the pre-params, e.g. once a key share is retired. Copies of the save
+// data hold the same secrets, so they are wiped too.
+func (local *LocalPartySaveData) Zeroize() {
+	local.LocalPreParams.Zeroize()
+	common.Zeroize(local.Xi)
+}
+
 func (local LocalPartySaveData) GetRingPedersen(partyIdIndex int) *zkproofs.RingPedersenParams {
 	return &zkproofs.RingPedersenParams{
 		N: local.NTildej[partyIdIndex],
@@ -106,6 +169,105 @@ func (local *LocalPartySaveData) RingPedersen() *zkproofs.RingPedersenParams {
 	}
 }
 
+// Validate checks that the save data of a keygen on `ec` is consistent before it is used to sign: the share ids of Ks
+// are distinct and ascending, Xi is the share of BigXj for ShareID, the Paillier and ring-Pedersen parameters of every
+// party have valid sizes and are those of the party, and ECDSAPub is the interpolation of BigXj at 0.
+// The threshold is not stored, so ECDSAPub is interpolated from all of BigXj; this holds for any threshold below the
+// party count.
+func (local LocalPartySaveData) Validate(ec elliptic.Curve) error {
+	if !local.LocalPreParams.Validate() || local.Xi == nil || local.ShareID == nil || local.ECDSAPub == nil {
+		return errors.New("save data is missing pre-params, the secret share or the public key")
+	}
+	n := len(local.Ks)
+	if n < 2 || len(local.BigXj) != n || len(local.NTildej) != n || len(local.H1j) != n || len(local.H2j) != n ||
+		len(local.PaillierPKs) != n {
+		return fmt.Errorf("save data must hold the parameters of at least 2 parties, one of each per party")
+	}
+	if !tss.SameCurve(ec, local.ECDSAPub.Curve()) || !local.ECDSAPub.IsOnCurve() {
+		return errors.New("ECDSAPub is not a point of the curve")
+	}
+	if _, err := vss.CheckIndexes(ec, local.Ks); err != nil {
+		return err
+	}
+	modQ, i := common.ModInt(ec.Params().N), -1
+	for j, kj := range local.Ks {
+		if 0 < j && local.Ks[j-1].Cmp(kj) >= 0 {
+			return fmt.Errorf("Ks are not in ascending order at %d", j)
+		}
+		if kj.Cmp(local.ShareID) == 0 {
+			i = j
+		}
+		if local.BigXj[j] == nil || !tss.SameCurve(ec, local.BigXj[j].Curve()) || !local.BigXj[j].IsOnCurve() {
+			return fmt.Errorf("BigXj[%d] is not a point of the curve", j)
+		}
+		if local.PaillierPKs[j] == nil || !tss.SecurityLevel112.ValidModulus(local.PaillierPKs[j].N) {
+			return fmt.Errorf("the Paillier modulus of party %d has an invalid size", j)
+		}
+		NTildej, H1j, H2j := local.NTildej[j], local.H1j[j], local.H2j[j]
+		if NTildej == nil || !tss.SecurityLevel112.ValidModulus(NTildej) {
+			return fmt.Errorf("the NTilde of party %d has an invalid size", j)
+		}
+		if H1j == nil || H2j == nil || H1j.Cmp(H2j) == 0 || H1j.Cmp(big.NewInt(1)) == 0 || H2j.Cmp(big.NewInt(1)) == 0 ||
+			!common.IsNumberInMultiplicativeGroup(NTildej, H1j) || !common.IsNumberInMultiplicativeGroup(NTildej, H2j) {
+			return fmt.Errorf("h1, h2 of party %d are not distinct members of the multiplicative group of NTilde", j)
+		}
+	}
+	if i < 0 {
+		return errors.New("ShareID is not one of Ks")
+	}
+	if local.PaillierSK.N.Cmp(local.PaillierPKs[i].N) != 0 || local.NTildei.Cmp(local.NTildej[i]) != 0 ||
+		local.H1i.Cmp(local.H1j[i]) != 0 || local.H2i.Cmp(local.H2j[i]) != 0 {
+		return errors.New("the pre-params do not match the parameters of the party")
+	}
+	if !crypto.ScalarBaseMult(ec, modQ.Mod(local.Xi)).Equals(local.BigXj[i]) {
+		return errors.New("Xi is not the share of BigXj")
+	}
+
+	// Lagrange interpolation at 0: y = sum_j Xj * prod_{m != j} km / (km - kj)
+	var y *crypto.ECPoint
+	for j, kj := range local.Ks {
+		lambda := big.NewInt(1)
+		for m, km := range local.Ks {
+			if m != j {
+				lambda = modQ.Mul(lambda, modQ.Mul(km, modQ.ModInverse(modQ.Sub(km, kj))))
+			}
+		}
+		term := local.BigXj[j].ScalarMult(lambda)
+		if y == nil {
+			y = term
+			continue
+		}
+		var err error
+		if y, err = y.Add(term); err != nil {
+			return fmt.Errorf("unable to interpolate ECDSAPub: %w", err)
+		}
+	}
+	if !y.Equals(local.ECDSAPub) {
+		return errors.New("ECDSAPub is not the interpolation of BigXj")
+	}
+	return nil
+}
+
+// PubKeySEC1 returns the compressed SEC1 encoding of ECDSAPub
+func (local LocalPartySaveData) PubKeySEC1() ([]byte, error) {
+	return local.ECDSAPub.MarshalCompressed()
+}
+
+// PubKeyDER returns the DER SubjectPublicKeyInfo of ECDSAPub, which crypto/x509 cannot parse on secp256k1
+func (local LocalPartySaveData) PubKeyDER() ([]byte, error) {
+	return local.ECDSAPub.MarshalPKIX()
+}
+
+// PubKeyPEM returns the PEM "PUBLIC KEY" block of PubKeyDER
+func (local LocalPartySaveData) PubKeyPEM() ([]byte, error) {
+	return local.ECDSAPub.MarshalPEM()
+}
+
+// PubKeyJWK returns the JSON Web Key of ECDSAPub
+func (local LocalPartySaveData) PubKeyJWK() ([]byte, error) {
+	return local.ECDSAPub.MarshalJWK()
+}
+
 // BuildLocalSaveDataSubset re-creates the LocalPartySaveData to contain data for only the list of signing parties.
 func BuildLocalSaveDataSubset(sourceData LocalPartySaveData, sortedIDs tss.SortedPartyIDs) LocalPartySaveData {
 	keysToIndices := make(map[string]int, len(sourceData.Ks))
@@ -0,0 +1,88 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/crypto/paillier"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+func TestLocalPartySaveDataValidate(t *testing.T) {
+	keys, pIDs, err := LoadKeygenTestFixtures(testParticipants)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	for i, key := range keys {
+		assert.NoError(t, key.Validate(tss.S256()), "fixture %d should be valid", i)
+	}
+
+	// clone returns the first fixture with slices and a share that a test case can change without changing the others
+	clone := func() LocalPartySaveData {
+		key := BuildLocalSaveDataSubset(keys[0], pIDs)
+		key.LocalSecrets = LocalSecrets{Xi: new(big.Int).Set(keys[0].Xi), ShareID: keys[0].ShareID}
+		return key
+	}
+	tests := []struct {
+		name   string
+		tamper func(key *LocalPartySaveData)
+	}{
+		{"wrong Xi", func(key *LocalPartySaveData) { key.Xi.Add(key.Xi, big.NewInt(1)) }},
+		{"unknown ShareID", func(key *LocalPartySaveData) { key.ShareID = big.NewInt(1) }},
+		{"unsorted Ks", func(key *LocalPartySaveData) {
+			key.Ks[1], key.Ks[2] = key.Ks[2], key.Ks[1]
+			key.BigXj[1], key.BigXj[2] = key.BigXj[2], key.BigXj[1]
+		}},
+		{"duplicate Ks", func(key *LocalPartySaveData) { key.Ks[2] = key.Ks[1] }},
+		{"missing party", func(key *LocalPartySaveData) { key.PaillierPKs = key.PaillierPKs[1:] }},
+		{"wrong BigXj", func(key *LocalPartySaveData) { key.BigXj[1] = key.BigXj[2] }},
+		{"wrong ECDSAPub", func(key *LocalPartySaveData) { key.ECDSAPub = key.BigXj[0] }},
+		{"small Paillier modulus", func(key *LocalPartySaveData) {
+			key.PaillierPKs[1] = &paillier.PublicKey{N: new(big.Int).Rsh(key.PaillierPKs[1].N, 8)}
+		}},
+		{"equal h1 and h2", func(key *LocalPartySaveData) { key.H2j[1] = key.H1j[1] }},
+		{"h1 outside the group", func(key *LocalPartySaveData) { key.H1j[1] = new(big.Int).Set(key.NTildej[1]) }},
+		{"foreign pre-params", func(key *LocalPartySaveData) { key.LocalPreParams = keys[1].LocalPreParams }},
+	}
+	for _, test := range tests {
+		key := clone()
+		if !assert.NoError(t, key.Validate(tss.S256()), "the copy should be valid") {
+			return
+		}
+		test.tamper(&key)
+		assert.Error(t, key.Validate(tss.S256()), test.name)
+	}
+	assert.Error(t, keys[0].Validate(tss.Edwards()), "save data of another curve should be invalid")
+}
+
+func TestUnmarshalSaveData(t *testing.T) {
+	keys, _, err := LoadKeygenTestFixtures(1)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	// the fixtures are from before the version field
+	assert.Zero(t, keys[0].Version)
+	bz, err := json.Marshal(keys[0])
+	assert.NoError(t, err)
+	key, err := UnmarshalSaveData(bz)
+	if assert.NoError(t, err) {
+		assert.Equal(t, uint32(SaveDataVersion), key.Version)
+		assert.Equal(t, keys[0].Xi, key.Xi)
+		assert.True(t, keys[0].ECDSAPub.Equals(key.ECDSAPub))
+	}
+
+	key.Version = SaveDataVersion + 1
+	bz, err = json.Marshal(key)
+	assert.NoError(t, err)
+	_, err = UnmarshalSaveData(bz)
+	assert.Error(t, err, "save data of a later release")
+}
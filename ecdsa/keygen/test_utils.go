@@ -12,6 +12,7 @@ import (
 	"io/ioutil"
 	"math/big"
 	"math/rand"
+	"os"
 	"path/filepath"
 	"runtime"
 	"sort"
@@ -31,16 +32,53 @@ const (
 const (
 	testFixtureDirFormat  = "%s/../../test/_ecdsa"
 	testFixtureFileFormat = "keygen_data_%d.json"
+	testFixtureFileGlob   = "keygen_data_*.json"
 )
 
 func LoadKeygenTestFixtures(qty int, optionalStart ...int) ([]LocalPartySaveData, tss.SortedPartyIDs, error) {
+	keys, partyIDs, err := loadKeygenTestFixtures(makeTestFixtureFilePath, qty, optionalStart...)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, key := range keys {
+		for _, kbxj := range key.BigXj {
+			kbxj.SetCurve(tss.S256())
+		}
+		key.ECDSAPub.SetCurve(tss.S256())
+	}
+	return keys, partyIDs, nil
+}
+
+// LoadKeygenTestFixturesFromDir loads fixtures written by WriteKeygenTestFixtures, e.g. with cmd/mpc-fixtures, from
+// `dir`. The points keep the curve that they were saved with, which must be registered with tss.RegisterCurve.
+func LoadKeygenTestFixturesFromDir(dir string, qty int, optionalStart ...int) ([]LocalPartySaveData, tss.SortedPartyIDs, error) {
+	return loadKeygenTestFixtures(func(partyIndex int) string {
+		return filepath.Join(dir, fmt.Sprintf(testFixtureFileFormat, partyIndex))
+	}, qty, optionalStart...)
+}
+
+// LoadKeygenBenchmarkFixtures loads all of the fixtures in the directory named by the test.FixturesDirEnv environment
+// variable, or else the checked-in ones
+func LoadKeygenBenchmarkFixtures() ([]LocalPartySaveData, tss.SortedPartyIDs, error) {
+	dir := os.Getenv(test.FixturesDirEnv)
+	if dir == "" {
+		return LoadKeygenTestFixtures(TestParticipants)
+	}
+	files, err := filepath.Glob(filepath.Join(dir, testFixtureFileGlob))
+	if err != nil {
+		return nil, nil, err
+	}
+	return LoadKeygenTestFixturesFromDir(dir, len(files))
+}
+
+func loadKeygenTestFixtures(filePath func(int) string, qty int, optionalStart ...int) ([]LocalPartySaveData, tss.SortedPartyIDs, error) {
 	keys := make([]LocalPartySaveData, 0, qty)
 	start := 0
 	if 0 < len(optionalStart) {
 		start = optionalStart[0]
 	}
 	for i := start; i < qty; i++ {
-		fixtureFilePath := makeTestFixtureFilePath(i)
+		fixtureFilePath := filePath(i)
 		bz, err := ioutil.ReadFile(fixtureFilePath)
 		if err != nil {
 			return nil, nil, errors.Wrapf(err,
@@ -53,10 +91,6 @@ func LoadKeygenTestFixtures(qty int, optionalStart ...int) ([]LocalPartySaveData
 				"could not unmarshal fixture data for party %d located at: %s",
 				i, fixtureFilePath)
 		}
-		for _, kbxj := range key.BigXj {
-			kbxj.SetCurve(tss.S256())
-		}
-		key.ECDSAPub.SetCurve(tss.S256())
 		keys = append(keys, key)
 	}
 	partyIDs := make(tss.UnSortedPartyIDs, len(keys))
@@ -0,0 +1,167 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"crypto/elliptic"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/kisdex/mpc-lib/crypto"
+	"github.com/kisdex/mpc-lib/crypto/paillier"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+type (
+	// tssLibPoint is the JSON of an ECPoint of binance-chain/tss-lib. Releases before v2 have no curve.
+	tssLibPoint struct {
+		Curve  string
+		Coords [2]*big.Int
+	}
+
+	// tssLibSaveData is the JSON of a LocalPartySaveData of the GG18 keygen of binance-chain/tss-lib. Releases before
+	// v1.3 have no Alpha, Beta, P and Q in the pre-params, and releases before v2 no P and Q in the Paillier key.
+	tssLibSaveData struct {
+		LocalPreParams
+		LocalSecrets
+		Ks                []*big.Int
+		NTildej, H1j, H2j []*big.Int
+		BigXj             []*tssLibPoint
+		PaillierPKs       []*paillier.PublicKey
+		ECDSAPub          *tssLibPoint
+	}
+)
+
+// tssLibCurveNames are the names of the curves of binance-chain/tss-lib that this library names otherwise
+var tssLibCurveNames = map[string]tss.CurveName{
+	"nist256p1": tss.P256,
+}
+
+// ImportTSSLibSaveData converts the JSON save data of a party of a GG18 keygen of binance-chain/tss-lib on `ec`, e.g.
+// one of its test fixtures keygen_data_N.json, into save data of this library, which it validates, so that a key of
+// tss-lib moves over without a resharing. It fills in the factors of the Paillier key of the save data of releases
+// before v2 from the key itself.
+//
+// The save data of releases before v1.3 has no secrets of the ring-Pedersen parameters of the party: it signs, but
+// ValidateWithProof reports false for it, and the parties should run ecdsa/refresh to get new pre-params.
+func ImportTSSLibSaveData(ec elliptic.Curve, data []byte) (LocalPartySaveData, error) {
+	var old tssLibSaveData
+	if err := json.Unmarshal(data, &old); err != nil {
+		return LocalPartySaveData{}, fmt.Errorf("invalid tss-lib save data: %w", err)
+	}
+	if len(old.BigXj) != len(old.Ks) {
+		return LocalPartySaveData{}, errors.New("tss-lib save data must have a BigXj per party")
+	}
+	key := NewLocalPartySaveData(len(old.Ks))
+	key.LocalPreParams, key.LocalSecrets = old.LocalPreParams, old.LocalSecrets
+	copy(key.Ks, old.Ks)
+	copy(key.NTildej, old.NTildej)
+	copy(key.H1j, old.H1j)
+	copy(key.H2j, old.H2j)
+	copy(key.PaillierPKs, old.PaillierPKs)
+	var err error
+	for j, xj := range old.BigXj {
+		if key.BigXj[j], err = xj.toECPoint(ec); err != nil {
+			return LocalPartySaveData{}, fmt.Errorf("BigXj[%d]: %w", j, err)
+		}
+	}
+	if key.ECDSAPub, err = old.ECDSAPub.toECPoint(ec); err != nil {
+		return LocalPartySaveData{}, fmt.Errorf("ECDSAPub: %w", err)
+	}
+	if sk := key.PaillierSK; sk != nil && (sk.P == nil || sk.Q == nil) {
+		if sk.P, sk.Q, err = paillierFactors(sk); err != nil {
+			return LocalPartySaveData{}, err
+		}
+	}
+	if err = key.Validate(ec); err != nil {
+		return LocalPartySaveData{}, fmt.Errorf("invalid tss-lib save data: %w", err)
+	}
+	return key, nil
+}
+
+// ImportTSSLibKey converts the save data of all of the parties of a key of binance-chain/tss-lib with
+// ImportTSSLibSaveData, in the order of `data`, and checks that they are of the same key: the parties must agree on
+// the public key and on the public parameters of every party, and each must hold a share of its own.
+func ImportTSSLibKey(ec elliptic.Curve, data [][]byte) ([]LocalPartySaveData, error) {
+	keys := make([]LocalPartySaveData, len(data))
+	shareIDs := make(map[string]int, len(data))
+	for i, bz := range data {
+		key, err := ImportTSSLibSaveData(ec, bz)
+		if err != nil {
+			return nil, fmt.Errorf("party %d: %w", i, err)
+		}
+		if other, ok := shareIDs[key.ShareID.String()]; ok {
+			return nil, fmt.Errorf("parties %d and %d hold the same share", other, i)
+		}
+		shareIDs[key.ShareID.String()] = i
+		if 0 < i {
+			if err = samePublicParameters(keys[0], key); err != nil {
+				return nil, fmt.Errorf("party %d is not of the key of party 0: %w", i, err)
+			}
+		}
+		keys[i] = key
+	}
+	return keys, nil
+}
+
+func samePublicParameters(a, b LocalPartySaveData) error {
+	if !a.ECDSAPub.Equals(b.ECDSAPub) {
+		return errors.New("the public keys differ")
+	}
+	if len(a.Ks) != len(b.Ks) {
+		return errors.New("the party counts differ")
+	}
+	for j := range a.Ks {
+		if a.Ks[j].Cmp(b.Ks[j]) != 0 || !a.BigXj[j].Equals(b.BigXj[j]) || a.PaillierPKs[j].N.Cmp(b.PaillierPKs[j].N) != 0 ||
+			a.NTildej[j].Cmp(b.NTildej[j]) != 0 || a.H1j[j].Cmp(b.H1j[j]) != 0 || a.H2j[j].Cmp(b.H2j[j]) != 0 {
+			return fmt.Errorf("the parameters of party %d differ", j)
+		}
+	}
+	return nil
+}
+
+func (p *tssLibPoint) toECPoint(ec elliptic.Curve) (*crypto.ECPoint, error) {
+	if p == nil || p.Coords[0] == nil || p.Coords[1] == nil {
+		return nil, errors.New("missing point")
+	}
+	if p.Curve != "" {
+		name, ok := tssLibCurveNames[p.Curve]
+		if !ok {
+			name = tss.CurveName(p.Curve)
+		}
+		if curve, ok := tss.GetCurveByName(name); !ok || !tss.SameCurve(curve, ec) {
+			return nil, fmt.Errorf("the point is on the curve %s", p.Curve)
+		}
+	}
+	return crypto.NewECPoint(ec, p.Coords[0], p.Coords[1])
+}
+
+// paillierFactors returns the primes p, q of N from N and phi(N) = (p-1)(q-1): p + q = N - phi(N) + 1 and p, q are
+// the roots of x^2 - (p+q)x + N
+func paillierFactors(sk *paillier.PrivateKey) (*big.Int, *big.Int, error) {
+	if sk.N == nil || sk.PhiN == nil {
+		return nil, nil, errors.New("the Paillier key of the tss-lib save data is incomplete")
+	}
+	sum := new(big.Int).Sub(sk.N, sk.PhiN)
+	sum.Add(sum, big.NewInt(1))
+	disc := new(big.Int).Mul(sum, sum)
+	disc.Sub(disc, new(big.Int).Lsh(sk.N, 2))
+	if disc.Sign() < 0 {
+		return nil, nil, errors.New("phi(N) of the Paillier key of the tss-lib save data is invalid")
+	}
+	root := new(big.Int).Sqrt(disc)
+	p := new(big.Int).Add(sum, root)
+	p.Rsh(p, 1)
+	q := new(big.Int).Sub(sum, root)
+	q.Rsh(q, 1)
+	if new(big.Int).Mul(p, q).Cmp(sk.N) != 0 {
+		return nil, nil, errors.New("phi(N) of the Paillier key of the tss-lib save data is invalid")
+	}
+	return p, q, nil
+}
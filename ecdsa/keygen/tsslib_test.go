@@ -0,0 +1,88 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+// tssLibFixture rewrites a fixture as tss-lib v1.2 wrote it: no version, no curve in the points, no P and Q in the
+// Paillier key, and no secrets of the ring-Pedersen parameters
+func tssLibFixture(t *testing.T, i int) []byte {
+	var fields map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(mustReadFile(t, makeTestFixtureFilePath(i))))
+	dec.UseNumber()
+	assert.NoError(t, dec.Decode(&fields))
+	for _, field := range []string{"Version", "Alpha", "Beta", "P", "Q"} {
+		delete(fields, field)
+	}
+	sk := fields["PaillierSK"].(map[string]interface{})
+	delete(sk, "P")
+	delete(sk, "Q")
+	delete(fields["ECDSAPub"].(map[string]interface{}), "Curve")
+	for _, xj := range fields["BigXj"].([]interface{}) {
+		delete(xj.(map[string]interface{}), "Curve")
+	}
+	bz, err := json.Marshal(fields)
+	assert.NoError(t, err)
+	return bz
+}
+
+func TestImportTSSLibKey(t *testing.T) {
+	fixtures, _, err := LoadKeygenTestFixtures(testParticipants)
+	if !assert.NoError(t, err) {
+		return
+	}
+	data := make([][]byte, len(fixtures))
+	for i := range data {
+		data[i] = tssLibFixture(t, i)
+	}
+	keys, err := ImportTSSLibKey(tss.S256(), data)
+	if !assert.NoError(t, err) {
+		return
+	}
+	for i, key := range keys {
+		assert.Equal(t, uint32(SaveDataVersion), key.Version)
+		assert.Equal(t, fixtures[i].Xi, key.Xi)
+		assert.True(t, fixtures[i].ECDSAPub.Equals(key.ECDSAPub))
+		p, q := fixtures[i].PaillierSK.P, fixtures[i].PaillierSK.Q
+		if key.PaillierSK.P.Cmp(p) != 0 {
+			p, q = q, p
+		}
+		assert.True(t, key.PaillierSK.P.Cmp(p) == 0 && key.PaillierSK.Q.Cmp(q) == 0, "the Paillier factors are recovered")
+		assert.False(t, key.ValidateWithProof())
+	}
+
+	// the save data of tss-lib v2 is that of this library but for the version
+	key, err := ImportTSSLibSaveData(tss.S256(), mustReadFile(t, makeTestFixtureFilePath(0)))
+	if assert.NoError(t, err) {
+		assert.True(t, key.ValidateWithProof())
+	}
+
+	_, err = ImportTSSLibKey(tss.S256(), [][]byte{data[0], data[0]})
+	assert.Error(t, err, "the same share twice")
+	_, err = ImportTSSLibSaveData(elliptic.P256(), data[0])
+	assert.Error(t, err, "a key of another curve")
+	_, err = ImportTSSLibSaveData(tss.S256(), []byte(`{"Ks":[1,2]}`))
+	assert.Error(t, err, "incomplete save data")
+}
+
+func mustReadFile(t *testing.T, path string) []byte {
+	bz, err := os.ReadFile(path)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return bz
+}
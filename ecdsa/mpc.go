@@ -12,8 +12,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto"
+	"github.com/kisdex/mpc-lib/crypto/paillier"
+	"github.com/kisdex/mpc-lib/ecdsa/cggplus"
 	"github.com/kisdex/mpc-lib/ecdsa/keygen"
 	"github.com/kisdex/mpc-lib/ecdsa/signing"
+	twopartykeygen "github.com/kisdex/mpc-lib/ecdsa/twoparty/keygen"
+	twopartysigning "github.com/kisdex/mpc-lib/ecdsa/twoparty/signing"
+	"github.com/kisdex/mpc-lib/store"
 	"github.com/kisdex/mpc-lib/tss"
 	"math"
 	"math/big"
@@ -30,6 +36,7 @@ const (
 )
 
 func init() {
+	// tss registers P-256 as tss.P256; this alias still reads the save data written under its former name
 	tss.RegisterCurve("elliptic.p256Curve", elliptic.P256())
 }
 
@@ -52,6 +59,35 @@ var (
 		"type.googleapis.com/binance.tsslib.ecdsa.signing.SignRound7Message":  12,
 		"type.googleapis.com/binance.tsslib.ecdsa.signing.SignRound8Message":  13,
 		"type.googleapis.com/binance.tsslib.ecdsa.signing.SignRound9Message":  14,
+
+		// CGG+ signing
+		"type.googleapis.com/binance.tsslib.ecdsa.cggplus.SignRound1Message":  5,
+		"type.googleapis.com/binance.tsslib.ecdsa.cggplus.SignRound2Message1": 6,
+		"type.googleapis.com/binance.tsslib.ecdsa.cggplus.SignRound2Message2": 6,
+		"type.googleapis.com/binance.tsslib.ecdsa.cggplus.SignRound3Message":  7,
+		"type.googleapis.com/binance.tsslib.ecdsa.cggplus.SignRound4Message":  8,
+		"type.googleapis.com/binance.tsslib.ecdsa.cggplus.SignRound5Message":  9,
+
+		// Two-party DKG
+		"type.googleapis.com/binance.tsslib.ecdsa.twoparty.keygen.KGRound1Message": 1,
+		"type.googleapis.com/binance.tsslib.ecdsa.twoparty.keygen.KGRound2Message": 2,
+		"type.googleapis.com/binance.tsslib.ecdsa.twoparty.keygen.KGRound3Message": 3,
+
+		// Two-party signing
+		"type.googleapis.com/binance.tsslib.ecdsa.twoparty.signing.SignRound1Message": 5,
+		"type.googleapis.com/binance.tsslib.ecdsa.twoparty.signing.SignRound2Message": 6,
+		"type.googleapis.com/binance.tsslib.ecdsa.twoparty.signing.SignRound3Message": 7,
+		"type.googleapis.com/binance.tsslib.ecdsa.twoparty.signing.SignRound4Message": 8,
+		"type.googleapis.com/binance.tsslib.ecdsa.twoparty.signing.SignRound5Message": 9,
+
+		// Resharing
+		"type.googleapis.com/binance.tsslib.ecdsa.resharing.DGRound1Message":  1,
+		"type.googleapis.com/binance.tsslib.ecdsa.resharing.DGRound2Message1": 2,
+		"type.googleapis.com/binance.tsslib.ecdsa.resharing.DGRound2Message2": 2,
+		"type.googleapis.com/binance.tsslib.ecdsa.resharing.DGRound3Message1": 3,
+		"type.googleapis.com/binance.tsslib.ecdsa.resharing.DGRound3Message2": 3,
+		"type.googleapis.com/binance.tsslib.ecdsa.resharing.DGRound4Message1": 4,
+		"type.googleapis.com/binance.tsslib.ecdsa.resharing.DGRound4Message2": 4,
 	}
 
 	broadcastMessages = map[string]struct{}{
@@ -69,6 +105,21 @@ var (
 		"type.googleapis.com/binance.tsslib.ecdsa.signing.SignRound7Message":  {},
 		"type.googleapis.com/binance.tsslib.ecdsa.signing.SignRound8Message":  {},
 		"type.googleapis.com/binance.tsslib.ecdsa.signing.SignRound9Message":  {},
+
+		// CGG+ signing
+		"type.googleapis.com/binance.tsslib.ecdsa.cggplus.SignRound1Message":  {},
+		"type.googleapis.com/binance.tsslib.ecdsa.cggplus.SignRound2Message1": {},
+		"type.googleapis.com/binance.tsslib.ecdsa.cggplus.SignRound2Message2": {},
+		"type.googleapis.com/binance.tsslib.ecdsa.cggplus.SignRound3Message":  {},
+		"type.googleapis.com/binance.tsslib.ecdsa.cggplus.SignRound4Message":  {},
+		"type.googleapis.com/binance.tsslib.ecdsa.cggplus.SignRound5Message":  {},
+
+		// Resharing
+		"type.googleapis.com/binance.tsslib.ecdsa.resharing.DGRound1Message":  {},
+		"type.googleapis.com/binance.tsslib.ecdsa.resharing.DGRound2Message1": {},
+		"type.googleapis.com/binance.tsslib.ecdsa.resharing.DGRound2Message2": {},
+		"type.googleapis.com/binance.tsslib.ecdsa.resharing.DGRound3Message2": {},
+		"type.googleapis.com/binance.tsslib.ecdsa.resharing.DGRound4Message2": {},
 	}
 )
 
@@ -85,11 +136,8 @@ func (parties parties) numericIDs() []uint16 {
 	return res
 }
 
-type Logger interface {
-	Debugf(format string, a ...interface{})
-	Warnf(format string, a ...interface{})
-	Errorf(format string, a ...interface{})
-}
+// Logger is the logger of a party; the protocols that it runs log to it too
+type Logger = common.Logger
 
 type party struct {
 	logger    Logger
@@ -100,14 +148,35 @@ type party struct {
 	in        chan tss.Message
 	shareData *keygen.LocalPartySaveData
 	closeChan chan struct{}
+
+	// set by SetTwoParty: the party runs the two-party protocol of ecdsa/twoparty, and has twoPartyShareData
+	// instead of shareData
+	twoParty          bool
+	twoPartyShareData *twopartykeygen.LocalPartySaveData
+
+	// set by SetSigningProtocol: the t-of-n protocol that Sign runs
+	signingProtocol SigningProtocol
+
+	// set by SetStore: where KeyGen, KeyGenSession and Reshare put the share data of the party, under keyID
+	store store.Store
+	keyID string
+
+	// set by SetPreParamsPool: where KeyGen and Reshare take the pre-parameters of the party
+	preParamsPool *keygen.PreParamsPool
+
+	// operations started with KeyGenSession and SignSession, by session id
+	sessionSendMsg SessionSender
+	sessionsLock   sync.Mutex
+	sessions       map[string]*session
 }
 
 func NewParty(id uint16, logger Logger) *party {
 	return &party{
-		logger: logger,
-		id:     tss.NewPartyID(fmt.Sprintf("%d", id), "", big.NewInt(int64(id))),
-		out:    make(chan tss.Message, 1000),
-		in:     make(chan tss.Message, 1000),
+		logger:   logger,
+		id:       tss.NewPartyID(fmt.Sprintf("%d", id), "", big.NewInt(int64(id))),
+		out:      make(chan tss.Message, 1000),
+		in:       make(chan tss.Message, 1000),
+		sessions: make(map[string]*session),
 	}
 }
 
@@ -115,6 +184,75 @@ func (p *party) ID() *tss.PartyID {
 	return p.id
 }
 
+// SetTwoParty makes the party run the two-party protocol of Lindell (2017) in KeyGen and Sign, which takes 3 and 5
+// messages between the two parties and needs no pre-parameters besides the Paillier key of the first party.
+// It must be called before KeyGen or SetShareData, and the party must be initialized with 2 parties and a threshold
+// of 1. The share data of the two-party protocol cannot be used by the t-of-n protocol, and vice versa.
+func (p *party) SetTwoParty() {
+	p.twoParty = true
+}
+
+// SigningProtocol is a t-of-n protocol that Sign can run. Both run on the share data of KeyGen, so a key can move from
+// one to the other without a new KeyGen, e.g. once all of its parties support the latter.
+type SigningProtocol int
+
+const (
+	// GG18 is the protocol of Gennaro and Goldfeder (2018) of ecdsa/signing, the default
+	GG18 SigningProtocol = iota
+	// CGGPlus is the protocol of Canetti et al. (2021) of ecdsa/cggplus, which takes 5 rounds instead of 9 and
+	// identifies the parties that abort it
+	CGGPlus
+)
+
+func (protocol SigningProtocol) String() string {
+	switch protocol {
+	case GG18:
+		return "GG18"
+	case CGGPlus:
+		return "CGG+"
+	default:
+		return fmt.Sprintf("SigningProtocol(%d)", int(protocol))
+	}
+}
+
+// SetSigningProtocol sets the protocol that Sign and SignSession run with the key of the party. All the signers of a
+// message must run the same one. The share data, if already set, must be compatible with the protocol; otherwise
+// SetShareData checks it.
+func (p *party) SetSigningProtocol(protocol SigningProtocol) error {
+	if protocol != GG18 && protocol != CGGPlus {
+		return fmt.Errorf("unknown signing protocol %v", protocol)
+	}
+	if p.twoParty {
+		return fmt.Errorf("the two-party protocol cannot run %v", protocol)
+	}
+	if p.shareData != nil {
+		if err := checkShareData(protocol, p.shareData); err != nil {
+			return err
+		}
+	}
+	p.signingProtocol = protocol
+	return nil
+}
+
+// checkShareData checks that the share data has what `protocol` needs: CGG+ proves its every step against the
+// Paillier and ring-Pedersen parameters of all the parties, so it needs all of them to be valid
+func checkShareData(protocol SigningProtocol, shareData *keygen.LocalPartySaveData) error {
+	if protocol != CGGPlus {
+		return nil
+	}
+	if err := shareData.Validate(elliptic.P256()); err != nil {
+		return fmt.Errorf("the share data cannot be used with %v: %w", protocol, err)
+	}
+	return nil
+}
+
+func (p *party) hasShareData() bool {
+	if p.twoParty {
+		return p.twoPartyShareData != nil
+	}
+	return p.shareData != nil
+}
+
 func (p *party) locatePartyIndex(id *tss.PartyID) int {
 	for index, p := range p.params.Parties().IDs() {
 		if bytes.Equal(p.Key, id.Key) {
@@ -142,35 +280,48 @@ func (p *party) ClassifyMsg(msgBytes []byte) (uint8, bool, error) {
 }
 
 func (p *party) OnMsg(msgBytes []byte, from uint16, broadcast bool) {
+	msg, ok := p.parseMsg(msgBytes, from, broadcast)
+	if !ok {
+		return
+	}
+	p.in <- msg
+}
+
+func (p *party) parseMsg(msgBytes []byte, from uint16, broadcast bool) (tss.ParsedMessage, bool) {
 	id := tss.NewPartyID(fmt.Sprintf("%d", from), "", big.NewInt(int64(from)))
 	id.Index = p.locatePartyIndex(id)
 	msg, err := tss.ParseWireMessage(msgBytes, id, broadcast)
 	if err != nil {
 		p.logger.Warnf("Received invalid message (%s) of %d bytes from %d: %v", base64.StdEncoding.EncodeToString(msgBytes), len(msgBytes), from, err)
-		return
+		return nil, false
 	}
 
 	key := msg.GetFrom().KeyInt()
 	if key == nil || key.Cmp(big.NewInt(int64(math.MaxUint16))) >= 0 {
 		p.logger.Warnf("Message received from invalid key: %v", key)
-		return
+		return nil, false
 	}
 
 	claimedFrom := uint16(key.Uint64())
 	if claimedFrom != from {
 		p.logger.Warnf("Message claimed to be from %d but was received from %d", claimedFrom, from)
-		return
+		return nil, false
 	}
 
-	p.in <- msg
+	return msg, true
 }
 
 func (p *party) TPubKey() (*ecdsa.PublicKey, error) {
-	if p.shareData == nil {
+	if !p.hasShareData() {
 		return nil, fmt.Errorf("must call SetShareData() before attempting to sign")
 	}
 
-	pk := p.shareData.ECDSAPub
+	var pk *crypto.ECPoint
+	if p.twoParty {
+		pk = p.twoPartyShareData.ECDSAPub
+	} else {
+		pk = p.shareData.ECDSAPub
+	}
 
 	return &ecdsa.PublicKey{
 		Curve: pk.Curve(),
@@ -188,8 +339,10 @@ func (p *party) ThresholdPK() ([]byte, error) {
 }
 
 func (p *party) SetShareData(shareData []byte) error {
-	var localSaveData keygen.LocalPartySaveData
-	err := json.Unmarshal(shareData, &localSaveData)
+	if p.twoParty {
+		return p.setTwoPartyShareData(shareData)
+	}
+	localSaveData, err := keygen.UnmarshalSaveData(shareData)
 	if err != nil {
 		return fmt.Errorf("failed deserializing shares: %w", err)
 	}
@@ -197,18 +350,45 @@ func (p *party) SetShareData(shareData []byte) error {
 	for _, xj := range localSaveData.BigXj {
 		xj.SetCurve(elliptic.P256())
 	}
+	if err := checkShareData(p.signingProtocol, &localSaveData); err != nil {
+		return err
+	}
 	p.shareData = &localSaveData
 	return nil
 }
 
+func (p *party) setTwoPartyShareData(shareData []byte) error {
+	var localSaveData twopartykeygen.LocalPartySaveData
+	err := json.Unmarshal(shareData, &localSaveData)
+	if err != nil {
+		return fmt.Errorf("failed deserializing shares: %w", err)
+	}
+	if err := localSaveData.Validate(); err != nil {
+		return fmt.Errorf("invalid two-party shares: %w", err)
+	}
+	localSaveData.ECDSAPub.SetCurve(elliptic.P256())
+	for _, xj := range localSaveData.BigXj {
+		xj.SetCurve(elliptic.P256())
+	}
+	p.twoPartyShareData = &localSaveData
+	return nil
+}
+
 func (p *party) Init(parties []uint16, threshold int, sendMsg func(msg []byte, isBroadcast bool, to uint16)) {
+	p.SetParties(parties, threshold)
+	p.sendMsg = sendMsg
+	p.closeChan = make(chan struct{})
+	go p.sendMessages()
+}
+
+// SetParties sets the parties and the threshold of the key without starting to send messages as Init does, e.g. for a
+// party that only runs sessions, see KeyGenSession and SignSession
+func (p *party) SetParties(parties []uint16, threshold int) {
 	partyIDs := partyIDsFromNumbers(parties)
 	ctx := tss.NewPeerContext(partyIDs)
 	p.params = tss.NewParameters(elliptic.P256(), ctx, p.id, len(parties), threshold)
+	p.params.SetLogger(p.logger)
 	p.id.Index = p.locatePartyIndex(p.id)
-	p.sendMsg = sendMsg
-	p.closeChan = make(chan struct{})
-	go p.sendMessages()
 }
 
 func partyIDsFromNumbers(parties []uint16) []*tss.PartyID {
@@ -221,7 +401,7 @@ func partyIDsFromNumbers(parties []uint16) []*tss.PartyID {
 }
 
 func (p *party) Sign(ctx context.Context, msgHash []byte) ([]byte, error) {
-	if p.shareData == nil {
+	if !p.hasShareData() {
 		return nil, fmt.Errorf("must call SetShareData() before attempting to sign")
 	}
 	p.logger.Debugf("Starting signing")
@@ -229,10 +409,22 @@ func (p *party) Sign(ctx context.Context, msgHash []byte) ([]byte, error) {
 
 	defer close(p.closeChan)
 
+	return p.sign(ctx, p.params, msgHash, p.in, p.out)
+}
+
+func (p *party) sign(ctx context.Context, params *tss.Parameters, msgHash []byte, in <-chan tss.Message, out chan<- tss.Message) ([]byte, error) {
 	end := make(chan *common.SignatureData, 1)
 
 	msgToSign := hashToInt(msgHash, elliptic.P256())
-	party := signing.NewLocalParty(msgToSign, p.params, *p.shareData, p.out, end)
+	var party tss.Party
+	switch {
+	case p.twoParty:
+		party = twopartysigning.NewLocalParty(msgToSign, params, *p.twoPartyShareData, out, end)
+	case p.signingProtocol == CGGPlus:
+		party = cggplus.NewLocalParty(msgToSign, params, *p.shareData, out, end)
+	default:
+		party = signing.NewLocalParty(msgToSign, params, *p.shareData, out, end)
+	}
 
 	var endWG sync.WaitGroup
 	endWG.Add(1)
@@ -270,7 +462,7 @@ func (p *party) Sign(ctx context.Context, msgHash []byte) ([]byte, error) {
 				return nil, fmt.Errorf("failed marshaling ECDSA signature: %w", err)
 			}
 			return sigRaw, nil
-		case msg := <-p.in:
+		case msg := <-in:
 			raw, routing, err := msg.WireBytes()
 			if err != nil {
 				p.logger.Warnf("Received error when serializing message: %v", err)
@@ -292,19 +484,32 @@ func (p *party) KeyGen(ctx context.Context) ([]byte, error) {
 
 	defer close(p.closeChan)
 
+	shareData, err := p.keygen(ctx, p.params, p.in, p.out)
+	if err != nil {
+		return nil, err
+	}
+	return shareData, p.storeShareData(ctx, shareData)
+}
+
+func (p *party) keygen(ctx context.Context, params *tss.Parameters, in <-chan tss.Message, out chan<- tss.Message) ([]byte, error) {
 	preParamGenTimeout := defaultSafePrimeGenTimeout
 
 	deadline, deadlineExists := ctx.Deadline()
 	if deadlineExists {
 		preParamGenTimeout = deadline.Sub(time.Now())
 	}
-	preParams, err := keygen.GeneratePreParams(preParamGenTimeout)
+	genCtx, cancel := context.WithTimeout(context.Background(), preParamGenTimeout)
+	defer cancel()
+	if p.twoParty {
+		return p.twoPartyKeygen(ctx, genCtx, params, in, out)
+	}
+	preParams, err := p.generatePreParams(ctx, params)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	end := make(chan *keygen.LocalPartySaveData, 1)
-	party := keygen.NewLocalParty(p.params, p.out, end, *preParams)
+	party := keygen.NewLocalParty(params, out, end, *preParams)
 
 	var endWG sync.WaitGroup
 	endWG.Add(1)
@@ -329,42 +534,120 @@ func (p *party) KeyGen(ctx context.Context) ([]byte, error) {
 				return nil, fmt.Errorf("failed serializing DKG output: %w", err)
 			}
 			return dkgRawOut, nil
-		case msg := <-p.in:
+		case msg := <-in:
 			raw, routing, err := msg.WireBytes()
 			if err != nil {
 				p.logger.Warnf("Received error when serializing message: %v", err)
 				continue
 			}
 			p.logger.Debugf("%s Got message from %s", p.id.Id, routing.From.Id)
-			ok, err := party.UpdateFromBytes(raw, routing.From, routing.IsBroadcast)
+			ok, tssErr := party.UpdateFromBytes(raw, routing.From, routing.IsBroadcast)
+			if tssErr != nil && len(tssErr.Evidence()) > 0 {
+				// the keygen cannot finish; the error names the culprits to exclude from a retry, with the evidence
+				return nil, fmt.Errorf("DKG aborted: %w", tssErr)
+			}
 			if !ok {
-				p.logger.Warnf("Received error when updating party: %v", err.Error())
+				p.logger.Warnf("Received error when updating party: %v", tssErr.Error())
 				continue
 			}
 		}
 	}
 }
 
-func (p *party) sendMessages() {
+// twoPartyKeygen runs the two-party DKG; only the first party samples a Paillier key, within the time given by genCtx
+func (p *party) twoPartyKeygen(ctx, genCtx context.Context, params *tss.Parameters, in <-chan tss.Message, out chan<- tss.Message) ([]byte, error) {
+	var paillierSKs []*paillier.PrivateKey
+	if params.PartyID().Index == twopartykeygen.P1 {
+		paillierSK, _, err := paillier.GenerateKeyPair(genCtx, params.SecurityLevel().ModulusBits())
+		if err != nil {
+			return nil, fmt.Errorf("failed generating the Paillier key: %w", err)
+		}
+		paillierSKs = append(paillierSKs, paillierSK)
+	}
+
+	end := make(chan *twopartykeygen.LocalPartySaveData, 1)
+	party := twopartykeygen.NewLocalParty(params, out, end, paillierSKs...)
+
+	var endWG sync.WaitGroup
+	endWG.Add(1)
+
+	go func() {
+		defer endWG.Done()
+		err := party.Start()
+		if err != nil {
+			p.logger.Errorf("Failed generating key: %v", err)
+		}
+	}()
+
+	defer endWG.Wait()
+
 	for {
 		select {
-		case <-p.closeChan:
-			return
-		case msg := <-p.out:
-			msgBytes, routing, err := msg.WireBytes()
+		case <-ctx.Done():
+			return nil, fmt.Errorf("DKG timed out: %w", ctx.Err())
+		case dkgOut := <-end:
+			dkgRawOut, err := json.Marshal(*dkgOut)
 			if err != nil {
-				p.logger.Warnf("Failed marshaling message: %v", err)
+				return nil, fmt.Errorf("failed serializing DKG output: %w", err)
+			}
+			return dkgRawOut, nil
+		case msg := <-in:
+			raw, routing, err := msg.WireBytes()
+			if err != nil {
+				p.logger.Warnf("Received error when serializing message: %v", err)
 				continue
 			}
-			if routing.IsBroadcast {
-				p.sendMsg(msgBytes, routing.IsBroadcast, 0)
-			} else {
-				for _, to := range msg.GetTo() {
-					p.sendMsg(msgBytes, routing.IsBroadcast, uint16(big.NewInt(0).SetBytes(to.Key).Uint64()))
-				}
+			p.logger.Debugf("%s Got message from %s", p.id.Id, routing.From.Id)
+			ok, err := party.UpdateFromBytes(raw, routing.From, routing.IsBroadcast)
+			if !ok {
+				p.logger.Warnf("Received error when updating party: %v", err.Error())
+				continue
+			}
+		}
+	}
+}
+
+func (p *party) sendMessages() {
+	p.forwardMessages(p.out, p.closeChan, p.sendMsg)
+}
+
+func (p *party) forwardMessages(out <-chan tss.Message, closeChan <-chan struct{}, sendMsg Sender) {
+	send := func(msg tss.Message) {
+		msgBytes, routing, err := msg.WireBytes()
+		if err != nil {
+			p.logger.Warnf("Failed marshaling message: %v", err)
+			return
+		}
+		if routing.IsBroadcast {
+			sendMsg(msgBytes, routing.IsBroadcast, 0)
+		} else {
+			for _, to := range msg.GetTo() {
+				sendMsg(msgBytes, routing.IsBroadcast, uint16(big.NewInt(0).SetBytes(to.Key).Uint64()))
 			}
 		}
 	}
+	for {
+		select {
+		case <-closeChan:
+			drainMessages(out, send)
+			return
+		case msg := <-out:
+			send(msg)
+		}
+	}
+}
+
+// drainMessages sends the messages left in `out` once the protocol has finished: the last messages of a party may
+// be queued still, and the other parties cannot finish without them
+func drainMessages(out <-chan tss.Message, send func(tss.Message)) {
+	for {
+		select {
+		case msg := <-out:
+			send(msg)
+		default:
+			return
+		}
+	}
 }
 
 // hashToInt is taken as-is from the Go ECDSA standard library
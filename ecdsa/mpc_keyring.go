@@ -0,0 +1,201 @@
+package ecdsa
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/kisdex/mpc-lib/ecdsa/keygen"
+	"github.com/kisdex/mpc-lib/store"
+)
+
+// KeySender sends a message of the session `sessionID` of the key `keyID`; the transport must deliver both ids along
+// with the message so that the recipient can pass them to Keyring.OnMsg.
+type KeySender func(keyID, sessionID string, msg []byte, isBroadcast bool, to uint16)
+
+// Keyring holds the shares of many threshold keys of one party, by key id. Each key has its own parties and
+// threshold, and runs its keygen and signing in sessions, so that the keys may sign concurrently and several times
+// each; the messages of all of them go through one KeySender and one OnMsg.
+type Keyring struct {
+	id      uint16
+	logger  Logger
+	sendMsg KeySender
+	// set by SetStore: where the keys keep their share data
+	store store.Store
+	// set by SetPreParamsPool: where the keys take the pre-parameters of their keygens
+	preParamsPool *keygen.PreParamsPool
+
+	lock sync.Mutex
+	keys map[string]*party
+	// messages of keys that are not in the keyring yet, e.g. of a keygen that the other parties started earlier
+	pending map[string][]pendingMsg
+}
+
+type pendingMsg struct {
+	sessionID string
+	msg       []byte
+	from      uint16
+	broadcast bool
+}
+
+// NewKeyring returns an empty keyring of the party `id`, which sends the messages of its keys with `sendMsg`
+func NewKeyring(id uint16, logger Logger, sendMsg KeySender) *Keyring {
+	return &Keyring{
+		id:      id,
+		logger:  logger,
+		sendMsg: sendMsg,
+		keys:    make(map[string]*party),
+		pending: make(map[string][]pendingMsg),
+	}
+}
+
+// AddKey adds the key `keyID` with its share data, as returned by KeyGen, shared among `parties` with `threshold`
+func (k *Keyring) AddKey(keyID string, parties []uint16, threshold int, shareData []byte) error {
+	p := k.newParty(keyID, parties, threshold)
+	if err := p.SetShareData(shareData); err != nil {
+		return fmt.Errorf("key %s: %w", keyID, err)
+	}
+	return k.add(keyID, p)
+}
+
+// SetStore makes the keyring keep the share data of its keys in `s`: KeyGen puts the share data of a new key there,
+// and LoadKey gets it back. It must be called before the keys are added.
+func (k *Keyring) SetStore(s store.Store) {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	k.store = s
+}
+
+// SetPreParamsPool makes the keygens and resharings of the keys of the keyring take their pre-parameters from `pool`,
+// see party.SetPreParamsPool. It must be called before the keys are added.
+func (k *Keyring) SetPreParamsPool(pool *keygen.PreParamsPool) {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	k.preParamsPool = pool
+}
+
+// LoadKey adds the key `keyID`, shared among `parties` with `threshold`, with its share data from the store of the
+// keyring, see SetStore
+func (k *Keyring) LoadKey(ctx context.Context, keyID string, parties []uint16, threshold int) error {
+	p := k.newParty(keyID, parties, threshold)
+	if err := p.LoadShareData(ctx); err != nil {
+		return err
+	}
+	return k.add(keyID, p)
+}
+
+// RemoveKey removes the key `keyID`; its running sessions finish
+func (k *Keyring) RemoveKey(keyID string) {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	delete(k.keys, keyID)
+	delete(k.pending, keyID)
+}
+
+// KeyIDs returns the ids of the keys of the keyring, sorted
+func (k *Keyring) KeyIDs() []string {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	keyIDs := make([]string, 0, len(k.keys))
+	for keyID := range k.keys {
+		keyIDs = append(keyIDs, keyID)
+	}
+	sort.Strings(keyIDs)
+	return keyIDs
+}
+
+// Party returns the party of the key `keyID`, e.g. to set its signing protocol with SetSigningProtocol
+func (k *Keyring) Party(keyID string) (*party, error) {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	p, ok := k.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("no key %s in the keyring", keyID)
+	}
+	return p, nil
+}
+
+// TPubKey returns the threshold public key of the key `keyID`
+func (k *Keyring) TPubKey(keyID string) (*ecdsa.PublicKey, error) {
+	p, err := k.Party(keyID)
+	if err != nil {
+		return nil, err
+	}
+	return p.TPubKey()
+}
+
+// OnMsg routes a message of the session `sessionID` of the key `keyID`. The messages of a key that is not in the
+// keyring yet are kept until it is added, or until RemoveKey drops them.
+func (k *Keyring) OnMsg(keyID, sessionID string, msg []byte, from uint16, broadcast bool) {
+	k.lock.Lock()
+	p, ok := k.keys[keyID]
+	if !ok {
+		k.pending[keyID] = append(k.pending[keyID], pendingMsg{sessionID: sessionID, msg: msg, from: from, broadcast: broadcast})
+		k.lock.Unlock()
+		return
+	}
+	k.lock.Unlock()
+	p.OnSessionMsg(sessionID, msg, from, broadcast)
+}
+
+// KeyGen generates the key `keyID` among `parties` with `threshold` in the session `sessionID`, adds it to the
+// keyring and returns its share data, which it puts in the store of the keyring if it has one
+func (k *Keyring) KeyGen(ctx context.Context, keyID, sessionID string, parties []uint16, threshold int) ([]byte, error) {
+	p := k.newParty(keyID, parties, threshold)
+	if err := k.add(keyID, p); err != nil {
+		return nil, err
+	}
+	shareData, err := p.KeyGenSession(ctx, sessionID)
+	if err == nil {
+		err = p.SetShareData(shareData)
+	}
+	if err != nil {
+		k.RemoveKey(keyID)
+		// with the share data if the keygen itself succeeded, so that it is not lost
+		return shareData, fmt.Errorf("key %s: %w", keyID, err)
+	}
+	return shareData, nil
+}
+
+// Sign signs `msgHash` with the key `keyID` in the session `sessionID`, concurrently with the other sessions of the
+// keyring. All of the signers must use the same session id.
+func (k *Keyring) Sign(ctx context.Context, keyID, sessionID string, msgHash []byte) ([]byte, error) {
+	p, err := k.Party(keyID)
+	if err != nil {
+		return nil, err
+	}
+	return p.SignSession(ctx, sessionID, msgHash)
+}
+
+func (k *Keyring) newParty(keyID string, parties []uint16, threshold int) *party {
+	p := NewParty(k.id, k.logger)
+	p.SetParties(parties, threshold)
+	k.lock.Lock()
+	if k.store != nil {
+		p.SetStore(k.store, keyID)
+	}
+	p.SetPreParamsPool(k.preParamsPool)
+	k.lock.Unlock()
+	sendMsg := k.sendMsg
+	p.SetSessionSender(func(sessionID string, msg []byte, isBroadcast bool, to uint16) {
+		sendMsg(keyID, sessionID, msg, isBroadcast, to)
+	})
+	return p
+}
+
+// add adds the party of a key and passes it the messages that arrived for the key before it, ahead of any later one
+func (k *Keyring) add(keyID string, p *party) error {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	if _, ok := k.keys[keyID]; ok {
+		return fmt.Errorf("key %s is already in the keyring", keyID)
+	}
+	k.keys[keyID] = p
+	for _, m := range k.pending[keyID] {
+		p.OnSessionMsg(m.sessionID, m.msg, m.from, m.broadcast)
+	}
+	delete(k.pending, keyID)
+	return nil
+}
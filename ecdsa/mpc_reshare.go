@@ -0,0 +1,196 @@
+package ecdsa
+
+import (
+	"bytes"
+	"context"
+	"crypto/elliptic"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/kisdex/mpc-lib/ecdsa/keygen"
+	"github.com/kisdex/mpc-lib/ecdsa/resharing"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+// Reshare moves the key of the old committee to the new committee with the threshold `newThreshold`, without changing
+// the public key. Every party of either committee calls it with the same arguments; the old threshold is the one the
+// party was initialized with. A party of the old committee must have its share data, see SetShareData, and a party of
+// both committees keeps its pre-parameters; a party of only the new committee generates them, as KeyGen does.
+// It returns the share data of the party in the new committee, or nil for a party of only the old committee; with a
+// store, see SetStore, it puts the share data there too.
+//
+// The protocol broadcasts to one committee or to both, so during Reshare the party sends every message to each of its
+// recipients in turn with `to` set, broadcasts included, and the Sender must deliver each one to `to` only.
+func (p *party) Reshare(ctx context.Context, oldCommittee, newCommittee []uint16, newThreshold int) ([]byte, error) {
+	shareData, err := p.reshare(ctx, oldCommittee, newCommittee, newThreshold)
+	if err != nil {
+		return nil, err
+	}
+	return shareData, p.storeShareData(ctx, shareData)
+}
+
+func (p *party) reshare(ctx context.Context, oldCommittee, newCommittee []uint16, newThreshold int) ([]byte, error) {
+	if p.params == nil {
+		return nil, fmt.Errorf("must call Init() before attempting to reshare")
+	}
+	if p.twoParty {
+		return nil, fmt.Errorf("the two-party protocol does not support resharing")
+	}
+	p.logger.Debugf("Starting resharing")
+	defer p.logger.Debugf("Finished resharing")
+
+	defer close(p.closeChan)
+
+	// the messages of the protocol are forwarded to their recipients only, so they do not go through p.out and
+	// sendMessages
+	out := make(chan tss.Message, cap(p.out))
+	go p.forwardReshareMessages(out, p.closeChan)
+
+	reshare, err := tss.NewReshare(elliptic.P256(), partyIDsFromNumbers(oldCommittee), partyIDsFromNumbers(newCommittee), p.params.Threshold(), newThreshold)
+	if err != nil {
+		return nil, err
+	}
+	params, err := reshare.Parameters(p.id)
+	if err != nil {
+		return nil, err
+	}
+	params.SetLogger(p.logger)
+
+	var key keygen.LocalPartySaveData
+	if params.IsOldCommittee() {
+		if p.shareData == nil {
+			return nil, fmt.Errorf("must call SetShareData() before resharing from the old committee")
+		}
+		// with its pre-parameters, which it keeps if it stays in the new committee
+		key = *p.shareData
+	} else {
+		key = keygen.NewLocalPartySaveData(len(newCommittee))
+		preParams, err := p.generatePreParams(ctx, params.Parameters)
+		if err != nil {
+			return nil, err
+		}
+		key.LocalPreParams = *preParams
+	}
+
+	end := make(chan *keygen.LocalPartySaveData, 1)
+	party := resharing.NewLocalParty(params, key, out, end)
+
+	// the party must start before it reads its messages, some of which may have arrived while it generated its
+	// pre-parameters; its first round only sends
+	if err := party.Start(); err != nil {
+		return nil, fmt.Errorf("failed starting resharing: %w", err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("resharing timed out: %w", ctx.Err())
+		case reshareOut := <-end:
+			if !params.IsNewCommittee() {
+				return nil, nil
+			}
+			reshareRawOut, err := json.Marshal(*reshareOut)
+			if err != nil {
+				return nil, fmt.Errorf("failed serializing resharing output: %w", err)
+			}
+			return reshareRawOut, nil
+		case msg := <-p.in:
+			raw, routing, err := msg.WireBytes()
+			if err != nil {
+				p.logger.Warnf("Received error when serializing message: %v", err)
+				continue
+			}
+			// a party of both committees has an id, and an index, in each; the sender's is that of its committee
+			var from *tss.PartyID
+			if parsed, ok := msg.(tss.ParsedMessage); ok {
+				from = reshareSender(reshare, parsed)
+			}
+			if from == nil {
+				p.logger.Warnf("Received resharing message from %s, which is not in its committee", routing.From.Id)
+				continue
+			}
+			p.logger.Debugf("%s Got message from %s", p.id.Id, from.Id)
+			ok, err := party.UpdateFromBytes(raw, from, routing.IsBroadcast)
+			if !ok {
+				p.logger.Warnf("Received error when updating party: %v", err.Error())
+				continue
+			}
+		}
+	}
+}
+
+// generatePreParams takes the pre-parameters of the party from its pool, or generates them, within the deadline of ctx
+func (p *party) generatePreParams(ctx context.Context, params *tss.Parameters) (*keygen.LocalPreParams, error) {
+	preParamGenTimeout := defaultSafePrimeGenTimeout
+
+	deadline, deadlineExists := ctx.Deadline()
+	if deadlineExists {
+		preParamGenTimeout = deadline.Sub(time.Now())
+	}
+	genCtx, cancel := context.WithTimeout(context.Background(), preParamGenTimeout)
+	defer cancel()
+	if p.preParamsPool != nil && p.preParamsPool.Level() == params.SecurityLevel() {
+		preParams, err := p.preParamsPool.Get(genCtx)
+		if err != nil {
+			return nil, fmt.Errorf("failed taking pre-parameters from the pool: %w", err)
+		}
+		return preParams, nil
+	}
+	preParams, err := keygen.GeneratePreParamsForLevel(genCtx, params.SecurityLevel())
+	if err != nil {
+		return nil, fmt.Errorf("failed generating pre-parameters: %w", err)
+	}
+	return preParams, nil
+}
+
+// SetPreParamsPool makes KeyGen, KeyGenSession and Reshare take the pre-parameters of the party from `pool`, which
+// must be running, instead of generating them, if the pool has the security level of the parameters of the ceremony
+func (p *party) SetPreParamsPool(pool *keygen.PreParamsPool) {
+	p.preParamsPool = pool
+}
+
+// reshareSender returns the id of the sender of a resharing message in the committee that sends it
+func reshareSender(reshare *tss.Reshare, msg tss.ParsedMessage) *tss.PartyID {
+	key := msg.GetFrom().KeyInt()
+	switch msg.Content().(type) {
+	case *resharing.DGRound2Message1, *resharing.DGRound2Message2, *resharing.DGRound4Message1, *resharing.DGRound4Message2:
+		return reshare.NewParties.FindByKey(key)
+	default:
+		return reshare.OldParties.FindByKey(key)
+	}
+}
+
+// forwardReshareMessages sends every message to each of its recipients but the party itself, once to a party of both
+// committees
+func (p *party) forwardReshareMessages(out <-chan tss.Message, closeChan <-chan struct{}) {
+	send := func(msg tss.Message) {
+		msgBytes, routing, err := msg.WireBytes()
+		if err != nil {
+			p.logger.Warnf("Failed marshaling message: %v", err)
+			return
+		}
+		sent := make(map[uint16]struct{}, len(msg.GetTo()))
+		for _, to := range msg.GetTo() {
+			if bytes.Equal(to.Key, p.id.Key) {
+				continue
+			}
+			id := uint16(big.NewInt(0).SetBytes(to.Key).Uint64())
+			if _, ok := sent[id]; ok {
+				continue
+			}
+			sent[id] = struct{}{}
+			p.sendMsg(msgBytes, routing.IsBroadcast, id)
+		}
+	}
+	for {
+		select {
+		case <-closeChan:
+			drainMessages(out, send)
+			return
+		case msg := <-out:
+			send(msg)
+		}
+	}
+}
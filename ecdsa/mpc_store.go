@@ -0,0 +1,38 @@
+package ecdsa
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kisdex/mpc-lib/store"
+)
+
+// SetStore makes the party keep its share data in `s`, under the key id `keyID` and the id of the party: KeyGen,
+// KeyGenSession and Reshare put the share data that they return, and LoadShareData gets it back, e.g. after a restart.
+// If the share data cannot be put, they return it along with the error, so that it is not lost.
+func (p *party) SetStore(s store.Store, keyID string) {
+	p.store, p.keyID = s, keyID
+}
+
+// LoadShareData sets the share data of the party from its store, as SetShareData does
+func (p *party) LoadShareData(ctx context.Context) error {
+	if p.store == nil {
+		return fmt.Errorf("must call SetStore() before loading the share data")
+	}
+	shareData, err := p.store.Get(ctx, p.keyID, p.id.Id)
+	if err != nil {
+		return fmt.Errorf("failed loading the share data of key %s: %w", p.keyID, err)
+	}
+	return p.SetShareData(shareData)
+}
+
+// storeShareData puts the share data returned by a keygen or a resharing in the store of the party, if it has one
+func (p *party) storeShareData(ctx context.Context, shareData []byte) error {
+	if p.store == nil || shareData == nil {
+		return nil
+	}
+	if err := p.store.Put(ctx, p.keyID, p.id.Id, shareData); err != nil {
+		return fmt.Errorf("failed storing the share data of key %s: %w", p.keyID, err)
+	}
+	return nil
+}
@@ -10,8 +10,14 @@ import (
 	"context"
 	"crypto/ecdsa"
 	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"github.com/kisdex/mpc-lib/ecdsa/keygen"
+	"github.com/kisdex/mpc-lib/store"
 	"github.com/kisdex/mpc-lib/tss"
 	"golang.org/x/crypto/sha3"
 	"math/big"
@@ -69,6 +75,39 @@ func (parties parties) sign(msg []byte) ([][]byte, error) {
 	return sigs, nil
 }
 
+// signSessions signs every message in its own session, with all of the sessions running at the same time
+func (parties parties) signSessions(msgs [][]byte) ([][][]byte, error) {
+	sigs := make([][][]byte, len(msgs))
+	var threadSafeError atomic.Value
+
+	var wg sync.WaitGroup
+	wg.Add(len(msgs) * len(parties))
+
+	for i, msg := range msgs {
+		sigs[i] = make([][]byte, len(parties))
+		for j, p := range parties {
+			go func(p *party, i, j int, msg []byte) {
+				defer wg.Done()
+				sig, err := p.SignSession(context.Background(), fmt.Sprintf("sign-%d", i), msg)
+				if err != nil {
+					threadSafeError.Store(err.Error())
+					return
+				}
+				sigs[i][j] = sig
+			}(p, i, j, msg)
+		}
+	}
+
+	wg.Wait()
+
+	err := threadSafeError.Load()
+	if err != nil {
+		return nil, fmt.Errorf(err.(string))
+	}
+
+	return sigs, nil
+}
+
 func (parties parties) keygen() ([][]byte, error) {
 	var lock sync.Mutex
 	shares := make([][]byte, len(parties))
@@ -102,6 +141,35 @@ func (parties parties) keygen() ([][]byte, error) {
 	return shares, nil
 }
 
+func (parties parties) reshare(oldCommittee, newCommittee []uint16, newThreshold int) ([][]byte, error) {
+	shares := make([][]byte, len(parties))
+	var threadSafeError atomic.Value
+
+	var wg sync.WaitGroup
+	wg.Add(len(parties))
+
+	for i, p := range parties {
+		go func(p *party, i int) {
+			defer wg.Done()
+			share, err := p.Reshare(context.Background(), oldCommittee, newCommittee, newThreshold)
+			if err != nil {
+				threadSafeError.Store(err.Error())
+				return
+			}
+			shares[i] = share
+		}(p, i)
+	}
+
+	wg.Wait()
+
+	err := threadSafeError.Load()
+	if err != nil {
+		return nil, fmt.Errorf(err.(string))
+	}
+
+	return shares, nil
+}
+
 func (parties parties) Mapping() map[string]*tss.PartyID {
 	partyIDMap := make(map[string]*tss.PartyID)
 	for _, id := range parties {
@@ -169,6 +237,195 @@ func TestTSS(t *testing.T) {
 	assert.NoError(t, err)
 
 	assert.True(t, ecdsa.VerifyASN1(pk, digest(msgToSign), sigs[0]))
+
+	t.Logf("Signing with CGG+")
+	parties.init(senders(parties))
+	for _, p := range parties {
+		assert.NoError(t, p.SetSigningProtocol(CGGPlus))
+	}
+	t1 = time.Now()
+	cggPlusSigs, err := parties.sign(digest(msgToSign))
+	assert.NoError(t, err)
+	t.Logf("Signing with CGG+ completed in %v", time.Since(t1))
+	for _, sig := range cggPlusSigs {
+		assert.True(t, ecdsa.VerifyASN1(pk, digest(msgToSign), sig))
+	}
+	for _, p := range parties {
+		assert.NoError(t, p.SetSigningProtocol(GG18))
+	}
+
+	// CGG+ needs the ring-Pedersen parameters of every party
+	var incomplete keygen.LocalPartySaveData
+	assert.NoError(t, json.Unmarshal(shares[0], &incomplete))
+	incomplete.H1j[1] = nil
+	incompleteShares, err := json.Marshal(incomplete)
+	assert.NoError(t, err)
+	pX := NewParty(1, logger("pX", t.Name()))
+	assert.NoError(t, pX.SetShareData(incompleteShares))
+	assert.Error(t, pX.SetSigningProtocol(CGGPlus))
+	pX = NewParty(1, logger("pX", t.Name()))
+	assert.NoError(t, pX.SetSigningProtocol(CGGPlus))
+	assert.Error(t, pX.SetShareData(incompleteShares))
+
+	t.Logf("Signing in concurrent sessions")
+	for i, p := range parties {
+		p.SetSessionSender(sessionSenders(parties)[i])
+	}
+	msgs := [][]byte{digest([]byte("session 1")), digest([]byte("session 2")), digest([]byte("session 3"))}
+	t1 = time.Now()
+	sessionSigs, err := parties.signSessions(msgs)
+	assert.NoError(t, err)
+	t.Logf("Signing %d sessions completed in %v", len(msgs), time.Since(t1))
+	for i, msg := range msgs {
+		for _, sig := range sessionSigs[i] {
+			assert.True(t, ecdsa.VerifyASN1(pk, msg, sig), "signature of session %d must verify", i)
+		}
+	}
+
+	t.Logf("Signing a certificate through crypto.Signer")
+	signer, err := pA.Signer(func(ctx context.Context, sessionID string, digest []byte) error {
+		errs := make(chan error, len(parties)-1)
+		for _, p := range parties[1:] {
+			go func(p *party) {
+				_, err := p.SignSession(ctx, sessionID, digest)
+				errs <- err
+			}(p)
+		}
+		for range parties[1:] {
+			if err := <-errs; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "threshold key"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, signer.Public(), signer)
+	if assert.NoError(t, err) {
+		cert, err := x509.ParseCertificate(der)
+		assert.NoError(t, err)
+		assert.NoError(t, cert.CheckSignatureFrom(cert))
+	}
+
+	t.Logf("Resharing to a committee that replaces pA with pD")
+	pD := NewParty(4, logger("pD", t.Name()))
+	all := append(parties[:len(parties):len(parties)], pD)
+	shareStore, err := store.NewFileStore(t.TempDir())
+	assert.NoError(t, err)
+	for i, p := range all {
+		p.Init(all.numericIDs(), len(parties)-1, reshareSenders(all)[i])
+		p.SetStore(shareStore, "after")
+	}
+	t1 = time.Now()
+	newShares, err := all.reshare([]uint16{1, 2, 3}, []uint16{2, 3, 4}, len(parties)-1)
+	if !assert.NoError(t, err) {
+		return
+	}
+	t.Logf("Resharing completed in %v", time.Since(t1))
+	assert.Nil(t, newShares[0], "pA leaves the committee")
+
+	newParties := append(parties[1:len(parties):len(parties)], pD)
+	storedIDs, err := shareStore.List(context.Background(), "after")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2", "3", "4"}, storedIDs, "the parties of the new committee store their shares")
+	newParties.init(senders(newParties))
+	for _, p := range newParties {
+		assert.NoError(t, p.LoadShareData(context.Background()))
+	}
+	sigs, err = newParties.sign(digest(msgToSign))
+	assert.NoError(t, err)
+	newPK, err := pD.TPubKey()
+	assert.NoError(t, err)
+	assert.True(t, newPK.Equal(pk), "resharing keeps the public key")
+	for _, sig := range sigs {
+		assert.True(t, ecdsa.VerifyASN1(pk, digest(msgToSign), sig))
+	}
+
+	t.Logf("Signing with the keys before and after resharing concurrently, from keyrings")
+	members := map[string][]uint16{"before": {1, 2, 3}, "after": {2, 3, 4}}
+	rings := keyrings(members, t.Name())
+	for i, id := range members["before"] {
+		assert.NoError(t, rings[id].AddKey("before", members["before"], len(parties)-1, shares[i]))
+	}
+	for _, id := range members["after"] {
+		rings[id].SetStore(shareStore)
+		assert.NoError(t, rings[id].LoadKey(context.Background(), "after", members["after"], len(parties)-1))
+	}
+	rings[1].SetStore(shareStore)
+	assert.Error(t, rings[1].LoadKey(context.Background(), "after", members["after"], len(parties)-1), "pA has no share of the key")
+	assert.Equal(t, []string{"after", "before"}, rings[2].KeyIDs())
+	assert.Error(t, rings[1].AddKey("before", members["before"], len(parties)-1, shares[0]))
+
+	type keySig struct {
+		keyID string
+		id    uint16
+		sig   []byte
+		err   error
+	}
+	keySigs := make(chan keySig, 6)
+	for keyID, ids := range members {
+		for _, id := range ids {
+			go func(keyID string, id uint16) {
+				// both keys sign in a session of the same id
+				sig, err := rings[id].Sign(context.Background(), keyID, "session", digest(msgToSign))
+				keySigs <- keySig{keyID: keyID, id: id, sig: sig, err: err}
+			}(keyID, id)
+		}
+	}
+	for range [6]struct{}{} {
+		ks := <-keySigs
+		if !assert.NoError(t, ks.err, "key %s of %d", ks.keyID, ks.id) {
+			continue
+		}
+		keyPK, err := rings[ks.id].TPubKey(ks.keyID)
+		assert.NoError(t, err)
+		assert.True(t, ecdsa.VerifyASN1(keyPK, digest(msgToSign), ks.sig))
+	}
+}
+
+func TestTwoParty(t *testing.T) {
+	pA := NewParty(1, logger("pA", t.Name()))
+	pB := NewParty(2, logger("pB", t.Name()))
+	pA.SetTwoParty()
+	pB.SetTwoParty()
+	assert.Error(t, pA.SetSigningProtocol(CGGPlus), "the two-party protocol is the only one of its keys")
+
+	parties := parties{pA, pB}
+	parties.init(senders(parties))
+
+	t.Logf("Running DKG")
+
+	t1 := time.Now()
+	shares, err := parties.keygen()
+	if !assert.NoError(t, err) {
+		return
+	}
+	t.Logf("DKG elapsed %s", time.Since(t1))
+
+	parties.init(senders(parties))
+	parties.setShareData(shares)
+
+	msgToSign := digest([]byte("bla bla"))
+	t1 = time.Now()
+	sigs, err := parties.sign(msgToSign)
+	assert.NoError(t, err)
+	t.Logf("Signing completed in %v", time.Since(t1))
+	assert.Len(t, sigs, 2)
+	assert.Equal(t, sigs[0], sigs[1])
+
+	pk, err := pA.TPubKey()
+	assert.NoError(t, err)
+	pkB, err := pB.TPubKey()
+	assert.NoError(t, err)
+	assert.True(t, pk.Equal(pkB))
+	assert.True(t, ecdsa.VerifyASN1(pk, msgToSign, sigs[0]))
 }
 
 func senders(parties parties) []Sender {
@@ -198,6 +455,67 @@ func senders(parties parties) []Sender {
 	return senders
 }
 
+// reshareSenders deliver every message to `to` only, as Reshare requires
+func reshareSenders(parties parties) []Sender {
+	var senders []Sender
+	for _, src := range parties {
+		src := src
+		sender := func(msgBytes []byte, broadcast bool, to uint16) {
+			messageSource := uint16(big.NewInt(0).SetBytes(src.id.Key).Uint64())
+			for _, dst := range parties {
+				if to == uint16(big.NewInt(0).SetBytes(dst.id.Key).Uint64()) {
+					dst.OnMsg(msgBytes, messageSource, broadcast)
+				}
+			}
+		}
+		senders = append(senders, sender)
+	}
+	return senders
+}
+
+// keyrings returns a keyring of each of the members of the keys, which delivers the messages of a key to its members
+func keyrings(members map[string][]uint16, testName string) map[uint16]*Keyring {
+	rings := make(map[uint16]*Keyring)
+	for _, ids := range members {
+		for _, id := range ids {
+			if _, ok := rings[id]; ok {
+				continue
+			}
+			src := id
+			rings[id] = NewKeyring(id, logger(fmt.Sprintf("ring%d", id), testName), func(keyID, sessionID string, msgBytes []byte, broadcast bool, to uint16) {
+				for _, dst := range members[keyID] {
+					if dst == src || (!broadcast && dst != to) {
+						continue
+					}
+					rings[dst].OnMsg(keyID, sessionID, msgBytes, src, broadcast)
+				}
+			})
+		}
+	}
+	return rings
+}
+
+func sessionSenders(parties parties) []SessionSender {
+	var senders []SessionSender
+	for _, src := range parties {
+		src := src
+		sender := func(sessionID string, msgBytes []byte, broadcast bool, to uint16) {
+			messageSource := uint16(big.NewInt(0).SetBytes(src.id.Key).Uint64())
+			for _, dst := range parties {
+				if dst.id == src.id {
+					continue
+				}
+				if !broadcast && to != uint16(big.NewInt(0).SetBytes(dst.id.Key).Uint64()) {
+					continue
+				}
+				dst.OnSessionMsg(sessionID, msgBytes, messageSource, broadcast)
+			}
+		}
+		senders = append(senders, sender)
+	}
+	return senders
+}
+
 func logger(id string, testName string) Logger {
 	logConfig := zap.NewDevelopmentConfig()
 	logger, _ := logConfig.Build()
@@ -0,0 +1,141 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package refresh
+
+import (
+	"fmt"
+	"runtime"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto"
+	"github.com/kisdex/mpc-lib/crypto/vss"
+	"github.com/kisdex/mpc-lib/ecdsa/keygen"
+	"github.com/kisdex/mpc-lib/test"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+const (
+	testParticipants = test.TestParticipants
+	testThreshold    = test.TestThreshold
+)
+
+func setUp(level string) {
+	if err := common.SetLogLevel(level); err != nil {
+		panic(err)
+	}
+}
+
+func TestE2EConcurrent(t *testing.T) {
+	setUp("info")
+
+	// PHASE: load keygen fixtures
+	keys, pIDs, err := keygen.LoadKeygenTestFixtures(testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	// PHASE: refresh
+	p2pCtx := tss.NewPeerContext(pIDs)
+	parties := make([]*LocalParty, 0, len(pIDs))
+
+	errCh := make(chan *tss.Error, len(pIDs))
+	outCh := make(chan tss.Message, len(pIDs))
+	endCh := make(chan *keygen.LocalPartySaveData, len(pIDs))
+
+	updater := test.SharedPartyUpdater
+
+	for i := 0; i < len(pIDs); i++ {
+		params := tss.NewParameters(tss.S256(), p2pCtx, pIDs[i], len(pIDs), testThreshold)
+		// re-use the pre-params of another fixture for speed; they only need to differ from the current ones
+		preParams := keys[(i+1)%len(keys)].LocalPreParams
+		P := NewLocalParty(params, keys[i], outCh, endCh, preParams).(*LocalParty)
+		parties = append(parties, P)
+		go func(P *LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	newKeys := make([]keygen.LocalPartySaveData, len(pIDs))
+	var ended int32
+refresh:
+	for {
+		fmt.Printf("ACTIVE GOROUTINES: %d\n", runtime.NumGoroutine())
+		select {
+		case err := <-errCh:
+			common.DefaultLogger().Errorf("Error: %s", err)
+			assert.FailNow(t, err.Error())
+			break refresh
+
+		case msg := <-outCh:
+			dest := msg.GetTo()
+			if dest == nil {
+				for _, P := range parties {
+					if P.PartyID().Index == msg.GetFrom().Index {
+						continue
+					}
+					go updater(P, msg, errCh)
+				}
+			} else {
+				if dest[0].Index == msg.GetFrom().Index {
+					t.Fatalf("party %d tried to send a message to itself (%d)", dest[0].Index, msg.GetFrom().Index)
+				}
+				go updater(parties[dest[0].Index], msg, errCh)
+			}
+
+		case save := <-endCh:
+			index, err := save.OriginalIndex()
+			assert.NoErrorf(t, err, "should not be an error getting a party's index from save data")
+			newKeys[index] = *save
+			if atomic.AddInt32(&ended, 1) == int32(len(pIDs)) {
+				t.Logf("Done. Received save data from %d participants", ended)
+				break refresh
+			}
+		}
+	}
+
+	// the public key is unchanged while every share and Paillier key is new
+	shares := make(vss.Shares, len(newKeys))
+	for i, newKey := range newKeys {
+		assert.True(t, newKey.ECDSAPub.Equals(keys[0].ECDSAPub), "the public key must not change")
+		assert.NotEqual(t, 0, newKey.Xi.Cmp(keys[i].Xi), "the share must change")
+		assert.Equal(t, 0, newKey.PaillierSK.N.Cmp(keys[(i+1)%len(keys)].PaillierSK.N))
+		for j := range newKeys {
+			assert.True(t, newKey.BigXj[j].Equals(crypto.ScalarBaseMult(tss.S256(), newKeys[j].Xi)))
+			assert.Equal(t, 0, newKey.PaillierPKs[j].N.Cmp(newKeys[j].PaillierSK.N))
+			assert.Equal(t, 0, newKey.NTildej[j].Cmp(newKeys[j].NTildei))
+		}
+		shares[i] = &vss.Share{Threshold: testThreshold, ID: newKey.ShareID, Share: newKey.Xi}
+	}
+	secret, err := shares.ReConstruct(tss.S256())
+	assert.NoError(t, err)
+	assert.True(t, crypto.ScalarBaseMult(tss.S256(), secret).Equals(keys[0].ECDSAPub), "the new shares must reconstruct the same key")
+}
+
+func FuzzMessages(f *testing.F) {
+	setUp("error")
+	keys, pIDs, err := keygen.LoadKeygenTestFixtures(testParticipants)
+	if err != nil {
+		f.Fatal(err)
+	}
+	p2pCtx := tss.NewPeerContext(pIDs)
+	outCh := make(chan tss.Message, len(pIDs)*len(pIDs))
+	endCh := make(chan *keygen.LocalPartySaveData, len(pIDs))
+	parties := make([]tss.Party, 0, len(pIDs))
+	for i := range pIDs {
+		params := tss.NewParameters(tss.S256(), p2pCtx, pIDs[i], len(pIDs), testThreshold)
+		parties = append(parties, NewLocalParty(params, keys[i], outCh, endCh, keys[(i+1)%len(keys)].LocalPreParams))
+	}
+	transcript, tssErr := test.RunSequentially(parties, outCh)
+	if tssErr != nil {
+		f.Fatal(tssErr)
+	}
+	test.FuzzWireMessages(f, tss.S256(), transcript)
+}
@@ -0,0 +1,137 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package refresh
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/kisdex/mpc-lib/crypto"
+	cmts "github.com/kisdex/mpc-lib/crypto/commitments"
+	"github.com/kisdex/mpc-lib/crypto/dlnproof"
+	"github.com/kisdex/mpc-lib/crypto/vss"
+	"github.com/kisdex/mpc-lib/ecdsa/keygen"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+var (
+	zero = big.NewInt(0)
+)
+
+// round 1 represents round 1 of the key refresh protocol (Canetti, Gennaro, Goldfeder, Makriyannis, Peled; 2021)
+func newRound1(params *tss.Parameters, input, save *keygen.LocalPartySaveData, temp *localTempData, out chan<- tss.Message, end chan<- *keygen.LocalPartySaveData) tss.Round {
+	return &round1{
+		&base{params, input, save, temp, out, end, make([]bool, len(params.Parties().IDs())), false, 1}}
+}
+
+func (round *round1) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started"))
+	}
+	round.number = 1
+	round.started = true
+	round.resetOK()
+
+	Pi := round.PartyID()
+	i := Pi.Index
+
+	// every share must be refreshed at once, otherwise the shares of the parties left out become useless
+	if len(round.input.Ks) != round.PartyCount() {
+		return round.WrapError(errors.New("key refresh requires every party of the committee"))
+	}
+	if round.input.Xi == nil || round.input.ECDSAPub == nil {
+		return round.WrapError(errors.New("the key to refresh is missing its secret share or public key"))
+	}
+
+	// 1. share the secret 0 so that the key stays the same when the shares are added to the old ones
+	ids := round.Parties().IDs().Keys()
+	vs, shares, err := vss.CreateZeroSharing(round.Params().EC(), round.Threshold(), ids)
+	if err != nil {
+		return round.WrapError(err, Pi)
+	}
+	round.save.Ks = ids
+	round.save.ShareID = ids[i]
+	round.save.ECDSAPub = round.input.ECDSAPub
+
+	// make commitment -> (C, D)
+	pGFlat, err := crypto.CompressECPoints(vs)
+	if err != nil {
+		return round.WrapError(err, Pi)
+	}
+	cmt := cmts.NewHashCommitment(pGFlat...)
+
+	// 2. generate new Paillier keys and Ring-Pedersen parameters
+	// use the pre-params if they were provided to the LocalParty constructor
+	var preParams *keygen.LocalPreParams
+	if round.save.LocalPreParams.ValidateWithProof() {
+		preParams = &round.save.LocalPreParams
+		if err := preParams.ValidateForLevel(round.SecurityLevel()); err != nil {
+			return round.WrapError(err, Pi)
+		}
+	} else {
+		preParams, err = keygen.GeneratePreParamsFor(round.Parameters)
+		if err != nil {
+			return round.WrapError(errors.New("pre-params generation failed"), Pi)
+		}
+		round.temp.preParams = preParams
+	}
+	if round.input.PaillierSK != nil && preParams.PaillierSK.N.Cmp(round.input.PaillierSK.N) == 0 {
+		return round.WrapError(errors.New("the new pre-params must differ from the ones of the key"), Pi)
+	}
+	round.save.LocalPreParams = *preParams
+	round.save.NTildej[i] = preParams.NTildei
+	round.save.H1j[i], round.save.H2j[i] = preParams.H1i, preParams.H2i
+	round.save.PaillierPKs[i] = &preParams.PaillierSK.PublicKey
+
+	dlnProof1 := dlnproof.NewDLNProof(preParams.H1i, preParams.H2i, preParams.Alpha, preParams.P, preParams.Q, preParams.NTildei)
+	dlnProof2 := dlnproof.NewDLNProof(preParams.H2i, preParams.H1i, preParams.Beta, preParams.P, preParams.Q, preParams.NTildei)
+
+	round.temp.ssidNonce = new(big.Int).SetUint64(0)
+	ssid, err := round.getSSID()
+	if err != nil {
+		return round.WrapError(errors.New("failed to generate ssid"))
+	}
+	round.temp.ssid = ssid
+	round.temp.vs = vs
+	round.temp.shares = shares
+	round.temp.deCommitPolyG = cmt.D
+
+	// BROADCAST commitments, paillier pk + proof; round 1 message
+	msg, err := NewRefreshRound1Message(
+		round.PartyID(), cmt.C, &preParams.PaillierSK.PublicKey, preParams.NTildei, preParams.H1i, preParams.H2i, dlnProof1, dlnProof2)
+	if err != nil {
+		return round.WrapError(err, Pi)
+	}
+	round.temp.rfRound1Messages[i] = msg
+	round.SendMessage(round.out, msg)
+	return nil
+}
+
+func (round *round1) CanAccept(msg tss.ParsedMessage) bool {
+	if _, ok := msg.Content().(*RefreshRound1Message); ok {
+		return msg.IsBroadcast()
+	}
+	return false
+}
+
+func (round *round1) Update() (bool, *tss.Error) {
+	for j, msg := range round.temp.rfRound1Messages {
+		if round.ok[j] {
+			continue
+		}
+		if msg == nil || !round.CanAccept(msg) {
+			return false, nil
+		}
+		round.ok[j] = true
+	}
+	return true, nil
+}
+
+func (round *round1) NextRound() tss.Round {
+	round.started = false
+	return &round2{round}
+}
@@ -0,0 +1,176 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package refresh
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto"
+	"github.com/kisdex/mpc-lib/crypto/commitments"
+	"github.com/kisdex/mpc-lib/crypto/vss"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+func (round *round3) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started"))
+	}
+	round.number = 3
+	round.started = true
+	round.resetOK()
+
+	Ps := round.Parties().IDs()
+	PIdx := round.PartyID().Index
+
+	// 1. verify the de-commitments, proofs and zero shares of every Pj
+	type vssOut struct {
+		unWrappedErr error
+		pjVs         vss.Vs
+	}
+	chs := make([]chan vssOut, len(Ps))
+	pool := round.NewWorkerPool()
+	for j := range Ps {
+		if j == PIdx {
+			continue
+		}
+		chs[j] = make(chan vssOut, 1)
+		ContextJ := common.AppendBigIntToBytesSlice(round.temp.ssid, big.NewInt(int64(j)))
+		ch := chs[j]
+		pool.GoFor(Ps[j], func() {
+			KGCj := round.temp.KGCs[j]
+			r2msg2 := round.temp.rfRound2Message2s[j].Content().(*RefreshRound2Message2)
+			KGDj := r2msg2.UnmarshalDeCommitment()
+			cmtDeCmt := commitments.HashCommitDecommit{C: KGCj, D: KGDj}
+			ok, flatPolyGs := cmtDeCmt.DeCommit()
+			if !ok || flatPolyGs == nil {
+				ch <- vssOut{errors.New("de-commitment verify failed"), nil}
+				return
+			}
+			PjVs, err := crypto.DecompressECPoints(round.Params().EC(), flatPolyGs)
+			if err != nil {
+				ch <- vssOut{err, nil}
+				return
+			}
+			if !round.Parameters.NoProofMod() {
+				modProof, err := r2msg2.UnmarshalModProof()
+				if err != nil || !modProof.Verify(ContextJ, round.save.PaillierPKs[j].N) {
+					ch <- vssOut{errors.New("modProof verify failed"), nil}
+					return
+				}
+			}
+			r2msg1 := round.temp.rfRound2Message1s[j].Content().(*RefreshRound2Message1)
+			PjShare := vss.Share{
+				Threshold: round.Threshold(),
+				ID:        round.PartyID().KeyInt(),
+				Share:     r2msg1.UnmarshalShare(),
+			}
+			if ok = PjShare.VerifyZeroSharing(round.Params().EC(), round.Threshold(), PjVs); !ok {
+				ch <- vssOut{errors.New("vss verify failed"), nil}
+				return
+			}
+			if !round.Parameters.NoProofFac() {
+				facProof, err := r2msg1.UnmarshalFacProof()
+				if err != nil || !facProof.Verify(ContextJ, round.EC(), round.save.PaillierPKs[j].N, round.save.NTildei,
+					round.save.H1i, round.save.H2i) {
+					ch <- vssOut{errors.New("facProof verify failed"), nil}
+					return
+				}
+			}
+			ch <- vssOut{nil, PjVs}
+		})
+	}
+
+	// consume the channels (end the goroutines)
+	vssResults := make([]vssOut, len(Ps))
+	{
+		culprits := make([]*tss.PartyID, 0, len(Ps)) // who caused the error(s)
+		var multiErr error
+		for j, Pj := range Ps {
+			if j == PIdx {
+				continue
+			}
+			vssResults[j] = <-chs[j]
+			if err := vssResults[j].unWrappedErr; err != nil {
+				culprits = append(culprits, Pj)
+				multiErr = multierror.Append(multiErr, err)
+			}
+		}
+		if len(culprits) > 0 {
+			return round.WrapError(multiErr, culprits...)
+		}
+	}
+
+	// 2. add the zero shares to xi
+	modQ := common.ModInt(round.Params().EC().Params().N)
+	xi := modQ.Add(round.input.Xi, round.temp.shares[PIdx].Share)
+	for j := range Ps {
+		if j == PIdx {
+			continue
+		}
+		r2msg1 := round.temp.rfRound2Message1s[j].Content().(*RefreshRound2Message1)
+		xi = modQ.Add(xi, r2msg1.UnmarshalShare())
+	}
+
+	// 3. sum the commitments of every zero sharing
+	Vc := make(vss.Vs, round.Threshold())
+	copy(Vc, round.temp.vs)
+	{
+		var err error
+		culprits := make([]*tss.PartyID, 0, len(Ps))
+		for j, Pj := range Ps {
+			if j == PIdx {
+				continue
+			}
+			for c := range Vc {
+				if Vc[c], err = Vc[c].Add(vssResults[j].pjVs[c]); err != nil {
+					culprits = append(culprits, Pj)
+				}
+			}
+		}
+		if len(culprits) > 0 {
+			return round.WrapError(errors.New("adding PjVs[c] to Vc[c] resulted in a point not on the curve"), culprits...)
+		}
+	}
+
+	// 4. compute the new Xj for each Pj
+	bigXj := round.save.BigXj
+	for j, Pj := range Ps {
+		delta, err := vss.ZeroSharingPublicShare(round.Params().EC(), Vc, Pj.KeyInt())
+		if err != nil {
+			return round.WrapError(err, Pj)
+		}
+		if bigXj[j], err = round.input.BigXj[j].Add(delta); err != nil {
+			return round.WrapError(errors.New("adding the zero share to BigXj resulted in a point not on the curve"), Pj)
+		}
+	}
+	if !crypto.ScalarBaseMult(round.Params().EC(), xi).Equals(bigXj[PIdx]) {
+		return round.WrapError(errors.New("the refreshed share does not match its public share"))
+	}
+	round.save.Xi = xi
+
+	round.temp.preParams = nil
+	round.end <- round.save
+	return nil
+}
+
+func (round *round3) CanAccept(msg tss.ParsedMessage) bool {
+	// not expecting any incoming messages in this round
+	return false
+}
+
+func (round *round3) Update() (bool, *tss.Error) {
+	// not expecting any incoming messages in this round
+	return false, nil
+}
+
+func (round *round3) NextRound() tss.Round {
+	return nil // finished!
+}
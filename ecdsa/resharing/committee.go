@@ -0,0 +1,58 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package resharing
+
+import (
+	"fmt"
+
+	"github.com/kisdex/mpc-lib/ecdsa/keygen"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+// NewLocalParties creates the parties of both committees of `reshare` when they all run in one process, e.g. in tests
+// or with a single operator. `keys` are the save data of the old committee in the order of its parties; `preParams`
+// are optional pre-parameters of the new committee in the order of its parties, which generate their own when nil.
+// A party of both committees is a single party in both slices, which keeps the pre-parameters of its key.
+func NewLocalParties(
+	reshare *tss.Reshare,
+	keys []keygen.LocalPartySaveData,
+	preParams []keygen.LocalPreParams,
+	out chan<- tss.Message,
+	end chan<- *keygen.LocalPartySaveData,
+) (oldCommittee, newCommittee []tss.Party, err error) {
+	if len(keys) != len(reshare.OldParties) {
+		return nil, nil, fmt.Errorf("expected the save data of %d old parties, got %d", len(reshare.OldParties), len(keys))
+	}
+	if preParams != nil && len(preParams) != len(reshare.NewParties) {
+		return nil, nil, fmt.Errorf("expected the pre-params of %d new parties, got %d", len(reshare.NewParties), len(preParams))
+	}
+	oldCommittee = make([]tss.Party, 0, len(reshare.OldParties))
+	for j, pID := range reshare.OldParties {
+		params, err := reshare.Parameters(pID)
+		if err != nil {
+			return nil, nil, err
+		}
+		oldCommittee = append(oldCommittee, NewLocalParty(params, keys[j], out, end))
+	}
+	newCommittee = make([]tss.Party, 0, len(reshare.NewParties))
+	for j, pID := range reshare.NewParties {
+		if oldPID := reshare.OldParties.FindByKey(pID.KeyInt()); oldPID != nil {
+			newCommittee = append(newCommittee, oldCommittee[oldPID.Index])
+			continue
+		}
+		params, err := reshare.Parameters(pID)
+		if err != nil {
+			return nil, nil, err
+		}
+		save := keygen.NewLocalPartySaveData(len(reshare.NewParties))
+		if preParams != nil {
+			save.LocalPreParams = preParams[j]
+		}
+		newCommittee = append(newCommittee, NewLocalParty(params, save, out, end))
+	}
+	return oldCommittee, newCommittee, nil
+}
@@ -22,6 +22,7 @@ import (
 // Implements Stringer
 var _ tss.Party = (*LocalParty)(nil)
 var _ fmt.Stringer = (*LocalParty)(nil)
+var _ tss.Zeroizer = (*LocalParty)(nil)
 
 type (
 	LocalParty struct {
@@ -68,6 +69,9 @@ type (
 // The `key` is read from and/or written to depending on whether this party is part of the old or the new committee.
 // You may optionally generate and set the LocalPreParams if you would like to use pre-generated safe primes and Paillier secret.
 // (This is similar to providing the `optionalPreParams` to `keygen.LocalParty`).
+// The old committee may be any t+1 or more parties of the key, e.g. a quorum chosen with tss.Reshare.WithOldQuorum.
+// A party of both committees runs as this one party with its old key, and sends with its id of the committee that
+// each message is from; see tss.ReSharingParameters.OldCommitteePartyID.
 func NewLocalParty(
 	params *tss.ReSharingParameters,
 	key keygen.LocalPartySaveData,
@@ -107,6 +111,17 @@ func (p *LocalParty) FirstRound() tss.Round {
 	return newRound1(p.params, &p.input, &p.save, &p.temp, p.out, p.end)
 }
 
+// Zeroize wipes the shares of the resharing, see tss.Zeroizer
+func (p *LocalParty) Zeroize() { p.temp.Zeroize() }
+
+// Zeroize wipes the shares that an old party dealt to the new committee and the new key share, unless it was saved
+func (temp *localTempData) Zeroize() {
+	for _, share := range temp.NewShares {
+		common.Zeroize(share.Share)
+	}
+	common.Zeroize(temp.newXi)
+}
+
 func (p *LocalParty) Start() *tss.Error {
 	return tss.BaseStart(p, TaskName)
 }
@@ -116,7 +131,7 @@ func (p *LocalParty) Update(msg tss.ParsedMessage) (ok bool, err *tss.Error) {
 }
 
 func (p *LocalParty) UpdateFromBytes(wireBytes []byte, from *tss.PartyID, isBroadcast bool) (bool, *tss.Error) {
-	msg, err := tss.ParseWireMessage(wireBytes, from, isBroadcast)
+	msg, err := messageTypes.ParseWireMessage(wireBytes, from, isBroadcast)
 	if err != nil {
 		return false, p.WrapError(err)
 	}
@@ -167,7 +182,7 @@ func (p *LocalParty) StoreMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
 	case *DGRound4Message2:
 		p.temp.dgRound4Message2s[fromPIdx] = msg
 	default: // unrecognised message, just ignore!
-		common.Logger.Warningf("unrecognised message ignored: %v", msg)
+		p.params.Logger().Warnf("unrecognised message ignored: %v", msg)
 		return false, nil
 	}
 	return true, nil
@@ -17,7 +17,6 @@ import (
 	"sync/atomic"
 	"testing"
 
-	"github.com/ipfs/go-log"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/kisdex/mpc-lib/common"
@@ -35,7 +34,7 @@ const (
 )
 
 func setUp(level string) {
-	if err := log.SetLogLevel("tss-lib", level); err != nil {
+	if err := common.SetLogLevel(level); err != nil {
 		panic(err)
 	}
 }
@@ -57,7 +56,7 @@ func TestE2EConcurrent(t *testing.T) {
 	// init the new parties; re-use the fixture pre-params for speed
 	fixtures, _, err := keygen.LoadKeygenTestFixtures(testParticipants)
 	if err != nil {
-		common.Logger.Info("No test fixtures were found, so the safe primes will be generated from scratch. This may take a while...")
+		common.DefaultLogger().Infof("No test fixtures were found, so the safe primes will be generated from scratch. This may take a while...")
 	}
 	newPIDs := tss.GenerateTestPartyIDs(testParticipants)
 	newP2PCtx := tss.NewPeerContext(newPIDs)
@@ -82,10 +81,6 @@ func TestE2EConcurrent(t *testing.T) {
 	// init the new parties
 	for j, pID := range newPIDs {
 		params := tss.NewReSharingParameters(tss.S256(), oldP2PCtx, newP2PCtx, pID, testParticipants, threshold, newPCount, newThreshold)
-		// do not use in untrusted setting
-		params.SetNoProofMod()
-		// do not use in untrusted setting
-		params.SetNoProofFac()
 		save := keygen.NewLocalPartySaveData(newPCount)
 		if j < len(fixtures) && len(newPIDs) <= len(fixtures) {
 			save.LocalPreParams = fixtures[j].LocalPreParams
@@ -118,7 +113,7 @@ func TestE2EConcurrent(t *testing.T) {
 		fmt.Printf("ACTIVE GOROUTINES: %d\n", runtime.NumGoroutine())
 		select {
 		case err := <-errCh:
-			common.Logger.Errorf("Error: %s", err)
+			common.DefaultLogger().Errorf("Error: %s", err)
 			assert.FailNow(t, err.Error())
 			return
 
@@ -194,7 +189,7 @@ signing:
 		fmt.Printf("ACTIVE GOROUTINES: %d\n", runtime.NumGoroutine())
 		select {
 		case err := <-signErrCh:
-			common.Logger.Errorf("Error: %s", err)
+			common.DefaultLogger().Errorf("Error: %s", err)
 			assert.FailNow(t, err.Error())
 			return
 
@@ -254,3 +249,155 @@ signing:
 		}
 	}
 }
+
+func BenchmarkReshare(b *testing.B) {
+	setUp("error")
+	fixtures, _, err := keygen.LoadKeygenBenchmarkFixtures()
+	if err != nil {
+		b.Fatal(err)
+	}
+	preParams := make([]keygen.LocalPreParams, len(fixtures))
+	for i, fixture := range fixtures {
+		preParams[i] = fixture.LocalPreParams
+	}
+	for _, size := range test.BenchmarkSizes(len(fixtures)) {
+		// the t+1 first parties of a keygen of this size reshare to a new committee of the same size
+		keys, pIDs, err := keygen.GenerateKeygenTestFixturesWithPreParams(tss.S256(), size.Threshold, tss.SecurityLevel112, 1, preParams[:size.Parties])
+		if err != nil {
+			b.Fatal(err)
+		}
+		oldKeys, oldPIDs := keys[:size.Threshold+1], pIDs[:size.Threshold+1]
+		oldP2PCtx := tss.NewPeerContext(oldPIDs)
+		b.Run(size.String(), func(b *testing.B) {
+			metrics := test.NewBenchmarkMetrics(len(oldPIDs) + size.Parties)
+			for i := 0; i < b.N; i++ {
+				newPIDs := tss.GenerateTestPartyIDs(size.Parties)
+				newP2PCtx := tss.NewPeerContext(newPIDs)
+				outCh := make(chan tss.Message, (len(oldPIDs)+size.Parties)*size.Parties)
+				endCh := make(chan *keygen.LocalPartySaveData, len(oldPIDs)+size.Parties)
+				oldCommittee := make([]tss.Party, 0, len(oldPIDs))
+				for j, pID := range oldPIDs {
+					params := tss.NewReSharingParameters(tss.S256(), oldP2PCtx, newP2PCtx, pID, size.Parties, size.Threshold, size.Parties, size.Threshold)
+					params.SetMetrics(metrics)
+					oldCommittee = append(oldCommittee, NewLocalParty(params, oldKeys[j], outCh, endCh))
+				}
+				newCommittee := make([]tss.Party, 0, size.Parties)
+				for j, pID := range newPIDs {
+					params := tss.NewReSharingParameters(tss.S256(), oldP2PCtx, newP2PCtx, pID, size.Parties, size.Threshold, size.Parties, size.Threshold)
+					params.SetMetrics(metrics)
+					save := keygen.NewLocalPartySaveData(size.Parties)
+					save.LocalPreParams = preParams[j]
+					newCommittee = append(newCommittee, NewLocalParty(params, save, outCh, endCh))
+				}
+				if _, err := test.RunResharingSequentially(oldCommittee, newCommittee, outCh); err != nil {
+					b.Fatal(err)
+				}
+			}
+			metrics.Report(b)
+		})
+	}
+}
+
+func FuzzMessages(f *testing.F) {
+	setUp("error")
+	fixtures, oldPIDs, err := keygen.LoadKeygenTestFixtures(testThreshold + 1)
+	if err != nil {
+		f.Fatal(err)
+	}
+	newPIDs := tss.GenerateTestPartyIDs(len(fixtures))
+	oldP2PCtx, newP2PCtx := tss.NewPeerContext(oldPIDs), tss.NewPeerContext(newPIDs)
+	outCh := make(chan tss.Message, (len(oldPIDs)+len(newPIDs))*len(newPIDs))
+	endCh := make(chan *keygen.LocalPartySaveData, len(oldPIDs)+len(newPIDs))
+	oldCommittee := make([]tss.Party, 0, len(oldPIDs))
+	for j, pID := range oldPIDs {
+		params := tss.NewReSharingParameters(tss.S256(), oldP2PCtx, newP2PCtx, pID, testParticipants, testThreshold, len(newPIDs), testThreshold)
+		oldCommittee = append(oldCommittee, NewLocalParty(params, fixtures[j], outCh, endCh))
+	}
+	newCommittee := make([]tss.Party, 0, len(newPIDs))
+	for j, pID := range newPIDs {
+		params := tss.NewReSharingParameters(tss.S256(), oldP2PCtx, newP2PCtx, pID, testParticipants, testThreshold, len(newPIDs), testThreshold)
+		save := keygen.NewLocalPartySaveData(len(newPIDs))
+		save.LocalPreParams = fixtures[j].LocalPreParams
+		newCommittee = append(newCommittee, NewLocalParty(params, save, outCh, endCh))
+	}
+	transcript, tssErr := test.RunResharingSequentially(oldCommittee, newCommittee, outCh)
+	if tssErr != nil {
+		f.Fatal(tssErr)
+	}
+	test.FuzzWireMessages(f, tss.S256(), transcript)
+}
+
+func TestCommitteeChanges(t *testing.T) {
+	setUp("error")
+	fixtures, pIDs, err := keygen.LoadKeygenTestFixtures(testParticipants)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the parties that stay in the committee take part in both committees with their pre-params
+	t.Run("AddParty", func(t *testing.T) {
+		oldKeys, oldPIDs, _ := keygen.LoadKeygenTestFixtures(testThreshold + 1)
+		extra := tss.GenerateTestPartyIDs(1, testParticipants)[0]
+		reshare, err := tss.AddParty(tss.S256(), oldPIDs, testThreshold, extra)
+		if !assert.NoError(t, err) {
+			return
+		}
+		preParams := make([]keygen.LocalPreParams, len(reshare.NewParties))
+		preParams[extra.Index] = fixtures[testThreshold+1].LocalPreParams
+		testCommitteeChange(t, reshare, oldKeys, preParams)
+	})
+	t.Run("RemoveParty", func(t *testing.T) {
+		oldKeys, _, _ := keygen.LoadKeygenTestFixtures(testParticipants)
+		reshare, err := tss.RemoveParty(tss.S256(), pIDs, testThreshold, pIDs[0])
+		if assert.NoError(t, err) {
+			testCommitteeChange(t, reshare, oldKeys, nil)
+		}
+	})
+	t.Run("RemoveOfflineParty", func(t *testing.T) {
+		// the remaining parties reshare without the removed one, which is the middle one of the committee
+		oldKeys, _, _ := keygen.LoadKeygenTestFixtures(testParticipants)
+		reshare, err := tss.RemoveParty(tss.S256(), pIDs, testThreshold, pIDs[1])
+		if !assert.NoError(t, err) {
+			return
+		}
+		reshare, err = reshare.WithOldQuorum(pIDs.Exclude(pIDs[1]))
+		if assert.NoError(t, err) {
+			testCommitteeChange(t, reshare, []keygen.LocalPartySaveData{oldKeys[0], oldKeys[2]}, nil)
+		}
+	})
+	t.Run("ChangeThreshold", func(t *testing.T) {
+		oldKeys, _, _ := keygen.LoadKeygenTestFixtures(testParticipants)
+		reshare, err := tss.ChangeThreshold(tss.S256(), pIDs, testThreshold, testThreshold+1)
+		if assert.NoError(t, err) {
+			testCommitteeChange(t, reshare, oldKeys, nil)
+		}
+	})
+}
+
+func testCommitteeChange(t *testing.T, reshare *tss.Reshare, oldKeys []keygen.LocalPartySaveData, preParams []keygen.LocalPreParams) {
+	pCount := len(reshare.OldParties) + len(reshare.NewParties)
+	outCh := make(chan tss.Message, pCount*len(reshare.NewParties))
+	endCh := make(chan *keygen.LocalPartySaveData, pCount)
+	pub := oldKeys[0].ECDSAPub
+	oldCommittee, newCommittee, err := NewLocalParties(reshare, oldKeys, preParams, outCh, endCh)
+	if !assert.NoError(t, err) {
+		return
+	}
+	if _, err := test.RunResharingSequentially(oldCommittee, newCommittee, outCh); err != nil {
+		t.Fatal(err)
+	}
+	newKeys := make([]keygen.LocalPartySaveData, 0, len(reshare.NewParties))
+	for len(newKeys) < len(reshare.NewParties) {
+		save := <-endCh
+		if save.Xi == nil {
+			continue // of the old committee
+		}
+		assert.NoError(t, save.Validate(tss.S256()))
+		assert.True(t, save.ECDSAPub.Equals(pub), "the public key must not change")
+		newKeys = append(newKeys, *save)
+	}
+	assert.NoError(t, keygen.VerifyReconstruction(tss.S256(), newKeys[:reshare.NewThreshold+1]))
+	if 1 < reshare.NewThreshold {
+		assert.ErrorIs(t, keygen.VerifyReconstruction(tss.S256(), newKeys[:reshare.NewThreshold]), keygen.ErrReconstructionMismatch)
+	}
+}
@@ -18,22 +18,23 @@ import (
 	"github.com/kisdex/mpc-lib/crypto/modproof"
 	"github.com/kisdex/mpc-lib/crypto/paillier"
 	"github.com/kisdex/mpc-lib/crypto/vss"
+	"github.com/kisdex/mpc-lib/crypto/zkproofs"
 	"github.com/kisdex/mpc-lib/tss"
 )
 
 // These messages were generated from Protocol Buffers definitions into ecdsa-resharing.pb.go
 
 var (
-	// Ensure that signing messages implement ValidateBasic
-	_ = []tss.MessageContent{
-		(*DGRound1Message)(nil),
-		(*DGRound2Message1)(nil),
-		(*DGRound2Message2)(nil),
-		(*DGRound3Message1)(nil),
-		(*DGRound3Message2)(nil),
-		(*DGRound4Message1)(nil),
-		(*DGRound4Message2)(nil),
-	}
+	// Ensure that signing messages implement ValidateBasic, and accept only them from the wire
+	messageTypes = tss.NewMessageTypes(
+		&DGRound1Message{},
+		&DGRound2Message1{},
+		&DGRound2Message2{},
+		&DGRound3Message1{},
+		&DGRound3Message2{},
+		&DGRound4Message1{},
+		&DGRound4Message2{},
+	)
 )
 
 // ----- //
@@ -52,8 +53,7 @@ func NewDGRound1Message(
 		IsToOldCommittee: false,
 	}
 	content := &DGRound1Message{
-		EcdsaPubX:   ecdsaPub.X().Bytes(),
-		EcdsaPubY:   ecdsaPub.Y().Bytes(),
+		EcdsaPub:    ecdsaPub.Bytes(),
 		VCommitment: vct.Bytes(),
 		Ssid:        ssid,
 	}
@@ -63,16 +63,12 @@ func NewDGRound1Message(
 
 func (m *DGRound1Message) ValidateBasic() bool {
 	return m != nil &&
-		common.NonEmptyBytes(m.EcdsaPubX) &&
-		common.NonEmptyBytes(m.EcdsaPubY) &&
+		common.NonEmptyBytes(m.EcdsaPub) &&
 		common.NonEmptyBytes(m.VCommitment)
 }
 
 func (m *DGRound1Message) UnmarshalECDSAPub(ec elliptic.Curve) (*crypto.ECPoint, error) {
-	return crypto.NewECPoint(
-		ec,
-		new(big.Int).SetBytes(m.EcdsaPubX),
-		new(big.Int).SetBytes(m.EcdsaPubY))
+	return crypto.NewECPointFromBytes(ec, m.GetEcdsaPub())
 }
 
 func (m *DGRound1Message) UnmarshalVCommitment() *big.Int {
@@ -92,6 +88,8 @@ func NewDGRound2Message1(
 	modProof *modproof.ProofMod,
 	NTildei, H1i, H2i *big.Int,
 	dlnProof1, dlnProof2 *dlnproof.Proof,
+	prmProof *zkproofs.PrmProof,
+	echo [][]byte,
 ) (tss.ParsedMessage, error) {
 	meta := tss.MessageRouting{
 		From:             from,
@@ -116,6 +114,8 @@ func NewDGRound2Message1(
 		H2:         H2i.Bytes(),
 		Dlnproof_1: dlnProof1Bz,
 		Dlnproof_2: dlnProof2Bz,
+		PrmProof:   prmProof.Bytes(),
+		Echo:       echo,
 	}
 	msg := tss.NewMessageWrapper(meta, content)
 	return tss.NewMessage(meta, content, msg), nil
@@ -131,13 +131,12 @@ func (m *DGRound2Message1) ValidateBasic() bool {
 		common.NonEmptyBytes(m.H2) &&
 		// expected len of dln proof = sizeof(int64) + len(alpha) + len(t)
 		common.NonEmptyMultiBytes(m.GetDlnproof_1(), 2+(dlnproof.Iterations*2)) &&
-		common.NonEmptyMultiBytes(m.GetDlnproof_2(), 2+(dlnproof.Iterations*2))
+		common.NonEmptyMultiBytes(m.GetDlnproof_2(), 2+(dlnproof.Iterations*2)) &&
+		common.NonEmptyMultiBytes(m.GetPrmProof(), zkproofs.PrmProofParts)
 }
 
 func (m *DGRound2Message1) UnmarshalPaillierPK() *paillier.PublicKey {
-	return &paillier.PublicKey{
-		N: new(big.Int).SetBytes(m.PaillierN),
-	}
+	return paillier.NewPublicKey(new(big.Int).SetBytes(m.PaillierN))
 }
 
 func (m *DGRound2Message1) UnmarshalNTilde() *big.Int {
@@ -164,6 +163,10 @@ func (m *DGRound2Message1) UnmarshalDLNProof2() (*dlnproof.Proof, error) {
 	return dlnproof.UnmarshalDLNProof(m.GetDlnproof_2())
 }
 
+func (m *DGRound2Message1) UnmarshalPrmProof() (*zkproofs.PrmProof, error) {
+	return zkproofs.PrmProofFromBytes(m.GetPrmProof())
+}
+
 // ----- //
 
 func NewDGRound2Message2(
@@ -266,6 +269,7 @@ func NewDGRound4Message1(
 	to *tss.PartyID,
 	from *tss.PartyID,
 	proof *facproof.ProofFac,
+	echo [][]byte,
 ) tss.ParsedMessage {
 	meta := tss.MessageRouting{
 		From:             from,
@@ -276,6 +280,7 @@ func NewDGRound4Message1(
 	pfBzs := proof.Bytes()
 	content := &DGRound4Message1{
 		FacProof: pfBzs[:],
+		Echo:     echo,
 	}
 	msg := tss.NewMessageWrapper(meta, content)
 	return tss.NewMessage(meta, content, msg)
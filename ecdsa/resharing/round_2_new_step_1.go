@@ -35,14 +35,14 @@ func (round *round2) Start() *tss.Error {
 		return nil
 	}
 
-	Pi := round.PartyID()
+	Pi := round.NewCommitteePartyID()
 	i := Pi.Index
 
 	// check consistency of SSID
 	r1msg := round.temp.dgRound1Messages[0].Content().(*DGRound1Message)
 	SSID := r1msg.UnmarshalSSID()
 	for j, Pj := range round.OldParties().IDs() {
-		if j == 0 || j == i {
+		if j == 0 {
 			continue
 		}
 		r1msg := round.temp.dgRound1Messages[j].Content().(*DGRound1Message)
@@ -55,9 +55,9 @@ func (round *round2) Start() *tss.Error {
 
 	// 2. "broadcast" "ACK" members of the OLD committee
 	r2msg1 := NewDGRound2Message2(
-		round.OldParties().IDs().Exclude(round.PartyID()), round.PartyID())
+		round.OldParties().IDs().Exclude(Pi), Pi)
 	round.temp.dgRound2Message2s[i] = r2msg1
-	round.out <- r2msg1
+	round.SendMessage(round.out, r2msg1)
 
 	// 1.
 	// generate Paillier public key E_i, private key and proof
@@ -70,9 +70,12 @@ func (round *round2) Start() *tss.Error {
 			errors.New("`optionalPreParams` failed to validate; it might have been generated with an older version of tss-lib"))
 	} else if round.save.LocalPreParams.ValidateWithProof() {
 		preParams = &round.save.LocalPreParams
+		if err := preParams.ValidateForLevel(round.SecurityLevel()); err != nil {
+			return round.WrapError(err, Pi)
+		}
 	} else {
 		var err error
-		preParams, err = keygen.GeneratePreParams(round.SafePrimeGenTimeout(), round.Concurrency())
+		preParams, err = keygen.GeneratePreParamsFor(round.Parameters)
 		if err != nil {
 			return round.WrapError(errors.New("pre-params generation failed"), Pi)
 		}
@@ -102,14 +105,16 @@ func (round *round2) Start() *tss.Error {
 			return round.WrapError(err, Pi)
 		}
 	}
+	prmProof := preParams.PrmProof(ContextI)
 	r2msg2, err := NewDGRound2Message1(
-		round.NewParties().IDs().Exclude(round.PartyID()), round.PartyID(),
-		&preParams.PaillierSK.PublicKey, modProof, preParams.NTildei, preParams.H1i, preParams.H2i, dlnProof1, dlnProof2)
+		round.NewParties().IDs().Exclude(Pi), Pi,
+		&preParams.PaillierSK.PublicKey, modProof, preParams.NTildei, preParams.H1i, preParams.H2i, dlnProof1, dlnProof2,
+		prmProof, tss.EchoDigests(round.temp.dgRound1Messages))
 	if err != nil {
 		return round.WrapError(err, Pi)
 	}
 	round.temp.dgRound2Message1s[i] = r2msg2
-	round.out <- r2msg2
+	round.SendMessage(round.out, r2msg2)
 
 	// for this P: SAVE de-commitments, paillier keys for round 2
 	round.save.PaillierSK = preParams.PaillierSK
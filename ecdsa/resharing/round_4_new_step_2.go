@@ -39,23 +39,31 @@ func (round *round4) Start() *tss.Error {
 		return nil
 	}
 
-	common.Logger.Debugf(
+	round.Logger().Debugf(
 		"%s Setting up DLN verification with concurrency level of %d",
 		round.PartyID(),
 		round.Concurrency(),
 	)
 	dlnVerifier := keygen.NewDlnProofVerifier(round.Concurrency())
+	dlnVerifier.SetMetrics(round.Metrics())
 
-	Pi := round.PartyID()
+	Pi := round.NewCommitteePartyID()
 	i := Pi.Index
 	round.newOK[i] = true
 
+	// check that the new committee received the same round 1 broadcasts from the old committee
+	if culprits := tss.VerifyEchoes(tss.EchoDigests(round.temp.dgRound1Messages), round.temp.dgRound2Message1s, round.OldParties().IDs()); culprits != nil {
+		return round.WrapError(errors.New("round 1 broadcast equivocation detected"), culprits...).WithCode(tss.ErrDuplicateMessage)
+	}
+
 	// 1-3. verify paillier & dln proofs, store message pieces, ensure uniqueness of h1j, h2j
 	h1H2Map := make(map[string]struct{}, len(round.temp.dgRound2Message1s)*2)
 	paiProofCulprits := make([]*tss.PartyID, len(round.temp.dgRound2Message1s)) // who caused the error(s)
 	dlnProof1FailCulprits := make([]*tss.PartyID, len(round.temp.dgRound2Message1s))
 	dlnProof2FailCulprits := make([]*tss.PartyID, len(round.temp.dgRound2Message1s))
+	prmProofFailCulprits := make([]*tss.PartyID, len(round.temp.dgRound2Message1s))
 	wg := new(sync.WaitGroup)
+	pool := round.NewWorkerPool()
 	for j, msg := range round.temp.dgRound2Message1s {
 		r2msg1 := msg.Content().(*DGRound2Message1)
 		paiPK, NTildej, H1j, H2j :=
@@ -63,9 +71,15 @@ func (round *round4) Start() *tss.Error {
 			r2msg1.UnmarshalNTilde(),
 			r2msg1.UnmarshalH1(),
 			r2msg1.UnmarshalH2()
+		if !round.SecurityLevel().ValidModulus(paiPK.N) {
+			return round.WrapError(errors.New("got paillier modulus with insufficient bits for this party"), msg.GetFrom())
+		}
 		if H1j.Cmp(H2j) == 0 {
 			return round.WrapError(errors.New("h1j and h2j were equal for this party"), msg.GetFrom())
 		}
+		if !round.SecurityLevel().ValidModulus(NTildej) {
+			return round.WrapError(errors.New("got NTildej with insufficient bits for this party"), msg.GetFrom())
+		}
 		h1JHex, h2JHex := hex.EncodeToString(H1j.Bytes()), hex.EncodeToString(H2j.Bytes())
 		if _, found := h1H2Map[h1JHex]; found {
 			return round.WrapError(errors.New("this h1j was already used by another party"), msg.GetFrom())
@@ -74,36 +88,44 @@ func (round *round4) Start() *tss.Error {
 			return round.WrapError(errors.New("this h2j was already used by another party"), msg.GetFrom())
 		}
 		h1H2Map[h1JHex], h1H2Map[h2JHex] = struct{}{}, struct{}{}
-		wg.Add(3)
-		go func(j int, msg tss.ParsedMessage, r2msg1 *DGRound2Message1) {
+		wg.Add(4)
+		pool.GoFor(msg.GetFrom(), func() {
 			defer wg.Done()
 			modProof, err := r2msg1.UnmarshalModProof()
 			if err != nil {
 				if !round.Parameters.NoProofMod() {
 					paiProofCulprits[j] = msg.GetFrom()
 				}
-				common.Logger.Warningf("modProof verify failed for party %s", msg.GetFrom(), err)
+				round.Logger().Warnf("modProof verify failed for party %s", msg.GetFrom(), err)
 				return
 			}
 			ContextJ := common.AppendBigIntToBytesSlice(round.temp.ssid, big.NewInt(int64(j)))
-			if ok := modProof.Verify(ContextJ, paiPK.N); !ok {
+			if ok := round.VerifyProof("mod", func() bool { return modProof.Verify(ContextJ, paiPK.N) }); !ok {
 				paiProofCulprits[j] = msg.GetFrom()
-				common.Logger.Warningf("modProof verify failed for party %s", msg.GetFrom(), err)
+				round.Logger().Warnf("modProof verify failed for party %s", msg.GetFrom(), err)
 			}
-		}(j, msg, r2msg1)
+		})
 		_j := j
 		_msg := msg
 		dlnVerifier.VerifyDLNProof1(r2msg1, H1j, H2j, NTildej, func(isValid bool) {
 			if !isValid {
 				dlnProof1FailCulprits[_j] = _msg.GetFrom()
-				common.Logger.Warningf("dln proof 1 verify failed for party %s", _msg.GetFrom())
+				round.Logger().Warnf("dln proof 1 verify failed for party %s", _msg.GetFrom())
 			}
 			wg.Done()
 		})
 		dlnVerifier.VerifyDLNProof2(r2msg1, H2j, H1j, NTildej, func(isValid bool) {
 			if !isValid {
 				dlnProof2FailCulprits[_j] = _msg.GetFrom()
-				common.Logger.Warningf("dln proof 2 verify failed for party %s", _msg.GetFrom())
+				round.Logger().Warnf("dln proof 2 verify failed for party %s", _msg.GetFrom())
+			}
+			wg.Done()
+		})
+		ContextJ := common.AppendBigIntToBytesSlice(round.temp.ssid, big.NewInt(int64(j)))
+		dlnVerifier.VerifyPrmProof(r2msg1, ContextJ, H1j, H2j, NTildej, func(isValid bool) {
+			if !isValid {
+				prmProofFailCulprits[_j] = _msg.GetFrom()
+				round.Logger().Warnf("prm proof verify failed for party %s", _msg.GetFrom())
 			}
 			wg.Done()
 		})
@@ -111,7 +133,12 @@ func (round *round4) Start() *tss.Error {
 	wg.Wait()
 	for _, culprit := range append(append(paiProofCulprits, dlnProof1FailCulprits...), dlnProof2FailCulprits...) {
 		if culprit != nil {
-			return round.WrapError(errors.New("dln proof verification failed"), culprit)
+			return round.WrapError(errors.New("dln proof verification failed"), culprit).WithCode(tss.ErrProofVerifyFailed)
+		}
+	}
+	for _, culprit := range prmProofFailCulprits {
+		if culprit != nil {
+			return round.WrapError(errors.New("prm proof verification failed"), culprit).WithCode(tss.ErrProofVerifyFailed)
 		}
 	}
 	// save NTilde_j, h1_j, h2_j received in NewCommitteeStep1 here
@@ -141,11 +168,11 @@ func (round *round4) Start() *tss.Error {
 		// 6. unpack flat "v" commitment content
 		vCmtDeCmt := commitments.HashCommitDecommit{C: vCj, D: vDj}
 		ok, flatVs := vCmtDeCmt.DeCommit()
-		if !ok || len(flatVs) != (round.NewThreshold()+1)*2 { // they're points so * 2
+		if !ok || len(flatVs) != round.NewThreshold()+1 {
 			// TODO collect culprits and return a list of them as per convention
 			return round.WrapError(errors.New("de-commitment of v_j0..v_jt failed"), round.Parties().IDs()[j])
 		}
-		vj, err := crypto.UnFlattenECPoints(round.Params().EC(), flatVs)
+		vj, err := crypto.DecompressECPoints(round.Params().EC(), flatVs)
 		if err != nil {
 			return round.WrapError(err, round.Parties().IDs()[j])
 		}
@@ -158,9 +185,11 @@ func (round *round4) Start() *tss.Error {
 			ID:        round.PartyID().KeyInt(),
 			Share:     new(big.Int).SetBytes(r3msg1.Share),
 		}
-		if ok := sharej.Verify(round.Params().EC(), round.NewThreshold(), vj); !ok {
+		if ok := round.VerifyProof("vss", func() bool {
+			return sharej.Verify(round.Params().EC(), round.NewThreshold(), vj)
+		}); !ok {
 			// TODO collect culprits and return a list of them as per convention
-			return round.WrapError(errors.New("share from old committee did not pass Verify()"), round.Parties().IDs()[j])
+			return round.WrapError(errors.New("share from old committee did not pass Verify()"), round.Parties().IDs()[j]).WithCode(tss.ErrProofVerifyFailed)
 		}
 
 		// 9.
@@ -212,7 +241,8 @@ func (round *round4) Start() *tss.Error {
 	round.temp.newKs = newKs
 	round.temp.newBigXjs = newBigXjs
 
-	// Send facProof to new parties
+	// Send facProof to new parties, echoing the round 2 and round 3 broadcasts
+	echo := round.round2And3EchoDigests()
 	for j, Pj := range round.NewParties().IDs() {
 		if j == i {
 			continue
@@ -227,14 +257,14 @@ func (round *round4) Start() *tss.Error {
 				return round.WrapError(err, Pi)
 			}
 		}
-		r4msg1 := NewDGRound4Message1(Pj, Pi, facProof)
-		round.out <- r4msg1
+		r4msg1 := NewDGRound4Message1(Pj, Pi, facProof, echo)
+		round.SendMessage(round.out, r4msg1)
 	}
 
 	// Send an "ACK" message to both committees to signal that we're ready to save our data
 	r4msg2 := NewDGRound4Message2(round.OldAndNewParties(), Pi)
 	round.temp.dgRound4Message2s[i] = r4msg2
-	round.out <- r4msg2
+	round.SendMessage(round.out, r4msg2)
 
 	return nil
 }
@@ -10,7 +10,6 @@ import (
 	"errors"
 	"math/big"
 
-	"github.com/kisdex/mpc-lib/common"
 	"github.com/kisdex/mpc-lib/tss"
 )
 
@@ -24,16 +23,22 @@ func (round *round5) Start() *tss.Error {
 	round.allOldOK()
 	round.allNewOK()
 
-	Pi := round.PartyID()
-	i := Pi.Index
-
 	if round.IsNewCommittee() {
+		i := round.NewCommitteePartyID().Index
+
 		// 21.
 		// for this P: SAVE data
+		// check that the new committee received the same round 2 and round 3 broadcasts
+		senders := append(append([]*tss.PartyID{}, round.NewParties().IDs()...), round.OldParties().IDs()...)
+		if culprits := tss.VerifyEchoes(round.round2And3EchoDigests(), round.temp.dgRound4Message1s, senders); culprits != nil {
+			return round.WrapError(errors.New("round 2 or round 3 broadcast equivocation detected"), culprits...).WithCode(tss.ErrDuplicateMessage)
+		}
+
 		ContextI := append(round.temp.ssid, big.NewInt(int64(i)).Bytes()...)
 		round.save.BigXj = round.temp.newBigXjs
 		round.save.ShareID = round.PartyID().KeyInt()
 		round.save.Xi = round.temp.newXi
+		round.temp.newXi = nil
 		round.save.Ks = round.temp.newKs
 
 		// misc: build list of paillier public keys to save
@@ -51,21 +56,24 @@ func (round *round5) Start() *tss.Error {
 			r4msg1 := msg.Content().(*DGRound4Message1)
 			proof, err := r4msg1.UnmarshalFacProof()
 			if err != nil && round.Parameters.NoProofFac() {
-				common.Logger.Warningf("facProof verify failed for party %s", msg.GetFrom(), err)
+				round.Logger().Warnf("facProof verify failed for party %s", msg.GetFrom(), err)
 			} else {
 				if err != nil {
-					common.Logger.Warningf("facProof verify failed for party %s", msg.GetFrom(), err)
-					return round.WrapError(err, round.NewParties().IDs()[j])
+					round.Logger().Warnf("facProof verify failed for party %s", msg.GetFrom(), err)
+					return round.WrapError(err, round.NewParties().IDs()[j]).WithCode(tss.ErrInvalidMessage)
 				}
-				if ok := proof.Verify(ContextI, round.EC(), round.save.PaillierPKs[j].N, round.save.NTildei,
-					round.save.H1i, round.save.H2i); !ok {
-					common.Logger.Warningf("facProof verify failed for party %s", msg.GetFrom(), err)
-					return round.WrapError(err, round.NewParties().IDs()[j])
+				if ok := round.VerifyProof("fac", func() bool {
+					return proof.Verify(ContextI, round.EC(), round.save.PaillierPKs[j].N, round.save.NTildei,
+						round.save.H1i, round.save.H2i)
+				}); !ok {
+					round.Logger().Warnf("facProof verify failed for party %s", msg.GetFrom())
+					return round.WrapError(errors.New("facProof verify failed"), round.NewParties().IDs()[j]).WithCode(tss.ErrProofVerifyFailed)
 				}
 			}
 
 		}
-	} else if round.IsOldCommittee() {
+	}
+	if round.IsOldCommittee() {
 		round.input.Xi.SetInt64(0)
 	}
 
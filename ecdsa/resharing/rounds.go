@@ -147,13 +147,20 @@ func (round *base) getSSID() ([]byte, error) {
 	if err != nil {
 		return nil, round.WrapError(errors.New("read BigXj failed"), round.PartyID())
 	}
-	ssidList = append(ssidList, BigXjList...)                    // BigXj
-	ssidList = append(ssidList, round.input.NTildej...)          // NTilde
-	ssidList = append(ssidList, round.input.H1j...)              // h1
-	ssidList = append(ssidList, round.input.H2j...)              // h2
-	ssidList = append(ssidList, big.NewInt(int64(round.number))) // round number
+	ssidList = append(ssidList, BigXjList...)                                           // BigXj
+	ssidList = append(ssidList, round.input.NTildej...)                                 // NTilde
+	ssidList = append(ssidList, round.input.H1j...)                                     // h1
+	ssidList = append(ssidList, round.input.H2j...)                                     // h2
+	ssidList = append(ssidList, new(big.Int).SetBytes(common.SHA512_256(round.SSID()))) // application session id
+	ssidList = append(ssidList, big.NewInt(int64(round.number)))                        // round number
 	ssidList = append(ssidList, round.temp.ssidNonce)
 	ssid := common.SHA512_256i(ssidList...).Bytes()
 
 	return ssid, nil
 }
+
+// round2And3EchoDigests hashes the round 2 broadcasts of the new committee followed by the round 3 broadcasts of
+// the old committee
+func (round *base) round2And3EchoDigests() [][]byte {
+	return append(tss.EchoDigests(round.temp.dgRound2Message1s), tss.EchoDigests(round.temp.dgRound3Message2s)...)
+}
@@ -0,0 +1,109 @@
+package ecdsa
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/kisdex/mpc-lib/common"
+)
+
+const (
+	// defaultSignTimeout bounds a signature of a Signer, since crypto.Signer takes no context
+	defaultSignTimeout = 2 * time.Minute
+)
+
+// SignRequester asks the other parties of a signature to sign `digest` in the session `sessionID`, e.g. with a request
+// over the transport between them upon which each of them calls SignSession. It may return as soon as the request is
+// delivered or once the other parties have signed; an error aborts the signature.
+type SignRequester func(ctx context.Context, sessionID string, digest []byte) error
+
+// Signer implements crypto.Signer with the threshold key of a party, so that the key can sign with crypto/tls,
+// x509.CreateCertificate or a JWT library. Each signature runs a signing session among the parties: the Signer
+// requests it from the other parties with its SignRequester and signs in it with its own party.
+type Signer struct {
+	party   *party
+	pub     *ecdsa.PublicKey
+	request SignRequester
+
+	// Timeout bounds each signature; 0 means defaultSignTimeout
+	Timeout time.Duration
+}
+
+var _ crypto.Signer = (*Signer)(nil)
+
+// Signer returns a crypto.Signer of the threshold key of the party. The party must have its share data and a session
+// sender, see SetShareData and SetSessionSender.
+func (p *party) Signer(request SignRequester) (*Signer, error) {
+	if request == nil {
+		return nil, fmt.Errorf("a SignRequester is required")
+	}
+	pub, err := p.TPubKey()
+	if err != nil {
+		return nil, err
+	}
+	return &Signer{party: p, pub: pub, request: request}, nil
+}
+
+// Public returns the *ecdsa.PublicKey of the threshold key
+func (s *Signer) Public() crypto.PublicKey {
+	return s.pub
+}
+
+// Sign signs `digest` with the threshold key and returns the ASN.1 DER signature, as ecdsa.PrivateKey.Sign does.
+// `rand` is not used: each party draws the randomness of its share of the signature itself.
+func (s *Signer) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	timeout := s.Timeout
+	if timeout == 0 {
+		timeout = defaultSignTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return s.SignContext(ctx, digest, opts)
+}
+
+// SignContext is Sign with a context that bounds the signing session
+func (s *Signer) SignContext(ctx context.Context, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if len(digest) == 0 {
+		return nil, fmt.Errorf("the digest to sign is empty")
+	}
+	if opts != nil && opts.HashFunc() != 0 && len(digest) != opts.HashFunc().Size() {
+		return nil, fmt.Errorf("the digest has %d bytes but %v has %d", len(digest), opts.HashFunc(), opts.HashFunc().Size())
+	}
+	nonce, err := common.GetRandomBytes(16)
+	if err != nil {
+		return nil, err
+	}
+	sessionID := "signer-" + hex.EncodeToString(nonce)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	requested := make(chan error, 1)
+	go func() {
+		err := s.request(ctx, sessionID, digest)
+		if err != nil {
+			// the other parties will not sign, so there is no point in waiting for them
+			cancel()
+		}
+		requested <- err
+	}()
+
+	sig, err := s.party.SignSession(ctx, sessionID, digest)
+	if err != nil {
+		cancel()
+	}
+	if reqErr := <-requested; reqErr != nil {
+		return nil, fmt.Errorf("failed requesting the signature of session %s: %w", sessionID, reqErr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !ecdsa.VerifyASN1(s.pub, digest, sig) {
+		return nil, fmt.Errorf("the signature of session %s does not verify", sessionID)
+	}
+	return sig, nil
+}
@@ -0,0 +1,149 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/kisdex/mpc-lib/crypto"
+	cmt "github.com/kisdex/mpc-lib/crypto/commitments"
+	"github.com/kisdex/mpc-lib/crypto/mta"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+// checkpointTempData is the temp data of a party in a checkpoint
+type checkpointTempData struct {
+	Xi *big.Int // the share, which is tweaked in round 1 when signing with a derived key
+
+	// round 1
+	W,
+	K,
+	Theta,
+	ThetaInverse,
+	Sigma,
+	Gamma *big.Int
+	Cis        []*big.Int
+	BigWs      []*crypto.ECPoint
+	PointGamma *crypto.ECPoint
+	DeCommit   cmt.HashDeCommitment
+
+	// round 2
+	Betas,
+	C1jis,
+	C2jis,
+	Vs []*big.Int
+	Pi1jis []*mta.ProofBob
+	Pi2jis []*mta.ProofBobWC
+
+	// round 5
+	Li,
+	Si,
+	Rx,
+	Ry,
+	Roi *big.Int
+	BigR,
+	AdaptorR,
+	BigAi,
+	BigVi *crypto.ECPoint
+	DPower cmt.HashDeCommitment
+
+	// round 7
+	Ui,
+	Ti *crypto.ECPoint
+	DTelda cmt.HashDeCommitment
+
+	SSIDNonce *big.Int
+	SSID      []byte
+}
+
+// Checkpoint returns the state of the running party, so that it can be resumed with Resume after a restart, e.g.
+// after every call to Start or Update. It holds the secret data of the party and must be stored encrypted.
+func (p *LocalParty) Checkpoint() ([]byte, error) {
+	cp, err := tss.BaseCheckpoint(p, TaskName, func(round tss.Round, cp *tss.Checkpoint) (err error) {
+		cp.OK = append([]bool{}, baseOf(round).ok...)
+		if cp.Messages, err = tss.NewCheckpointMessages(p.temp.signRound1Message1s, p.temp.signRound1Message2s,
+			p.temp.signRound2Messages, p.temp.signRound3Messages, p.temp.signRound4Messages, p.temp.signRound5Messages,
+			p.temp.signRound6Messages, p.temp.signRound7Messages, p.temp.signRound8Messages,
+			p.temp.signRound9Messages); err != nil {
+			return err
+		}
+		if cp.Data, err = json.Marshal(p.data); err != nil {
+			return err
+		}
+		t := &p.temp
+		cp.Temp, err = json.Marshal(&checkpointTempData{
+			Xi: p.keys.Xi,
+			W:  t.w, K: t.k, Theta: t.theta, ThetaInverse: t.thetaInverse, Sigma: t.sigma, Gamma: t.gamma,
+			Cis: t.cis, BigWs: t.bigWs, PointGamma: t.pointGamma, DeCommit: t.deCommit,
+			Betas: t.betas, C1jis: t.c1jis, C2jis: t.c2jis, Vs: t.vs, Pi1jis: t.pi1jis, Pi2jis: t.pi2jis,
+			Li: t.li, Si: t.si, Rx: t.rx, Ry: t.ry, Roi: t.roi,
+			BigR: t.bigR, AdaptorR: t.adaptorR, BigAi: t.bigAi, BigVi: t.bigVi, DPower: t.DPower,
+			Ui: t.Ui, Ti: t.Ti, DTelda: t.DTelda,
+			SSIDNonce: t.ssidNonce, SSID: t.ssid,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(cp)
+}
+
+// Resume continues signing from a checkpoint taken with Checkpoint; it is called in place of Start on a party
+// constructed with the same message, key and parameters as the one the checkpoint was taken of
+func (p *LocalParty) Resume(checkpoint []byte) *tss.Error {
+	cp := new(tss.Checkpoint)
+	if err := json.Unmarshal(checkpoint, cp); err != nil {
+		return p.WrapError(err)
+	}
+	return tss.BaseResume(p, TaskName, cp, func(round tss.Round) *tss.Error {
+		msgs, err := tss.ParseCheckpointMessages(cp.Messages, p.params.Parties().IDs())
+		if err != nil {
+			return round.WrapError(err)
+		}
+		for _, msg := range msgs {
+			if _, err := p.StoreMessage(msg); err != nil {
+				return err
+			}
+		}
+		if err = json.Unmarshal(cp.Data, p.data); err != nil {
+			return round.WrapError(err)
+		}
+		temp := new(checkpointTempData)
+		if err = json.Unmarshal(cp.Temp, temp); err != nil {
+			return round.WrapError(err)
+		}
+		p.keys.Xi = temp.Xi
+		t := &p.temp
+		t.w, t.k, t.theta, t.thetaInverse, t.sigma, t.gamma = temp.W, temp.K, temp.Theta, temp.ThetaInverse, temp.Sigma, temp.Gamma
+		t.cis, t.bigWs, t.pointGamma, t.deCommit = temp.Cis, temp.BigWs, temp.PointGamma, temp.DeCommit
+		t.betas, t.c1jis, t.c2jis, t.vs, t.pi1jis, t.pi2jis = temp.Betas, temp.C1jis, temp.C2jis, temp.Vs, temp.Pi1jis, temp.Pi2jis
+		t.li, t.si, t.rx, t.ry, t.roi = temp.Li, temp.Si, temp.Rx, temp.Ry, temp.Roi
+		t.bigR, t.adaptorR, t.bigAi, t.bigVi, t.DPower = temp.BigR, temp.AdaptorR, temp.BigAi, temp.BigVi, temp.DPower
+		t.Ui, t.Ti, t.DTelda = temp.Ui, temp.Ti, temp.DTelda
+		t.ssidNonce, t.ssid = temp.SSIDNonce, temp.SSID
+
+		b := baseOf(round)
+		if len(cp.OK) != len(b.ok) {
+			return round.WrapError(fmt.Errorf("the checkpoint has %d round flags but the party has %d", len(cp.OK), len(b.ok)))
+		}
+		copy(b.ok, cp.OK)
+		b.number, b.started = cp.Round, true
+		return nil
+	})
+}
+
+// baseOf returns the state shared by `round` and the rounds before it
+func baseOf(round tss.Round) *base {
+	return round.(interface{ roundBase() *base }).roundBase()
+}
+
+func (round *base) roundBase() *base {
+	return round
+}
@@ -0,0 +1,78 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/ecdsa/keygen"
+	"github.com/kisdex/mpc-lib/test"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+func TestE2ECheckpointResume(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs)*len(signPIDs)*3)
+	endCh := make(chan *common.SignatureData, len(signPIDs))
+	msg := big.NewInt(42)
+	newParty := func(i int) *LocalParty {
+		params := tss.NewParameters(tss.S256(), p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+		return NewLocalParty(msg, params, keys[i], outCh, endCh).(*LocalParty)
+	}
+
+	parties := make([]*LocalParty, len(signPIDs))
+	for i := range signPIDs {
+		parties[i] = newParty(i)
+		if !assert.Nil(t, parties[i].Start()) {
+			return
+		}
+	}
+	// party 0 is restarted from a checkpoint after every message it processes
+	restart := func() {
+		cp, err := parties[0].Checkpoint()
+		if err != nil {
+			assert.False(t, parties[0].Running(), "a running party must be checkpointed: %v", err)
+			return
+		}
+		P := newParty(0)
+		assert.Nil(t, P.Resume(cp))
+		parties[0] = P
+	}
+	restart()
+
+	for len(endCh) < len(signPIDs) {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+		case msg := <-outCh:
+			for _, P := range parties {
+				if dest := msg.GetTo(); dest != nil && dest[0].Index != P.PartyID().Index {
+					continue
+				}
+				test.SharedPartyUpdater(P, msg, errCh)
+			}
+			restart()
+		}
+	}
+
+	pk := keys[0].ECDSAPub.ToECDSAPubKey()
+	for range signPIDs {
+		data := <-endCh
+		r, s := new(big.Int).SetBytes(data.R), new(big.Int).SetBytes(data.S)
+		assert.True(t, ecdsa.Verify(pk, msg.Bytes(), r, s), "ecdsa verify must pass")
+	}
+}
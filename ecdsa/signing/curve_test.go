@@ -0,0 +1,63 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/ecdsa/keygen"
+	"github.com/kisdex/mpc-lib/test"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+func TestE2ESignStark(t *testing.T) {
+	setUp("info")
+	ec := tss.StarkCurve()
+
+	fixtures, _, err := keygen.LoadKeygenTestFixtures(testParticipants)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	preParams := make([]keygen.LocalPreParams, len(fixtures))
+	for i, fixture := range fixtures {
+		preParams[i] = fixture.LocalPreParams
+	}
+	keys, pIDs, err := keygen.GenerateKeygenTestFixturesWithPreParams(ec, testThreshold, tss.SecurityLevel112, 1, preParams)
+	if !assert.NoError(t, err, "keygen should succeed") {
+		return
+	}
+
+	// a message of StarkNet is a field element of up to 251 bits
+	digest := sha256.Sum256([]byte("Stark"))
+	msg := new(big.Int).Rsh(new(big.Int).SetBytes(digest[:]), 5)
+	signPIDs := pIDs[:testThreshold+1]
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	outCh := make(chan tss.Message, len(signPIDs)*len(signPIDs)*9)
+	endCh := make(chan *common.SignatureData, len(signPIDs))
+	parties := make([]tss.Party, 0, len(signPIDs))
+	for i, pID := range signPIDs {
+		params := tss.NewParameters(ec, p2pCtx, pID, len(signPIDs), testThreshold)
+		parties = append(parties, NewLocalParty(msg, params, keys[i], outCh, endCh))
+	}
+	if _, tssErr := test.RunSequentially(parties, outCh); !assert.Nil(t, tssErr, "signing should succeed") {
+		return
+	}
+
+	pk := keys[0].ECDSAPub.ToECDSAPubKey()
+	for range parties {
+		sig := <-endCh
+		assert.Len(t, sig.Signature, 64)
+		r, s := new(big.Int).SetBytes(sig.R), new(big.Int).SetBytes(sig.S)
+		assert.True(t, ecdsa.Verify(pk, common.ECDSADigest(ec.Params().N, msg), r, s), "ecdsa verify must pass on the STARK curve")
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+//go:build tss_deterministic
+
+package signing
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/ecdsa/keygen"
+	"github.com/kisdex/mpc-lib/test"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+func TestE2EDeterministic(t *testing.T) {
+	setUp("info")
+	defer common.SetRandomSource(nil)
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixtures(testThreshold + 1)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	run := func(seed string) ([][]byte, []*common.SignatureData) {
+		common.SetRandomSource(common.NewDeterministicReader([]byte(seed)))
+		p2pCtx := tss.NewPeerContext(signPIDs)
+		outCh := make(chan tss.Message, len(signPIDs)*len(signPIDs))
+		endCh := make(chan *common.SignatureData, len(signPIDs))
+		parties := make([]tss.Party, 0, len(signPIDs))
+		for i := range signPIDs {
+			params := tss.NewParameters(tss.S256(), p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+			params.SetConcurrency(1)
+			parties = append(parties, NewLocalParty(big.NewInt(42), params, keys[i], outCh, endCh))
+		}
+		transcript, tssErr := test.RunSequentially(parties, outCh)
+		if !assert.Nil(t, tssErr, "signing should succeed") {
+			t.FailNow()
+		}
+		sigs := make([]*common.SignatureData, 0, len(signPIDs))
+		for range signPIDs {
+			sigs = append(sigs, <-endCh)
+		}
+		return transcript, sigs
+	}
+
+	transcript, sigs := run("signing")
+	transcript2, sigs2 := run("signing")
+	assert.Equal(t, transcript, transcript2, "the messages of two runs with the same seed should be equal")
+	for i := range sigs {
+		assert.Equal(t, sigs[i].Signature, sigs2[i].Signature, "the signatures of two runs with the same seed should be equal")
+	}
+	_, sigs3 := run("another signing")
+	assert.NotEqual(t, sigs[0].Signature, sigs3[0].Signature, "a run with another seed should use another nonce")
+}
@@ -13,6 +13,7 @@ import (
 	"math/big"
 
 	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto/adaptor"
 	"github.com/kisdex/mpc-lib/tss"
 )
 
@@ -36,6 +37,10 @@ func (round *finalization) Start() *tss.Error {
 		sumS = modN.Add(sumS, r9msg.UnmarshalS())
 	}
 
+	if round.temp.adaptorPoint != nil {
+		return round.finalizePreSignature(sumS)
+	}
+
 	recid := 0
 	// byte v = if(R.X > curve.N) then 2 else 0) | (if R.Y.IsEven then 0 else 1);
 	if round.temp.rx.Cmp(round.Params().EC().Params().N) > 0 {
@@ -45,18 +50,12 @@ func (round *finalization) Start() *tss.Error {
 		recid |= 1
 	}
 
-	// This is copied from:
-	// https://github.com/btcsuite/btcd/blob/c26ffa870fd817666a857af1bf6498fabba1ffe3/btcec/signature.go#L442-L444
-	// This is needed because of tendermint checks here:
-	// https://github.com/tendermint/tendermint/blob/d9481e3648450cb99e15c6a070c1fb69aa0c255b/crypto/secp256k1/secp256k1_nocgo.go#L43-L47
-	secp256k1halfN := new(big.Int).Rsh(round.Params().EC().Params().N, 1)
-	if sumS.Cmp(secp256k1halfN) > 0 {
-		sumS.Sub(round.Params().EC().Params().N, sumS)
-		recid ^= 1
+	if !round.Params().NoLowS() {
+		sumS, recid = common.NormalizeLowS(round.Params().EC().Params().N, sumS, recid)
 	}
 
 	// save the signature for final output
-	bitSizeInBytes := round.Params().EC().Params().BitSize / 8
+	bitSizeInBytes := (round.Params().EC().Params().BitSize + 7) / 8
 	round.data.R = padToLengthBytesInPlace(round.temp.rx.Bytes(), bitSizeInBytes)
 	round.data.S = padToLengthBytesInPlace(sumS.Bytes(), bitSizeInBytes)
 	round.data.Signature = append(round.data.R, round.data.S...)
@@ -68,7 +67,7 @@ func (round *finalization) Start() *tss.Error {
 		X:     round.key.ECDSAPub.X(),
 		Y:     round.key.ECDSAPub.Y(),
 	}
-	ok := ecdsa.Verify(&pk, round.temp.m.Bytes(), round.temp.rx, sumS)
+	ok := ecdsa.Verify(&pk, common.ECDSADigest(round.Params().EC().Params().N, round.temp.m), round.temp.rx, sumS)
 	if !ok {
 		return round.WrapError(fmt.Errorf("signature verification failed"))
 	}
@@ -78,6 +77,22 @@ func (round *finalization) Start() *tss.Error {
 	return nil
 }
 
+// finalizePreSignature outputs the adaptor pre-signature; it must not be normalized to low-S as
+// that would break the relation between s' and the adaptor secret
+func (round *finalization) finalizePreSignature(sumS *big.Int) *tss.Error {
+	preSig := &adaptor.PreSignature{
+		R:    round.temp.adaptorR,
+		RHat: round.temp.bigR,
+		S:    sumS,
+		M:    round.temp.m,
+	}
+	if !preSig.Verify(round.key.ECDSAPub) {
+		return round.WrapError(fmt.Errorf("pre-signature verification failed"))
+	}
+	round.adaptorEnd <- preSig
+	return nil
+}
+
 func (round *finalization) CanAccept(msg tss.ParsedMessage) bool {
 	// not expecting any incoming messages in this round
 	return false
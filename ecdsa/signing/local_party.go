@@ -13,6 +13,7 @@ import (
 
 	"github.com/kisdex/mpc-lib/common"
 	"github.com/kisdex/mpc-lib/crypto"
+	"github.com/kisdex/mpc-lib/crypto/adaptor"
 	cmt "github.com/kisdex/mpc-lib/crypto/commitments"
 	"github.com/kisdex/mpc-lib/crypto/mta"
 	"github.com/kisdex/mpc-lib/ecdsa/keygen"
@@ -23,6 +24,7 @@ import (
 // Implements Stringer
 var _ tss.Party = (*LocalParty)(nil)
 var _ fmt.Stringer = (*LocalParty)(nil)
+var _ tss.Zeroizer = (*LocalParty)(nil)
 
 type (
 	LocalParty struct {
@@ -34,8 +36,9 @@ type (
 		data *common.SignatureData
 
 		// outbound messaging
-		out chan<- tss.Message
-		end chan<- *common.SignatureData
+		out        chan<- tss.Message
+		end        chan<- *common.SignatureData
+		adaptorEnd chan<- *adaptor.PreSignature
 	}
 
 	localMessageStore struct {
@@ -63,10 +66,11 @@ type (
 		sigma,
 		keyDerivationDelta,
 		gamma *big.Int
-		cis        []*big.Int
-		bigWs      []*crypto.ECPoint
-		pointGamma *crypto.ECPoint
-		deCommit   cmt.HashDeCommitment
+		cis          []*big.Int
+		bigWs        []*crypto.ECPoint
+		pointGamma   *crypto.ECPoint
+		adaptorPoint *crypto.ECPoint
+		deCommit     cmt.HashDeCommitment
 
 		// round 2
 		betas, // return value of Bob_mid
@@ -83,6 +87,7 @@ type (
 		ry,
 		roi *big.Int
 		bigR,
+		adaptorR,
 		bigAi,
 		bigVi *crypto.ECPoint
 		DPower cmt.HashDeCommitment
@@ -150,8 +155,25 @@ func NewLocalPartyWithKDD(
 	return p
 }
 
+// NewLocalPartyWithAdaptor returns a party which produces an adaptor pre-signature of `msg` against the
+// adaptor point `T` and sends it to `adaptorEnd`. The pre-signature becomes a valid signature once it is
+// completed with the discrete logarithm of T, see adaptor.PreSignature.
+func NewLocalPartyWithAdaptor(
+	msg *big.Int,
+	params *tss.Parameters,
+	key keygen.LocalPartySaveData,
+	T *crypto.ECPoint,
+	out chan<- tss.Message,
+	adaptorEnd chan<- *adaptor.PreSignature,
+) tss.Party {
+	p := NewLocalPartyWithKDD(msg, params, key, nil, out, nil).(*LocalParty)
+	p.temp.adaptorPoint = T
+	p.adaptorEnd = adaptorEnd
+	return p
+}
+
 func (p *LocalParty) FirstRound() tss.Round {
-	return newRound1(p.params, &p.keys, p.data, &p.temp, p.out, p.end)
+	return newRound1(p.params, &p.keys, p.data, &p.temp, p.out, p.end, p.adaptorEnd)
 }
 
 func (p *LocalParty) Start() *tss.Error {
@@ -172,7 +194,7 @@ func (p *LocalParty) Update(msg tss.ParsedMessage) (ok bool, err *tss.Error) {
 }
 
 func (p *LocalParty) UpdateFromBytes(wireBytes []byte, from *tss.PartyID, isBroadcast bool) (bool, *tss.Error) {
-	msg, err := tss.ParseWireMessage(wireBytes, from, isBroadcast)
+	msg, err := messageTypes.ParseWireMessage(wireBytes, from, isBroadcast)
 	if err != nil {
 		return false, p.WrapError(err)
 	}
@@ -222,7 +244,7 @@ func (p *LocalParty) StoreMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
 	case *SignRound9Message:
 		p.temp.signRound9Messages[fromPIdx] = msg
 	default: // unrecognised message, just ignore!
-		common.Logger.Warningf("unrecognised message ignored: %v", msg)
+		p.params.Logger().Warnf("unrecognised message ignored: %v", msg)
 		return false, nil
 	}
 	return true, nil
@@ -232,6 +254,19 @@ func (p *LocalParty) PartyID() *tss.PartyID {
 	return p.params.PartyID()
 }
 
+// Zeroize wipes the nonces and the other secrets of the signing, see tss.Zeroizer
+func (p *LocalParty) Zeroize() {
+	p.temp.Zeroize()
+}
+
+// Zeroize wipes the nonces k and gamma, the additive share w of the key, the products of the MtA exchanges and the
+// blinding scalars of rounds 5-7
+func (temp *localTempData) Zeroize() {
+	common.Zeroize(temp.w, temp.k, temp.gamma, temp.sigma, temp.li, temp.roi)
+	common.Zeroize(temp.betas...)
+	common.Zeroize(temp.vs...)
+}
+
 func (p *LocalParty) String() string {
 	return fmt.Sprintf("id: %s, %s", p.PartyID(), p.BaseParty.String())
 }
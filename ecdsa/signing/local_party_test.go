@@ -8,6 +8,7 @@ package signing
 
 import (
 	"crypto/ecdsa"
+	"crypto/rand"
 	"fmt"
 	"math/big"
 	"runtime"
@@ -15,10 +16,12 @@ import (
 	"testing"
 
 	"github.com/btcsuite/btcd/btcec/v2"
-	"github.com/ipfs/go-log"
+	btcecdsa "github.com/btcsuite/btcd/btcec/v2/ecdsa"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto"
+	"github.com/kisdex/mpc-lib/crypto/adaptor"
 	"github.com/kisdex/mpc-lib/ecdsa/keygen"
 	"github.com/kisdex/mpc-lib/test"
 	"github.com/kisdex/mpc-lib/tss"
@@ -30,7 +33,7 @@ const (
 )
 
 func setUp(level string) {
-	if err := log.SetLogLevel("tss-lib", level); err != nil {
+	if err := common.SetLogLevel(level); err != nil {
 		panic(err)
 	}
 }
@@ -77,7 +80,7 @@ signing:
 		fmt.Printf("ACTIVE GOROUTINES: %d\n", runtime.NumGoroutine())
 		select {
 		case err := <-errCh:
-			common.Logger.Errorf("Error: %s", err)
+			common.DefaultLogger().Errorf("Error: %s", err)
 			assert.FailNow(t, err.Error())
 			break signing
 
@@ -97,7 +100,20 @@ signing:
 				go updater(parties[dest[0].Index], msg, errCh)
 			}
 
-		case <-endCh:
+		case data := <-endCh:
+			// the Ethereum encoding must recover the signing key
+			eth, err := data.EthereumSignature()
+			assert.NoError(t, err)
+			recovered, _, err := btcecdsa.RecoverCompact(append([]byte{eth[64] + 27}, eth[:64]...), data.M)
+			if assert.NoError(t, err) {
+				assert.True(t, recovered.ToECDSA().Equal(keys[0].ECDSAPub.ToECDSAPubKey()), "recovery id must select the public key")
+			}
+			halfN := new(big.Int).Rsh(tss.S256().Params().N, 1)
+			assert.True(t, new(big.Int).SetBytes(data.S).Cmp(halfN) <= 0, "signature must be low-S")
+			der, err := data.DERSignature()
+			assert.NoError(t, err)
+			assert.True(t, ecdsa.VerifyASN1(keys[0].ECDSAPub.ToECDSAPubKey(), data.M, der), "DER signature must verify")
+
 			atomic.AddInt32(&ended, 1)
 			if atomic.LoadInt32(&ended) == int32(len(signPIDs)) {
 				t.Logf("Done. Received signature data from %d participants", ended)
@@ -187,7 +203,7 @@ signing:
 	for {
 		select {
 		case err := <-errCh:
-			common.Logger.Errorf("Error: %s", err)
+			common.DefaultLogger().Errorf("Error: %s", err)
 			assert.FailNow(t, err.Error())
 			break signing
 
@@ -243,6 +259,297 @@ signing:
 	}
 }
 
+func TestE2EWithAdaptor(t *testing.T) {
+	setUp("info")
+	threshold := testThreshold
+
+	// PHASE: load keygen fixtures
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	assert.Equal(t, testThreshold+1, len(keys))
+	assert.Equal(t, testThreshold+1, len(signPIDs))
+
+	// the adaptor secret is known only to the counterparty
+	adaptorSecret := common.GetRandomPositiveInt(tss.S256().Params().N)
+	T := crypto.ScalarBaseMult(tss.S256(), adaptorSecret)
+
+	// PHASE: signing
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]*LocalParty, 0, len(signPIDs))
+
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs))
+	preSigCh := make(chan *adaptor.PreSignature, len(signPIDs))
+
+	updater := test.SharedPartyUpdater
+
+	// init the parties
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(tss.S256(), p2pCtx, signPIDs[i], len(signPIDs), threshold)
+
+		P := NewLocalPartyWithAdaptor(big.NewInt(42), params, keys[i], T, outCh, preSigCh).(*LocalParty)
+		parties = append(parties, P)
+		go func(P *LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	var ended int32
+
+signing:
+
+	for {
+		select {
+		case err := <-errCh:
+			common.DefaultLogger().Errorf("Error: %s", err)
+			assert.FailNow(t, err.Error())
+			break signing
+
+		case msg := <-outCh:
+			dest := msg.GetTo()
+			if dest == nil {
+				for _, P := range parties {
+					if P.PartyID().Index == msg.GetFrom().Index {
+						continue
+					}
+					go updater(P, msg, errCh)
+				}
+			} else {
+				if dest[0].Index == msg.GetFrom().Index {
+					t.Fatalf("party %d tried to send a message to itself (%d)", dest[0].Index, msg.GetFrom().Index)
+				}
+				go updater(parties[dest[0].Index], msg, errCh)
+			}
+
+		case preSig := <-preSigCh:
+			assert.True(t, preSig.Verify(keys[0].ECDSAPub), "pre-signature must verify")
+			if atomic.AddInt32(&ended, 1) < int32(len(signPIDs)) {
+				continue
+			}
+			t.Logf("Done. Received pre-signatures from %d participants", ended)
+
+			// BEGIN the pre-signature alone must not verify
+			pk := keys[0].ECDSAPub.ToECDSAPubKey()
+			assert.False(t, ecdsa.Verify(pk, big.NewInt(42).Bytes(), preSig.Rx(), preSig.S), "pre-signature must not be a valid signature")
+			// END the pre-signature alone must not verify
+
+			// BEGIN complete, verify and extract
+			sig, err := preSig.Complete(adaptorSecret)
+			assert.NoError(t, err)
+			r, s := new(big.Int).SetBytes(sig.R), new(big.Int).SetBytes(sig.S)
+			assert.True(t, ecdsa.Verify(pk, big.NewInt(42).Bytes(), r, s), "ecdsa verify must pass")
+
+			extracted, err := preSig.Extract(sig, T)
+			assert.NoError(t, err)
+			assert.Equal(t, 0, extracted.Cmp(adaptorSecret), "extracted adaptor secret must match")
+			// END complete, verify and extract
+
+			break signing
+		}
+	}
+}
+
+func TestE2EMultiplex(t *testing.T) {
+	setUp("info")
+	threshold := testThreshold
+
+	// PHASE: load keygen fixtures
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	assert.Equal(t, testThreshold+1, len(keys))
+	assert.Equal(t, testThreshold+1, len(signPIDs))
+
+	msgs := []*big.Int{big.NewInt(42), big.NewInt(43), big.NewInt(44)}
+
+	// PHASE: signing
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]*MultiplexLocalParty, 0, len(signPIDs))
+
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs)*len(signPIDs))
+	endCh := make(chan []*common.SignatureData, len(signPIDs))
+
+	updater := test.SharedPartyUpdater
+
+	// init the parties; every party is started before messages are delivered
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(tss.S256(), p2pCtx, signPIDs[i], len(signPIDs), threshold)
+
+		P := NewMultiplexLocalParty(msgs, params, keys[i], outCh, endCh).(*MultiplexLocalParty)
+		parties = append(parties, P)
+	}
+	for _, P := range parties {
+		if err := P.Start(); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+	}
+
+	var ended int32
+	var first []*common.SignatureData
+
+signing:
+
+	for {
+		select {
+		case err := <-errCh:
+			common.DefaultLogger().Errorf("Error: %s", err)
+			assert.FailNow(t, err.Error())
+			break signing
+
+		case msg := <-outCh:
+			dest := msg.GetTo()
+			if dest == nil {
+				for _, P := range parties {
+					if P.PartyID().Index == msg.GetFrom().Index {
+						continue
+					}
+					go updater(P, msg, errCh)
+				}
+			} else {
+				if dest[0].Index == msg.GetFrom().Index {
+					t.Fatalf("party %d tried to send a message to itself (%d)", dest[0].Index, msg.GetFrom().Index)
+				}
+				go updater(parties[dest[0].Index], msg, errCh)
+			}
+
+		case sigs := <-endCh:
+			assert.Len(t, sigs, len(msgs))
+			if first == nil {
+				first = sigs
+			}
+			for i, sig := range sigs {
+				assert.Equal(t, first[i].Signature, sig.Signature, "all parties must output the same signatures")
+			}
+			if atomic.AddInt32(&ended, 1) < int32(len(signPIDs)) {
+				continue
+			}
+			t.Logf("Done. Received signature batches from %d participants", ended)
+
+			pk := keys[0].ECDSAPub.ToECDSAPubKey()
+			for i, sig := range first {
+				assert.Equal(t, msgs[i].Bytes(), sig.M)
+				r, s := new(big.Int).SetBytes(sig.R), new(big.Int).SetBytes(sig.S)
+				assert.True(t, ecdsa.Verify(pk, msgs[i].Bytes(), r, s), "ecdsa verify must pass for message %d", i)
+			}
+			assert.NotEqual(t, first[0].R, first[1].R, "every message must be signed with its own nonce")
+			break signing
+		}
+	}
+}
+
+func TestMultiplexInsecureProofsRefused(t *testing.T) {
+	keys, signPIDs, err := keygen.LoadKeygenTestFixtures(testThreshold + 1)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	params := tss.NewParameters(tss.S256(), tss.NewPeerContext(signPIDs), signPIDs[0], len(signPIDs), testThreshold)
+	params.SetNoProofMod()
+	out := make(chan tss.Message, len(signPIDs))
+	P := NewMultiplexLocalParty([]*big.Int{big.NewInt(42), big.NewInt(43)}, params, keys[0], out, nil)
+	err2 := P.Start()
+	if assert.Error(t, err2, "a multiplexing party must not start without the Πmod proofs in production mode") {
+		assert.ErrorIs(t, err2, tss.ErrInvalidState)
+	}
+	assert.Empty(t, out)
+	assert.False(t, P.Running())
+}
+
+func TestE2EImportedKey(t *testing.T) {
+	setUp("info")
+
+	fixtures, _, err := keygen.LoadKeygenTestFixtures(testParticipants)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	preParams := make([]keygen.LocalPreParams, len(fixtures))
+	for i, fixture := range fixtures {
+		preParams[i] = fixture.LocalPreParams
+	}
+	sk, err := ecdsa.GenerateKey(tss.S256(), rand.Reader)
+	if !assert.NoError(t, err) {
+		return
+	}
+	pIDs := tss.GenerateTestPartyIDs(testParticipants)
+	keys, err := keygen.ImportKeyWithPreParams(tss.S256(), sk.D, pIDs, testThreshold, preParams)
+	if !assert.NoError(t, err, "should import the key") {
+		return
+	}
+
+	// sign with the last threshold+1 parties
+	signPIDs := tss.SortPartyIDs(tss.UnSortedPartyIDs(pIDs[len(pIDs)-testThreshold-1:]))
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	outCh := make(chan tss.Message, len(signPIDs)*len(signPIDs))
+	endCh := make(chan *common.SignatureData, len(signPIDs))
+	parties := make([]tss.Party, 0, len(signPIDs))
+	for i, pID := range signPIDs {
+		params := tss.NewParameters(tss.S256(), p2pCtx, pID, len(signPIDs), testThreshold)
+		parties = append(parties, NewLocalParty(big.NewInt(42), params, keys[len(pIDs)-len(signPIDs)+i], outCh, endCh))
+	}
+	if _, tssErr := test.RunSequentially(parties, outCh); !assert.Nil(t, tssErr, "signing should succeed") {
+		return
+	}
+	sig := <-endCh
+	r, s := new(big.Int).SetBytes(sig.R), new(big.Int).SetBytes(sig.S)
+	assert.True(t, ecdsa.Verify(&sk.PublicKey, big.NewInt(42).Bytes(), r, s), "ecdsa verify must pass under the imported key")
+}
+
+func FuzzMessages(f *testing.F) {
+	setUp("error")
+	keys, signPIDs, err := keygen.LoadKeygenTestFixtures(testThreshold + 1)
+	if err != nil {
+		f.Fatal(err)
+	}
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	outCh := make(chan tss.Message, len(signPIDs)*len(signPIDs))
+	endCh := make(chan *common.SignatureData, len(signPIDs))
+	parties := make([]tss.Party, 0, len(signPIDs))
+	for i := range signPIDs {
+		params := tss.NewParameters(tss.S256(), p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+		parties = append(parties, NewLocalParty(big.NewInt(42), params, keys[i], outCh, endCh))
+	}
+	transcript, tssErr := test.RunSequentially(parties, outCh)
+	if tssErr != nil {
+		f.Fatal(tssErr)
+	}
+	test.FuzzWireMessages(f, tss.S256(), transcript)
+}
+
+func TestE2EZeroizesNonces(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixtures(testThreshold + 1)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	outCh := make(chan tss.Message, len(signPIDs)*len(signPIDs))
+	endCh := make(chan *common.SignatureData, len(signPIDs))
+	parties := make([]tss.Party, 0, len(signPIDs))
+	for i := range signPIDs {
+		params := tss.NewParameters(tss.S256(), p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+		parties = append(parties, NewLocalParty(big.NewInt(42), params, keys[i], outCh, endCh))
+	}
+	if _, tssErr := test.RunSequentially(parties, outCh); !assert.Nil(t, tssErr, "signing should succeed") {
+		return
+	}
+	for _, party := range parties {
+		temp := party.(*LocalParty).temp
+		for _, secret := range []*big.Int{temp.w, temp.k, temp.gamma, temp.sigma} {
+			assert.Zero(t, secret.Sign(), "the nonces of a finished signing must be wiped")
+		}
+	}
+}
+
+func TestPrepareForSigningCopiesShare(t *testing.T) {
+	ec := tss.S256()
+	xi := big.NewInt(42)
+	wi, _ := PrepareForSigning(ec, 0, 1, xi, []*big.Int{big.NewInt(1)}, []*crypto.ECPoint{crypto.ScalarBaseMult(ec, xi)})
+	common.Zeroize(wi)
+	assert.Equal(t, int64(42), xi.Int64(), "wiping wi of a lone signer must not wipe the key share")
+}
+
 func TestFillTo32BytesInPlace(t *testing.T) {
 	s := big.NewInt(123456789)
 	normalizedS := padToLengthBytesInPlace(s.Bytes(), 32)
@@ -266,3 +573,59 @@ func fillBytes(x *big.Int, buf []byte) []byte {
 	}
 	return buf
 }
+
+func TestE2EQuorum(t *testing.T) {
+	setUp("error")
+
+	// the first party of the key is offline; any other parties may sign
+	keys, pIDs, err := keygen.LoadKeygenTestFixtures(testParticipants)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	quorum, err := tss.SelectRandomQuorum(tss.S256(), pIDs, testThreshold, pIDs[1:], []byte("message 42"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	errCh := make(chan *tss.Error, len(quorum.Signers))
+	outCh := make(chan tss.Message, len(quorum.Signers))
+	endCh := make(chan *common.SignatureData, len(quorum.Signers))
+	parties := make([]*LocalParty, 0, len(quorum.Signers))
+	for _, signer := range quorum.Signers {
+		params, err := quorum.Parameters(signer)
+		if !assert.NoError(t, err) {
+			return
+		}
+		// the signer takes the save data of the whole key
+		key := keys[pIDs.FindByKey(signer.KeyInt()).Index]
+		P := NewLocalParty(big.NewInt(42), params, key, outCh, endCh).(*LocalParty)
+		parties = append(parties, P)
+		go func(P *LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	for ended := 0; ended < len(parties); {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+		case msg := <-outCh:
+			if dest := msg.GetTo(); dest == nil {
+				for _, P := range parties {
+					if P.PartyID().Index != msg.GetFrom().Index {
+						go test.SharedPartyUpdater(P, msg, errCh)
+					}
+				}
+			} else {
+				go test.SharedPartyUpdater(parties[dest[0].Index], msg, errCh)
+			}
+		case data := <-endCh:
+			der, err := data.DERSignature()
+			assert.NoError(t, err)
+			assert.True(t, ecdsa.VerifyASN1(keys[0].ECDSAPub.ToECDSAPubKey(), data.M, der), "DER signature must verify")
+			ended++
+		}
+	}
+}
@@ -8,6 +8,7 @@ package signing
 
 import (
 	"crypto/elliptic"
+	"errors"
 	"math/big"
 
 	"github.com/kisdex/mpc-lib/common"
@@ -22,19 +23,20 @@ import (
 // The following messages are registered on the Protocol Buffers "wire"
 
 var (
-	// Ensure that signing messages implement ValidateBasic
-	_ = []tss.MessageContent{
-		(*SignRound1Message1)(nil),
-		(*SignRound1Message2)(nil),
-		(*SignRound2Message)(nil),
-		(*SignRound3Message)(nil),
-		(*SignRound4Message)(nil),
-		(*SignRound5Message)(nil),
-		(*SignRound6Message)(nil),
-		(*SignRound7Message)(nil),
-		(*SignRound8Message)(nil),
-		(*SignRound9Message)(nil),
-	}
+	// Ensure that signing messages implement ValidateBasic, and accept only them from the wire
+	messageTypes = tss.NewMessageTypes(
+		&SignRound1Message1{},
+		&SignRound1Message2{},
+		&SignRound2Message{},
+		&SignRound3Message{},
+		&SignRound4Message{},
+		&SignRound5Message{},
+		&SignRound6Message{},
+		&SignRound7Message{},
+		&SignRound8Message{},
+		&SignRound9Message{},
+		&SignBatchMessage{},
+	)
 )
 
 // ----- //
@@ -168,6 +170,8 @@ func NewSignRound4Message(
 	from *tss.PartyID,
 	deCommitment cmt.HashDeCommitment,
 	proof *schnorr.ZKProof,
+	adaptorGamma *crypto.ECPoint,
+	adaptorProof *schnorr.DLEQProof,
 ) tss.ParsedMessage {
 	meta := tss.MessageRouting{
 		From:        from,
@@ -176,20 +180,30 @@ func NewSignRound4Message(
 	dcBzs := common.BigIntsToBytes(deCommitment)
 	content := &SignRound4Message{
 		DeCommitment: dcBzs,
-		ProofAlphaX:  proof.Alpha.X().Bytes(),
-		ProofAlphaY:  proof.Alpha.Y().Bytes(),
+		ProofAlpha:   proof.Alpha.Bytes(),
 		ProofT:       proof.T.Bytes(),
 	}
+	if adaptorGamma != nil && adaptorProof != nil {
+		content.AdaptorGamma = [][]byte{adaptorGamma.Bytes()}
+		content.AdaptorProof = adaptorProof.Bytes()
+	}
 	msg := tss.NewMessageWrapper(meta, content)
 	return tss.NewMessage(meta, content, msg)
 }
 
 func (m *SignRound4Message) ValidateBasic() bool {
 	return m != nil &&
-		common.NonEmptyMultiBytes(m.DeCommitment, 3) &&
-		common.NonEmptyBytes(m.ProofAlphaX) &&
-		common.NonEmptyBytes(m.ProofAlphaY) &&
-		common.NonEmptyBytes(m.ProofT)
+		common.NonEmptyMultiBytes(m.DeCommitment, 2) &&
+		common.NonEmptyBytes(m.ProofAlpha) &&
+		common.NonEmptyBytes(m.ProofT) &&
+		(m.HasAdaptor() ||
+			(len(m.AdaptorGamma) == 0 && len(m.AdaptorProof) == 0))
+}
+
+// HasAdaptor reports whether the sender attached its share of the adaptor nonce point
+func (m *SignRound4Message) HasAdaptor() bool {
+	return common.NonEmptyMultiBytes(m.GetAdaptorGamma(), 1) &&
+		common.NonEmptyMultiBytes(m.GetAdaptorProof(), schnorr.DLEQProofBytesParts)
 }
 
 func (m *SignRound4Message) UnmarshalDeCommitment() []*big.Int {
@@ -198,10 +212,7 @@ func (m *SignRound4Message) UnmarshalDeCommitment() []*big.Int {
 }
 
 func (m *SignRound4Message) UnmarshalZKProof(ec elliptic.Curve) (*schnorr.ZKProof, error) {
-	point, err := crypto.NewECPoint(
-		ec,
-		new(big.Int).SetBytes(m.GetProofAlphaX()),
-		new(big.Int).SetBytes(m.GetProofAlphaY()))
+	point, err := crypto.NewECPointFromBytes(ec, m.GetProofAlpha())
 	if err != nil {
 		return nil, err
 	}
@@ -211,6 +222,18 @@ func (m *SignRound4Message) UnmarshalZKProof(ec elliptic.Curve) (*schnorr.ZKProo
 	}, nil
 }
 
+func (m *SignRound4Message) UnmarshalAdaptorGamma(ec elliptic.Curve) (*crypto.ECPoint, error) {
+	bzs := m.GetAdaptorGamma()
+	if len(bzs) != 1 {
+		return nil, errors.New("expected the adaptor gamma to be 1 encoded point")
+	}
+	return crypto.NewECPointFromBytes(ec, bzs[0])
+}
+
+func (m *SignRound4Message) UnmarshalAdaptorProof(ec elliptic.Curve) (*schnorr.DLEQProof, error) {
+	return schnorr.NewDLEQProofFromBytes(ec, m.GetAdaptorProof())
+}
+
 // ----- //
 
 func NewSignRound5Message(
@@ -252,11 +275,9 @@ func NewSignRound6Message(
 	dcBzs := common.BigIntsToBytes(deCommitment)
 	content := &SignRound6Message{
 		DeCommitment: dcBzs,
-		ProofAlphaX:  proof.Alpha.X().Bytes(),
-		ProofAlphaY:  proof.Alpha.Y().Bytes(),
+		ProofAlpha:   proof.Alpha.Bytes(),
 		ProofT:       proof.T.Bytes(),
-		VProofAlphaX: vProof.Alpha.X().Bytes(),
-		VProofAlphaY: vProof.Alpha.Y().Bytes(),
+		VProofAlpha:  vProof.Alpha.Bytes(),
 		VProofT:      vProof.T.Bytes(),
 		VProofU:      vProof.U.Bytes(),
 	}
@@ -266,12 +287,10 @@ func NewSignRound6Message(
 
 func (m *SignRound6Message) ValidateBasic() bool {
 	return m != nil &&
-		common.NonEmptyMultiBytes(m.DeCommitment, 5) &&
-		common.NonEmptyBytes(m.ProofAlphaX) &&
-		common.NonEmptyBytes(m.ProofAlphaY) &&
+		common.NonEmptyMultiBytes(m.DeCommitment, 3) &&
+		common.NonEmptyBytes(m.ProofAlpha) &&
 		common.NonEmptyBytes(m.ProofT) &&
-		common.NonEmptyBytes(m.VProofAlphaX) &&
-		common.NonEmptyBytes(m.VProofAlphaY) &&
+		common.NonEmptyBytes(m.VProofAlpha) &&
 		common.NonEmptyBytes(m.VProofT) &&
 		common.NonEmptyBytes(m.VProofU)
 }
@@ -282,10 +301,7 @@ func (m *SignRound6Message) UnmarshalDeCommitment() []*big.Int {
 }
 
 func (m *SignRound6Message) UnmarshalZKProof(ec elliptic.Curve) (*schnorr.ZKProof, error) {
-	point, err := crypto.NewECPoint(
-		ec,
-		new(big.Int).SetBytes(m.GetProofAlphaX()),
-		new(big.Int).SetBytes(m.GetProofAlphaY()))
+	point, err := crypto.NewECPointFromBytes(ec, m.GetProofAlpha())
 	if err != nil {
 		return nil, err
 	}
@@ -296,10 +312,7 @@ func (m *SignRound6Message) UnmarshalZKProof(ec elliptic.Curve) (*schnorr.ZKProo
 }
 
 func (m *SignRound6Message) UnmarshalZKVProof(ec elliptic.Curve) (*schnorr.ZKVProof, error) {
-	point, err := crypto.NewECPoint(
-		ec,
-		new(big.Int).SetBytes(m.GetVProofAlphaX()),
-		new(big.Int).SetBytes(m.GetVProofAlphaY()))
+	point, err := crypto.NewECPointFromBytes(ec, m.GetVProofAlpha())
 	if err != nil {
 		return nil, err
 	}
@@ -356,7 +369,7 @@ func NewSignRound8Message(
 
 func (m *SignRound8Message) ValidateBasic() bool {
 	return m != nil &&
-		common.NonEmptyMultiBytes(m.DeCommitment, 5)
+		common.NonEmptyMultiBytes(m.DeCommitment, 3)
 }
 
 func (m *SignRound8Message) UnmarshalDeCommitment() []*big.Int {
@@ -389,3 +402,29 @@ func (m *SignRound9Message) ValidateBasic() bool {
 func (m *SignRound9Message) UnmarshalS() *big.Int {
 	return new(big.Int).SetBytes(m.S)
 }
+
+// ----- //
+
+func NewSignBatchMessage(
+	from, to *tss.PartyID,
+	isBroadcast bool,
+	messages [][]byte,
+) tss.ParsedMessage {
+	meta := tss.MessageRouting{
+		From:        from,
+		IsBroadcast: isBroadcast,
+	}
+	if to != nil {
+		meta.To = []*tss.PartyID{to}
+	}
+	content := &SignBatchMessage{
+		Messages: messages,
+	}
+	msg := tss.NewMessageWrapper(meta, content)
+	return tss.NewMessage(meta, content, msg)
+}
+
+func (m *SignBatchMessage) ValidateBasic() bool {
+	return m != nil &&
+		common.NonEmptyMultiBytes(m.Messages)
+}
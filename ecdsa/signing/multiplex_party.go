@@ -0,0 +1,395 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/ecdsa/keygen"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+// Implements Party
+// Implements Stringer
+var _ tss.Party = (*MultiplexLocalParty)(nil)
+var _ fmt.Stringer = (*MultiplexLocalParty)(nil)
+var _ tss.Zeroizer = (*MultiplexLocalParty)(nil)
+var _ tss.Round = (*multiplexRound)(nil)
+
+type (
+	// MultiplexLocalParty multiplexes one independent GG18 signing per digest over a single session. The instances
+	// advance their rounds in lockstep: the messages that they send to the same recipient in a round travel together
+	// in one SignBatchMessage, and the messages that it carries are processed by all of the instances concurrently.
+	// It saves messages and round trips, not computation: every digest gets its own nonce and therefore its own MtA
+	// exchanges and proofs, so signing k digests costs the computation of k signings. Sharing the Paillier and range
+	// proofs across the digests would take a batched MtA, which this party does not implement.
+	MultiplexLocalParty struct {
+		*tss.BaseParty
+		params *tss.Parameters
+
+		parties []*LocalParty
+		state   *multiplexState
+	}
+
+	// multiplexState is the plumbing between the multiplexed signing instances and the wire, which outlives the rounds
+	multiplexState struct {
+		outs []chan tss.Message
+		ends []chan *common.SignatureData
+
+		// outgoing instance messages, grouped until every instance has produced its own
+		pending      map[string][]tss.Message
+		pendingOrder []string
+
+		sigs     []*common.SignatureData
+		finished bool
+
+		// outbound messaging
+		out chan<- tss.Message
+		end chan<- []*common.SignatureData
+	}
+
+	// multiplexRound is the current round of every multiplexed signing instance
+	multiplexRound struct {
+		*multiplexState
+		params *tss.Parameters
+		rounds []tss.Round
+	}
+)
+
+// NewMultiplexLocalParty returns a party that signs every one of `msgs` with its own signing instance, multiplexed
+// over the messages of a single session; it ends with the signatures in the order of `msgs`
+func NewMultiplexLocalParty(
+	msgs []*big.Int,
+	params *tss.Parameters,
+	key keygen.LocalPartySaveData,
+	out chan<- tss.Message,
+	end chan<- []*common.SignatureData) tss.Party {
+	return NewMultiplexLocalPartyWithKDD(msgs, params, key, nil, out, end)
+}
+
+// NewMultiplexLocalPartyWithKDD returns a multiplexing party with key derivation delta for HD support
+func NewMultiplexLocalPartyWithKDD(
+	msgs []*big.Int,
+	params *tss.Parameters,
+	key keygen.LocalPartySaveData,
+	keyDerivationDelta *big.Int,
+	out chan<- tss.Message,
+	end chan<- []*common.SignatureData,
+) tss.Party {
+	partyCount := len(params.Parties().IDs())
+	p := &MultiplexLocalParty{
+		BaseParty: new(tss.BaseParty),
+		params:    params,
+		parties:   make([]*LocalParty, len(msgs)),
+		state: &multiplexState{
+			outs:    make([]chan tss.Message, len(msgs)),
+			ends:    make([]chan *common.SignatureData, len(msgs)),
+			pending: make(map[string][]tss.Message),
+			sigs:    make([]*common.SignatureData, len(msgs)),
+			out:     out,
+			end:     end,
+		},
+	}
+	for i, msg := range msgs {
+		// an instance sends fewer messages than this during a whole session, so it never blocks on its channel
+		p.state.outs[i] = make(chan tss.Message, 2*partyCount+8)
+		p.state.ends[i] = make(chan *common.SignatureData, 1)
+		p.parties[i] = NewLocalPartyWithKDD(msg, params, key, keyDerivationDelta, p.state.outs[i], p.state.ends[i]).(*LocalParty)
+		// bind each instance's proofs to its position among the digests
+		p.parties[i].temp.ssidNonce = big.NewInt(int64(i))
+	}
+	return p
+}
+
+// FirstRound returns the first round of every signing instance; the instances are driven by the rounds of the
+// multiplexing party rather than by their own
+func (p *MultiplexLocalParty) FirstRound() tss.Round {
+	rounds := make([]tss.Round, len(p.parties))
+	for i, P := range p.parties {
+		rounds[i] = P.FirstRound()
+	}
+	return &multiplexRound{multiplexState: p.state, params: p.params, rounds: rounds}
+}
+
+func (p *MultiplexLocalParty) Start() *tss.Error {
+	if len(p.parties) == 0 {
+		return p.WrapError(errors.New("could not start. there are no messages to sign"))
+	}
+	return tss.BaseStart(p, TaskName, func(round tss.Round) *tss.Error {
+		batch, ok := round.(*multiplexRound)
+		if !ok {
+			return round.WrapError(errors.New("unable to Start(). party is in an unexpected round"))
+		}
+		for _, r := range batch.rounds {
+			round1, ok := r.(*round1)
+			if !ok {
+				return round.WrapError(errors.New("unable to Start(). party is in an unexpected round"))
+			}
+			if err := round1.prepare(); err != nil {
+				return round.WrapError(err)
+			}
+		}
+		return nil
+	})
+}
+
+func (p *MultiplexLocalParty) Update(msg tss.ParsedMessage) (ok bool, err *tss.Error) {
+	return tss.BaseUpdate(p, msg, TaskName)
+}
+
+func (p *MultiplexLocalParty) UpdateFromBytes(wireBytes []byte, from *tss.PartyID, isBroadcast bool) (bool, *tss.Error) {
+	msg, err := messageTypes.ParseWireMessage(wireBytes, from, isBroadcast)
+	if err != nil {
+		return false, p.WrapError(err)
+	}
+	return p.Update(msg)
+}
+
+func (p *MultiplexLocalParty) ValidateMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
+	if ok, err := p.BaseParty.ValidateMessage(msg); !ok || err != nil {
+		return ok, err
+	}
+	// check that the message's "from index" will fit into the array
+	if maxFromIdx := len(p.params.Parties().IDs()) - 1; maxFromIdx < msg.GetFrom().Index {
+		return false, p.WrapError(fmt.Errorf("received msg with a sender index too great (%d <= %d)",
+			maxFromIdx, msg.GetFrom().Index), msg.GetFrom())
+	}
+	batch, ok := msg.Content().(*SignBatchMessage)
+	if !ok {
+		return false, p.WrapError(fmt.Errorf("received msg of unexpected type %s", msg.Type()), msg.GetFrom())
+	}
+	if len(batch.GetMessages()) != len(p.parties) {
+		return false, p.WrapError(fmt.Errorf("received a batch of %d messages, expected %d",
+			len(batch.GetMessages()), len(p.parties)), msg.GetFrom())
+	}
+	return true, nil
+}
+
+// StoreMessage hands the messages of a batch to the signing instances without advancing them
+func (p *MultiplexLocalParty) StoreMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
+	if ok, err := p.ValidateMessage(msg); !ok || err != nil {
+		return ok, err
+	}
+	instanceMsgs, err := parseBatch(msg, len(p.parties))
+	if err != nil {
+		return false, p.WrapError(err, msg.GetFrom())
+	}
+	for i, P := range p.parties {
+		if ok, err := P.StoreMessage(instanceMsgs[i]); !ok || err != nil {
+			return ok, err
+		}
+	}
+	return true, nil
+}
+
+func (p *MultiplexLocalParty) PartyID() *tss.PartyID {
+	return p.params.PartyID()
+}
+
+func (p *MultiplexLocalParty) String() string {
+	return fmt.Sprintf("id: %s, %d multiplexed signings", p.PartyID(), len(p.parties))
+}
+
+// Zeroize wipes the nonces and the other secrets of every signing instance, see tss.Zeroizer
+func (p *MultiplexLocalParty) Zeroize() {
+	for _, P := range p.parties {
+		P.Zeroize()
+	}
+}
+
+// ----- //
+
+func (round *multiplexRound) Params() *tss.Parameters {
+	return round.params
+}
+
+func (round *multiplexRound) RoundNumber() int {
+	return round.rounds[0].RoundNumber()
+}
+
+func (round *multiplexRound) Start() *tss.Error {
+	if err := round.forEachInstance(func(r tss.Round) *tss.Error {
+		return r.Start()
+	}); err != nil {
+		return err
+	}
+	return round.flush()
+}
+
+func (round *multiplexRound) Update() (bool, *tss.Error) {
+	if err := round.forEachInstance(func(r tss.Round) *tss.Error {
+		_, err := r.Update()
+		return err
+	}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CanAccept reports whether the instances accept the messages of a batch, which are of the same round
+func (round *multiplexRound) CanAccept(msg tss.ParsedMessage) bool {
+	batch, ok := msg.Content().(*SignBatchMessage)
+	if !ok || len(batch.GetMessages()) == 0 {
+		return false
+	}
+	first, err := messageTypes.ParseWireMessage(batch.GetMessages()[0], msg.GetFrom(), msg.IsBroadcast())
+	return err == nil && round.rounds[0].CanAccept(first)
+}
+
+func (round *multiplexRound) CanProceed() bool {
+	for _, r := range round.rounds {
+		if !r.CanProceed() {
+			return false
+		}
+	}
+	return true
+}
+
+// NextRound returns the next round of every instance, or nil once they have finished; they finish together
+func (round *multiplexRound) NextRound() tss.Round {
+	rounds := make([]tss.Round, len(round.rounds))
+	for i, r := range round.rounds {
+		if rounds[i] = r.NextRound(); rounds[i] == nil {
+			return nil
+		}
+	}
+	return &multiplexRound{multiplexState: round.multiplexState, params: round.params, rounds: rounds}
+}
+
+func (round *multiplexRound) WaitingFor() []*tss.PartyID {
+	seen := make(map[int]bool)
+	ids := make([]*tss.PartyID, 0, len(round.params.Parties().IDs()))
+	for _, r := range round.rounds {
+		for _, Pj := range r.WaitingFor() {
+			if !seen[Pj.Index] {
+				seen[Pj.Index] = true
+				ids = append(ids, Pj)
+			}
+		}
+	}
+	return ids
+}
+
+func (round *multiplexRound) WrapError(err error, culprits ...*tss.PartyID) *tss.Error {
+	return tss.NewError(err, TaskName, round.RoundNumber(), round.params.PartyID(), culprits...)
+}
+
+// ----- //
+
+// parseBatch returns the message of each of the `count` instances of a batch
+func parseBatch(msg tss.ParsedMessage, count int) ([]tss.ParsedMessage, error) {
+	batch := msg.Content().(*SignBatchMessage)
+	instanceMsgs := make([]tss.ParsedMessage, count)
+	for i, bz := range batch.GetMessages() {
+		instanceMsg, err := messageTypes.ParseWireMessage(bz, msg.GetFrom(), msg.IsBroadcast())
+		if err != nil {
+			return nil, fmt.Errorf("could not parse message %d of the batch: %w", i, err)
+		}
+		instanceMsgs[i] = instanceMsg
+	}
+	return instanceMsgs, nil
+}
+
+// forEachInstance runs `fn` for the round of every signing instance concurrently and returns the first error, if any
+func (round *multiplexRound) forEachInstance(fn func(tss.Round) *tss.Error) *tss.Error {
+	errs := make([]*tss.Error, len(round.rounds))
+	pool := round.params.NewWorkerPool()
+	for i, r := range round.rounds {
+		pool.Go(func() {
+			errs[i] = fn(r)
+		})
+	}
+	pool.Wait()
+	for i, err := range errs {
+		if err != nil {
+			return tss.NewError(fmt.Errorf("signing instance %d: %w", i, err.Cause()),
+				err.Task(), err.Round(), err.Victim(), err.Culprits()...)
+		}
+	}
+	return nil
+}
+
+// flush collects the messages and signatures produced by the instances, sends every complete group of
+// messages as one batch and, once all of the instances are done, the signatures in the order of the digests
+func (round *multiplexRound) flush() *tss.Error {
+	for i, ch := range round.outs {
+	drain:
+		for {
+			select {
+			case msg := <-ch:
+				key := msg.Type()
+				if to := msg.GetTo(); to != nil {
+					key += "/" + strconv.Itoa(to[0].Index)
+				}
+				if _, ok := round.pending[key]; !ok {
+					round.pending[key] = make([]tss.Message, len(round.rounds))
+					round.pendingOrder = append(round.pendingOrder, key)
+				}
+				if round.pending[key][i] != nil {
+					return round.WrapError(fmt.Errorf("signing instance %d sent %s twice", i, key))
+				}
+				round.pending[key][i] = msg
+			default:
+				break drain
+			}
+		}
+	}
+	remaining := round.pendingOrder[:0]
+	for _, key := range round.pendingOrder {
+		group := round.pending[key]
+		if !allSent(group) {
+			remaining = append(remaining, key)
+			continue
+		}
+		bzs := make([][]byte, len(group))
+		for i, msg := range group {
+			bz, _, err := msg.WireBytes()
+			if err != nil {
+				return round.WrapError(err)
+			}
+			bzs[i] = bz
+		}
+		var to *tss.PartyID
+		if dest := group[0].GetTo(); dest != nil {
+			to = dest[0]
+		}
+		round.out <- NewSignBatchMessage(round.params.PartyID(), to, group[0].IsBroadcast(), bzs)
+		delete(round.pending, key)
+	}
+	round.pendingOrder = remaining
+
+	for i, ch := range round.ends {
+		select {
+		case sig := <-ch:
+			round.sigs[i] = sig
+		default:
+		}
+	}
+	for _, sig := range round.sigs {
+		if sig == nil {
+			return nil
+		}
+	}
+	if !round.finished {
+		round.finished = true
+		round.end <- round.sigs
+	}
+	return nil
+}
+
+func allSent(group []tss.Message) bool {
+	for _, msg := range group {
+		if msg == nil {
+			return false
+		}
+	}
+	return true
+}
@@ -9,7 +9,6 @@ package signing
 import (
 	"errors"
 	"math/big"
-	"sync"
 
 	errorspkg "github.com/pkg/errors"
 
@@ -29,20 +28,18 @@ func (round *round2) Start() *tss.Error {
 	round.ok[i] = true
 
 	errChs := make(chan *tss.Error, (len(round.Parties().IDs())-1)*2)
-	wg := sync.WaitGroup{}
-	wg.Add((len(round.Parties().IDs()) - 1) * 2)
+	pool := round.NewWorkerPool()
 	ContextI := append(round.temp.ssid, new(big.Int).SetUint64(uint64(i)).Bytes()...)
 	for j, Pj := range round.Parties().IDs() {
 		if j == i {
 			continue
 		}
 		// Bob_mid
-		go func(j int, Pj *tss.PartyID) {
-			defer wg.Done()
+		pool.GoFor(Pj, func() {
 			r1msg := round.temp.signRound1Message1s[j].Content().(*SignRound1Message1)
 			rangeProofAliceJ, err := r1msg.UnmarshalRangeProofAlice()
 			if err != nil {
-				errChs <- round.WrapError(errorspkg.Wrapf(err, "UnmarshalRangeProofAlice failed"), Pj)
+				errChs <- round.WrapError(errorspkg.Wrapf(err, "UnmarshalRangeProofAlice failed"), Pj).WithCode(tss.ErrInvalidMessage)
 				return
 			}
 			beta, c1ji, _, pi1ji, err := mta.BobMid(
@@ -63,16 +60,15 @@ func (round *round2) Start() *tss.Error {
 			round.temp.c1jis[j] = c1ji
 			round.temp.pi1jis[j] = pi1ji
 			if err != nil {
-				errChs <- round.WrapError(err, Pj)
+				errChs <- round.WrapError(err, Pj).WithCode(tss.ErrProofVerifyFailed)
 			}
-		}(j, Pj)
+		})
 		// Bob_mid_wc
-		go func(j int, Pj *tss.PartyID) {
-			defer wg.Done()
+		pool.GoFor(Pj, func() {
 			r1msg := round.temp.signRound1Message1s[j].Content().(*SignRound1Message1)
 			rangeProofAliceJ, err := r1msg.UnmarshalRangeProofAlice()
 			if err != nil {
-				errChs <- round.WrapError(errorspkg.Wrapf(err, "UnmarshalRangeProofAlice failed"), Pj)
+				errChs <- round.WrapError(errorspkg.Wrapf(err, "UnmarshalRangeProofAlice failed"), Pj).WithCode(tss.ErrInvalidMessage)
 				return
 			}
 			v, c2ji, _, pi2ji, err := mta.BobMidWC(
@@ -93,19 +89,21 @@ func (round *round2) Start() *tss.Error {
 			round.temp.c2jis[j] = c2ji
 			round.temp.pi2jis[j] = pi2ji
 			if err != nil {
-				errChs <- round.WrapError(err, Pj)
+				errChs <- round.WrapError(err, Pj).WithCode(tss.ErrProofVerifyFailed)
 			}
-		}(j, Pj)
+		})
 	}
 	// consume error channels; wait for goroutines
-	wg.Wait()
+	pool.Wait()
 	close(errChs)
 	culprits := make([]*tss.PartyID, 0, len(round.Parties().IDs()))
+	errs := make([]*tss.Error, 0, len(errChs))
 	for err := range errChs {
 		culprits = append(culprits, err.Culprits()...)
+		errs = append(errs, err)
 	}
 	if len(culprits) > 0 {
-		return round.WrapError(errors.New("failed to calculate Bob_mid or Bob_mid_wc"), culprits...)
+		return round.WrapError(errors.New("failed to calculate Bob_mid or Bob_mid_wc"), culprits...).WithCode(tss.CommonErrorCode(errs...))
 	}
 	// create and send messages
 	for j, Pj := range round.Parties().IDs() {
@@ -114,7 +112,7 @@ func (round *round2) Start() *tss.Error {
 		}
 		r2msg := NewSignRound2Message(
 			Pj, round.PartyID(), round.temp.c1jis[j], round.temp.pi1jis[j], round.temp.c2jis[j], round.temp.pi2jis[j])
-		round.out <- r2msg
+		round.SendMessage(round.out, r2msg)
 	}
 	return nil
 }
@@ -9,7 +9,6 @@ package signing
 import (
 	"errors"
 	"math/big"
-	"sync"
 
 	errorspkg "github.com/pkg/errors"
 
@@ -32,20 +31,18 @@ func (round *round3) Start() *tss.Error {
 	i := round.PartyID().Index
 
 	errChs := make(chan *tss.Error, (len(round.Parties().IDs())-1)*2)
-	wg := sync.WaitGroup{}
-	wg.Add((len(round.Parties().IDs()) - 1) * 2)
+	pool := round.NewWorkerPool()
 	for j, Pj := range round.Parties().IDs() {
 		if j == i {
 			continue
 		}
 		ContextJ := append(round.temp.ssid, new(big.Int).SetUint64(uint64(j)).Bytes()...)
 		// Alice_end
-		go func(j int, Pj *tss.PartyID) {
-			defer wg.Done()
+		pool.GoFor(Pj, func() {
 			r2msg := round.temp.signRound2Messages[j].Content().(*SignRound2Message)
 			proofBob, err := r2msg.UnmarshalProofBob()
 			if err != nil {
-				errChs <- round.WrapError(errorspkg.Wrapf(err, "UnmarshalProofBob failed"), Pj)
+				errChs <- round.WrapError(errorspkg.Wrapf(err, "UnmarshalProofBob failed"), Pj).WithCode(tss.ErrInvalidMessage)
 				return
 			}
 			alphaIj, err := mta.AliceEnd(
@@ -61,16 +58,15 @@ func (round *round3) Start() *tss.Error {
 				round.key.PaillierSK)
 			alphas[j] = alphaIj
 			if err != nil {
-				errChs <- round.WrapError(err, Pj)
+				errChs <- round.WrapError(err, Pj).WithCode(tss.ErrProofVerifyFailed)
 			}
-		}(j, Pj)
+		})
 		// Alice_end_wc
-		go func(j int, Pj *tss.PartyID) {
-			defer wg.Done()
+		pool.GoFor(Pj, func() {
 			r2msg := round.temp.signRound2Messages[j].Content().(*SignRound2Message)
 			proofBobWC, err := r2msg.UnmarshalProofBobWC(round.Parameters.EC())
 			if err != nil {
-				errChs <- round.WrapError(errorspkg.Wrapf(err, "UnmarshalProofBobWC failed"), Pj)
+				errChs <- round.WrapError(errorspkg.Wrapf(err, "UnmarshalProofBobWC failed"), Pj).WithCode(tss.ErrInvalidMessage)
 				return
 			}
 			uIj, err := mta.AliceEndWC(
@@ -87,20 +83,22 @@ func (round *round3) Start() *tss.Error {
 				round.key.PaillierSK)
 			us[j] = uIj
 			if err != nil {
-				errChs <- round.WrapError(err, Pj)
+				errChs <- round.WrapError(err, Pj).WithCode(tss.ErrProofVerifyFailed)
 			}
-		}(j, Pj)
+		})
 	}
 
 	// consume error channels; wait for goroutines
-	wg.Wait()
+	pool.Wait()
 	close(errChs)
 	culprits := make([]*tss.PartyID, 0, len(round.Parties().IDs()))
+	errs := make([]*tss.Error, 0, len(errChs))
 	for err := range errChs {
 		culprits = append(culprits, err.Culprits()...)
+		errs = append(errs, err)
 	}
 	if len(culprits) > 0 {
-		return round.WrapError(errors.New("failed to calculate Alice_end or Alice_end_wc"), culprits...)
+		return round.WrapError(errors.New("failed to calculate Alice_end or Alice_end_wc"), culprits...).WithCode(tss.CommonErrorCode(errs...))
 	}
 
 	modN := common.ModInt(round.Params().EC().Params().N)
@@ -119,7 +117,7 @@ func (round *round3) Start() *tss.Error {
 	round.temp.sigma = sigma
 	r3msg := NewSignRound3Message(round.PartyID(), thelta)
 	round.temp.signRound3Messages[round.PartyID().Index] = r3msg
-	round.out <- r3msg
+	round.SendMessage(round.out, r3msg)
 
 	return nil
 }
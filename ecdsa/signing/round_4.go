@@ -13,6 +13,7 @@ import (
 	errors2 "github.com/pkg/errors"
 
 	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto"
 	"github.com/kisdex/mpc-lib/crypto/schnorr"
 	"github.com/kisdex/mpc-lib/tss"
 )
@@ -47,10 +48,19 @@ func (round *round4) Start() *tss.Error {
 	if err != nil {
 		return round.WrapError(errors2.Wrapf(err, "NewZKProof(gamma, bigGamma)"))
 	}
+	var adaptorGamma *crypto.ECPoint
+	var piAdaptor *schnorr.DLEQProof
+	if T := round.temp.adaptorPoint; T != nil {
+		adaptorGamma = T.ScalarMult(round.temp.gamma)
+		piAdaptor, err = schnorr.NewDLEQProof(ContextI, round.temp.gamma, T, round.temp.pointGamma, adaptorGamma)
+		if err != nil {
+			return round.WrapError(errors2.Wrapf(err, "NewDLEQProof(gamma, bigGamma, adaptorGamma)"))
+		}
+	}
 	round.temp.thetaInverse = thetaInverse
-	r4msg := NewSignRound4Message(round.PartyID(), round.temp.deCommit, piGamma)
+	r4msg := NewSignRound4Message(round.PartyID(), round.temp.deCommit, piGamma, adaptorGamma, piAdaptor)
 	round.temp.signRound4Messages[round.PartyID().Index] = r4msg
-	round.out <- r4msg
+	round.SendMessage(round.out, r4msg)
 
 	return nil
 }
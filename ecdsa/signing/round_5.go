@@ -27,6 +27,12 @@ func (round *round5) Start() *tss.Error {
 	round.resetOK()
 
 	R := round.temp.pointGamma
+	// when signing against an adaptor point T, the nonce point of the final signature is k^-1 * T
+	T := round.temp.adaptorPoint
+	var adaptorR *crypto.ECPoint
+	if T != nil {
+		adaptorR = T.ScalarMult(round.temp.gamma)
+	}
 	for j, Pj := range round.Parties().IDs() {
 		if j == round.PartyID().Index {
 			continue
@@ -37,10 +43,10 @@ func (round *round5) Start() *tss.Error {
 		SCj, SDj := r1msg2.UnmarshalCommitment(), r4msg.UnmarshalDeCommitment()
 		cmtDeCmt := commitments.HashCommitDecommit{C: SCj, D: SDj}
 		ok, bigGammaJ := cmtDeCmt.DeCommit()
-		if !ok || len(bigGammaJ) != 2 {
-			return round.WrapError(errors.New("commitment verify failed"), Pj)
+		if !ok || len(bigGammaJ) != 1 {
+			return round.WrapError(errors.New("commitment verify failed"), Pj).WithCode(tss.ErrProofVerifyFailed)
 		}
-		bigGammaJPoint, err := crypto.NewECPoint(round.Params().EC(), bigGammaJ[0], bigGammaJ[1])
+		bigGammaJPoint, err := crypto.NewECPointFromBytes(round.Params().EC(), bigGammaJ[0].Bytes())
 		if err != nil {
 			return round.WrapError(errors2.Wrapf(err, "NewECPoint(bigGammaJ)"), Pj)
 		}
@@ -48,14 +54,33 @@ func (round *round5) Start() *tss.Error {
 		if err != nil {
 			return round.WrapError(errors.New("failed to unmarshal bigGamma proof"), Pj)
 		}
-		ok = proof.Verify(ContextJ, bigGammaJPoint)
+		ok = round.VerifyProof("schnorr", func() bool { return proof.Verify(ContextJ, bigGammaJPoint) })
 		if !ok {
-			return round.WrapError(errors.New("failed to prove bigGamma"), Pj)
+			return round.WrapError(errors.New("failed to prove bigGamma"), Pj).WithCode(tss.ErrProofVerifyFailed)
 		}
 		R, err = R.Add(bigGammaJPoint)
 		if err != nil {
 			return round.WrapError(errors2.Wrapf(err, "R.Add(bigGammaJ)"), Pj)
 		}
+		if T != nil {
+			if !r4msg.HasAdaptor() {
+				return round.WrapError(errors.New("party did not send its adaptor gamma"), Pj)
+			}
+			adaptorGammaJ, err := r4msg.UnmarshalAdaptorGamma(round.Params().EC())
+			if err != nil {
+				return round.WrapError(errors2.Wrapf(err, "NewECPoint(adaptorGammaJ)"), Pj)
+			}
+			adaptorProof, err := r4msg.UnmarshalAdaptorProof(round.Params().EC())
+			if err != nil || !round.VerifyProof("adaptor", func() bool {
+				return adaptorProof.Verify(ContextJ, T, bigGammaJPoint, adaptorGammaJ)
+			}) {
+				return round.WrapError(errors.New("failed to prove adaptorGamma"), Pj).WithCode(tss.ErrProofVerifyFailed)
+			}
+			adaptorR, err = adaptorR.Add(adaptorGammaJ)
+			if err != nil {
+				return round.WrapError(errors2.Wrapf(err, "adaptorR.Add(adaptorGammaJ)"), Pj)
+			}
+		}
 	}
 
 	R = R.ScalarMult(round.temp.thetaInverse)
@@ -63,11 +88,15 @@ func (round *round5) Start() *tss.Error {
 	modN := common.ModInt(N)
 	rx := R.X()
 	ry := R.Y()
+	if T != nil {
+		// r is taken from k^-1 * T while R = k^-1 * G is kept for the checks of rounds 6-9, which then hold for s'
+		adaptorR = adaptorR.ScalarMult(round.temp.thetaInverse)
+		rx, ry = adaptorR.X(), adaptorR.Y()
+	}
 	si := modN.Add(modN.Mul(round.temp.m, round.temp.k), modN.Mul(rx, round.temp.sigma))
 
-	// clear temp.w and temp.k from memory, lint ignore
-	round.temp.w = zero
-	round.temp.k = zero
+	// w and k are no longer needed
+	common.Zeroize(round.temp.w, round.temp.k)
 
 	li := common.GetRandomPositiveInt(N)  // li
 	roI := common.GetRandomPositiveInt(N) // pi
@@ -79,10 +108,14 @@ func (round *round5) Start() *tss.Error {
 		return round.WrapError(errors2.Wrapf(err, "rToSi.Add(li)"))
 	}
 
-	cmt := commitments.NewHashCommitment(bigVi.X(), bigVi.Y(), bigAi.X(), bigAi.Y())
+	values, err := crypto.CompressECPoints([]*crypto.ECPoint{bigVi, bigAi})
+	if err != nil {
+		return round.WrapError(errors2.Wrapf(err, "CompressECPoints(bigVi, bigAi)"))
+	}
+	cmt := commitments.NewHashCommitment(values...)
 	r5msg := NewSignRound5Message(round.PartyID(), cmt.C)
 	round.temp.signRound5Messages[round.PartyID().Index] = r5msg
-	round.out <- r5msg
+	round.SendMessage(round.out, r5msg)
 
 	round.temp.li = li
 	round.temp.bigAi = bigAi
@@ -93,6 +126,7 @@ func (round *round5) Start() *tss.Error {
 	round.temp.rx = rx
 	round.temp.ry = ry
 	round.temp.bigR = R
+	round.temp.adaptorR = adaptorR
 
 	return nil
 }
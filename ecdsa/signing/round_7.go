@@ -38,27 +38,23 @@ func (round *round7) Start() *tss.Error {
 		cj, dj := r5msg.UnmarshalCommitment(), r6msg.UnmarshalDeCommitment()
 		cmtDeCmt := commitments.HashCommitDecommit{C: cj, D: dj}
 		ok, values := cmtDeCmt.DeCommit()
-		if !ok || len(values) != 4 {
+		if !ok || len(values) != 2 {
 			return round.WrapError(errors.New("de-commitment for bigVj and bigAj failed"), Pj)
 		}
-		bigVjX, bigVjY, bigAjX, bigAjY := values[0], values[1], values[2], values[3]
-		bigVj, err := crypto.NewECPoint(round.Params().EC(), bigVjX, bigVjY)
+		points, err := crypto.DecompressECPoints(round.Params().EC(), values)
 		if err != nil {
-			return round.WrapError(errors2.Wrapf(err, "NewECPoint(bigVj)"), Pj)
+			return round.WrapError(errors2.Wrapf(err, "DecompressECPoints(bigVj, bigAj)"), Pj)
 		}
+		bigVj, bigAj := points[0], points[1]
 		bigVjs[j] = bigVj
-		bigAj, err := crypto.NewECPoint(round.Params().EC(), bigAjX, bigAjY)
-		if err != nil {
-			return round.WrapError(errors2.Wrapf(err, "NewECPoint(bigAj)"), Pj)
-		}
 		bigAjs[j] = bigAj
 		pijA, err := r6msg.UnmarshalZKProof(round.Params().EC())
-		if err != nil || !pijA.Verify(ContextJ, bigAj) {
-			return round.WrapError(errors.New("schnorr verify for Aj failed"), Pj)
+		if err != nil || !round.VerifyProof("schnorr", func() bool { return pijA.Verify(ContextJ, bigAj) }) {
+			return round.WrapError(errors.New("schnorr verify for Aj failed"), Pj).WithCode(tss.ErrProofVerifyFailed)
 		}
 		pijV, err := r6msg.UnmarshalZKVProof(round.Params().EC())
-		if err != nil || !pijV.Verify(ContextJ, bigVj, round.temp.bigR) {
-			return round.WrapError(errors.New("vverify for Vj failed"), Pj)
+		if err != nil || !round.VerifyProof("schnorr_v", func() bool { return pijV.Verify(ContextJ, bigVj, round.temp.bigR) }) {
+			return round.WrapError(errors.New("vverify for Vj failed"), Pj).WithCode(tss.ErrProofVerifyFailed)
 		}
 	}
 
@@ -83,10 +79,14 @@ func (round *round7) Start() *tss.Error {
 	TiX, TiY := round.Params().EC().ScalarMult(AX, AY, round.temp.li.Bytes())
 	round.temp.Ui = crypto.NewECPointNoCurveCheck(round.Params().EC(), UiX, UiY)
 	round.temp.Ti = crypto.NewECPointNoCurveCheck(round.Params().EC(), TiX, TiY)
-	cmt := commitments.NewHashCommitment(UiX, UiY, TiX, TiY)
+	values, err := crypto.CompressECPoints([]*crypto.ECPoint{round.temp.Ui, round.temp.Ti})
+	if err != nil {
+		return round.WrapError(errors2.Wrapf(err, "CompressECPoints(Ui, Ti)"))
+	}
+	cmt := commitments.NewHashCommitment(values...)
 	r7msg := NewSignRound7Message(round.PartyID(), cmt.C)
 	round.temp.signRound7Messages[round.PartyID().Index] = r7msg
-	round.out <- r7msg
+	round.SendMessage(round.out, r7msg)
 	round.temp.DTelda = cmt.D
 
 	return nil
@@ -9,6 +9,7 @@ package signing
 import (
 	"errors"
 
+	"github.com/kisdex/mpc-lib/crypto"
 	"github.com/kisdex/mpc-lib/crypto/commitments"
 	"github.com/kisdex/mpc-lib/tss"
 )
@@ -33,12 +34,15 @@ func (round *round9) Start() *tss.Error {
 		cj, dj := r7msg.UnmarshalCommitment(), r8msg.UnmarshalDeCommitment()
 		cmt := commitments.HashCommitDecommit{C: cj, D: dj}
 		ok, values := cmt.DeCommit()
-		if !ok && len(values) != 4 {
-			return round.WrapError(errors.New("de-commitment for bigVj and bigAj failed"), Pj)
+		if !ok || len(values) != 2 {
+			return round.WrapError(errors.New("de-commitment for Uj and Tj failed"), Pj)
 		}
-		UjX, UjY, TjX, TjY := values[0], values[1], values[2], values[3]
-		UX, UY = round.Params().EC().Add(UX, UY, UjX, UjY)
-		TX, TY = round.Params().EC().Add(TX, TY, TjX, TjY)
+		points, err := crypto.DecompressECPoints(round.Params().EC(), values)
+		if err != nil {
+			return round.WrapError(err, Pj)
+		}
+		UX, UY = round.Params().EC().Add(UX, UY, points[0].X(), points[0].Y())
+		TX, TY = round.Params().EC().Add(TX, TY, points[1].X(), points[1].Y())
 	}
 	if UX.Cmp(TX) != 0 || UY.Cmp(TY) != 0 {
 		return round.WrapError(errors.New("U doesn't equal T"), round.PartyID())
@@ -46,7 +50,7 @@ func (round *round9) Start() *tss.Error {
 
 	r9msg := NewSignRound9Message(round.PartyID(), round.temp.si)
 	round.temp.signRound9Messages[round.PartyID().Index] = r9msg
-	round.out <- r9msg
+	round.SendMessage(round.out, r9msg)
 	return nil
 }
 
@@ -12,6 +12,7 @@ import (
 
 	"github.com/kisdex/mpc-lib/common"
 	"github.com/kisdex/mpc-lib/crypto"
+	"github.com/kisdex/mpc-lib/crypto/adaptor"
 	"github.com/kisdex/mpc-lib/ecdsa/keygen"
 	"github.com/kisdex/mpc-lib/tss"
 )
@@ -23,14 +24,15 @@ const (
 type (
 	base struct {
 		*tss.Parameters
-		key     *keygen.LocalPartySaveData
-		data    *common.SignatureData
-		temp    *localTempData
-		out     chan<- tss.Message
-		end     chan<- *common.SignatureData
-		ok      []bool // `ok` tracks parties which have been verified by Update()
-		started bool
-		number  int
+		key        *keygen.LocalPartySaveData
+		data       *common.SignatureData
+		temp       *localTempData
+		out        chan<- tss.Message
+		end        chan<- *common.SignatureData
+		adaptorEnd chan<- *adaptor.PreSignature
+		ok         []bool // `ok` tracks parties which have been verified by Update()
+		started    bool
+		number     int
 	}
 	round1 struct {
 		*base
@@ -134,11 +136,12 @@ func (round *base) getSSID() ([]byte, error) {
 	if err != nil {
 		return nil, round.WrapError(errors.New("read BigXj failed"), round.PartyID())
 	}
-	ssidList = append(ssidList, BigXjList...)                    // BigXj
-	ssidList = append(ssidList, round.key.NTildej...)            // NTilde
-	ssidList = append(ssidList, round.key.H1j...)                // h1
-	ssidList = append(ssidList, round.key.H2j...)                // h2
-	ssidList = append(ssidList, big.NewInt(int64(round.number))) // round number
+	ssidList = append(ssidList, BigXjList...)                                           // BigXj
+	ssidList = append(ssidList, round.key.NTildej...)                                   // NTilde
+	ssidList = append(ssidList, round.key.H1j...)                                       // h1
+	ssidList = append(ssidList, round.key.H2j...)                                       // h2
+	ssidList = append(ssidList, new(big.Int).SetBytes(common.SHA512_256(round.SSID()))) // application session id
+	ssidList = append(ssidList, big.NewInt(int64(round.number)))                        // round number
 	ssidList = append(ssidList, round.temp.ssidNonce)
 	ssid := common.SHA512_256i(ssidList...).Bytes()
 
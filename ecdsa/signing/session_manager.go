@@ -0,0 +1,313 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"context"
+	"crypto/elliptic"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/ecdsa/keygen"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+// ErrNotSigner is returned by SessionManager.Sign when the party is not in the quorum of the current attempt
+var ErrNotSigner = errors.New("the party is not a signer of the current attempt")
+
+type (
+	// SessionCallbacks connect a SessionManager to the operators of the other parties of the key
+	SessionCallbacks struct {
+		// Send sends a message of an attempt to the operators of its recipients, which pass it to Deliver
+		Send func(attempt int, msg tss.Message)
+		// OnStall is called when a round of the attempt timed out waiting for the `stalled` parties. It should tell the
+		// operators of all of the other parties of the key, which pass the notice to Abort.
+		OnStall func(attempt, round int, stalled []*tss.PartyID)
+		// OnRestart, if set, is called with the quorum of every attempt after the first one. An operator that waits
+		// with its party out of the quorum calls Sign again once the quorum contains it.
+		OnRestart func(attempt int, quorum *tss.Quorum)
+	}
+
+	// SessionManager signs a message with the party `partyID` of a key and recovers from signers that stop responding
+	// in the middle of the session: when a round times out, the manager aborts the attempt, selects a new quorum
+	// without the parties that the round waited for, and starts the next attempt with it. Every operator of the key
+	// runs its own manager, and they reach the same quorums as long as they learn of the same stalls, which holds for
+	// a peer that stops altogether. The attempt number is bound into the session id of each attempt.
+	SessionManager struct {
+		ec        elliptic.Curve
+		key       keygen.LocalPartySaveData
+		parties   tss.SortedPartyIDs
+		partyID   *tss.PartyID
+		threshold int
+		ssid      []byte
+		timeout   time.Duration
+		online    tss.SortedPartyIDs
+		callbacks SessionCallbacks
+
+		mtx      sync.Mutex
+		attempt  int
+		quorum   *tss.Quorum
+		excluded map[string]struct{}
+		party    *LocalParty
+		aborted  chan struct{}
+		pending  map[int][]pendingMessage
+		errs     chan *tss.Error
+	}
+
+	pendingMessage struct {
+		wireBytes   []byte
+		from        *tss.PartyID
+		isBroadcast bool
+	}
+)
+
+// NewSessionManager returns a manager of the signing session `ssid` of the party `partyID` of the key `parties`,
+// whose save data is `key`. Its first attempt is signed by the first Threshold+1 parties that are `online`, and the
+// rounds of every attempt time out after `roundTimeout`.
+func NewSessionManager(
+	ec elliptic.Curve,
+	key keygen.LocalPartySaveData,
+	parties tss.SortedPartyIDs,
+	partyID *tss.PartyID,
+	threshold int,
+	online tss.SortedPartyIDs,
+	ssid []byte,
+	roundTimeout time.Duration,
+	callbacks SessionCallbacks,
+) (*SessionManager, error) {
+	if roundTimeout <= 0 {
+		return nil, fmt.Errorf("invalid round timeout %v", roundTimeout)
+	}
+	if callbacks.Send == nil || callbacks.OnStall == nil {
+		return nil, errors.New("the Send and OnStall callbacks are required")
+	}
+	if parties.FindByKey(partyID.KeyInt()) == nil {
+		return nil, fmt.Errorf("party %s is not a party of the key", partyID)
+	}
+	m := &SessionManager{
+		ec:        ec,
+		key:       key,
+		parties:   parties,
+		partyID:   partyID,
+		threshold: threshold,
+		ssid:      ssid,
+		timeout:   roundTimeout,
+		online:    online,
+		callbacks: callbacks,
+		excluded:  make(map[string]struct{}),
+		aborted:   make(chan struct{}),
+		pending:   make(map[int][]pendingMessage),
+		errs:      make(chan *tss.Error, 1),
+	}
+	quorum, err := m.selectQuorum()
+	if err != nil {
+		return nil, err
+	}
+	m.quorum = quorum
+	return m, nil
+}
+
+// Quorum returns the current attempt and its quorum
+func (m *SessionManager) Quorum() (int, *tss.Quorum) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	return m.attempt, m.quorum
+}
+
+// Sign runs the attempts of the session until one of them produces the signature of `msg`. It returns ErrNotSigner
+// when the quorum of the current attempt does not contain the party, and an error when an attempt fails for another
+// reason than a stall or when too few parties are left for a quorum.
+func (m *SessionManager) Sign(ctx context.Context, msg *big.Int) (*common.SignatureData, error) {
+	done := make(chan struct{})
+	defer close(done)
+	for {
+		end := make(chan *common.SignatureData, 1)
+		attempt, aborted, err := m.startAttempt(msg, end, done)
+		if err != nil {
+			return nil, err
+		}
+		select {
+		case <-ctx.Done():
+			m.abandon(attempt)
+			return nil, fmt.Errorf("signing attempt %d: %w", attempt, ctx.Err())
+		case tssErr := <-m.errs:
+			m.abandon(attempt)
+			return nil, fmt.Errorf("signing attempt %d: %w", attempt, tssErr)
+		case data := <-end:
+			return data, nil
+		case <-aborted:
+		}
+	}
+}
+
+// Deliver passes a message of an attempt from the party `from` of the key to the party. The messages of later
+// attempts wait until the session gets there, as a peer may restart first; those of earlier attempts are dropped.
+func (m *SessionManager) Deliver(attempt int, wireBytes []byte, from *tss.PartyID, isBroadcast bool) error {
+	m.mtx.Lock()
+	switch {
+	case attempt > m.attempt || attempt == m.attempt && m.party == nil:
+		m.pending[attempt] = append(m.pending[attempt], pendingMessage{wireBytes, from, isBroadcast})
+		m.mtx.Unlock()
+		return nil
+	case attempt < m.attempt:
+		m.mtx.Unlock()
+		return nil
+	}
+	party, quorum := m.party, m.quorum
+	m.mtx.Unlock()
+
+	sender := quorum.Signers.FindByKey(from.KeyInt())
+	if sender == nil {
+		return fmt.Errorf("party %s is not a signer of attempt %d", from, attempt)
+	}
+	if _, err := party.UpdateFromBytes(wireBytes, sender, isBroadcast); err != nil {
+		select {
+		case m.errs <- err:
+		default:
+		}
+		return err
+	}
+	return nil
+}
+
+// Abort aborts the attempt because its rounds stalled waiting for the `stalled` parties, which the next attempts
+// exclude. The notice of an attempt that has already been aborted is ignored.
+func (m *SessionManager) Abort(attempt int, stalled []*tss.PartyID) error {
+	m.mtx.Lock()
+	if attempt != m.attempt {
+		m.mtx.Unlock()
+		return nil
+	}
+	for _, pID := range stalled {
+		m.excluded[string(pID.Key)] = struct{}{}
+	}
+	quorum, err := m.selectQuorum()
+	if err != nil {
+		m.mtx.Unlock()
+		return fmt.Errorf("cannot restart attempt %d: %w", attempt, err)
+	}
+	if m.party != nil {
+		tss.BaseAbort(m.party)
+		m.party = nil
+	}
+	delete(m.pending, m.attempt)
+	m.attempt++
+	m.quorum = quorum
+	close(m.aborted)
+	m.aborted = make(chan struct{})
+	next := m.attempt
+	m.mtx.Unlock()
+
+	common.DefaultLogger().Warnf("signing session %x: attempt %d stalled waiting for %v, restarting with %v",
+		m.ssid, attempt, stalled, quorum.Signers)
+	if m.callbacks.OnRestart != nil {
+		m.callbacks.OnRestart(next, quorum)
+	}
+	return nil
+}
+
+// startAttempt starts the party of the current attempt and returns the attempt and the channel that is closed when it
+// is aborted. The messages of the party are sent until then or until `done` is closed.
+func (m *SessionManager) startAttempt(msg *big.Int, end chan *common.SignatureData, done <-chan struct{}) (int, <-chan struct{}, error) {
+	m.mtx.Lock()
+	attempt, quorum, aborted := m.attempt, m.quorum, m.aborted
+	params, err := quorum.Parameters(m.partyID)
+	if err != nil {
+		m.mtx.Unlock()
+		return attempt, nil, fmt.Errorf("attempt %d: %w", attempt, ErrNotSigner)
+	}
+	params.SetSSID(m.attemptSSID(attempt))
+	params.SetRoundTimeout(m.timeout, func(_ *tss.PartyID, round int, waitingFor []*tss.PartyID) {
+		m.stalled(attempt, round, waitingFor)
+	})
+	out := make(chan tss.Message, len(quorum.Signers))
+	party := NewLocalParty(msg, params, m.key, out, end).(*LocalParty)
+	m.party = party
+	pending := m.pending[attempt]
+	delete(m.pending, attempt)
+	m.mtx.Unlock()
+
+	go func() {
+		for {
+			select {
+			case msg := <-out:
+				m.callbacks.Send(attempt, msg)
+			case <-aborted:
+				return
+			case <-done:
+				// the party has sent the messages of its last round before it ended
+				for {
+					select {
+					case msg := <-out:
+						m.callbacks.Send(attempt, msg)
+					default:
+						return
+					}
+				}
+			}
+		}
+	}()
+	if err := party.Start(); err != nil {
+		m.abandon(attempt)
+		return attempt, nil, fmt.Errorf("signing attempt %d: %w", attempt, err)
+	}
+	for _, p := range pending {
+		if err := m.Deliver(attempt, p.wireBytes, p.from, p.isBroadcast); err != nil {
+			common.DefaultLogger().Warnf("signing session %x: dropping a message of attempt %d: %v", m.ssid, attempt, err)
+		}
+	}
+	return attempt, aborted, nil
+}
+
+// stalled reports the timeout of a round of the attempt and aborts it. The parties of the round are mapped back to
+// the parties of the key, as the other operators know them.
+func (m *SessionManager) stalled(attempt, round int, waitingFor []*tss.PartyID) {
+	stalled := make([]*tss.PartyID, 0, len(waitingFor))
+	for _, pID := range waitingFor {
+		if keyPID := m.parties.FindByKey(pID.KeyInt()); keyPID != nil {
+			stalled = append(stalled, keyPID)
+		}
+	}
+	m.callbacks.OnStall(attempt, round, stalled)
+	if err := m.Abort(attempt, stalled); err != nil {
+		select {
+		case m.errs <- tss.NewError(err, TaskName, round, m.partyID, stalled...):
+		default:
+		}
+	}
+}
+
+// abandon aborts the party of the attempt after it failed or was given up on
+func (m *SessionManager) abandon(attempt int) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if attempt == m.attempt && m.party != nil {
+		tss.BaseAbort(m.party)
+		m.party = nil
+	}
+}
+
+// selectQuorum selects the quorum of the parties that are online and have not been excluded; it is called with the
+// manager locked
+func (m *SessionManager) selectQuorum() (*tss.Quorum, error) {
+	online := make(tss.SortedPartyIDs, 0, len(m.online))
+	for _, pID := range m.online {
+		if _, ok := m.excluded[string(pID.Key)]; !ok {
+			online = append(online, pID)
+		}
+	}
+	return tss.SelectQuorum(m.ec, m.parties, m.threshold, online)
+}
+
+func (m *SessionManager) attemptSSID(attempt int) []byte {
+	return binary.BigEndian.AppendUint32(append([]byte{}, m.ssid...), uint32(attempt))
+}
@@ -0,0 +1,103 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/ecdsa/keygen"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+func TestSessionManagerRecovers(t *testing.T) {
+	setUp("error")
+
+	keys, pIDs, err := keygen.LoadKeygenTestFixtures(testParticipants)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	// party 1 is in the first quorum but its operator never signs; party 2 waits out of the quorum
+	managers := make(map[int]*SessionManager)
+	results := make(chan *common.SignatureData, len(pIDs))
+	errCh := make(chan error, len(pIDs))
+	sign := func(m *SessionManager) {
+		data, err := m.Sign(context.Background(), big.NewInt(42))
+		if err != nil {
+			errCh <- err
+			return
+		}
+		results <- data
+	}
+	stalls := make(chan []*tss.PartyID, len(pIDs))
+	for _, i := range []int{0, 2} {
+		from := i
+		m, err := NewSessionManager(tss.S256(), keys[i], pIDs, pIDs[i], testThreshold, pIDs, []byte("session"), 5*time.Second,
+			SessionCallbacks{
+				Send: func(attempt int, msg tss.Message) {
+					raw, routing, err := msg.WireBytes()
+					if !assert.NoError(t, err) {
+						return
+					}
+					for j, peer := range managers {
+						if j == from || routing.To != nil && routing.To[0].KeyInt().Cmp(pIDs[j].KeyInt()) != 0 {
+							continue
+						}
+						go peer.Deliver(attempt, raw, routing.From, routing.IsBroadcast)
+					}
+				},
+				OnStall: func(attempt, round int, stalled []*tss.PartyID) {
+					stalls <- stalled
+					for j, peer := range managers {
+						if j != from {
+							go peer.Abort(attempt, stalled)
+						}
+					}
+				},
+				OnRestart: func(attempt int, quorum *tss.Quorum) {
+					if from == 2 && quorum.Contains(pIDs[2]) {
+						go sign(managers[2])
+					}
+				},
+			})
+		if !assert.NoError(t, err) {
+			return
+		}
+		managers[i] = m
+	}
+	_, quorum := managers[2].Quorum()
+	assert.True(t, quorum.Contains(pIDs[1]))
+	_, err = managers[2].Sign(context.Background(), big.NewInt(42))
+	assert.True(t, errors.Is(err, ErrNotSigner))
+
+	go sign(managers[0])
+	for ended := 0; ended < 2; {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+		case stalled := <-stalls:
+			assert.Equal(t, []*tss.PartyID{pIDs[1]}, stalled)
+		case data := <-results:
+			der, err := data.DERSignature()
+			assert.NoError(t, err)
+			assert.True(t, ecdsa.VerifyASN1(keys[0].ECDSAPub.ToECDSAPubKey(), data.M, der), "DER signature must verify")
+			ended++
+		}
+	}
+	for _, m := range managers {
+		attempt, quorum := m.Quorum()
+		assert.Equal(t, 1, attempt)
+		assert.False(t, quorum.Contains(pIDs[1]))
+	}
+}
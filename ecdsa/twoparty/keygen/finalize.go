@@ -0,0 +1,94 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kisdex/mpc-lib/crypto"
+	cmts "github.com/kisdex/mpc-lib/crypto/commitments"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+func (round *finalization) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started"))
+	}
+	round.number = 4
+	round.started = true
+	round.resetOK()
+	for j := range round.ok {
+		round.ok[j] = true
+	}
+
+	if round.PartyID().Index == P2 {
+		if err := round.verifyRound3Message(); err != nil {
+			return err
+		}
+	}
+
+	round.temp.generatedSK = false
+	round.end <- round.save
+	return nil
+}
+
+// verifyRound3Message checks the opening of Q1 and the Paillier key of P1, and computes the public key Q = x2*Q1
+func (round *finalization) verifyRound3Message() *tss.Error {
+	P1ID := round.other()
+	r3msg := round.temp.kgRound3Messages[P1].Content().(*KGRound3Message)
+	cmtDeCmt := cmts.HashCommitDecommit{C: round.temp.commitment, D: r3msg.UnmarshalDeCommitment()}
+	ok, encoded := cmtDeCmt.DeCommit()
+	if !ok || len(encoded) != 1 {
+		return round.WrapError(errors.New("de-commitment verify failed"), P1ID).WithCode(tss.ErrProofVerifyFailed)
+	}
+	bigX1, err := crypto.NewECPointFromBytes(round.EC(), encoded[0].Bytes())
+	if err != nil || !crypto.ValidatePoints(bigX1) {
+		return round.WrapError(errors.New("failed to unmarshal Q1"), P1ID)
+	}
+	proof1, err := r3msg.UnmarshalZKProof(round.EC())
+	if err != nil {
+		return round.WrapError(errors.New("failed to unmarshal the proof of Q1"), P1ID)
+	}
+	if !proof1.Verify(round.proofSession(P1), bigX1) {
+		return round.WrapError(errors.New("failed to verify the proof of Q1"), P1ID).WithCode(tss.ErrProofVerifyFailed)
+	}
+
+	paillierPK := r3msg.UnmarshalPaillierPK()
+	if !round.SecurityLevel().ValidModulus(paillierPK.N) {
+		return round.WrapError(fmt.Errorf("the Paillier modulus must have at least %d bits", round.SecurityLevel().ModulusBits()), P1ID)
+	}
+	modProof, err := r3msg.UnmarshalModProof()
+	if err != nil || !modProof.Verify(round.temp.ssid, paillierPK.N) {
+		return round.WrapError(errors.New("failed to verify the Paillier-Blum modulus proof"), P1ID).WithCode(tss.ErrProofVerifyFailed)
+	}
+	cKey := r3msg.UnmarshalCKey()
+	pdlProof, err := r3msg.UnmarshalPDLProof(round.EC())
+	if err != nil || !pdlProof.Verify(round.temp.ssid, paillierPK, cKey, bigX1) {
+		return round.WrapError(errors.New("failed to verify that the encrypted share matches Q1"), P1ID).WithCode(tss.ErrProofVerifyFailed)
+	}
+
+	round.save.BigXj[P1] = bigX1
+	round.save.ECDSAPub = bigX1.ScalarMult(round.save.Xi)
+	round.save.PaillierPK = paillierPK
+	round.save.CKey = cKey
+	return nil
+}
+
+func (round *finalization) CanAccept(msg tss.ParsedMessage) bool {
+	// not expecting any incoming messages in this round
+	return false
+}
+
+func (round *finalization) Update() (bool, *tss.Error) {
+	// not expecting any incoming messages in this round
+	return false, nil
+}
+
+func (round *finalization) NextRound() tss.Round {
+	return nil // finished!
+}
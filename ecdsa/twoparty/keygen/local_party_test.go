@@ -0,0 +1,85 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto"
+	ecdsakeygen "github.com/kisdex/mpc-lib/ecdsa/keygen"
+	"github.com/kisdex/mpc-lib/test"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+func setUp(level string) {
+	if err := common.SetLogLevel(level); err != nil {
+		panic(err)
+	}
+}
+
+func TestE2E(t *testing.T) {
+	setUp("info")
+
+	// re-use the Paillier key of a keygen fixture for speed
+	fixtures, _, err := ecdsakeygen.LoadKeygenTestFixtures(1)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	pIDs := tss.GenerateTestPartyIDs(2)
+	p2pCtx := tss.NewPeerContext(pIDs)
+	outCh := make(chan tss.Message, len(pIDs))
+	endCh := make(chan *LocalPartySaveData, len(pIDs))
+	parties := make([]tss.Party, 0, len(pIDs))
+	for i := range pIDs {
+		params := tss.NewParameters(tss.S256(), p2pCtx, pIDs[i], len(pIDs), 1)
+		parties = append(parties, NewLocalParty(params, outCh, endCh, fixtures[0].PaillierSK))
+	}
+	_, tssErr := test.RunSequentially(parties, outCh)
+	if !assert.Nil(t, tssErr) {
+		return
+	}
+
+	saves := make([]*LocalPartySaveData, 2)
+	for range pIDs {
+		save := <-endCh
+		assert.NoError(t, save.Validate())
+		index, err := save.OriginalIndex()
+		assert.NoError(t, err)
+		saves[index] = save
+	}
+	save1, save2 := saves[P1], saves[P2]
+
+	// Q = x1*x2*G
+	x := common.ModInt(tss.S256().Params().N).Mul(save1.Xi, save2.Xi)
+	assert.True(t, crypto.ScalarBaseMult(tss.S256(), x).Equals(save1.ECDSAPub))
+	assert.True(t, save1.ECDSAPub.Equals(save2.ECDSAPub))
+	for j, save := range saves {
+		assert.True(t, crypto.ScalarBaseMult(tss.S256(), save.Xi).Equals(save1.BigXj[j]), "ensure BigX_j == g^x_j")
+		assert.True(t, save1.BigXj[j].Equals(save2.BigXj[j]))
+	}
+
+	// only P1 has the Paillier secret key, and P2 has the encryption of x1 under it
+	assert.Nil(t, save2.PaillierSK)
+	assert.Equal(t, 0, save1.PaillierPK.N.Cmp(save2.PaillierPK.N))
+	x1, err := save1.PaillierSK.Decrypt(save2.CKey)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, x1.Cmp(save1.Xi))
+}
+
+func TestTooManyParties(t *testing.T) {
+	setUp("info")
+
+	pIDs := tss.GenerateTestPartyIDs(3)
+	params := tss.NewParameters(tss.S256(), tss.NewPeerContext(pIDs), pIDs[0], len(pIDs), 1)
+	P := NewLocalParty(params, make(chan tss.Message, 1), make(chan *LocalPartySaveData, 1))
+	err := P.Start()
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "requires 2 parties")
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"crypto/elliptic"
+	"math/big"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto"
+	cmt "github.com/kisdex/mpc-lib/crypto/commitments"
+	"github.com/kisdex/mpc-lib/crypto/modproof"
+	"github.com/kisdex/mpc-lib/crypto/paillier"
+	"github.com/kisdex/mpc-lib/crypto/pdlproof"
+	"github.com/kisdex/mpc-lib/crypto/schnorr"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+// These messages were generated from Protocol Buffers definitions into ecdsa-twoparty-keygen.pb.go
+// The following messages are registered on the Protocol Buffers "wire"
+
+var (
+	// Ensure that keygen messages implement ValidateBasic, and accept only them from the wire
+	messageTypes = tss.NewMessageTypes(
+		&KGRound1Message{},
+		&KGRound2Message{},
+		&KGRound3Message{},
+	)
+)
+
+// ----- //
+
+func NewKGRound1Message(
+	to, from *tss.PartyID,
+	ct cmt.HashCommitment,
+) tss.ParsedMessage {
+	meta := tss.MessageRouting{
+		From:        from,
+		To:          []*tss.PartyID{to},
+		IsBroadcast: false,
+	}
+	content := &KGRound1Message{
+		Commitment: ct.Bytes(),
+	}
+	msg := tss.NewMessageWrapper(meta, content)
+	return tss.NewMessage(meta, content, msg)
+}
+
+func (m *KGRound1Message) ValidateBasic() bool {
+	return m != nil &&
+		common.NonEmptyBytes(m.GetCommitment())
+}
+
+func (m *KGRound1Message) UnmarshalCommitment() *big.Int {
+	return new(big.Int).SetBytes(m.GetCommitment())
+}
+
+// ----- //
+
+func NewKGRound2Message(
+	to, from *tss.PartyID,
+	publicShare *crypto.ECPoint,
+	proof *schnorr.ZKProof,
+) tss.ParsedMessage {
+	meta := tss.MessageRouting{
+		From:        from,
+		To:          []*tss.PartyID{to},
+		IsBroadcast: false,
+	}
+	content := &KGRound2Message{
+		PublicShare: publicShare.Bytes(),
+		ProofAlpha:  proof.Alpha.Bytes(),
+		ProofT:      proof.T.Bytes(),
+	}
+	msg := tss.NewMessageWrapper(meta, content)
+	return tss.NewMessage(meta, content, msg)
+}
+
+func (m *KGRound2Message) ValidateBasic() bool {
+	return m != nil &&
+		common.NonEmptyBytes(m.GetPublicShare()) &&
+		common.NonEmptyBytes(m.GetProofAlpha()) &&
+		common.NonEmptyBytes(m.GetProofT())
+}
+
+func (m *KGRound2Message) UnmarshalPublicShare(ec elliptic.Curve) (*crypto.ECPoint, error) {
+	return crypto.NewECPointFromBytes(ec, m.GetPublicShare())
+}
+
+func (m *KGRound2Message) UnmarshalZKProof(ec elliptic.Curve) (*schnorr.ZKProof, error) {
+	return unmarshalZKProof(ec, m.GetProofAlpha(), m.GetProofT())
+}
+
+// ----- //
+
+func NewKGRound3Message(
+	to, from *tss.PartyID,
+	deCommitment cmt.HashDeCommitment,
+	proof *schnorr.ZKProof,
+	paillierPK *paillier.PublicKey,
+	cKey *big.Int,
+	modProof *modproof.ProofMod,
+	pdlProof *pdlproof.Proof,
+) tss.ParsedMessage {
+	meta := tss.MessageRouting{
+		From:        from,
+		To:          []*tss.PartyID{to},
+		IsBroadcast: false,
+	}
+	modProofBzs := modProof.Bytes()
+	pdlProofBzs := pdlProof.Bytes()
+	content := &KGRound3Message{
+		DeCommitment: common.BigIntsToBytes(deCommitment),
+		ProofAlpha:   proof.Alpha.Bytes(),
+		ProofT:       proof.T.Bytes(),
+		PaillierN:    paillierPK.N.Bytes(),
+		CKey:         cKey.Bytes(),
+		ModProof:     modProofBzs[:],
+		PdlProof:     pdlProofBzs[:],
+	}
+	msg := tss.NewMessageWrapper(meta, content)
+	return tss.NewMessage(meta, content, msg)
+}
+
+func (m *KGRound3Message) ValidateBasic() bool {
+	return m != nil &&
+		common.NonEmptyMultiBytes(m.GetDeCommitment(), 2) &&
+		common.NonEmptyBytes(m.GetProofAlpha()) &&
+		common.NonEmptyBytes(m.GetProofT()) &&
+		common.NonEmptyBytes(m.GetPaillierN()) &&
+		common.NonEmptyBytes(m.GetCKey()) &&
+		common.NonEmptyMultiBytes(m.GetModProof(), modproof.ProofModBytesParts) &&
+		common.NonEmptyMultiBytes(m.GetPdlProof(), pdlproof.ProofBytesParts)
+}
+
+func (m *KGRound3Message) UnmarshalDeCommitment() []*big.Int {
+	return cmt.NewHashDeCommitmentFromBytes(m.GetDeCommitment())
+}
+
+func (m *KGRound3Message) UnmarshalZKProof(ec elliptic.Curve) (*schnorr.ZKProof, error) {
+	return unmarshalZKProof(ec, m.GetProofAlpha(), m.GetProofT())
+}
+
+func (m *KGRound3Message) UnmarshalPaillierPK() *paillier.PublicKey {
+	return paillier.NewPublicKey(new(big.Int).SetBytes(m.GetPaillierN()))
+}
+
+func (m *KGRound3Message) UnmarshalCKey() *big.Int {
+	return new(big.Int).SetBytes(m.GetCKey())
+}
+
+func (m *KGRound3Message) UnmarshalModProof() (*modproof.ProofMod, error) {
+	return modproof.NewProofFromBytes(m.GetModProof())
+}
+
+func (m *KGRound3Message) UnmarshalPDLProof(ec elliptic.Curve) (*pdlproof.Proof, error) {
+	return pdlproof.NewProofFromBytes(ec, m.GetPdlProof())
+}
+
+// ----- //
+
+func unmarshalZKProof(ec elliptic.Curve, alpha, t []byte) (*schnorr.ZKProof, error) {
+	point, err := crypto.NewECPointFromBytes(ec, alpha)
+	if err != nil {
+		return nil, err
+	}
+	return &schnorr.ZKProof{
+		Alpha: point,
+		T:     new(big.Int).SetBytes(t),
+	}, nil
+}
@@ -0,0 +1,111 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto"
+	cmts "github.com/kisdex/mpc-lib/crypto/commitments"
+	"github.com/kisdex/mpc-lib/crypto/paillier"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+// round 1 represents round 1 of the two-party keygen of Lindell (2017), Protocol 3.1
+func newRound1(params *tss.Parameters, save *LocalPartySaveData, temp *localTempData, out chan<- tss.Message, end chan<- *LocalPartySaveData) tss.Round {
+	return &round1{
+		&base{params, save, temp, out, end, make([]bool, len(params.Parties().IDs())), false, 1}}
+}
+
+func (round *round1) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started"))
+	}
+	round.number = 1
+	round.started = true
+	round.resetOK()
+
+	Pi := round.PartyID()
+	i := Pi.Index
+
+	if round.PartyCount() != 2 || round.Threshold() != 1 {
+		return round.WrapError(errors.New("two-party keygen requires 2 parties with a threshold of 1"))
+	}
+
+	round.temp.ssidNonce = new(big.Int).SetUint64(0)
+	ssid, err := round.getSSID()
+	if err != nil {
+		return round.WrapError(errors.New("failed to generate ssid"))
+	}
+	round.temp.ssid = ssid
+
+	// 1. sample the share xi, which is multiplied with the share of the other party
+	ids := round.Parties().IDs().Keys()
+	xi := common.GetRandomPositiveRelativelyPrimeInt(round.EC().Params().N)
+	round.temp.bigX = crypto.ScalarBaseMult(round.EC(), xi)
+	round.save.Xi = xi
+	round.save.ShareID = ids[i]
+	round.save.Ks = ids
+	round.save.BigXj[i] = round.temp.bigX
+
+	// P2 waits for the commitment of P1
+	if !round.startSending(P1) {
+		return nil
+	}
+
+	// 2. generate the Paillier key unless it was provided to the LocalParty constructor
+	if round.temp.paillierSK == nil {
+		paillierSK, _, err := paillier.GenerateKeyPair(context.Background(), round.SecurityLevel().ModulusBits())
+		if err != nil {
+			return round.WrapError(errors.New("paillier key generation failed"), Pi)
+		}
+		round.temp.paillierSK = paillierSK
+		round.temp.generatedSK = true
+	}
+	if !round.SecurityLevel().ValidModulus(round.temp.paillierSK.N) {
+		return round.WrapError(fmt.Errorf("the Paillier key does not have the %d-bit modulus of security level %d",
+			round.SecurityLevel().ModulusBits(), round.SecurityLevel()), Pi)
+	}
+
+	// 3. commit to Q1 = x1*G
+	cmt := cmts.NewHashCommitment(new(big.Int).SetBytes(round.temp.bigX.Bytes()))
+	round.temp.deCommitment = cmt.D
+
+	r1msg := NewKGRound1Message(round.other(), Pi, cmt.C)
+	round.temp.kgRound1Messages[i] = r1msg
+	round.SendMessage(round.out, r1msg)
+	return nil
+}
+
+func (round *round1) CanAccept(msg tss.ParsedMessage) bool {
+	if _, ok := msg.Content().(*KGRound1Message); ok {
+		return !msg.IsBroadcast()
+	}
+	return false
+}
+
+func (round *round1) Update() (bool, *tss.Error) {
+	for j, msg := range round.temp.kgRound1Messages {
+		if round.ok[j] {
+			continue
+		}
+		if msg == nil || !round.CanAccept(msg) {
+			return false, nil
+		}
+		round.ok[j] = true
+	}
+	return true, nil
+}
+
+func (round *round1) NextRound() tss.Round {
+	round.started = false
+	return &round2{round}
+}
@@ -0,0 +1,70 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"errors"
+
+	"github.com/kisdex/mpc-lib/crypto/schnorr"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+func (round *round2) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started"))
+	}
+	round.number = 2
+	round.started = true
+	round.resetOK()
+
+	Pi := round.PartyID()
+	i := Pi.Index
+
+	// P1 waits for Q2
+	if !round.startSending(P2) {
+		return nil
+	}
+
+	// 1. keep the commitment of P1, which is opened once Q2 is sent
+	r1msg := round.temp.kgRound1Messages[P1].Content().(*KGRound1Message)
+	round.temp.commitment = r1msg.UnmarshalCommitment()
+
+	// 2. send Q2 = x2*G with a proof of knowledge of x2
+	proof, err := schnorr.NewZKProof(round.proofSession(i), round.save.Xi, round.temp.bigX)
+	if err != nil {
+		return round.WrapError(err, Pi)
+	}
+	r2msg := NewKGRound2Message(round.other(), Pi, round.temp.bigX, proof)
+	round.temp.kgRound2Messages[i] = r2msg
+	round.SendMessage(round.out, r2msg)
+	return nil
+}
+
+func (round *round2) CanAccept(msg tss.ParsedMessage) bool {
+	if _, ok := msg.Content().(*KGRound2Message); ok {
+		return !msg.IsBroadcast()
+	}
+	return false
+}
+
+func (round *round2) Update() (bool, *tss.Error) {
+	for j, msg := range round.temp.kgRound2Messages {
+		if round.ok[j] {
+			continue
+		}
+		if msg == nil || !round.CanAccept(msg) {
+			return false, nil
+		}
+		round.ok[j] = true
+	}
+	return true, nil
+}
+
+func (round *round2) NextRound() tss.Round {
+	round.started = false
+	return &round3{round}
+}
@@ -0,0 +1,103 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"errors"
+
+	"github.com/kisdex/mpc-lib/crypto"
+	"github.com/kisdex/mpc-lib/crypto/modproof"
+	"github.com/kisdex/mpc-lib/crypto/pdlproof"
+	"github.com/kisdex/mpc-lib/crypto/schnorr"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+func (round *round3) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started"))
+	}
+	round.number = 3
+	round.started = true
+	round.resetOK()
+
+	Pi := round.PartyID()
+	i := Pi.Index
+
+	// P2 waits for the opening of the commitment and the Paillier key of P1
+	if !round.startSending(P1) {
+		return nil
+	}
+
+	// 1. verify Q2 and compute the public key Q = x1*Q2
+	P2ID := round.other()
+	r2msg := round.temp.kgRound2Messages[P2].Content().(*KGRound2Message)
+	bigX2, err := r2msg.UnmarshalPublicShare(round.EC())
+	if err != nil || !crypto.ValidatePoints(bigX2) {
+		return round.WrapError(errors.New("failed to unmarshal Q2"), P2ID)
+	}
+	proof2, err := r2msg.UnmarshalZKProof(round.EC())
+	if err != nil {
+		return round.WrapError(errors.New("failed to unmarshal the proof of Q2"), P2ID)
+	}
+	if !proof2.Verify(round.proofSession(P2), bigX2) {
+		return round.WrapError(errors.New("failed to verify the proof of Q2"), P2ID).WithCode(tss.ErrProofVerifyFailed)
+	}
+	round.save.BigXj[P2] = bigX2
+	round.save.ECDSAPub = bigX2.ScalarMult(round.save.Xi)
+
+	// 2. encrypt x1 under the Paillier key and prove that the ciphertext encrypts the discrete log of Q1
+	paillierSK := round.temp.paillierSK
+	cKey, rho, err := paillierSK.EncryptAndReturnRandomness(round.save.Xi)
+	if err != nil {
+		return round.WrapError(err, Pi)
+	}
+	modProof, err := modproof.NewProof(round.temp.ssid, paillierSK.N, paillierSK.P, paillierSK.Q)
+	if err != nil {
+		return round.WrapError(err, Pi)
+	}
+	pdlProof, err := pdlproof.NewProof(round.temp.ssid, &paillierSK.PublicKey, cKey, round.temp.bigX, round.save.Xi, rho)
+	if err != nil {
+		return round.WrapError(err, Pi)
+	}
+	proof1, err := schnorr.NewZKProof(round.proofSession(i), round.save.Xi, round.temp.bigX)
+	if err != nil {
+		return round.WrapError(err, Pi)
+	}
+	round.save.PaillierSK = paillierSK
+	round.save.PaillierPK = &paillierSK.PublicKey
+
+	// 3. open the commitment to Q1
+	r3msg := NewKGRound3Message(P2ID, Pi, round.temp.deCommitment, proof1, &paillierSK.PublicKey, cKey, modProof, pdlProof)
+	round.temp.kgRound3Messages[i] = r3msg
+	round.SendMessage(round.out, r3msg)
+	return nil
+}
+
+func (round *round3) CanAccept(msg tss.ParsedMessage) bool {
+	if _, ok := msg.Content().(*KGRound3Message); ok {
+		return !msg.IsBroadcast()
+	}
+	return false
+}
+
+func (round *round3) Update() (bool, *tss.Error) {
+	for j, msg := range round.temp.kgRound3Messages {
+		if round.ok[j] {
+			continue
+		}
+		if msg == nil || !round.CanAccept(msg) {
+			return false, nil
+		}
+		round.ok[j] = true
+	}
+	return true, nil
+}
+
+func (round *round3) NextRound() tss.Round {
+	round.started = false
+	return &finalization{round}
+}
@@ -0,0 +1,84 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/kisdex/mpc-lib/crypto"
+	"github.com/kisdex/mpc-lib/crypto/paillier"
+)
+
+// P1 is the index of the party that holds the Paillier key, the first of the sorted party IDs; P2 is the other one
+const (
+	P1 = 0
+	P2 = 1
+)
+
+type (
+	LocalSecrets struct {
+		// secret fields (not shared, but stored locally)
+		Xi, ShareID *big.Int // xi, kj
+	}
+
+	// Everything in LocalPartySaveData is saved locally to user's HD when done.
+	// The key is multiplicatively shared: ECDSAPub = x1*x2*G.
+	LocalPartySaveData struct {
+		LocalSecrets
+
+		// the Paillier key of P1; only P1 has the secret key
+		PaillierSK *paillier.PrivateKey
+		PaillierPK *paillier.PublicKey
+
+		// Enc(x1) under PaillierPK; only P2 has it
+		CKey *big.Int
+
+		// original indexes, P1 first
+		Ks []*big.Int
+
+		// public keys (Xj = xj*G for each Pj)
+		BigXj []*crypto.ECPoint
+
+		ECDSAPub *crypto.ECPoint // y
+	}
+)
+
+func NewLocalPartySaveData() (saveData LocalPartySaveData) {
+	saveData.Ks = make([]*big.Int, 2)
+	saveData.BigXj = make([]*crypto.ECPoint, 2)
+	return
+}
+
+// OriginalIndex recovers a party's original index in the set of parties during keygen: P1 or P2
+func (save LocalPartySaveData) OriginalIndex() (int, error) {
+	for j, kj := range save.Ks {
+		if kj != nil && save.ShareID != nil && kj.Cmp(save.ShareID) == 0 {
+			return j, nil
+		}
+	}
+	return -1, errors.New("a party index could not be recovered from Ks")
+}
+
+// Validate checks that the save data holds the secrets of its role: the Paillier secret key for P1 and the
+// encrypted share of P1 for P2
+func (save LocalPartySaveData) Validate() error {
+	if save.Xi == nil || save.ECDSAPub == nil || len(save.Ks) != 2 || len(save.BigXj) != 2 || save.PaillierPK == nil {
+		return errors.New("the two-party save data is incomplete")
+	}
+	i, err := save.OriginalIndex()
+	if err != nil {
+		return err
+	}
+	if i == P1 && save.PaillierSK == nil {
+		return errors.New("the save data of P1 is missing its Paillier secret key")
+	}
+	if i == P2 && save.CKey == nil {
+		return errors.New("the save data of P2 is missing the encrypted share of P1")
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/ecdsa/twoparty/keygen"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+func (round *finalization) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started"))
+	}
+	round.number = 6
+	round.started = true
+	round.resetOK()
+	for j := range round.ok {
+		round.ok[j] = true
+	}
+
+	if round.PartyID().Index == keygen.P2 {
+		r5msg := round.temp.signRound5Messages[keygen.P1].Content().(*SignRound5Message)
+		s := r5msg.UnmarshalS()
+		if !round.verifySignature(s) {
+			return round.WrapError(errors.New("signature verification failed"), round.other())
+		}
+		round.temp.s = s
+	}
+
+	s, recid := round.temp.s, 0
+	rx, ry := round.temp.bigR.X(), round.temp.bigR.Y()
+	// byte v = if(R.X > curve.N) then 2 else 0) | (if R.Y.IsEven then 0 else 1);
+	if rx.Cmp(round.EC().Params().N) > 0 {
+		recid = 2
+	}
+	if ry.Bit(0) != 0 {
+		recid |= 1
+	}
+	if !round.Params().NoLowS() {
+		s, recid = common.NormalizeLowS(round.EC().Params().N, s, recid)
+	}
+
+	// save the signature for final output
+	bitSizeInBytes := (round.EC().Params().BitSize + 7) / 8
+	round.data.R = padToLengthBytesInPlace(round.r().Bytes(), bitSizeInBytes)
+	round.data.S = padToLengthBytesInPlace(s.Bytes(), bitSizeInBytes)
+	round.data.Signature = append(round.data.R, round.data.S...)
+	round.data.SignatureRecovery = []byte{byte(recid)}
+	round.data.M = round.temp.m.Bytes()
+
+	round.end <- round.data
+	return nil
+}
+
+// r returns the x-coordinate of R mod q
+func (round *base) r() *big.Int {
+	return new(big.Int).Mod(round.temp.bigR.X(), round.EC().Params().N)
+}
+
+// verifySignature checks (r, s) against the public key
+func (round *base) verifySignature(s *big.Int) bool {
+	pk := ecdsa.PublicKey{
+		Curve: round.EC(),
+		X:     round.key.ECDSAPub.X(),
+		Y:     round.key.ECDSAPub.Y(),
+	}
+	return ecdsa.Verify(&pk, common.ECDSADigest(round.EC().Params().N, round.temp.m), round.r(), s)
+}
+
+func (round *finalization) CanAccept(msg tss.ParsedMessage) bool {
+	// not expecting any incoming messages in this round
+	return false
+}
+
+func (round *finalization) Update() (bool, *tss.Error) {
+	// not expecting any incoming messages in this round
+	return false, nil
+}
+
+func (round *finalization) NextRound() tss.Round {
+	return nil // finished!
+}
+
+func padToLengthBytesInPlace(src []byte, length int) []byte {
+	oriLen := len(src)
+	if oriLen < length {
+		for i := 0; i < length-oriLen; i++ {
+			src = append([]byte{0}, src...)
+		}
+	}
+	return src
+}
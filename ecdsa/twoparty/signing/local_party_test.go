@@ -0,0 +1,102 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/common"
+	ecdsakeygen "github.com/kisdex/mpc-lib/ecdsa/keygen"
+	"github.com/kisdex/mpc-lib/ecdsa/twoparty/keygen"
+	"github.com/kisdex/mpc-lib/test"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+func setUp(level string) {
+	if err := common.SetLogLevel(level); err != nil {
+		panic(err)
+	}
+}
+
+func runKeygen(t *testing.T, pIDs tss.SortedPartyIDs) []*keygen.LocalPartySaveData {
+	// re-use the Paillier key of a keygen fixture for speed
+	fixtures, _, err := ecdsakeygen.LoadKeygenTestFixtures(1)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	p2pCtx := tss.NewPeerContext(pIDs)
+	outCh := make(chan tss.Message, len(pIDs))
+	endCh := make(chan *keygen.LocalPartySaveData, len(pIDs))
+	parties := make([]tss.Party, 0, len(pIDs))
+	for i := range pIDs {
+		params := tss.NewParameters(tss.S256(), p2pCtx, pIDs[i], len(pIDs), 1)
+		parties = append(parties, keygen.NewLocalParty(params, outCh, endCh, fixtures[0].PaillierSK))
+	}
+	_, tssErr := test.RunSequentially(parties, outCh)
+	assert.Nil(t, tssErr)
+
+	saves := make([]*keygen.LocalPartySaveData, len(pIDs))
+	for range pIDs {
+		save := <-endCh
+		index, err := save.OriginalIndex()
+		assert.NoError(t, err)
+		saves[index] = save
+	}
+	return saves
+}
+
+func TestE2E(t *testing.T) {
+	setUp("info")
+
+	pIDs := tss.GenerateTestPartyIDs(2)
+	saves := runKeygen(t, pIDs)
+
+	msg := common.GetRandomPositiveInt(tss.S256().Params().N)
+	p2pCtx := tss.NewPeerContext(pIDs)
+	outCh := make(chan tss.Message, len(pIDs))
+	endCh := make(chan *common.SignatureData, len(pIDs))
+	parties := make([]tss.Party, 0, len(pIDs))
+	for i := range pIDs {
+		params := tss.NewParameters(tss.S256(), p2pCtx, pIDs[i], len(pIDs), 1)
+		parties = append(parties, NewLocalParty(msg, params, *saves[i], outCh, endCh))
+	}
+	_, tssErr := test.RunSequentially(parties, outCh)
+	if !assert.Nil(t, tssErr) {
+		return
+	}
+
+	sig1, sig2 := <-endCh, <-endCh
+	assert.Equal(t, sig1.Signature, sig2.Signature)
+	assert.Equal(t, sig1.SignatureRecovery, sig2.SignatureRecovery)
+
+	pk := ecdsa.PublicKey{
+		Curve: tss.S256(),
+		X:     saves[keygen.P1].ECDSAPub.X(),
+		Y:     saves[keygen.P1].ECDSAPub.Y(),
+	}
+	r, s := new(big.Int).SetBytes(sig1.R), new(big.Int).SetBytes(sig1.S)
+	assert.True(t, ecdsa.Verify(&pk, msg.Bytes(), r, s), "ecdsa verify must pass")
+	assert.True(t, s.Cmp(new(big.Int).Rsh(tss.S256().Params().N, 1)) <= 0, "s must be low")
+}
+
+func TestWrongParties(t *testing.T) {
+	setUp("info")
+
+	pIDs := tss.GenerateTestPartyIDs(2)
+	saves := runKeygen(t, pIDs)
+
+	// the save data of P2 used by P1
+	params := tss.NewParameters(tss.S256(), tss.NewPeerContext(pIDs), pIDs[keygen.P1], len(pIDs), 1)
+	P := NewLocalParty(big.NewInt(42), params, *saves[keygen.P2], make(chan tss.Message, 1), make(chan *common.SignatureData, 1))
+	err := P.Start()
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "parties must be those of the keygen")
+	}
+}
@@ -0,0 +1,198 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"crypto/elliptic"
+	"math/big"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto"
+	cmt "github.com/kisdex/mpc-lib/crypto/commitments"
+	"github.com/kisdex/mpc-lib/crypto/schnorr"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+// These messages were generated from Protocol Buffers definitions into ecdsa-twoparty-signing.pb.go
+// The following messages are registered on the Protocol Buffers "wire"
+
+var (
+	// Ensure that signing messages implement ValidateBasic, and accept only them from the wire
+	messageTypes = tss.NewMessageTypes(
+		&SignRound1Message{},
+		&SignRound2Message{},
+		&SignRound3Message{},
+		&SignRound4Message{},
+		&SignRound5Message{},
+	)
+)
+
+// ----- //
+
+func NewSignRound1Message(
+	to, from *tss.PartyID,
+	commitment cmt.HashCommitment,
+) tss.ParsedMessage {
+	meta := tss.MessageRouting{
+		From:        from,
+		To:          []*tss.PartyID{to},
+		IsBroadcast: false,
+	}
+	content := &SignRound1Message{
+		Commitment: commitment.Bytes(),
+	}
+	msg := tss.NewMessageWrapper(meta, content)
+	return tss.NewMessage(meta, content, msg)
+}
+
+func (m *SignRound1Message) ValidateBasic() bool {
+	return m != nil &&
+		common.NonEmptyBytes(m.GetCommitment())
+}
+
+func (m *SignRound1Message) UnmarshalCommitment() *big.Int {
+	return new(big.Int).SetBytes(m.GetCommitment())
+}
+
+// ----- //
+
+func NewSignRound2Message(
+	to, from *tss.PartyID,
+	noncePoint *crypto.ECPoint,
+	proof *schnorr.ZKProof,
+) tss.ParsedMessage {
+	meta := tss.MessageRouting{
+		From:        from,
+		To:          []*tss.PartyID{to},
+		IsBroadcast: false,
+	}
+	content := &SignRound2Message{
+		NoncePoint: noncePoint.Bytes(),
+		ProofAlpha: proof.Alpha.Bytes(),
+		ProofT:     proof.T.Bytes(),
+	}
+	msg := tss.NewMessageWrapper(meta, content)
+	return tss.NewMessage(meta, content, msg)
+}
+
+func (m *SignRound2Message) ValidateBasic() bool {
+	return m != nil &&
+		common.NonEmptyBytes(m.GetNoncePoint()) &&
+		common.NonEmptyBytes(m.GetProofAlpha()) &&
+		common.NonEmptyBytes(m.GetProofT())
+}
+
+func (m *SignRound2Message) UnmarshalNoncePoint(ec elliptic.Curve) (*crypto.ECPoint, error) {
+	return crypto.NewECPointFromBytes(ec, m.GetNoncePoint())
+}
+
+func (m *SignRound2Message) UnmarshalZKProof(ec elliptic.Curve) (*schnorr.ZKProof, error) {
+	return unmarshalZKProof(ec, m.GetProofAlpha(), m.GetProofT())
+}
+
+// ----- //
+
+func NewSignRound3Message(
+	to, from *tss.PartyID,
+	deCommitment cmt.HashDeCommitment,
+	proof *schnorr.ZKProof,
+) tss.ParsedMessage {
+	meta := tss.MessageRouting{
+		From:        from,
+		To:          []*tss.PartyID{to},
+		IsBroadcast: false,
+	}
+	content := &SignRound3Message{
+		DeCommitment: common.BigIntsToBytes(deCommitment),
+		ProofAlpha:   proof.Alpha.Bytes(),
+		ProofT:       proof.T.Bytes(),
+	}
+	msg := tss.NewMessageWrapper(meta, content)
+	return tss.NewMessage(meta, content, msg)
+}
+
+func (m *SignRound3Message) ValidateBasic() bool {
+	return m != nil &&
+		common.NonEmptyMultiBytes(m.GetDeCommitment(), 2) &&
+		common.NonEmptyBytes(m.GetProofAlpha()) &&
+		common.NonEmptyBytes(m.GetProofT())
+}
+
+func (m *SignRound3Message) UnmarshalDeCommitment() []*big.Int {
+	return cmt.NewHashDeCommitmentFromBytes(m.GetDeCommitment())
+}
+
+func (m *SignRound3Message) UnmarshalZKProof(ec elliptic.Curve) (*schnorr.ZKProof, error) {
+	return unmarshalZKProof(ec, m.GetProofAlpha(), m.GetProofT())
+}
+
+// ----- //
+
+func NewSignRound4Message(
+	to, from *tss.PartyID,
+	c3 *big.Int,
+) tss.ParsedMessage {
+	meta := tss.MessageRouting{
+		From:        from,
+		To:          []*tss.PartyID{to},
+		IsBroadcast: false,
+	}
+	content := &SignRound4Message{
+		C3: c3.Bytes(),
+	}
+	msg := tss.NewMessageWrapper(meta, content)
+	return tss.NewMessage(meta, content, msg)
+}
+
+func (m *SignRound4Message) ValidateBasic() bool {
+	return m != nil &&
+		common.NonEmptyBytes(m.GetC3())
+}
+
+func (m *SignRound4Message) UnmarshalC3() *big.Int {
+	return new(big.Int).SetBytes(m.GetC3())
+}
+
+// ----- //
+
+func NewSignRound5Message(
+	to, from *tss.PartyID,
+	s *big.Int,
+) tss.ParsedMessage {
+	meta := tss.MessageRouting{
+		From:        from,
+		To:          []*tss.PartyID{to},
+		IsBroadcast: false,
+	}
+	content := &SignRound5Message{
+		S: s.Bytes(),
+	}
+	msg := tss.NewMessageWrapper(meta, content)
+	return tss.NewMessage(meta, content, msg)
+}
+
+func (m *SignRound5Message) ValidateBasic() bool {
+	return m != nil &&
+		common.NonEmptyBytes(m.GetS())
+}
+
+func (m *SignRound5Message) UnmarshalS() *big.Int {
+	return new(big.Int).SetBytes(m.GetS())
+}
+
+// ----- //
+
+func unmarshalZKProof(ec elliptic.Curve, alpha, t []byte) (*schnorr.ZKProof, error) {
+	point, err := crypto.NewECPointFromBytes(ec, alpha)
+	if err != nil {
+		return nil, err
+	}
+	return &schnorr.ZKProof{
+		Alpha: point,
+		T:     new(big.Int).SetBytes(t),
+	}, nil
+}
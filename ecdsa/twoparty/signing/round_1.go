@@ -0,0 +1,96 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto"
+	cmts "github.com/kisdex/mpc-lib/crypto/commitments"
+	"github.com/kisdex/mpc-lib/ecdsa/twoparty/keygen"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+// round 1 represents round 1 of the two-party signing of Lindell (2017), Protocol 4.1
+func newRound1(params *tss.Parameters, key *keygen.LocalPartySaveData, data *common.SignatureData, temp *localTempData, out chan<- tss.Message, end chan<- *common.SignatureData) tss.Round {
+	return &round1{
+		&base{params, key, data, temp, out, end, make([]bool, len(params.Parties().IDs())), false, 1}}
+}
+
+func (round *round1) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started"))
+	}
+	round.number = 1
+	round.started = true
+	round.resetOK()
+
+	Pi := round.PartyID()
+	i := Pi.Index
+
+	if round.PartyCount() != 2 {
+		return round.WrapError(errors.New("two-party signing requires 2 parties"))
+	}
+	if err := round.key.Validate(); err != nil {
+		return round.WrapError(err, Pi)
+	}
+	if index, _ := round.key.OriginalIndex(); index != i {
+		return round.WrapError(errors.New("the parties must be those of the keygen"), Pi)
+	}
+
+	round.temp.ssidNonce = new(big.Int).SetUint64(0)
+	ssid, err := round.getSSID()
+	if err != nil {
+		return round.WrapError(errors.New("failed to generate ssid"))
+	}
+	round.temp.ssid = ssid
+
+	// 1. sample the nonce share ki; the nonce is k = k1*k2
+	round.temp.ki = common.GetRandomPositiveRelativelyPrimeInt(round.EC().Params().N)
+	round.temp.pointRi = crypto.ScalarBaseMult(round.EC(), round.temp.ki)
+
+	// P2 waits for the commitment of P1
+	if !round.startSending(keygen.P1) {
+		return nil
+	}
+
+	// 2. commit to R1 = k1*G
+	cmt := cmts.NewHashCommitment(new(big.Int).SetBytes(round.temp.pointRi.Bytes()))
+	round.temp.deCommit = cmt.D
+
+	r1msg := NewSignRound1Message(round.other(), Pi, cmt.C)
+	round.temp.signRound1Messages[i] = r1msg
+	round.SendMessage(round.out, r1msg)
+	return nil
+}
+
+func (round *round1) CanAccept(msg tss.ParsedMessage) bool {
+	if _, ok := msg.Content().(*SignRound1Message); ok {
+		return !msg.IsBroadcast()
+	}
+	return false
+}
+
+func (round *round1) Update() (bool, *tss.Error) {
+	for j, msg := range round.temp.signRound1Messages {
+		if round.ok[j] {
+			continue
+		}
+		if msg == nil || !round.CanAccept(msg) {
+			return false, nil
+		}
+		round.ok[j] = true
+	}
+	return true, nil
+}
+
+func (round *round1) NextRound() tss.Round {
+	round.started = false
+	return &round2{round}
+}
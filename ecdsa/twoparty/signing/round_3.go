@@ -0,0 +1,84 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"errors"
+
+	"github.com/kisdex/mpc-lib/crypto"
+	"github.com/kisdex/mpc-lib/crypto/schnorr"
+	"github.com/kisdex/mpc-lib/ecdsa/twoparty/keygen"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+func (round *round3) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started"))
+	}
+	round.number = 3
+	round.started = true
+	round.resetOK()
+
+	Pi := round.PartyID()
+	i := Pi.Index
+
+	// P2 waits for the opening of the commitment to R1
+	if !round.startSending(keygen.P1) {
+		return nil
+	}
+
+	// 1. verify R2 and compute R = k1*R2
+	P2ID := round.other()
+	r2msg := round.temp.signRound2Messages[keygen.P2].Content().(*SignRound2Message)
+	pointR2, err := r2msg.UnmarshalNoncePoint(round.EC())
+	if err != nil || !crypto.ValidatePoints(pointR2) {
+		return round.WrapError(errors.New("failed to unmarshal R2"), P2ID)
+	}
+	proof2, err := r2msg.UnmarshalZKProof(round.EC())
+	if err != nil {
+		return round.WrapError(errors.New("failed to unmarshal the proof of R2"), P2ID)
+	}
+	if !proof2.Verify(round.proofSession(keygen.P2), pointR2) {
+		return round.WrapError(errors.New("failed to verify the proof of R2"), P2ID).WithCode(tss.ErrProofVerifyFailed)
+	}
+	round.temp.bigR = pointR2.ScalarMult(round.temp.ki)
+
+	// 2. open the commitment to R1 with a proof of knowledge of k1
+	proof1, err := schnorr.NewZKProof(round.proofSession(i), round.temp.ki, round.temp.pointRi)
+	if err != nil {
+		return round.WrapError(err, Pi)
+	}
+	r3msg := NewSignRound3Message(P2ID, Pi, round.temp.deCommit, proof1)
+	round.temp.signRound3Messages[i] = r3msg
+	round.SendMessage(round.out, r3msg)
+	return nil
+}
+
+func (round *round3) CanAccept(msg tss.ParsedMessage) bool {
+	if _, ok := msg.Content().(*SignRound3Message); ok {
+		return !msg.IsBroadcast()
+	}
+	return false
+}
+
+func (round *round3) Update() (bool, *tss.Error) {
+	for j, msg := range round.temp.signRound3Messages {
+		if round.ok[j] {
+			continue
+		}
+		if msg == nil || !round.CanAccept(msg) {
+			return false, nil
+		}
+		round.ok[j] = true
+	}
+	return true, nil
+}
+
+func (round *round3) NextRound() tss.Round {
+	round.started = false
+	return &round4{round}
+}
@@ -0,0 +1,112 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto"
+	cmts "github.com/kisdex/mpc-lib/crypto/commitments"
+	"github.com/kisdex/mpc-lib/ecdsa/twoparty/keygen"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+func (round *round4) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started"))
+	}
+	round.number = 4
+	round.started = true
+	round.resetOK()
+
+	Pi := round.PartyID()
+	i := Pi.Index
+
+	// P1 waits for the encrypted partial signature of P2
+	if !round.startSending(keygen.P2) {
+		return nil
+	}
+
+	// 1. open the commitment to R1 and compute R = k2*R1
+	P1ID := round.other()
+	r3msg := round.temp.signRound3Messages[keygen.P1].Content().(*SignRound3Message)
+	cmtDeCmt := cmts.HashCommitDecommit{C: round.temp.commitment, D: r3msg.UnmarshalDeCommitment()}
+	ok, encoded := cmtDeCmt.DeCommit()
+	if !ok || len(encoded) != 1 {
+		return round.WrapError(errors.New("de-commitment verify failed"), P1ID).WithCode(tss.ErrProofVerifyFailed)
+	}
+	pointR1, err := crypto.NewECPointFromBytes(round.EC(), encoded[0].Bytes())
+	if err != nil || !crypto.ValidatePoints(pointR1) {
+		return round.WrapError(errors.New("failed to unmarshal R1"), P1ID)
+	}
+	proof1, err := r3msg.UnmarshalZKProof(round.EC())
+	if err != nil {
+		return round.WrapError(errors.New("failed to unmarshal the proof of R1"), P1ID)
+	}
+	if !proof1.Verify(round.proofSession(keygen.P1), pointR1) {
+		return round.WrapError(errors.New("failed to verify the proof of R1"), P1ID).WithCode(tss.ErrProofVerifyFailed)
+	}
+	round.temp.bigR = pointR1.ScalarMult(round.temp.ki)
+
+	// 2. compute c3 = Enc(rho*q + k2^-1*m) + (k2^-1*r*x2)*Enc(x1), which P1 decrypts to k2^-1*(m + r*x) mod q;
+	// rho in [0, q^2) masks the reduction mod q of the plaintext
+	q := round.EC().Params().N
+	modQ := common.ModInt(q)
+	r := round.r()
+	if r.Sign() == 0 {
+		return round.WrapError(errors.New("the nonce point R has an x-coordinate of 0 mod q"), Pi)
+	}
+	k2Inv := modQ.ModInverse(round.temp.ki)
+	rho := common.GetRandomPositiveInt(new(big.Int).Mul(q, q))
+	pt := new(big.Int).Add(new(big.Int).Mul(rho, q), modQ.Mul(k2Inv, round.temp.m))
+	paillierPK := round.key.PaillierPK
+	c1, err := paillierPK.Encrypt(pt)
+	if err != nil {
+		return round.WrapError(err, Pi)
+	}
+	v := modQ.Mul(modQ.Mul(k2Inv, r), round.key.Xi)
+	c2, err := paillierPK.HomoMult(v, round.key.CKey)
+	if err != nil {
+		return round.WrapError(err, Pi)
+	}
+	c3, err := paillierPK.HomoAdd(c1, c2)
+	if err != nil {
+		return round.WrapError(err, Pi)
+	}
+
+	r4msg := NewSignRound4Message(P1ID, Pi, c3)
+	round.temp.signRound4Messages[i] = r4msg
+	round.SendMessage(round.out, r4msg)
+	return nil
+}
+
+func (round *round4) CanAccept(msg tss.ParsedMessage) bool {
+	if _, ok := msg.Content().(*SignRound4Message); ok {
+		return !msg.IsBroadcast()
+	}
+	return false
+}
+
+func (round *round4) Update() (bool, *tss.Error) {
+	for j, msg := range round.temp.signRound4Messages {
+		if round.ok[j] {
+			continue
+		}
+		if msg == nil || !round.CanAccept(msg) {
+			return false, nil
+		}
+		round.ok[j] = true
+	}
+	return true, nil
+}
+
+func (round *round4) NextRound() tss.Round {
+	round.started = false
+	return &round5{round}
+}
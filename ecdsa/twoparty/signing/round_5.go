@@ -0,0 +1,82 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"errors"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/ecdsa/twoparty/keygen"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+func (round *round5) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started"))
+	}
+	round.number = 5
+	round.started = true
+	round.resetOK()
+
+	Pi := round.PartyID()
+	i := Pi.Index
+
+	// P2 waits for the signature
+	if !round.startSending(keygen.P1) {
+		return nil
+	}
+
+	// 1. decrypt c3 and compute s = k1^-1*k2^-1*(m + r*x) mod q
+	P2ID := round.other()
+	r4msg := round.temp.signRound4Messages[keygen.P2].Content().(*SignRound4Message)
+	c3 := r4msg.UnmarshalC3()
+	if !round.key.PaillierPK.ValidateCiphertexts(c3) {
+		return round.WrapError(errors.New("the encrypted partial signature is not a valid ciphertext"), P2ID)
+	}
+	sPrime, err := round.key.PaillierSK.Decrypt(c3)
+	if err != nil {
+		return round.WrapError(err, P2ID)
+	}
+	modQ := common.ModInt(round.EC().Params().N)
+	s := modQ.Mul(modQ.ModInverse(round.temp.ki), sPrime)
+
+	// 2. P2 can bias s with a bad c3, so the signature is checked before it is released
+	if !round.verifySignature(s) {
+		return round.WrapError(errors.New("signature verification failed"), P2ID)
+	}
+	round.temp.s = s
+
+	r5msg := NewSignRound5Message(P2ID, Pi, s)
+	round.temp.signRound5Messages[i] = r5msg
+	round.SendMessage(round.out, r5msg)
+	return nil
+}
+
+func (round *round5) CanAccept(msg tss.ParsedMessage) bool {
+	if _, ok := msg.Content().(*SignRound5Message); ok {
+		return !msg.IsBroadcast()
+	}
+	return false
+}
+
+func (round *round5) Update() (bool, *tss.Error) {
+	for j, msg := range round.temp.signRound5Messages {
+		if round.ok[j] {
+			continue
+		}
+		if msg == nil || !round.CanAccept(msg) {
+			return false, nil
+		}
+		round.ok[j] = true
+	}
+	return true, nil
+}
+
+func (round *round5) NextRound() tss.Round {
+	round.started = false
+	return &finalization{round}
+}
@@ -0,0 +1,67 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"github.com/kisdex/mpc-lib/crypto"
+	"github.com/kisdex/mpc-lib/crypto/vss"
+	"github.com/kisdex/mpc-lib/tss"
+	"math/big"
+)
+
+// ImportKey splits the secret scalar `sk` of an existing key on `ec` into the save data of `pIDs` with the given
+// threshold, in the order of `pIDs`; for an RFC 8032 private key, take the scalar from Ed25519Scalar.
+//
+// The caller acts as a trusted dealer: it learns every share, so it must run in an isolated environment and erase `sk`
+// and the save data once they are handed to the parties, which should then reshare the key among themselves.
+func ImportKey(ec elliptic.Curve, sk *big.Int, pIDs tss.SortedPartyIDs, threshold int) ([]LocalPartySaveData, error) {
+	if sk == nil || sk.Sign() <= 0 || ec.Params().N.Cmp(sk) <= 0 {
+		return nil, errors.New("the private key must be in [1, N-1] of the curve")
+	}
+	if len(pIDs) < 2 || threshold < 1 || len(pIDs) <= threshold {
+		return nil, fmt.Errorf("invalid party count %d and threshold %d", len(pIDs), threshold)
+	}
+	ks := pIDs.Keys()
+	_, shares, err := vss.Create(ec, threshold, sk, ks)
+	if err != nil {
+		return nil, err
+	}
+	pub := crypto.ScalarBaseMult(ec, sk)
+
+	keys := make([]LocalPartySaveData, len(pIDs))
+	for i := range keys {
+		key := NewLocalPartySaveData(len(pIDs))
+		key.Xi, key.ShareID = shares[i].Share, ks[i]
+		key.EDDSAPub = pub
+		for j, share := range shares {
+			key.Ks[j] = ks[j]
+			key.BigXj[j] = crypto.ScalarBaseMult(ec, share.Share)
+		}
+		keys[i] = key
+	}
+	return keys, nil
+}
+
+// Ed25519Scalar returns the secret scalar of an RFC 8032 private key modulo the order of the curve: the clamped first
+// half of the SHA-512 of its seed, whose multiple of the base point is the public key
+func Ed25519Scalar(priv ed25519.PrivateKey) *big.Int {
+	h := sha512.Sum512(priv.Seed())
+	h[0] &= 248
+	h[31] &= 127
+	h[31] |= 64
+	// the scalar is little-endian
+	be := make([]byte, 32)
+	for i := range be {
+		be[i] = h[31-i]
+	}
+	return new(big.Int).Mod(new(big.Int).SetBytes(be), tss.Edwards().Params().N)
+}
@@ -0,0 +1,44 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"crypto/ed25519"
+	"github.com/kisdex/mpc-lib/crypto/vss"
+	"github.com/kisdex/mpc-lib/tss"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportKey(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	sk := Ed25519Scalar(priv)
+	pIDs := tss.GenerateTestPartyIDs(testParticipants)
+	keys, err := ImportKey(tss.Edwards(), sk, pIDs, testThreshold)
+	if !assert.NoError(t, err) {
+		return
+	}
+	shares := make(vss.Shares, 0, testThreshold+1)
+	for i, key := range keys {
+		bz, err := key.PubKeyBytes()
+		assert.NoError(t, err)
+		assert.Equal(t, []byte(pub), bz, "the public key of party %d should be the imported one", i)
+		if i <= testThreshold {
+			shares = append(shares, &vss.Share{Threshold: testThreshold, ID: key.ShareID, Share: key.Xi})
+		}
+	}
+	secret, err := shares.ReConstruct(tss.Edwards())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, sk.Cmp(secret), "threshold+1 shares should reconstruct the imported key")
+
+	_, err = ImportKey(tss.Edwards(), sk, pIDs, testParticipants)
+	assert.Error(t, err, "the threshold must be below the party count")
+}
@@ -20,6 +20,7 @@ import (
 // Implements Stringer
 var _ tss.Party = (*LocalParty)(nil)
 var _ fmt.Stringer = (*LocalParty)(nil)
+var _ tss.Zeroizer = (*LocalParty)(nil)
 
 type (
 	LocalParty struct {
@@ -95,7 +96,7 @@ func (p *LocalParty) Update(msg tss.ParsedMessage) (ok bool, err *tss.Error) {
 }
 
 func (p *LocalParty) UpdateFromBytes(wireBytes []byte, from *tss.PartyID, isBroadcast bool) (bool, *tss.Error) {
-	msg, err := tss.ParseWireMessage(wireBytes, from, isBroadcast)
+	msg, err := messageTypes.ParseWireMessage(wireBytes, from, isBroadcast)
 	if err != nil {
 		return false, p.WrapError(err)
 	}
@@ -131,7 +132,7 @@ func (p *LocalParty) StoreMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
 	case *KGRound2Message2:
 		p.temp.kgRound2Message2s[fromPIdx] = msg
 	default: // unrecognised message, just ignore!
-		common.Logger.Warningf("unrecognised message ignored: %v", msg)
+		p.params.Logger().Warnf("unrecognised message ignored: %v", msg)
 		return false, nil
 	}
 	return true, nil
@@ -158,6 +159,21 @@ func (p *LocalParty) PartyID() *tss.PartyID {
 	return p.params.PartyID()
 }
 
+// Zeroize wipes the secret polynomial of the keygen once the share is saved, see tss.Zeroizer
+func (p *LocalParty) Zeroize() {
+	p.temp.Zeroize()
+}
+
+// Zeroize wipes ui, the secret of this party's polynomial, and the shares of it that were sent to the other parties
+func (temp *localTempData) Zeroize() {
+	common.Zeroize(temp.ui)
+	for _, share := range temp.shares {
+		if share != nil {
+			common.Zeroize(share.Share)
+		}
+	}
+}
+
 func (p *LocalParty) String() string {
 	return fmt.Sprintf("id: %s, %s", p.PartyID(), p.BaseParty.String())
 }
@@ -21,12 +21,12 @@ import (
 // The following messages are registered on the Protocol Buffers "wire"
 
 var (
-	// Ensure that keygen messages implement ValidateBasic
-	_ = []tss.MessageContent{
-		(*KGRound1Message)(nil),
-		(*KGRound2Message1)(nil),
-		(*KGRound2Message2)(nil),
-	}
+	// Ensure that keygen messages implement ValidateBasic, and accept only them from the wire
+	messageTypes = tss.NewMessageTypes(
+		&KGRound1Message{},
+		&KGRound2Message1{},
+		&KGRound2Message2{},
+	)
 )
 
 // ----- //
@@ -92,8 +92,7 @@ func NewKGRound2Message2(
 	dcBzs := common.BigIntsToBytes(deCommitment)
 	content := &KGRound2Message2{
 		DeCommitment: dcBzs,
-		ProofAlphaX:  proof.Alpha.X().Bytes(),
-		ProofAlphaY:  proof.Alpha.Y().Bytes(),
+		ProofAlpha:   proof.Alpha.Bytes(),
 		ProofT:       proof.T.Bytes(),
 	}
 	msg := tss.NewMessageWrapper(meta, content)
@@ -111,10 +110,7 @@ func (m *KGRound2Message2) UnmarshalDeCommitment() []*big.Int {
 }
 
 func (m *KGRound2Message2) UnmarshalZKProof(ec elliptic.Curve) (*schnorr.ZKProof, error) {
-	point, err := crypto.NewECPoint(
-		ec,
-		new(big.Int).SetBytes(m.GetProofAlphaX()),
-		new(big.Int).SetBytes(m.GetProofAlphaY()))
+	point, err := crypto.NewECPointFromBytes(ec, m.GetProofAlpha())
 	if err != nil {
 		return nil, err
 	}
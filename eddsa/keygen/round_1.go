@@ -61,7 +61,7 @@ func (round *round1) Start() *tss.Error {
 	_ = ui    // silences a linter warning
 
 	// 3. make commitment -> (C, D)
-	pGFlat, err := crypto.FlattenECPoints(vs)
+	pGFlat, err := crypto.CompressECPoints(vs)
 	if err != nil {
 		return round.WrapError(err, Pi)
 	}
@@ -82,7 +82,7 @@ func (round *round1) Start() *tss.Error {
 	{
 		msg := NewKGRound1Message(round.PartyID(), cmt.C)
 		round.temp.kgRound1Messages[i] = msg
-		round.out <- msg
+		round.SendMessage(round.out, msg)
 	}
 	return nil
 }
@@ -54,11 +54,12 @@ func (round *round3) Start() *tss.Error {
 		pjVs         vss.Vs
 	}
 	chs := make([]chan vssOut, len(Ps))
+	pool := round.NewWorkerPool()
 	for i := range chs {
 		if i == PIdx {
 			continue
 		}
-		chs[i] = make(chan vssOut)
+		chs[i] = make(chan vssOut, 1)
 	}
 	for j := range Ps {
 		if j == PIdx {
@@ -67,7 +68,8 @@ func (round *round3) Start() *tss.Error {
 		ContextJ := common.AppendBigIntToBytesSlice(round.temp.ssid, big.NewInt(int64(j)))
 
 		// 6-9.
-		go func(j int, ch chan<- vssOut) {
+		ch := chs[j]
+		pool.GoFor(Ps[j], func() {
 			// 4-10.
 			KGCj := round.temp.KGCs[j]
 			r2msg2 := round.temp.kgRound2Message2s[j].Content().(*KGRound2Message2)
@@ -79,7 +81,7 @@ func (round *round3) Start() *tss.Error {
 				return
 			}
 
-			PjVs, err := crypto.UnFlattenECPoints(round.Params().EC(), flatPolyGs)
+			PjVs, err := crypto.DecompressECPoints(round.Params().EC(), flatPolyGs)
 			for i, PjV := range PjVs {
 				PjVs[i] = PjV.EightInvEight()
 			}
@@ -93,7 +95,7 @@ func (round *round3) Start() *tss.Error {
 				ch <- vssOut{errors.New("failed to unmarshal schnorr proof"), nil}
 				return
 			}
-			ok = proof.Verify(ContextJ, PjVs[0])
+			ok = round.VerifyProof("schnorr", func() bool { return proof.Verify(ContextJ, PjVs[0]) })
 			if !ok {
 				ch <- vssOut{errors.New("failed to prove schnorr proof"), nil}
 				return
@@ -104,16 +106,18 @@ func (round *round3) Start() *tss.Error {
 				ID:        round.PartyID().KeyInt(),
 				Share:     r2msg1.UnmarshalShare(),
 			}
-			if ok = PjShare.Verify(round.Params().EC(), round.Threshold(), PjVs); !ok {
+			if ok = round.VerifyProof("vss", func() bool {
+				return PjShare.Verify(round.Params().EC(), round.Threshold(), PjVs)
+			}); !ok {
 				ch <- vssOut{errors.New("vss verify failed"), nil}
 				return
 			}
 			// (9) handled above
 			ch <- vssOut{nil, PjVs}
-		}(j, chs[j])
+		})
 	}
 
-	// consume unbuffered channels (end the goroutines)
+	// consume the channels (end the goroutines)
 	vssResults := make([]vssOut, len(Ps))
 	{
 		culprits := make([]*tss.PartyID, 0, len(Ps)) // who caused the error(s)
@@ -193,7 +197,7 @@ func (round *round3) Start() *tss.Error {
 	round.save.EDDSAPub = eddsaPubKey
 
 	// PRINT public key & private share
-	common.Logger.Debugf("%s public key: %x", round.PartyID(), eddsaPubKey)
+	round.Logger().Debugf("%s public key: %x", round.PartyID(), eddsaPubKey)
 
 	round.end <- round.save
 	return nil
@@ -8,6 +8,8 @@ package keygen
 
 import (
 	"encoding/hex"
+	"encoding/json"
+	"github.com/kisdex/mpc-lib/common"
 	"github.com/kisdex/mpc-lib/crypto"
 	"github.com/kisdex/mpc-lib/tss"
 	"math/big"
@@ -21,6 +23,9 @@ type (
 
 	// Everything in LocalPartySaveData is saved locally to user's HD when done
 	LocalPartySaveData struct {
+		// Version is the version of the format of the save data, see SaveDataVersion
+		Version uint32
+
 		LocalSecrets
 
 		// original indexes (ki in signing preparation phase)
@@ -34,12 +39,64 @@ type (
 	}
 )
 
+// SaveDataVersion is the version of the format of the LocalPartySaveData of this release. A release that adds or
+// renames a field bumps it, and registers the migration of the save data of the previous version in
+// SaveDataMigrations.
+const SaveDataVersion = 1
+
+// SaveDataMigrations upgrades the JSON encoding of LocalPartySaveData of the older versions, see UnmarshalSaveData
+var SaveDataMigrations = common.NewMigrations("Version", SaveDataVersion)
+
+func init() {
+	// version 1 only adds the version to the save data from before it
+	SaveDataMigrations.Register(0, func(map[string]json.RawMessage) error { return nil })
+}
+
+// UnmarshalSaveData decodes the JSON encoding of LocalPartySaveData of this version or of an older one, which it
+// migrates first
+func UnmarshalSaveData(data []byte) (LocalPartySaveData, error) {
+	var saveData LocalPartySaveData
+	migrated, err := SaveDataMigrations.Migrate(data)
+	if err != nil {
+		return saveData, err
+	}
+	err = json.Unmarshal(migrated, &saveData)
+	return saveData, err
+}
+
 func NewLocalPartySaveData(partyCount int) (saveData LocalPartySaveData) {
+	saveData.Version = SaveDataVersion
 	saveData.Ks = make([]*big.Int, partyCount)
 	saveData.BigXj = make([]*crypto.ECPoint, partyCount)
 	return
 }
 
+// Zeroize wipes the share Xi, e.g. once a key share is retired. Copies of the save data hold the same share, so they
+// are wiped too.
+func (local *LocalPartySaveData) Zeroize() {
+	common.Zeroize(local.Xi)
+}
+
+// PubKeyBytes returns the 32-byte RFC 8032 encoding of EDDSAPub, as an ed25519.PublicKey
+func (local LocalPartySaveData) PubKeyBytes() ([]byte, error) {
+	return local.EDDSAPub.MarshalCompressed()
+}
+
+// PubKeyDER returns the DER SubjectPublicKeyInfo of EDDSAPub
+func (local LocalPartySaveData) PubKeyDER() ([]byte, error) {
+	return local.EDDSAPub.MarshalPKIX()
+}
+
+// PubKeyPEM returns the PEM "PUBLIC KEY" block of PubKeyDER
+func (local LocalPartySaveData) PubKeyPEM() ([]byte, error) {
+	return local.EDDSAPub.MarshalPEM()
+}
+
+// PubKeyJWK returns the JSON Web Key of EDDSAPub
+func (local LocalPartySaveData) PubKeyJWK() ([]byte, error) {
+	return local.EDDSAPub.MarshalJWK()
+}
+
 // BuildLocalSaveDataSubset re-creates the LocalPartySaveData to contain data for only the list of signing parties.
 func BuildLocalSaveDataSubset(sourceData LocalPartySaveData, sortedIDs tss.SortedPartyIDs) LocalPartySaveData {
 	keysToIndices := make(map[string]int, len(sourceData.Ks))
@@ -4,22 +4,20 @@ Copyright IBM Corp. All Rights Reserved.
 SPDX-License-Identifier: Apache-2.0
 */
 
-package ecdsa
+package eddsa
 
 import (
 	"bytes"
 	"context"
-	"crypto/elliptic"
+	"crypto/ed25519"
 	"crypto/sha256"
 	"encoding/base64"
-	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"github.com/kisdex/mpc-lib/common"
 	"github.com/kisdex/mpc-lib/eddsa/keygen"
 	"github.com/kisdex/mpc-lib/eddsa/signing"
 	"github.com/kisdex/mpc-lib/tss"
-	"golang.org/x/crypto/sha3"
 	"math"
 	"math/big"
 	"sync"
@@ -71,11 +69,8 @@ func (parties parties) numericIDs() []uint16 {
 	return res
 }
 
-type Logger interface {
-	Debugf(format string, a ...interface{})
-	Warnf(format string, a ...interface{})
-	Errorf(format string, a ...interface{})
-}
+// Logger is the logger of a party; the protocols that it runs log to it too
+type Logger = common.Logger
 
 type party struct {
 	logger    Logger
@@ -86,14 +81,20 @@ type party struct {
 	in        chan tss.Message
 	shareData *keygen.LocalPartySaveData
 	closeChan chan struct{}
+
+	// operations started with KeyGenSession and SignSession, by session id
+	sessionSendMsg SessionSender
+	sessionsLock   sync.Mutex
+	sessions       map[string]*session
 }
 
 func NewParty(id uint16, logger Logger) *party {
 	return &party{
-		logger: logger,
-		id:     tss.NewPartyID(fmt.Sprintf("%d", id), "", big.NewInt(int64(id))),
-		out:    make(chan tss.Message, 1000),
-		in:     make(chan tss.Message, 1000),
+		logger:   logger,
+		id:       tss.NewPartyID(fmt.Sprintf("%d", id), "", big.NewInt(int64(id))),
+		out:      make(chan tss.Message, 1000),
+		in:       make(chan tss.Message, 1000),
+		sessions: make(map[string]*session),
 	}
 }
 
@@ -128,63 +129,54 @@ func (p *party) ClassifyMsg(msgBytes []byte) (uint8, bool, error) {
 }
 
 func (p *party) OnMsg(msgBytes []byte, from uint16, broadcast bool) {
+	msg, ok := p.parseMsg(msgBytes, from, broadcast)
+	if !ok {
+		return
+	}
+	p.in <- msg
+}
+
+func (p *party) parseMsg(msgBytes []byte, from uint16, broadcast bool) (tss.ParsedMessage, bool) {
 	id := tss.NewPartyID(fmt.Sprintf("%d", from), "", big.NewInt(int64(from)))
 	id.Index = p.locatePartyIndex(id)
 	msg, err := tss.ParseWireMessage(msgBytes, id, broadcast)
 	if err != nil {
 		p.logger.Warnf("Received invalid message (%s) of %d bytes from %d: %v", base64.StdEncoding.EncodeToString(msgBytes), len(msgBytes), from, err)
-		return
+		return nil, false
 	}
 
 	key := msg.GetFrom().KeyInt()
 	if key == nil || key.Cmp(big.NewInt(int64(math.MaxUint16))) >= 0 {
 		p.logger.Warnf("Message received from invalid key: %v", key)
-		return
+		return nil, false
 	}
 
 	claimedFrom := uint16(key.Uint64())
 	if claimedFrom != from {
 		p.logger.Warnf("Message claimed to be from %d but was received from %d", claimedFrom, from)
-		return
+		return nil, false
 	}
 
-	p.in <- msg
+	return msg, true
 }
 
-func (p *party) ThresholdPK() ([]byte, error) {
+// TPubKey returns the threshold public key, which verifies the signatures of Sign with ed25519.Verify
+func (p *party) TPubKey() (ed25519.PublicKey, error) {
 	if p.shareData == nil {
 		return nil, fmt.Errorf("must call SetShareData() before attempting to sign")
 	}
 
 	pk := p.shareData.EDDSAPub
+	return edwards.NewPublicKey(pk.X(), pk.Y()).Serialize(), nil
+}
 
-	edPK := &edwards.PublicKey{
-		Curve: tss.Edwards(),
-		X:     pk.X(),
-		Y:     pk.Y(),
-	}
-	// 将公钥转换为压缩格式
-	pubKeyBytes := elliptic.MarshalCompressed(edPK.Curve, edPK.X, edPK.Y)
-
-	// 计算 Keccak-256 哈希
-	hash := sha3.NewLegacyKeccak256()
-	hash.Write(pubKeyBytes[1:]) // 去掉第一个字节（前缀）
-	//addressBytes := hash.Sum(nil)[12:] // 获取最后20字节
-
-	//return hex.EncodeToString(addressBytes), nil
-	pubHash := hash.Sum(nil)
-	// 取哈希结果的后20字节作为地址
-	address := pubHash[len(pubHash)-20:]
-	// 转换为以太坊地址格式 (0x 开头的十六进制字符串)
-	fmt.Println("address:", "0x"+hex.EncodeToString(address))
-
-	pkBytes := copyBytes(edPK.Serialize())
-	return pkBytes[:], nil
+// ThresholdPK returns the 32-byte encoding of the threshold public key
+func (p *party) ThresholdPK() ([]byte, error) {
+	return p.TPubKey()
 }
 
 func (p *party) SetShareData(shareData []byte) error {
-	var localSaveData keygen.LocalPartySaveData
-	err := json.Unmarshal(shareData, &localSaveData)
+	localSaveData, err := keygen.UnmarshalSaveData(shareData)
 	if err != nil {
 		return fmt.Errorf("failed deserializing shares: %w", err)
 	}
@@ -200,6 +192,7 @@ func (p *party) Init(parties []uint16, threshold int, sendMsg func(msg []byte, i
 	partyIDs := partyIDsFromNumbers(parties)
 	ctx := tss.NewPeerContext(partyIDs)
 	p.params = tss.NewParameters(tss.Edwards(), ctx, p.id, len(parties), threshold)
+	p.params.SetLogger(p.logger)
 	p.id.Index = p.locatePartyIndex(p.id)
 	p.sendMsg = sendMsg
 	p.closeChan = make(chan struct{})
@@ -215,7 +208,9 @@ func partyIDsFromNumbers(parties []uint16) []*tss.PartyID {
 	return tss.SortPartyIDs(partyIDs)
 }
 
-func (p *party) Sign(ctx context.Context, msgHash []byte) ([]byte, error) {
+// Sign signs `msg` and returns the 64-byte Ed25519 signature. Ed25519 hashes the message itself, so `msg` is the
+// message and not a digest of it. The protocol takes the message as an integer, so it must not start with a zero byte.
+func (p *party) Sign(ctx context.Context, msg []byte) ([]byte, error) {
 	if p.shareData == nil {
 		return nil, fmt.Errorf("must call SetShareData() before attempting to sign")
 	}
@@ -224,10 +219,17 @@ func (p *party) Sign(ctx context.Context, msgHash []byte) ([]byte, error) {
 
 	defer close(p.closeChan)
 
+	return p.sign(ctx, p.params, msg, p.in, p.out)
+}
+
+func (p *party) sign(ctx context.Context, params *tss.Parameters, msg []byte, in <-chan tss.Message, out chan<- tss.Message) ([]byte, error) {
+	if len(msg) == 0 || msg[0] == 0 {
+		return nil, fmt.Errorf("the message to sign must not be empty nor start with a zero byte")
+	}
 	end := make(chan *common.SignatureData, 1)
 
-	msgToSign := big.NewInt(0).SetBytes(msgHash)
-	party := signing.NewLocalParty(msgToSign, p.params, *p.shareData, p.out, end)
+	msgToSign := new(big.Int).SetBytes(msg)
+	party := signing.NewLocalParty(msgToSign, params, *p.shareData, out, end)
 
 	var endWG sync.WaitGroup
 	endWG.Add(1)
@@ -247,22 +249,13 @@ func (p *party) Sign(ctx context.Context, msgHash []byte) ([]byte, error) {
 		case <-ctx.Done():
 			return nil, fmt.Errorf("signing timed out: %w", ctx.Err())
 		case sigOut := <-end:
-			if !bytes.Equal(sigOut.M, msgToSign.Bytes()) {
+			if !bytes.Equal(sigOut.M, msg) {
 				return nil, fmt.Errorf("message we requested to sign is %s but actual message signed is %s",
-					base64.StdEncoding.EncodeToString(msgHash),
+					base64.StdEncoding.EncodeToString(msg),
 					base64.StdEncoding.EncodeToString(sigOut.M))
 			}
-			var sig struct {
-				R, S *big.Int
-			}
-			sig.R = big.NewInt(0)
-			sig.S = big.NewInt(0)
-			sig.R.SetBytes(sigOut.R)
-			sig.S.SetBytes(sigOut.S)
-
-			s := &edwards.Signature{R: sig.R, S: sig.S}
-			return s.Serialize(), nil
-		case msg := <-p.in:
+			return sigOut.Signature, nil
+		case msg := <-in:
 			raw, routing, err := msg.WireBytes()
 			if err != nil {
 				p.logger.Warnf("Received error when serializing message: %v", err)
@@ -284,8 +277,12 @@ func (p *party) KeyGen(ctx context.Context) ([]byte, error) {
 
 	defer close(p.closeChan)
 
+	return p.keygen(ctx, p.params, p.in, p.out)
+}
+
+func (p *party) keygen(ctx context.Context, params *tss.Parameters, in <-chan tss.Message, out chan<- tss.Message) ([]byte, error) {
 	end := make(chan *keygen.LocalPartySaveData, 1)
-	party := keygen.NewLocalParty(p.params, p.out, end)
+	party := keygen.NewLocalParty(params, out, end)
 
 	var endWG sync.WaitGroup
 	endWG.Add(1)
@@ -310,7 +307,7 @@ func (p *party) KeyGen(ctx context.Context) ([]byte, error) {
 				return nil, fmt.Errorf("failed serializing DKG output: %w", err)
 			}
 			return dkgRawOut, nil
-		case msg := <-p.in:
+		case msg := <-in:
 			raw, routing, err := msg.WireBytes()
 			if err != nil {
 				p.logger.Warnf("Received error when serializing message: %v", err)
@@ -327,21 +324,25 @@ func (p *party) KeyGen(ctx context.Context) ([]byte, error) {
 }
 
 func (p *party) sendMessages() {
+	p.forwardMessages(p.out, p.closeChan, p.sendMsg)
+}
+
+func (p *party) forwardMessages(out <-chan tss.Message, closeChan <-chan struct{}, sendMsg Sender) {
 	for {
 		select {
-		case <-p.closeChan:
+		case <-closeChan:
 			return
-		case msg := <-p.out:
+		case msg := <-out:
 			msgBytes, routing, err := msg.WireBytes()
 			if err != nil {
 				p.logger.Warnf("Failed marshaling message: %v", err)
 				continue
 			}
 			if routing.IsBroadcast {
-				p.sendMsg(msgBytes, routing.IsBroadcast, 0)
+				sendMsg(msgBytes, routing.IsBroadcast, 0)
 			} else {
 				for _, to := range msg.GetTo() {
-					p.sendMsg(msgBytes, routing.IsBroadcast, uint16(big.NewInt(0).SetBytes(to.Key).Uint64()))
+					sendMsg(msgBytes, routing.IsBroadcast, uint16(big.NewInt(0).SetBytes(to.Key).Uint64()))
 				}
 			}
 		}
@@ -353,27 +354,3 @@ func digest(in []byte) []byte {
 	h.Write(in)
 	return h.Sum(nil)
 }
-
-// copyBytes copies a byte slice to a 32 byte array.
-func copyBytes(aB []byte) *[32]byte {
-	if aB == nil {
-		return nil
-	}
-	s := new([32]byte)
-
-	// If we have a short byte string, expand
-	// it so that it's long enough.
-	aBLen := len(aB)
-	if aBLen < 32 {
-		diff := 32 - aBLen
-		for i := 0; i < diff; i++ {
-			aB = append([]byte{0x00}, aB...)
-		}
-	}
-
-	for i := 0; i < 32; i++ {
-		s[i] = aB[i]
-	}
-
-	return s
-}
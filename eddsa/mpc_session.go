@@ -0,0 +1,126 @@
+package eddsa
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+// SessionSender sends a message of the session `sessionID`; the transport must deliver the session id along with
+// the message so that the recipient can pass both to OnSessionMsg.
+type SessionSender func(sessionID string, msg []byte, isBroadcast bool, to uint16)
+
+// session is a keygen or signing operation that runs alongside the other sessions of a party
+type session struct {
+	in        chan tss.Message
+	out       chan tss.Message
+	closeChan chan struct{}
+	running   bool
+}
+
+func newSession() *session {
+	return &session{
+		in:        make(chan tss.Message, 1000),
+		out:       make(chan tss.Message, 1000),
+		closeChan: make(chan struct{}),
+	}
+}
+
+// SetSessionSender sets the sender used by KeyGenSession and SignSession
+func (p *party) SetSessionSender(sendMsg SessionSender) {
+	p.sessionsLock.Lock()
+	defer p.sessionsLock.Unlock()
+	p.sessionSendMsg = sendMsg
+}
+
+// OnSessionMsg routes a message to the session `sessionID`. Messages for a session that has not been started yet
+// are buffered until it is, since other parties may start the same session earlier.
+func (p *party) OnSessionMsg(sessionID string, msgBytes []byte, from uint16, broadcast bool) {
+	msg, ok := p.parseMsg(msgBytes, from, broadcast)
+	if !ok {
+		return
+	}
+	p.sessionsLock.Lock()
+	s, ok := p.sessions[sessionID]
+	if !ok {
+		s = newSession()
+		p.sessions[sessionID] = s
+	}
+	p.sessionsLock.Unlock()
+	s.in <- msg
+}
+
+// KeyGenSession runs a distributed key generation in the session `sessionID`, concurrently with the other sessions
+// of the party. All of the parties must use the same session id, which is bound into the proofs of the protocol.
+func (p *party) KeyGenSession(ctx context.Context, sessionID string) ([]byte, error) {
+	s, params, err := p.startSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer p.endSession(sessionID)
+
+	p.logger.Debugf("Starting DKG in session %s", sessionID)
+	defer p.logger.Debugf("Finished DKG in session %s", sessionID)
+
+	return p.keygen(ctx, params, s.in, s.out)
+}
+
+// SignSession signs `msg` in the session `sessionID`, concurrently with the other sessions of the party.
+// All of the parties must use the same session id, which is bound into the proofs of the protocol.
+func (p *party) SignSession(ctx context.Context, sessionID string, msg []byte) ([]byte, error) {
+	if p.shareData == nil {
+		return nil, fmt.Errorf("must call SetShareData() before attempting to sign")
+	}
+	s, params, err := p.startSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer p.endSession(sessionID)
+
+	p.logger.Debugf("Starting signing in session %s", sessionID)
+	defer p.logger.Debugf("Finished signing in session %s", sessionID)
+
+	return p.sign(ctx, params, msg, s.in, s.out)
+}
+
+// startSession registers the session, adopting the messages that were buffered for it, and starts forwarding its
+// outgoing messages. Each session gets its own copy of the parameters with the session id set.
+func (p *party) startSession(sessionID string) (*session, *tss.Parameters, error) {
+	if p.params == nil {
+		return nil, nil, fmt.Errorf("must call Init() before starting a session")
+	}
+	p.sessionsLock.Lock()
+	defer p.sessionsLock.Unlock()
+	if p.sessionSendMsg == nil {
+		return nil, nil, fmt.Errorf("must call SetSessionSender() before starting a session")
+	}
+	s, ok := p.sessions[sessionID]
+	if !ok {
+		s = newSession()
+		p.sessions[sessionID] = s
+	}
+	if s.running {
+		return nil, nil, fmt.Errorf("session %s is already running", sessionID)
+	}
+	s.running = true
+
+	params := *p.params
+	params.SetSSID([]byte(sessionID))
+
+	sendMsg := p.sessionSendMsg
+	go p.forwardMessages(s.out, s.closeChan, func(msg []byte, isBroadcast bool, to uint16) {
+		sendMsg(sessionID, msg, isBroadcast, to)
+	})
+	return s, &params, nil
+}
+
+// endSession stops forwarding the messages of the session and forgets it, so that the session id may be reused
+func (p *party) endSession(sessionID string) {
+	p.sessionsLock.Lock()
+	defer p.sessionsLock.Unlock()
+	if s, ok := p.sessions[sessionID]; ok {
+		close(s.closeChan)
+		delete(p.sessions, sessionID)
+	}
+}
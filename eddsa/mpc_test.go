@@ -4,7 +4,7 @@ Copyright IBM Corp. All Rights Reserved.
 SPDX-License-Identifier: Apache-2.0
 */
 
-package ecdsa
+package eddsa
 
 import (
 	"context"
@@ -66,6 +66,39 @@ func (parties parties) sign(msg []byte) ([][]byte, error) {
 	return sigs, nil
 }
 
+// signSessions signs every message in its own session, with all of the sessions running at the same time
+func (parties parties) signSessions(msgs [][]byte) ([][][]byte, error) {
+	sigs := make([][][]byte, len(msgs))
+	var threadSafeError atomic.Value
+
+	var wg sync.WaitGroup
+	wg.Add(len(msgs) * len(parties))
+
+	for i, msg := range msgs {
+		sigs[i] = make([][]byte, len(parties))
+		for j, p := range parties {
+			go func(p *party, i, j int, msg []byte) {
+				defer wg.Done()
+				sig, err := p.SignSession(context.Background(), fmt.Sprintf("sign-%d", i), msg)
+				if err != nil {
+					threadSafeError.Store(err.Error())
+					return
+				}
+				sigs[i][j] = sig
+			}(p, i, j, msg)
+		}
+	}
+
+	wg.Wait()
+
+	err := threadSafeError.Load()
+	if err != nil {
+		return nil, fmt.Errorf(err.(string))
+	}
+
+	return sigs, nil
+}
+
 func (parties parties) keygen() ([][]byte, error) {
 	var lock sync.Mutex
 	shares := make([][]byte, len(parties))
@@ -148,6 +181,25 @@ func TestTSS(t *testing.T) {
 	assert.NoError(t, err)
 
 	assert.True(t, ed25519.Verify(pk, digest(msgToSign), sigs[0]))
+
+	t.Logf("Signing in concurrent sessions")
+	for i, p := range parties {
+		p.SetSessionSender(sessionSenders(parties)[i])
+	}
+	// Ed25519 hashes the message itself, so the messages are signed as they are
+	msgs := [][]byte{[]byte("session 1"), []byte("session 2"), []byte("session 3")}
+	t1 = time.Now()
+	sessionSigs, err := parties.signSessions(msgs)
+	assert.NoError(t, err)
+	t.Logf("Signing %d sessions completed in %v", len(msgs), time.Since(t1))
+	for i, msg := range msgs {
+		for _, sig := range sessionSigs[i] {
+			assert.True(t, ed25519.Verify(pk, msg, sig), "signature of session %d must verify", i)
+		}
+	}
+
+	_, err = pA.SignSession(context.Background(), "zero", []byte{0, 1})
+	assert.Error(t, err, "a message that starts with a zero byte cannot be signed")
 }
 
 func senders(parties parties) []Sender {
@@ -177,6 +229,27 @@ func senders(parties parties) []Sender {
 	return senders
 }
 
+func sessionSenders(parties parties) []SessionSender {
+	var senders []SessionSender
+	for _, src := range parties {
+		src := src
+		sender := func(sessionID string, msgBytes []byte, broadcast bool, to uint16) {
+			messageSource := uint16(big.NewInt(0).SetBytes(src.id.Key).Uint64())
+			for _, dst := range parties {
+				if dst.id == src.id {
+					continue
+				}
+				if !broadcast && to != uint16(big.NewInt(0).SetBytes(dst.id.Key).Uint64()) {
+					continue
+				}
+				dst.OnSessionMsg(sessionID, msgBytes, messageSource, broadcast)
+			}
+		}
+		senders = append(senders, sender)
+	}
+	return senders
+}
+
 func logger(id string, testName string) Logger {
 	logConfig := zap.NewDevelopmentConfig()
 	logger, _ := logConfig.Build()
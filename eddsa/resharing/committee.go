@@ -0,0 +1,47 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package resharing
+
+import (
+	"fmt"
+	"github.com/kisdex/mpc-lib/eddsa/keygen"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+// NewLocalParties creates the parties of both committees of `reshare` when they all run in one process, with `keys`
+// the save data of the old committee in the order of its parties. A party of both committees is in both slices.
+func NewLocalParties(
+	reshare *tss.Reshare,
+	keys []keygen.LocalPartySaveData,
+	out chan<- tss.Message,
+	end chan<- *keygen.LocalPartySaveData,
+) (oldCommittee, newCommittee []tss.Party, err error) {
+	if len(keys) != len(reshare.OldParties) {
+		return nil, nil, fmt.Errorf("expected the save data of %d old parties, got %d", len(reshare.OldParties), len(keys))
+	}
+	oldCommittee = make([]tss.Party, 0, len(reshare.OldParties))
+	for j, pID := range reshare.OldParties {
+		params, err := reshare.Parameters(pID)
+		if err != nil {
+			return nil, nil, err
+		}
+		oldCommittee = append(oldCommittee, NewLocalParty(params, keys[j], out, end))
+	}
+	newCommittee = make([]tss.Party, 0, len(reshare.NewParties))
+	for _, pID := range reshare.NewParties {
+		if oldPID := reshare.OldParties.FindByKey(pID.KeyInt()); oldPID != nil {
+			newCommittee = append(newCommittee, oldCommittee[oldPID.Index])
+			continue
+		}
+		params, err := reshare.Parameters(pID)
+		if err != nil {
+			return nil, nil, err
+		}
+		newCommittee = append(newCommittee, NewLocalParty(params, keygen.NewLocalPartySaveData(len(reshare.NewParties)), out, end))
+	}
+	return oldCommittee, newCommittee, nil
+}
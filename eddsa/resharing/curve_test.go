@@ -0,0 +1,103 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package resharing
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto"
+	"github.com/kisdex/mpc-lib/crypto/edwards448"
+	"github.com/kisdex/mpc-lib/eddsa/keygen"
+	"github.com/kisdex/mpc-lib/eddsa/signing"
+	"github.com/kisdex/mpc-lib/test"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+func TestE2EReshareEd448(t *testing.T) {
+	setUp("info")
+	ec := tss.Edwards448()
+
+	// PHASE: keygen
+	oldPIDs := tss.GenerateTestPartyIDs(testParticipants)
+	oldP2PCtx := tss.NewPeerContext(oldPIDs)
+	outCh := make(chan tss.Message, 2*testParticipants*testParticipants)
+	endCh := make(chan *keygen.LocalPartySaveData, 2*testParticipants)
+	kgParties := make([]tss.Party, 0, len(oldPIDs))
+	for _, pID := range oldPIDs {
+		params := tss.NewParameters(ec, oldP2PCtx, pID, len(oldPIDs), testThreshold)
+		kgParties = append(kgParties, keygen.NewLocalParty(params, outCh, endCh))
+	}
+	if _, tssErr := test.RunSequentially(kgParties, outCh); !assert.Nil(t, tssErr, "keygen should succeed") {
+		return
+	}
+	oldKeys := make([]keygen.LocalPartySaveData, len(oldPIDs))
+	for range oldPIDs {
+		save := <-endCh
+		index, err := save.OriginalIndex()
+		if !assert.NoError(t, err) {
+			return
+		}
+		oldKeys[index] = *save
+	}
+
+	// PHASE: resharing from t+1 of the old parties
+	oldPIDs = oldPIDs[:testThreshold+1]
+	oldP2PCtx = tss.NewPeerContext(oldPIDs)
+	newPIDs := tss.GenerateTestPartyIDs(testParticipants)
+	newP2PCtx := tss.NewPeerContext(newPIDs)
+	oldCommittee := make([]tss.Party, 0, len(oldPIDs))
+	for j, pID := range oldPIDs {
+		params := tss.NewReSharingParameters(ec, oldP2PCtx, newP2PCtx, pID, testParticipants, testThreshold, len(newPIDs), testThreshold)
+		oldCommittee = append(oldCommittee, NewLocalParty(params, oldKeys[j], outCh, endCh))
+	}
+	newCommittee := make([]tss.Party, 0, len(newPIDs))
+	for _, pID := range newPIDs {
+		params := tss.NewReSharingParameters(ec, oldP2PCtx, newP2PCtx, pID, testParticipants, testThreshold, len(newPIDs), testThreshold)
+		newCommittee = append(newCommittee, NewLocalParty(params, keygen.NewLocalPartySaveData(len(newPIDs)), outCh, endCh))
+	}
+	if _, tssErr := test.RunResharingSequentially(oldCommittee, newCommittee, outCh); !assert.Nil(t, tssErr, "resharing should succeed") {
+		return
+	}
+	newKeys := make([]keygen.LocalPartySaveData, len(newPIDs))
+	for range append(oldCommittee, newCommittee...) {
+		save := <-endCh
+		if save.Xi == nil {
+			continue
+		}
+		index, err := save.OriginalIndex()
+		if !assert.NoError(t, err) {
+			return
+		}
+		newKeys[index] = *save
+	}
+	for j, key := range newKeys {
+		assert.True(t, key.BigXj[j].Equals(crypto.ScalarBaseMult(ec, key.Xi)), "ensure BigX_j == g^x_j")
+		assert.True(t, key.EDDSAPub.Equals(oldKeys[0].EDDSAPub), "the public key must not change")
+	}
+
+	// PHASE: signing with the new parties
+	signPIDs := newPIDs[:testThreshold+1]
+	signP2pCtx := tss.NewPeerContext(signPIDs)
+	msg := big.NewInt(42)
+	signEndCh := make(chan *common.SignatureData, len(signPIDs))
+	signParties := make([]tss.Party, 0, len(signPIDs))
+	for j, pID := range signPIDs {
+		params := tss.NewParameters(ec, signP2pCtx, pID, len(signPIDs), testThreshold)
+		signParties = append(signParties, signing.NewLocalParty(msg, params, newKeys[j], outCh, signEndCh))
+	}
+	if _, tssErr := test.RunSequentially(signParties, outCh); !assert.Nil(t, tssErr, "signing should succeed") {
+		return
+	}
+	pub := edwards448.EncodePoint(newKeys[0].EDDSAPub.X(), newKeys[0].EDDSAPub.Y())
+	for range signParties {
+		assert.True(t, edwards448.Verify(pub, msg.Bytes(), (<-signEndCh).Signature), "ed448 verify must pass")
+	}
+}
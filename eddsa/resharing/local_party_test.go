@@ -9,6 +9,7 @@ package resharing
 import (
 	"github.com/kisdex/mpc-lib/common"
 	"github.com/kisdex/mpc-lib/crypto"
+	"github.com/kisdex/mpc-lib/crypto/vss"
 	"github.com/kisdex/mpc-lib/test"
 	"github.com/kisdex/mpc-lib/tss"
 	"math/big"
@@ -16,7 +17,6 @@ import (
 	"testing"
 
 	"github.com/decred/dcrd/dcrec/edwards/v2"
-	"github.com/ipfs/go-log"
 	"github.com/kisdex/mpc-lib/eddsa/keygen"
 	"github.com/kisdex/mpc-lib/eddsa/signing"
 	"github.com/stretchr/testify/assert"
@@ -28,7 +28,7 @@ const (
 )
 
 func setUp(level string) {
-	if err := log.SetLogLevel("tss-lib", level); err != nil {
+	if err := common.SetLogLevel(level); err != nil {
 		panic(err)
 	}
 
@@ -43,7 +43,7 @@ func TestE2EConcurrent(t *testing.T) {
 
 	// PHASE: load keygen fixtures
 	firstPartyIdx, extraParties := 1, 1 // // extra can be 0 to N-first
-	oldKeys, oldPIDs, err := keygen.LoadKeygenTestFixtures(testThreshold+1+extraParties+firstPartyIdx, firstPartyIdx)
+	oldKeys, oldPIDs, err := keygen.LoadKeygenTestFixtures(testThreshold+extraParties+firstPartyIdx, firstPartyIdx)
 	assert.NoError(t, err, "should load keygen fixtures")
 
 	// PHASE: resharing
@@ -102,7 +102,7 @@ func TestE2EConcurrent(t *testing.T) {
 	for {
 		select {
 		case err := <-errCh:
-			common.Logger.Errorf("Error: %s", err)
+			common.DefaultLogger().Errorf("Error: %s", err)
 			assert.FailNow(t, err.Error())
 			return
 
@@ -176,7 +176,7 @@ signing:
 	for {
 		select {
 		case err := <-signErrCh:
-			common.Logger.Errorf("Error: %s", err)
+			common.DefaultLogger().Errorf("Error: %s", err)
 			assert.FailNow(t, err.Error())
 			return
 
@@ -226,3 +226,91 @@ signing:
 		}
 	}
 }
+
+func FuzzMessages(f *testing.F) {
+	setUp("error")
+	oldKeys, oldPIDs, err := keygen.LoadKeygenTestFixtures(testThreshold + 1)
+	if err != nil {
+		f.Fatal(err)
+	}
+	newPIDs := tss.GenerateTestPartyIDs(testParticipants)
+	oldP2PCtx, newP2PCtx := tss.NewPeerContext(oldPIDs), tss.NewPeerContext(newPIDs)
+	outCh := make(chan tss.Message, (len(oldPIDs)+len(newPIDs))*len(newPIDs))
+	endCh := make(chan *keygen.LocalPartySaveData, len(oldPIDs)+len(newPIDs))
+	oldCommittee := make([]tss.Party, 0, len(oldPIDs))
+	for j, pID := range oldPIDs {
+		params := tss.NewReSharingParameters(tss.Edwards(), oldP2PCtx, newP2PCtx, pID, testParticipants, testThreshold, len(newPIDs), testThreshold)
+		oldCommittee = append(oldCommittee, NewLocalParty(params, oldKeys[j], outCh, endCh))
+	}
+	newCommittee := make([]tss.Party, 0, len(newPIDs))
+	for _, pID := range newPIDs {
+		params := tss.NewReSharingParameters(tss.Edwards(), oldP2PCtx, newP2PCtx, pID, testParticipants, testThreshold, len(newPIDs), testThreshold)
+		newCommittee = append(newCommittee, NewLocalParty(params, keygen.NewLocalPartySaveData(len(newPIDs)), outCh, endCh))
+	}
+	transcript, tssErr := test.RunResharingSequentially(oldCommittee, newCommittee, outCh)
+	if tssErr != nil {
+		f.Fatal(tssErr)
+	}
+	test.FuzzWireMessages(f, tss.Edwards(), transcript)
+}
+
+func TestCommitteeChanges(t *testing.T) {
+	setUp("error")
+	pIDs := func() tss.SortedPartyIDs {
+		_, pIDs, err := keygen.LoadKeygenTestFixtures(testParticipants)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pIDs
+	}
+	keys := func(qty int) []keygen.LocalPartySaveData {
+		keys, _, _ := keygen.LoadKeygenTestFixtures(qty)
+		return keys
+	}
+	t.Run("AddParty", func(t *testing.T) {
+		reshare, err := tss.AddParty(tss.Edwards(), pIDs(), testThreshold, tss.GenerateTestPartyIDs(1, testParticipants)[0])
+		if assert.NoError(t, err) {
+			testCommitteeChange(t, reshare, keys(testParticipants))
+		}
+	})
+	t.Run("RemoveParty", func(t *testing.T) {
+		parties := pIDs()
+		reshare, err := tss.RemoveParty(tss.Edwards(), parties, testThreshold, parties[1])
+		if assert.NoError(t, err) {
+			testCommitteeChange(t, reshare, keys(testParticipants))
+		}
+	})
+	t.Run("ChangeThreshold", func(t *testing.T) {
+		reshare, err := tss.ChangeThreshold(tss.Edwards(), pIDs(), testThreshold, testThreshold+1)
+		if assert.NoError(t, err) {
+			testCommitteeChange(t, reshare, keys(testParticipants))
+		}
+	})
+}
+
+func testCommitteeChange(t *testing.T, reshare *tss.Reshare, oldKeys []keygen.LocalPartySaveData) {
+	pCount := len(reshare.OldParties) + len(reshare.NewParties)
+	outCh := make(chan tss.Message, pCount*len(reshare.NewParties))
+	endCh := make(chan *keygen.LocalPartySaveData, pCount)
+	pub := oldKeys[0].EDDSAPub
+	oldCommittee, newCommittee, err := NewLocalParties(reshare, oldKeys, outCh, endCh)
+	if !assert.NoError(t, err) {
+		return
+	}
+	if _, err := test.RunResharingSequentially(oldCommittee, newCommittee, outCh); err != nil {
+		t.Fatal(err)
+	}
+	shares := make(vss.Shares, 0, len(reshare.NewParties))
+	for len(shares) < len(reshare.NewParties) {
+		save := <-endCh
+		if save.Xi == nil {
+			continue // of the old committee
+		}
+		assert.True(t, save.EDDSAPub.Equals(pub), "the public key must not change")
+		shares = append(shares, &vss.Share{Threshold: reshare.NewThreshold, ID: save.ShareID, Share: save.Xi})
+	}
+	sk, err := shares[:reshare.NewThreshold+1].ReConstruct(tss.Edwards())
+	if assert.NoError(t, err) {
+		assert.True(t, crypto.ScalarBaseMult(tss.Edwards(), sk).Equals(pub), "t+1 new shares must reconstruct the key")
+	}
+}
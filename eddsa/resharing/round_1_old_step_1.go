@@ -35,9 +35,14 @@ func (round *round1) Start() *tss.Error {
 	if !round.ReSharingParams().IsOldCommittee() {
 		return nil
 	}
-	round.allOldOK()
+	if !round.ReSharingParams().IsNewCommittee() {
+		// only the new committee receive in this round; a party of both waits for the other old parties
+		round.allOldOK()
+	} else if round.OldCommitteePartyID() == round.NewCommitteePartyID() {
+		return round.WrapError(errors.New("a party of both committees must have a distinct id in each"))
+	}
 
-	Pi := round.PartyID()
+	Pi := round.OldCommitteePartyID()
 	i := Pi.Index
 
 	// 1. PrepareForSigning() -> w_i
@@ -55,7 +60,7 @@ func (round *round1) Start() *tss.Error {
 	}
 
 	// 3.
-	flatVis, err := crypto.FlattenECPoints(vi)
+	flatVis, err := crypto.CompressECPoints(vi)
 	if err != nil {
 		return round.WrapError(err, round.PartyID())
 	}
@@ -67,10 +72,10 @@ func (round *round1) Start() *tss.Error {
 
 	// 5. "broadcast" C_i to members of the NEW committee
 	r1msg := NewDGRound1Message(
-		round.NewParties().IDs().Exclude(round.PartyID()), round.PartyID(),
+		round.NewParties().IDs().Exclude(Pi), Pi,
 		round.input.EDDSAPub, vCmt.C)
 	round.temp.dgRound1Messages[i] = r1msg
-	round.out <- r1msg
+	round.SendMessage(round.out, r1msg)
 
 	return nil
 }
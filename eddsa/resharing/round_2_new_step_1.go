@@ -23,15 +23,18 @@ func (round *round2) Start() *tss.Error {
 	if !round.ReSharingParams().IsNewCommittee() {
 		return nil
 	}
-	round.allNewOK()
+	if !round.ReSharingParams().IsOldCommittee() {
+		// only the old committee receive in this round; a party of both waits for the other new parties
+		round.allNewOK()
+	}
 
-	Pi := round.PartyID()
+	Pi := round.NewCommitteePartyID()
 	i := Pi.Index
 
 	// 1. "broadcast" "ACK" members of the OLD committee
 	r2msg := NewDGRound2Message(round.OldParties().IDs(), Pi)
 	round.temp.dgRound2Messages[i] = r2msg
-	round.out <- r2msg
+	round.SendMessage(round.out, r2msg)
 
 	return nil
 }
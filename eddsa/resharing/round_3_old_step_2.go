@@ -23,26 +23,32 @@ func (round *round3) Start() *tss.Error {
 	if !round.ReSharingParams().IsOldCommittee() {
 		return nil
 	}
-	round.allOldOK()
+	if !round.ReSharingParams().IsNewCommittee() {
+		round.allOldOK()
+	}
 
-	Pi := round.PartyID()
+	Pi := round.OldCommitteePartyID()
 	i := Pi.Index
 
 	// 1-2. send share to Pj from the new committee
 	for j, Pj := range round.NewParties().IDs() {
 		share := round.temp.NewShares[j]
-		r3msg1 := NewDGRound3Message1(Pj, round.PartyID(), share)
-		round.temp.dgRound3Message1s[i] = r3msg1
-		round.out <- r3msg1
+		r3msg1 := NewDGRound3Message1(Pj, Pi, share)
+		if Pj.KeyInt().Cmp(Pi.KeyInt()) == 0 {
+			// keep the share of this party in the new committee
+			round.temp.dgRound3Message1s[i] = r3msg1
+			continue
+		}
+		round.SendMessage(round.out, r3msg1)
 	}
 
 	// 3. broadcast de-commitment to new committees
 	vDeCmt := round.temp.VD
 	r3msg2 := NewDGRound3Message2(
-		round.NewParties().IDs().Exclude(round.PartyID()), round.PartyID(),
+		round.NewParties().IDs().Exclude(Pi), Pi,
 		vDeCmt)
 	round.temp.dgRound3Message2s[i] = r3msg2
-	round.out <- r3msg2
+	round.SendMessage(round.out, r3msg2)
 
 	return nil
 }
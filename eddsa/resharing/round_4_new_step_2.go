@@ -32,7 +32,7 @@ func (round *round4) Start() *tss.Error {
 		return nil
 	}
 
-	Pi := round.PartyID()
+	Pi := round.NewCommitteePartyID()
 	i := Pi.Index
 
 	// 1.
@@ -50,11 +50,11 @@ func (round *round4) Start() *tss.Error {
 		// 3. unpack flat "v" commitment content
 		vCmtDeCmt := commitments.HashCommitDecommit{C: vCj, D: vDj}
 		ok, flatVs := vCmtDeCmt.DeCommit()
-		if !ok || len(flatVs) != (round.NewThreshold()+1)*2 { // they're points so * 2
+		if !ok || len(flatVs) != round.NewThreshold()+1 {
 			// TODO collect culprits and return a list of them as per convention
 			return round.WrapError(errors.New("de-commitment of v_j0..v_jt failed"), round.Parties().IDs()[j])
 		}
-		vj, err := crypto.UnFlattenECPoints(round.Params().EC(), flatVs)
+		vj, err := crypto.DecompressECPoints(round.Params().EC(), flatVs)
 		if err != nil {
 			return round.WrapError(err, round.Parties().IDs()[j])
 		}
@@ -71,8 +71,10 @@ func (round *round4) Start() *tss.Error {
 			ID:        round.PartyID().KeyInt(),
 			Share:     new(big.Int).SetBytes(r3msg1.Share),
 		}
-		if ok := sharej.Verify(round.Params().EC(), round.NewThreshold(), vj); !ok {
-			return round.WrapError(errors.New("share from old committee did not pass Verify()"), round.Parties().IDs()[j])
+		if ok := round.VerifyProof("vss", func() bool {
+			return sharej.Verify(round.Params().EC(), round.NewThreshold(), vj)
+		}); !ok {
+			return round.WrapError(errors.New("share from old committee did not pass Verify()"), round.Parties().IDs()[j]).WithCode(tss.ErrProofVerifyFailed)
 		}
 
 		newXi = new(big.Int).Add(newXi, sharej.Share)
@@ -126,7 +128,7 @@ func (round *round4) Start() *tss.Error {
 	// 21. Send an "ACK" message to both committees to signal that we're ready to save our data
 	r4msg := NewDGRound4Message(round.OldAndNewParties(), Pi)
 	round.temp.dgRound4Messages[i] = r4msg
-	round.out <- r4msg
+	round.SendMessage(round.out, r4msg)
 
 	return nil
 }
@@ -27,8 +27,8 @@ func (round *round5) Start() *tss.Error {
 		round.save.ShareID = round.PartyID().KeyInt()
 		round.save.Xi = round.temp.newXi
 		round.save.Ks = round.temp.newKs
-
-	} else if round.IsOldCommittee() {
+	}
+	if round.IsOldCommittee() {
 		round.input.Xi.SetInt64(0)
 	}
 
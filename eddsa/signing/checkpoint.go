@@ -0,0 +1,111 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/kisdex/mpc-lib/crypto"
+	cmt "github.com/kisdex/mpc-lib/crypto/commitments"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+// checkpointTempData is the temp data of a party in a checkpoint
+type checkpointTempData struct {
+	Xi *big.Int // the share, which is tweaked in round 1 when signing with a derived key
+	Wi,
+	Ri *big.Int
+	PointRi   *crypto.ECPoint
+	DeCommit  cmt.HashDeCommitment
+	Cjs       []*big.Int
+	Si        *big.Int
+	R         *big.Int
+	SSID      []byte
+	SSIDNonce *big.Int
+}
+
+// Checkpoint returns the state of the running party, so that it can be resumed with Resume after a restart, e.g.
+// after every call to Start or Update. It holds the secret data of the party and must be stored encrypted.
+func (p *LocalParty) Checkpoint() ([]byte, error) {
+	cp, err := tss.BaseCheckpoint(p, TaskName, func(round tss.Round, cp *tss.Checkpoint) (err error) {
+		cp.OK = append([]bool{}, baseOf(round).ok...)
+		if cp.Messages, err = tss.NewCheckpointMessages(p.temp.signRound1Messages, p.temp.signRound2Messages,
+			p.temp.signRound3Messages); err != nil {
+			return err
+		}
+		if cp.Data, err = json.Marshal(p.data); err != nil {
+			return err
+		}
+		cp.Temp, err = json.Marshal(&checkpointTempData{
+			Xi:        p.keys.Xi,
+			Wi:        p.temp.wi,
+			Ri:        p.temp.ri,
+			PointRi:   p.temp.pointRi,
+			DeCommit:  p.temp.deCommit,
+			Cjs:       p.temp.cjs,
+			Si:        p.temp.si,
+			R:         p.temp.r,
+			SSID:      p.temp.ssid,
+			SSIDNonce: p.temp.ssidNonce,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(cp)
+}
+
+// Resume continues signing from a checkpoint taken with Checkpoint; it is called in place of Start on a party
+// constructed with the same message, key and parameters as the one the checkpoint was taken of
+func (p *LocalParty) Resume(checkpoint []byte) *tss.Error {
+	cp := new(tss.Checkpoint)
+	if err := json.Unmarshal(checkpoint, cp); err != nil {
+		return p.WrapError(err)
+	}
+	return tss.BaseResume(p, TaskName, cp, func(round tss.Round) *tss.Error {
+		msgs, err := tss.ParseCheckpointMessages(cp.Messages, p.params.Parties().IDs())
+		if err != nil {
+			return round.WrapError(err)
+		}
+		for _, msg := range msgs {
+			if _, err := p.StoreMessage(msg); err != nil {
+				return err
+			}
+		}
+		if err = json.Unmarshal(cp.Data, p.data); err != nil {
+			return round.WrapError(err)
+		}
+		temp := new(checkpointTempData)
+		if err = json.Unmarshal(cp.Temp, temp); err != nil {
+			return round.WrapError(err)
+		}
+		p.keys.Xi = temp.Xi
+		p.temp.wi, p.temp.ri, p.temp.pointRi, p.temp.deCommit = temp.Wi, temp.Ri, temp.PointRi, temp.DeCommit
+		p.temp.cjs, p.temp.si, p.temp.r = temp.Cjs, temp.Si, temp.R
+		p.temp.ssid, p.temp.ssidNonce = temp.SSID, temp.SSIDNonce
+
+		b := baseOf(round)
+		if len(cp.OK) != len(b.ok) {
+			return round.WrapError(fmt.Errorf("the checkpoint has %d round flags but the party has %d", len(cp.OK), len(b.ok)))
+		}
+		copy(b.ok, cp.OK)
+		b.number, b.started = cp.Round, true
+		return nil
+	})
+}
+
+// baseOf returns the state shared by `round` and the rounds before it
+func baseOf(round tss.Round) *base {
+	return round.(interface{ roundBase() *base }).roundBase()
+}
+
+func (round *base) roundBase() *base {
+	return round
+}
@@ -0,0 +1,100 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"crypto/elliptic"
+	"math/big"
+	"testing"
+
+	"github.com/ChainSafe/go-schnorrkel"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto"
+	"github.com/kisdex/mpc-lib/crypto/edwards448"
+	"github.com/kisdex/mpc-lib/crypto/ristretto255"
+	"github.com/kisdex/mpc-lib/eddsa/keygen"
+	"github.com/kisdex/mpc-lib/test"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+func TestE2ESignEd448(t *testing.T) {
+	setUp("info")
+	msg := big.NewInt(448)
+	pub, sigs := keygenAndSign(t, tss.Edwards448(), msg)
+	for _, sig := range sigs {
+		assert.Len(t, sig, edwards448.SignatureSize)
+		assert.True(t, edwards448.Verify(edwards448.EncodePoint(pub.X(), pub.Y()), msg.Bytes(), sig), "ed448 verify must pass")
+	}
+}
+
+func TestE2ESignSR25519(t *testing.T) {
+	setUp("info")
+	msg := new(big.Int).SetBytes([]byte("a substrate extrinsic payload"))
+	pub, sigs := keygenAndSign(t, tss.Ristretto(), msg)
+	if len(sigs) == 0 {
+		return
+	}
+	pk, err := schnorrkel.NewPublicKey([ristretto255.EncodingSize]byte(ristretto255.Encode(pub.X(), pub.Y())))
+	if !assert.NoError(t, err) {
+		return
+	}
+	for _, sig := range sigs {
+		signature := new(schnorrkel.Signature)
+		if !assert.NoError(t, signature.Decode([schnorrkel.SignatureSize]byte(sig)), "must be a schnorrkel signature") {
+			continue
+		}
+		ok, err := pk.Verify(signature, schnorrkel.NewSigningContext([]byte(SR25519Context), msg.Bytes()))
+		assert.NoError(t, err)
+		assert.True(t, ok, "sr25519 verify must pass")
+	}
+}
+
+// keygenAndSign runs a keygen on `ec` and signs `msg` with t+1 of the parties, returning the public key and the
+// signatures of the parties
+func keygenAndSign(t *testing.T, ec elliptic.Curve, msg *big.Int) (*crypto.ECPoint, [][]byte) {
+	pIDs := tss.GenerateTestPartyIDs(testParticipants)
+	p2pCtx := tss.NewPeerContext(pIDs)
+	outCh := make(chan tss.Message, len(pIDs)*len(pIDs)*2)
+	kgEndCh := make(chan *keygen.LocalPartySaveData, len(pIDs))
+	kgParties := make([]tss.Party, 0, len(pIDs))
+	for _, pID := range pIDs {
+		params := tss.NewParameters(ec, p2pCtx, pID, len(pIDs), testThreshold)
+		kgParties = append(kgParties, keygen.NewLocalParty(params, outCh, kgEndCh))
+	}
+	if _, tssErr := test.RunSequentially(kgParties, outCh); !assert.Nil(t, tssErr, "keygen should succeed") {
+		return nil, nil
+	}
+	keys := make([]keygen.LocalPartySaveData, len(pIDs))
+	for range pIDs {
+		save := <-kgEndCh
+		index, err := save.OriginalIndex()
+		if !assert.NoError(t, err) {
+			return nil, nil
+		}
+		keys[index] = *save
+	}
+
+	signPIDs := pIDs[:testThreshold+1]
+	signP2pCtx := tss.NewPeerContext(signPIDs)
+	endCh := make(chan *common.SignatureData, len(signPIDs))
+	parties := make([]tss.Party, 0, len(signPIDs))
+	for j, pID := range signPIDs {
+		params := tss.NewParameters(ec, signP2pCtx, pID, len(signPIDs), testThreshold)
+		parties = append(parties, NewLocalParty(msg, params, keys[j], outCh, endCh))
+	}
+	if _, tssErr := test.RunSequentially(parties, outCh); !assert.Nil(t, tssErr, "signing should succeed") {
+		return nil, nil
+	}
+
+	sigs := make([][]byte, 0, len(parties))
+	for range parties {
+		sigs = append(sigs, (<-endCh).Signature)
+	}
+	return keys[0].EDDSAPub, sigs
+}
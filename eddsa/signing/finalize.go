@@ -9,11 +9,8 @@ package signing
 import (
 	"errors"
 	"fmt"
+	"github.com/kisdex/mpc-lib/common"
 	"github.com/kisdex/mpc-lib/tss"
-	"math/big"
-
-	"github.com/agl/ed25519/edwards25519"
-	"github.com/decred/dcrd/dcrec/edwards/v2"
 )
 
 func (round *finalization) Start() *tss.Error {
@@ -24,33 +21,29 @@ func (round *finalization) Start() *tss.Error {
 	round.started = true
 	round.resetOK()
 
-	sumS := round.temp.si
+	sch, err := schemeOf(round.Params().EC())
+	if err != nil {
+		return round.WrapError(err)
+	}
+
+	modN := common.ModInt(round.Params().EC().Params().N)
+	s := round.temp.si
 	for j := range round.Parties().IDs() {
 		round.ok[j] = true
 		if j == round.PartyID().Index {
 			continue
 		}
 		r3msg := round.temp.signRound3Messages[j].Content().(*SignRound3Message)
-		sjBytes := bigIntToEncodedBytes(r3msg.UnmarshalS())
-		var tmpSumS [32]byte
-		edwards25519.ScMulAdd(&tmpSumS, sumS, bigIntToEncodedBytes(big.NewInt(1)), sjBytes)
-		sumS = &tmpSumS
+		s = modN.Add(s, r3msg.UnmarshalS())
 	}
-	s := encodedBytesToBigInt(sumS)
 
 	// save the signature for final output
-	round.data.Signature = append(bigIntToEncodedBytes(round.temp.r)[:], sumS[:]...)
+	round.data.Signature = sch.signature(round.temp.r, s)
 	round.data.R = round.temp.r.Bytes()
 	round.data.S = s.Bytes()
 	round.data.M = round.temp.m.Bytes()
 
-	pk := edwards.PublicKey{
-		Curve: round.Params().EC(),
-		X:     round.key.EDDSAPub.X(),
-		Y:     round.key.EDDSAPub.Y(),
-	}
-
-	ok := edwards.Verify(&pk, round.temp.m.Bytes(), round.temp.r, s)
+	ok := sch.verify(round.key.EDDSAPub, round.temp.m.Bytes(), round.data.Signature)
 	if !ok {
 		return round.WrapError(fmt.Errorf("signature verification failed"))
 	}
@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"github.com/kisdex/mpc-lib/common"
 	"github.com/kisdex/mpc-lib/crypto"
+	"github.com/kisdex/mpc-lib/eddsa/keygen"
 	cmt "github.com/kisdex/mpc-lib/crypto/commitments"
 	"github.com/kisdex/mpc-lib/tss"
 	"math/big"
@@ -20,6 +21,7 @@ import (
 // Implements Stringer
 var _ tss.Party = (*LocalParty)(nil)
 var _ fmt.Stringer = (*LocalParty)(nil)
+var _ tss.Zeroizer = (*LocalParty)(nil)
 
 type (
 	LocalParty struct {
@@ -53,11 +55,13 @@ type (
 
 		// round 2
 		cjs []*big.Int
-		si  *[32]byte
+		si  *big.Int
 
 		// round 3
 		r *big.Int
 
+		keyDerivationDelta *big.Int
+
 		ssid      []byte
 		ssidNonce *big.Int
 	}
@@ -69,6 +73,18 @@ func NewLocalParty(
 	key keygen.LocalPartySaveData,
 	out chan<- tss.Message,
 	end chan<- *common.SignatureData,
+) tss.Party {
+	return NewLocalPartyWithKDD(msg, params, key, nil, out, end)
+}
+
+// NewLocalPartyWithKDD returns a party with key derivation delta for HD support
+func NewLocalPartyWithKDD(
+	msg *big.Int,
+	params *tss.Parameters,
+	key keygen.LocalPartySaveData,
+	keyDerivationDelta *big.Int,
+	out chan<- tss.Message,
+	end chan<- *common.SignatureData,
 ) tss.Party {
 	partyCount := len(params.Parties().IDs())
 	p := &LocalParty{
@@ -86,6 +102,7 @@ func NewLocalParty(
 	p.temp.signRound3Messages = make([]tss.ParsedMessage, partyCount)
 
 	// temp data init
+	p.temp.keyDerivationDelta = keyDerivationDelta
 	p.temp.m = msg
 	p.temp.cjs = make([]*big.Int, partyCount)
 	return p
@@ -113,7 +130,7 @@ func (p *LocalParty) Update(msg tss.ParsedMessage) (ok bool, err *tss.Error) {
 }
 
 func (p *LocalParty) UpdateFromBytes(wireBytes []byte, from *tss.PartyID, isBroadcast bool) (bool, *tss.Error) {
-	msg, err := tss.ParseWireMessage(wireBytes, from, isBroadcast)
+	msg, err := messageTypes.ParseWireMessage(wireBytes, from, isBroadcast)
 	if err != nil {
 		return false, p.WrapError(err)
 	}
@@ -152,7 +169,7 @@ func (p *LocalParty) StoreMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
 		p.temp.signRound3Messages[fromPIdx] = msg
 
 	default: // unrecognised message, just ignore!
-		common.Logger.Warningf("unrecognised message ignored: %v", msg)
+		p.params.Logger().Warnf("unrecognised message ignored: %v", msg)
 		return false, nil
 	}
 	return true, nil
@@ -162,6 +179,16 @@ func (p *LocalParty) PartyID() *tss.PartyID {
 	return p.params.PartyID()
 }
 
+// Zeroize wipes the nonce and the other secrets of the signing, see tss.Zeroizer
+func (p *LocalParty) Zeroize() {
+	p.temp.Zeroize()
+}
+
+// Zeroize wipes the nonce ri and the additive share wi of the key
+func (temp *localTempData) Zeroize() {
+	common.Zeroize(temp.wi, temp.ri)
+}
+
 func (p *LocalParty) String() string {
 	return fmt.Sprintf("id: %s, %s", p.PartyID(), p.BaseParty.String())
 }
@@ -7,17 +7,17 @@
 package signing
 
 import (
+	"crypto/ed25519"
 	"fmt"
 	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/eddsa/keygen"
 	"github.com/kisdex/mpc-lib/test"
 	"github.com/kisdex/mpc-lib/tss"
 	"math/big"
 	"sync/atomic"
 	"testing"
 
-	"github.com/agl/ed25519/edwards25519"
 	"github.com/decred/dcrd/dcrec/edwards/v2"
-	"github.com/ipfs/go-log"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -27,7 +27,7 @@ const (
 )
 
 func setUp(level string) {
-	if err := log.SetLogLevel("tss-lib", level); err != nil {
+	if err := common.SetLogLevel(level); err != nil {
 		panic(err)
 	}
 
@@ -76,7 +76,7 @@ signing:
 	for {
 		select {
 		case err := <-errCh:
-			common.Logger.Errorf("Error: %s", err)
+			common.DefaultLogger().Errorf("Error: %s", err)
 			assert.FailNow(t, err.Error())
 			break signing
 
@@ -103,17 +103,16 @@ signing:
 				R := parties[0].temp.r
 
 				// BEGIN check s correctness
+				modN := common.ModInt(tss.Edwards().Params().N)
 				sumS := parties[0].temp.si
 				for i, p := range parties {
 					if i == 0 {
 						continue
 					}
 
-					var tmpSumS [32]byte
-					edwards25519.ScMulAdd(&tmpSumS, sumS, bigIntToEncodedBytes(big.NewInt(1)), p.temp.si)
-					sumS = &tmpSumS
+					sumS = modN.Add(sumS, p.temp.si)
 				}
-				fmt.Printf("S: %s\n", encodedBytesToBigInt(sumS).String())
+				fmt.Printf("S: %s\n", sumS.String())
 				fmt.Printf("R: %s\n", R.String())
 				// END check s correctness
 
@@ -140,3 +139,204 @@ signing:
 		}
 	}
 }
+
+func TestE2EWithHDKeyDerivation(t *testing.T) {
+	setUp("info")
+	threshold := testThreshold
+
+	// PHASE: load keygen fixtures
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	assert.Equal(t, testThreshold+1, len(keys))
+	assert.Equal(t, testThreshold+1, len(signPIDs))
+
+	chainCode, err := common.GetRandomBytes(32)
+	assert.NoError(t, err)
+
+	il, extendedChildPk, errorDerivation := derivingPubkeyFromPath(keys[0].EDDSAPub, chainCode, []uint32{44, 501, 0, 7}, tss.Edwards())
+	assert.NoErrorf(t, errorDerivation, "there should not be an error deriving the child public key")
+
+	keyDerivationDelta := il
+
+	err = UpdatePublicKeyAndAdjustBigXj(keyDerivationDelta, keys, &extendedChildPk.PublicKey, tss.Edwards())
+	assert.NoErrorf(t, err, "there should not be an error setting the derived keys")
+
+	// PHASE: signing
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]*LocalParty, 0, len(signPIDs))
+
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs))
+	endCh := make(chan *common.SignatureData, len(signPIDs))
+
+	updater := test.SharedPartyUpdater
+
+	msg := big.NewInt(200)
+	// init the parties
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(tss.Edwards(), p2pCtx, signPIDs[i], len(signPIDs), threshold)
+
+		P := NewLocalPartyWithKDD(msg, params, keys[i], keyDerivationDelta, outCh, endCh).(*LocalParty)
+		parties = append(parties, P)
+		go func(P *LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	var ended int32
+signing:
+	for {
+		select {
+		case err := <-errCh:
+			common.DefaultLogger().Errorf("Error: %s", err)
+			assert.FailNow(t, err.Error())
+			break signing
+
+		case msg := <-outCh:
+			dest := msg.GetTo()
+			if dest == nil {
+				for _, P := range parties {
+					if P.PartyID().Index == msg.GetFrom().Index {
+						continue
+					}
+					go updater(P, msg, errCh)
+				}
+			} else {
+				if dest[0].Index == msg.GetFrom().Index {
+					t.Fatalf("party %d tried to send a message to itself (%d)", dest[0].Index, msg.GetFrom().Index)
+				}
+				go updater(parties[dest[0].Index], msg, errCh)
+			}
+
+		case <-endCh:
+			atomic.AddInt32(&ended, 1)
+			if atomic.LoadInt32(&ended) == int32(len(signPIDs)) {
+				t.Logf("Done. Received signature data from %d participants", ended)
+
+				// BEGIN EDDSA verify against the derived child key
+				pk := edwards.PublicKey{
+					Curve: tss.Edwards(),
+					X:     extendedChildPk.X,
+					Y:     extendedChildPk.Y,
+				}
+				newSig, err := edwards.ParseSignature(parties[0].data.Signature)
+				assert.NoError(t, err)
+
+				ok := edwards.Verify(&pk, msg.Bytes(), newSig.R, newSig.S)
+				assert.True(t, ok, "eddsa verify must pass")
+				t.Log("EDDSA signing with HD key derivation test done.")
+				// END EDDSA verify
+
+				break signing
+			}
+		}
+	}
+}
+
+func TestPrepareForSigningCopiesShare(t *testing.T) {
+	xi := big.NewInt(42)
+	wi := PrepareForSigning(tss.Edwards(), 0, 1, xi, []*big.Int{big.NewInt(1)})
+	common.Zeroize(wi)
+	assert.Equal(t, int64(42), xi.Int64(), "wiping wi of a lone signer must not wipe the key share")
+}
+
+func TestE2EImportedKey(t *testing.T) {
+	setUp("info")
+
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	pIDs := tss.GenerateTestPartyIDs(testParticipants)
+	keys, err := keygen.ImportKey(tss.Edwards(), keygen.Ed25519Scalar(priv), pIDs, testThreshold)
+	if !assert.NoError(t, err, "should import the key") {
+		return
+	}
+
+	signPIDs := pIDs[:testThreshold+1]
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	outCh := make(chan tss.Message, len(signPIDs)*len(signPIDs))
+	endCh := make(chan *common.SignatureData, len(signPIDs))
+	parties := make([]tss.Party, 0, len(signPIDs))
+	msg := big.NewInt(200)
+	for i, pID := range signPIDs {
+		params := tss.NewParameters(tss.Edwards(), p2pCtx, pID, len(signPIDs), testThreshold)
+		parties = append(parties, NewLocalParty(msg, params, keys[i], outCh, endCh))
+	}
+	if _, tssErr := test.RunSequentially(parties, outCh); !assert.Nil(t, tssErr, "signing should succeed") {
+		return
+	}
+	sig := <-endCh
+	assert.True(t, ed25519.Verify(pub, msg.Bytes(), sig.Signature), "ed25519 verify must pass under the imported key")
+}
+
+func BenchmarkSign(b *testing.B) {
+	setUp("error")
+	msg := big.NewInt(200)
+	// a keygen of EdDSA is cheap, so the committees are not limited by the fixtures
+	for _, size := range test.BenchmarkSizes(7) {
+		pIDs := tss.GenerateTestPartyIDs(size.Parties)
+		p2pCtx := tss.NewPeerContext(pIDs)
+		outCh := make(chan tss.Message, size.Parties*size.Parties)
+		keygenEndCh := make(chan *keygen.LocalPartySaveData, size.Parties)
+		keygenParties := make([]tss.Party, 0, size.Parties)
+		for _, pID := range pIDs {
+			params := tss.NewParameters(tss.Edwards(), p2pCtx, pID, size.Parties, size.Threshold)
+			keygenParties = append(keygenParties, keygen.NewLocalParty(params, outCh, keygenEndCh))
+		}
+		if _, err := test.RunSequentially(keygenParties, outCh); err != nil {
+			b.Fatal(err)
+		}
+		keys := make([]keygen.LocalPartySaveData, size.Parties)
+		for range keys {
+			save := <-keygenEndCh
+			index, err := save.OriginalIndex()
+			if err != nil {
+				b.Fatal(err)
+			}
+			keys[index] = *save
+		}
+
+		signPIDs := pIDs[:size.Threshold+1]
+		signP2PCtx := tss.NewPeerContext(signPIDs)
+		b.Run(size.String(), func(b *testing.B) {
+			metrics := test.NewBenchmarkMetrics(len(signPIDs))
+			for i := 0; i < b.N; i++ {
+				endCh := make(chan *common.SignatureData, len(signPIDs))
+				parties := make([]tss.Party, 0, len(signPIDs))
+				for j, pID := range signPIDs {
+					params := tss.NewParameters(tss.Edwards(), signP2PCtx, pID, len(signPIDs), size.Threshold)
+					params.SetMetrics(metrics)
+					parties = append(parties, NewLocalParty(msg, params, keys[j], outCh, endCh))
+				}
+				if _, err := test.RunSequentially(parties, outCh); err != nil {
+					b.Fatal(err)
+				}
+			}
+			metrics.Report(b)
+		})
+	}
+}
+
+func FuzzMessages(f *testing.F) {
+	setUp("error")
+	keys, signPIDs, err := keygen.LoadKeygenTestFixtures(testThreshold + 1)
+	if err != nil {
+		f.Fatal(err)
+	}
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	outCh := make(chan tss.Message, len(signPIDs)*len(signPIDs))
+	endCh := make(chan *common.SignatureData, len(signPIDs))
+	parties := make([]tss.Party, 0, len(signPIDs))
+	for i := range signPIDs {
+		params := tss.NewParameters(tss.Edwards(), p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+		parties = append(parties, NewLocalParty(big.NewInt(200), params, keys[i], outCh, endCh))
+	}
+	transcript, tssErr := test.RunSequentially(parties, outCh)
+	if tssErr != nil {
+		f.Fatal(tssErr)
+	}
+	test.FuzzWireMessages(f, tss.Edwards(), transcript)
+}
@@ -20,12 +20,12 @@ import (
 // The following messages are registered on the Protocol Buffers "wire"
 
 var (
-	// Ensure that signing messages implement ValidateBasic
-	_ = []tss.MessageContent{
-		(*SignRound1Message)(nil),
-		(*SignRound2Message)(nil),
-		(*SignRound3Message)(nil),
-	}
+	// Ensure that signing messages implement ValidateBasic, and accept only them from the wire
+	messageTypes = tss.NewMessageTypes(
+		&SignRound1Message{},
+		&SignRound2Message{},
+		&SignRound3Message{},
+	)
 )
 
 // ----- //
@@ -68,8 +68,7 @@ func NewSignRound2Message(
 	dcBzs := common.BigIntsToBytes(deCommitment)
 	content := &SignRound2Message{
 		DeCommitment: dcBzs,
-		ProofAlphaX:  proof.Alpha.X().Bytes(),
-		ProofAlphaY:  proof.Alpha.Y().Bytes(),
+		ProofAlpha:   proof.Alpha.Bytes(),
 		ProofT:       proof.T.Bytes(),
 	}
 	msg := tss.NewMessageWrapper(meta, content)
@@ -78,9 +77,8 @@ func NewSignRound2Message(
 
 func (m *SignRound2Message) ValidateBasic() bool {
 	return m != nil &&
-		common.NonEmptyMultiBytes(m.DeCommitment, 3) &&
-		common.NonEmptyBytes(m.ProofAlphaX) &&
-		common.NonEmptyBytes(m.ProofAlphaY) &&
+		common.NonEmptyMultiBytes(m.DeCommitment, 2) &&
+		common.NonEmptyBytes(m.ProofAlpha) &&
 		common.NonEmptyBytes(m.ProofT)
 }
 
@@ -90,10 +88,7 @@ func (m *SignRound2Message) UnmarshalDeCommitment() []*big.Int {
 }
 
 func (m *SignRound2Message) UnmarshalZKProof(ec elliptic.Curve) (*schnorr.ZKProof, error) {
-	point, err := crypto.NewECPoint(
-		ec,
-		new(big.Int).SetBytes(m.GetProofAlphaX()),
-		new(big.Int).SetBytes(m.GetProofAlphaY()))
+	point, err := crypto.NewECPointFromBytes(ec, m.GetProofAlpha())
 	if err != nil {
 		return nil, err
 	}
@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"github.com/kisdex/mpc-lib/common"
 	"github.com/kisdex/mpc-lib/crypto"
+	"github.com/kisdex/mpc-lib/eddsa/keygen"
 	"github.com/kisdex/mpc-lib/crypto/commitments"
 	"github.com/kisdex/mpc-lib/tss"
 	"math/big"
@@ -42,7 +43,7 @@ func (round *round1) Start() *tss.Error {
 
 	// 2. make commitment
 	pointRi := crypto.ScalarBaseMult(round.Params().EC(), ri)
-	cmt := commitments.NewHashCommitment(pointRi.X(), pointRi.Y())
+	cmt := commitments.NewHashCommitment(new(big.Int).SetBytes(pointRi.Bytes()))
 
 	// 3. store r1 message pieces
 	round.temp.ri = ri
@@ -55,7 +56,7 @@ func (round *round1) Start() *tss.Error {
 	// 4. broadcast commitment
 	r1msg2 := NewSignRound1Message(round.PartyID(), cmt.C)
 	round.temp.signRound1Messages[i] = r1msg2
-	round.out <- r1msg2
+	round.SendMessage(round.out, r1msg2)
 
 	return nil
 }
@@ -94,6 +95,15 @@ func (round *round1) prepare() error {
 	xi := round.key.Xi
 	ks := round.key.Ks
 
+	if round.temp.keyDerivationDelta != nil {
+		// adding the key derivation delta to the xi's
+		// Suppose x has shamir shares x_0,     x_1,     ..., x_n
+		// So x + D has shamir shares  x_0 + D, x_1 + D, ..., x_n + D
+		mod := common.ModInt(round.Params().EC().Params().N)
+		xi = mod.Add(round.temp.keyDerivationDelta, xi)
+		round.key.Xi = xi
+	}
+
 	if round.Threshold()+1 > len(ks) {
 		return fmt.Errorf("t+1=%d is not satisfied by the key count of %d", round.Threshold()+1, len(ks))
 	}
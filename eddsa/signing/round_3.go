@@ -7,15 +7,12 @@
 package signing
 
 import (
-	"crypto/sha512"
-	"errors"
+	"github.com/pkg/errors"
 	"github.com/kisdex/mpc-lib/common"
 	"github.com/kisdex/mpc-lib/crypto"
 	"github.com/kisdex/mpc-lib/crypto/commitments"
 	"github.com/kisdex/mpc-lib/tss"
 	"math/big"
-
-	"github.com/agl/ed25519/edwards25519"
 )
 
 func (round *round3) Start() *tss.Error {
@@ -27,10 +24,13 @@ func (round *round3) Start() *tss.Error {
 	round.started = true
 	round.resetOK()
 
+	sch, err := schemeOf(round.Params().EC())
+	if err != nil {
+		return round.WrapError(err)
+	}
+
 	// 1. init R
-	var R edwards25519.ExtendedGroupElement
-	riBytes := bigIntToEncodedBytes(round.temp.ri)
-	edwards25519.GeScalarMultBase(&R, riBytes)
+	R := round.temp.pointRi
 
 	// 2-6. compute R
 	i := round.PartyID().Index
@@ -43,15 +43,15 @@ func (round *round3) Start() *tss.Error {
 		msg := round.temp.signRound2Messages[j]
 		r2msg := msg.Content().(*SignRound2Message)
 		cmtDeCmt := commitments.HashCommitDecommit{C: round.temp.cjs[j], D: r2msg.UnmarshalDeCommitment()}
-		ok, coordinates := cmtDeCmt.DeCommit()
+		ok, encoded := cmtDeCmt.DeCommit()
 		if !ok {
-			return round.WrapError(errors.New("de-commitment verify failed"))
+			return round.WrapError(errors.New("de-commitment verify failed")).WithCode(tss.ErrProofVerifyFailed)
 		}
-		if len(coordinates) != 2 {
-			return round.WrapError(errors.New("length of de-commitment should be 2"))
+		if len(encoded) != 1 {
+			return round.WrapError(errors.New("length of de-commitment should be 1"))
 		}
 
-		Rj, err := crypto.NewECPoint(round.Params().EC(), coordinates[0], coordinates[1])
+		Rj, err := crypto.NewECPointFromBytes(round.Params().EC(), encoded[0].Bytes())
 		Rj = Rj.EightInvEight()
 		if err != nil {
 			return round.WrapError(errors.Wrapf(err, "NewECPoint(Rj)"), Pj)
@@ -60,44 +60,32 @@ func (round *round3) Start() *tss.Error {
 		if err != nil {
 			return round.WrapError(errors.New("failed to unmarshal Rj proof"), Pj)
 		}
-		ok = proof.Verify(ContextJ, Rj)
+		ok = round.VerifyProof("schnorr", func() bool { return proof.Verify(ContextJ, Rj) })
 		if !ok {
-			return round.WrapError(errors.New("failed to prove Rj"), Pj)
+			return round.WrapError(errors.New("failed to prove Rj"), Pj).WithCode(tss.ErrProofVerifyFailed)
 		}
 
-		extendedRj := ecPointToExtendedElement(round.Params().EC(), Rj.X(), Rj.Y())
-		R = addExtendedElements(R, extendedRj)
+		if R, err = R.Add(Rj); err != nil {
+			return round.WrapError(errors.Wrapf(err, "R.Add(Rj)"), Pj)
+		}
 	}
 
-	// 7. compute lambda
-	var encodedR [32]byte
-	R.ToBytes(&encodedR)
-	encodedPubKey := ecPointToEncodedBytes(round.key.EDDSAPub.X(), round.key.EDDSAPub.Y())
-
-	// h = hash512(k || A || M)
-	h := sha512.New()
-	h.Reset()
-	h.Write(encodedR[:])
-	h.Write(encodedPubKey[:])
-	h.Write(round.temp.m.Bytes())
-
-	var lambda [64]byte
-	h.Sum(lambda[:0])
-	var lambdaReduced [32]byte
-	edwards25519.ScReduce(&lambdaReduced, &lambda)
+	// 7. compute lambda = H(R || A || M)
+	encodedR := sch.encodePoint(R)
+	lambda := sch.challenge(encodedR, sch.encodePoint(round.key.EDDSAPub), round.temp.m.Bytes())
 
-	// 8. compute si
-	var localS [32]byte
-	edwards25519.ScMulAdd(&localS, &lambdaReduced, bigIntToEncodedBytes(round.temp.wi), riBytes)
+	// 8. compute si = ri + lambda * wi
+	modN := common.ModInt(round.Params().EC().Params().N)
+	si := modN.Add(round.temp.ri, modN.Mul(lambda, round.temp.wi))
 
-	// 9. store r3 message pieces
-	round.temp.si = &localS
-	round.temp.r = encodedBytesToBigInt(&encodedR)
+	// 9. store r3 message pieces; r is the encoded R read as a little-endian integer
+	round.temp.si = si
+	round.temp.r = new(big.Int).SetBytes(reversed(encodedR))
 
 	// 10. broadcast si to other parties
-	r3msg := NewSignRound3Message(round.PartyID(), encodedBytesToBigInt(&localS))
+	r3msg := NewSignRound3Message(round.PartyID(), si)
 	round.temp.signRound3Messages[round.PartyID().Index] = r3msg
-	round.out <- r3msg
+	round.SendMessage(round.out, r3msg)
 
 	return nil
 }
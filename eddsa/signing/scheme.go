@@ -0,0 +1,143 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"crypto/elliptic"
+	"crypto/sha512"
+	"fmt"
+	"math/big"
+
+	"github.com/ChainSafe/go-schnorrkel"
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+
+	"github.com/kisdex/mpc-lib/crypto"
+	"github.com/kisdex/mpc-lib/crypto/edwards448"
+	"github.com/kisdex/mpc-lib/crypto/ristretto255"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+// SR25519Context is the signing context of the sr25519 signatures made on tss.Ristretto, that of the transactions of
+// Substrate chains
+const SR25519Context = "substrate"
+
+// scheme is the Schnorr variant of a curve: the encoding of its points and signatures, its challenge and its
+// verification. The rounds compute R = sum(Rj) and s = sum(ri + lambda*wi) mod N on any curve and leave the rest to
+// the scheme.
+type scheme interface {
+	encodePoint(p *crypto.ECPoint) []byte
+	// signature encodes (R, s) for the encoded R read as a little-endian integer r
+	signature(r, s *big.Int) []byte
+	// challenge returns lambda = H(R || A || M) mod N
+	challenge(encodedR, encodedPub, msg []byte) *big.Int
+	verify(pub *crypto.ECPoint, msg, sig []byte) bool
+}
+
+func schemeOf(ec elliptic.Curve) (scheme, error) {
+	name, _ := tss.GetCurveName(ec)
+	switch name {
+	case tss.Ed25519:
+		return ed25519Scheme{}, nil
+	case tss.Ed448:
+		return ed448Scheme{}, nil
+	case tss.Ristretto255:
+		return sr25519Scheme{}, nil
+	}
+	return nil, fmt.Errorf("EdDSA signing does not support the curve %q", ec.Params().Name)
+}
+
+type ed25519Scheme struct{}
+
+func (ed25519Scheme) encodePoint(p *crypto.ECPoint) []byte {
+	return ecPointToEncodedBytes(p.X(), p.Y())[:]
+}
+
+func (ed25519Scheme) signature(r, s *big.Int) []byte {
+	return append(bigIntToEncodedBytes(r)[:], bigIntToEncodedBytes(s)[:]...)
+}
+
+func (ed25519Scheme) challenge(encodedR, encodedPub, msg []byte) *big.Int {
+	h := sha512.New()
+	h.Write(encodedR)
+	h.Write(encodedPub)
+	h.Write(msg)
+	lambda := new(big.Int).SetBytes(reversed(h.Sum(nil)))
+	return lambda.Mod(lambda, edwards.Edwards().Params().N)
+}
+
+func (ed25519Scheme) verify(pub *crypto.ECPoint, msg, sig []byte) bool {
+	signature, err := edwards.ParseSignature(sig)
+	if err != nil {
+		return false
+	}
+	pk := edwards.PublicKey{Curve: pub.Curve(), X: pub.X(), Y: pub.Y()}
+	return edwards.Verify(&pk, msg, signature.R, signature.S)
+}
+
+type ed448Scheme struct{}
+
+func (ed448Scheme) encodePoint(p *crypto.ECPoint) []byte {
+	return edwards448.EncodePoint(p.X(), p.Y())
+}
+
+func (ed448Scheme) signature(r, s *big.Int) []byte {
+	return append(edwards448.EncodeScalar(r), edwards448.EncodeScalar(s)...)
+}
+
+func (ed448Scheme) challenge(encodedR, encodedPub, msg []byte) *big.Int {
+	return edwards448.Challenge(encodedR, encodedPub, msg)
+}
+
+func (ed448Scheme) verify(pub *crypto.ECPoint, msg, sig []byte) bool {
+	return edwards448.Verify(edwards448.EncodePoint(pub.X(), pub.Y()), msg, sig)
+}
+
+// sr25519Scheme is the Schnorr signature of schnorrkel over ristretto255, whose challenge comes from a merlin
+// transcript of the signing context and message
+type sr25519Scheme struct{}
+
+func (sr25519Scheme) encodePoint(p *crypto.ECPoint) []byte {
+	return ristretto255.Encode(p.X(), p.Y())
+}
+
+func (sr25519Scheme) signature(r, s *big.Int) []byte {
+	sig := append(bigIntToEncodedBytes(r)[:], bigIntToEncodedBytes(s)[:]...)
+	// marks the signature as one of schnorrkel rather than Ed25519
+	sig[len(sig)-1] |= 0x80
+	return sig
+}
+
+func (sr25519Scheme) challenge(encodedR, encodedPub, msg []byte) *big.Int {
+	t := schnorrkel.NewSigningContext([]byte(SR25519Context), msg)
+	t.AppendMessage([]byte("proto-name"), []byte("Schnorr-sig"))
+	t.AppendMessage([]byte("sign:pk"), encodedPub)
+	t.AppendMessage([]byte("sign:R"), encodedR)
+	lambda := new(big.Int).SetBytes(reversed(t.ExtractBytes([]byte("sign:c"), 64)))
+	return lambda.Mod(lambda, ristretto255.Ristretto255().Params().N)
+}
+
+func (sr25519Scheme) verify(pub *crypto.ECPoint, msg, sig []byte) bool {
+	pk, err := schnorrkel.NewPublicKey([ristretto255.EncodingSize]byte(ristretto255.Encode(pub.X(), pub.Y())))
+	if err != nil || len(sig) != schnorrkel.SignatureSize {
+		return false
+	}
+	signature := new(schnorrkel.Signature)
+	if err = signature.Decode([schnorrkel.SignatureSize]byte(sig)); err != nil {
+		return false
+	}
+	ok, err := pk.Verify(signature, schnorrkel.NewSigningContext([]byte(SR25519Context), msg))
+	return err == nil && ok
+}
+
+// reversed returns a copy of `bz` in the reverse order, e.g. a little-endian encoding in big-endian
+func reversed(bz []byte) []byte {
+	out := make([]byte, len(bz))
+	for i := range bz {
+		out[len(bz)-1-i] = bz[i]
+	}
+	return out
+}
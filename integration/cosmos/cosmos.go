@@ -0,0 +1,124 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package cosmos signs Cosmos SDK transactions in SIGN_MODE_DIRECT with a threshold secp256k1 key: the sign bytes,
+// the serialized SignDoc, are hashed with SHA-256 as the secp256k1 keys of the SDK do, the hash is signed, e.g. by
+// ecdsa/signing, and the signature is returned in the 64-byte R || S format with a low S that Tendermint verifies.
+
+package cosmos
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcutil/bech32"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	secpecdsa "github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"golang.org/x/crypto/ripemd160"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto"
+)
+
+const (
+	// SignatureLen is the length of a Tendermint secp256k1 signature, R || S
+	SignatureLen = common.RawSignatureBytesLen
+
+	// PubKeyLen is the length of the compressed public key of a cosmos.crypto.secp256k1.PubKey
+	PubKeyLen = 33
+)
+
+// SignFunc signs `hash` with the threshold key, e.g. by running ecdsa/signing with the hash as its message
+type SignFunc func(ctx context.Context, hash []byte) (*common.SignatureData, error)
+
+// SignDocBytes returns the sign bytes of SIGN_MODE_DIRECT: the protobuf encoding of the cosmos.tx.v1beta1.SignDoc of
+// the serialized TxBody and AuthInfo of a transaction, with the fields in order and those of default value omitted
+func SignDocBytes(bodyBytes, authInfoBytes []byte, chainID string, accountNumber uint64) []byte {
+	var b []byte
+	if len(bodyBytes) > 0 {
+		b = protowire.AppendTag(b, 1, protowire.BytesType)
+		b = protowire.AppendBytes(b, bodyBytes)
+	}
+	if len(authInfoBytes) > 0 {
+		b = protowire.AppendTag(b, 2, protowire.BytesType)
+		b = protowire.AppendBytes(b, authInfoBytes)
+	}
+	if chainID != "" {
+		b = protowire.AppendTag(b, 3, protowire.BytesType)
+		b = protowire.AppendString(b, chainID)
+	}
+	if accountNumber != 0 {
+		b = protowire.AppendTag(b, 4, protowire.VarintType)
+		b = protowire.AppendVarint(b, accountNumber)
+	}
+	return b
+}
+
+// SignDirect signs the SIGN_MODE_DIRECT sign bytes `signBytes` with `sign`, and returns the R || S signature for the
+// signatures of a TxRaw. The signature is checked against `pub` as a validator would check it.
+func SignDirect(ctx context.Context, signBytes []byte, pub *crypto.ECPoint, sign SignFunc) ([]byte, error) {
+	if len(signBytes) == 0 {
+		return nil, errors.New("the sign bytes are empty")
+	}
+	hash := sha256.Sum256(signBytes)
+	sigData, err := sign(ctx, hash[:])
+	if err != nil {
+		return nil, err
+	}
+	sig, err := sigData.RawSignature()
+	if err != nil {
+		return nil, err
+	}
+	if !VerifySignature(pub, signBytes, sig) {
+		return nil, fmt.Errorf("the signature does not verify under the public key %x", PubKeyBytes(pub))
+	}
+	return sig, nil
+}
+
+// VerifySignature verifies the R || S signature `sig` of `signBytes` by the rules of the secp256k1 keys of Tendermint
+// and the Cosmos SDK: the signature must have a low S, and it signs the SHA-256 hash of the bytes
+func VerifySignature(pub *crypto.ECPoint, signBytes, sig []byte) bool {
+	if pub == nil || len(sig) != SignatureLen {
+		return false
+	}
+	pk, err := secp256k1.ParsePubKey(PubKeyBytes(pub))
+	if err != nil {
+		return false
+	}
+	var r, s secp256k1.ModNScalar
+	if overflow := r.SetByteSlice(sig[:32]); overflow || r.IsZero() {
+		return false
+	}
+	if overflow := s.SetByteSlice(sig[32:]); overflow || s.IsZero() || s.IsOverHalfOrder() {
+		return false
+	}
+	hash := sha256.Sum256(signBytes)
+	return secpecdsa.NewSignature(&r, &s).Verify(hash[:], pk)
+}
+
+// PubKeyBytes returns the 33-byte compressed public key of a cosmos.crypto.secp256k1.PubKey
+func PubKeyBytes(pub *crypto.ECPoint) []byte {
+	bz := make([]byte, PubKeyLen)
+	bz[0] = 2 + byte(pub.Y().Bit(0))
+	pub.X().FillBytes(bz[1:])
+	return bz
+}
+
+// Address returns the bech32 account address of the public key with the human-readable prefix `hrp`, e.g. "cosmos":
+// the encoding of RIPEMD-160(SHA-256(compressed public key))
+func Address(pub *crypto.ECPoint, hrp string) (string, error) {
+	sha := sha256.Sum256(PubKeyBytes(pub))
+	hasher := ripemd160.New()
+	hasher.Write(sha[:])
+	data, err := bech32.ConvertBits(hasher.Sum(nil), 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+	return bech32.Encode(hrp, data)
+}
@@ -0,0 +1,93 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package cosmos
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcutil/bech32"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ripemd160"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/ecdsa/keygen"
+	"github.com/kisdex/mpc-lib/ecdsa/signing"
+	"github.com/kisdex/mpc-lib/test"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+func TestSignDocBytes(t *testing.T) {
+	signBytes := SignDocBytes([]byte("body"), []byte("auth"), "test-1", 300)
+	assert.Equal(t, "0a04626f6479120461757468"+"1a06746573742d31"+"20ac02", hex.EncodeToString(signBytes))
+
+	// the fields of default value are omitted
+	assert.Equal(t, "0a04626f6479", hex.EncodeToString(SignDocBytes([]byte("body"), nil, "", 0)))
+}
+
+func TestSignDirect(t *testing.T) {
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(test.TestThreshold+1, test.TestParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	pub := keys[0].ECDSAPub
+
+	sign := func(_ context.Context, hash []byte) (*common.SignatureData, error) {
+		p2pCtx := tss.NewPeerContext(signPIDs)
+		outCh := make(chan tss.Message, len(signPIDs)*len(signPIDs))
+		endCh := make(chan *common.SignatureData, len(signPIDs))
+		parties := make([]tss.Party, 0, len(signPIDs))
+		for i := range signPIDs {
+			params := tss.NewParameters(tss.S256(), p2pCtx, signPIDs[i], len(signPIDs), test.TestThreshold)
+			parties = append(parties, signing.NewLocalParty(new(big.Int).SetBytes(hash), params, keys[i], outCh, endCh))
+		}
+		if _, err := test.RunSequentially(parties, outCh); err != nil {
+			return nil, err
+		}
+		return <-endCh, nil
+	}
+	signBytes := SignDocBytes([]byte("body"), []byte("auth"), "cosmoshub-4", 12)
+	sig, err := SignDirect(context.Background(), signBytes, pub, sign)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, sig, SignatureLen)
+	assert.True(t, VerifySignature(pub, signBytes, sig))
+	assert.False(t, VerifySignature(pub, SignDocBytes([]byte("body"), []byte("auth"), "cosmoshub-4", 13), sig))
+
+	// the same signature with a high S is refused, as Tendermint refuses it
+	N := tss.S256().Params().N
+	highS := make([]byte, SignatureLen)
+	copy(highS, sig[:32])
+	new(big.Int).Sub(N, new(big.Int).SetBytes(sig[32:])).FillBytes(highS[32:])
+	assert.False(t, VerifySignature(pub, signBytes, highS))
+}
+
+func TestAddress(t *testing.T) {
+	keys, _, err := keygen.LoadKeygenTestFixtures(1)
+	assert.NoError(t, err, "should load keygen fixtures")
+	pub := keys[0].ECDSAPub
+
+	pubBytes := PubKeyBytes(pub)
+	assert.Len(t, pubBytes, PubKeyLen)
+
+	address, err := Address(pub, "cosmos")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(address, "cosmos1"))
+	hrp, data, err := bech32.Decode(address)
+	assert.NoError(t, err)
+	assert.Equal(t, "cosmos", hrp)
+	decoded, err := bech32.ConvertBits(data, 5, 8, false)
+	assert.NoError(t, err)
+
+	sha := sha256.Sum256(pubBytes)
+	hasher := ripemd160.New()
+	hasher.Write(sha[:])
+	assert.Equal(t, hasher.Sum(nil), decoded)
+}
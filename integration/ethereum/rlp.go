@@ -0,0 +1,57 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package ethereum
+
+import (
+	"math/big"
+)
+
+// rlpItem is a value of the recursive length prefix encoding of Ethereum: a byte string, or a list of items
+type rlpItem interface {
+	rlpEncode() []byte
+}
+
+type (
+	rlpString []byte
+	rlpList   []rlpItem
+)
+
+func (s rlpString) rlpEncode() []byte {
+	if len(s) == 1 && s[0] < 0x80 {
+		return []byte{s[0]}
+	}
+	return append(rlpHeader(0x80, len(s)), s...)
+}
+
+func (l rlpList) rlpEncode() []byte {
+	var payload []byte
+	for _, item := range l {
+		payload = append(payload, item.rlpEncode()...)
+	}
+	return append(rlpHeader(0xc0, len(payload)), payload...)
+}
+
+// rlpUint encodes an integer as its big-endian bytes without leading zeros; 0 is the empty string
+func rlpUint(x uint64) rlpString {
+	return rlpBigInt(new(big.Int).SetUint64(x))
+}
+
+func rlpBigInt(x *big.Int) rlpString {
+	if x == nil {
+		return rlpString{}
+	}
+	return x.Bytes()
+}
+
+// rlpHeader returns the prefix of a string (offset 0x80) or a list (offset 0xc0) whose payload has `length` bytes
+func rlpHeader(offset byte, length int) []byte {
+	if length <= 55 {
+		return []byte{offset + byte(length)}
+	}
+	lengthBytes := new(big.Int).SetInt64(int64(length)).Bytes()
+	return append([]byte{offset + 55 + byte(len(lengthBytes))}, lengthBytes...)
+}
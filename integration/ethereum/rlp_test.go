@@ -0,0 +1,45 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package ethereum
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// the examples of the RLP specification of the Ethereum yellow paper and wiki
+func TestRLPEncode(t *testing.T) {
+	longString := rlpString("Lorem ipsum dolor sit amet, consectetur adipisicing elit")
+	tests := []struct {
+		item rlpItem
+		hex  string
+	}{
+		{rlpString("dog"), "83646f67"},
+		{rlpList{rlpString("cat"), rlpString("dog")}, "c88363617483646f67"},
+		{rlpString(""), "80"},
+		{rlpList{}, "c0"},
+		{rlpUint(0), "80"},
+		{rlpString{0x00}, "00"},
+		{rlpUint(15), "0f"},
+		{rlpUint(1024), "820400"},
+		{rlpBigInt(new(big.Int).Lsh(big.NewInt(1), 64)), "89010000000000000000"},
+		// the set theoretical representation of three
+		{rlpList{rlpList{}, rlpList{rlpList{}}, rlpList{rlpList{}, rlpList{rlpList{}}}}, "c7c0c1c0c3c0c1c0"},
+		{longString, "b838" + hex.EncodeToString(longString)},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.hex, hex.EncodeToString(test.item.rlpEncode()))
+	}
+
+	// a list of 56 bytes has a long header too
+	list := rlpList{longString[:55]}
+	assert.True(t, bytes.HasPrefix(list.rlpEncode(), []byte{0xf8, 56, 0xb7}))
+}
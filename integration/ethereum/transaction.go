@@ -0,0 +1,265 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package ethereum signs Ethereum transactions with a threshold secp256k1 key: it computes the signing hash of a
+// legacy (EIP-155) or dynamic-fee (EIP-1559) transaction, has it signed, e.g. by ecdsa/signing, and assembles the
+// signed transaction in the encoding of eth_sendRawTransaction.
+
+package ethereum
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	secpecdsa "github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"golang.org/x/crypto/sha3"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto"
+)
+
+const (
+	AddressLength = 20
+
+	// DynamicFeeTxType is the type byte of the EIP-2718 envelope of an EIP-1559 transaction
+	DynamicFeeTxType = 0x02
+)
+
+type (
+	Address [AddressLength]byte
+
+	AccessTuple struct {
+		Address     Address
+		StorageKeys [][32]byte
+	}
+
+	// AccessList is the EIP-2930 list of the addresses and storage keys that a transaction accesses
+	AccessList []AccessTuple
+
+	// LegacyTx is an unsigned legacy transaction
+	LegacyTx struct {
+		Nonce    uint64
+		GasPrice *big.Int
+		Gas      uint64
+		To       *Address // nil creates a contract
+		Value    *big.Int
+		Data     []byte
+
+		// ChainID is the chain id of EIP-155; a nil ChainID signs the transaction without replay protection
+		ChainID *big.Int
+	}
+
+	// DynamicFeeTx is an unsigned EIP-1559 transaction
+	DynamicFeeTx struct {
+		ChainID    *big.Int
+		Nonce      uint64
+		GasTipCap  *big.Int
+		GasFeeCap  *big.Int
+		Gas        uint64
+		To         *Address // nil creates a contract
+		Value      *big.Int
+		Data       []byte
+		AccessList AccessList
+	}
+
+	// Transaction is an unsigned transaction, *LegacyTx or *DynamicFeeTx
+	Transaction interface {
+		// SigningHash returns the hash that the sender signs
+		SigningHash() []byte
+		// encodeSigned returns the network encoding of the transaction with its signature
+		encodeSigned(recid byte, r, s *big.Int) []byte
+	}
+
+	// SignFunc signs `hash` with the threshold key of the sender, e.g. by running ecdsa/signing with the hash as
+	// its message. The signature must carry its recovery id.
+	SignFunc func(ctx context.Context, hash []byte) (*common.SignatureData, error)
+)
+
+var (
+	_ Transaction = (*LegacyTx)(nil)
+	_ Transaction = (*DynamicFeeTx)(nil)
+)
+
+// SignTx signs the transaction `tx` of the sender `from` with `sign`, and returns the signed transaction in the
+// encoding of eth_sendRawTransaction. The signature is checked to recover `from`, so that a transaction is never
+// broadcast with the wrong v.
+func SignTx(ctx context.Context, tx Transaction, from Address, sign SignFunc) ([]byte, error) {
+	hash := tx.SigningHash()
+	sig, err := sign(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	// [R || S || V] with V in {0, 1}
+	ethSig, err := sig.EthereumSignature()
+	if err != nil {
+		return nil, err
+	}
+	r, s, recid := new(big.Int).SetBytes(ethSig[:32]), new(big.Int).SetBytes(ethSig[32:64]), ethSig[64]
+	// EIP-2: a signature with a high s is invalid
+	if s.Cmp(new(big.Int).Rsh(secp256k1.S256().N, 1)) > 0 {
+		return nil, errors.New("the signature s must be in the lower half of the group order")
+	}
+
+	compact := append([]byte{27 + recid}, ethSig[:64]...)
+	pub, _, err := secpecdsa.RecoverCompact(compact, hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed recovering the signer of the transaction: %w", err)
+	}
+	if recovered := pubKeyToAddress(pub.SerializeUncompressed()); recovered != from {
+		return nil, fmt.Errorf("the signature recovers %s instead of the sender %s", recovered.Hex(), from.Hex())
+	}
+	return tx.encodeSigned(recid, r, s), nil
+}
+
+// TxHash returns the hash of a signed transaction in its network encoding, by which the network knows it
+func TxHash(raw []byte) []byte {
+	return keccak256(raw)
+}
+
+// ----- //
+
+func (tx *LegacyTx) fields() rlpList {
+	return rlpList{
+		rlpUint(tx.Nonce),
+		rlpBigInt(tx.GasPrice),
+		rlpUint(tx.Gas),
+		rlpAddress(tx.To),
+		rlpBigInt(tx.Value),
+		rlpString(tx.Data),
+	}
+}
+
+// SigningHash returns keccak256(rlp([nonce, gasPrice, gas, to, value, data, chainId, 0, 0])) of EIP-155, or the hash
+// of the first six fields without a chain id
+func (tx *LegacyTx) SigningHash() []byte {
+	fields := tx.fields()
+	if tx.ChainID != nil {
+		fields = append(fields, rlpBigInt(tx.ChainID), rlpUint(0), rlpUint(0))
+	}
+	return keccak256(fields.rlpEncode())
+}
+
+// encodeSigned returns rlp([nonce, gasPrice, gas, to, value, data, v, r, s]) for v = recid + chainId*2 + 35, or
+// recid + 27 without a chain id
+func (tx *LegacyTx) encodeSigned(recid byte, r, s *big.Int) []byte {
+	v := big.NewInt(int64(recid) + 27)
+	if tx.ChainID != nil {
+		v.Lsh(tx.ChainID, 1).Add(v, big.NewInt(int64(recid)+35))
+	}
+	return append(tx.fields(), rlpBigInt(v), rlpBigInt(r), rlpBigInt(s)).rlpEncode()
+}
+
+// ----- //
+
+func (tx *DynamicFeeTx) fields() rlpList {
+	return rlpList{
+		rlpBigInt(tx.ChainID),
+		rlpUint(tx.Nonce),
+		rlpBigInt(tx.GasTipCap),
+		rlpBigInt(tx.GasFeeCap),
+		rlpUint(tx.Gas),
+		rlpAddress(tx.To),
+		rlpBigInt(tx.Value),
+		rlpString(tx.Data),
+		tx.AccessList.rlpList(),
+	}
+}
+
+// SigningHash returns keccak256(0x02 || rlp([chainId, nonce, maxPriorityFeePerGas, maxFeePerGas, gas, to, value, data,
+// accessList])) of EIP-1559
+func (tx *DynamicFeeTx) SigningHash() []byte {
+	return keccak256(append([]byte{DynamicFeeTxType}, tx.fields().rlpEncode()...))
+}
+
+// encodeSigned returns 0x02 || rlp([chainId, ..., accessList, yParity, r, s])
+func (tx *DynamicFeeTx) encodeSigned(recid byte, r, s *big.Int) []byte {
+	fields := append(tx.fields(), rlpUint(uint64(recid)), rlpBigInt(r), rlpBigInt(s))
+	return append([]byte{DynamicFeeTxType}, fields.rlpEncode()...)
+}
+
+func (al AccessList) rlpList() rlpList {
+	list := make(rlpList, 0, len(al))
+	for _, tuple := range al {
+		keys := make(rlpList, 0, len(tuple.StorageKeys))
+		for _, key := range tuple.StorageKeys {
+			keys = append(keys, rlpString(key[:]))
+		}
+		list = append(list, rlpList{rlpString(tuple.Address[:]), keys})
+	}
+	return list
+}
+
+// ----- //
+
+// ParseAddress parses a hex address, with or without the 0x prefix
+func ParseAddress(s string) (Address, error) {
+	var a Address
+	bz, err := hex.DecodeString(strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X"))
+	if err != nil {
+		return a, fmt.Errorf("invalid address %q: %w", s, err)
+	}
+	if len(bz) != AddressLength {
+		return a, fmt.Errorf("invalid address %q: %d bytes instead of %d", s, len(bz), AddressLength)
+	}
+	copy(a[:], bz)
+	return a, nil
+}
+
+// PubKeyToAddress returns the address of the secp256k1 public key `pub`, e.g. the ECDSAPub of ecdsa/keygen
+func PubKeyToAddress(pub *crypto.ECPoint) Address {
+	uncompressed := make([]byte, 65)
+	uncompressed[0] = 4
+	pub.X().FillBytes(uncompressed[1:33])
+	pub.Y().FillBytes(uncompressed[33:])
+	return pubKeyToAddress(uncompressed)
+}
+
+// Hex returns the EIP-55 mixed-case checksum encoding of the address
+func (a Address) Hex() string {
+	lower := hex.EncodeToString(a[:])
+	hash := keccak256([]byte(lower))
+	out := []byte(lower)
+	for i, c := range out {
+		// a letter is upper case when the matching nibble of the hash is at least 8
+		nibble := hash[i/2] >> 4
+		if i%2 == 1 {
+			nibble = hash[i/2] & 0x0f
+		}
+		if 'a' <= c && c <= 'f' && nibble >= 8 {
+			out[i] = c - 'a' + 'A'
+		}
+	}
+	return "0x" + string(out)
+}
+
+func (a Address) String() string {
+	return a.Hex()
+}
+
+// pubKeyToAddress returns the last 20 bytes of the hash of the uncompressed public key without its prefix
+func pubKeyToAddress(uncompressed []byte) Address {
+	var a Address
+	copy(a[:], keccak256(uncompressed[1:])[12:])
+	return a
+}
+
+func rlpAddress(to *Address) rlpString {
+	if to == nil {
+		return rlpString{}
+	}
+	return to[:]
+}
+
+func keccak256(data []byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(data)
+	return h.Sum(nil)
+}
@@ -0,0 +1,126 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package ethereum
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	secpecdsa "github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto"
+	"github.com/kisdex/mpc-lib/ecdsa/keygen"
+	"github.com/kisdex/mpc-lib/ecdsa/signing"
+	"github.com/kisdex/mpc-lib/test"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	bz, err := hex.DecodeString(s)
+	assert.NoError(t, err)
+	return bz
+}
+
+// the example of EIP-155, whose signature is that of the private key 0x4646...46
+func TestLegacyTxEIP155(t *testing.T) {
+	to, err := ParseAddress("0x3535353535353535353535353535353535353535")
+	assert.NoError(t, err)
+	tx := &LegacyTx{
+		Nonce:    9,
+		GasPrice: big.NewInt(20000000000),
+		Gas:      21000,
+		To:       &to,
+		Value:    new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil),
+		ChainID:  big.NewInt(1),
+	}
+	assert.Equal(t, "daf5a779ae972f972197303d7b574746c7ef83eadac0f2791ad23db92e4c8e53", hex.EncodeToString(tx.SigningHash()))
+
+	sk := secp256k1.PrivKeyFromBytes(mustHex(t, "4646464646464646464646464646464646464646464646464646464646464646"))
+	pub := sk.PubKey()
+	from := PubKeyToAddress(crypto.NewECPointNoCurveCheck(tss.S256(), pub.X(), pub.Y()))
+	assert.Equal(t, "0x9d8A62f656a8d1615C1294fd71e9CFb3E4855A4F", from.Hex())
+
+	sign := func(_ context.Context, hash []byte) (*common.SignatureData, error) {
+		return &common.SignatureData{
+			R:                 mustHex(t, "28ef61340bd939bc2195fe537567866003e1a15d3c71ff63e1590620aa636276"),
+			S:                 mustHex(t, "67cbe9d8997f761aecb703304b3800ccf555c9f3dc64214b297fb1966a3b6d83"),
+			SignatureRecovery: []byte{0},
+			M:                 hash,
+		}, nil
+	}
+	raw, err := SignTx(context.Background(), tx, from, sign)
+	assert.NoError(t, err)
+	assert.Equal(t, "f86c098504a817c800825208943535353535353535353535353535353535353535880de0b6b3a76400008025a028ef61340bd939bc2195fe537567866003e1a15d3c71ff63e1590620aa636276a067cbe9d8997f761aecb703304b3800ccf555c9f3dc64214b297fb1966a3b6d83", hex.EncodeToString(raw))
+
+	// a signature that recovers another address is refused
+	_, err = SignTx(context.Background(), tx, to, sign)
+	assert.Error(t, err)
+}
+
+func TestDynamicFeeTxThresholdSigning(t *testing.T) {
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(test.TestThreshold+1, test.TestParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	from := PubKeyToAddress(keys[0].ECDSAPub)
+
+	to, err := ParseAddress("0x3535353535353535353535353535353535353535")
+	assert.NoError(t, err)
+	tx := &DynamicFeeTx{
+		ChainID:   big.NewInt(11155111),
+		Nonce:     3,
+		GasTipCap: big.NewInt(1500000000),
+		GasFeeCap: big.NewInt(30000000000),
+		Gas:       50000,
+		To:        &to,
+		Value:     big.NewInt(1),
+		Data:      []byte{0xa9, 0x05, 0x9c, 0xbb},
+		AccessList: AccessList{{
+			Address:     to,
+			StorageKeys: [][32]byte{{31: 1}},
+		}},
+	}
+
+	sign := func(_ context.Context, hash []byte) (*common.SignatureData, error) {
+		p2pCtx := tss.NewPeerContext(signPIDs)
+		outCh := make(chan tss.Message, len(signPIDs)*len(signPIDs))
+		endCh := make(chan *common.SignatureData, len(signPIDs))
+		parties := make([]tss.Party, 0, len(signPIDs))
+		for i := range signPIDs {
+			params := tss.NewParameters(tss.S256(), p2pCtx, signPIDs[i], len(signPIDs), test.TestThreshold)
+			parties = append(parties, signing.NewLocalParty(new(big.Int).SetBytes(hash), params, keys[i], outCh, endCh))
+		}
+		if _, err := test.RunSequentially(parties, outCh); err != nil {
+			return nil, err
+		}
+		return <-endCh, nil
+	}
+	raw, err := SignTx(context.Background(), tx, from, sign)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, byte(DynamicFeeTxType), raw[0])
+	assert.Len(t, TxHash(raw), 32)
+
+	// the signature at the end of the encoding, yParity || r || s, recovers the sender; r or s is shorter than 32
+	// bytes (a0 prefix) once in about 128 signatures, which this check skips
+	sig := raw[len(raw)-66:]
+	if sig[0] == 0xa0 && sig[33] == 0xa0 {
+		yParity := raw[len(raw)-67]
+		if yParity == 0x80 {
+			yParity = 0
+		}
+		compact := append(append([]byte{27 + yParity}, sig[1:33]...), sig[34:]...)
+		pub, _, err := secpecdsa.RecoverCompact(compact, tx.SigningHash())
+		if assert.NoError(t, err) {
+			assert.Equal(t, from, pubKeyToAddress(pub.SerializeUncompressed()))
+		}
+	}
+}
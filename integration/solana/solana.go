@@ -0,0 +1,165 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package solana signs Solana transactions with a threshold Ed25519 key: the serialized message of a transaction is
+// signed as it is, without a pre-hash, e.g. by eddsa/signing, and the signature is placed in the slot of the signer
+// among the signatures of the transaction in its wire encoding.
+
+package solana
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto"
+)
+
+const (
+	SignatureLen = ed25519.SignatureSize
+	PubKeyLen    = ed25519.PublicKeySize
+
+	// versionPrefix marks the first byte of a versioned message; a legacy message starts with its header instead
+	versionPrefix = 0x80
+)
+
+// SignFunc signs `message` with the threshold key, e.g. by running eddsa/signing with new(big.Int).SetBytes(message)
+// as its message. Ed25519 hashes the message itself, so `message` must be signed as it is and not hashed first.
+type SignFunc func(ctx context.Context, message []byte) (*common.SignatureData, error)
+
+// SignMessage signs the serialized message of a transaction with `sign` and returns the 64-byte signature, checked
+// against the public key `pub` as the Solana runtime checks it.
+func SignMessage(ctx context.Context, message []byte, pub *crypto.ECPoint, sign SignFunc) ([]byte, error) {
+	if len(message) == 0 {
+		return nil, errors.New("the message to sign is empty")
+	}
+	// eddsa/signing takes the message as an integer, which drops leading zero bytes; no message starts with a zero,
+	// since a legacy message starts with its count of signatures and a versioned one with versionPrefix
+	if message[0] == 0 {
+		return nil, errors.New("a message that starts with a zero byte is not a Solana message")
+	}
+	sig, err := sign(ctx, message)
+	if err != nil {
+		return nil, err
+	}
+	if len(sig.Signature) != SignatureLen {
+		return nil, fmt.Errorf("the signature has %d bytes instead of %d", len(sig.Signature), SignatureLen)
+	}
+	if !VerifySignature(pub, message, sig.Signature) {
+		return nil, errors.New("the signature does not verify under the public key")
+	}
+	return sig.Signature, nil
+}
+
+// SignTransaction signs the serialized message of a transaction with `sign` and returns the transaction in its wire
+// encoding: the signatures of the required signers, followed by the message. The signature of `pub` goes to its slot;
+// the slots of the other signers are left zero for them to fill.
+func SignTransaction(ctx context.Context, message []byte, pub *crypto.ECPoint, sign SignFunc) ([]byte, error) {
+	signers, err := requiredSigners(message)
+	if err != nil {
+		return nil, err
+	}
+	pubBytes := PubKeyBytes(pub)
+	slot := -1
+	for i, signer := range signers {
+		if string(signer) == string(pubBytes) {
+			slot = i
+			break
+		}
+	}
+	if slot < 0 {
+		return nil, fmt.Errorf("the key %s is not a required signer of the message", Address(pub))
+	}
+	sig, err := SignMessage(ctx, message, pub, sign)
+	if err != nil {
+		return nil, err
+	}
+	tx := appendShortVec(nil, len(signers))
+	sigs := make([]byte, len(signers)*SignatureLen)
+	copy(sigs[slot*SignatureLen:], sig)
+	tx = append(tx, sigs...)
+	return append(tx, message...), nil
+}
+
+// VerifySignature reports whether `sig` is the Ed25519 signature of `message` by `pub`
+func VerifySignature(pub *crypto.ECPoint, message, sig []byte) bool {
+	if len(sig) != SignatureLen || !crypto.ValidatePoints(pub) {
+		return false
+	}
+	return ed25519.Verify(PubKeyBytes(pub), message, sig)
+}
+
+// PubKeyBytes returns the 32-byte encoding of the Ed25519 public key `pub`, e.g. the EDDSAPub of eddsa/keygen
+func PubKeyBytes(pub *crypto.ECPoint) []byte {
+	return edwards.NewPublicKey(pub.X(), pub.Y()).Serialize()
+}
+
+// Address returns the base58 address of the Ed25519 public key `pub`
+func Address(pub *crypto.ECPoint) string {
+	return base58.Encode(PubKeyBytes(pub))
+}
+
+// ----- //
+
+// requiredSigners returns the public keys of the signers of a legacy or v0 message, the first keys of its account keys
+func requiredSigners(message []byte) ([][]byte, error) {
+	rest := message
+	if len(rest) > 0 && rest[0]&versionPrefix != 0 {
+		if version := rest[0] &^ versionPrefix; version != 0 {
+			return nil, fmt.Errorf("unsupported message version %d", version)
+		}
+		rest = rest[1:]
+	}
+	// the header: the counts of required signatures, of read-only signed and of read-only unsigned accounts
+	if len(rest) < 3 {
+		return nil, errors.New("the message is too short for its header")
+	}
+	numSigners := int(rest[0])
+	rest = rest[3:]
+	numKeys, rest, err := readShortVec(rest)
+	if err != nil {
+		return nil, err
+	}
+	if numSigners == 0 || numKeys < numSigners {
+		return nil, fmt.Errorf("the message requires %d signatures of its %d account keys", numSigners, numKeys)
+	}
+	if len(rest) < numKeys*PubKeyLen {
+		return nil, errors.New("the message is too short for its account keys")
+	}
+	signers := make([][]byte, numSigners)
+	for i := range signers {
+		signers[i] = rest[i*PubKeyLen : (i+1)*PubKeyLen]
+	}
+	return signers, nil
+}
+
+// appendShortVec appends the compact-u16 encoding of `n`: 7 bits a byte, the lowest first
+func appendShortVec(b []byte, n int) []byte {
+	for n >= 0x80 {
+		b = append(b, byte(n&0x7f)|0x80)
+		n >>= 7
+	}
+	return append(b, byte(n))
+}
+
+func readShortVec(b []byte) (int, []byte, error) {
+	n := 0
+	for i := 0; i < 3; i++ {
+		if len(b) <= i {
+			return 0, nil, errors.New("the message is too short for a length")
+		}
+		n |= int(b[i]&0x7f) << (7 * i)
+		if b[i]&0x80 == 0 {
+			return n, b[i+1:], nil
+		}
+	}
+	return 0, nil, errors.New("a length of the message overflows a u16")
+}
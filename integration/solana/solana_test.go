@@ -0,0 +1,137 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package solana
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/binary"
+	"math/big"
+	"testing"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/eddsa/keygen"
+	"github.com/kisdex/mpc-lib/eddsa/signing"
+	"github.com/kisdex/mpc-lib/test"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+func init() {
+	tss.SetCurve(tss.Edwards())
+}
+
+// transferMessage returns a legacy message of a system transfer of `lamports` from `from` to `to`
+func transferMessage(from, to []byte, lamports uint64) []byte {
+	// header: one signer, no read-only signer, one read-only account, the system program
+	msg := []byte{1, 0, 1}
+	msg = appendShortVec(msg, 3)
+	msg = append(msg, from...)
+	msg = append(msg, to...)
+	msg = append(msg, make([]byte, PubKeyLen)...)
+	// the recent blockhash
+	msg = append(msg, make([]byte, 32)...)
+	for i := len(msg) - 32; i < len(msg); i++ {
+		msg[i] = byte(i)
+	}
+	data := binary.LittleEndian.AppendUint32(nil, 2)
+	data = binary.LittleEndian.AppendUint64(data, lamports)
+	msg = appendShortVec(msg, 1)
+	msg = append(msg, 2)
+	msg = appendShortVec(msg, 2)
+	msg = append(msg, 0, 1)
+	msg = appendShortVec(msg, len(data))
+	return append(msg, data...)
+}
+
+func TestShortVec(t *testing.T) {
+	for n, expected := range map[int][]byte{
+		0:      {0x00},
+		0x7f:   {0x7f},
+		0x80:   {0x80, 0x01},
+		0x3fff: {0xff, 0x7f},
+		0x4000: {0x80, 0x80, 0x01},
+		0xffff: {0xff, 0xff, 0x03},
+	} {
+		assert.Equal(t, expected, appendShortVec(nil, n))
+		decoded, rest, err := readShortVec(append(expected, 0xaa))
+		assert.NoError(t, err)
+		assert.Equal(t, n, decoded)
+		assert.Equal(t, []byte{0xaa}, rest)
+	}
+	_, _, err := readShortVec([]byte{0x80, 0x80, 0x80, 0x01})
+	assert.Error(t, err)
+}
+
+func TestSignTransaction(t *testing.T) {
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(test.TestThreshold+1, test.TestParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	pub := keys[0].EDDSAPub
+
+	sign := func(_ context.Context, message []byte) (*common.SignatureData, error) {
+		p2pCtx := tss.NewPeerContext(signPIDs)
+		outCh := make(chan tss.Message, len(signPIDs)*len(signPIDs))
+		endCh := make(chan *common.SignatureData, len(signPIDs))
+		parties := make([]tss.Party, 0, len(signPIDs))
+		for i := range signPIDs {
+			params := tss.NewParameters(tss.Edwards(), p2pCtx, signPIDs[i], len(signPIDs), test.TestThreshold)
+			parties = append(parties, signing.NewLocalParty(new(big.Int).SetBytes(message), params, keys[i], outCh, endCh))
+		}
+		if _, err := test.RunSequentially(parties, outCh); err != nil {
+			return nil, err
+		}
+		return <-endCh, nil
+	}
+
+	from := PubKeyBytes(pub)
+	assert.Len(t, from, PubKeyLen)
+	assert.Equal(t, from, base58.Decode(Address(pub)))
+	to := make([]byte, PubKeyLen)
+	to[0] = 7
+	message := transferMessage(from, to, 1_000_000)
+
+	tx, err := SignTransaction(context.Background(), message, pub, sign)
+	if !assert.NoError(t, err) {
+		return
+	}
+	// one signature, then the message
+	assert.Equal(t, byte(1), tx[0])
+	assert.Equal(t, message, tx[1+SignatureLen:])
+	sig := tx[1 : 1+SignatureLen]
+	assert.True(t, ed25519.Verify(from, message, sig), "the signature must verify with crypto/ed25519")
+	assert.True(t, VerifySignature(pub, message, sig))
+	assert.False(t, VerifySignature(pub, transferMessage(from, to, 1_000_001), sig))
+
+	// the same message as a v0 message without address lookup tables
+	v0 := append([]byte{versionPrefix}, message...)
+	v0 = append(v0, 0)
+	tx, err = SignTransaction(context.Background(), v0, pub, sign)
+	if assert.NoError(t, err) {
+		assert.True(t, ed25519.Verify(from, v0, tx[1:1+SignatureLen]))
+	}
+
+	// the key must be a required signer
+	_, err = SignTransaction(context.Background(), transferMessage(to, from, 1), pub, sign)
+	assert.Error(t, err)
+}
+
+func TestRequiredSigners(t *testing.T) {
+	from, to := make([]byte, PubKeyLen), make([]byte, PubKeyLen)
+	from[0], to[0] = 1, 2
+	signers, err := requiredSigners(transferMessage(from, to, 1))
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{from}, signers)
+
+	_, err = requiredSigners([]byte{versionPrefix | 1, 1, 0, 1})
+	assert.Error(t, err, "only v0 messages are supported")
+	_, err = requiredSigners([]byte{2, 0, 0, 1})
+	assert.Error(t, err, "the message must have its signer keys")
+	_, err = requiredSigners(transferMessage(from, to, 1)[:40])
+	assert.Error(t, err, "the message is truncated")
+}
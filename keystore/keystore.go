@@ -0,0 +1,247 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package keystore encrypts the save data of a party, e.g. a keygen.LocalPartySaveData, for storage at rest.
+//
+// A keystore file is a header followed by the JSON encoding of the save data, encrypted with XChaCha20-Poly1305.
+// The header holds the format version, the key derivation function and its parameters, the salt and the nonce; it is
+// authenticated along with the ciphertext, so any modification of the file is detected when it is opened.
+package keystore
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/scrypt"
+)
+
+// KDF identifies how the encryption key of a keystore file is obtained
+type KDF uint8
+
+const (
+	// KDFRaw uses a raw 32-byte key as is
+	KDFRaw KDF = iota
+	// KDFArgon2id derives the key from a passphrase with Argon2id
+	KDFArgon2id
+	// KDFScrypt derives the key from a passphrase with scrypt
+	KDFScrypt
+)
+
+const (
+	// Version is the format version of the keystore files written by this package
+	Version = 1
+
+	// KeySize is the size of a raw key
+	KeySize = chacha20poly1305.KeySize
+
+	magic      = "MPCK"
+	saltSize   = 16
+	headerSize = len(magic) + 1 + 1 + 3*4 + saltSize + chacha20poly1305.NonceSizeX
+
+	argon2Time, argon2Memory, argon2Threads = 3, 64 * 1024, 4
+	scryptN, scryptR, scryptP               = 1 << 15, 8, 1
+
+	// upper bounds on the parameters read from a file, so that a forged header cannot exhaust the host
+	maxArgon2Time, maxArgon2Memory, maxArgon2Threads = 16, 4 * 1024 * 1024, 64
+	maxScryptN, maxScryptR, maxScryptP               = 1 << 22, 32, 16
+)
+
+type (
+	// Key is the secret that a keystore file is encrypted under: a raw key or a passphrase
+	Key struct {
+		kdf    KDF
+		secret []byte
+	}
+
+	header struct {
+		kdf    KDF
+		params [3]uint32
+		salt   [saltSize]byte
+		nonce  [chacha20poly1305.NonceSizeX]byte
+	}
+)
+
+// RawKey returns a Key for a raw 32-byte key, e.g. one unwrapped from a KMS
+func RawKey(key []byte) Key {
+	return Key{kdf: KDFRaw, secret: key}
+}
+
+// Argon2idPassphrase returns a Key that derives the encryption key from `passphrase` with Argon2id
+func Argon2idPassphrase(passphrase []byte) Key {
+	return Key{kdf: KDFArgon2id, secret: passphrase}
+}
+
+// ScryptPassphrase returns a Key that derives the encryption key from `passphrase` with scrypt
+func ScryptPassphrase(passphrase []byte) Key {
+	return Key{kdf: KDFScrypt, secret: passphrase}
+}
+
+// Seal encrypts the JSON encoding of `data` under `key`
+func Seal(data interface{}, key Key) ([]byte, error) {
+	plaintext, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	defer zero(plaintext)
+	return Encrypt(plaintext, key)
+}
+
+// Open decrypts a keystore file sealed under `key` into `data`
+func Open(sealed []byte, key Key, data interface{}) error {
+	plaintext, err := Decrypt(sealed, key)
+	if err != nil {
+		return err
+	}
+	defer zero(plaintext)
+	return json.Unmarshal(plaintext, data)
+}
+
+// SealToFile seals `data` under `key` and writes it to a new file at `path` that only the owner can read
+func SealToFile(path string, data interface{}, key Key) error {
+	sealed, err := Seal(data, key)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(sealed); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// OpenFile reads the keystore file at `path` and decrypts it under `key` into `data`
+func OpenFile(path string, key Key, data interface{}) error {
+	sealed, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return Open(sealed, key, data)
+}
+
+// Encrypt encrypts `plaintext` under `key` and prepends the header of the keystore format
+func Encrypt(plaintext []byte, key Key) ([]byte, error) {
+	h := &header{kdf: key.kdf}
+	switch key.kdf {
+	case KDFRaw:
+	case KDFArgon2id:
+		h.params = [3]uint32{argon2Time, argon2Memory, argon2Threads}
+	case KDFScrypt:
+		h.params = [3]uint32{scryptN, scryptR, scryptP}
+	default:
+		return nil, fmt.Errorf("unknown key derivation function %d", key.kdf)
+	}
+	if _, err := rand.Read(h.salt[:]); err != nil {
+		return nil, err
+	}
+	if _, err := rand.Read(h.nonce[:]); err != nil {
+		return nil, err
+	}
+	aead, err := h.aead(key)
+	if err != nil {
+		return nil, err
+	}
+	hdr := h.bytes()
+	return aead.Seal(hdr, h.nonce[:], plaintext, hdr), nil
+}
+
+// Decrypt checks the integrity of a keystore file and decrypts it under `key`
+func Decrypt(sealed []byte, key Key) ([]byte, error) {
+	h, err := parseHeader(sealed)
+	if err != nil {
+		return nil, err
+	}
+	if h.kdf != key.kdf {
+		return nil, fmt.Errorf("the keystore was sealed with key derivation function %d but the key is for %d", h.kdf, key.kdf)
+	}
+	aead, err := h.aead(key)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, h.nonce[:], sealed[headerSize:], sealed[:headerSize])
+	if err != nil {
+		return nil, errors.New("could not decrypt the keystore: wrong key or corrupted data")
+	}
+	return plaintext, nil
+}
+
+// ----- //
+
+func parseHeader(sealed []byte) (*header, error) {
+	if len(sealed) < headerSize+chacha20poly1305.Overhead || string(sealed[:len(magic)]) != magic {
+		return nil, errors.New("not a keystore file")
+	}
+	if v := sealed[len(magic)]; v != Version {
+		return nil, fmt.Errorf("unsupported keystore version %d; this library supports version %d", v, Version)
+	}
+	h := &header{kdf: KDF(sealed[len(magic)+1])}
+	pos := len(magic) + 2
+	for i := range h.params {
+		h.params[i] = binary.BigEndian.Uint32(sealed[pos:])
+		pos += 4
+	}
+	pos += copy(h.salt[:], sealed[pos:])
+	copy(h.nonce[:], sealed[pos:])
+	return h, nil
+}
+
+func (h *header) bytes() []byte {
+	bz := make([]byte, 0, headerSize)
+	bz = append(bz, magic...)
+	bz = append(bz, Version, byte(h.kdf))
+	for _, p := range h.params {
+		bz = binary.BigEndian.AppendUint32(bz, p)
+	}
+	bz = append(bz, h.salt[:]...)
+	return append(bz, h.nonce[:]...)
+}
+
+// aead derives the encryption key with the parameters of the header
+func (h *header) aead(key Key) (cipher.AEAD, error) {
+	var k []byte
+	switch h.kdf {
+	case KDFRaw:
+		if len(key.secret) != KeySize {
+			return nil, fmt.Errorf("a raw key must be %d bytes long", KeySize)
+		}
+		k = append([]byte{}, key.secret...)
+	case KDFArgon2id:
+		t, m, p := h.params[0], h.params[1], h.params[2]
+		if t == 0 || maxArgon2Time < t || m == 0 || maxArgon2Memory < m || p == 0 || maxArgon2Threads < p {
+			return nil, errors.New("invalid Argon2id parameters")
+		}
+		k = argon2.IDKey(key.secret, h.salt[:], t, m, uint8(p), KeySize)
+	case KDFScrypt:
+		N, r, p := h.params[0], h.params[1], h.params[2]
+		if N < 2 || maxScryptN < N || r == 0 || maxScryptR < r || p == 0 || maxScryptP < p {
+			return nil, errors.New("invalid scrypt parameters")
+		}
+		var err error
+		if k, err = scrypt.Key(key.secret, h.salt[:], int(N), int(r), int(p), KeySize); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown key derivation function %d", h.kdf)
+	}
+	defer zero(k)
+	return chacha20poly1305.NewX(k)
+}
+
+func zero(bz []byte) {
+	for i := range bz {
+		bz[i] = 0
+	}
+}
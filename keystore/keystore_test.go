@@ -0,0 +1,79 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keystore_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/ecdsa/keygen"
+	. "github.com/kisdex/mpc-lib/keystore"
+)
+
+func TestSealOpen(t *testing.T) {
+	keys, _, err := keygen.LoadKeygenTestFixtures(1)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	save := keys[0]
+	rawKey := bytes.Repeat([]byte{7}, KeySize)
+
+	for name, key := range map[string]Key{
+		"raw":      RawKey(rawKey),
+		"argon2id": Argon2idPassphrase([]byte("correct horse battery staple")),
+		"scrypt":   ScryptPassphrase([]byte("correct horse battery staple")),
+	} {
+		t.Run(name, func(t *testing.T) {
+			sealed, err := Seal(&save, key)
+			assert.NoError(t, err)
+			assert.False(t, bytes.Contains(sealed, save.Xi.Bytes()), "the share must not be stored in the clear")
+
+			var opened keygen.LocalPartySaveData
+			assert.NoError(t, Open(sealed, key, &opened))
+			assert.Equal(t, 0, save.Xi.Cmp(opened.Xi))
+			assert.Equal(t, 0, save.ECDSAPub.X().Cmp(opened.ECDSAPub.X()))
+
+			for _, i := range []int{5, len(sealed) - 1} {
+				tampered := append([]byte{}, sealed...)
+				tampered[i] ^= 1
+				assert.Error(t, Open(tampered, key, &opened), "a modified header or ciphertext must be detected")
+			}
+		})
+	}
+
+	sealed, err := Seal(&save, Argon2idPassphrase([]byte("passphrase")))
+	assert.NoError(t, err)
+	var opened keygen.LocalPartySaveData
+	assert.Error(t, Open(sealed, Argon2idPassphrase([]byte("wrong")), &opened))
+	assert.Error(t, Open(sealed, ScryptPassphrase([]byte("passphrase")), &opened))
+	assert.Error(t, Open(sealed, RawKey(rawKey), &opened))
+	_, err = Seal(&save, RawKey(rawKey[:16]))
+	assert.Error(t, err, "a raw key must be 32 bytes")
+}
+
+func TestSealToFile(t *testing.T) {
+	keys, _, err := keygen.LoadKeygenTestFixtures(1)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	key := RawKey(bytes.Repeat([]byte{7}, KeySize))
+	path := filepath.Join(t.TempDir(), "share.keystore")
+
+	assert.NoError(t, SealToFile(path, &keys[0], key))
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	assert.Error(t, SealToFile(path, &keys[0], key), "an existing keystore must not be overwritten")
+
+	var opened keygen.LocalPartySaveData
+	assert.NoError(t, OpenFile(path, key, &opened))
+	assert.Equal(t, 0, keys[0].Xi.Cmp(opened.Xi))
+}
@@ -0,0 +1,267 @@
+// Package mobile runs a party of threshold ECDSA in a mobile app, bound with gomobile:
+//
+//	gomobile bind -target=ios,android github.com/kisdex/mpc-lib/mobile
+//
+// gomobile binds only functions and methods of strings, bytes, signed integers, errors and interfaces of such methods,
+// so the party of the ecdsa package is flattened here: the ids of the parties are ints, a list of them is a string of
+// comma-separated ids, the calls block and take a timeout in seconds instead of a context, and the app sends the
+// messages of the party with a Transport of its own and hands over the messages of the other parties with OnMessage.
+// Every operation runs in a session, so that the party may sign several messages concurrently.
+package mobile
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/ecdsa"
+)
+
+const (
+	// reshareSessionPrefix marks the session ids of the messages of Reshare, which the ecdsa party receives apart from
+	// those of its sessions
+	reshareSessionPrefix = "reshare:"
+)
+
+// Transport sends the messages of a party. The app implements it, e.g. over its connection to a relay server.
+type Transport interface {
+	// Send sends `msg` of the session `sessionID` to the party `to`, or to all of the other parties of the session if
+	// `isBroadcast` is set. The recipient must get the session id, the sender and `isBroadcast` along with the message,
+	// and pass them to OnMessage.
+	Send(sessionID string, msg []byte, isBroadcast bool, to int)
+}
+
+// ecdsaParty is the party of the ecdsa package, which is not exported
+type ecdsaParty interface {
+	Init(parties []uint16, threshold int, sendMsg func(msg []byte, isBroadcast bool, to uint16))
+	SetParties(parties []uint16, threshold int)
+	OnMsg(msgBytes []byte, from uint16, broadcast bool)
+	OnSessionMsg(sessionID string, msgBytes []byte, from uint16, broadcast bool)
+	SetSessionSender(sendMsg ecdsa.SessionSender)
+	SetShareData(shareData []byte) error
+	ThresholdPK() ([]byte, error)
+	KeyGenSession(ctx context.Context, sessionID string) ([]byte, error)
+	SignSession(ctx context.Context, sessionID string, msgHash []byte) ([]byte, error)
+	Reshare(ctx context.Context, oldCommittee, newCommittee []uint16, newThreshold int) ([]byte, error)
+}
+
+// ECDSAParty is a party of threshold ECDSA on P-256
+type ECDSAParty struct {
+	party     ecdsaParty
+	transport Transport
+
+	lock sync.Mutex
+	// the parties of the key and its threshold, which the sessions run with
+	parties   []uint16
+	threshold int
+	// the session of the running Reshare, if any, and the messages of the sessions of Reshare that it has not started
+	resharing string
+	pending   map[string][]pendingMsg
+}
+
+type pendingMsg struct {
+	msg       []byte
+	from      uint16
+	broadcast bool
+}
+
+// SetLogLevel sets the level of the log of the library, written to stderr, to "debug", "info", "warn" or "error"
+func SetLogLevel(level string) error {
+	return common.SetLogLevel(level)
+}
+
+// NewECDSAParty returns the party `id` of a key of the comma-separated `parties`, of which more than `threshold` must
+// take part in a signature. It sends its messages with `transport`.
+func NewECDSAParty(id int, parties string, threshold int, transport Transport) (*ECDSAParty, error) {
+	if transport == nil {
+		return nil, fmt.Errorf("a transport is required")
+	}
+	partyID, err := toPartyID(id)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := parsePartyIDs(parties)
+	if err != nil {
+		return nil, err
+	}
+	if threshold < 1 || threshold >= len(ids) {
+		return nil, fmt.Errorf("the threshold must be at least 1 and less than the %d parties", len(ids))
+	}
+	p := &ECDSAParty{
+		party:     ecdsa.NewParty(partyID, common.DefaultLogger()),
+		transport: transport,
+		pending:   make(map[string][]pendingMsg),
+	}
+	p.party.SetSessionSender(func(sessionID string, msg []byte, isBroadcast bool, to uint16) {
+		transport.Send(sessionID, msg, isBroadcast, int(to))
+	})
+	p.setParties(ids, threshold)
+	return p, nil
+}
+
+func (p *ECDSAParty) setParties(parties []uint16, threshold int) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.parties, p.threshold = parties, threshold
+	p.party.SetParties(parties, threshold)
+}
+
+// OnMessage passes the party a message of the session `sessionID` from the party `from`
+func (p *ECDSAParty) OnMessage(sessionID string, msg []byte, from int, isBroadcast bool) {
+	fromID, err := toPartyID(from)
+	if err != nil {
+		return
+	}
+	if !strings.HasPrefix(sessionID, reshareSessionPrefix) {
+		p.party.OnSessionMsg(sessionID, msg, fromID, isBroadcast)
+		return
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.resharing != sessionID {
+		p.pending[sessionID] = append(p.pending[sessionID], pendingMsg{msg: msg, from: fromID, broadcast: isBroadcast})
+		return
+	}
+	p.party.OnMsg(msg, fromID, isBroadcast)
+}
+
+// SetShareData sets the share data of the key, as returned by KeyGen or Reshare
+func (p *ECDSAParty) SetShareData(shareData []byte) error {
+	return p.party.SetShareData(shareData)
+}
+
+// KeyGen generates a key in the session `sessionID` and returns the share data of the party, which the app must
+// store securely and pass to SetShareData when it runs the party again
+func (p *ECDSAParty) KeyGen(sessionID string, timeoutSeconds int64) ([]byte, error) {
+	ctx, cancel := timeout(timeoutSeconds)
+	defer cancel()
+	shareData, err := p.party.KeyGenSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.SetShareData(shareData); err != nil {
+		return nil, err
+	}
+	return shareData, nil
+}
+
+// Sign signs the 32-byte digest `msgHash` in the session `sessionID` and returns the ASN.1 DER signature
+func (p *ECDSAParty) Sign(sessionID string, msgHash []byte, timeoutSeconds int64) ([]byte, error) {
+	ctx, cancel := timeout(timeoutSeconds)
+	defer cancel()
+	return p.party.SignSession(ctx, sessionID, msgHash)
+}
+
+// PublicKey returns the threshold public key in its PKIX DER encoding
+func (p *ECDSAParty) PublicKey() ([]byte, error) {
+	return p.party.ThresholdPK()
+}
+
+// Reshare moves the key from the comma-separated `oldParties` with the threshold `oldThreshold` to `newParties` with
+// the threshold `newThreshold`, in the session `sessionID`; the public key stays the same. Every party of either
+// committee calls it, a party of only the new committee with no share data. It returns the share data of the party in
+// the new committee, which it then signs with, or nil if it is only in the old committee and has no share left.
+func (p *ECDSAParty) Reshare(sessionID, oldParties string, oldThreshold int, newParties string, newThreshold int, timeoutSeconds int64) ([]byte, error) {
+	oldIDs, err := parsePartyIDs(oldParties)
+	if err != nil {
+		return nil, err
+	}
+	newIDs, err := parsePartyIDs(newParties)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := timeout(timeoutSeconds)
+	defer cancel()
+
+	// the messages of resharing are not of a session: the ecdsa party receives them with OnMsg once it is initialized
+	// with both committees, and sends each of them to its recipients
+	reshareSessionID := reshareSessionPrefix + sessionID
+	p.lock.Lock()
+	if p.resharing != "" {
+		p.lock.Unlock()
+		return nil, fmt.Errorf("a resharing is already running")
+	}
+	p.party.Init(union(oldIDs, newIDs), oldThreshold, func(msg []byte, isBroadcast bool, to uint16) {
+		p.transport.Send(reshareSessionID, msg, isBroadcast, int(to))
+	})
+	p.resharing = reshareSessionID
+	for _, m := range p.pending[reshareSessionID] {
+		p.party.OnMsg(m.msg, m.from, m.broadcast)
+	}
+	delete(p.pending, reshareSessionID)
+	parties, threshold := p.parties, p.threshold
+	p.lock.Unlock()
+
+	shareData, err := p.party.Reshare(ctx, oldIDs, newIDs, newThreshold)
+
+	p.lock.Lock()
+	p.resharing = ""
+	p.lock.Unlock()
+	if err == nil && shareData != nil {
+		err = p.SetShareData(shareData)
+	}
+	if err != nil || shareData == nil {
+		p.setParties(parties, threshold)
+		return nil, err
+	}
+	p.setParties(newIDs, newThreshold)
+	return shareData, nil
+}
+
+func timeout(seconds int64) (context.Context, context.CancelFunc) {
+	if seconds <= 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), time.Duration(seconds)*time.Second)
+}
+
+func toPartyID(id int) (uint16, error) {
+	if id <= 0 || id >= math.MaxUint16 {
+		return 0, fmt.Errorf("the party id %d is not between 1 and %d", id, math.MaxUint16-1)
+	}
+	return uint16(id), nil
+}
+
+// parsePartyIDs parses a list of distinct party ids separated by commas
+func parsePartyIDs(parties string) ([]uint16, error) {
+	var ids []uint16
+	seen := make(map[uint16]struct{})
+	for _, field := range strings.Split(parties, ",") {
+		id, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return nil, fmt.Errorf("invalid party id %q", field)
+		}
+		partyID, err := toPartyID(id)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := seen[partyID]; ok {
+			return nil, fmt.Errorf("party %d is listed twice", id)
+		}
+		seen[partyID] = struct{}{}
+		ids = append(ids, partyID)
+	}
+	if len(ids) < 2 {
+		return nil, fmt.Errorf("a key needs at least 2 parties")
+	}
+	return ids, nil
+}
+
+func union(a, b []uint16) []uint16 {
+	ids := append([]uint16{}, a...)
+	for _, id := range b {
+		found := false
+		for _, other := range a {
+			found = found || other == id
+		}
+		if !found {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,37 @@
+package mobile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type nopTransport struct{}
+
+func (nopTransport) Send(string, []byte, bool, int) {}
+
+func TestParsePartyIDs(t *testing.T) {
+	ids, err := parsePartyIDs("3, 1,2")
+	assert.NoError(t, err)
+	assert.Equal(t, []uint16{3, 1, 2}, ids)
+
+	for _, parties := range []string{"", "1", "1,1", "1,x", "0,1", "1,65535"} {
+		_, err := parsePartyIDs(parties)
+		assert.Error(t, err, "%q", parties)
+	}
+	assert.Equal(t, []uint16{1, 2, 3, 4}, union([]uint16{1, 2, 3}, []uint16{2, 3, 4}))
+}
+
+func TestNewECDSAParty(t *testing.T) {
+	p, err := NewECDSAParty(1, "1,2,3", 1, nopTransport{})
+	assert.NoError(t, err)
+	_, err = p.PublicKey()
+	assert.Error(t, err, "the party has no share data yet")
+	_, err = p.Sign("session", make([]byte, 32), 1)
+	assert.Error(t, err)
+
+	_, err = NewECDSAParty(1, "1,2,3", 3, nopTransport{})
+	assert.Error(t, err, "the threshold must be less than the parties")
+	_, err = NewECDSAParty(1, "1,2,3", 1, nil)
+	assert.Error(t, err)
+}
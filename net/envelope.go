@@ -0,0 +1,281 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package net
+
+import (
+	"bytes"
+	"context"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/hkdf"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+const (
+	envelopeSignatureTag = "mpc-lib envelope signature v1"
+	envelopeKDFInfo      = "mpc-lib envelope encryption v1"
+)
+
+// Implements Transport
+var _ Transport = (*EnvelopeTransport)(nil)
+
+type (
+	// EnvelopeKey is the static key pair of a party: it signs the messages that the party sends and decrypts the
+	// point-to-point messages sent to it
+	EnvelopeKey struct {
+		Signing    ed25519.PrivateKey
+		Encryption *ecdh.PrivateKey
+	}
+
+	// EnvelopePublicKey is the public half of an EnvelopeKey, which the other parties must know in advance
+	EnvelopePublicKey struct {
+		Signing    ed25519.PublicKey
+		Encryption *ecdh.PublicKey
+	}
+
+	// EnvelopeTransport wraps another Transport so that every message is signed by its sender and every
+	// point-to-point message is encrypted to its recipient. Messages that fail to verify or decrypt are dropped.
+	EnvelopeTransport struct {
+		inner Transport
+		self  *tss.PartyID
+		key   *EnvelopeKey
+		peers map[string]*EnvelopePublicKey // by party id
+		in    chan *Message
+
+		closeOnce sync.Once
+		done      chan struct{}
+	}
+)
+
+// GenerateEnvelopeKey generates a new static key pair for a party
+func GenerateEnvelopeKey() (*EnvelopeKey, error) {
+	_, signing, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	encryption, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &EnvelopeKey{Signing: signing, Encryption: encryption}, nil
+}
+
+func (k *EnvelopeKey) Public() *EnvelopePublicKey {
+	return &EnvelopePublicKey{
+		Signing:    k.Signing.Public().(ed25519.PublicKey),
+		Encryption: k.Encryption.PublicKey(),
+	}
+}
+
+func (k *EnvelopePublicKey) ValidateBasic() bool {
+	return k != nil && len(k.Signing) == ed25519.PublicKeySize && k.Encryption != nil &&
+		k.Encryption.Curve() == ecdh.X25519()
+}
+
+// SealMessage signs a wire message from the party `from` and, if `to` is not nil, encrypts it to the party `to`
+// whose public key is `toKey`. A nil `to` seals a broadcast, which is signed but not encrypted.
+func SealMessage(key *EnvelopeKey, from, to *tss.PartyID, toKey *EnvelopePublicKey, wireBytes []byte) ([]byte, error) {
+	if key == nil || from == nil {
+		return nil, errors.New("SealMessage() received nil value(s)")
+	}
+	env := &Envelope{From: from.Key, Payload: wireBytes}
+	if to != nil {
+		if !toKey.ValidateBasic() {
+			return nil, fmt.Errorf("invalid public key of party %s", to.Id)
+		}
+		eph, err := ecdh.X25519().GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		shared, err := eph.ECDH(toKey.Encryption)
+		if err != nil {
+			return nil, err
+		}
+		env.To = to.Key
+		env.EphemeralKey = eph.PublicKey().Bytes()
+		aead, err := envelopeAEAD(shared, env.EphemeralKey, toKey.Encryption.Bytes())
+		if err != nil {
+			return nil, err
+		}
+		// every message is encrypted under a fresh key, so a fixed nonce is never reused
+		nonce := make([]byte, aead.NonceSize())
+		env.Payload = aead.Seal(nil, nonce, wireBytes, envelopeAD(env.From, env.To))
+	}
+	env.Signature = ed25519.Sign(key.Signing, envelopeSigningBytes(env))
+	return proto.Marshal(env)
+}
+
+// OpenMessage verifies that a sealed message was signed by the party `from`, whose public key is `fromKey`, and
+// returns the wire message, decrypted with `key` if it was sent point-to-point to the party `self`.
+func OpenMessage(key *EnvelopeKey, self, from *tss.PartyID, fromKey *EnvelopePublicKey, sealed []byte) (wireBytes []byte, isBroadcast bool, err error) {
+	if key == nil || self == nil || from == nil || !fromKey.ValidateBasic() {
+		return nil, false, errors.New("OpenMessage() received nil or invalid value(s)")
+	}
+	env := new(Envelope)
+	if err = proto.Unmarshal(sealed, env); err != nil {
+		return nil, false, err
+	}
+	if !bytes.Equal(env.GetFrom(), from.Key) {
+		return nil, false, fmt.Errorf("the message was not sealed by party %s", from.Id)
+	}
+	if !ed25519.Verify(fromKey.Signing, envelopeSigningBytes(env), env.GetSignature()) {
+		return nil, false, fmt.Errorf("invalid signature of party %s", from.Id)
+	}
+	if len(env.GetTo()) == 0 {
+		if len(env.GetEphemeralKey()) != 0 {
+			return nil, false, errors.New("a broadcast must not be encrypted")
+		}
+		return env.GetPayload(), true, nil
+	}
+	if !bytes.Equal(env.GetTo(), self.Key) {
+		return nil, false, errors.New("the message was sealed for another party")
+	}
+	eph, err := ecdh.X25519().NewPublicKey(env.GetEphemeralKey())
+	if err != nil {
+		return nil, false, err
+	}
+	shared, err := key.Encryption.ECDH(eph)
+	if err != nil {
+		return nil, false, err
+	}
+	aead, err := envelopeAEAD(shared, env.GetEphemeralKey(), key.Encryption.PublicKey().Bytes())
+	if err != nil {
+		return nil, false, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if wireBytes, err = aead.Open(nil, nonce, env.GetPayload(), envelopeAD(env.GetFrom(), env.GetTo())); err != nil {
+		return nil, false, errors.New("could not decrypt the message")
+	}
+	return wireBytes, false, nil
+}
+
+// NewEnvelopeTransport seals the messages sent through `inner` with `key` and opens the messages received from it
+// with the public keys of the peers, given by party id
+func NewEnvelopeTransport(inner Transport, self *tss.PartyID, key *EnvelopeKey, peers map[string]*EnvelopePublicKey) (*EnvelopeTransport, error) {
+	if inner == nil || self == nil || key == nil {
+		return nil, errors.New("NewEnvelopeTransport() received nil value(s)")
+	}
+	for id, pk := range peers {
+		if !pk.ValidateBasic() {
+			return nil, fmt.Errorf("invalid public key of peer %s", id)
+		}
+	}
+	t := &EnvelopeTransport{
+		inner: inner,
+		self:  self,
+		key:   key,
+		peers: peers,
+		in:    make(chan *Message, receiveBufferSize),
+		done:  make(chan struct{}),
+	}
+	go t.open()
+	return t, nil
+}
+
+func (t *EnvelopeTransport) Send(ctx context.Context, to *tss.PartyID, wireBytes []byte) error {
+	if to == nil {
+		return errors.New("Send() requires a recipient")
+	}
+	toKey, ok := t.peers[to.Id]
+	if !ok {
+		return fmt.Errorf("unknown peer %s", to.Id)
+	}
+	sealed, err := SealMessage(t.key, t.self, to, toKey, wireBytes)
+	if err != nil {
+		return err
+	}
+	return t.inner.Send(ctx, to, sealed)
+}
+
+func (t *EnvelopeTransport) Broadcast(ctx context.Context, wireBytes []byte) error {
+	sealed, err := SealMessage(t.key, t.self, nil, nil, wireBytes)
+	if err != nil {
+		return err
+	}
+	return t.inner.Broadcast(ctx, sealed)
+}
+
+func (t *EnvelopeTransport) Receive() <-chan *Message {
+	return t.in
+}
+
+func (t *EnvelopeTransport) Close() error {
+	t.closeOnce.Do(func() {
+		close(t.done)
+	})
+	return t.inner.Close()
+}
+
+// ----- //
+
+// open passes on the messages of the inner transport that open successfully
+func (t *EnvelopeTransport) open() {
+	defer close(t.in)
+	for {
+		select {
+		case <-t.done:
+			return
+		case msg, ok := <-t.inner.Receive():
+			if !ok {
+				return
+			}
+			if msg.From == nil {
+				continue
+			}
+			fromKey, ok := t.peers[msg.From.Id]
+			if !ok {
+				common.DefaultLogger().Warnf("dropping a message from unknown peer %s", msg.From.Id)
+				continue
+			}
+			wireBytes, isBroadcast, err := OpenMessage(t.key, t.self, msg.From, fromKey, msg.WireBytes)
+			if err != nil {
+				common.DefaultLogger().Warnf("dropping a message from peer %s: %v", msg.From.Id, err)
+				continue
+			}
+			if isBroadcast != msg.IsBroadcast {
+				common.DefaultLogger().Warnf("dropping a message from peer %s: the envelope does not match the routing", msg.From.Id)
+				continue
+			}
+			select {
+			case t.in <- &Message{From: msg.From, IsBroadcast: isBroadcast, WireBytes: wireBytes}:
+			case <-t.done:
+				return
+			}
+		}
+	}
+}
+
+// envelopeAEAD derives the one-time key of a point-to-point message, in the manner of HPKE's base mode
+func envelopeAEAD(shared, ephemeralKey, recipientKey []byte) (cipher.AEAD, error) {
+	salt := append(append([]byte{}, ephemeralKey...), recipientKey...)
+	key := make([]byte, chacha20poly1305.KeySize)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, shared, salt, []byte(envelopeKDFInfo)), key); err != nil {
+		return nil, err
+	}
+	return chacha20poly1305.New(key)
+}
+
+// envelopeAD binds the sender and recipient to the ciphertext
+func envelopeAD(from, to []byte) []byte {
+	return common.SHA512_256([]byte(envelopeKDFInfo), from, to)
+}
+
+func envelopeSigningBytes(env *Envelope) []byte {
+	return common.SHA512_256([]byte(envelopeSignatureTag), env.GetFrom(), env.GetTo(), env.GetEphemeralKey(), env.GetPayload())
+}
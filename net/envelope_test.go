@@ -0,0 +1,100 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package net_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/kisdex/mpc-lib/net"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+func TestSealOpenMessage(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(testParticipants)
+	keys := make([]*EnvelopeKey, len(pIDs))
+	for i := range keys {
+		var err error
+		keys[i], err = GenerateEnvelopeKey()
+		assert.NoError(t, err)
+	}
+	wire := []byte("wire message")
+
+	sealed, err := SealMessage(keys[0], pIDs[0], pIDs[1], keys[1].Public(), wire)
+	assert.NoError(t, err)
+	assert.False(t, bytes.Contains(sealed, wire), "a point-to-point message must be encrypted")
+	opened, isBroadcast, err := OpenMessage(keys[1], pIDs[1], pIDs[0], keys[0].Public(), sealed)
+	assert.NoError(t, err)
+	assert.False(t, isBroadcast)
+	assert.Equal(t, wire, opened)
+
+	_, _, err = OpenMessage(keys[2], pIDs[2], pIDs[0], keys[0].Public(), sealed)
+	assert.Error(t, err, "only the recipient may open a point-to-point message")
+	_, _, err = OpenMessage(keys[1], pIDs[1], pIDs[2], keys[2].Public(), sealed)
+	assert.Error(t, err, "the message must not open as sent by another party")
+	tampered := append([]byte{}, sealed...)
+	tampered[len(tampered)-1] ^= 1
+	_, _, err = OpenMessage(keys[1], pIDs[1], pIDs[0], keys[0].Public(), tampered)
+	assert.Error(t, err, "a tampered message must not open")
+
+	sealed, err = SealMessage(keys[0], pIDs[0], nil, nil, wire)
+	assert.NoError(t, err)
+	for _, i := range []int{1, 2} {
+		opened, isBroadcast, err = OpenMessage(keys[i], pIDs[i], pIDs[0], keys[0].Public(), sealed)
+		assert.NoError(t, err)
+		assert.True(t, isBroadcast)
+		assert.Equal(t, wire, opened)
+	}
+	_, _, err = OpenMessage(keys[1], pIDs[1], pIDs[0], keys[2].Public(), sealed)
+	assert.Error(t, err, "a broadcast signed with another key must not open")
+}
+
+func TestEnvelopeTransport(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(testParticipants)
+	grpcTransports := newTestTransports(t, pIDs)
+	keys := make([]*EnvelopeKey, len(pIDs))
+	pubs := make(map[string]*EnvelopePublicKey, len(pIDs))
+	for i, pID := range pIDs {
+		var err error
+		keys[i], err = GenerateEnvelopeKey()
+		assert.NoError(t, err)
+		pubs[pID.Id] = keys[i].Public()
+	}
+	transports := make([]*EnvelopeTransport, len(pIDs))
+	for i, pID := range pIDs {
+		var err error
+		transports[i], err = NewEnvelopeTransport(grpcTransports[i], pID, keys[i], pubs)
+		assert.NoError(t, err)
+	}
+	defer func() {
+		for _, tr := range transports {
+			_ = tr.Close()
+		}
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// a message sent around the envelope layer is dropped
+	assert.NoError(t, grpcTransports[2].Send(ctx, pIDs[1], []byte("forged")))
+	assert.NoError(t, transports[0].Send(ctx, pIDs[1], []byte("p2p")))
+	msg := <-transports[1].Receive()
+	assert.Equal(t, pIDs[0].Id, msg.From.Id)
+	assert.False(t, msg.IsBroadcast)
+	assert.Equal(t, []byte("p2p"), msg.WireBytes)
+
+	assert.NoError(t, transports[2].Broadcast(ctx, []byte("bcast")))
+	for _, i := range []int{0, 1} {
+		msg := <-transports[i].Receive()
+		assert.Equal(t, pIDs[2].Id, msg.From.Id)
+		assert.True(t, msg.IsBroadcast)
+		assert.Equal(t, []byte("bcast"), msg.WireBytes)
+	}
+}
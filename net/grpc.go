@@ -0,0 +1,252 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package net
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	gonet "net"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+const (
+	transportServiceName   = "binance.tsslib.net.Transport"
+	transportDeliverMethod = "/" + transportServiceName + "/Deliver"
+
+	// the number of received messages that are buffered before the peers are made to wait
+	receiveBufferSize = 1000
+)
+
+// Implements Transport
+var _ Transport = (*GRPCTransport)(nil)
+
+type (
+	// GRPCConfig configures a GRPCTransport. The parties authenticate each other with mutual TLS, and every party's
+	// certificate must carry its PartyID.Id as the subject common name.
+	GRPCConfig struct {
+		// Self is the local party
+		Self *tss.PartyID
+		// Peers are the other parties. Their ids should be the sorted ids that the tss parameters were built with,
+		// as the sender of a received message is reported with the id given here.
+		Peers []GRPCPeer
+		// ListenAddr is the address on which the peers connect, e.g. ":9000". It is ignored if Listener is set.
+		ListenAddr string
+		Listener   gonet.Listener
+		// TLS holds the local certificate, the CA pool that verifies the peers as servers (RootCAs) and
+		// the CA pool that verifies the peers as clients (ClientCAs)
+		TLS *tls.Config
+	}
+
+	GRPCPeer struct {
+		ID *tss.PartyID
+		// Addr is the host:port on which the peer listens
+		Addr string
+	}
+
+	// GRPCTransport is a Transport that delivers every message with a unary gRPC call over mutual TLS
+	GRPCTransport struct {
+		self     *tss.PartyID
+		server   *grpc.Server
+		listener gonet.Listener
+		peers    map[string]*grpcPeer // by party id
+		in       chan *Message
+
+		closeOnce sync.Once
+		done      chan struct{}
+	}
+
+	grpcPeer struct {
+		id   *tss.PartyID
+		conn *grpc.ClientConn
+	}
+
+	// transportServer is the handler type of the Transport service
+	transportServer interface {
+		deliver(ctx context.Context, msg *TransportMessage) (*TransportAck, error)
+	}
+)
+
+var transportServiceDesc = grpc.ServiceDesc{
+	ServiceName: transportServiceName,
+	HandlerType: (*transportServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Deliver",
+			Handler:    deliverHandler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "protob/net-transport.proto",
+}
+
+// NewGRPCTransport starts serving the peers and prepares the connections to them; the connections are established
+// on first use.
+func NewGRPCTransport(cfg GRPCConfig) (*GRPCTransport, error) {
+	if cfg.Self == nil || !cfg.Self.ValidateBasic() {
+		return nil, errors.New("NewGRPCTransport() requires a valid local party id")
+	}
+	if cfg.TLS == nil {
+		return nil, errors.New("NewGRPCTransport() requires a TLS config")
+	}
+	t := &GRPCTransport{
+		self:  cfg.Self,
+		peers: make(map[string]*grpcPeer, len(cfg.Peers)),
+		in:    make(chan *Message, receiveBufferSize),
+		done:  make(chan struct{}),
+	}
+	for _, p := range cfg.Peers {
+		if p.ID == nil || p.ID.Id == cfg.Self.Id {
+			continue
+		}
+		if _, ok := t.peers[p.ID.Id]; ok {
+			t.closeConns()
+			return nil, fmt.Errorf("duplicate peer %s", p.ID.Id)
+		}
+		clientTLS := cfg.TLS.Clone()
+		clientTLS.VerifyConnection = verifyCommonName(p.ID.Id)
+		conn, err := grpc.NewClient(p.Addr, grpc.WithTransportCredentials(credentials.NewTLS(clientTLS)))
+		if err != nil {
+			t.closeConns()
+			return nil, fmt.Errorf("could not create the client of peer %s: %w", p.ID.Id, err)
+		}
+		t.peers[p.ID.Id] = &grpcPeer{id: p.ID, conn: conn}
+	}
+
+	t.listener = cfg.Listener
+	if t.listener == nil {
+		lis, err := gonet.Listen("tcp", cfg.ListenAddr)
+		if err != nil {
+			t.closeConns()
+			return nil, err
+		}
+		t.listener = lis
+	}
+	serverTLS := cfg.TLS.Clone()
+	serverTLS.ClientAuth = tls.RequireAndVerifyClientCert
+	t.server = grpc.NewServer(grpc.Creds(credentials.NewTLS(serverTLS)))
+	t.server.RegisterService(&transportServiceDesc, t)
+	go func() {
+		_ = t.server.Serve(t.listener)
+	}()
+	return t, nil
+}
+
+// Addr returns the address on which the transport accepts the connections of the peers
+func (t *GRPCTransport) Addr() gonet.Addr {
+	return t.listener.Addr()
+}
+
+func (t *GRPCTransport) Send(ctx context.Context, to *tss.PartyID, wireBytes []byte) error {
+	if to == nil {
+		return errors.New("Send() requires a recipient")
+	}
+	p, ok := t.peers[to.Id]
+	if !ok {
+		return fmt.Errorf("unknown peer %s", to.Id)
+	}
+	return t.deliverTo(ctx, p, &TransportMessage{WireBytes: wireBytes})
+}
+
+func (t *GRPCTransport) Broadcast(ctx context.Context, wireBytes []byte) error {
+	msg := &TransportMessage{IsBroadcast: true, WireBytes: wireBytes}
+	for _, p := range t.peers {
+		if err := t.deliverTo(ctx, p, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *GRPCTransport) Receive() <-chan *Message {
+	return t.in
+}
+
+func (t *GRPCTransport) Close() error {
+	t.closeOnce.Do(func() {
+		close(t.done)
+		t.server.Stop()
+		t.closeConns()
+	})
+	return nil
+}
+
+// ----- //
+
+func (t *GRPCTransport) deliverTo(ctx context.Context, p *grpcPeer, msg *TransportMessage) error {
+	if err := p.conn.Invoke(ctx, transportDeliverMethod, msg, new(TransportAck)); err != nil {
+		return fmt.Errorf("could not deliver the message to peer %s: %w", p.id.Id, err)
+	}
+	return nil
+}
+
+// deliver receives a message from a peer, which is identified by its verified client certificate
+func (t *GRPCTransport) deliver(ctx context.Context, msg *TransportMessage) (*TransportAck, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "no peer information")
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "the peer did not present a verified certificate")
+	}
+	from, ok := t.peers[tlsInfo.State.VerifiedChains[0][0].Subject.CommonName]
+	if !ok {
+		return nil, status.Error(codes.PermissionDenied, "the peer is not a party of this transport")
+	}
+	select {
+	case t.in <- &Message{From: from.id, IsBroadcast: msg.GetIsBroadcast(), WireBytes: msg.GetWireBytes()}:
+		return &TransportAck{}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-t.done:
+		return nil, status.Error(codes.Unavailable, "the transport is closed")
+	}
+}
+
+func (t *GRPCTransport) closeConns() {
+	for _, p := range t.peers {
+		_ = p.conn.Close()
+	}
+}
+
+// verifyCommonName checks that a server is the expected party, on top of the usual certificate verification
+func verifyCommonName(id string) func(tls.ConnectionState) error {
+	return func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 || cs.PeerCertificates[0].Subject.CommonName != id {
+			return fmt.Errorf("the server is not party %s", id)
+		}
+		return nil
+	}
+}
+
+func deliverHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransportMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(transportServer).deliver(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: transportDeliverMethod,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(transportServer).deliver(ctx, req.(*TransportMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
@@ -0,0 +1,170 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package net_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	gonet "net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/eddsa/keygen"
+	. "github.com/kisdex/mpc-lib/net"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+const (
+	testParticipants = 3
+	testThreshold    = 1
+)
+
+// newTestTransports connects every party to all of the others with certificates issued by a throwaway CA
+func newTestTransports(t *testing.T, pIDs tss.SortedPartyIDs) []*GRPCTransport {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	caTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	assert.NoError(t, err)
+	caCert, err := x509.ParseCertificate(caDER)
+	assert.NoError(t, err)
+	pool := x509.NewCertPool()
+	pool.AddCert(caCert)
+
+	listeners := make([]gonet.Listener, len(pIDs))
+	peers := make([]GRPCPeer, len(pIDs))
+	for i, pID := range pIDs {
+		listeners[i], err = gonet.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		peers[i] = GRPCPeer{ID: pID, Addr: listeners[i].Addr().String()}
+	}
+
+	transports := make([]*GRPCTransport, len(pIDs))
+	for i, pID := range pIDs {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		assert.NoError(t, err)
+		tmpl := &x509.Certificate{
+			SerialNumber: big.NewInt(int64(i + 2)),
+			Subject:      pkix.Name{CommonName: pID.Id},
+			IPAddresses:  []gonet.IP{gonet.ParseIP("127.0.0.1")},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, caCert, &key.PublicKey, caKey)
+		assert.NoError(t, err)
+		tlsConfig := &tls.Config{
+			Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+			RootCAs:      pool,
+			ClientCAs:    pool,
+			MinVersion:   tls.VersionTLS13,
+		}
+		transports[i], err = NewGRPCTransport(GRPCConfig{
+			Self:     pID,
+			Peers:    peers,
+			Listener: listeners[i],
+			TLS:      tlsConfig,
+		})
+		assert.NoError(t, err)
+	}
+	return transports
+}
+
+func TestGRPCSendAndBroadcast(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(testParticipants)
+	transports := newTestTransports(t, pIDs)
+	defer func() {
+		for _, tr := range transports {
+			_ = tr.Close()
+		}
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	assert.NoError(t, transports[0].Send(ctx, pIDs[1], []byte("p2p")))
+	msg := <-transports[1].Receive()
+	assert.Equal(t, pIDs[0].Id, msg.From.Id, "the sender must be identified from its certificate")
+	assert.False(t, msg.IsBroadcast)
+	assert.Equal(t, []byte("p2p"), msg.WireBytes)
+
+	assert.NoError(t, transports[2].Broadcast(ctx, []byte("bcast")))
+	for _, i := range []int{0, 1} {
+		msg := <-transports[i].Receive()
+		assert.Equal(t, pIDs[2].Id, msg.From.Id)
+		assert.True(t, msg.IsBroadcast)
+		assert.Equal(t, []byte("bcast"), msg.WireBytes)
+	}
+
+	assert.Error(t, transports[0].Send(ctx, tss.NewPartyID("x", "x", big.NewInt(99)), []byte("p2p")), "unknown peers must be rejected")
+}
+
+func TestGRPCKeygen(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(testParticipants)
+	transports := newTestTransports(t, pIDs)
+	defer func() {
+		for _, tr := range transports {
+			_ = tr.Close()
+		}
+	}()
+	p2pCtx := tss.NewPeerContext(pIDs)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	saves := make([]*keygen.LocalPartySaveData, len(pIDs))
+	wg := sync.WaitGroup{}
+	for i := range pIDs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			params := tss.NewParameters(tss.Edwards(), p2pCtx, pIDs[i], len(pIDs), testThreshold)
+			out := make(chan tss.Message, len(pIDs)*4)
+			end := make(chan *keygen.LocalPartySaveData, 1)
+			P := keygen.NewLocalParty(params, out, end)
+			if err := P.Start(); err != nil {
+				t.Errorf("party %d could not start: %v", i, err)
+				return
+			}
+			routeCtx, stop := context.WithCancel(ctx)
+			defer stop()
+			errCh := make(chan error, 1)
+			go func() {
+				errCh <- Route(routeCtx, transports[i], P, out)
+			}()
+			select {
+			case saves[i] = <-end:
+			case err := <-errCh:
+				t.Errorf("party %d failed: %v", i, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for _, save := range saves {
+		if !assert.NotNil(t, save) {
+			return
+		}
+		assert.True(t, save.EDDSAPub.Equals(saves[0].EDDSAPub), "all parties must output the same public key")
+	}
+}
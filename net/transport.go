@@ -0,0 +1,75 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package net carries the messages of the tss protocols between parties.
+package net
+
+import (
+	"context"
+	"errors"
+
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+type (
+	// Transport moves wire messages between the local party and its peers.
+	// Implementations must authenticate their peers: the routing of a received message trusts the sender that
+	// the transport reports.
+	Transport interface {
+		// Send delivers a point-to-point message to the party `to`
+		Send(ctx context.Context, to *tss.PartyID, wireBytes []byte) error
+		// Broadcast delivers a message to every other party
+		Broadcast(ctx context.Context, wireBytes []byte) error
+		// Receive returns the messages received from the other parties
+		Receive() <-chan *Message
+		// Close releases the connections and stops receiving messages
+		Close() error
+	}
+
+	// Message is a wire message received by a Transport
+	Message struct {
+		From        *tss.PartyID
+		IsBroadcast bool
+		WireBytes   []byte
+	}
+)
+
+// Route connects a party to a transport until ctx is done: the messages that the party sends on `out` are sent
+// through the transport, and the messages received by the transport are passed to the party.
+// It returns the first error reported by the party or the transport; the caller is expected to cancel ctx once
+// the party has delivered its result on its end channel.
+func Route(ctx context.Context, transport Transport, party tss.Party, out <-chan tss.Message) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg := <-out:
+			wireBytes, routing, err := msg.WireBytes()
+			if err != nil {
+				return err
+			}
+			if routing.IsBroadcast || routing.To == nil {
+				err = transport.Broadcast(ctx, wireBytes)
+			} else {
+				for _, to := range routing.To {
+					if err = transport.Send(ctx, to, wireBytes); err != nil {
+						break
+					}
+				}
+			}
+			if err != nil {
+				return err
+			}
+		case msg, ok := <-transport.Receive():
+			if !ok {
+				return errors.New("the transport was closed")
+			}
+			if _, err := party.UpdateFromBytes(msg.WireBytes, msg.From, msg.IsBroadcast); err != nil {
+				return err
+			}
+		}
+	}
+}
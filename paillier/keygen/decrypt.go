@@ -0,0 +1,47 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto/paillier"
+)
+
+// PartialDecrypt returns the decryption share c^Si mod N^2 of the ciphertext `c` under the shared key
+func (save LocalPartySaveData) PartialDecrypt(c *big.Int) (*big.Int, error) {
+	if !save.PaillierPK.ValidateCiphertexts(c) {
+		return nil, errors.New("the ciphertext is not in Z*_{N^2}")
+	}
+	return common.ModInt(save.PaillierPK.NSquare()).SecretExp(c, save.Si, nil), nil
+}
+
+// Combine returns the plaintext of a ciphertext from the decryption shares of all of the parties. Their product is
+// c^(beta*phi(N)) = 1 + m*Theta*N mod N^2, as the randomness of the ciphertext vanishes in the exponent phi(N).
+func Combine(pk *paillier.PublicKey, theta *big.Int, shares []*big.Int) (*big.Int, error) {
+	if len(shares) == 0 {
+		return nil, errors.New("no decryption shares to combine")
+	}
+	modN2 := common.ModInt(pk.NSquare())
+	u := big.NewInt(1)
+	for _, share := range shares {
+		if !pk.ValidateCiphertexts(share) {
+			return nil, errors.New("a decryption share is not in Z*_{N^2}")
+		}
+		u = modN2.Mul(u, share)
+	}
+	if new(big.Int).Mod(u, pk.N).Cmp(big.NewInt(1)) != 0 {
+		return nil, errors.New("the decryption shares do not combine to a plaintext; a share is missing or invalid")
+	}
+	thetaInv := new(big.Int).ModInverse(theta, pk.N)
+	if thetaInv == nil {
+		return nil, errors.New("theta must be invertible mod N")
+	}
+	return common.ModInt(pk.N).Mul(paillier.L(u, pk.N), thetaInv), nil
+}
@@ -0,0 +1,61 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/kisdex/mpc-lib/crypto/paillier"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+func (round *finalization) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started"))
+	}
+	round.number = 8
+	round.started = true
+	round.resetOK()
+	for j := range round.ok {
+		round.ok[j] = true
+	}
+
+	N := round.temp.bigN
+	theta := new(big.Int)
+	for j, msg := range round.temp.kgRound7Messages {
+		thetaShare := msg.Content().(*KGRound7Message).UnmarshalThetaShare()
+		if thetaShare.Cmp(N) >= 0 {
+			return round.WrapError(errors.New("the share of theta must be less than N"), round.Parties().IDs()[j])
+		}
+		theta.Add(theta, thetaShare)
+	}
+	theta.Mod(theta, N)
+
+	round.save.PaillierPK = paillier.NewPublicKey(N)
+	round.save.Theta = theta
+	if err := round.save.Validate(); err != nil {
+		return round.WrapError(err)
+	}
+
+	round.end <- round.save
+	return nil
+}
+
+func (round *finalization) CanAccept(msg tss.ParsedMessage) bool {
+	// not expecting any incoming messages in this round
+	return false
+}
+
+func (round *finalization) Update() (bool, *tss.Error) {
+	// not expecting any incoming messages in this round
+	return false, nil
+}
+
+func (round *finalization) NextRound() tss.Round {
+	return nil // finished!
+}
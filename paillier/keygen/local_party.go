@@ -0,0 +1,185 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/kisdex/mpc-lib/crypto/paillier"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+// Implements Party
+// Implements Stringer
+var _ tss.Party = (*LocalParty)(nil)
+var _ fmt.Stringer = (*LocalParty)(nil)
+
+type (
+	LocalParty struct {
+		*tss.BaseParty
+		params *tss.Parameters
+
+		temp localTempData
+		data LocalPartySaveData
+
+		// outbound messaging
+		out chan<- tss.Message
+		end chan<- *LocalPartySaveData
+	}
+
+	localMessageStore struct {
+		kgRound1Messages,
+		kgRound2Messages,
+		kgRound3Messages,
+		kgRound4Messages,
+		kgRound5Messages,
+		kgRound6Messages,
+		kgRound7Messages []tss.ParsedMessage
+	}
+
+	localTempData struct {
+		localMessageStore
+
+		// temp data (thrown away after keygen)
+		ssid        []byte
+		ssidNonce   *big.Int
+		modulusBits int
+
+		// the Paillier keys of the parties for their MtA, which are not related to the generated key
+		paillierSK  *paillier.PrivateKey
+		paillierPKs []*paillier.PublicKey
+
+		// the candidates of the current attempt: the shares of p and q, the sums of the masks of the MtA of the
+		// products p_j*q_i, and the moduli that are left for the biprimality test
+		attempt    uint32
+		p, q       []*big.Int
+		masks      []*big.Int
+		moduli     []*big.Int
+		candidates []int
+
+		// the generated modulus N, the shares of its factors, and the share of the mask of phi(N)
+		bigN        *big.Int
+		pi, qi      *big.Int
+		beta        *big.Int
+		betaMaskSum *big.Int
+	}
+)
+
+// Exported, used in `tss` client
+// NewLocalParty returns a party of the distributed generation of a Paillier key of Boneh and Franklin (1997), in which
+// the parties jointly sample the primes p and q of the modulus N = p*q as sums of their shares, so that none of them
+// knows the factorization of N. All of the parties are needed to decrypt, see PartialDecrypt and Combine.
+// The protocol is secure against semi-honest parties. Each party uses a Paillier key of its own for the MtA of the
+// protocol, which is generated unless `optionalPaillierSK` is given; it must have at least
+// AuxModulusBits(modulus bits) bits. Many candidates are tried for p and q, so the keygen takes many rounds.
+func NewLocalParty(
+	params *tss.Parameters,
+	out chan<- tss.Message,
+	end chan<- *LocalPartySaveData,
+	optionalPaillierSK ...*paillier.PrivateKey,
+) tss.Party {
+	partyCount := params.PartyCount()
+	p := &LocalParty{
+		BaseParty: new(tss.BaseParty),
+		params:    params,
+		temp:      localTempData{},
+		data:      NewLocalPartySaveData(partyCount),
+		out:       out,
+		end:       end,
+	}
+	if 0 < len(optionalPaillierSK) {
+		if 1 < len(optionalPaillierSK) {
+			panic(errors.New("paillier/keygen.NewLocalParty expected 0 or 1 item in `optionalPaillierSK`"))
+		}
+		p.temp.paillierSK = optionalPaillierSK[0]
+	}
+	// msgs init
+	p.temp.kgRound1Messages = make([]tss.ParsedMessage, partyCount)
+	p.temp.kgRound2Messages = make([]tss.ParsedMessage, partyCount)
+	p.temp.kgRound3Messages = make([]tss.ParsedMessage, partyCount)
+	p.temp.kgRound4Messages = make([]tss.ParsedMessage, partyCount)
+	p.temp.kgRound5Messages = make([]tss.ParsedMessage, partyCount)
+	p.temp.kgRound6Messages = make([]tss.ParsedMessage, partyCount)
+	p.temp.kgRound7Messages = make([]tss.ParsedMessage, partyCount)
+	// temp data init
+	p.temp.modulusBits = params.SecurityLevel().ModulusBits()
+	p.temp.paillierPKs = make([]*paillier.PublicKey, partyCount)
+	return p
+}
+
+func (p *LocalParty) FirstRound() tss.Round {
+	return newRound1(p.params, &p.data, &p.temp, p.out, p.end)
+}
+
+func (p *LocalParty) Start() *tss.Error {
+	return tss.BaseStart(p, TaskName)
+}
+
+func (p *LocalParty) Update(msg tss.ParsedMessage) (ok bool, err *tss.Error) {
+	return tss.BaseUpdate(p, msg, TaskName)
+}
+
+func (p *LocalParty) UpdateFromBytes(wireBytes []byte, from *tss.PartyID, isBroadcast bool) (bool, *tss.Error) {
+	msg, err := messageTypes.ParseWireMessage(wireBytes, from, isBroadcast)
+	if err != nil {
+		return false, p.WrapError(err)
+	}
+	return p.Update(msg)
+}
+
+func (p *LocalParty) ValidateMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
+	if ok, err := p.BaseParty.ValidateMessage(msg); !ok || err != nil {
+		return ok, err
+	}
+	// check that the message's "from index" will fit into the array
+	if maxFromIdx := p.params.PartyCount() - 1; maxFromIdx < msg.GetFrom().Index {
+		return false, p.WrapError(fmt.Errorf("received msg with a sender index too great (%d <= %d)",
+			p.params.PartyCount(), msg.GetFrom().Index), msg.GetFrom())
+	}
+	return true, nil
+}
+
+func (p *LocalParty) StoreMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
+	// ValidateBasic is cheap; double-check the message here in case the public StoreMessage was called externally
+	if ok, err := p.ValidateMessage(msg); !ok || err != nil {
+		return ok, err
+	}
+	fromPIdx := msg.GetFrom().Index
+
+	// switch/case is necessary to store any messages beyond current round
+	// this does not handle message replays. we expect the caller to apply replay and spoofing protection.
+	switch msg.Content().(type) {
+	case *KGRound1Message:
+		p.temp.kgRound1Messages[fromPIdx] = msg
+	case *KGRound2Message:
+		p.temp.kgRound2Messages[fromPIdx] = msg
+	case *KGRound3Message:
+		p.temp.kgRound3Messages[fromPIdx] = msg
+	case *KGRound4Message:
+		p.temp.kgRound4Messages[fromPIdx] = msg
+	case *KGRound5Message:
+		p.temp.kgRound5Messages[fromPIdx] = msg
+	case *KGRound6Message:
+		p.temp.kgRound6Messages[fromPIdx] = msg
+	case *KGRound7Message:
+		p.temp.kgRound7Messages[fromPIdx] = msg
+	default: // unrecognised message, just ignore!
+		p.params.Logger().Warnf("unrecognised message ignored: %v", msg)
+		return false, nil
+	}
+	return true, nil
+}
+
+func (p *LocalParty) PartyID() *tss.PartyID {
+	return p.params.PartyID()
+}
+
+func (p *LocalParty) String() string {
+	return fmt.Sprintf("id: %s, %s", p.PartyID(), p.BaseParty.String())
+}
@@ -0,0 +1,109 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto/paillier"
+	"github.com/kisdex/mpc-lib/test"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+const (
+	// a short modulus, so that the test does not try the many candidates of a real one
+	testModulusBits  = 96
+	testParticipants = 3
+)
+
+func setUp(level string) {
+	if err := common.SetLogLevel(level); err != nil {
+		panic(err)
+	}
+}
+
+// testPaillierSK returns a Paillier key of random primes for the MtA, which is quicker to generate than one of safe
+// primes
+func testPaillierSK(t *testing.T) *paillier.PrivateKey {
+	P, err := rand.Prime(rand.Reader, 512)
+	assert.NoError(t, err)
+	Q, err := rand.Prime(rand.Reader, 512)
+	assert.NoError(t, err)
+	PMinus1, QMinus1 := new(big.Int).Sub(P, big.NewInt(1)), new(big.Int).Sub(Q, big.NewInt(1))
+	phiN := new(big.Int).Mul(PMinus1, QMinus1)
+	lambdaN := new(big.Int).Div(phiN, new(big.Int).GCD(nil, nil, PMinus1, QMinus1))
+	return &paillier.PrivateKey{PublicKey: *paillier.NewPublicKey(new(big.Int).Mul(P, Q)), LambdaN: lambdaN, PhiN: phiN, P: P, Q: Q}
+}
+
+func TestE2E(t *testing.T) {
+	setUp("info")
+
+	pIDs := tss.GenerateTestPartyIDs(testParticipants)
+	p2pCtx := tss.NewPeerContext(pIDs)
+	outCh := make(chan tss.Message, len(pIDs)*len(pIDs))
+	endCh := make(chan *LocalPartySaveData, len(pIDs))
+	parties := make([]tss.Party, 0, len(pIDs))
+	for i := range pIDs {
+		params := tss.NewParameters(tss.S256(), p2pCtx, pIDs[i], len(pIDs), len(pIDs)-1)
+		P := NewLocalParty(params, outCh, endCh, testPaillierSK(t)).(*LocalParty)
+		P.temp.modulusBits = testModulusBits
+		parties = append(parties, P)
+	}
+	_, tssErr := test.RunSequentially(parties, outCh)
+	if !assert.Nil(t, tssErr) {
+		return
+	}
+
+	saves := make([]*LocalPartySaveData, len(pIDs))
+	for range pIDs {
+		save := <-endCh
+		assert.NoError(t, save.Validate())
+		index, err := save.OriginalIndex()
+		assert.NoError(t, err)
+		saves[index] = save
+	}
+	N := saves[0].PaillierPK.N
+	assert.Equal(t, testModulusBits, N.BitLen())
+	assert.False(t, N.ProbablyPrime(20))
+	for _, save := range saves {
+		assert.Equal(t, 0, N.Cmp(save.PaillierPK.N))
+		assert.Equal(t, 0, saves[0].Theta.Cmp(save.Theta))
+	}
+
+	// the shares decrypt together, and only together
+	m := common.GetRandomPositiveInt(N)
+	c, err := saves[0].PaillierPK.Encrypt(m)
+	assert.NoError(t, err)
+	shares := make([]*big.Int, len(saves))
+	for i, save := range saves {
+		shares[i], err = save.PartialDecrypt(c)
+		assert.NoError(t, err)
+	}
+	decrypted, err := Combine(saves[0].PaillierPK, saves[0].Theta, shares)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 0, m.Cmp(decrypted))
+	}
+	_, err = Combine(saves[0].PaillierPK, saves[0].Theta, shares[1:])
+	assert.Error(t, err)
+}
+
+func TestTooFewParties(t *testing.T) {
+	setUp("info")
+
+	pIDs := tss.GenerateTestPartyIDs(1)
+	params := tss.NewParameters(tss.S256(), tss.NewPeerContext(pIDs), pIDs[0], len(pIDs), 0)
+	P := NewLocalParty(params, make(chan tss.Message, 1), make(chan *LocalPartySaveData, 1), testPaillierSK(t))
+	err := P.Start()
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "at least 2 parties")
+	}
+}
@@ -0,0 +1,249 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"math/big"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto/paillier"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+// These messages were generated from Protocol Buffers definitions into paillier-keygen.pb.go
+// The following messages are registered on the Protocol Buffers "wire"
+
+var (
+	// Ensure that keygen messages implement ValidateBasic, and accept only them from the wire
+	messageTypes = tss.NewMessageTypes(
+		&KGRound1Message{},
+		&KGRound2Message{},
+		&KGRound3Message{},
+		&KGRound4Message{},
+		&KGRound5Message{},
+		&KGRound6Message{},
+		&KGRound7Message{},
+	)
+)
+
+// ----- //
+
+func NewKGRound1Message(
+	from *tss.PartyID,
+	attempt uint32,
+	paillierPK *paillier.PublicKey,
+	encP []*big.Int,
+) tss.ParsedMessage {
+	meta := tss.MessageRouting{
+		From:        from,
+		IsBroadcast: true,
+	}
+	content := &KGRound1Message{
+		Attempt:   attempt,
+		PaillierN: paillierPK.N.Bytes(),
+		EncP:      common.BigIntsToBytes(encP),
+	}
+	msg := tss.NewMessageWrapper(meta, content)
+	return tss.NewMessage(meta, content, msg)
+}
+
+func (m *KGRound1Message) ValidateBasic() bool {
+	return m != nil &&
+		common.NonEmptyBytes(m.GetPaillierN()) &&
+		common.NonEmptyMultiBytes(m.GetEncP(), CandidatesPerAttempt)
+}
+
+func (m *KGRound1Message) UnmarshalPaillierPK() *paillier.PublicKey {
+	return paillier.NewPublicKey(new(big.Int).SetBytes(m.GetPaillierN()))
+}
+
+func (m *KGRound1Message) UnmarshalEncP() []*big.Int {
+	return common.MultiBytesToBigInts(m.GetEncP())
+}
+
+// ----- //
+
+func NewKGRound2Message(
+	to, from *tss.PartyID,
+	attempt uint32,
+	mtaResponses []*big.Int,
+) tss.ParsedMessage {
+	meta := tss.MessageRouting{
+		From:        from,
+		To:          []*tss.PartyID{to},
+		IsBroadcast: false,
+	}
+	content := &KGRound2Message{
+		Attempt:     attempt,
+		MtaResponse: common.BigIntsToBytes(mtaResponses),
+	}
+	msg := tss.NewMessageWrapper(meta, content)
+	return tss.NewMessage(meta, content, msg)
+}
+
+func (m *KGRound2Message) ValidateBasic() bool {
+	return m != nil &&
+		common.NonEmptyMultiBytes(m.GetMtaResponse(), CandidatesPerAttempt)
+}
+
+func (m *KGRound2Message) UnmarshalMtAResponses() []*big.Int {
+	return common.MultiBytesToBigInts(m.GetMtaResponse())
+}
+
+// ----- //
+
+func NewKGRound3Message(
+	from *tss.PartyID,
+	attempt uint32,
+	modulusShares []*big.Int,
+) tss.ParsedMessage {
+	meta := tss.MessageRouting{
+		From:        from,
+		IsBroadcast: true,
+	}
+	bzs := make([][]byte, len(modulusShares))
+	for k, share := range modulusShares {
+		bzs[k] = common.SignedIntBytes(share)
+	}
+	content := &KGRound3Message{
+		Attempt:      attempt,
+		ModulusShare: bzs,
+	}
+	msg := tss.NewMessageWrapper(meta, content)
+	return tss.NewMessage(meta, content, msg)
+}
+
+func (m *KGRound3Message) ValidateBasic() bool {
+	return m != nil &&
+		common.NonEmptyMultiBytes(m.GetModulusShare(), CandidatesPerAttempt)
+}
+
+// UnmarshalModulusShares returns nil if one of the shares is malformed
+func (m *KGRound3Message) UnmarshalModulusShares() []*big.Int {
+	shares := make([]*big.Int, len(m.GetModulusShare()))
+	for k, bz := range m.GetModulusShare() {
+		if shares[k] = common.SignedIntFromBytes(bz); shares[k] == nil {
+			return nil
+		}
+	}
+	return shares
+}
+
+// ----- //
+
+func NewKGRound4Message(
+	from *tss.PartyID,
+	attempt uint32,
+	biprimality []*big.Int,
+) tss.ParsedMessage {
+	meta := tss.MessageRouting{
+		From:        from,
+		IsBroadcast: true,
+	}
+	content := &KGRound4Message{
+		Attempt:     attempt,
+		Biprimality: common.BigIntsToBytes(biprimality),
+	}
+	msg := tss.NewMessageWrapper(meta, content)
+	return tss.NewMessage(meta, content, msg)
+}
+
+// ValidateBasic allows an empty message, which is sent when none of the candidates of the attempt is left to test
+func (m *KGRound4Message) ValidateBasic() bool {
+	if m == nil || len(m.GetBiprimality())%BiprimalityTests != 0 {
+		return false
+	}
+	for _, bz := range m.GetBiprimality() {
+		if !common.NonEmptyBytes(bz) {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *KGRound4Message) UnmarshalBiprimality() []*big.Int {
+	return common.MultiBytesToBigInts(m.GetBiprimality())
+}
+
+// ----- //
+
+func NewKGRound5Message(
+	from *tss.PartyID,
+	encBeta *big.Int,
+) tss.ParsedMessage {
+	meta := tss.MessageRouting{
+		From:        from,
+		IsBroadcast: true,
+	}
+	content := &KGRound5Message{
+		EncBeta: encBeta.Bytes(),
+	}
+	msg := tss.NewMessageWrapper(meta, content)
+	return tss.NewMessage(meta, content, msg)
+}
+
+func (m *KGRound5Message) ValidateBasic() bool {
+	return m != nil &&
+		common.NonEmptyBytes(m.GetEncBeta())
+}
+
+func (m *KGRound5Message) UnmarshalEncBeta() *big.Int {
+	return new(big.Int).SetBytes(m.GetEncBeta())
+}
+
+// ----- //
+
+func NewKGRound6Message(
+	to, from *tss.PartyID,
+	mtaResponse *big.Int,
+) tss.ParsedMessage {
+	meta := tss.MessageRouting{
+		From:        from,
+		To:          []*tss.PartyID{to},
+		IsBroadcast: false,
+	}
+	content := &KGRound6Message{
+		MtaResponse: mtaResponse.Bytes(),
+	}
+	msg := tss.NewMessageWrapper(meta, content)
+	return tss.NewMessage(meta, content, msg)
+}
+
+func (m *KGRound6Message) ValidateBasic() bool {
+	return m != nil &&
+		common.NonEmptyBytes(m.GetMtaResponse())
+}
+
+func (m *KGRound6Message) UnmarshalMtAResponse() *big.Int {
+	return new(big.Int).SetBytes(m.GetMtaResponse())
+}
+
+// ----- //
+
+func NewKGRound7Message(
+	from *tss.PartyID,
+	thetaShare *big.Int,
+) tss.ParsedMessage {
+	meta := tss.MessageRouting{
+		From:        from,
+		IsBroadcast: true,
+	}
+	content := &KGRound7Message{
+		ThetaShare: thetaShare.Bytes(),
+	}
+	msg := tss.NewMessageWrapper(meta, content)
+	return tss.NewMessage(meta, content, msg)
+}
+
+func (m *KGRound7Message) ValidateBasic() bool {
+	return m != nil &&
+		common.NonEmptyBytes(m.GetThetaShare())
+}
+
+func (m *KGRound7Message) UnmarshalThetaShare() *big.Int {
+	return new(big.Int).SetBytes(m.GetThetaShare())
+}
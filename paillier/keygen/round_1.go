@@ -0,0 +1,138 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto/paillier"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+// round 1 represents round 1 of the distributed Paillier keygen; rounds 1 to 4 are repeated with new candidates
+// until a modulus passes the biprimality test in round 5
+func newRound1(params *tss.Parameters, save *LocalPartySaveData, temp *localTempData, out chan<- tss.Message, end chan<- *LocalPartySaveData) tss.Round {
+	return &round1{
+		&base{params, save, temp, out, end, make([]bool, len(params.Parties().IDs())), false, 1}}
+}
+
+func (round *round1) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started"))
+	}
+	round.number = 1
+	round.started = true
+	round.resetOK()
+
+	Pi := round.PartyID()
+	i := Pi.Index
+
+	if round.temp.attempt == 0 {
+		if err := round.prepare(); err != nil {
+			return err
+		}
+	}
+
+	// 1. sample the shares of the candidates: p = 3*2^(b-2) + sum(p_j), where p_0 = 3 mod 4 and p_j = 0 mod 4 for the
+	// other parties, so that p = 3 mod 4 has its two top bits set; the same for q
+	b := round.primeBits()
+	offset := new(big.Int).Lsh(big.NewInt(3), uint(b-2))
+	shareBound := new(big.Int).Div(new(big.Int).Lsh(big.NewInt(1), uint(b-2)), big.NewInt(int64(round.PartyCount())))
+	sampleShare := func() *big.Int {
+		share := new(big.Int).Lsh(common.GetRandomPositiveInt(new(big.Int).Rsh(shareBound, 2)), 2)
+		if i == 0 {
+			share.Add(share, offset).Add(share, big.NewInt(3))
+		}
+		return share
+	}
+	round.temp.p = make([]*big.Int, CandidatesPerAttempt)
+	round.temp.q = make([]*big.Int, CandidatesPerAttempt)
+	encP := make([]*big.Int, CandidatesPerAttempt)
+	for k := range encP {
+		round.temp.p[k], round.temp.q[k] = sampleShare(), sampleShare()
+		// 2. encrypt p_i for the MtA of the products p_i*q_j with the other parties
+		var err error
+		if encP[k], err = round.temp.paillierSK.Encrypt(round.temp.p[k]); err != nil {
+			return round.WrapError(err, Pi)
+		}
+	}
+	round.temp.paillierPKs[i] = &round.temp.paillierSK.PublicKey
+
+	r1msg := NewKGRound1Message(Pi, round.temp.attempt, &round.temp.paillierSK.PublicKey, encP)
+	round.temp.kgRound1Messages[i] = r1msg
+	round.SendMessage(round.out, r1msg)
+	return nil
+}
+
+// prepare checks the parameters, computes the ssid and generates the Paillier key of the party for the MtA
+func (round *round1) prepare() *tss.Error {
+	Pi := round.PartyID()
+	i := Pi.Index
+
+	if round.PartyCount() < 2 {
+		return round.WrapError(errors.New("the distributed Paillier keygen requires at least 2 parties"))
+	}
+	if round.temp.modulusBits < 64 || round.temp.modulusBits%2 != 0 {
+		return round.WrapError(fmt.Errorf("invalid modulus length of %d bits", round.temp.modulusBits))
+	}
+	round.temp.ssidNonce = new(big.Int).SetUint64(0)
+	ssid, err := round.getSSID()
+	if err != nil {
+		return round.WrapError(errors.New("failed to generate ssid"))
+	}
+	round.temp.ssid = ssid
+
+	auxBits := AuxModulusBits(round.temp.modulusBits)
+	if round.temp.paillierSK == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), round.SafePrimeGenTimeout())
+		defer cancel()
+		// round up to a multiple of 1024 bits, the usual length of the primes
+		paillierSK, _, err := paillier.GenerateKeyPair(ctx, (auxBits+1023)/1024*1024, round.Concurrency())
+		if err != nil {
+			return round.WrapError(errors.New("paillier key generation failed"), Pi)
+		}
+		round.temp.paillierSK = paillierSK
+	}
+	if round.temp.paillierSK.N.BitLen() < auxBits {
+		return round.WrapError(fmt.Errorf("the Paillier key of the party must have at least %d bits", auxBits), Pi)
+	}
+
+	ids := round.Parties().IDs().Keys()
+	round.save.ShareID = ids[i]
+	round.save.Ks = ids
+	return nil
+}
+
+func (round *round1) CanAccept(msg tss.ParsedMessage) bool {
+	if r1msg, ok := msg.Content().(*KGRound1Message); ok {
+		return msg.IsBroadcast() && r1msg.GetAttempt() == round.temp.attempt
+	}
+	return false
+}
+
+func (round *round1) Update() (bool, *tss.Error) {
+	for j, msg := range round.temp.kgRound1Messages {
+		if round.ok[j] {
+			continue
+		}
+		// a message of a previous attempt waits to be replaced
+		if msg == nil || !round.CanAccept(msg) {
+			return false, nil
+		}
+		round.ok[j] = true
+	}
+	return true, nil
+}
+
+func (round *round1) NextRound() tss.Round {
+	round.started = false
+	return &round2{round}
+}
@@ -0,0 +1,95 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+func (round *round2) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started"))
+	}
+	round.number = 2
+	round.started = true
+	round.resetOK()
+
+	Pi := round.PartyID()
+	i := Pi.Index
+	round.ok[i] = true
+
+	// the masks hide the products p_j*q_i < 2^(2b) statistically
+	maskBound := new(big.Int).Lsh(big.NewInt(1), uint(2*round.primeBits()+StatisticalSecurity))
+	round.temp.masks = make([]*big.Int, CandidatesPerAttempt)
+	for k := range round.temp.masks {
+		round.temp.masks[k] = new(big.Int)
+	}
+	for j, msg := range round.temp.kgRound1Messages {
+		if j == i {
+			continue
+		}
+		Pj := round.Parties().IDs()[j]
+		r1msg := msg.Content().(*KGRound1Message)
+
+		// 1. check the Paillier key of Pj, under which its share of the products is encrypted
+		paillierPKj := r1msg.UnmarshalPaillierPK()
+		if auxBits := AuxModulusBits(round.temp.modulusBits); paillierPKj.N.BitLen() < auxBits {
+			return round.WrapError(fmt.Errorf("the Paillier key of the party must have at least %d bits", auxBits), Pj)
+		}
+		round.temp.paillierPKs[j] = paillierPKj
+		encP := r1msg.UnmarshalEncP()
+		if !paillierPKj.ValidateCiphertexts(encP...) {
+			return round.WrapError(errors.New("the encrypted shares of p are not valid ciphertexts"), Pj)
+		}
+
+		// 2. answer the MtA of p_j*q_i with Enc(p_j*q_i + mask), and keep -mask as the share of the product
+		responses := make([]*big.Int, CandidatesPerAttempt)
+		for k := range encP {
+			mask := common.GetRandomPositiveInt(maskBound)
+			response, err := mtaResponse(paillierPKj, encP[k], round.temp.q[k], mask)
+			if err != nil {
+				return round.WrapError(err, Pi)
+			}
+			responses[k] = response
+			round.temp.masks[k].Add(round.temp.masks[k], mask)
+		}
+		r2msg := NewKGRound2Message(Pj, Pi, round.temp.attempt, responses)
+		round.SendMessage(round.out, r2msg)
+	}
+	return nil
+}
+
+func (round *round2) CanAccept(msg tss.ParsedMessage) bool {
+	if r2msg, ok := msg.Content().(*KGRound2Message); ok {
+		return !msg.IsBroadcast() && r2msg.GetAttempt() == round.temp.attempt
+	}
+	return false
+}
+
+func (round *round2) Update() (bool, *tss.Error) {
+	for j, msg := range round.temp.kgRound2Messages {
+		if round.ok[j] {
+			continue
+		}
+		// a message of a previous attempt waits to be replaced
+		if msg == nil || !round.CanAccept(msg) {
+			return false, nil
+		}
+		round.ok[j] = true
+	}
+	return true, nil
+}
+
+func (round *round2) NextRound() tss.Round {
+	round.started = false
+	return &round3{round}
+}
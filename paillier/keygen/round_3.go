@@ -0,0 +1,79 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+func (round *round3) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started"))
+	}
+	round.number = 3
+	round.started = true
+	round.resetOK()
+
+	Pi := round.PartyID()
+	i := Pi.Index
+
+	// 1. N_i = p_i*q_i + sum(p_i*q_j + mask_j) - sum(mask_i), so that N = sum(N_j) = p*q
+	modulusShares := make([]*big.Int, CandidatesPerAttempt)
+	for k := range modulusShares {
+		modulusShares[k] = new(big.Int).Mul(round.temp.p[k], round.temp.q[k])
+		modulusShares[k].Sub(modulusShares[k], round.temp.masks[k])
+	}
+	for j, msg := range round.temp.kgRound2Messages {
+		if j == i {
+			continue
+		}
+		Pj := round.Parties().IDs()[j]
+		responses := msg.Content().(*KGRound2Message).UnmarshalMtAResponses()
+		for k, response := range responses {
+			alpha, err := round.temp.paillierSK.Decrypt(response)
+			if err != nil {
+				return round.WrapError(errors.New("failed to decrypt the MtA response"), Pj)
+			}
+			modulusShares[k].Add(modulusShares[k], alpha)
+		}
+	}
+
+	// 2. reveal N_i
+	r3msg := NewKGRound3Message(Pi, round.temp.attempt, modulusShares)
+	round.temp.kgRound3Messages[i] = r3msg
+	round.SendMessage(round.out, r3msg)
+	return nil
+}
+
+func (round *round3) CanAccept(msg tss.ParsedMessage) bool {
+	if r3msg, ok := msg.Content().(*KGRound3Message); ok {
+		return msg.IsBroadcast() && r3msg.GetAttempt() == round.temp.attempt
+	}
+	return false
+}
+
+func (round *round3) Update() (bool, *tss.Error) {
+	for j, msg := range round.temp.kgRound3Messages {
+		if round.ok[j] {
+			continue
+		}
+		// a message of a previous attempt waits to be replaced
+		if msg == nil || !round.CanAccept(msg) {
+			return false, nil
+		}
+		round.ok[j] = true
+	}
+	return true, nil
+}
+
+func (round *round3) NextRound() tss.Round {
+	round.started = false
+	return &round4{round}
+}
@@ -0,0 +1,111 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/otiai10/primes"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+func (round *round4) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started"))
+	}
+	round.number = 4
+	round.started = true
+	round.resetOK()
+
+	Pi := round.PartyID()
+	i := Pi.Index
+
+	// 1. compute the candidate moduli N = sum(N_j), and keep those of the right length without small factors
+	moduli := make([]*big.Int, CandidatesPerAttempt)
+	for k := range moduli {
+		moduli[k] = new(big.Int)
+	}
+	for j, msg := range round.temp.kgRound3Messages {
+		shares := msg.Content().(*KGRound3Message).UnmarshalModulusShares()
+		if shares == nil {
+			return round.WrapError(errors.New("failed to unmarshal the shares of the candidate moduli"), round.Parties().IDs()[j])
+		}
+		for k, share := range shares {
+			moduli[k].Add(moduli[k], share)
+		}
+	}
+	round.temp.moduli = moduli
+	round.temp.candidates = round.temp.candidates[:0]
+	smallPrimes := primes.Until(trialDivisionBound).List()
+	for k, N := range moduli {
+		if N.Sign() != 1 || N.BitLen() != round.temp.modulusBits || hasSmallFactor(N, smallPrimes) {
+			continue
+		}
+		round.temp.candidates = append(round.temp.candidates, k)
+	}
+
+	// 2. for each remaining candidate, compute v_i = g^((N+1-p_0-q_0)/4) for P_0 and g^((p_i+q_i)/4) for the other
+	// parties, for the shared bases g of the biprimality test of Boneh and Franklin (1997)
+	biprimality := make([]*big.Int, 0, len(round.temp.candidates)*BiprimalityTests)
+	for _, k := range round.temp.candidates {
+		N := moduli[k]
+		e := new(big.Int).Add(round.temp.p[k], round.temp.q[k])
+		if i == 0 {
+			e.Sub(new(big.Int).Add(N, big.NewInt(1)), e)
+		}
+		e.Rsh(e, 2)
+		modN := common.ModInt(N)
+		for t := 0; t < BiprimalityTests; t++ {
+			biprimality = append(biprimality, modN.SecretExp(round.biprimalityBase(N, t), e, nil))
+		}
+	}
+
+	r4msg := NewKGRound4Message(Pi, round.temp.attempt, biprimality)
+	round.temp.kgRound4Messages[i] = r4msg
+	round.SendMessage(round.out, r4msg)
+	return nil
+}
+
+// hasSmallFactor returns true if N is divisible by one of `smallPrimes`
+func hasSmallFactor(N *big.Int, smallPrimes []int64) bool {
+	r := new(big.Int)
+	for _, prime := range smallPrimes {
+		if r.Mod(N, big.NewInt(prime)).Sign() == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (round *round4) CanAccept(msg tss.ParsedMessage) bool {
+	if r4msg, ok := msg.Content().(*KGRound4Message); ok {
+		return msg.IsBroadcast() && r4msg.GetAttempt() == round.temp.attempt
+	}
+	return false
+}
+
+func (round *round4) Update() (bool, *tss.Error) {
+	for j, msg := range round.temp.kgRound4Messages {
+		if round.ok[j] {
+			continue
+		}
+		// a message of a previous attempt waits to be replaced
+		if msg == nil || !round.CanAccept(msg) {
+			return false, nil
+		}
+		round.ok[j] = true
+	}
+	return true, nil
+}
+
+func (round *round4) NextRound() tss.Round {
+	round.started = false
+	return &round5{round}
+}
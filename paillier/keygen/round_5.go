@@ -0,0 +1,115 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+func (round *round5) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started"))
+	}
+	round.number = 5
+	round.started = true
+	round.resetOK()
+
+	Pi := round.PartyID()
+	i := Pi.Index
+
+	values := make([][]*big.Int, len(round.temp.kgRound4Messages))
+	for j, msg := range round.temp.kgRound4Messages {
+		values[j] = msg.Content().(*KGRound4Message).UnmarshalBiprimality()
+		if len(values[j]) != len(round.temp.candidates)*BiprimalityTests {
+			return round.WrapError(errors.New("unexpected number of biprimality test values"), round.Parties().IDs()[j])
+		}
+	}
+
+	// 1. v_0 / prod(v_j) = g^(phi(N)/4) is +-1 mod N for every base g when N is the product of two primes = 3 mod 4;
+	// the first candidate that passes every test is the modulus
+	for c, k := range round.temp.candidates {
+		if round.biprimalityTest(round.temp.moduli[k], values, c) {
+			round.temp.bigN = round.temp.moduli[k]
+			round.temp.pi, round.temp.qi = round.temp.p[k], round.temp.q[k]
+			break
+		}
+	}
+	if round.temp.bigN == nil {
+		// none of the candidates is a biprime: NextRound starts another attempt
+		for j := range round.ok {
+			round.ok[j] = true
+		}
+		return nil
+	}
+
+	// 2. sample the share beta_i of the mask beta of phi(N), and encrypt it for the MtA of the products
+	// beta_i*(p_j+q_j) with the other parties
+	beta := common.GetRandomPositiveInt(round.temp.bigN)
+	encBeta, err := round.temp.paillierSK.Encrypt(beta)
+	if err != nil {
+		return round.WrapError(err, Pi)
+	}
+	round.temp.beta = beta
+
+	r5msg := NewKGRound5Message(Pi, encBeta)
+	round.temp.kgRound5Messages[i] = r5msg
+	round.SendMessage(round.out, r5msg)
+	return nil
+}
+
+// biprimalityTest runs the tests of the c-th candidate N with the values of all of the parties
+func (round *round5) biprimalityTest(N *big.Int, values [][]*big.Int, c int) bool {
+	modN := common.ModInt(N)
+	minusOne := new(big.Int).Sub(N, big.NewInt(1))
+	for t := 0; t < BiprimalityTests; t++ {
+		v := modN.Mod(values[0][c*BiprimalityTests+t])
+		for j := 1; j < len(values); j++ {
+			vjInv := modN.ModInverse(values[j][c*BiprimalityTests+t])
+			if vjInv == nil {
+				return false
+			}
+			v = modN.Mul(v, vjInv)
+		}
+		if v.Cmp(big.NewInt(1)) != 0 && v.Cmp(minusOne) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (round *round5) CanAccept(msg tss.ParsedMessage) bool {
+	if _, ok := msg.Content().(*KGRound5Message); ok {
+		return msg.IsBroadcast()
+	}
+	return false
+}
+
+func (round *round5) Update() (bool, *tss.Error) {
+	for j, msg := range round.temp.kgRound5Messages {
+		if round.ok[j] {
+			continue
+		}
+		if msg == nil || !round.CanAccept(msg) {
+			return false, nil
+		}
+		round.ok[j] = true
+	}
+	return true, nil
+}
+
+func (round *round5) NextRound() tss.Round {
+	round.started = false
+	if round.temp.bigN == nil {
+		round.temp.attempt++
+		return round.round1
+	}
+	return &round6{round}
+}
@@ -0,0 +1,81 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+func (round *round6) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started"))
+	}
+	round.number = 6
+	round.started = true
+	round.resetOK()
+
+	Pi := round.PartyID()
+	i := Pi.Index
+	round.ok[i] = true
+
+	// the masks hide the products beta_j*(p_i+q_i) < 2^(3b+1) statistically
+	maskBound := new(big.Int).Lsh(big.NewInt(1), uint(3*round.primeBits()+1+StatisticalSecurity))
+	xi := new(big.Int).Add(round.temp.pi, round.temp.qi)
+	round.temp.betaMaskSum = new(big.Int)
+	for j, msg := range round.temp.kgRound5Messages {
+		if j == i {
+			continue
+		}
+		Pj := round.Parties().IDs()[j]
+		paillierPKj := round.temp.paillierPKs[j]
+		encBeta := msg.Content().(*KGRound5Message).UnmarshalEncBeta()
+		if !paillierPKj.ValidateCiphertexts(encBeta) {
+			return round.WrapError(errors.New("the encrypted share of beta is not a valid ciphertext"), Pj)
+		}
+
+		// answer the MtA of beta_j*(p_i+q_i) with Enc(beta_j*(p_i+q_i) + mask), and keep -mask as the share of the product
+		mask := common.GetRandomPositiveInt(maskBound)
+		response, err := mtaResponse(paillierPKj, encBeta, xi, mask)
+		if err != nil {
+			return round.WrapError(err, Pi)
+		}
+		round.temp.betaMaskSum.Add(round.temp.betaMaskSum, mask)
+
+		r6msg := NewKGRound6Message(Pj, Pi, response)
+		round.SendMessage(round.out, r6msg)
+	}
+	return nil
+}
+
+func (round *round6) CanAccept(msg tss.ParsedMessage) bool {
+	if _, ok := msg.Content().(*KGRound6Message); ok {
+		return !msg.IsBroadcast()
+	}
+	return false
+}
+
+func (round *round6) Update() (bool, *tss.Error) {
+	for j, msg := range round.temp.kgRound6Messages {
+		if round.ok[j] {
+			continue
+		}
+		if msg == nil || !round.CanAccept(msg) {
+			return false, nil
+		}
+		round.ok[j] = true
+	}
+	return true, nil
+}
+
+func (round *round6) NextRound() tss.Round {
+	round.started = false
+	return &round7{round}
+}
@@ -0,0 +1,76 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+func (round *round7) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started"))
+	}
+	round.number = 7
+	round.started = true
+	round.resetOK()
+
+	Pi := round.PartyID()
+	i := Pi.Index
+
+	// 1. phi(N) = N+1 - sum(p_j+q_j), so beta*phi(N) = sum(beta_i*(N+1)) - sum(beta_i*(p_j+q_j)) over all i and j, and
+	// s_i = beta_i*(N+1) - beta_i*(p_i+q_i) - sum(beta_i*(p_j+q_j) + mask_j) + sum(mask_i)
+	N := round.temp.bigN
+	beta := round.temp.beta
+	si := new(big.Int).Mul(beta, new(big.Int).Add(N, big.NewInt(1)))
+	si.Sub(si, new(big.Int).Mul(beta, new(big.Int).Add(round.temp.pi, round.temp.qi)))
+	si.Add(si, round.temp.betaMaskSum)
+	for j, msg := range round.temp.kgRound6Messages {
+		if j == i {
+			continue
+		}
+		alpha, err := round.temp.paillierSK.Decrypt(msg.Content().(*KGRound6Message).UnmarshalMtAResponse())
+		if err != nil {
+			return round.WrapError(errors.New("failed to decrypt the MtA response"), round.Parties().IDs()[j])
+		}
+		si.Sub(si, alpha)
+	}
+	round.save.Si = si
+
+	// 2. reveal s_i mod N, whose sum is Theta = beta*phi(N) mod N
+	r7msg := NewKGRound7Message(Pi, new(big.Int).Mod(si, N))
+	round.temp.kgRound7Messages[i] = r7msg
+	round.SendMessage(round.out, r7msg)
+	return nil
+}
+
+func (round *round7) CanAccept(msg tss.ParsedMessage) bool {
+	if _, ok := msg.Content().(*KGRound7Message); ok {
+		return msg.IsBroadcast()
+	}
+	return false
+}
+
+func (round *round7) Update() (bool, *tss.Error) {
+	for j, msg := range round.temp.kgRound7Messages {
+		if round.ok[j] {
+			continue
+		}
+		if msg == nil || !round.CanAccept(msg) {
+			return false, nil
+		}
+		round.ok[j] = true
+	}
+	return true, nil
+}
+
+func (round *round7) NextRound() tss.Round {
+	round.started = false
+	return &finalization{round}
+}
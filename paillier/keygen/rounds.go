@@ -0,0 +1,179 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"math/big"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto/paillier"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+const (
+	TaskName = "paillier-keygen"
+
+	// CandidatesPerAttempt is the number of candidates for p and q that the parties try in each attempt
+	CandidatesPerAttempt = 64
+	// BiprimalityTests is the number of tests of a candidate modulus; a modulus that is not the product of two primes
+	// passes each test with a probability of at most 1/2
+	BiprimalityTests = 128
+	// StatisticalSecurity is the bit length by which the masks of the MtA exceed the products that they hide
+	StatisticalSecurity = 128
+
+	// a candidate modulus with a factor below trialDivisionBound is discarded without a biprimality test
+	trialDivisionBound = 1 << 12
+)
+
+// AuxModulusBits returns the minimum bit length of the Paillier keys of the parties for the MtA of the generation of a
+// modulus of `modulusBits` bits, so that the masked products of beta < N and of p_j + q_j do not wrap around
+func AuxModulusBits(modulusBits int) int {
+	return 3*(modulusBits/2) + StatisticalSecurity + 8
+}
+
+type (
+	base struct {
+		*tss.Parameters
+		save    *LocalPartySaveData
+		temp    *localTempData
+		out     chan<- tss.Message
+		end     chan<- *LocalPartySaveData
+		ok      []bool // `ok` tracks parties which have been verified by Update()
+		started bool
+		number  int
+	}
+	round1 struct {
+		*base
+	}
+	round2 struct {
+		*round1
+	}
+	round3 struct {
+		*round2
+	}
+	round4 struct {
+		*round3
+	}
+	round5 struct {
+		*round4
+	}
+	round6 struct {
+		*round5
+	}
+	round7 struct {
+		*round6
+	}
+	finalization struct {
+		*round7
+	}
+)
+
+var (
+	_ tss.Round = (*round1)(nil)
+	_ tss.Round = (*round2)(nil)
+	_ tss.Round = (*round3)(nil)
+	_ tss.Round = (*round4)(nil)
+	_ tss.Round = (*round5)(nil)
+	_ tss.Round = (*round6)(nil)
+	_ tss.Round = (*round7)(nil)
+	_ tss.Round = (*finalization)(nil)
+)
+
+// ----- //
+
+func (round *base) Params() *tss.Parameters {
+	return round.Parameters
+}
+
+func (round *base) RoundNumber() int {
+	return round.number
+}
+
+// CanProceed is inherited by other rounds
+func (round *base) CanProceed() bool {
+	if !round.started {
+		return false
+	}
+	for _, ok := range round.ok {
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// WaitingFor is called by a Party for reporting back to the caller
+func (round *base) WaitingFor() []*tss.PartyID {
+	Ps := round.Parties().IDs()
+	ids := make([]*tss.PartyID, 0, len(round.ok))
+	for j, ok := range round.ok {
+		if ok {
+			continue
+		}
+		ids = append(ids, Ps[j])
+	}
+	return ids
+}
+
+func (round *base) WrapError(err error, culprits ...*tss.PartyID) *tss.Error {
+	return tss.NewError(err, TaskName, round.number, round.PartyID(), culprits...)
+}
+
+// ----- //
+
+// `ok` tracks parties which have been verified by Update()
+func (round *base) resetOK() {
+	for j := range round.ok {
+		round.ok[j] = false
+	}
+}
+
+// get ssid from local params
+func (round *base) getSSID() ([]byte, error) {
+	ssidList := append([]*big.Int{}, round.Parties().IDs().Keys()...)
+	ssidList = append(ssidList, new(big.Int).SetBytes(common.SHA512_256(round.SSID()))) // application session id
+	ssidList = append(ssidList, big.NewInt(int64(round.number)))                        // round number
+	ssidList = append(ssidList, round.temp.ssidNonce)
+	ssid := common.SHA512_256i(ssidList...).Bytes()
+
+	return ssid, nil
+}
+
+// primeBits returns the bit length of p and q
+func (round *base) primeBits() int {
+	return round.temp.modulusBits / 2
+}
+
+// biprimalityBase returns the base of the t-th biprimality test of N, which is derived from the session so that all of
+// the parties use the same one. It is drawn from Z_N with a Jacobi symbol of 1.
+func (round *base) biprimalityBase(N *big.Int, t int) *big.Int {
+	for ctr := int64(0); ; ctr++ {
+		// expand the hash to 128 bits more than N so that the base is close to uniform mod N
+		g := new(big.Int)
+		for block := int64(0); g.BitLen() < N.BitLen()+128; block++ {
+			h := common.SHA512_256i(new(big.Int).SetBytes(round.temp.ssid), N, big.NewInt(int64(t)), big.NewInt(ctr), big.NewInt(block))
+			g.Lsh(g, 256).Or(g, h)
+		}
+		g.Mod(g, N)
+		if big.Jacobi(g, N) == 1 {
+			return g
+		}
+	}
+}
+
+// mtaResponse returns Enc(a*b + mask) under `pk` for the ciphertext `encA` of a, the answer of the MtA of a*b
+func mtaResponse(pk *paillier.PublicKey, encA, b, mask *big.Int) (*big.Int, error) {
+	encAB, err := pk.HomoMult(b, encA)
+	if err != nil {
+		return nil, err
+	}
+	encMask, err := pk.Encrypt(mask)
+	if err != nil {
+		return nil, err
+	}
+	return pk.HomoAdd(encAB, encMask)
+}
@@ -0,0 +1,68 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/kisdex/mpc-lib/crypto/paillier"
+)
+
+type (
+	LocalSecrets struct {
+		// secret fields (not shared, but stored locally)
+		// Si is the additive share of beta*phi(N) of the party, which may be negative
+		Si, ShareID *big.Int // si, kj
+	}
+
+	// Everything in LocalPartySaveData is saved locally to user's HD when done
+	LocalPartySaveData struct {
+		LocalSecrets
+
+		// the Paillier key of the shared modulus, whose factorization is known to none of the parties
+		PaillierPK *paillier.PublicKey
+
+		// Theta = beta*phi(N) mod N for the secret beta that masks phi(N); the combined decryption shares of a
+		// ciphertext of m give m*Theta
+		Theta *big.Int
+
+		// original indexes (ki in signing preparation phase)
+		Ks []*big.Int
+	}
+)
+
+func NewLocalPartySaveData(partyCount int) (saveData LocalPartySaveData) {
+	saveData.Ks = make([]*big.Int, partyCount)
+	return
+}
+
+// OriginalIndex recovers a party's original index in the set of parties during keygen
+func (save LocalPartySaveData) OriginalIndex() (int, error) {
+	for j, kj := range save.Ks {
+		if kj != nil && save.ShareID != nil && kj.Cmp(save.ShareID) == 0 {
+			return j, nil
+		}
+	}
+	return -1, errors.New("a party index could not be recovered from Ks")
+}
+
+// Validate checks that the save data is complete and that Theta is invertible mod N
+func (save LocalPartySaveData) Validate() error {
+	if save.Si == nil || save.ShareID == nil || save.PaillierPK == nil || save.PaillierPK.N == nil || save.Theta == nil {
+		return errors.New("the Paillier keygen save data is incomplete")
+	}
+	for _, kj := range save.Ks {
+		if kj == nil {
+			return errors.New("the Paillier keygen save data is incomplete")
+		}
+	}
+	if new(big.Int).GCD(nil, nil, save.Theta, save.PaillierPK.N).Cmp(big.NewInt(1)) != 0 {
+		return errors.New("theta must be invertible mod N")
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/kisdex/mpc-lib/crypto"
+)
+
+const (
+	// MessageBytesLen is the length of the digest signed by BIP340
+	MessageBytesLen = 32
+
+	// XOnlyPubKeyBytesLen is the length of a BIP340 x-only public key
+	XOnlyPubKeyBytesLen = 32
+
+	// SignatureBytesLen is the length of a BIP340 signature, R.x || s
+	SignatureBytesLen = 64
+)
+
+// XOnlyPubKey returns the 32-byte BIP340 encoding of the public key, which only carries the x-coordinate
+func XOnlyPubKey(pub *crypto.ECPoint) []byte {
+	return padToLengthBytes(pub.X().Bytes(), XOnlyPubKeyBytesLen)
+}
+
+// TaprootTweak computes the BIP341 tweak t = H_TapTweak(P.x || merkleRoot) for the internal key `pub` along with the
+// resulting output key Q = lift_x(P.x) + t*G. Pass a nil merkleRoot for a key-path only output (BIP86).
+// The tweak is meant to be handed to NewLocalPartyWithTweak.
+func TaprootTweak(pub *crypto.ECPoint, merkleRoot []byte) (*big.Int, *crypto.ECPoint, error) {
+	ec := pub.Curve()
+	h := chainhash.TaggedHash(chainhash.TagTapTweak, XOnlyPubKey(pub), merkleRoot)
+	t := new(big.Int).SetBytes(h[:])
+	if t.Sign() == 0 || t.Cmp(ec.Params().N) >= 0 {
+		return nil, nil, errors.New("invalid taproot tweak")
+	}
+	if isOdd(pub.Y()) {
+		pub = negatePoint(pub)
+	}
+	outputKey, err := pub.Add(crypto.ScalarBaseMult(ec, t))
+	if err != nil {
+		return nil, nil, err
+	}
+	return t, outputKey, nil
+}
+
+// ----- //
+
+func challenge(rx *big.Int, pub *crypto.ECPoint, m []byte) *big.Int {
+	h := chainhash.TaggedHash(chainhash.TagBIP0340Challenge, padToLengthBytes(rx.Bytes(), 32), XOnlyPubKey(pub), m)
+	return new(big.Int).SetBytes(h[:])
+}
+
+func isOdd(a *big.Int) bool {
+	return a.Bit(0) == 1
+}
+
+func negatePoint(p *crypto.ECPoint) *crypto.ECPoint {
+	ec := p.Curve()
+	negY := new(big.Int).Sub(ec.Params().P, p.Y())
+	return crypto.NewECPointNoCurveCheck(ec, p.X(), negY)
+}
+
+func padToLengthBytes(src []byte, length int) []byte {
+	oriLen := len(src)
+	if oriLen < length {
+		dst := make([]byte, length)
+		copy(dst[length-oriLen:], src)
+		return dst
+	}
+	return src
+}
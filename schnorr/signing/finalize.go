@@ -0,0 +1,73 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+func (round *finalization) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started"))
+	}
+	round.number = 4
+	round.started = true
+	round.resetOK()
+
+	modN := common.ModInt(round.Params().EC().Params().N)
+	sumS := round.temp.si
+	for j := range round.Parties().IDs() {
+		round.ok[j] = true
+		if j == round.PartyID().Index {
+			continue
+		}
+		r3msg := round.temp.signRound3Messages[j].Content().(*SignRound3Message)
+		sumS = modN.Add(sumS, r3msg.UnmarshalS())
+	}
+
+	// save the signature for final output
+	rBz := padToLengthBytes(round.temp.r.Bytes(), 32)
+	sBz := padToLengthBytes(sumS.Bytes(), 32)
+	round.data.Signature = append(append([]byte{}, rBz...), sBz...)
+	round.data.R = rBz
+	round.data.S = sBz
+	round.data.M = round.temp.m
+
+	pk, err := schnorr.ParsePubKey(XOnlyPubKey(round.temp.pubKey))
+	if err != nil {
+		return round.WrapError(err)
+	}
+	sig, err := schnorr.ParseSignature(round.data.Signature)
+	if err != nil {
+		return round.WrapError(err)
+	}
+	if ok := sig.Verify(round.temp.m, pk); !ok {
+		return round.WrapError(fmt.Errorf("signature verification failed"))
+	}
+	round.end <- round.data
+
+	return nil
+}
+
+func (round *finalization) CanAccept(msg tss.ParsedMessage) bool {
+	// not expecting any incoming messages in this round
+	return false
+}
+
+func (round *finalization) Update() (bool, *tss.Error) {
+	// not expecting any incoming messages in this round
+	return false, nil
+}
+
+func (round *finalization) NextRound() tss.Round {
+	return nil // finished!
+}
@@ -0,0 +1,180 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"crypto/sha256"
+	"math/big"
+	"sync/atomic"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto"
+	"github.com/kisdex/mpc-lib/ecdsa/keygen"
+	"github.com/kisdex/mpc-lib/test"
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+const (
+	testParticipants = test.TestParticipants
+	testThreshold    = test.TestThreshold
+)
+
+func setUp(level string) {
+	if err := common.SetLogLevel(level); err != nil {
+		panic(err)
+	}
+}
+
+func TestE2EConcurrent(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	msg := sha256.Sum256([]byte("bip340 threshold signing"))
+	sig := runSigning(t, msg[:], keys, signPIDs, nil)
+
+	pk, err := schnorr.ParsePubKey(XOnlyPubKey(keys[0].ECDSAPub))
+	assert.NoError(t, err)
+	parsed, err := schnorr.ParseSignature(sig.Signature)
+	assert.NoError(t, err)
+	assert.True(t, parsed.Verify(msg[:], pk), "bip340 verify must pass")
+}
+
+func TestE2ETaprootKeyPath(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	tweak, outputKey, err := TaprootTweak(keys[0].ECDSAPub, nil)
+	assert.NoError(t, err)
+
+	msg := sha256.Sum256([]byte("bip341 key-path spend"))
+	sig := runSigning(t, msg[:], keys, signPIDs, tweak)
+
+	pk, err := schnorr.ParsePubKey(XOnlyPubKey(outputKey))
+	assert.NoError(t, err)
+	parsed, err := schnorr.ParseSignature(sig.Signature)
+	assert.NoError(t, err)
+	assert.True(t, parsed.Verify(msg[:], pk), "bip340 verify must pass under the tweaked output key")
+
+	// the untweaked internal key must not verify the same signature
+	internal, err := schnorr.ParsePubKey(XOnlyPubKey(keys[0].ECDSAPub))
+	assert.NoError(t, err)
+	assert.False(t, parsed.Verify(msg[:], internal))
+}
+
+func TestAdjustShareForEvenY(t *testing.T) {
+	ec := tss.S256()
+	// x and -x produce P and -P; both must end up signing for the same even-Y key
+	for _, x := range []*big.Int{big.NewInt(7), new(big.Int).Sub(ec.Params().N, big.NewInt(7))} {
+		pub := crypto.ScalarBaseMult(ec, x)
+		d, evenPub, err := adjustShareForEvenY(ec, x, pub, nil)
+		assert.NoError(t, err)
+		assert.False(t, isOdd(evenPub.Y()))
+		assert.True(t, crypto.ScalarBaseMult(ec, d).Equals(evenPub))
+		assert.Equal(t, 0, evenPub.X().Cmp(pub.X()))
+
+		tweak, outputKey, err := TaprootTweak(pub, nil)
+		assert.NoError(t, err)
+		d, evenPub, err = adjustShareForEvenY(ec, x, pub, tweak)
+		assert.NoError(t, err)
+		assert.False(t, isOdd(evenPub.Y()))
+		assert.True(t, crypto.ScalarBaseMult(ec, d).Equals(evenPub))
+		assert.Equal(t, 0, evenPub.X().Cmp(outputKey.X()))
+	}
+}
+
+func runSigning(t *testing.T, msg []byte, keys []keygen.LocalPartySaveData, signPIDs tss.SortedPartyIDs, tweak *big.Int) *common.SignatureData {
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]*LocalParty, 0, len(signPIDs))
+
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs))
+	endCh := make(chan *common.SignatureData, len(signPIDs))
+
+	updater := test.SharedPartyUpdater
+
+	// init the parties
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(tss.S256(), p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+
+		P := NewLocalPartyWithTweak(msg, params, keys[i], tweak, outCh, endCh).(*LocalParty)
+		parties = append(parties, P)
+		go func(P *LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	var ended int32
+	var sig *common.SignatureData
+	for {
+		select {
+		case err := <-errCh:
+			common.DefaultLogger().Errorf("Error: %s", err)
+			assert.FailNow(t, err.Error())
+			return nil
+
+		case msg := <-outCh:
+			dest := msg.GetTo()
+			if dest == nil {
+				for _, P := range parties {
+					if P.PartyID().Index == msg.GetFrom().Index {
+						continue
+					}
+					go updater(P, msg, errCh)
+				}
+			} else {
+				if dest[0].Index == msg.GetFrom().Index {
+					t.Fatalf("party %d tried to send a message to itself (%d)", dest[0].Index, msg.GetFrom().Index)
+				}
+				go updater(parties[dest[0].Index], msg, errCh)
+			}
+
+		case data := <-endCh:
+			if sig == nil {
+				sig = data
+			} else {
+				assert.Equal(t, sig.Signature, data.Signature, "all parties must output the same signature")
+			}
+			if atomic.AddInt32(&ended, 1) == int32(len(signPIDs)) {
+				t.Logf("Done. Received signature data from %d participants", ended)
+				assert.Len(t, sig.Signature, SignatureBytesLen)
+				return sig
+			}
+		}
+	}
+}
+
+func FuzzMessages(f *testing.F) {
+	setUp("error")
+	keys, signPIDs, err := keygen.LoadKeygenTestFixtures(testThreshold + 1)
+	if err != nil {
+		f.Fatal(err)
+	}
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	outCh := make(chan tss.Message, len(signPIDs)*len(signPIDs))
+	endCh := make(chan *common.SignatureData, len(signPIDs))
+	parties := make([]tss.Party, 0, len(signPIDs))
+	msg := sha256.Sum256([]byte("bip340 threshold signing"))
+	for i := range signPIDs {
+		params := tss.NewParameters(tss.S256(), p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+		parties = append(parties, NewLocalParty(msg[:], params, keys[i], outCh, endCh))
+	}
+	transcript, tssErr := test.RunSequentially(parties, outCh)
+	if tssErr != nil {
+		f.Fatal(tssErr)
+	}
+	test.FuzzWireMessages(f, tss.S256(), transcript)
+}
@@ -0,0 +1,74 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"crypto/elliptic"
+	"fmt"
+	"math/big"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto"
+)
+
+// PrepareForSigning(), Fig. 7
+func PrepareForSigning(ec elliptic.Curve, i, pax int, xi *big.Int, ks []*big.Int) (wi *big.Int) {
+	modQ := common.ModInt(ec.Params().N)
+	if len(ks) != pax {
+		panic(fmt.Errorf("PrepareForSigning: len(ks) != pax (%d != %d)", len(ks), pax))
+	}
+	if len(ks) <= i {
+		panic(fmt.Errorf("PrepareForSigning: len(ks) <= i (%d <= %d)", len(ks), i))
+	}
+
+	// 1-4.
+	// a copy of xi, as the party wipes wi once it has signed
+	wi = new(big.Int).Set(xi)
+	for j := 0; j < pax; j++ {
+		if j == i {
+			continue
+		}
+		ksj := ks[j]
+		ksi := ks[i]
+		if ksj.Cmp(ksi) == 0 {
+			panic(fmt.Errorf("index of two parties are equal"))
+		}
+		// big.Int Div is calculated as: a/b = a * modInv(b,q)
+		coef := modQ.Mul(ks[j], modQ.ModInverse(new(big.Int).Sub(ksj, ksi)))
+		wi = modQ.Mul(wi, coef)
+	}
+
+	return
+}
+
+// adjustShareForEvenY maps the Shamir share xi of x, where P = x*G, to a share of the secret d that BIP340 signs
+// with. Negating or shifting every share by the same amount does the same to the shared secret, so this runs locally:
+//  1. if P has odd Y, use -x so that the key is the even-Y lift of P.x
+//  2. if a tweak t is given, use x + t so that the key is Q = P + t*G
+//  3. if the resulting key has odd Y, negate once more
+//
+// It returns the adjusted share and the even-Y public key.
+func adjustShareForEvenY(ec elliptic.Curve, xi *big.Int, pub *crypto.ECPoint, tweak *big.Int) (*big.Int, *crypto.ECPoint, error) {
+	modQ := common.ModInt(ec.Params().N)
+	var err error
+	if isOdd(pub.Y()) {
+		xi, pub = modQ.Sub(big.NewInt(0), xi), negatePoint(pub)
+	}
+	if tweak != nil {
+		if tweak.Sign() <= 0 || tweak.Cmp(ec.Params().N) >= 0 {
+			return nil, nil, fmt.Errorf("tweak is out of range")
+		}
+		xi = modQ.Add(xi, tweak)
+		if pub, err = pub.Add(crypto.ScalarBaseMult(ec, tweak)); err != nil {
+			return nil, nil, err
+		}
+	}
+	if isOdd(pub.Y()) {
+		xi, pub = modQ.Sub(big.NewInt(0), xi), negatePoint(pub)
+	}
+	return xi, pub, nil
+}
@@ -0,0 +1,118 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/crypto"
+	"github.com/kisdex/mpc-lib/crypto/commitments"
+	"github.com/kisdex/mpc-lib/tss"
+
+	errors2 "github.com/pkg/errors"
+)
+
+func (round *round3) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started"))
+	}
+
+	round.number = 3
+	round.started = true
+	round.resetOK()
+
+	// 1. init R
+	R := round.temp.pointRi
+
+	// 2-6. compute R
+	i := round.PartyID().Index
+	for j, Pj := range round.Parties().IDs() {
+		if j == i {
+			continue
+		}
+
+		ContextJ := common.AppendBigIntToBytesSlice(round.temp.ssid, big.NewInt(int64(j)))
+		msg := round.temp.signRound2Messages[j]
+		r2msg := msg.Content().(*SignRound2Message)
+		cmtDeCmt := commitments.HashCommitDecommit{C: round.temp.cjs[j], D: r2msg.UnmarshalDeCommitment()}
+		ok, encoded := cmtDeCmt.DeCommit()
+		if !ok {
+			return round.WrapError(errors.New("de-commitment verify failed"), Pj).WithCode(tss.ErrProofVerifyFailed)
+		}
+		if len(encoded) != 1 {
+			return round.WrapError(errors.New("length of de-commitment should be 1"), Pj)
+		}
+
+		Rj, err := crypto.NewECPointFromBytes(round.Params().EC(), encoded[0].Bytes())
+		if err != nil {
+			return round.WrapError(errors2.Wrapf(err, "NewECPoint(Rj)"), Pj)
+		}
+		proof, err := r2msg.UnmarshalZKProof(round.Params().EC())
+		if err != nil {
+			return round.WrapError(errors.New("failed to unmarshal Rj proof"), Pj)
+		}
+		ok = proof.Verify(ContextJ, Rj)
+		if !ok {
+			return round.WrapError(errors.New("failed to prove Rj"), Pj).WithCode(tss.ErrProofVerifyFailed)
+		}
+
+		if R, err = R.Add(Rj); err != nil {
+			return round.WrapError(errors2.Wrapf(err, "R.Add(Rj)"), Pj)
+		}
+	}
+
+	// 7. BIP340 requires an even-Y nonce point; if R is odd every party negates its nonce share
+	modN := common.ModInt(round.Params().EC().Params().N)
+	ki := round.temp.ri
+	if isOdd(R.Y()) {
+		ki = modN.Sub(big.NewInt(0), ki)
+	}
+
+	// 8. e = H_challenge(R.x || P.x || m)
+	e := new(big.Int).Mod(challenge(R.X(), round.temp.pubKey, round.temp.m), round.Params().EC().Params().N)
+
+	// 9. si = ki + e * wi
+	si := modN.Add(ki, modN.Mul(e, round.temp.wi))
+
+	// 10. store r3 message pieces
+	round.temp.si = si
+	round.temp.r = R.X()
+
+	// 11. broadcast si to other parties
+	r3msg := NewSignRound3Message(round.PartyID(), si)
+	round.temp.signRound3Messages[i] = r3msg
+	round.SendMessage(round.out, r3msg)
+
+	return nil
+}
+
+func (round *round3) Update() (bool, *tss.Error) {
+	for j, msg := range round.temp.signRound3Messages {
+		if round.ok[j] {
+			continue
+		}
+		if msg == nil || !round.CanAccept(msg) {
+			return false, nil
+		}
+		round.ok[j] = true
+	}
+	return true, nil
+}
+
+func (round *round3) CanAccept(msg tss.ParsedMessage) bool {
+	if _, ok := msg.Content().(*SignRound3Message); ok {
+		return msg.IsBroadcast()
+	}
+	return false
+}
+
+func (round *round3) NextRound() tss.Round {
+	round.started = false
+	return &finalization{round}
+}
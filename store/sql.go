@@ -0,0 +1,117 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// Placeholders are the bind parameters of the SQL dialect of a database
+type Placeholders int
+
+const (
+	// QuestionPlaceholders are the `?` of e.g. SQLite and MySQL
+	QuestionPlaceholders Placeholders = iota
+	// DollarPlaceholders are the `$1` of e.g. PostgreSQL
+	DollarPlaceholders
+)
+
+var tableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// SQLStore is a Store in a table of a database/sql database, opened with the driver of the database. The table must
+// have been created with a unique key over (key_id, party_id), e.g. in PostgreSQL:
+//
+//	CREATE TABLE shares (
+//	    key_id   VARCHAR(255) NOT NULL,
+//	    party_id VARCHAR(255) NOT NULL,
+//	    data     BYTEA NOT NULL,
+//	    PRIMARY KEY (key_id, party_id)
+//	);
+//
+// The statements are plain SQL, so that the store runs on any database with a driver.
+type SQLStore struct {
+	db                        *sql.DB
+	get, list, delete, insert string
+}
+
+var _ Store = (*SQLStore)(nil)
+
+// NewSQLStore returns a SQLStore in the table `table` of `db`
+func NewSQLStore(db *sql.DB, table string, placeholders Placeholders) (*SQLStore, error) {
+	if !tableName.MatchString(table) {
+		return nil, fmt.Errorf("store: invalid table name %q", table)
+	}
+	p := func(i int) string {
+		if placeholders == DollarPlaceholders {
+			return fmt.Sprintf("$%d", i)
+		}
+		return "?"
+	}
+	return &SQLStore{
+		db:     db,
+		get:    fmt.Sprintf("SELECT data FROM %s WHERE key_id = %s AND party_id = %s", table, p(1), p(2)),
+		list:   fmt.Sprintf("SELECT party_id FROM %s WHERE key_id = %s ORDER BY party_id", table, p(1)),
+		delete: fmt.Sprintf("DELETE FROM %s WHERE key_id = %s AND party_id = %s", table, p(1), p(2)),
+		insert: fmt.Sprintf("INSERT INTO %s (key_id, party_id, data) VALUES (%s, %s, %s)", table, p(1), p(2), p(3)),
+	}, nil
+}
+
+// Put replaces the row of the key and the party in a transaction, rather than with an upsert, which the dialects
+// of SQL spell differently
+func (s *SQLStore) Put(ctx context.Context, keyID, partyID string, data []byte) error {
+	if err := validateIDs(keyID, partyID); err != nil {
+		return err
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if _, err = tx.ExecContext(ctx, s.delete, keyID, partyID); err == nil {
+		_, err = tx.ExecContext(ctx, s.insert, keyID, partyID, data)
+	}
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *SQLStore) Get(ctx context.Context, keyID, partyID string) ([]byte, error) {
+	if err := validateIDs(keyID, partyID); err != nil {
+		return nil, err
+	}
+	var data []byte
+	err := s.db.QueryRowContext(ctx, s.get, keyID, partyID).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return data, err
+}
+
+func (s *SQLStore) List(ctx context.Context, keyID string) ([]string, error) {
+	if err := validateIDs(keyID); err != nil {
+		return nil, err
+	}
+	rows, err := s.db.QueryContext(ctx, s.list, keyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var partyIDs []string
+	for rows.Next() {
+		var partyID string
+		if err = rows.Scan(&partyID); err != nil {
+			return nil, err
+		}
+		partyIDs = append(partyIDs, partyID)
+	}
+	return partyIDs, rows.Err()
+}
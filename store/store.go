@@ -0,0 +1,165 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package store persists the save data of parties, e.g. the JSON encoding of a keygen.LocalPartySaveData, by the id
+// of the key and the id of the party that holds the share, so that a host of several parties, or of several keys,
+// keeps them apart.
+//
+// The save data holds the secret share of the party. The stores write it as given: seal it first, e.g. with the
+// keystore package, unless the storage is encrypted at rest.
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ErrNotFound is returned by Get when the store has no save data for the key and the party
+var ErrNotFound = errors.New("store: save data not found")
+
+// Store keeps the save data of parties by key id and party id. A Put replaces the save data of the key and the party,
+// e.g. after a resharing. The implementations are safe for concurrent use.
+type Store interface {
+	Put(ctx context.Context, keyID, partyID string, data []byte) error
+	// Get returns ErrNotFound if there is no save data for the key and the party
+	Get(ctx context.Context, keyID, partyID string) ([]byte, error)
+	// List returns the ids of the parties with save data for the key, sorted
+	List(ctx context.Context, keyID string) ([]string, error)
+}
+
+func validateIDs(ids ...string) error {
+	for _, id := range ids {
+		if id == "" {
+			return errors.New("store: empty key id or party id")
+		}
+	}
+	return nil
+}
+
+// FileStore is a Store in a directory, with a file for the save data of every party in a directory for every key
+type FileStore struct {
+	dir string
+}
+
+var _ Store = (*FileStore)(nil)
+
+// NewFileStore returns a FileStore in `dir`, which it creates if it does not exist
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+const fileSuffix = ".json"
+
+// fileName escapes an id into a file name that cannot be a path, nor collide with the name of another id
+func fileName(id string) string {
+	var b strings.Builder
+	for i := 0; i < len(id); i++ {
+		c := id[i]
+		if ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9') || c == '-' || c == '_' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func unescapeFileName(name string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(name); i++ {
+		if name[i] != '%' {
+			b.WriteByte(name[i])
+			continue
+		}
+		var c byte
+		if i+2 >= len(name) {
+			return "", fmt.Errorf("store: invalid file name %s", name)
+		}
+		if _, err := fmt.Sscanf(name[i+1:i+3], "%02X", &c); err != nil {
+			return "", fmt.Errorf("store: invalid file name %s", name)
+		}
+		b.WriteByte(c)
+		i += 2
+	}
+	return b.String(), nil
+}
+
+func (fs *FileStore) path(keyID, partyID string) string {
+	return filepath.Join(fs.dir, fileName(keyID), fileName(partyID)+fileSuffix)
+}
+
+// Put writes the save data to a temporary file that it then renames, so that a crash leaves the previous save data
+// or the new one, never a part of it
+func (fs *FileStore) Put(_ context.Context, keyID, partyID string, data []byte) error {
+	if err := validateIDs(keyID, partyID); err != nil {
+		return err
+	}
+	path := fs.path(keyID, partyID)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), ".put-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err = f.Write(data); err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+func (fs *FileStore) Get(_ context.Context, keyID, partyID string) ([]byte, error) {
+	if err := validateIDs(keyID, partyID); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(fs.path(keyID, partyID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return data, err
+}
+
+func (fs *FileStore) List(_ context.Context, keyID string) ([]string, error) {
+	if err := validateIDs(keyID); err != nil {
+		return nil, err
+	}
+	files, err := os.ReadDir(filepath.Join(fs.dir, fileName(keyID)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var partyIDs []string
+	for _, f := range files {
+		name := f.Name()
+		if f.IsDir() || strings.HasPrefix(name, ".") || !strings.HasSuffix(name, fileSuffix) {
+			continue
+		}
+		partyID, err := unescapeFileName(strings.TrimSuffix(name, fileSuffix))
+		if err != nil {
+			return nil, err
+		}
+		partyIDs = append(partyIDs, partyID)
+	}
+	sort.Strings(partyIDs)
+	return partyIDs, nil
+}
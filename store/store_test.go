@@ -0,0 +1,165 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package store_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/kisdex/mpc-lib/store"
+)
+
+func testStore(t *testing.T, s Store) {
+	ctx := context.Background()
+	_, err := s.Get(ctx, "key-1", "1")
+	assert.ErrorIs(t, err, ErrNotFound)
+	partyIDs, err := s.List(ctx, "key-1")
+	assert.NoError(t, err)
+	assert.Empty(t, partyIDs)
+
+	assert.NoError(t, s.Put(ctx, "key-1", "2", []byte("share 2")))
+	assert.NoError(t, s.Put(ctx, "key-1", "1", []byte("share 1")))
+	assert.NoError(t, s.Put(ctx, "key-2", "1", []byte("share of key 2")))
+	// ids are names, not paths
+	assert.NoError(t, s.Put(ctx, "../key-1", "1/../2", []byte("other")))
+
+	data, err := s.Get(ctx, "key-1", "1")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("share 1"), data)
+	assert.NoError(t, s.Put(ctx, "key-1", "1", []byte("reshared 1")))
+	data, err = s.Get(ctx, "key-1", "1")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("reshared 1"), data)
+
+	partyIDs, err = s.List(ctx, "key-1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1", "2"}, partyIDs)
+	partyIDs, err = s.List(ctx, "../key-1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1/../2"}, partyIDs)
+	data, err = s.Get(ctx, "../key-1", "1/../2")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("other"), data)
+
+	assert.Error(t, s.Put(ctx, "", "1", []byte("share")))
+}
+
+func TestFileStore(t *testing.T) {
+	s, err := NewFileStore(t.TempDir())
+	if assert.NoError(t, err) {
+		testStore(t, s)
+	}
+}
+
+func TestSQLStore(t *testing.T) {
+	db := sql.OpenDB(&memConnector{rows: make(map[[2]string][]byte)})
+	defer db.Close()
+	_, err := NewSQLStore(db, "shares; DROP TABLE shares", QuestionPlaceholders)
+	assert.Error(t, err)
+	s, err := NewSQLStore(db, "shares", DollarPlaceholders)
+	if assert.NoError(t, err) {
+		testStore(t, s)
+	}
+}
+
+// memConnector is a database/sql driver of a single table in memory, which runs the statements of SQLStore only
+type memConnector struct {
+	mtx  sync.Mutex
+	rows map[[2]string][]byte
+}
+
+type (
+	memConn struct{ db *memConnector }
+	memStmt struct {
+		conn  *memConn
+		query string
+	}
+	memRows struct {
+		cols   []string
+		values [][]driver.Value
+	}
+	memTx struct{ conn *memConn }
+)
+
+func (c *memConnector) Connect(context.Context) (driver.Conn, error) { return &memConn{db: c}, nil }
+func (c *memConnector) Driver() driver.Driver                        { return nil }
+
+func (c *memConn) Prepare(query string) (driver.Stmt, error) {
+	return &memStmt{conn: c, query: query}, nil
+}
+func (c *memConn) Close() error              { return nil }
+func (c *memConn) Begin() (driver.Tx, error) { return memTx{conn: c}, nil }
+
+// the statements of a transaction apply at once; this is enough for a test with no concurrent writers
+func (memTx) Commit() error   { return nil }
+func (memTx) Rollback() error { return nil }
+
+func (s *memStmt) Close() error  { return nil }
+func (s *memStmt) NumInput() int { return -1 }
+
+func (s *memStmt) Exec(args []driver.Value) (driver.Result, error) {
+	db := s.conn.db
+	db.mtx.Lock()
+	defer db.mtx.Unlock()
+	switch s.query {
+	case "DELETE FROM shares WHERE key_id = $1 AND party_id = $2":
+		delete(db.rows, [2]string{args[0].(string), args[1].(string)})
+	case "INSERT INTO shares (key_id, party_id, data) VALUES ($1, $2, $3)":
+		key := [2]string{args[0].(string), args[1].(string)}
+		if _, ok := db.rows[key]; ok {
+			return nil, fmt.Errorf("duplicate key %v", key)
+		}
+		db.rows[key] = append([]byte{}, args[2].([]byte)...)
+	default:
+		return nil, fmt.Errorf("unexpected statement %s", s.query)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *memStmt) Query(args []driver.Value) (driver.Rows, error) {
+	db := s.conn.db
+	db.mtx.Lock()
+	defer db.mtx.Unlock()
+	switch s.query {
+	case "SELECT data FROM shares WHERE key_id = $1 AND party_id = $2":
+		rows := &memRows{cols: []string{"data"}}
+		if data, ok := db.rows[[2]string{args[0].(string), args[1].(string)}]; ok {
+			rows.values = append(rows.values, []driver.Value{data})
+		}
+		return rows, nil
+	case "SELECT party_id FROM shares WHERE key_id = $1 ORDER BY party_id":
+		rows := &memRows{cols: []string{"party_id"}}
+		for key := range db.rows {
+			if key[0] == args[0].(string) {
+				rows.values = append(rows.values, []driver.Value{key[1]})
+			}
+		}
+		sort.Slice(rows.values, func(i, j int) bool { return rows.values[i][0].(string) < rows.values[j][0].(string) })
+		return rows, nil
+	default:
+		return nil, fmt.Errorf("unexpected query %s", s.query)
+	}
+}
+
+func (r *memRows) Columns() []string { return r.cols }
+func (r *memRows) Close() error      { return nil }
+func (r *memRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
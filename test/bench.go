@@ -0,0 +1,108 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package test
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+var _ tss.Metrics = (*BenchmarkMetrics)(nil)
+
+// FixturesDirEnv names the environment variable that the benchmarks read a directory of fixtures written by
+// cmd/mpc-fixtures from, to scale beyond the TestParticipants parties of the checked-in fixtures
+const FixturesDirEnv = "MPC_FIXTURES_DIR"
+
+type (
+	// BenchmarkSize is a committee that a benchmark runs a protocol with
+	BenchmarkSize struct {
+		Parties, Threshold int
+	}
+
+	// BenchmarkMetrics implements tss.Metrics for the parties of a benchmark: it adds up the time that each round
+	// takes at every party and the bytes of the messages that they send, to report them with Report
+	BenchmarkMetrics struct {
+		mtx      sync.Mutex
+		rounds   map[int]time.Duration
+		messages map[string]int
+		parties  int
+	}
+)
+
+// BenchmarkSizes returns the committees of 2 to `maxParties` parties, each with the threshold of an honest majority
+// and with the highest threshold
+func BenchmarkSizes(maxParties int) []BenchmarkSize {
+	sizes := make([]BenchmarkSize, 0, 2*maxParties)
+	for n := 2; n <= maxParties; n++ {
+		if t := (n - 1) / 2; 0 < t && t < n-1 {
+			sizes = append(sizes, BenchmarkSize{n, t})
+		}
+		sizes = append(sizes, BenchmarkSize{n, n - 1})
+	}
+	return sizes
+}
+
+func (size BenchmarkSize) String() string {
+	return fmt.Sprintf("n=%d,t=%d", size.Parties, size.Threshold)
+}
+
+// NewBenchmarkMetrics returns the metrics of a benchmark whose runs each have `parties` parties
+func NewBenchmarkMetrics(parties int) *BenchmarkMetrics {
+	return &BenchmarkMetrics{
+		rounds:   make(map[int]time.Duration),
+		messages: make(map[string]int),
+		parties:  parties,
+	}
+}
+
+func (m *BenchmarkMetrics) ObserveRound(_ string, round int, elapsed time.Duration) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.rounds[round] += elapsed
+}
+
+func (m *BenchmarkMetrics) ObserveProof(string, time.Duration, bool) {}
+
+func (m *BenchmarkMetrics) ObserveMessage(msgType string, outgoing bool, size int) {
+	if !outgoing {
+		return
+	}
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.messages[msgType] += size
+}
+
+func (m *BenchmarkMetrics) ObserveFailure(string, int, tss.ErrorCode) {}
+
+func (m *BenchmarkMetrics) ObserveBuffer(string, bool) {}
+
+// Report reports the mean time of each round at a party and the bytes sent in each type of message per run of `b`.
+// The time of a round runs from its start to the arrival of its last message, so it includes the wait for the peers.
+func (m *BenchmarkMetrics) Report(b *testing.B) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	runs := float64(b.N)
+	for round, elapsed := range m.rounds {
+		b.ReportMetric(float64(elapsed.Nanoseconds())/runs/float64(m.parties), fmt.Sprintf("ns/round-%d", round))
+	}
+	total := 0
+	types := make([]string, 0, len(m.messages))
+	for msgType, size := range m.messages {
+		types, total = append(types, msgType), total+size
+	}
+	sort.Strings(types)
+	for _, msgType := range types {
+		b.ReportMetric(float64(m.messages[msgType])/runs, "B/"+msgType[strings.LastIndex(msgType, ".")+1:])
+	}
+	b.ReportMetric(float64(total)/runs, "msg-B/op")
+}
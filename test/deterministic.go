@@ -0,0 +1,120 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package test
+
+import (
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+// RunSequentially runs the protocol of `parties` on the calling goroutine: it starts them in order, then delivers the
+// messages that they send on `outCh` one at a time in the order they were sent, until none are left. The parties must
+// be indexed by the Index of their PartyID and `outCh` must buffer the messages that a party sends in one step.
+//
+// It returns the wire bytes of the messages in the order they were sent, so that two runs can be compared. Together
+// with common.NewDeterministicReader, in a build with the tss_deterministic tag, it makes a run reproducible byte for
+// byte.
+func RunSequentially(parties []tss.Party, outCh <-chan tss.Message) ([][]byte, *tss.Error) {
+	return runSequentially(parties, outCh, func(msg tss.Message) []tss.Party {
+		return recipients(parties, msg)
+	})
+}
+
+// RunResharingSequentially is RunSequentially for a resharing from `oldCommittee` to `newCommittee`; the new parties
+// are started first so that they are waiting for the messages of the old ones. A party of both committees is in both.
+func RunResharingSequentially(oldCommittee, newCommittee []tss.Party, outCh <-chan tss.Message) ([][]byte, *tss.Error) {
+	parties := uniqueParties(append(append(make([]tss.Party, 0, len(oldCommittee)+len(newCommittee)), newCommittee...), oldCommittee...))
+	return runSequentially(parties, outCh, func(msg tss.Message) []tss.Party {
+		dest := msg.GetTo()
+		toOld, toNew := dest[:0], dest
+		if msg.IsToOldAndNewCommittees() {
+			// the old committee comes first
+			toOld, toNew = dest[:len(oldCommittee)], dest[len(oldCommittee):]
+		} else if msg.IsToOldCommittee() {
+			toOld, toNew = dest, dest[:0]
+		}
+		to := make([]tss.Party, 0, len(dest))
+		for _, Pj := range toOld {
+			to = append(to, oldCommittee[Pj.Index])
+		}
+		for _, Pj := range toNew {
+			to = append(to, newCommittee[Pj.Index])
+		}
+		return uniqueParties(to)
+	})
+}
+
+// recipients are the parties that `msg` is sent to, the parties being indexed by the Index of their PartyID
+func recipients(parties []tss.Party, msg tss.Message) []tss.Party {
+	dest := msg.GetTo()
+	if dest == nil {
+		to := make([]tss.Party, 0, len(parties)-1)
+		for _, P := range parties {
+			if P.PartyID().Index != msg.GetFrom().Index {
+				to = append(to, P)
+			}
+		}
+		return to
+	}
+	to := make([]tss.Party, 0, len(dest))
+	for _, Pj := range dest {
+		to = append(to, parties[Pj.Index])
+	}
+	return to
+}
+
+// uniqueParties drops the repeats of a party of both committees, which is in both slices
+func uniqueParties(parties []tss.Party) []tss.Party {
+	seen := make(map[tss.Party]struct{}, len(parties))
+	unique := parties[:0]
+	for _, P := range parties {
+		if _, ok := seen[P]; !ok {
+			seen[P] = struct{}{}
+			unique = append(unique, P)
+		}
+	}
+	return unique
+}
+
+func runSequentially(parties []tss.Party, outCh <-chan tss.Message, route func(tss.Message) []tss.Party) ([][]byte, *tss.Error) {
+	queue := make([]tss.Message, 0, len(parties))
+	drain := func() {
+		for {
+			select {
+			case msg := <-outCh:
+				queue = append(queue, msg)
+			default:
+				return
+			}
+		}
+	}
+	for _, P := range parties {
+		if err := P.Start(); err != nil {
+			return nil, err
+		}
+		drain()
+	}
+	transcript := make([][]byte, 0, len(queue))
+	for ; 0 < len(queue); queue = queue[1:] {
+		msg := queue[0]
+		bz, _, err := msg.WireBytes()
+		if err != nil {
+			return nil, tss.NewError(err, "", -1, nil, msg.GetFrom())
+		}
+		transcript = append(transcript, bz)
+		for _, P := range route(msg) {
+			pMsg, err := tss.ParseWireMessage(bz, msg.GetFrom(), msg.IsBroadcast())
+			if err != nil {
+				return nil, P.WrapError(err)
+			}
+			if _, err := P.Update(pMsg); err != nil {
+				return nil, err
+			}
+			drain()
+		}
+	}
+	return transcript, nil
+}
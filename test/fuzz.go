@@ -0,0 +1,53 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package test
+
+import (
+	"crypto/elliptic"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+var curveType = reflect.TypeOf((*elliptic.Curve)(nil)).Elem()
+
+// FuzzWireMessages fuzzes tss.ParseWireMessage with the messages of `transcript` as seeds, e.g. from RunSequentially.
+// As a party does, it decodes a message that passes ValidateBasic with each Unmarshal method of its content, passing
+// `ec` to those that take a curve; the input comes from peers, so none of them may panic.
+func FuzzWireMessages(f *testing.F, ec elliptic.Curve, transcript [][]byte) {
+	for _, bz := range transcript {
+		f.Add(bz, true)
+	}
+	from := tss.NewPartyID("1", "P[1]", big.NewInt(1))
+	f.Fuzz(func(t *testing.T, wireBytes []byte, isBroadcast bool) {
+		msg, err := tss.ParseWireMessage(wireBytes, from, isBroadcast)
+		if err != nil || !msg.ValidateBasic() {
+			return
+		}
+		UnmarshalContent(msg.Content(), ec)
+	})
+}
+
+// UnmarshalContent calls each Unmarshal method of `content` that takes no argument or a curve, and discards the results
+func UnmarshalContent(content tss.MessageContent, ec elliptic.Curve) {
+	v := reflect.ValueOf(content)
+	for i := 0; i < v.NumMethod(); i++ {
+		if !strings.HasPrefix(v.Type().Method(i).Name, "Unmarshal") {
+			continue
+		}
+		method := v.Method(i)
+		switch in := method.Type(); {
+		case in.NumIn() == 0:
+			method.Call(nil)
+		case in.NumIn() == 1 && in.In(0) == curveType:
+			method.Call([]reflect.Value{reflect.ValueOf(&ec).Elem()})
+		}
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package test
+
+import (
+	"container/heap"
+	"crypto/sha256"
+	"encoding/binary"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/kisdex/mpc-lib/tss"
+)
+
+type (
+	// NetworkConditions are the faults of a simulated network. The fault of every delivery is drawn from the seed and
+	// the delivery alone, i.e. the message, its recipient and how many times it was delivered to it before, so that
+	// the same messages meet the same faults however the goroutines of a test are scheduled.
+	NetworkConditions struct {
+		// Latency delays every message
+		Latency time.Duration
+		// Jitter delays every message by up to this much on top of Latency, so that messages may overtake each other
+		Jitter time.Duration
+		// DropRate is the probability that a message is lost
+		DropRate float64
+		// DuplicateRate is the probability that a message is delivered twice
+		DuplicateRate float64
+		// ReorderRate is the probability that a message is held back until the messages sent after it have arrived
+		ReorderRate float64
+		Seed        int64
+	}
+
+	// Network delivers the messages of parties under NetworkConditions. Its Update is a drop-in for
+	// SharedPartyUpdater that delivers the message after its delay on a goroutine of its own.
+	Network struct {
+		conditions NetworkConditions
+		wg         sync.WaitGroup
+
+		mtx        sync.Mutex
+		deliveries map[[sha256.Size]byte]int
+		stats      NetworkStats
+	}
+
+	// NetworkStats counts the faults that a Network injected
+	NetworkStats struct {
+		Sent, Dropped, Duplicated, Reordered int
+	}
+)
+
+// NewNetwork returns a network with the faults of `conditions`
+func NewNetwork(conditions NetworkConditions) *Network {
+	return &Network{conditions: conditions, deliveries: make(map[[sha256.Size]byte]int)}
+}
+
+// Update sends `msg` to `party` through the network. Errors are reported on `errCh` as with SharedPartyUpdater.
+func (n *Network) Update(party tss.Party, msg tss.Message, errCh chan<- *tss.Error) {
+	if party.PartyID() == msg.GetFrom() {
+		return
+	}
+	bz, _, err := msg.WireBytes()
+	if err != nil {
+		errCh <- party.WrapError(err)
+		return
+	}
+	for _, delay := range n.deliver(bz, party.PartyID()) {
+		n.wg.Add(1)
+		time.AfterFunc(delay, func() {
+			defer n.wg.Done()
+			updateFromWire(party, bz, msg, errCh)
+		})
+	}
+}
+
+// Wait waits for the messages in flight to be delivered
+func (n *Network) Wait() {
+	n.wg.Wait()
+}
+
+// Stats returns the faults that the network has injected so far
+func (n *Network) Stats() NetworkStats {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	return n.stats
+}
+
+// deliver draws the fate of the message `bz` to `to`: the delays of the copies that arrive, none if it is lost
+func (n *Network) deliver(bz []byte, to *tss.PartyID) []time.Duration {
+	c := n.conditions
+	h := sha256.New()
+	_ = binary.Write(h, binary.BigEndian, c.Seed)
+	_, _ = h.Write(to.Key)
+	_, _ = h.Write(bz)
+	var key [sha256.Size]byte
+	h.Sum(key[:0])
+
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	attempt := n.deliveries[key]
+	n.deliveries[key]++
+	rng := rand.New(rand.NewSource(int64(binary.BigEndian.Uint64(key[:8])) + int64(attempt)))
+
+	n.stats.Sent++
+	if rng.Float64() < c.DropRate {
+		n.stats.Dropped++
+		return nil
+	}
+	copies := 1
+	if rng.Float64() < c.DuplicateRate {
+		n.stats.Duplicated++
+		copies = 2
+	}
+	reordered := rng.Float64() < c.ReorderRate
+	if reordered {
+		n.stats.Reordered++
+	}
+	delays := make([]time.Duration, 0, copies)
+	for i := 0; i < copies; i++ {
+		delay := c.Latency
+		if 0 < c.Jitter {
+			delay += time.Duration(rng.Int63n(int64(c.Jitter)))
+		}
+		if reordered {
+			// later than any message that is sent up to a full delay after it
+			delay += 2*(c.Latency+c.Jitter) + time.Millisecond
+		}
+		delays = append(delays, delay)
+	}
+	return delays
+}
+
+// RunOverNetwork is RunSequentially over `network`, on a simulated clock instead of timers: a message arrives at its
+// recipient after the delay that the network gives it, and the messages are delivered in the order of their arrival.
+// A run is as reproducible as with RunSequentially and takes no longer.
+//
+// It returns when no message is left in flight, which with dropped messages may be before the parties have finished.
+// The transcript holds the wire bytes of the messages in the order they were delivered, copies included.
+func RunOverNetwork(network *Network, parties []tss.Party, outCh <-chan tss.Message) ([][]byte, *tss.Error) {
+	var (
+		now      time.Duration
+		inFlight arrivals
+		seq      int
+	)
+	send := func() {
+		for {
+			select {
+			case msg := <-outCh:
+				bz, _, err := msg.WireBytes()
+				if err != nil {
+					continue
+				}
+				for _, P := range recipients(parties, msg) {
+					for _, delay := range network.deliver(bz, P.PartyID()) {
+						heap.Push(&inFlight, &arrival{at: now + delay, seq: seq, to: P, msg: msg, bz: bz})
+						seq++
+					}
+				}
+			default:
+				return
+			}
+		}
+	}
+	for _, P := range parties {
+		if err := P.Start(); err != nil {
+			return nil, err
+		}
+		send()
+	}
+	transcript := make([][]byte, 0, inFlight.Len())
+	for 0 < inFlight.Len() {
+		a := heap.Pop(&inFlight).(*arrival)
+		now = a.at
+		transcript = append(transcript, a.bz)
+		pMsg, err := tss.ParseWireMessage(a.bz, a.msg.GetFrom(), a.msg.IsBroadcast())
+		if err != nil {
+			return nil, a.to.WrapError(err)
+		}
+		if _, err := a.to.Update(pMsg); err != nil {
+			return nil, err
+		}
+		send()
+	}
+	return transcript, nil
+}
+
+type (
+	arrival struct {
+		at  time.Duration
+		seq int // breaks the ties in the order the messages were sent
+		to  tss.Party
+		msg tss.Message
+		bz  []byte
+	}
+
+	// arrivals is a heap of the messages in flight by their arrival
+	arrivals []*arrival
+)
+
+func (a arrivals) Len() int { return len(a) }
+func (a arrivals) Less(i, j int) bool {
+	return a[i].at < a[j].at || (a[i].at == a[j].at && a[i].seq < a[j].seq)
+}
+func (a arrivals) Swap(i, j int)       { a[i], a[j] = a[j], a[i] }
+func (a *arrivals) Push(x interface{}) { *a = append(*a, x.(*arrival)) }
+func (a *arrivals) Pop() interface{} {
+	old := *a
+	last := old[len(old)-1]
+	*a = old[:len(old)-1]
+	return last
+}
@@ -20,6 +20,11 @@ func SharedPartyUpdater(party tss.Party, msg tss.Message, errCh chan<- *tss.Erro
 		errCh <- party.WrapError(err)
 		return
 	}
+	updateFromWire(party, bz, msg, errCh)
+}
+
+// updateFromWire parses the wire bytes `bz` of `msg` and passes them to `party`
+func updateFromWire(party tss.Party, bz []byte, msg tss.Message, errCh chan<- *tss.Error) {
 	pMsg, err := tss.ParseWireMessage(bz, msg.GetFrom(), msg.IsBroadcast())
 	if err != nil {
 		errCh <- party.WrapError(err)
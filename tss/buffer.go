@@ -0,0 +1,161 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"bytes"
+	"fmt"
+)
+
+const (
+	DefaultMaxBufferedPerSender = 32
+	DefaultMaxBuffered          = 256
+)
+
+type (
+	// BufferLimits bounds the messages that a party keeps while they arrive ahead of their round, e.g. from a peer
+	// that has moved on to the next round or before the party has started. Once a limit is reached the oldest
+	// buffered message, of the sender or of all of them, is evicted to make room.
+	BufferLimits struct {
+		// MaxPerSender is the largest number of messages buffered from a single sender
+		MaxPerSender int
+		// MaxTotal is the largest number of messages buffered from all of the senders
+		MaxTotal int
+	}
+
+	// messageBuffer keeps the messages that the current round of a party cannot accept in their order of arrival,
+	// and the digest of the last message received of each sender, type and channel to drop the duplicates
+	messageBuffer struct {
+		msgs []ParsedMessage
+		seen map[string][]byte
+	}
+)
+
+// DefaultBufferLimits returns limits that are above the messages of several rounds of any protocol of this library
+func DefaultBufferLimits() BufferLimits {
+	return BufferLimits{
+		MaxPerSender: DefaultMaxBufferedPerSender,
+		MaxTotal:     DefaultMaxBuffered,
+	}
+}
+
+// BufferLimits returns the limits set with SetBufferLimits, or DefaultBufferLimits
+func (params *Parameters) BufferLimits() BufferLimits {
+	if params.bufferLimits == nil {
+		return DefaultBufferLimits()
+	}
+	return *params.bufferLimits
+}
+
+// SetBufferLimits sets the limits of the messages that the party buffers ahead of their round. A limit below 1 is
+// taken as 1. It must be called before the party is started.
+func (params *Parameters) SetBufferLimits(limits BufferLimits) {
+	limits.MaxPerSender, limits.MaxTotal = max(limits.MaxPerSender, 1), max(limits.MaxTotal, 1)
+	params.bufferLimits = &limits
+}
+
+// add buffers `msg` and returns the messages that it evicts
+func (b *messageBuffer) add(msg ParsedMessage, limits BufferLimits) (evicted []ParsedMessage) {
+	b.msgs = append(b.msgs, msg)
+	from, count := msg.GetFrom(), 0
+	for i := len(b.msgs) - 1; 0 <= i; i-- {
+		if b.msgs[i].GetFrom().KeyInt().Cmp(from.KeyInt()) != 0 {
+			continue
+		}
+		if count++; limits.MaxPerSender < count {
+			evicted = append(evicted, b.msgs[i])
+			b.msgs = append(b.msgs[:i], b.msgs[i+1:]...)
+		}
+	}
+	if over := len(b.msgs) - limits.MaxTotal; 0 < over {
+		evicted = append(evicted, b.msgs[:over]...)
+		b.msgs = append([]ParsedMessage{}, b.msgs[over:]...)
+	}
+	return evicted
+}
+
+// duplicate reports whether `msg` was received before, e.g. delivered again by an at-least-once transport, and
+// otherwise remembers it. A message is identified by its sender, type and channel, so that the bookkeeping is bounded
+// by the messages of the protocol; one that differs from the last received in its place is not a duplicate.
+func (b *messageBuffer) duplicate(msg ParsedMessage) bool {
+	key, digest := seenKey(msg), EchoDigest(msg)
+	if last, ok := b.seen[key]; ok && bytes.Equal(last, digest) {
+		return true
+	}
+	if b.seen == nil {
+		b.seen = make(map[string][]byte)
+	}
+	b.seen[key] = digest
+	return false
+}
+
+// forget forgets an evicted message, so that it is taken again when it is sent again
+func (b *messageBuffer) forget(msg ParsedMessage) {
+	key := seenKey(msg)
+	if last, ok := b.seen[key]; ok && bytes.Equal(last, EchoDigest(msg)) {
+		delete(b.seen, key)
+	}
+}
+
+func seenKey(msg ParsedMessage) string {
+	return fmt.Sprintf("%x/%s/%t", msg.GetFrom().Key, msg.Type(), msg.IsBroadcast())
+}
+
+// take removes the buffered messages that `round` accepts and returns them in their order of arrival
+func (b *messageBuffer) take(round Round) []ParsedMessage {
+	var taken []ParsedMessage
+	kept := b.msgs[:0]
+	for _, msg := range b.msgs {
+		if round.CanAccept(msg) {
+			taken = append(taken, msg)
+		} else {
+			kept = append(kept, msg)
+		}
+	}
+	clear(b.msgs[len(kept):])
+	b.msgs = kept
+	return taken
+}
+
+// drain drops the buffered messages and returns them
+func (b *messageBuffer) drain() []ParsedMessage {
+	msgs := b.msgs
+	b.msgs = nil
+	return msgs
+}
+
+// bufferMessage is called with the party locked to buffer `msg`, which arrived ahead of its round
+func bufferMessage(p Party, msg ParsedMessage) {
+	params := partyParams(p)
+	params.observeBuffer(msg, false)
+	for _, evicted := range p.buffer().add(msg, params.BufferLimits()) {
+		p.logger().Warnf("party %s: evicted the buffered message %s", params.PartyID(), evicted)
+		p.buffer().forget(evicted)
+		params.observeBuffer(evicted, true)
+	}
+}
+
+// replayBuffered is called with the party locked once a round has started: it stores the buffered messages that the
+// round accepts, and returns the last one, with which the party must be updated to process them, or nil
+func replayBuffered(p Party) (ParsedMessage, *Error) {
+	round := p.round()
+	if round == nil {
+		// the party has finished, and no round will accept the messages
+		for _, msg := range p.buffer().drain() {
+			p.FirstRound().Params().observeBuffer(msg, true)
+		}
+		return nil, nil
+	}
+	var last ParsedMessage
+	for _, msg := range p.buffer().take(round) {
+		if ok, err := p.StoreMessage(msg); err != nil || !ok {
+			return nil, invalidMessage(err, msg)
+		}
+		last = msg
+	}
+	return last, nil
+}
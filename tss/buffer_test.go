@@ -0,0 +1,121 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/eddsa/keygen"
+	"github.com/kisdex/mpc-lib/test"
+	. "github.com/kisdex/mpc-lib/tss"
+)
+
+func TestBufferedMessages(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(test.TestParticipants)
+	errCh := make(chan *Error, len(pIDs))
+	outCh := make(chan Message, len(pIDs)*len(pIDs)*3)
+	endCh := make(chan *keygen.LocalPartySaveData, len(pIDs))
+	metrics := &recordingMetrics{proofs: make(map[string]int)}
+	parties := newKeygenParties(pIDs, outCh, endCh, func(params *Parameters) {
+		if params.PartyID().Index == 0 {
+			params.SetMetrics(metrics)
+			params.SetBufferLimits(BufferLimits{MaxPerSender: 1, MaxTotal: 1})
+		}
+	})
+
+	// party 0 starts late: the round 1 messages of its peers wait for it, and the second one evicts the first one from
+	// the buffer of a single message, which the party has stored as it has not started
+	for _, P := range parties[1:] {
+		if !assert.Nil(t, P.Start()) {
+			return
+		}
+	}
+	first, second := <-outCh, <-outCh
+	test.SharedPartyUpdater(parties[0], first, errCh)
+	test.SharedPartyUpdater(parties[0], second, errCh)
+	assert.Equal(t, 2, metrics.buffered)
+	assert.Equal(t, 1, metrics.evicted)
+	for _, msg := range []Message{first, second} {
+		test.SharedPartyUpdater(parties[3-msg.GetFrom().Index], msg, errCh)
+	}
+	if !assert.Nil(t, parties[0].Start()) {
+		return
+	}
+
+	for len(endCh) < len(pIDs) {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+		case msg := <-outCh:
+			for _, P := range parties {
+				if P.PartyID().Index == msg.GetFrom().Index {
+					continue
+				}
+				if dest := msg.GetTo(); dest != nil && dest[0].Index != P.PartyID().Index {
+					continue
+				}
+				test.SharedPartyUpdater(P, msg, errCh)
+			}
+		case <-time.After(5 * time.Second):
+			assert.FailNow(t, "keygen is stuck")
+		}
+	}
+	assert.Empty(t, metrics.failures)
+}
+
+func TestDuplicateMessages(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(test.TestParticipants)
+	errCh := make(chan *Error, len(pIDs))
+	outCh := make(chan Message, len(pIDs)*len(pIDs)*3)
+	endCh := make(chan *keygen.LocalPartySaveData, len(pIDs))
+	metrics := &recordingMetrics{proofs: make(map[string]int)}
+	parties := newKeygenParties(pIDs, outCh, endCh, func(params *Parameters) {
+		if params.PartyID().Index == 0 {
+			params.SetMetrics(metrics)
+		}
+	})
+	for _, P := range parties {
+		if !assert.Nil(t, P.Start()) {
+			return
+		}
+	}
+
+	// an at-least-once transport delivers every message twice, and again once the parties have finished
+	var sent []Message
+	deliver := func(msg Message) {
+		for _, P := range parties {
+			if P.PartyID().Index == msg.GetFrom().Index {
+				continue
+			}
+			if dest := msg.GetTo(); dest != nil && dest[0].Index != P.PartyID().Index {
+				continue
+			}
+			test.SharedPartyUpdater(P, msg, errCh)
+			test.SharedPartyUpdater(P, msg, errCh)
+		}
+	}
+	for len(endCh) < len(pIDs) {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+		case msg := <-outCh:
+			sent = append(sent, msg)
+			deliver(msg)
+		case <-time.After(5 * time.Second):
+			assert.FailNow(t, "keygen is stuck")
+		}
+	}
+	for _, msg := range sent {
+		deliver(msg)
+	}
+	assert.Empty(t, errCh)
+	assert.Empty(t, metrics.failures)
+	assert.Zero(t, metrics.buffered, "a duplicate of a message of a past round is not buffered")
+}
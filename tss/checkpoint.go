@@ -0,0 +1,158 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// CheckpointVersion is the format version of the checkpoints written by this release
+const CheckpointVersion = 1
+
+type (
+	// Checkpoint is the state of a running party between two updates: the round it is in, the messages it has
+	// stored and its protocol-specific data. A party that is resumed from a checkpoint continues from that round;
+	// the messages it had sent before the checkpoint are not sent again.
+	//
+	// A checkpoint holds the secret data of the party, so it must be stored encrypted, e.g. with keystore.Encrypt.
+	Checkpoint struct {
+		Version  uint32              `json:"version"`
+		Task     string              `json:"task"`
+		Round    int                 `json:"round"`
+		OK       []bool              `json:"ok"` // the parties whose messages were verified in the round
+		Messages []CheckpointMessage `json:"messages"`
+		Data     json.RawMessage     `json:"data"` // the output of the party in progress
+		Temp     json.RawMessage     `json:"temp"`
+	}
+
+	// CheckpointMessage is a message stored by a party, in its wire format
+	CheckpointMessage struct {
+		From        int    `json:"from"` // the index of the sender in the parties of the protocol
+		IsBroadcast bool   `json:"is_broadcast"`
+		WireBytes   []byte `json:"wire_bytes"`
+	}
+)
+
+// BaseCheckpoint takes a checkpoint of a running party. `fill` is called with the current round while the party is
+// locked, to add the protocol-specific state to the checkpoint.
+func BaseCheckpoint(p Party, task string, fill func(round Round, cp *Checkpoint) error) (*Checkpoint, error) {
+	p.lock()
+	defer p.unlock()
+	if p.round() == nil {
+		return nil, errors.New("could not take a checkpoint: the party is not running")
+	}
+	cp := &Checkpoint{
+		Version: CheckpointVersion,
+		Task:    task,
+		Round:   p.round().RoundNumber(),
+	}
+	if err := fill(p.round(), cp); err != nil {
+		return nil, err
+	}
+	return cp, nil
+}
+
+// BaseResume resumes a party that was constructed like the one the checkpoint was taken of, in place of Start.
+// `restore` is called with the round of the checkpoint to restore the protocol-specific state, including the number
+// and the started flag of the round; the round then processes the stored messages and the party proceeds as far as
+// they allow.
+func BaseResume(p Party, task string, cp *Checkpoint, restore func(round Round) *Error) (err *Error) {
+	p.lock()
+	defer p.unlock()
+	defer func() { p.observeFailure(task, err) }()
+	if p.round() != nil {
+		return p.WrapError(errors.New("could not resume. this party is in an unexpected state. use the constructor and Resume()")).WithCode(ErrInvalidState)
+	}
+	if cp.Version != CheckpointVersion {
+		return p.WrapError(fmt.Errorf("unsupported checkpoint version %d; this library supports version %d", cp.Version, CheckpointVersion)).WithCode(ErrInvalidState)
+	}
+	if cp.Task != task {
+		return p.WrapError(fmt.Errorf("the checkpoint was taken of a %s party, not %s", cp.Task, task)).WithCode(ErrInvalidState)
+	}
+	// the rounds are numbered in sequence from 1
+	round := p.FirstRound()
+	for n := 1; round != nil && n < cp.Round; n++ {
+		round = round.NextRound()
+	}
+	if round == nil || cp.Round < 1 {
+		return p.WrapError(fmt.Errorf("the checkpoint has an invalid round number %d", cp.Round)).WithCode(ErrInvalidState)
+	}
+	if err := round.Params().CheckProductionSafety(); err != nil {
+		return round.WrapError(err).WithCode(ErrInvalidState)
+	}
+	if err := p.setRound(round); err != nil {
+		return err
+	}
+	if err := restore(round); err != nil {
+		return err
+	}
+	if round.RoundNumber() != cp.Round {
+		return p.WrapError(fmt.Errorf("the checkpoint was restored in round %d instead of %d", round.RoundNumber(), cp.Round))
+	}
+	p.beginRound(task)
+	p.logger().Infof("party %s: %s round %d resumed", p.PartyID(), task, cp.Round)
+	notifyRoundStart(p, task)
+	for {
+		// the messages that arrived before the party resumed
+		if _, err := replayBuffered(p); err != nil {
+			return err
+		}
+		if _, err := updateRound(p, task, nil); err != nil {
+			return err
+		}
+		if !p.round().CanProceed() {
+			return nil
+		}
+		p.advance()
+		if p.beginRound(task); p.round() == nil {
+			p.logger().Infof("party %s: %s finished!", p.PartyID(), task)
+			_, err := replayBuffered(p)
+			return err
+		}
+		if err := startRound(p, task); err != nil {
+			return err
+		}
+		notifyRoundStart(p, task)
+		p.logger().Infof("party %s: %s round %d started", p.PartyID(), task, p.round().RoundNumber())
+	}
+}
+
+// NewCheckpointMessages encodes the non-nil messages of the message stores of a party
+func NewCheckpointMessages(stores ...[]ParsedMessage) ([]CheckpointMessage, error) {
+	msgs := make([]CheckpointMessage, 0)
+	for _, store := range stores {
+		for _, msg := range store {
+			if msg == nil {
+				continue
+			}
+			bz, _, err := msg.WireBytes()
+			if err != nil {
+				return nil, err
+			}
+			msgs = append(msgs, CheckpointMessage{From: msg.GetFrom().Index, IsBroadcast: msg.IsBroadcast(), WireBytes: bz})
+		}
+	}
+	return msgs, nil
+}
+
+// ParseCheckpointMessages decodes the messages of a checkpoint, whose senders are looked up in `parties`
+func ParseCheckpointMessages(msgs []CheckpointMessage, parties SortedPartyIDs) ([]ParsedMessage, error) {
+	parsed := make([]ParsedMessage, 0, len(msgs))
+	for _, m := range msgs {
+		if m.From < 0 || len(parties) <= m.From {
+			return nil, fmt.Errorf("a message of the checkpoint has an invalid sender index %d", m.From)
+		}
+		msg, err := ParseWireMessage(m.WireBytes, parties[m.From], m.IsBroadcast)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, msg)
+	}
+	return parsed, nil
+}
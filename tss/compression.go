@@ -0,0 +1,133 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+)
+
+const (
+	// CompressionGzip compresses the messages with gzip of the standard library
+	CompressionGzip = "gzip"
+
+	// MinCompressedSize is the size of the smallest message that is compressed; smaller ones, and those that do not
+	// compress, are sent as they are
+	MinCompressedSize = 1024
+)
+
+// Compressor compresses the messages of the wire format, e.g. with zstd, once it is registered with
+// RegisterCompression
+type Compressor interface {
+	Compress(bz []byte) ([]byte, error)
+	// Decompress returns an error rather than more than `limit` bytes
+	Decompress(bz []byte, limit int) ([]byte, error)
+}
+
+var (
+	compressors = map[string]Compressor{
+		CompressionGzip: gzipCompressor{},
+	}
+	// the compressions that NegotiateCompression prefers, most preferred first
+	compressionOrder = []string{CompressionGzip}
+)
+
+// RegisterCompression makes `compressor` available as `name` to SetCompression and ParseWireMessage, preferred by
+// NegotiateCompression over the compressions registered before it.
+// Like tss.RegisterCurve it must be called before any party runs, e.g. in an init function.
+func RegisterCompression(name string, compressor Compressor) {
+	if _, exist := compressors[name]; !exist {
+		compressionOrder = append([]string{name}, compressionOrder...)
+	}
+	compressors[name] = compressor
+}
+
+// SupportedCompressions returns the compressions that this node can decompress, most preferred first.
+// Nodes may exchange these lists to agree on a compression with NegotiateCompression.
+func SupportedCompressions() []string {
+	return append([]string{}, compressionOrder...)
+}
+
+// NegotiateCompression returns the most preferred compression supported by both this node and a peer that supports
+// `peerCompressions`, or "" if they have none in common and must send their messages uncompressed
+func NegotiateCompression(peerCompressions []string) string {
+	for _, c := range compressionOrder {
+		for _, peer := range peerCompressions {
+			if peer == c {
+				return c
+			}
+		}
+	}
+	return ""
+}
+
+// SetCompression compresses the messages that the party sends with `name`, once they are larger than
+// MinCompressedSize, e.g. with the compression returned by NegotiateCompression. The messages are stamped with it
+// when they are sent and WireBytes compresses them; ParseWireMessage decompresses them on the other end.
+// It must be called before the party is started.
+func (params *Parameters) SetCompression(name string) error {
+	if _, ok := compressors[name]; name != "" && !ok {
+		return fmt.Errorf("SetCompression: unknown compression %q; supported compressions are %v", name, compressionOrder)
+	}
+	params.compression = name
+	return nil
+}
+
+// Compression returns the compression set with SetCompression, or "" if the messages are sent uncompressed
+func (params *Parameters) Compression() string {
+	return params.compression
+}
+
+func compress(name string, bz []byte) ([]byte, error) {
+	compressor, ok := compressors[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown compression %q", name)
+	}
+	return compressor.Compress(bz)
+}
+
+func decompress(name string, bz []byte, limit int) ([]byte, error) {
+	compressor, ok := compressors[name]
+	if !ok {
+		return nil, fmt.Errorf("the message uses the compression %q but this library supports %v", name, compressionOrder)
+	}
+	return compressor.Decompress(bz, limit)
+}
+
+// ----- //
+
+type gzipCompressor struct{}
+
+func (gzipCompressor) Compress(bz []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(bz); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (gzipCompressor) Decompress(bz []byte, limit int) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(bz))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	out, err := io.ReadAll(io.LimitReader(r, int64(limit)+1))
+	if err != nil {
+		return nil, err
+	}
+	if limit < len(out) {
+		return nil, fmt.Errorf("the message decompresses to more than %d bytes", limit)
+	}
+	return out, nil
+}
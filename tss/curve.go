@@ -13,18 +13,28 @@ import (
 
 	s256k1 "github.com/btcsuite/btcd/btcec/v2"
 	"github.com/decred/dcrd/dcrec/edwards/v2"
+
+	"github.com/kisdex/mpc-lib/crypto/edwards448"
+	"github.com/kisdex/mpc-lib/crypto/ristretto255"
+	"github.com/kisdex/mpc-lib/crypto/stark"
 )
 
 type CurveName string
 
 const (
-	Secp256k1 CurveName = "secp256k1"
-	Ed25519   CurveName = "ed25519"
+	Secp256k1    CurveName = "secp256k1"
+	Ed25519      CurveName = "ed25519"
+	Ed448        CurveName = "ed448"
+	Ristretto255 CurveName = "ristretto255"
+	P256         CurveName = "p256"
+	P384         CurveName = "p384"
+	Stark        CurveName = "stark"
 )
 
 var (
 	ec       elliptic.Curve
 	registry map[CurveName]elliptic.Curve
+	names    map[reflect.Type]CurveName
 )
 
 // Init default curve (secp256k1)
@@ -32,12 +42,23 @@ func init() {
 	ec = s256k1.S256()
 
 	registry = make(map[CurveName]elliptic.Curve)
-	registry[Secp256k1] = s256k1.S256()
-	registry[Ed25519] = edwards.Edwards()
+	names = make(map[reflect.Type]CurveName)
+	RegisterCurve(Secp256k1, s256k1.S256())
+	RegisterCurve(Ed25519, edwards.Edwards())
+	RegisterCurve(Ed448, edwards448.Edwards448())
+	RegisterCurve(Ristretto255, ristretto255.Ristretto255())
+	RegisterCurve(P256, elliptic.P256())
+	RegisterCurve(P384, elliptic.P384())
+	RegisterCurve(Stark, stark.Stark())
 }
 
+// RegisterCurve registers `curve` as `name`. The first name of a curve is the one that GetCurveName returns, e.g. in
+// saved data; later names are aliases that GetCurveByName still resolves.
 func RegisterCurve(name CurveName, curve elliptic.Curve) {
 	registry[name] = curve
+	if _, exist := names[reflect.TypeOf(curve)]; !exist {
+		names[reflect.TypeOf(curve)] = name
+	}
 }
 
 // return curve, exist(bool)
@@ -51,13 +72,8 @@ func GetCurveByName(name CurveName) (elliptic.Curve, bool) {
 
 // return name, exist(bool)
 func GetCurveName(curve elliptic.Curve) (CurveName, bool) {
-	for name, e := range registry {
-		if reflect.TypeOf(curve) == reflect.TypeOf(e) {
-			return name, true
-		}
-	}
-
-	return "", false
+	name, exist := names[reflect.TypeOf(curve)]
+	return name, exist
 }
 
 // SameCurve returns true if both lhs and rhs are the same known curve
@@ -93,3 +109,19 @@ func S256() elliptic.Curve {
 func Edwards() elliptic.Curve {
 	return edwards.Edwards()
 }
+
+// Edwards448 returns the curve of Ed448
+func Edwards448() elliptic.Curve {
+	return edwards448.Edwards448()
+}
+
+// Ristretto returns the curve of ristretto255, the group of sr25519. Its arithmetic is that of Edwards, but a key
+// generated on it is saved as a ristretto255 key and signed for with sr25519.
+func Ristretto() elliptic.Curve {
+	return ristretto255.Ristretto255()
+}
+
+// StarkCurve returns the STARK curve of StarkNet
+func StarkCurve() elliptic.Curve {
+	return stark.Stark()
+}
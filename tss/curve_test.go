@@ -0,0 +1,78 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss_test
+
+import (
+	"crypto/elliptic"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/eddsa/keygen"
+	. "github.com/kisdex/mpc-lib/tss"
+)
+
+func TestCurveRegistry(t *testing.T) {
+	for name, curve := range map[CurveName]elliptic.Curve{Secp256k1: S256(), Ed25519: Edwards(), Ed448: Edwards448(), Ristretto255: Ristretto(), P256: elliptic.P256(), P384: elliptic.P384(), Stark: StarkCurve()} {
+		registered, ok := GetCurveByName(name)
+		assert.True(t, ok, "%s should be registered", name)
+		assert.Equal(t, curve, registered)
+		got, ok := GetCurveName(curve)
+		assert.True(t, ok)
+		assert.Equal(t, name, got)
+	}
+	assert.False(t, SameCurve(elliptic.P256(), elliptic.P384()))
+	_, ok := GetCurveName(elliptic.P224())
+	assert.False(t, ok, "P-224 is not registered")
+
+	RegisterCurve("nist-p256", elliptic.P256())
+	alias, ok := GetCurveByName("nist-p256")
+	assert.True(t, ok)
+	assert.True(t, SameCurve(alias, elliptic.P256()))
+	name, _ := GetCurveName(elliptic.P256())
+	assert.Equal(t, P256, name, "an alias must not change the name of a curve")
+}
+
+func TestWireCurve(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(2)
+	p2pCtx := NewPeerContext(pIDs)
+	P := keygen.NewLocalParty(NewParameters(Edwards(), p2pCtx, pIDs[0], len(pIDs), 1), nil, nil)
+	out := make(chan Message, 2)
+
+	// a sender on Ed448 stamps its messages with "ed448", which must survive the wire
+	msg := keygen.NewKGRound1Message(pIDs[1], big.NewInt(1))
+	NewParameters(Edwards448(), p2pCtx, pIDs[1], len(pIDs), 1).SendMessage(out, msg)
+	bz, _, err := (<-out).WireBytes()
+	assert.NoError(t, err)
+	parsed, err := ParseWireMessage(bz, pIDs[1], true)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, string(Ed448), parsed.WireMsg().GetCurve())
+
+	_, tssErr := P.Update(parsed)
+	if assert.NotNil(t, tssErr, "a message on another curve must be rejected") {
+		assert.Equal(t, ErrInvalidMessage, tssErr.Code())
+		assert.Equal(t, []*PartyID{pIDs[1]}, tssErr.Culprits())
+		assert.Contains(t, tssErr.Error(), "ed448")
+	}
+
+	msg = keygen.NewKGRound1Message(pIDs[1], big.NewInt(1))
+	NewParameters(Edwards(), p2pCtx, pIDs[1], len(pIDs), 1).SendMessage(out, msg)
+	assert.Equal(t, string(Ed25519), (<-out).WireMsg().GetCurve())
+	ok, tssErr := P.Update(msg)
+	assert.Nil(t, tssErr)
+	assert.True(t, ok, "a message on the same curve must be accepted")
+
+	// messages of older releases have no curve name
+	legacy, err := EncodeWireMessage(parsed, WireVersionLegacy)
+	assert.NoError(t, err)
+	parsed, err = ParseWireMessage(legacy, pIDs[1], true)
+	assert.NoError(t, err)
+	assert.Empty(t, parsed.WireMsg().GetCurve())
+}
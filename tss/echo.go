@@ -0,0 +1,92 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"bytes"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/kisdex/mpc-lib/common"
+)
+
+// Echo broadcast.
+// A "broadcast" message is only sent to every party one by one, so a malicious party may broadcast different values
+// to different peers. To catch this, each party echoes a digest of the broadcasts that it received in a round along
+// with its message of a later round, and every recipient compares the echo with the broadcasts that it received itself.
+
+// EchoContent is the content of a message that carries an echo
+type EchoContent interface {
+	MessageContent
+	GetEcho() [][]byte
+}
+
+// EchoDigest hashes the broadcasts that a single party sent in a round. A missing message hashes as empty.
+func EchoDigest(msgs ...ParsedMessage) []byte {
+	parts := make([][]byte, 0, 2*len(msgs))
+	for _, msg := range msgs {
+		if msg == nil || msg.Content() == nil {
+			parts = append(parts, nil, nil)
+			continue
+		}
+		bz, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg.Content())
+		if err != nil {
+			bz = nil
+		}
+		parts = append(parts, []byte(msg.Type()), bz)
+	}
+	return common.SHA512_256(parts...)
+}
+
+// EchoDigests returns the EchoDigest of every party, given the broadcasts of a round indexed by sender
+func EchoDigests(msgs []ParsedMessage) [][]byte {
+	digests := make([][]byte, len(msgs))
+	for j, msg := range msgs {
+		digests[j] = EchoDigest(msg)
+	}
+	return digests
+}
+
+// EchoCulprits compares the digests echoed by the party `echoer` with the local ones and returns nil if they match.
+// Otherwise either the senders of the mismatched broadcasts equivocated or the echoer lied about what it received;
+// the two cannot be told apart, so the echoer and those senders are all returned as culprits.
+func EchoCulprits(own, echoed [][]byte, echoer *PartyID, senders []*PartyID) []*PartyID {
+	if len(echoed) != len(own) {
+		return []*PartyID{echoer}
+	}
+	var culprits []*PartyID
+	for j := range own {
+		if bytes.Equal(own[j], echoed[j]) {
+			continue
+		}
+		if culprits == nil {
+			culprits = []*PartyID{echoer}
+		}
+		if j < len(senders) && senders[j] != echoer {
+			culprits = append(culprits, senders[j])
+		}
+	}
+	return culprits
+}
+
+// VerifyEchoes checks the echo carried by each of `msgs` against the local digests `own` of the broadcasts of
+// `senders`, and returns the culprits of the first mismatch or nil if every echo matches
+func VerifyEchoes(own [][]byte, msgs []ParsedMessage, senders []*PartyID) []*PartyID {
+	for _, msg := range msgs {
+		if msg == nil {
+			continue
+		}
+		var echoed [][]byte
+		if content, ok := msg.Content().(EchoContent); ok {
+			echoed = content.GetEcho()
+		}
+		if culprits := EchoCulprits(own, echoed, msg.GetFrom(), senders); culprits != nil {
+			return culprits
+		}
+	}
+	return nil
+}
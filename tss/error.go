@@ -7,9 +7,40 @@
 package tss
 
 import (
+	"errors"
 	"fmt"
 )
 
+// ErrorCode classifies an Error so that an orchestrator can decide programmatically whether to retry, to blame the
+// culprits or to abort. An ErrorCode is also an error, so `errors.Is(err, tss.ErrProofVerifyFailed)` reports whether
+// a *tss.Error has that code.
+type ErrorCode int
+
+const (
+	// ErrUnspecified is the code of the errors that were not classified, e.g. internal failures
+	ErrUnspecified ErrorCode = iota
+	// ErrInvalidMessage means that a received message is malformed or does not belong to the protocol
+	ErrInvalidMessage
+	// ErrDuplicateMessage means that a party sent conflicting messages for the same slot of a round, e.g. a broadcast
+	// with different contents to different parties
+	ErrDuplicateMessage
+	// ErrProofVerifyFailed means that a proof, a commitment or a signature share sent by the culprits did not verify
+	ErrProofVerifyFailed
+	// ErrCulpritIdentified means that the culprits misbehaved in some other way
+	ErrCulpritIdentified
+	// ErrInvalidState means that the party was used incorrectly, e.g. started twice; it is not caused by the peers
+	ErrInvalidState
+)
+
+// Evidence shows that a culprit of an Error misbehaved, e.g. with the messages of the culprit that failed a check, so
+// that the other parties or an orchestrator can check the blame for themselves before they exclude the culprit from a
+// retry
+type Evidence interface {
+	// Verify repeats the failed check with the Parameters of any party of the protocol run, and returns the culprit
+	// if it fails again
+	Verify(params *Parameters) (*PartyID, error)
+}
+
 // fundamental is an error that has a message and a stack, but no caller.
 type Error struct {
 	cause    error
@@ -17,12 +48,33 @@ type Error struct {
 	round    int
 	victim   *PartyID
 	culprits []*PartyID
+	code     ErrorCode
+	msgType  string
+	evidence []Evidence
 }
 
 func NewError(err error, task string, round int, victim *PartyID, culprits ...*PartyID) *Error {
 	return &Error{cause: err, task: task, round: round, victim: victim, culprits: culprits}
 }
 
+// WithCode sets the code of the error and returns it
+func (err *Error) WithCode(code ErrorCode) *Error {
+	err.code = code
+	return err
+}
+
+// WithMessageType sets the type of the message that caused the error and returns it
+func (err *Error) WithMessageType(msgType string) *Error {
+	err.msgType = msgType
+	return err
+}
+
+// WithEvidence adds the evidence against the culprits of the error and returns it
+func (err *Error) WithEvidence(evidence ...Evidence) *Error {
+	err.evidence = append(err.evidence, evidence...)
+	return err
+}
+
 func (err *Error) Unwrap() error { return err.cause }
 
 func (err *Error) Cause() error { return err.cause }
@@ -35,6 +87,49 @@ func (err *Error) Victim() *PartyID { return err.victim }
 
 func (err *Error) Culprits() []*PartyID { return err.culprits }
 
+// Evidence returns the evidence against the culprits set with WithEvidence, if the protocol provides any
+func (err *Error) Evidence() []Evidence { return err.evidence }
+
+// MessageType returns the type of the message that caused the error, if known
+func (err *Error) MessageType() string { return err.msgType }
+
+// Code returns the code set with WithCode. Otherwise it is the ErrorCode wrapped by the cause, if any, or
+// ErrCulpritIdentified when the error has culprits.
+func (err *Error) Code() ErrorCode {
+	if err.code != ErrUnspecified {
+		return err.code
+	}
+	var code ErrorCode
+	if errors.As(err.cause, &code) {
+		return code
+	}
+	if len(err.culprits) > 0 {
+		return ErrCulpritIdentified
+	}
+	return ErrUnspecified
+}
+
+// CommonErrorCode returns the code of `errs` if they all have the same one, e.g. to classify an error that aggregates
+// the errors of concurrent verifications, or else ErrUnspecified
+func CommonErrorCode(errs ...*Error) ErrorCode {
+	if len(errs) == 0 {
+		return ErrUnspecified
+	}
+	code := errs[0].Code()
+	for _, err := range errs[1:] {
+		if err.Code() != code {
+			return ErrUnspecified
+		}
+	}
+	return code
+}
+
+// Is reports whether `target` is the code of the error
+func (err *Error) Is(target error) bool {
+	code, ok := target.(ErrorCode)
+	return ok && err.Code() == code
+}
+
 func (err *Error) Error() string {
 	if err == nil || err.cause == nil {
 		return "Error is nil"
@@ -46,3 +141,24 @@ func (err *Error) Error() string {
 	return fmt.Sprintf("task %s, party %v, round %d: %s",
 		err.task, err.victim, err.round, err.cause.Error())
 }
+
+func (code ErrorCode) String() string {
+	switch code {
+	case ErrUnspecified:
+		return "unspecified"
+	case ErrInvalidMessage:
+		return "invalid message"
+	case ErrDuplicateMessage:
+		return "duplicate message"
+	case ErrProofVerifyFailed:
+		return "proof verification failed"
+	case ErrCulpritIdentified:
+		return "culprit identified"
+	case ErrInvalidState:
+		return "invalid state"
+	default:
+		return fmt.Sprintf("error code %d", int(code))
+	}
+}
+
+func (code ErrorCode) Error() string { return code.String() }
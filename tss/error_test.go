@@ -0,0 +1,57 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss_test
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/eddsa/keygen"
+	. "github.com/kisdex/mpc-lib/tss"
+)
+
+func TestErrorCodes(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(2)
+
+	err := NewError(errors.New("internal"), "signing", 1, pIDs[0])
+	assert.Equal(t, ErrUnspecified, err.Code())
+	err = NewError(errors.New("bad share"), "signing", 1, pIDs[0], pIDs[1])
+	assert.Equal(t, ErrCulpritIdentified, err.Code(), "an error with culprits identifies them")
+	err = NewError(fmt.Errorf("verify: %w", ErrProofVerifyFailed), "signing", 2, pIDs[0], pIDs[1])
+	assert.Equal(t, ErrProofVerifyFailed, err.Code(), "the code may be wrapped by the cause")
+	err = NewError(errors.New("bad proof"), "signing", 2, pIDs[0], pIDs[1]).WithCode(ErrProofVerifyFailed).WithMessageType("SignRound2Message")
+	assert.Equal(t, ErrProofVerifyFailed, err.Code())
+	assert.Equal(t, "SignRound2Message", err.MessageType())
+	assert.Equal(t, 2, err.Round())
+
+	var wrapped error = fmt.Errorf("session 7: %w", err)
+	assert.True(t, errors.Is(wrapped, ErrProofVerifyFailed))
+	assert.False(t, errors.Is(wrapped, ErrInvalidMessage))
+
+	assert.Equal(t, ErrProofVerifyFailed, CommonErrorCode(err, err))
+	assert.Equal(t, ErrUnspecified, CommonErrorCode(err, NewError(errors.New("bad share"), "signing", 1, pIDs[0], pIDs[1])))
+}
+
+func TestInvalidMessageCode(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(2)
+	p2pCtx := NewPeerContext(pIDs)
+	params := NewParameters(Edwards(), p2pCtx, pIDs[0], len(pIDs), 1)
+	P := keygen.NewLocalParty(params, nil, nil)
+
+	// an empty commitment does not pass ValidateBasic
+	msg := keygen.NewKGRound1Message(pIDs[1], big.NewInt(0))
+	_, err := P.Update(msg)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, ErrInvalidMessage, err.Code())
+		assert.Equal(t, msg.Type(), err.MessageType())
+		assert.Equal(t, []*PartyID{pIDs[1]}, err.Culprits())
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	DefaultMaxMessageSize = 16 << 20
+	DefaultMaxFieldSize   = 1 << 20
+	DefaultMaxFields      = 1 << 16
+)
+
+// MessageLimits bounds the messages that ParseWireMessage accepts from the peers. They are checked before a message
+// is decoded, so that a malicious peer cannot exhaust the memory of a party with e.g. a big.Int of hundreds of MB.
+type MessageLimits struct {
+	// MaxMessageSize is the size of the largest message, both on the wire and once it is decompressed
+	MaxMessageSize int
+	// MaxFieldSize is the size of the largest bytes field of a message, e.g. a big.Int or a message of a batch
+	MaxFieldSize int
+	// MaxFields is the largest number of fields of a message, counting each entry of a repeated field
+	MaxFields int
+}
+
+var messageLimits = DefaultMessageLimits()
+
+// DefaultMessageLimits returns limits that are far above the size of the messages of any protocol of this library
+func DefaultMessageLimits() MessageLimits {
+	return MessageLimits{
+		MaxMessageSize: DefaultMaxMessageSize,
+		MaxFieldSize:   DefaultMaxFieldSize,
+		MaxFields:      DefaultMaxFields,
+	}
+}
+
+// SetMessageLimits sets the limits of the messages that ParseWireMessage accepts, e.g. lower ones for a signer with
+// little memory. Like tss.RegisterCompression it must be called before any party runs.
+func SetMessageLimits(limits MessageLimits) error {
+	if limits.MaxMessageSize <= 0 || limits.MaxFieldSize <= 0 || limits.MaxFields <= 0 {
+		return fmt.Errorf("SetMessageLimits: the limits must be positive, got %+v", limits)
+	}
+	messageLimits = limits
+	return nil
+}
+
+// GetMessageLimits returns the limits set with SetMessageLimits, or DefaultMessageLimits
+func GetMessageLimits() MessageLimits {
+	return messageLimits
+}
+
+// checkFields walks the encoded message `bz` of type `desc` without decoding it, and returns an error once a bytes
+// field is longer than MaxFieldSize or the message has more than MaxFields fields. `fields` counts the fields of the
+// nested messages too.
+func (limits MessageLimits) checkFields(bz []byte, desc protoreflect.MessageDescriptor, fields *int) error {
+	for 0 < len(bz) {
+		num, typ, n := protowire.ConsumeTag(bz)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		bz = bz[n:]
+		if *fields++; limits.MaxFields < *fields {
+			return fmt.Errorf("the message has more than %d fields", limits.MaxFields)
+		}
+		if typ != protowire.BytesType {
+			if n = protowire.ConsumeFieldValue(num, typ, bz); n < 0 {
+				return protowire.ParseError(n)
+			}
+			bz = bz[n:]
+			continue
+		}
+		v, n := protowire.ConsumeBytes(bz)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		bz = bz[n:]
+		if fd := desc.Fields().ByNumber(num); fd != nil && fd.Kind() == protoreflect.MessageKind {
+			if err := limits.checkFields(v, fd.Message(), fields); err != nil {
+				return err
+			}
+		} else if limits.MaxFieldSize < len(v) {
+			return fmt.Errorf("the field %d of %s has %d bytes, more than the limit of %d",
+				num, desc.FullName(), len(v), limits.MaxFieldSize)
+		}
+	}
+	return nil
+}
+
+// ----- //
+
+// MessageTypes is the allowlist of the messages of a protocol. Its ParseWireMessage rejects any other message
+// before it is decoded, e.g. a message of another protocol that would pass the checks of a round.
+type MessageTypes []MessageContent
+
+// NewMessageTypes returns the allowlist of the messages of `contents`. Their names are resolved once the messages
+// are parsed, so it may initialize a package variable before the Protocol Buffers types are registered.
+func NewMessageTypes(contents ...MessageContent) MessageTypes {
+	return contents
+}
+
+// Contains reports whether the message `name` is in the allowlist
+func (types MessageTypes) Contains(name protoreflect.FullName) bool {
+	for _, content := range types {
+		if proto.MessageName(content) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseWireMessage is tss.ParseWireMessage for a party of the protocol, which accepts only the messages of `types`
+func (types MessageTypes) ParseWireMessage(wireBytes []byte, from *PartyID, isBroadcast bool) (ParsedMessage, error) {
+	if len(types) == 0 {
+		return nil, errors.New("ParseWireMessage: the allowlist of the messages is empty")
+	}
+	return parseWireMessage(wireBytes, from, isBroadcast, types)
+}
@@ -0,0 +1,45 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"time"
+)
+
+// RoundListener is told of the progress of a party, e.g. to drive a progress bar, write structured logs or raise
+// alerts. Unlike Metrics it gets the party and the messages themselves. The methods are called synchronously, some of
+// them with the party locked, so they must return quickly and must not call the party.
+type RoundListener interface {
+	// OnRoundStart is called when a round of `task` has started and sent its messages, or has been resumed
+	OnRoundStart(party *PartyID, task string, round int)
+	// OnRoundComplete is called when a round of `task` has its messages and the party moves on to the next round, with
+	// the time since the round began. The last round of most protocols outputs the result when it starts, and is not
+	// completed.
+	OnRoundComplete(party *PartyID, task string, round int, elapsed time.Duration)
+	// OnMessageSent is called for every message that the party sends
+	OnMessageSent(party *PartyID, msg Message)
+	// OnMessageReceived is called for every message that the party has been updated with
+	OnMessageReceived(party *PartyID, msg ParsedMessage)
+}
+
+// RoundListener returns the listener set with SetRoundListener, or nil
+func (params *Parameters) RoundListener() RoundListener {
+	return params.listener
+}
+
+// SetRoundListener makes the party tell `listener` of its rounds and messages. It must be called before the party
+// is started.
+func (params *Parameters) SetRoundListener(listener RoundListener) {
+	params.listener = listener
+}
+
+// notifyRoundStart is called with the party locked when its current round has started
+func notifyRoundStart(p Party, task string) {
+	if round := p.round(); round != nil && round.Params().listener != nil {
+		round.Params().listener.OnRoundStart(round.Params().PartyID(), task, round.RoundNumber())
+	}
+}
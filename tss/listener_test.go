@@ -0,0 +1,105 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/eddsa/keygen"
+	"github.com/kisdex/mpc-lib/test"
+	. "github.com/kisdex/mpc-lib/tss"
+)
+
+type recordingListener struct {
+	mtx      sync.Mutex
+	party    *PartyID
+	events   []string
+	sent     int
+	received int
+}
+
+func (l *recordingListener) OnRoundStart(party *PartyID, task string, round int) {
+	l.record(party, fmt.Sprintf("%s %d start", task, round))
+}
+
+func (l *recordingListener) OnRoundComplete(party *PartyID, task string, round int, elapsed time.Duration) {
+	l.record(party, fmt.Sprintf("%s %d complete", task, round))
+}
+
+func (l *recordingListener) OnMessageSent(party *PartyID, msg Message) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	l.sent++
+}
+
+func (l *recordingListener) OnMessageReceived(party *PartyID, msg ParsedMessage) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	l.received++
+}
+
+func (l *recordingListener) record(party *PartyID, event string) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	if party == l.party {
+		l.events = append(l.events, event)
+	}
+}
+
+func TestRoundListener(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(test.TestParticipants)
+	errCh := make(chan *Error, len(pIDs))
+	outCh := make(chan Message, len(pIDs)*len(pIDs)*3)
+	endCh := make(chan *keygen.LocalPartySaveData, len(pIDs))
+	listener := &recordingListener{party: pIDs[0]}
+	parties := newKeygenParties(pIDs, outCh, endCh, func(params *Parameters) {
+		if params.PartyID().Index == 0 {
+			params.SetRoundListener(listener)
+		}
+	})
+	for _, P := range parties {
+		if !assert.Nil(t, P.Start()) {
+			return
+		}
+	}
+	for len(endCh) < len(pIDs) {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+		case msg := <-outCh:
+			for _, P := range parties {
+				if P.PartyID().Index == msg.GetFrom().Index {
+					continue
+				}
+				if dest := msg.GetTo(); dest != nil && dest[0].Index != P.PartyID().Index {
+					continue
+				}
+				test.SharedPartyUpdater(P, msg, errCh)
+			}
+		case <-time.After(5 * time.Second):
+			assert.FailNow(t, "keygen is stuck")
+		}
+	}
+
+	others := len(pIDs) - 1
+	// the last round finishes the protocol when it starts, without waiting for messages
+	assert.Equal(t, []string{
+		"eddsa-keygen 1 start",
+		"eddsa-keygen 1 complete",
+		"eddsa-keygen 2 start",
+		"eddsa-keygen 2 complete",
+		"eddsa-keygen 3 start",
+	}, listener.events)
+	// a broadcast in round 1, and in round 2 a broadcast and a message to each peer
+	assert.Equal(t, 2+others, listener.sent)
+	assert.Equal(t, 3*others, listener.received)
+}
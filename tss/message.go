@@ -91,6 +91,7 @@ func NewMessageWrapper(routing MessageRouting, content MessageContent) *MessageW
 		}
 	}
 	return &MessageWrapper{
+		ProtocolVersion:         WireVersion,
 		IsBroadcast:             routing.IsBroadcast,
 		IsToOldCommittee:        routing.IsToOldCommittee,
 		IsToOldAndNewCommittees: routing.IsToOldAndNewCommittees,
@@ -136,8 +137,14 @@ func (mm *MessageImpl) IsToOldAndNewCommittees() bool {
 	return mm.wire.IsToOldAndNewCommittees
 }
 
+// WireBytes encodes the message in the wire format version of its wrapper, which is WireVersion unless it was
+// changed to a version negotiated with the peers
 func (mm *MessageImpl) WireBytes() ([]byte, *MessageRouting, error) {
-	bz, err := proto.Marshal(mm.wire.Message)
+	version := mm.wire.ProtocolVersion
+	if version == 0 {
+		version = WireVersion
+	}
+	bz, err := EncodeWireMessage(mm, version)
 	if err != nil {
 		return nil, nil, err
 	}
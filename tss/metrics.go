@@ -0,0 +1,116 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Metrics receives the measurements of the protocols run by a party, e.g. to export them as Prometheus histograms
+// and counters. The library does not depend on a metrics backend; an implementation typically records each call in
+// a vector labelled by its string and integer arguments. The methods may be called concurrently.
+type Metrics interface {
+	// ObserveRound is called when a round of `task` has its messages and the next one begins, with the time since the
+	// round began
+	ObserveRound(task string, round int, elapsed time.Duration)
+	// ObserveProof is called when a proof or a share sent by a peer has been verified, with the verification time
+	ObserveProof(proof string, elapsed time.Duration, ok bool)
+	// ObserveMessage is called for every message that the party sends or receives, with the size of its encoded
+	// content in bytes
+	ObserveMessage(msgType string, outgoing bool, size int)
+	// ObserveFailure is called when the party returns an error from Start, Update or Resume
+	ObserveFailure(task string, round int, code ErrorCode)
+	// ObserveBuffer is called when a message that arrived ahead of its round is buffered, and again with `evicted`
+	// when it is dropped from the buffer without a round accepting it
+	ObserveBuffer(msgType string, evicted bool)
+}
+
+// Metrics returns the metrics set with SetMetrics, or nil
+func (params *Parameters) Metrics() Metrics {
+	return params.metrics
+}
+
+// SetMetrics makes the party report its measurements to `metrics`. It must be called before the party is started.
+func (params *Parameters) SetMetrics(metrics Metrics) {
+	params.metrics = metrics
+}
+
+// VerifyProof calls `verify` and reports its duration and result as the verification of `proof`, which it also
+// traces in a span
+func (params *Parameters) VerifyProof(proof string, verify func() bool) bool {
+	span := params.startSpan("verify "+proof, Attribute{AttributeProof, proof})
+	ok := VerifyProof(params.metrics, proof, verify)
+	if span != nil {
+		if ok {
+			span.End(nil)
+		} else {
+			span.End(fmt.Errorf("the verification of %s failed", proof))
+		}
+	}
+	return ok
+}
+
+// VerifyProof calls `verify` and reports its duration and result to `metrics`, if it is not nil
+func VerifyProof(metrics Metrics, proof string, verify func() bool) bool {
+	if metrics == nil {
+		return verify()
+	}
+	start := time.Now()
+	ok := verify()
+	metrics.ObserveProof(proof, time.Since(start), ok)
+	return ok
+}
+
+func (params *Parameters) observeMessage(msg Message, outgoing bool) {
+	if params.metrics == nil || msg == nil || msg.WireMsg() == nil {
+		return
+	}
+	params.metrics.ObserveMessage(msg.Type(), outgoing, proto.Size(msg.WireMsg().GetMessage()))
+}
+
+func (params *Parameters) observeBuffer(msg ParsedMessage, evicted bool) {
+	if params.metrics != nil {
+		params.metrics.ObserveBuffer(msg.Type(), evicted)
+	}
+}
+
+// observeRound is called with the party locked when a round is set: it reports the duration of the previous round,
+// and its completion to the listener
+func (p *BaseParty) observeRound(task string) {
+	if p.ended != nil {
+		// the next round has not started yet, so the number of the previous one is still set
+		params, number, elapsed := p.ended.Params(), p.ended.RoundNumber(), time.Since(p.begun)
+		if params.metrics != nil {
+			params.metrics.ObserveRound(task, number, elapsed)
+		}
+		if params.listener != nil {
+			params.listener.OnRoundComplete(params.PartyID(), task, number, elapsed)
+		}
+	}
+	p.ended, p.begun = p.rnd, time.Now()
+}
+
+// observeReceived is called with the party locked when it has been updated with `msg`
+func (p *BaseParty) observeReceived(msg ParsedMessage) {
+	if p.params != nil && msg != nil {
+		p.params.observeMessage(msg, false)
+		if p.params.listener != nil {
+			p.params.listener.OnMessageReceived(p.params.PartyID(), msg)
+		}
+	}
+}
+
+// observeFailure is called with the party locked when it returns `err`
+func (p *BaseParty) observeFailure(task string, err *Error) {
+	if err == nil || p.params == nil || p.params.metrics == nil {
+		return
+	}
+	p.params.metrics.ObserveFailure(task, err.Round(), err.Code())
+}
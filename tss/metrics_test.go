@@ -0,0 +1,129 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss_test
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/eddsa/keygen"
+	"github.com/kisdex/mpc-lib/test"
+	. "github.com/kisdex/mpc-lib/tss"
+)
+
+type recordingMetrics struct {
+	mtx      sync.Mutex
+	rounds   []int
+	proofs   map[string]int
+	sent     int
+	received int
+	failures []ErrorCode
+	buffered int
+	evicted  int
+}
+
+func (m *recordingMetrics) ObserveRound(task string, round int, elapsed time.Duration) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if task == keygen.TaskName && 0 < elapsed {
+		m.rounds = append(m.rounds, round)
+	}
+}
+
+func (m *recordingMetrics) ObserveProof(proof string, elapsed time.Duration, ok bool) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if ok {
+		m.proofs[proof]++
+	}
+}
+
+func (m *recordingMetrics) ObserveMessage(msgType string, outgoing bool, size int) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if size == 0 {
+		return
+	}
+	if outgoing {
+		m.sent++
+	} else {
+		m.received++
+	}
+}
+
+func (m *recordingMetrics) ObserveFailure(task string, round int, code ErrorCode) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.failures = append(m.failures, code)
+}
+
+func (m *recordingMetrics) ObserveBuffer(msgType string, evicted bool) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if evicted {
+		m.evicted++
+	} else {
+		m.buffered++
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(test.TestParticipants)
+	p2pCtx := NewPeerContext(pIDs)
+	errCh := make(chan *Error, len(pIDs))
+	outCh := make(chan Message, len(pIDs)*len(pIDs)*3)
+	endCh := make(chan *keygen.LocalPartySaveData, len(pIDs))
+	metrics := &recordingMetrics{proofs: make(map[string]int)}
+
+	parties := make([]*keygen.LocalParty, len(pIDs))
+	for i := range pIDs {
+		params := NewParameters(Edwards(), p2pCtx, pIDs[i], len(pIDs), test.TestThreshold)
+		if i == 0 {
+			params.SetMetrics(metrics)
+		}
+		parties[i] = keygen.NewLocalParty(params, outCh, endCh).(*keygen.LocalParty)
+	}
+	for _, P := range parties {
+		if !assert.Nil(t, P.Start()) {
+			return
+		}
+	}
+	for len(endCh) < len(pIDs) {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+		case msg := <-outCh:
+			for _, P := range parties {
+				if dest := msg.GetTo(); dest != nil && dest[0].Index != P.PartyID().Index {
+					continue
+				}
+				test.SharedPartyUpdater(P, msg, errCh)
+			}
+		case <-time.After(5 * time.Second):
+			assert.FailNow(t, "keygen is stuck")
+		}
+	}
+
+	others := len(pIDs) - 1
+	// the last round finishes the protocol when it starts, without waiting for messages
+	assert.Equal(t, []int{1, 2}, metrics.rounds)
+	assert.Equal(t, others, metrics.proofs["schnorr"])
+	assert.Equal(t, others, metrics.proofs["vss"])
+	// a broadcast in round 1, and in round 2 a broadcast and a message to each peer
+	assert.Equal(t, 2+others, metrics.sent)
+	assert.Equal(t, 3*others, metrics.received)
+	assert.Empty(t, metrics.failures)
+
+	_, err := parties[0].Update(keygen.NewKGRound1Message(pIDs[1], big.NewInt(0)))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, []ErrorCode{ErrInvalidMessage}, metrics.failures)
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss_test
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/eddsa/keygen"
+	"github.com/kisdex/mpc-lib/test"
+	. "github.com/kisdex/mpc-lib/tss"
+)
+
+func newKeygenParties(pIDs SortedPartyIDs, outCh chan Message, endCh chan *keygen.LocalPartySaveData, configure func(*Parameters)) []Party {
+	p2pCtx := NewPeerContext(pIDs)
+	parties := make([]Party, len(pIDs))
+	for i := range pIDs {
+		params := NewParameters(Edwards(), p2pCtx, pIDs[i], len(pIDs), test.TestThreshold)
+		if configure != nil {
+			configure(params)
+		}
+		parties[i] = keygen.NewLocalParty(params, outCh, endCh)
+	}
+	return parties
+}
+
+func TestKeygenOverReorderingNetwork(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(test.TestParticipants)
+	outCh := make(chan Message, len(pIDs)*len(pIDs))
+	endCh := make(chan *keygen.LocalPartySaveData, len(pIDs))
+	parties := newKeygenParties(pIDs, outCh, endCh, nil)
+
+	network := test.NewNetwork(test.NetworkConditions{
+		Latency:       10 * time.Millisecond,
+		Jitter:        50 * time.Millisecond,
+		DuplicateRate: 0.2,
+		ReorderRate:   0.3,
+		Seed:          1,
+	})
+	transcript, err := test.RunOverNetwork(network, parties, outCh)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Len(t, endCh, len(pIDs), "every party must finish")
+	stats := network.Stats()
+	assert.NotZero(t, stats.Duplicated)
+	assert.NotZero(t, stats.Reordered)
+	assert.Equal(t, stats.Sent+stats.Duplicated, len(transcript))
+}
+
+func TestKeygenOverLossyNetwork(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(test.TestParticipants)
+	errCh := make(chan *Error, len(pIDs))
+	outCh := make(chan Message, len(pIDs)*len(pIDs)*3)
+	endCh := make(chan *keygen.LocalPartySaveData, len(pIDs))
+	network := test.NewNetwork(test.NetworkConditions{
+		Latency:  time.Millisecond,
+		Jitter:   5 * time.Millisecond,
+		DropRate: 0.2,
+		Seed:     2,
+	})
+
+	// the rounds time out, and the test plays the transport that keeps the sent messages and sends them again to the
+	// parties that are still waiting for them, through the same lossy network
+	var timeouts int32
+	parties := newKeygenParties(pIDs, outCh, endCh, func(params *Parameters) {
+		params.SetRoundTimeout(50*time.Millisecond, func(*PartyID, int, []*PartyID) {
+			atomic.AddInt32(&timeouts, 1)
+		})
+	})
+	for _, P := range parties {
+		if !assert.Nil(t, P.Start()) {
+			return
+		}
+	}
+	sent := make(map[int][]Message)
+	send := func(msg Message, P Party) {
+		if dest := msg.GetTo(); dest == nil || dest[0].Index == P.PartyID().Index {
+			go network.Update(P, msg, errCh)
+		}
+	}
+	resend := time.NewTicker(100 * time.Millisecond)
+	defer resend.Stop()
+	stuck := time.After(10 * time.Second)
+	for len(endCh) < len(pIDs) {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+		case msg := <-outCh:
+			sent[msg.GetFrom().Index] = append(sent[msg.GetFrom().Index], msg)
+			for _, P := range parties {
+				send(msg, P)
+			}
+		case <-resend.C:
+			for _, P := range parties {
+				for _, late := range P.(*keygen.LocalParty).WaitingFor() {
+					for _, msg := range sent[late.Index] {
+						send(msg, P)
+					}
+				}
+			}
+		case <-stuck:
+			assert.FailNow(t, "keygen is stuck")
+		}
+	}
+	network.Wait()
+	assert.NotZero(t, network.Stats().Dropped)
+	assert.NotZero(t, atomic.LoadInt32(&timeouts), "the rounds that miss a message must time out")
+}
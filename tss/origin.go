@@ -0,0 +1,118 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/kisdex/mpc-lib/common"
+)
+
+// Proof of origin.
+// The parties rely on the transport to tell them the sender of every message. When the transport cannot be trusted
+// with that, every party gets a long-term ed25519 key: each message that a party sends carries its signature over
+// the message, the session id and the routing, and the recipients only accept the messages that verify against the
+// key of the party that they are from. Messages of the legacy wire format carry no signature.
+
+var originDomain = []byte("tss-origin-v1")
+
+// NewPeerContextWithOriginKeys returns a PeerContext of `parties` in which `originKeys[i]` is the long-term public key
+// of `parties[i]`. The parties of a context with origin keys only accept messages signed with the key of their
+// sender; each party signs its own with the private key set with Parameters.SetOriginKey.
+func NewPeerContextWithOriginKeys(parties SortedPartyIDs, originKeys []ed25519.PublicKey) (*PeerContext, error) {
+	if len(originKeys) != len(parties) {
+		return nil, fmt.Errorf("NewPeerContextWithOriginKeys: %d origin keys for %d parties", len(originKeys), len(parties))
+	}
+	keys := make(map[string]ed25519.PublicKey, len(parties))
+	for i, pID := range parties {
+		if len(originKeys[i]) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("NewPeerContextWithOriginKeys: the origin key of party %s is invalid", pID)
+		}
+		keys[string(pID.Key)] = originKeys[i]
+	}
+	return &PeerContext{partyIDs: parties, originKeys: keys}, nil
+}
+
+// OriginKey returns the origin key of the party with the key of `pID`, or nil
+func (p2pCtx *PeerContext) OriginKey(pID *PartyID) ed25519.PublicKey {
+	return p2pCtx.originKeys[string(pID.Key)]
+}
+
+// HasOriginKeys reports whether the context was built with NewPeerContextWithOriginKeys
+func (p2pCtx *PeerContext) HasOriginKeys() bool {
+	return p2pCtx.originKeys != nil
+}
+
+// SetOriginKey sets the long-term private key with which the party signs the messages that it sends, which must
+// belong to the origin key of the party in its PeerContext. It must be called before the party is started.
+func (params *Parameters) SetOriginKey(key ed25519.PrivateKey) {
+	params.originKey = key
+}
+
+// signOrigin signs the message that the party is sending, if it has an origin key
+func (params *Parameters) signOrigin(msg Message) {
+	wire := msg.WireMsg()
+	if params.originKey == nil || wire == nil || wire.Message == nil {
+		return
+	}
+	var to *PartyID
+	if dest := msg.GetTo(); !msg.IsBroadcast() && len(dest) == 1 {
+		to = dest[0]
+	}
+	wire.Signature = ed25519.Sign(params.originKey, originDigest(params.ssid, msg.GetFrom(), to, msg.IsBroadcast(), wire.Message))
+}
+
+// verifyOrigin checks the signature of a message received by the party, when its peers have origin keys. A message
+// sent to the party alone was signed for it.
+func (params *Parameters) verifyOrigin(msg ParsedMessage) error {
+	contexts := []*PeerContext{params.parties, params.newParties}
+	required := false
+	var key ed25519.PublicKey
+	for _, ctx := range contexts {
+		if ctx == nil || !ctx.HasOriginKeys() {
+			continue
+		}
+		required = true
+		if key == nil {
+			key = ctx.OriginKey(msg.GetFrom())
+		}
+	}
+	if !required {
+		return nil
+	}
+	if key == nil {
+		return fmt.Errorf("the sender %s has no origin key", msg.GetFrom())
+	}
+	wire := msg.WireMsg()
+	if wire == nil || wire.Message == nil || len(wire.Signature) == 0 {
+		return errors.New("the message is not signed by its sender")
+	}
+	var to *PartyID
+	if !msg.IsBroadcast() {
+		to = params.partyID
+	}
+	if !ed25519.Verify(key, originDigest(params.ssid, msg.GetFrom(), to, msg.IsBroadcast(), wire.Message), wire.Signature) {
+		return fmt.Errorf("the signature of the message does not verify against the origin key of %s", msg.GetFrom())
+	}
+	return nil
+}
+
+func originDigest(ssid []byte, from, to *PartyID, isBroadcast bool, content *anypb.Any) []byte {
+	var toKey []byte
+	if to != nil {
+		toKey = to.Key
+	}
+	broadcast := []byte{0}
+	if isBroadcast {
+		broadcast[0] = 1
+	}
+	return common.SHA512_256(originDomain, ssid, from.Key, toKey, broadcast, []byte(content.GetTypeUrl()), content.GetValue())
+}
@@ -0,0 +1,122 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/crypto/vss"
+	"github.com/kisdex/mpc-lib/eddsa/keygen"
+	"github.com/kisdex/mpc-lib/test"
+	. "github.com/kisdex/mpc-lib/tss"
+)
+
+func TestOriginKeys(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(test.TestParticipants)
+	publicKeys := make([]ed25519.PublicKey, len(pIDs))
+	privateKeys := make([]ed25519.PrivateKey, len(pIDs))
+	for i := range pIDs {
+		var err error
+		publicKeys[i], privateKeys[i], err = ed25519.GenerateKey(rand.Reader)
+		if !assert.NoError(t, err) {
+			return
+		}
+	}
+	_, err := NewPeerContextWithOriginKeys(pIDs, publicKeys[1:])
+	assert.Error(t, err, "every party must have an origin key")
+	p2pCtx, err := NewPeerContextWithOriginKeys(pIDs, publicKeys)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	outCh := make(chan Message, len(pIDs)*len(pIDs)*3)
+	endCh := make(chan *keygen.LocalPartySaveData, len(pIDs))
+	parties := make([]Party, len(pIDs))
+	for i := range pIDs {
+		params := NewParameters(Edwards(), p2pCtx, pIDs[i], len(pIDs), test.TestThreshold)
+		params.SetSSID([]byte("origin"))
+		params.SetOriginKey(privateKeys[i])
+		parties[i] = keygen.NewLocalParty(params, outCh, endCh)
+	}
+	deliver := func(P Party, msg Message) (ParsedMessage, *Error) {
+		bz, routing, err := msg.WireBytes()
+		if !assert.NoError(t, err) {
+			return nil, nil
+		}
+		parsed, err := ParseWireMessage(bz, routing.From, routing.IsBroadcast)
+		if !assert.NoError(t, err) {
+			return nil, nil
+		}
+		_, tssErr := P.Update(parsed)
+		return parsed, tssErr
+	}
+	for _, P := range parties {
+		if !assert.Nil(t, P.Start()) {
+			return
+		}
+	}
+
+	// a message of party 1 that is passed off as one of party 2, and one that lost its signature, are rejected
+	msg := <-outCh
+	bz, _, err := msg.WireBytes()
+	assert.NoError(t, err)
+	forged, err := ParseWireMessage(bz, pIDs[2], msg.IsBroadcast())
+	assert.NoError(t, err)
+	_, tssErr := parties[0].Update(forged)
+	if assert.NotNil(t, tssErr, "a message from another sender must be rejected") {
+		assert.Equal(t, ErrInvalidMessage, tssErr.Code())
+		assert.Empty(t, tssErr.Culprits())
+	}
+	unsigned, err := ParseWireMessage(bz, msg.GetFrom(), msg.IsBroadcast())
+	assert.NoError(t, err)
+	unsigned.WireMsg().Signature = nil
+	_, tssErr = parties[0].Update(unsigned)
+	if assert.NotNil(t, tssErr, "an unsigned message must be rejected") {
+		assert.Equal(t, ErrInvalidMessage, tssErr.Code())
+	}
+	outCh <- msg
+
+	for len(endCh) < len(pIDs) {
+		select {
+		case msg := <-outCh:
+			for _, P := range parties {
+				if P.PartyID().Index == msg.GetFrom().Index {
+					continue
+				}
+				if dest := msg.GetTo(); dest != nil && dest[0].Index != P.PartyID().Index {
+					continue
+				}
+				if _, tssErr := deliver(P, msg); !assert.Nil(t, tssErr) {
+					return
+				}
+			}
+		case <-time.After(5 * time.Second):
+			assert.FailNow(t, "keygen is stuck")
+		}
+	}
+
+	// a message sent to party 1 alone does not verify when it is redirected to party 2
+	msg = keygen.NewKGRound2Message1(pIDs[1], pIDs[0], &vss.Share{Share: big.NewInt(1)})
+	params := NewParameters(Edwards(), p2pCtx, pIDs[0], len(pIDs), test.TestThreshold)
+	params.SetSSID([]byte("origin"))
+	params.SetOriginKey(privateKeys[0])
+	params.SendMessage(outCh, msg)
+	bz, _, err = (<-outCh).WireBytes()
+	assert.NoError(t, err)
+	redirected, err := ParseWireMessage(bz, pIDs[0], false)
+	assert.NoError(t, err)
+	_, tssErr = parties[2].Update(redirected)
+	if assert.NotNil(t, tssErr, "a message to another party must be rejected") {
+		assert.Equal(t, ErrInvalidMessage, tssErr.Code())
+	}
+}
@@ -7,9 +7,13 @@
 package tss
 
 import (
+	"crypto/ed25519"
 	"crypto/elliptic"
+	"errors"
 	"runtime"
 	"time"
+
+	"github.com/kisdex/mpc-lib/common"
 )
 
 type (
@@ -17,15 +21,39 @@ type (
 		ec                  elliptic.Curve
 		partyID             *PartyID
 		parties             *PeerContext
+		newParties          *PeerContext // the new committee of a resharing
 		partyCount          int
 		threshold           int
 		concurrency         int
 		safePrimeGenTimeout time.Duration
+		securityLevel       SecurityLevel
 		// proof session info
 		nonce int
+		ssid  []byte
 		// for keygen
 		noProofMod bool
 		noProofFac bool
+		// set by UnsafeAllowInsecureProofs: the party may start with noProofMod or noProofFac
+		allowInsecureProofs bool
+		// for signing
+		noLowS          bool
+		proofAggregator *PartyID
+		deferProofs     bool
+		// round deadlines and retransmission
+		roundTimeout   time.Duration
+		onRoundTimeout RoundTimeoutFunc
+		outbox         *outbox
+		bufferLimits   *BufferLimits
+		metrics        Metrics
+		listener       RoundListener
+		tracer         Tracer
+		trace          *traceState
+		labels         *labelState
+		transcript     TranscriptRecorder
+		originKey      ed25519.PrivateKey
+		logger         common.Logger
+		// wire compression of the messages sent
+		compression string
 	}
 
 	ReSharingParameters struct {
@@ -50,6 +78,8 @@ func NewParameters(ec elliptic.Curve, ctx *PeerContext, partyID *PartyID, partyC
 		threshold:           threshold,
 		concurrency:         runtime.GOMAXPROCS(0),
 		safePrimeGenTimeout: defaultSafePrimeGenTimeout,
+		outbox:              new(outbox),
+		labels:              new(labelState),
 	}
 }
 
@@ -81,6 +111,8 @@ func (params *Parameters) SafePrimeGenTimeout() time.Duration {
 	return params.safePrimeGenTimeout
 }
 
+// SetConcurrency sets the number of goroutines that the party uses to generate the safe primes and to do the work of
+// a round for its peers, see NewWorkerPool. It defaults to GOMAXPROCS; set it to 1 on small devices.
 // The concurrency level must be >= 1.
 func (params *Parameters) SetConcurrency(concurrency int) {
 	params.concurrency = concurrency
@@ -90,27 +122,129 @@ func (params *Parameters) SetSafePrimeGenTimeout(timeout time.Duration) {
 	params.safePrimeGenTimeout = timeout
 }
 
+// Logger returns the logger set with SetLogger, or else common.DefaultLogger
+func (params *Parameters) Logger() common.Logger {
+	if params.logger == nil {
+		return common.DefaultLogger()
+	}
+	return params.logger
+}
+
+// SetLogger sets the logger of the party, e.g. to tag its messages with the session id
+func (params *Parameters) SetLogger(logger common.Logger) {
+	params.logger = logger
+}
+
+// SSID returns the session id set with SetSSID, or nil
+func (params *Parameters) SSID() []byte {
+	return params.ssid
+}
+
+// SetSSID sets a session id, e.g. an epoch counter or a request id, that is bound into the Fiat-Shamir challenge of
+// every proof together with the party ids and key material, so that proofs cannot be replayed in another session.
+// All of the parties of a session must set the same value.
+func (params *Parameters) SetSSID(ssid []byte) {
+	params.ssid = ssid
+}
+
+// NoProofMod reports whether the party skips the CGG21 Πmod proof (crypto/modproof) that its Paillier modulus is a
+// Paillier-Blum modulus, and accepts the messages of peers that do not include one
 func (params *Parameters) NoProofMod() bool {
 	return params.noProofMod
 }
 
+// NoProofFac reports whether the party skips the CGG21 Πfac proofs (crypto/facproof) that its Paillier modulus has
+// no small factors, and accepts the messages of peers that do not include them
 func (params *Parameters) NoProofFac() bool {
 	return params.noProofFac
 }
 
+// SetNoProofMod disables the Πmod proof, e.g. to run keygen with parties of older versions. A proof that a peer
+// does send is still verified. It does not affect the GG18 Paillier key proof of keygen round 3. A party refuses to
+// start with it unless UnsafeAllowInsecureProofs is set too.
 func (params *Parameters) SetNoProofMod() {
 	params.noProofMod = true
 }
 
+// SetNoProofFac disables the Πfac proofs, see SetNoProofMod
 func (params *Parameters) SetNoProofFac() {
 	params.noProofFac = true
 }
 
+// UnsafeAllowInsecureProofs lets the party start with SetNoProofMod, SetNoProofFac, SetDeferProofs or
+// SetProofAggregator. Without the proofs, a peer may choose a Paillier modulus with small factors and learn the secret
+// shares of the party from the MtA of signing; only set it for tests, while a committee migrates from parties of older
+// versions or, for SetDeferProofs and SetProofAggregator, when every party is operated by the same organization.
+func (params *Parameters) UnsafeAllowInsecureProofs() {
+	params.allowInsecureProofs = true
+}
+
+// CheckProductionSafety returns an error if the parameters disable, defer or aggregate proofs without
+// UnsafeAllowInsecureProofs. A party checks its parameters when it starts or resumes.
+func (params *Parameters) CheckProductionSafety() error {
+	if params.allowInsecureProofs {
+		return nil
+	}
+	if params.noProofMod || params.noProofFac {
+		return errors.New("the parameters disable the Πmod or Πfac proofs; call UnsafeAllowInsecureProofs() to allow it")
+	}
+	if params.deferProofs {
+		return errors.New("the parameters defer the verification of the proofs; call UnsafeAllowInsecureProofs() to allow it")
+	}
+	if params.proofAggregator != nil {
+		return errors.New("the parameters set a proof aggregator, which can forge proofs; call UnsafeAllowInsecureProofs() to allow it")
+	}
+	return nil
+}
+
+// NoLowS reports whether ECDSA signing should output s as computed rather than in its low-S form
+func (params *Parameters) NoLowS() bool {
+	return params.noLowS
+}
+
+// SetNoLowS disables the low-S normalization of ECDSA signatures. Only use this if the verifier
+// does not enforce BIP-62 / EIP-2 canonical signatures and expects the raw s value.
+func (params *Parameters) SetNoLowS() {
+	params.noLowS = true
+}
+
+// ProofAggregator returns the party set with SetProofAggregator, or nil if the signers make a proof for each verifier
+func (params *Parameters) ProofAggregator() *PartyID {
+	return params.proofAggregator
+}
+
+// SetProofAggregator makes the CGG+ signers make each of their range proofs once, against the ring-Pedersen
+// parameters of `aggregator` rather than those of every other signer, which brings the size of a message from one
+// proof per party down to one proof. The aggregator makes its own proofs against the parameters of the signer after
+// it. Whoever knows the factorization of the parameters that a proof is made against can forge it, so every signer
+// trusts the aggregator not to collude with a prover; only set it for committees too large for one proof per party.
+// All of the signers of a session must set the same aggregator, which is bound into the session id, and the party does
+// not start without UnsafeAllowInsecureProofs.
+func (params *Parameters) SetProofAggregator(aggregator *PartyID) {
+	params.proofAggregator = aggregator
+}
+
+// DeferProofs reports whether the party defers the verification of the proofs of its peers, see SetDeferProofs
+func (params *Parameters) DeferProofs() bool {
+	return params.deferProofs
+}
+
+// SetDeferProofs makes a CGG+ signer record the proofs of its peers and verify them only if the signature that it
+// outputs does not verify, to blame the parties whose proofs do not hold; a presigning party verifies them before it
+// outputs the presignature, which it cannot check. This saves most of the work of signing, but the party then
+// decrypts ciphertexts and reveals values before it knows that they are well-formed, which a malicious peer can use to
+// learn its secret shares. Only set it when every party is operated by the same organization; the party does not start
+// without UnsafeAllowInsecureProofs.
+func (params *Parameters) SetDeferProofs() {
+	params.deferProofs = true
+}
+
 // ----- //
 
 // Exported, used in `tss` client
 func NewReSharingParameters(ec elliptic.Curve, ctx, newCtx *PeerContext, partyID *PartyID, partyCount, threshold, newPartyCount, newThreshold int) *ReSharingParameters {
 	params := NewParameters(ec, ctx, partyID, partyCount, threshold)
+	params.newParties = newCtx
 	return &ReSharingParameters{
 		Parameters:    params,
 		newParties:    newCtx,
@@ -166,3 +300,14 @@ func (rgParams *ReSharingParameters) IsNewCommittee() bool {
 	}
 	return false
 }
+
+// OldCommitteePartyID returns the id of this party in the old committee, whose Index is its index there, or nil if it
+// is not a member. A party of both committees has a distinct id in each, as its indexes differ.
+func (rgParams *ReSharingParameters) OldCommitteePartyID() *PartyID {
+	return rgParams.parties.IDs().FindByKey(rgParams.partyID.KeyInt())
+}
+
+// NewCommitteePartyID returns the id of this party in the new committee, see OldCommitteePartyID
+func (rgParams *ReSharingParameters) NewCommitteePartyID() *PartyID {
+	return rgParams.newParties.IDs().FindByKey(rgParams.partyID.KeyInt())
+}
@@ -10,6 +10,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/kisdex/mpc-lib/common"
 )
@@ -34,13 +35,31 @@ type Party interface {
 	setRound(Round) *Error
 	round() Round
 	advance()
+	abort()
+	started() bool
+	buffer() *messageBuffer
+	beginRound(task string)
+	observeReceived(msg ParsedMessage)
+	observeFailure(task string, err *Error)
+	logger() common.Logger
 	lock()
 	unlock()
 }
 
+// Zeroizer is implemented by the parties that keep secrets in their temporary data, e.g. the nonces of a signing.
+// BaseStart and BaseUpdate call Zeroize once the party has finished or failed, when no round needs them any more.
+type Zeroizer interface {
+	Zeroize()
+}
+
 type BaseParty struct {
 	mtx        sync.Mutex
 	rnd        Round
+	timer      *time.Timer // fires when the current round times out
+	params     *Parameters // the parameters of the rounds, once the first one is set
+	ended      Round       // the round to report the duration of when the next one begins
+	begun      time.Time
+	buffered   messageBuffer // the messages received ahead of their round
 	FirstRound Round
 }
 
@@ -67,13 +86,15 @@ func (p *BaseParty) WrapError(err error, culprits ...*PartyID) *Error {
 // an implementation of ValidateMessage that is shared across the different types of parties (keygen, signing, dynamic groups)
 func (p *BaseParty) ValidateMessage(msg ParsedMessage) (bool, *Error) {
 	if msg == nil || msg.Content() == nil {
-		return false, p.WrapError(fmt.Errorf("received nil msg: %s", msg))
+		return false, p.WrapError(fmt.Errorf("received nil msg: %s", msg)).WithCode(ErrInvalidMessage)
 	}
 	if msg.GetFrom() == nil || !msg.GetFrom().ValidateBasic() {
-		return false, p.WrapError(fmt.Errorf("received msg with an invalid sender: %s", msg))
+		return false, p.WrapError(fmt.Errorf("received msg with an invalid sender: %s", msg)).
+			WithCode(ErrInvalidMessage).WithMessageType(msg.Type())
 	}
 	if !msg.ValidateBasic() {
-		return false, p.WrapError(fmt.Errorf("message failed ValidateBasic: %s", msg), msg.GetFrom())
+		return false, p.WrapError(fmt.Errorf("message failed ValidateBasic: %s", msg), msg.GetFrom()).
+			WithCode(ErrInvalidMessage).WithMessageType(msg.Type())
 	}
 	return true, nil
 }
@@ -87,9 +108,9 @@ func (p *BaseParty) String() string {
 
 func (p *BaseParty) setRound(round Round) *Error {
 	if p.rnd != nil {
-		return p.WrapError(errors.New("a round is already set on this party"))
+		return p.WrapError(errors.New("a round is already set on this party")).WithCode(ErrInvalidState)
 	}
-	p.rnd = round
+	p.rnd, p.params = round, round.Params()
 	return nil
 }
 
@@ -101,6 +122,31 @@ func (p *BaseParty) advance() {
 	p.rnd = p.rnd.NextRound()
 }
 
+// abort drops the current round of the party and stops its timer, as if the party had finished
+func (p *BaseParty) abort() {
+	if p.timer != nil {
+		p.timer.Stop()
+		p.timer = nil
+	}
+	p.rnd = nil
+}
+
+// started reports whether the party has been started, even if it has finished since
+func (p *BaseParty) started() bool {
+	return p.params != nil
+}
+
+func (p *BaseParty) buffer() *messageBuffer {
+	return &p.buffered
+}
+
+func (p *BaseParty) logger() common.Logger {
+	if p.params == nil {
+		return common.DefaultLogger()
+	}
+	return p.params.Logger()
+}
+
 func (p *BaseParty) lock() {
 	p.mtx.Lock()
 }
@@ -111,39 +157,80 @@ func (p *BaseParty) unlock() {
 
 // ----- //
 
-func BaseStart(p Party, task string, prepare ...func(Round) *Error) *Error {
+func BaseStart(p Party, task string, prepare ...func(Round) *Error) (err *Error) {
+	replay, err := baseStart(p, task, prepare...)
+	if err != nil || replay == nil {
+		return err
+	}
+	// messages arrived before the party started: update it with them now that the first round accepts them
+	if _, err = baseUpdate(p, replay, task, false); err != nil {
+		p.lock()
+		p.observeFailure(task, err)
+		p.unlock()
+	}
+	return err
+}
+
+// baseStart starts the first round of the party and returns the last of the buffered messages that it accepts
+func baseStart(p Party, task string, prepare ...func(Round) *Error) (replay ParsedMessage, err *Error) {
 	p.lock()
 	defer p.unlock()
+	defer func() { p.observeFailure(task, err) }()
 	if p.PartyID() == nil || !p.PartyID().ValidateBasic() {
-		return p.WrapError(fmt.Errorf("could not start. this party has an invalid PartyID: %+v", p.PartyID()))
+		return nil, p.WrapError(fmt.Errorf("could not start. this party has an invalid PartyID: %+v", p.PartyID())).WithCode(ErrInvalidState)
 	}
 	if p.round() != nil {
-		return p.WrapError(errors.New("could not start. this party is in an unexpected state. use the constructor and Start()"))
+		return nil, p.WrapError(errors.New("could not start. this party is in an unexpected state. use the constructor and Start()")).WithCode(ErrInvalidState)
 	}
 	round := p.FirstRound()
+	if err := round.Params().CheckProductionSafety(); err != nil {
+		return nil, round.WrapError(err).WithCode(ErrInvalidState)
+	}
 	if err := p.setRound(round); err != nil {
-		return err
+		return nil, err
 	}
 	if 1 < len(prepare) {
-		return p.WrapError(errors.New("too many prepare functions given to Start(); 1 allowed"))
+		return nil, p.WrapError(errors.New("too many prepare functions given to Start(); 1 allowed")).WithCode(ErrInvalidState)
 	}
 	if len(prepare) == 1 {
 		if err := prepare[0](round); err != nil {
-			return err
+			return nil, err
 		}
 	}
-	common.Logger.Infof("party %s: %s round %d starting", p.round().Params().PartyID(), task, 1)
+	p.beginRound(task)
+	p.logger().Infof("party %s: %s round %d starting", round.Params().PartyID(), task, 1)
 	defer func() {
-		common.Logger.Debugf("party %s: %s round %d finished", p.round().Params().PartyID(), task, 1)
+		p.logger().Debugf("party %s: %s round %d finished", round.Params().PartyID(), task, 1)
 	}()
-	return p.round().Start()
+	if err := startRound(p, task); err != nil {
+		zeroize(p)
+		return nil, err
+	}
+	notifyRoundStart(p, task)
+	return replayBuffered(p)
 }
 
 // an implementation of Update that is shared across the different types of parties (keygen, signing, dynamic groups)
 func BaseUpdate(p Party, msg ParsedMessage, task string) (ok bool, err *Error) {
+	ok, err = baseUpdate(p, msg, task, true)
+	p.lock()
+	defer p.unlock()
+	p.observeReceived(msg)
+	p.observeFailure(task, err)
+	return ok, err
+}
+
+// baseUpdate records `msg` in the transcript of the party if it is `received`, i.e. not updated with again once a
+// round has advanced
+func baseUpdate(p Party, msg ParsedMessage, task string, received bool) (ok bool, err *Error) {
 	// fast-fail on an invalid message; do not lock the mutex yet
 	if _, err := p.ValidateMessage(msg); err != nil {
-		return false, err
+		if received {
+			p.lock()
+			recordReceived(p, msg)
+			p.unlock()
+		}
+		return false, invalidMessage(err, msg)
 	}
 	// lock the mutex. need this mtx unlock hook; L108 is recursive so cannot use defer
 	r := func(ok bool, err *Error) (bool, *Error) {
@@ -151,33 +238,127 @@ func BaseUpdate(p Party, msg ParsedMessage, task string) (ok bool, err *Error) {
 		return ok, err
 	}
 	p.lock() // data is written to P state below
-	common.Logger.Debugf("party %s received message: %s", p.PartyID(), msg.String())
+	if received {
+		if err := partyParams(p).verifyOrigin(msg); err != nil {
+			return r(false, p.WrapError(err).WithCode(ErrInvalidMessage).WithMessageType(msg.Type()))
+		}
+	}
+	if received && p.buffer().duplicate(msg) {
+		p.logger().Debugf("party %s ignored a duplicate message: %s", p.PartyID(), msg.String())
+		return r(true, nil)
+	}
+	if received {
+		recordReceived(p, msg)
+	}
+	p.logger().Debugf("party %s received message: %s", p.PartyID(), msg.String())
 	if p.round() != nil {
-		common.Logger.Debugf("party %s round %d update: %s", p.PartyID(), p.round().RoundNumber(), msg.String())
+		p.logger().Debugf("party %s round %d update: %s", p.PartyID(), p.round().RoundNumber(), msg.String())
 	}
-	if ok, err := p.StoreMessage(msg); err != nil || !ok {
+	if err := checkCurve(p, msg); err != nil {
 		return r(false, err)
 	}
+	// a message that arrives ahead of its round waits in the buffer until a round accepts it. A party that has not
+	// started stores it as well, as the rounds may also be driven outside of the party.
+	ahead := false
+	if received {
+		if round := p.round(); round == nil && !p.started() {
+			bufferMessage(p, msg)
+		} else if round != nil && !round.CanAccept(msg) {
+			bufferMessage(p, msg)
+			ahead = true
+		}
+	}
+	if !ahead {
+		if ok, err := p.StoreMessage(msg); err != nil || !ok {
+			return r(false, invalidMessage(err, msg))
+		}
+	}
 	if p.round() != nil {
-		common.Logger.Debugf("party %s: %s round %d update", p.round().Params().PartyID(), task, p.round().RoundNumber())
-		if _, err := p.round().Update(); err != nil {
+		p.logger().Debugf("party %s: %s round %d update", p.round().Params().PartyID(), task, p.round().RoundNumber())
+		if _, err := updateRound(p, task, msg); err != nil {
+			zeroize(p)
 			return r(false, err)
 		}
 		if p.round().CanProceed() {
-			if p.advance(); p.round() != nil {
-				if err := p.round().Start(); err != nil {
+			p.advance()
+			if p.beginRound(task); p.round() != nil {
+				if err := startRound(p, task); err != nil {
+					zeroize(p)
 					return r(false, err)
 				}
+				notifyRoundStart(p, task)
 				rndNum := p.round().RoundNumber()
-				common.Logger.Infof("party %s: %s round %d started", p.round().Params().PartyID(), task, rndNum)
+				p.logger().Infof("party %s: %s round %d started", p.round().Params().PartyID(), task, rndNum)
 			} else {
 				// finished! the round implementation will have sent the data through the `end` channel.
-				common.Logger.Infof("party %s: %s finished!", p.PartyID(), task)
+				p.logger().Infof("party %s: %s finished!", p.PartyID(), task)
+				zeroize(p)
 			}
-			p.unlock()                      // recursive so can't defer after return
-			return BaseUpdate(p, msg, task) // re-run round update or finish)
+			if _, err := replayBuffered(p); err != nil {
+				zeroize(p)
+				return r(false, err)
+			}
+			p.unlock()                             // recursive so can't defer after return
+			return baseUpdate(p, msg, task, false) // re-run round update or finish)
 		}
 		return r(true, nil)
 	}
 	return r(true, nil)
 }
+
+// BaseAbort stops `p` in the middle of the protocol, e.g. when a session is given up on because a peer stopped
+// responding: the round timeout of the party no longer fires, later messages do not advance it and its secrets are
+// wiped. The party does not report an error or a result.
+func BaseAbort(p Party) {
+	p.lock()
+	defer p.unlock()
+	p.abort()
+	zeroize(p)
+}
+
+// partyParams returns the parameters of the current round of `p`, or of its first round when it has none
+func partyParams(p Party) *Parameters {
+	if round := p.round(); round != nil {
+		return round.Params()
+	}
+	return p.FirstRound().Params()
+}
+
+// zeroize wipes the secrets of `p` if it keeps any
+func zeroize(p Party) {
+	if z, ok := p.(Zeroizer); ok {
+		z.Zeroize()
+	}
+}
+
+// checkCurve fails fast on a message stamped with another curve than the one of `p`, whose proofs could not verify.
+// Messages of older releases and of the legacy wire format have no curve name and are not checked.
+func checkCurve(p Party, msg ParsedMessage) *Error {
+	wire := msg.WireMsg()
+	if wire == nil || wire.Curve == "" {
+		return nil
+	}
+	round := p.round()
+	if round == nil {
+		round = p.FirstRound()
+	}
+	if round == nil || round.Params() == nil {
+		return nil
+	}
+	if name, ok := GetCurveName(round.Params().EC()); ok && string(name) != wire.Curve {
+		return p.WrapError(fmt.Errorf("received a message on curve %q but this party runs on %q: %s", wire.Curve, name, msg), msg.GetFrom()).
+			WithCode(ErrInvalidMessage).WithMessageType(msg.Type())
+	}
+	return nil
+}
+
+// invalidMessage classifies an error returned by the validation or the storage of `msg`, unless it already has a code
+func invalidMessage(err *Error, msg ParsedMessage) *Error {
+	if err == nil || err.code != ErrUnspecified {
+		return err
+	}
+	if msg != nil && err.msgType == "" {
+		err.msgType = msg.Type()
+	}
+	return err.WithCode(ErrInvalidMessage)
+}
@@ -6,9 +6,15 @@
 
 package tss
 
+import (
+	"crypto/ed25519"
+)
+
 type (
 	PeerContext struct {
 		partyIDs SortedPartyIDs
+		// the long-term public keys of the parties by their key, see NewPeerContextWithOriginKeys
+		originKeys map[string]ed25519.PublicKey
 	}
 )
 
@@ -0,0 +1,68 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"context"
+	"runtime/pprof"
+	"sync"
+)
+
+// WorkerPool runs the work that a round does for each peer, such as verifying its proofs, on a bounded number of
+// goroutines, so that the cost of a round does not grow with the size of the committee.
+type WorkerPool struct {
+	semaphore chan struct{}
+	wg        sync.WaitGroup
+	labels    context.Context // the pprof labels of the round that made the pool, which GoFor extends
+}
+
+// NewWorkerPool returns a pool that runs at most `concurrency` functions at once; a level below 1 is taken as 1
+func NewWorkerPool(concurrency int) *WorkerPool {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &WorkerPool{
+		semaphore: make(chan struct{}, concurrency),
+		labels:    context.Background(),
+	}
+}
+
+// NewWorkerPool returns a pool bounded by the concurrency level of the party. Each call returns a new pool, so a
+// function run in a pool may wait on a pool of its own.
+func (params *Parameters) NewWorkerPool() *WorkerPool {
+	pool := NewWorkerPool(params.Concurrency())
+	pool.labels = params.labelContext()
+	return pool
+}
+
+// Go runs `f` on a new goroutine once fewer than the concurrency level of the pool are running; it blocks until then
+func (pool *WorkerPool) Go(f func()) {
+	pool.wg.Add(1)
+	pool.semaphore <- struct{}{}
+	go func() {
+		defer func() {
+			<-pool.semaphore
+			pool.wg.Done()
+		}()
+		f()
+	}()
+}
+
+// GoFor is Go for work done for `peer`, e.g. the verification of its proofs: the goroutine is labeled with the peer in
+// the CPU profiles, besides the labels of the round that made the pool
+func (pool *WorkerPool) GoFor(peer *PartyID, f func()) {
+	pool.Go(func() {
+		pprof.Do(pool.labels, pprof.Labels(AttributePeer, peer.Id), func(context.Context) {
+			f()
+		})
+	})
+}
+
+// Wait blocks until all of the functions run with Go have returned
+func (pool *WorkerPool) Wait() {
+	pool.wg.Wait()
+}
@@ -0,0 +1,71 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss_test
+
+import (
+	"bytes"
+	"runtime/pprof"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/test"
+	. "github.com/kisdex/mpc-lib/tss"
+)
+
+func TestWorkerPool(t *testing.T) {
+	for _, concurrency := range []int{0, 1, 3} {
+		pool := NewWorkerPool(concurrency)
+		var running, peak, done int32
+		for i := 0; i < 12; i++ {
+			pool.Go(func() {
+				n := atomic.AddInt32(&running, 1)
+				for {
+					p := atomic.LoadInt32(&peak)
+					if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+						break
+					}
+				}
+				time.Sleep(5 * time.Millisecond)
+				atomic.AddInt32(&running, -1)
+				atomic.AddInt32(&done, 1)
+			})
+		}
+		pool.Wait()
+		assert.EqualValues(t, 12, done)
+		assert.LessOrEqual(t, peak, int32(max(concurrency, 1)))
+	}
+}
+
+func TestWorkerPoolLabels(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(test.TestParticipants)
+	params := NewParameters(Edwards(), NewPeerContext(pIDs), pIDs[0], len(pIDs), test.TestThreshold)
+	params.SetConcurrency(len(pIDs))
+	pool := params.NewWorkerPool()
+	var started sync.WaitGroup
+	release := make(chan struct{})
+	for _, peer := range pIDs[1:] {
+		started.Add(1)
+		pool.GoFor(peer, func() {
+			started.Done()
+			<-release
+		})
+	}
+
+	// the goroutines of the pool are blocked, with their labels in the goroutine profile
+	started.Wait()
+	var profile bytes.Buffer
+	assert.NoError(t, pprof.Lookup("goroutine").WriteTo(&profile, 1))
+	close(release)
+	pool.Wait()
+	for _, peer := range pIDs[1:] {
+		assert.Contains(t, profile.String(), `"`+AttributePeer+`":"`+peer.Id+`"`)
+	}
+}
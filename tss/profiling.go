@@ -0,0 +1,53 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"context"
+	"runtime/pprof"
+	"strconv"
+	"sync"
+)
+
+// labelState is the pprof label set of the round that a party is running, which its worker pools extend with the peer
+// of each goroutine. The goroutines of a round are labeled with the keys of the span attributes: AttributeParty,
+// AttributeTask, AttributeRound and, for the work done for a peer, AttributePeer, e.g.
+//
+//	go tool pprof -tagfocus=tss.round=3 -tagshow=tss.peer cpu.pprof
+type labelState struct {
+	mtx sync.Mutex
+	ctx context.Context
+}
+
+// doLabeled runs `f`, the start or the update of round `round` of `task`, with the pprof labels of the round, which the
+// goroutines that it spawns inherit
+func (params *Parameters) doLabeled(task string, round int, f func()) {
+	labels := pprof.Labels(AttributeParty, params.partyID.Id, AttributeTask, task, AttributeRound, strconv.Itoa(round))
+	pprof.Do(context.Background(), labels, func(ctx context.Context) {
+		params.labels.mtx.Lock()
+		prev := params.labels.ctx
+		params.labels.ctx = ctx
+		params.labels.mtx.Unlock()
+		defer func() {
+			params.labels.mtx.Lock()
+			params.labels.ctx = prev
+			params.labels.mtx.Unlock()
+		}()
+		f()
+	})
+}
+
+// labelContext returns the context of the pprof labels of the round that the party is running, or a context without
+// labels
+func (params *Parameters) labelContext() context.Context {
+	params.labels.mtx.Lock()
+	defer params.labels.mtx.Unlock()
+	if params.labels.ctx == nil {
+		return context.Background()
+	}
+	return params.labels.ctx
+}
@@ -0,0 +1,95 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+)
+
+// Quorum is the subset of Threshold+1 parties of a key that sign with it, with ids of their own that are indexed
+// among the signers, so that the signing parties pick the shares and the Lagrange coefficients of the signers. Every
+// operator computes the same Quorum from the same arguments of SelectQuorum or SelectRandomQuorum, so that each one
+// can build the parameters of its own signer with Parameters.
+type Quorum struct {
+	EC        elliptic.Curve
+	Signers   SortedPartyIDs
+	Threshold int
+
+	ctx *PeerContext
+}
+
+// SelectQuorum selects the first Threshold+1 parties of the key that are in `online`, in the order of `parties`
+func SelectQuorum(ec elliptic.Curve, parties SortedPartyIDs, threshold int, online SortedPartyIDs) (*Quorum, error) {
+	return selectQuorum(ec, onlineParties(parties, online), threshold)
+}
+
+// SelectRandomQuorum selects Threshold+1 parties of the key that are in `online` in an order drawn from `seed`, e.g.
+// the hash of the message to sign, to spread the signing among the parties. Every operator must use the same seed.
+func SelectRandomQuorum(ec elliptic.Curve, parties SortedPartyIDs, threshold int, online SortedPartyIDs, seed []byte) (*Quorum, error) {
+	candidates := onlineParties(parties, online)
+	draws := make(map[*PartyID][]byte, len(candidates))
+	for _, pID := range candidates {
+		h := sha256.New()
+		h.Write(seed)
+		h.Write(pID.Key)
+		draws[pID] = h.Sum(nil)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return bytes.Compare(draws[candidates[i]], draws[candidates[j]]) < 0
+	})
+	return selectQuorum(ec, candidates, threshold)
+}
+
+func selectQuorum(ec elliptic.Curve, candidates []*PartyID, threshold int) (*Quorum, error) {
+	if threshold < 1 {
+		return nil, fmt.Errorf("invalid threshold %d", threshold)
+	}
+	if len(candidates) <= threshold {
+		return nil, fmt.Errorf("%d parties are online, %d are needed", len(candidates), threshold+1)
+	}
+	signers := make(UnSortedPartyIDs, 0, threshold+1)
+	for _, pID := range candidates[:threshold+1] {
+		// a copy, as sorting the signers re-indexes them
+		signers = append(signers, NewPartyID(pID.Id, pID.Moniker, pID.KeyInt()))
+	}
+	sorted := SortPartyIDs(signers)
+	return &Quorum{EC: ec, Signers: sorted, Threshold: threshold, ctx: NewPeerContext(sorted)}, nil
+}
+
+// onlineParties returns the parties of the key that are in `online`, which may hold ids of other sessions
+func onlineParties(parties, online SortedPartyIDs) []*PartyID {
+	candidates := make([]*PartyID, 0, len(parties))
+	keys := make(map[string]struct{}, len(parties))
+	for _, pID := range parties {
+		if _, dup := keys[string(pID.Key)]; dup {
+			continue
+		}
+		keys[string(pID.Key)] = struct{}{}
+		if online.FindByKey(pID.KeyInt()) != nil {
+			candidates = append(candidates, pID)
+		}
+	}
+	return candidates
+}
+
+// Parameters returns the signing parameters of the signer with the key of `partyID`
+func (q *Quorum) Parameters(partyID *PartyID) (*Parameters, error) {
+	pID := q.Signers.FindByKey(partyID.KeyInt())
+	if pID == nil {
+		return nil, fmt.Errorf("party %s is not a signer", partyID)
+	}
+	return NewParameters(q.EC, q.ctx, pID, len(q.Signers), q.Threshold), nil
+}
+
+// Contains reports whether the party with the key of `partyID` is a signer
+func (q *Quorum) Contains(partyID *PartyID) bool {
+	return q.Signers.FindByKey(partyID.KeyInt()) != nil
+}
@@ -0,0 +1,68 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/kisdex/mpc-lib/tss"
+)
+
+func TestSelectQuorum(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(5)
+	// the liveness of the parties may come with ids of its own, e.g. from a transport
+	online := SortedPartyIDs{}
+	for _, i := range []int{4, 1, 3} {
+		online = append(online, NewPartyID(pIDs[i].Id, pIDs[i].Moniker, pIDs[i].KeyInt()))
+	}
+
+	quorum, err := SelectQuorum(S256(), pIDs, 1, online)
+	if assert.NoError(t, err) {
+		assert.Len(t, quorum.Signers, 2)
+		assert.Equal(t, pIDs[1].Id, quorum.Signers[0].Id)
+		assert.Equal(t, pIDs[3].Id, quorum.Signers[1].Id)
+		for i, pID := range quorum.Signers {
+			assert.Equal(t, i, pID.Index, "the signers are indexed among themselves")
+		}
+		// the ids of the key are not re-indexed
+		for i, pID := range pIDs {
+			assert.Equal(t, i, pID.Index)
+		}
+		params, err := quorum.Parameters(pIDs[3])
+		if assert.NoError(t, err) {
+			assert.Same(t, quorum.Signers[1], params.PartyID())
+			assert.Equal(t, 2, params.PartyCount())
+			assert.Equal(t, 1, params.Threshold())
+			assert.Equal(t, quorum.Signers, params.Parties().IDs())
+		}
+		assert.False(t, quorum.Contains(pIDs[4]))
+		_, err = quorum.Parameters(pIDs[4])
+		assert.Error(t, err)
+	}
+
+	_, err = SelectQuorum(S256(), pIDs, 3, online)
+	assert.Error(t, err, "3 parties cannot meet the threshold 3")
+
+	// every operator draws the same random quorum from the same seed, and the quorum varies with the seed
+	seen := make(map[string]bool)
+	for _, seed := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		quorum, err = SelectRandomQuorum(S256(), pIDs, 1, online, []byte(seed))
+		if !assert.NoError(t, err) {
+			continue
+		}
+		again, err := SelectRandomQuorum(S256(), pIDs, 1, online, []byte(seed))
+		assert.NoError(t, err)
+		assert.Equal(t, quorum.Signers, again.Signers)
+		for _, pID := range quorum.Signers {
+			assert.NotNil(t, online.FindByKey(pID.KeyInt()), "a signer must be online")
+		}
+		seen[quorum.Signers[0].Id+quorum.Signers[1].Id] = true
+	}
+	assert.Less(t, 1, len(seen))
+}
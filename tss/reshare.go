@@ -0,0 +1,119 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+)
+
+// Reshare describes a resharing of a key on EC from the old committee to the new one. AddParty, RemoveParty and
+// ChangeThreshold build it for the common changes of a committee; every operator computes the same Reshare from the
+// same arguments, so that each one can build the parameters of its own parties with Parameters.
+type Reshare struct {
+	EC                         elliptic.Curve
+	OldParties, NewParties     SortedPartyIDs
+	OldThreshold, NewThreshold int
+
+	oldCtx, newCtx *PeerContext
+}
+
+// NewReshare checks the committees of a resharing and returns it
+func NewReshare(ec elliptic.Curve, oldParties, newParties SortedPartyIDs, oldThreshold, newThreshold int) (*Reshare, error) {
+	if oldThreshold < 1 || len(oldParties) < oldThreshold+1 {
+		return nil, fmt.Errorf("the old committee of %d parties cannot meet the threshold %d", len(oldParties), oldThreshold)
+	}
+	if newThreshold < 1 || len(newParties) <= newThreshold {
+		return nil, fmt.Errorf("invalid new party count %d and threshold %d", len(newParties), newThreshold)
+	}
+	for _, committee := range []SortedPartyIDs{oldParties, newParties} {
+		keys := make(map[string]struct{}, len(committee))
+		for _, pID := range committee {
+			if _, dup := keys[string(pID.Key)]; dup {
+				return nil, fmt.Errorf("the key of party %s is used twice", pID)
+			}
+			keys[string(pID.Key)] = struct{}{}
+		}
+	}
+	for _, pID := range newParties {
+		if oldParties.FindByKey(pID.KeyInt()) == pID {
+			return nil, fmt.Errorf("party %s of both committees must have a distinct id in each", pID)
+		}
+	}
+	return &Reshare{
+		EC:           ec,
+		OldParties:   oldParties,
+		NewParties:   newParties,
+		OldThreshold: oldThreshold,
+		NewThreshold: newThreshold,
+		oldCtx:       NewPeerContext(oldParties),
+		newCtx:       NewPeerContext(newParties),
+	}, nil
+}
+
+// AddParty reshares the key of `parties` to them and `party`, with the same threshold
+func AddParty(ec elliptic.Curve, parties SortedPartyIDs, threshold int, party *PartyID) (*Reshare, error) {
+	newParties := append(copyIDs(parties), party)
+	return NewReshare(ec, parties, SortPartyIDs(newParties), threshold, threshold)
+}
+
+// RemoveParty reshares the key of `parties` to all of them but `party`, with the same threshold, which the remaining
+// parties must still exceed
+func RemoveParty(ec elliptic.Curve, parties SortedPartyIDs, threshold int, party *PartyID) (*Reshare, error) {
+	if parties.FindByKey(party.KeyInt()) == nil {
+		return nil, errors.New("the party to remove is not in the committee")
+	}
+	remaining := copyIDs(parties.Exclude(party))
+	return NewReshare(ec, parties, SortPartyIDs(remaining), threshold, threshold)
+}
+
+// ChangeThreshold reshares the key of `parties` to them with another threshold
+func ChangeThreshold(ec elliptic.Curve, parties SortedPartyIDs, threshold, newThreshold int) (*Reshare, error) {
+	return NewReshare(ec, parties, SortPartyIDs(copyIDs(parties)), threshold, newThreshold)
+}
+
+// WithOldQuorum returns the resharing from only the first OldThreshold+1 parties of the old committee that are in
+// `online`, for when some of them are offline: any t+1 shares determine the key, and the old parties weigh theirs with
+// the Lagrange coefficients of the quorum. Every operator must pass the same online parties to agree on the quorum.
+func (r *Reshare) WithOldQuorum(online SortedPartyIDs) (*Reshare, error) {
+	quorum := make(UnSortedPartyIDs, 0, r.OldThreshold+1)
+	for _, pID := range r.OldParties {
+		if len(quorum) == r.OldThreshold+1 {
+			break
+		}
+		if online.FindByKey(pID.KeyInt()) != nil {
+			// a copy, as sorting the quorum re-indexes its parties
+			quorum = append(quorum, NewPartyID(pID.Id, pID.Moniker, pID.KeyInt()))
+		}
+	}
+	if len(quorum) <= r.OldThreshold {
+		return nil, fmt.Errorf("%d parties of the old committee are online, %d are needed", len(quorum), r.OldThreshold+1)
+	}
+	return NewReshare(r.EC, SortPartyIDs(quorum), r.NewParties, r.OldThreshold, r.NewThreshold)
+}
+
+// Parameters returns the parameters of the party of either committee with the key of `partyID`; a party that stays in
+// the committee takes part in both with the same parameters
+func (r *Reshare) Parameters(partyID *PartyID) (*ReSharingParameters, error) {
+	for _, committee := range []SortedPartyIDs{r.OldParties, r.NewParties} {
+		if pID := committee.FindByKey(partyID.KeyInt()); pID != nil {
+			return NewReSharingParameters(r.EC, r.oldCtx, r.newCtx, pID, len(r.OldParties), r.OldThreshold,
+				len(r.NewParties), r.NewThreshold), nil
+		}
+	}
+	return nil, fmt.Errorf("party %s is in neither committee", partyID)
+}
+
+// copyIDs returns new ids for members of the old committee that stay in the new one, which sorting indexes there
+func copyIDs(parties SortedPartyIDs) UnSortedPartyIDs {
+	ids := make(UnSortedPartyIDs, 0, len(parties)+1)
+	for _, pID := range parties {
+		ids = append(ids, NewPartyID(pID.Id, pID.Moniker, pID.KeyInt()))
+	}
+	return ids
+}
@@ -0,0 +1,118 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/kisdex/mpc-lib/tss"
+)
+
+func TestReshareCommittees(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(3)
+	extra := GenerateTestPartyIDs(1, 3)[0]
+
+	reshare, err := AddParty(S256(), pIDs, 1, extra)
+	if assert.NoError(t, err) {
+		assert.Equal(t, pIDs, reshare.OldParties)
+		assert.Len(t, reshare.NewParties, 4)
+		assert.Equal(t, 1, reshare.NewThreshold)
+		for i, pID := range reshare.NewParties {
+			assert.Equal(t, i, pID.Index)
+		}
+		// continuing members stay in the new committee with ids of their own
+		for i, pID := range pIDs {
+			newPID := reshare.NewParties.FindByKey(pID.KeyInt())
+			if assert.NotNil(t, newPID, "party %s must stay in the committee", pID) {
+				assert.NotSame(t, pID, newPID)
+				assert.Equal(t, pID.Id, newPID.Id)
+			}
+			assert.Equal(t, i, pID.Index)
+		}
+		params, err := reshare.Parameters(extra)
+		if assert.NoError(t, err) {
+			assert.True(t, params.IsNewCommittee())
+			assert.False(t, params.IsOldCommittee())
+			assert.Equal(t, 4, params.NewPartyCount())
+		}
+		params, err = reshare.Parameters(pIDs[0])
+		if assert.NoError(t, err) {
+			assert.True(t, params.IsOldCommittee())
+			assert.True(t, params.IsNewCommittee())
+			assert.Same(t, pIDs[0], params.OldCommitteePartyID())
+			assert.Same(t, reshare.NewParties.FindByKey(pIDs[0].KeyInt()), params.NewCommitteePartyID())
+		}
+		_, err = reshare.Parameters(GenerateTestPartyIDs(1)[0])
+		assert.Error(t, err)
+	}
+
+	reshare, err = RemoveParty(S256(), pIDs, 1, pIDs[1])
+	if assert.NoError(t, err) {
+		assert.Len(t, reshare.NewParties, 2)
+		for _, pID := range reshare.NewParties {
+			assert.NotEqual(t, pIDs[1].Id, pID.Id)
+		}
+	}
+	_, err = RemoveParty(S256(), pIDs, 2, pIDs[1])
+	assert.Error(t, err, "2 parties cannot meet the threshold 2")
+	_, err = RemoveParty(S256(), pIDs, 1, extra)
+	assert.Error(t, err, "the party is not in the committee")
+
+	reshare, err = ChangeThreshold(S256(), pIDs, 1, 2)
+	if assert.NoError(t, err) {
+		assert.Len(t, reshare.NewParties, 3)
+		assert.Equal(t, 1, reshare.OldThreshold)
+		assert.Equal(t, 2, reshare.NewThreshold)
+	}
+	_, err = ChangeThreshold(S256(), pIDs, 1, 3)
+	assert.Error(t, err)
+	_, err = ChangeThreshold(S256(), pIDs[:1], 1, 1)
+	assert.Error(t, err)
+
+	_, err = NewReshare(S256(), pIDs, pIDs, 1, 1)
+	assert.Error(t, err, "a party of both committees needs an id for each")
+	_, err = NewReshare(S256(), pIDs, append(GenerateTestPartyIDs(1), pIDs[0], pIDs[0]), 1, 1)
+	assert.Error(t, err, "a key cannot be twice in a committee")
+}
+
+func TestReshareWithOldQuorum(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(5)
+	reshare, err := RemoveParty(S256(), pIDs, 2, pIDs[1])
+	if !assert.NoError(t, err) {
+		return
+	}
+	online := SortedPartyIDs{pIDs[4], pIDs[0], pIDs[3], pIDs[2]}
+	quorum, err := reshare.WithOldQuorum(online)
+	if assert.NoError(t, err) {
+		// the first t+1 online parties in the order of the committee
+		assert.Equal(t, []string{pIDs[0].Id, pIDs[2].Id, pIDs[3].Id}, []string{quorum.OldParties[0].Id, quorum.OldParties[1].Id, quorum.OldParties[2].Id})
+		for i, pID := range quorum.OldParties {
+			assert.Equal(t, i, pID.Index)
+		}
+		assert.Equal(t, reshare.NewParties, quorum.NewParties)
+		// the parties of the full committee keep their indexes
+		for i, pID := range pIDs {
+			assert.Equal(t, i, pID.Index)
+		}
+		params, err := quorum.Parameters(pIDs[3])
+		if assert.NoError(t, err) {
+			assert.Equal(t, 2, params.PartyID().Index)
+			assert.Len(t, params.OldParties().IDs(), 3)
+		}
+		params, err = quorum.Parameters(pIDs[4])
+		if assert.NoError(t, err) {
+			assert.False(t, params.IsOldCommittee(), "a party outside of the quorum only receives")
+			assert.True(t, params.IsNewCommittee())
+		}
+		_, err = quorum.Parameters(pIDs[1])
+		assert.Error(t, err, "the removed party takes no part")
+	}
+	_, err = reshare.WithOldQuorum(SortedPartyIDs{pIDs[0], pIDs[3]})
+	assert.Error(t, err)
+}
@@ -0,0 +1,108 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"sync"
+	"time"
+)
+
+type (
+	// RoundTimeoutFunc is called when a round of `party` has waited longer than the round timeout for the messages
+	// of the parties in `waitingFor`. The party keeps waiting; the callback may e.g. resend the messages of the party
+	// with OutgoingMessages, ask the late parties to resend theirs, or abort the protocol.
+	RoundTimeoutFunc func(party *PartyID, round int, waitingFor []*PartyID)
+
+	// outbox keeps the messages that a party sent in its last two rounds: a party that has moved on to the next
+	// round may have peers that are still waiting for its messages of the previous one
+	outbox struct {
+		mtx        sync.Mutex
+		prev, curr []Message
+	}
+)
+
+// RoundTimeout returns the deadline of every round set with SetRoundTimeout, or 0 if the rounds do not time out
+func (params *Parameters) RoundTimeout() time.Duration {
+	return params.roundTimeout
+}
+
+// SetRoundTimeout calls `onTimeout` whenever a round of the party has not received all of its messages `timeout`
+// after it started. It must be called before the party is started.
+func (params *Parameters) SetRoundTimeout(timeout time.Duration, onTimeout RoundTimeoutFunc) {
+	params.roundTimeout, params.onRoundTimeout = timeout, onTimeout
+}
+
+// SendMessage sends `msg` to `out` and keeps it until the round after the next one starts, so that it can be sent
+// again with OutgoingMessages. The rounds of every protocol send their messages with it, which stamps them with the
+// name of the curve of the party and with its compression, and signs them with the origin key of the party.
+func (params *Parameters) SendMessage(out chan<- Message, msg Message) {
+	if wire := msg.WireMsg(); wire != nil {
+		if name, ok := GetCurveName(params.EC()); ok && wire.Curve == "" {
+			wire.Curve = string(name)
+		}
+		if wire.Compression == "" {
+			wire.Compression = params.compression
+		}
+		params.signOrigin(msg)
+	}
+	params.outbox.mtx.Lock()
+	params.outbox.curr = append(params.outbox.curr, msg)
+	params.outbox.mtx.Unlock()
+	params.observeMessage(msg, true)
+	params.recordMessage(msg, true)
+	if params.listener != nil {
+		params.listener.OnMessageSent(params.PartyID(), msg)
+	}
+	out <- msg
+}
+
+// OutgoingMessages returns the messages that the party sent in its current and previous rounds, e.g. to send them
+// again to the parties that it is waiting for when a round times out
+func (p *BaseParty) OutgoingMessages() []Message {
+	p.lock()
+	defer p.unlock()
+	if p.rnd == nil {
+		return []Message{}
+	}
+	o := p.rnd.Params().outbox
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	return append(append([]Message{}, o.prev...), o.curr...)
+}
+
+// beginRound is called with the party locked when a round is set and before it starts: it rotates the outbox of the
+// party and sets the timer of the round. When the party has finished it stops the timer.
+func (p *BaseParty) beginRound(task string) {
+	if p.timer != nil {
+		p.timer.Stop()
+		p.timer = nil
+	}
+	p.observeRound(task)
+	round := p.rnd
+	if round == nil {
+		return
+	}
+	params := round.Params()
+	o := params.outbox
+	o.mtx.Lock()
+	o.prev, o.curr = o.curr, nil
+	o.mtx.Unlock()
+	if params.roundTimeout <= 0 || params.onRoundTimeout == nil {
+		return
+	}
+	p.timer = time.AfterFunc(params.roundTimeout, func() {
+		p.lock()
+		if p.rnd != round {
+			p.unlock()
+			return
+		}
+		number, waitingFor := round.RoundNumber(), round.WaitingFor()
+		p.unlock()
+		params.Logger().Warnf("party %s: round %d timed out waiting for %v", params.PartyID(), number, waitingFor)
+		params.onRoundTimeout(params.PartyID(), number, waitingFor)
+	})
+}
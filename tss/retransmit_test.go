@@ -0,0 +1,95 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/eddsa/keygen"
+	"github.com/kisdex/mpc-lib/test"
+	. "github.com/kisdex/mpc-lib/tss"
+)
+
+type roundTimeout struct {
+	party      *PartyID
+	round      int
+	waitingFor []*PartyID
+}
+
+func TestRoundTimeoutAndResend(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(test.TestParticipants)
+	p2pCtx := NewPeerContext(pIDs)
+	errCh := make(chan *Error, len(pIDs))
+	outCh := make(chan Message, len(pIDs)*len(pIDs)*3)
+	endCh := make(chan *keygen.LocalPartySaveData, len(pIDs))
+	timeoutCh := make(chan roundTimeout, len(pIDs)*4)
+
+	parties := make([]*keygen.LocalParty, len(pIDs))
+	for i := range pIDs {
+		params := NewParameters(Edwards(), p2pCtx, pIDs[i], len(pIDs), test.TestThreshold)
+		params.SetRoundTimeout(50*time.Millisecond, func(party *PartyID, round int, waitingFor []*PartyID) {
+			timeoutCh <- roundTimeout{party, round, waitingFor}
+		})
+		parties[i] = keygen.NewLocalParty(params, outCh, endCh).(*keygen.LocalParty)
+	}
+	for _, P := range parties {
+		if !assert.Nil(t, P.Start()) {
+			return
+		}
+	}
+
+	deliver := func(msg Message, to *keygen.LocalParty) {
+		if dest := msg.GetTo(); dest != nil && dest[0].Index != to.PartyID().Index {
+			return
+		}
+		test.SharedPartyUpdater(to, msg, errCh)
+	}
+	// the network loses the round 1 broadcast of the last party to the first one
+	lost := false
+	route := func(msg Message) {
+		for _, P := range parties {
+			if !lost && msg.GetFrom().Index == len(pIDs)-1 && P.PartyID().Index == 0 &&
+				msg.Type() == "binance.tsslib.eddsa.keygen.KGRound1Message" {
+				lost = true
+				continue
+			}
+			deliver(msg, P)
+		}
+	}
+	timeouts := 0
+	for len(endCh) < len(pIDs) {
+		// route the queued messages before a resend, which could otherwise deliver the lost message before it is lost
+		if 0 < len(outCh) {
+			route(<-outCh)
+			continue
+		}
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+		case msg := <-outCh:
+			route(msg)
+		case to := <-timeoutCh:
+			// the parties that wait on the first one in the next round may time out too
+			if to.party.Index == 0 && to.round == 1 {
+				timeouts++
+				assert.Contains(t, to.waitingFor, pIDs[len(pIDs)-1])
+			}
+			for _, late := range to.waitingFor {
+				for _, msg := range parties[late.Index].OutgoingMessages() {
+					deliver(msg, parties[to.party.Index])
+				}
+			}
+		case <-time.After(5 * time.Second):
+			assert.FailNow(t, "keygen is stuck")
+		}
+	}
+	assert.True(t, lost)
+	assert.NotZero(t, timeouts, "the round of the first party must time out")
+}
@@ -0,0 +1,65 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"math/big"
+)
+
+// SecurityLevel is the computational security, in bits, of the Paillier and ring-Pedersen moduli that a party
+// generates in the ECDSA protocols and accepts from its peers.
+//
+// The statistical parameter epsilon of the range proofs of crypto/zkproofs does not depend on the level: it is
+// twice the bit size of the curve, which hides the witnesses within 2^-256 on a 256-bit curve at every level.
+type SecurityLevel int
+
+const (
+	// SecurityLevel112 uses 2048-bit moduli; it is the default
+	SecurityLevel112 SecurityLevel = 112
+	// SecurityLevel128 uses 3072-bit moduli
+	SecurityLevel128 SecurityLevel = 128
+	// SecurityLevel140 uses 4096-bit moduli, commonly estimated at about 140 bits of security
+	SecurityLevel140 SecurityLevel = 140
+
+	defaultSecurityLevel = SecurityLevel112
+	maxSecurityLevel     = SecurityLevel140
+)
+
+// ModulusBits returns the size of the Paillier and ring-Pedersen moduli of the level, or 0 for an unknown level
+func (level SecurityLevel) ModulusBits() int {
+	switch level {
+	case SecurityLevel112:
+		return 2048
+	case SecurityLevel128:
+		return 3072
+	case SecurityLevel140:
+		return 4096
+	}
+	return 0
+}
+
+// ValidModulus reports whether a modulus received from a peer has at least the size of the level. It must not be
+// larger than the moduli of the highest level, which bounds the work that a peer can cause; peers with different
+// levels may run a protocol together as long as each accepts the moduli of the others.
+func (level SecurityLevel) ValidModulus(N *big.Int) bool {
+	bits := N.BitLen()
+	return level.ModulusBits() <= bits && bits <= maxSecurityLevel.ModulusBits()
+}
+
+// SecurityLevel returns the level set with SetSecurityLevel, or SecurityLevel112
+func (params *Parameters) SecurityLevel() SecurityLevel {
+	if params.securityLevel == 0 {
+		return defaultSecurityLevel
+	}
+	return params.securityLevel
+}
+
+// SetSecurityLevel sets the size of the moduli that the party generates and the minimum size of those of its peers.
+// Pre-parameters given to a party must have been generated for the same level, see keygen.GeneratePreParamsForLevel.
+func (params *Parameters) SetSecurityLevel(level SecurityLevel) {
+	params.securityLevel = level
+}
@@ -0,0 +1,108 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/kisdex/mpc-lib/tss"
+)
+
+func TestSecurityLevel(t *testing.T) {
+	params := NewParameters(EC(), nil, nil, 2, 1)
+	assert.Equal(t, SecurityLevel112, params.SecurityLevel())
+	params.SetSecurityLevel(SecurityLevel128)
+	assert.Equal(t, 3072, params.SecurityLevel().ModulusBits())
+
+	modulus := func(bits int) *big.Int {
+		return new(big.Int).Lsh(big.NewInt(1), uint(bits-1))
+	}
+	assert.True(t, SecurityLevel112.ValidModulus(modulus(2048)))
+	assert.True(t, SecurityLevel112.ValidModulus(modulus(3072)))
+	assert.False(t, SecurityLevel112.ValidModulus(modulus(2047)))
+	assert.False(t, SecurityLevel128.ValidModulus(modulus(2048)))
+	assert.True(t, SecurityLevel140.ValidModulus(modulus(4096)))
+	assert.False(t, SecurityLevel112.ValidModulus(modulus(4097)))
+}
+
+func TestCheckProductionSafety(t *testing.T) {
+	params := NewParameters(EC(), nil, nil, 2, 1)
+	assert.NoError(t, params.CheckProductionSafety())
+
+	for name, set := range map[string]func(*Parameters){
+		"no Πmod":         (*Parameters).SetNoProofMod,
+		"no Πfac":         (*Parameters).SetNoProofFac,
+		"deferred proofs": (*Parameters).SetDeferProofs,
+		"proof aggregator": func(params *Parameters) {
+			params.SetProofAggregator(GenerateTestPartyIDs(1)[0])
+		},
+	} {
+		params := NewParameters(EC(), nil, nil, 2, 1)
+		set(params)
+		assert.Error(t, params.CheckProductionSafety(), name)
+		params.UnsafeAllowInsecureProofs()
+		assert.NoError(t, params.CheckProductionSafety(), name)
+	}
+}
+
+func TestCheckProductionSafetyOnResume(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(2)
+	params := NewParameters(EC(), NewPeerContext(pIDs), pIDs[0], len(pIDs), 1)
+	params.SetDeferProofs()
+	cp := &Checkpoint{Version: CheckpointVersion, Task: stubTask, Round: 1}
+	restore := func(Round) *Error { return nil }
+
+	err := BaseResume(newStubParty(params), stubTask, cp, restore)
+	if assert.Error(t, err, "a party must not resume with deferred proofs in production mode") {
+		assert.ErrorIs(t, err, ErrInvalidState)
+	}
+
+	params.UnsafeAllowInsecureProofs()
+	assert.Nil(t, BaseResume(newStubParty(params), stubTask, cp, restore))
+}
+
+const stubTask = "stub"
+
+type (
+	// stubParty is a party of a single round that waits for messages forever
+	stubParty struct {
+		*BaseParty
+		params *Parameters
+	}
+
+	stubRound struct {
+		params *Parameters
+	}
+)
+
+func newStubParty(params *Parameters) *stubParty {
+	return &stubParty{BaseParty: new(BaseParty), params: params}
+}
+
+func (p *stubParty) Start() *Error                           { return BaseStart(p, stubTask) }
+func (p *stubParty) Update(msg ParsedMessage) (bool, *Error) { return BaseUpdate(p, msg, stubTask) }
+func (p *stubParty) UpdateFromBytes([]byte, *PartyID, bool) (bool, *Error) {
+	return false, nil
+}
+func (p *stubParty) StoreMessage(ParsedMessage) (bool, *Error) { return true, nil }
+func (p *stubParty) FirstRound() Round                         { return &stubRound{p.params} }
+func (p *stubParty) PartyID() *PartyID                         { return p.params.PartyID() }
+
+func (round *stubRound) Params() *Parameters          { return round.params }
+func (round *stubRound) Start() *Error                { return nil }
+func (round *stubRound) Update() (bool, *Error)       { return true, nil }
+func (round *stubRound) RoundNumber() int             { return 1 }
+func (round *stubRound) CanAccept(ParsedMessage) bool { return false }
+func (round *stubRound) CanProceed() bool             { return false }
+func (round *stubRound) NextRound() Round             { return nil }
+func (round *stubRound) WaitingFor() []*PartyID       { return nil }
+func (round *stubRound) WrapError(err error, culprits ...*PartyID) *Error {
+	return NewError(err, stubTask, 1, round.params.PartyID(), culprits...)
+}
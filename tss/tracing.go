@@ -0,0 +1,151 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"sync"
+)
+
+// Attribute keys of the spans of a party, which are also the keys of the pprof labels of its goroutines
+const (
+	AttributeParty = "tss.party"
+	AttributeTask  = "tss.task"
+	AttributeRound = "tss.round"
+	AttributeFrom  = "tss.from"
+	AttributePeer  = "tss.peer"
+	AttributeProof = "tss.proof"
+)
+
+type (
+	// Tracer starts the spans of the work of a party, e.g. as OpenTelemetry spans. The library does not depend on a
+	// tracing backend; an implementation typically wraps an OpenTelemetry trace.Tracer and the context of the
+	// ceremony, which all of the parties of a committee derive from the same trace id to be seen together:
+	//
+	//	func (t *otelTracer) Start(name string, attributes ...tss.Attribute) tss.Span {
+	//		ctx, span := t.tracer.Start(t.ctx, name, trace.WithAttributes(otelAttributes(attributes)...))
+	//		return &otelSpan{ctx: ctx, tracer: t.tracer, span: span}
+	//	}
+	//
+	// A party traces the start of each of its rounds, in which it computes and sends its messages, each update of a
+	// round with a message, and each verification of a proof or a share sent by a peer within them.
+	Tracer interface {
+		// Start starts a span
+		Start(name string, attributes ...Attribute) Span
+	}
+
+	// Span is a span started by a Tracer. Its methods may be called concurrently.
+	Span interface {
+		// Start starts a child span
+		Start(name string, attributes ...Attribute) Span
+		// SetAttributes adds attributes to the span
+		SetAttributes(attributes ...Attribute)
+		// End ends the span, which failed if `err` is not nil
+		End(err error)
+	}
+
+	// Attribute is an attribute of a span: its value is a string, an int or a bool
+	Attribute struct {
+		Key   string
+		Value any
+	}
+
+	// traceState is the span of a party in which its proofs are verified
+	traceState struct {
+		mtx     sync.Mutex
+		current Span
+	}
+)
+
+// Tracer returns the tracer set with SetTracer, or nil
+func (params *Parameters) Tracer() Tracer {
+	return params.tracer
+}
+
+// SetTracer makes the party trace its work with `tracer`. It must be called before the party is started.
+func (params *Parameters) SetTracer(tracer Tracer) {
+	params.tracer, params.trace = tracer, new(traceState)
+}
+
+// startSpan starts a span of the party as a child of its current span, if any; it returns nil without a tracer
+func (params *Parameters) startSpan(name string, attributes ...Attribute) Span {
+	if params.tracer == nil {
+		return nil
+	}
+	params.trace.mtx.Lock()
+	parent := params.trace.current
+	params.trace.mtx.Unlock()
+	attributes = append(attributes, Attribute{AttributeParty, params.partyID.Id})
+	if parent != nil {
+		return parent.Start(name, attributes...)
+	}
+	return params.tracer.Start(name, attributes...)
+}
+
+// setSpan makes `span` the current span of the party, the parent of the spans of the proofs
+func (params *Parameters) setSpan(span Span) {
+	params.trace.mtx.Lock()
+	params.trace.current = span
+	params.trace.mtx.Unlock()
+}
+
+// startRound is called with the party locked to start its current round in a span, with the pprof labels of the round
+func startRound(p Party, task string) (err *Error) {
+	round := p.round()
+	round.Params().doLabeled(task, round.RoundNumber(), func() {
+		err = traceStart(round, task)
+	})
+	return
+}
+
+// updateRound is called with the party locked to update its current round with `msg` in a span, with the pprof labels
+// of the round
+func updateRound(p Party, task string, msg ParsedMessage) (ok bool, err *Error) {
+	round := p.round()
+	round.Params().doLabeled(task, round.RoundNumber(), func() {
+		ok, err = traceUpdate(round, task, msg)
+	})
+	return
+}
+
+func traceStart(round Round, task string) *Error {
+	params := round.Params()
+	if params.tracer == nil {
+		return round.Start()
+	}
+	span := params.startSpan(task+" round", Attribute{AttributeTask, task})
+	params.setSpan(span)
+	err := round.Start()
+	params.setSpan(nil)
+	span.SetAttributes(Attribute{AttributeRound, round.RoundNumber()})
+	endSpan(span, err)
+	return err
+}
+
+func traceUpdate(round Round, task string, msg ParsedMessage) (bool, *Error) {
+	params := round.Params()
+	if params.tracer == nil {
+		return round.Update()
+	}
+	attributes := []Attribute{{AttributeTask, task}, {AttributeRound, round.RoundNumber()}}
+	if msg != nil {
+		attributes = append(attributes, Attribute{AttributeFrom, msg.GetFrom().Id})
+	}
+	span := params.startSpan(task+" update", attributes...)
+	params.setSpan(span)
+	ok, err := round.Update()
+	params.setSpan(nil)
+	endSpan(span, err)
+	return ok, err
+}
+
+func endSpan(span Span, err *Error) {
+	if err != nil {
+		span.End(err)
+	} else {
+		span.End(nil)
+	}
+}
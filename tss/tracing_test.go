@@ -0,0 +1,133 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/eddsa/keygen"
+	"github.com/kisdex/mpc-lib/test"
+	. "github.com/kisdex/mpc-lib/tss"
+)
+
+type (
+	recordingTracer struct {
+		mtx   sync.Mutex
+		spans []*recordingSpan
+	}
+
+	recordingSpan struct {
+		tracer     *recordingTracer
+		parent     *recordingSpan
+		name       string
+		attributes map[string]any
+		ended      bool
+		err        error
+	}
+)
+
+func (t *recordingTracer) Start(name string, attributes ...Attribute) Span {
+	return t.start(nil, name, attributes)
+}
+
+func (t *recordingTracer) start(parent *recordingSpan, name string, attributes []Attribute) *recordingSpan {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	span := &recordingSpan{tracer: t, parent: parent, name: name, attributes: make(map[string]any)}
+	for _, attr := range attributes {
+		span.attributes[attr.Key] = attr.Value
+	}
+	t.spans = append(t.spans, span)
+	return span
+}
+
+func (s *recordingSpan) Start(name string, attributes ...Attribute) Span {
+	return s.tracer.start(s, name, attributes)
+}
+
+func (s *recordingSpan) SetAttributes(attributes ...Attribute) {
+	s.tracer.mtx.Lock()
+	defer s.tracer.mtx.Unlock()
+	for _, attr := range attributes {
+		s.attributes[attr.Key] = attr.Value
+	}
+}
+
+func (s *recordingSpan) End(err error) {
+	s.tracer.mtx.Lock()
+	defer s.tracer.mtx.Unlock()
+	s.ended, s.err = true, err
+}
+
+func TestTracer(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(test.TestParticipants)
+	errCh := make(chan *Error, len(pIDs))
+	outCh := make(chan Message, len(pIDs)*len(pIDs)*3)
+	endCh := make(chan *keygen.LocalPartySaveData, len(pIDs))
+	tracer := &recordingTracer{}
+	parties := newKeygenParties(pIDs, outCh, endCh, func(params *Parameters) {
+		if params.PartyID().Index == 0 {
+			params.SetTracer(tracer)
+		}
+	})
+	for _, P := range parties {
+		if !assert.Nil(t, P.Start()) {
+			return
+		}
+	}
+	for len(endCh) < len(pIDs) {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+		case msg := <-outCh:
+			for _, P := range parties {
+				if P.PartyID().Index == msg.GetFrom().Index {
+					continue
+				}
+				if dest := msg.GetTo(); dest != nil && dest[0].Index != P.PartyID().Index {
+					continue
+				}
+				test.SharedPartyUpdater(P, msg, errCh)
+			}
+		case <-time.After(5 * time.Second):
+			assert.FailNow(t, "keygen is stuck")
+		}
+	}
+
+	var rounds []any
+	updates, proofs := 0, make(map[any]int)
+	for _, span := range tracer.spans {
+		assert.True(t, span.ended, "span %s must end", span.name)
+		assert.NoError(t, span.err)
+		assert.Equal(t, pIDs[0].Id, span.attributes[AttributeParty])
+		switch span.name {
+		case keygen.TaskName + " round":
+			assert.Nil(t, span.parent)
+			rounds = append(rounds, span.attributes[AttributeRound])
+		case keygen.TaskName + " update":
+			assert.Nil(t, span.parent)
+			assert.NotEqual(t, pIDs[0].Id, span.attributes[AttributeFrom])
+			updates++
+		default:
+			// the proofs of the peers are verified in round 3, when it starts
+			if assert.NotNil(t, span.parent) {
+				assert.Equal(t, keygen.TaskName+" round", span.parent.name)
+				assert.Equal(t, 3, span.parent.attributes[AttributeRound])
+			}
+			proofs[span.attributes[AttributeProof]]++
+		}
+	}
+	others := len(pIDs) - 1
+	assert.Equal(t, []any{1, 2, 3}, rounds)
+	// an update with each message, and one more of rounds 2 and 3 once they started
+	assert.Equal(t, 3*others+2, updates)
+	assert.Equal(t, map[any]int{"schnorr": others, "vss": others}, proofs)
+}
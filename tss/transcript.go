@@ -0,0 +1,197 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"sync"
+	"time"
+)
+
+type (
+	// TranscriptRecorder receives every message that a party sends or receives, in the order in which the party
+	// sends or processes them, e.g. to write the transcript of a session to a file with TranscriptWriter. The methods
+	// are called with the party locked, so they must not call the party.
+	//
+	// The point-to-point messages of some protocols carry secret shares in the clear, so a transcript must be stored
+	// as securely as the save data of the party.
+	TranscriptRecorder interface {
+		RecordMessage(entry TranscriptEntry)
+	}
+
+	// TranscriptEntry is a message of a transcript with its sender, which is all that is needed to parse it again
+	TranscriptEntry struct {
+		Time        time.Time `json:"time"`
+		Outgoing    bool      `json:"outgoing"`
+		Type        string    `json:"type"`
+		From        string    `json:"from"`
+		FromMoniker string    `json:"from_moniker"`
+		FromKey     []byte    `json:"from_key"`
+		FromIndex   int       `json:"from_index"`
+		Broadcast   bool      `json:"broadcast"`
+		Wire        []byte    `json:"wire"`
+	}
+
+	// Transcript is the messages of a session of a party in the order of a TranscriptRecorder
+	Transcript []TranscriptEntry
+
+	// TranscriptWriter is a TranscriptRecorder that writes every entry as a line of JSON
+	TranscriptWriter struct {
+		mtx sync.Mutex
+		enc *json.Encoder
+		err error
+	}
+)
+
+// TranscriptRecorder returns the recorder set with SetTranscriptRecorder, or nil
+func (params *Parameters) TranscriptRecorder() TranscriptRecorder {
+	return params.transcript
+}
+
+// SetTranscriptRecorder makes the party record its messages with `recorder`. It must be called before the party is
+// started.
+func (params *Parameters) SetTranscriptRecorder(recorder TranscriptRecorder) {
+	params.transcript = recorder
+}
+
+func (params *Parameters) recordMessage(msg Message, outgoing bool) {
+	if params.transcript == nil || msg == nil || msg.GetFrom() == nil {
+		return
+	}
+	wire, _, err := msg.WireBytes()
+	if err != nil {
+		params.Logger().Warnf("could not record a message %s: %v", msg.Type(), err)
+		return
+	}
+	from := msg.GetFrom()
+	params.transcript.RecordMessage(TranscriptEntry{
+		Time:        time.Now(),
+		Outgoing:    outgoing,
+		Type:        msg.Type(),
+		From:        from.Id,
+		FromMoniker: from.Moniker,
+		FromKey:     from.Key,
+		FromIndex:   from.Index,
+		Broadcast:   msg.IsBroadcast(),
+		Wire:        wire,
+	})
+}
+
+// recordReceived is called with `p` locked when it is about to process `msg`, or has rejected it as invalid
+func recordReceived(p Party, msg ParsedMessage) {
+	round := p.round()
+	if round == nil {
+		// the party has not started yet
+		round = p.FirstRound()
+	}
+	if round != nil && round.Params() != nil {
+		round.Params().recordMessage(msg, false)
+	}
+}
+
+// NewTranscriptWriter returns a TranscriptWriter that writes to `w`
+func NewTranscriptWriter(w io.Writer) *TranscriptWriter {
+	return &TranscriptWriter{enc: json.NewEncoder(w)}
+}
+
+func (tw *TranscriptWriter) RecordMessage(entry TranscriptEntry) {
+	tw.mtx.Lock()
+	defer tw.mtx.Unlock()
+	if tw.err == nil {
+		tw.err = tw.enc.Encode(entry)
+	}
+}
+
+// Err returns the first error of the writer, after which it stops writing
+func (tw *TranscriptWriter) Err() error {
+	tw.mtx.Lock()
+	defer tw.mtx.Unlock()
+	return tw.err
+}
+
+// ReadTranscript reads a transcript written by a TranscriptWriter
+func ReadTranscript(r io.Reader) (Transcript, error) {
+	var transcript Transcript
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 2*messageLimits.MaxMessageSize+4096)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry TranscriptEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, err
+		}
+		transcript = append(transcript, entry)
+	}
+	return transcript, scanner.Err()
+}
+
+// Sent returns the wire bytes of the messages that the party sent
+func (t Transcript) Sent() [][]byte {
+	var sent [][]byte
+	for _, entry := range t {
+		if entry.Outgoing {
+			sent = append(sent, entry.Wire)
+		}
+	}
+	return sent
+}
+
+// ReplayTranscript starts `party` and updates it with the messages that were received in `transcript`, in their
+// recorded order, until the first error of the party, which it returns. The party must be built with the
+// parameters and the save data of the party that recorded the transcript; it sends its messages on `out`, which
+// must buffer the messages of a step, and ReplayTranscript returns their wire bytes in order.
+//
+// The messages of the party depend on its randomness: a replay sends the messages of Transcript.Sent again only as
+// long as the party draws the values of the recording, e.g. from the same common.NewDeterministicReader in a build
+// with the tss_deterministic tag. The messages of the other parties are checked the same either way, so that a replay
+// reproduces e.g. a proof that failed to verify, unless the proof is bound to values that the party drew.
+func ReplayTranscript(party Party, transcript Transcript, out <-chan Message) ([][]byte, *Error) {
+	var sent [][]byte
+	drain := func() *Error {
+		for {
+			select {
+			case msg := <-out:
+				wire, _, err := msg.WireBytes()
+				if err != nil {
+					return party.WrapError(err)
+				}
+				sent = append(sent, wire)
+			default:
+				return nil
+			}
+		}
+	}
+	if err := party.Start(); err != nil {
+		return sent, err
+	}
+	if err := drain(); err != nil {
+		return sent, err
+	}
+	for _, entry := range transcript {
+		if entry.Outgoing {
+			continue
+		}
+		if entry.FromKey == nil {
+			return sent, party.WrapError(errors.New("a received message of the transcript has no sender"))
+		}
+		from := NewPartyID(entry.From, entry.FromMoniker, new(big.Int).SetBytes(entry.FromKey))
+		from.Index = entry.FromIndex
+		if _, err := party.UpdateFromBytes(entry.Wire, from, entry.Broadcast); err != nil {
+			return sent, err
+		}
+		if err := drain(); err != nil {
+			return sent, err
+		}
+	}
+	return sent, nil
+}
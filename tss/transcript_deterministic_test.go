@@ -0,0 +1,83 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+//go:build tss_deterministic
+
+package tss_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/common"
+	"github.com/kisdex/mpc-lib/eddsa/keygen"
+	"github.com/kisdex/mpc-lib/test"
+	. "github.com/kisdex/mpc-lib/tss"
+)
+
+func TestTranscriptRecordAndReplay(t *testing.T) {
+	seed := []byte("transcript")
+	common.SetRandomSource(common.NewDeterministicReader(seed))
+	defer common.SetRandomSource(nil)
+
+	pIDs := GenerateTestPartyIDs(test.TestParticipants)
+	outCh := make(chan Message, len(pIDs)*len(pIDs))
+	endCh := make(chan *keygen.LocalPartySaveData, len(pIDs))
+	var recorded bytes.Buffer
+	writer := NewTranscriptWriter(&recorded)
+	parties := newKeygenParties(pIDs, outCh, endCh, func(params *Parameters) {
+		if params.PartyID().Index == 0 {
+			params.SetTranscriptRecorder(writer)
+		}
+	})
+	all, err := test.RunSequentially(parties, outCh)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.NoError(t, writer.Err())
+	saved := make(map[string]*keygen.LocalPartySaveData, len(pIDs))
+	for len(endCh) > 0 {
+		data := <-endCh
+		saved[string(data.ShareID.Bytes())] = data
+	}
+
+	transcript, rerr := ReadTranscript(&recorded)
+	if !assert.NoError(t, rerr) {
+		return
+	}
+	received := 0
+	for _, entry := range transcript {
+		if entry.Outgoing {
+			assert.Equal(t, pIDs[0].Id, entry.From)
+		} else {
+			received++
+			assert.NotEqual(t, pIDs[0].Id, entry.From)
+		}
+	}
+	assert.NotEmpty(t, transcript.Sent())
+	assert.Equal(t, (len(pIDs)-1)*3, received, "party 0 receives the message of round 1 and the two of round 2 of each other party")
+	assert.Subset(t, all, transcript.Sent())
+
+	// the same random values and the same messages make the same party. The proof of round 2 draws its nonce after the
+	// other parties have drawn theirs in the recording, so only the messages of round 1 are the same byte for byte.
+	common.SetRandomSource(common.NewDeterministicReader(seed))
+	outCh = make(chan Message, len(pIDs)*len(pIDs))
+	endCh = make(chan *keygen.LocalPartySaveData, 1)
+	replayed := newKeygenParties(pIDs, outCh, endCh, nil)[0]
+	sent, err := ReplayTranscript(replayed, transcript, outCh)
+	if !assert.Nil(t, err) {
+		return
+	}
+	if assert.Len(t, sent, len(transcript.Sent())) {
+		assert.Equal(t, transcript.Sent()[0], sent[0])
+	}
+	if assert.Len(t, endCh, 1) {
+		data := <-endCh
+		assert.Equal(t, saved[string(data.ShareID.Bytes())].Xi, data.Xi)
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kisdex/mpc-lib/eddsa/keygen"
+	"github.com/kisdex/mpc-lib/test"
+	. "github.com/kisdex/mpc-lib/tss"
+)
+
+func TestReplayReproducesFailure(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(test.TestParticipants)
+	outCh := make(chan Message, len(pIDs)*len(pIDs))
+	endCh := make(chan *keygen.LocalPartySaveData, len(pIDs))
+	var recorded bytes.Buffer
+	parties := newKeygenParties(pIDs, outCh, endCh, func(params *Parameters) {
+		if params.PartyID().Index == 0 {
+			params.SetTranscriptRecorder(NewTranscriptWriter(&recorded))
+		}
+	})
+	_, err := test.RunSequentially(parties, outCh)
+	if !assert.Nil(t, err) {
+		return
+	}
+	transcript, rerr := ReadTranscript(&recorded)
+	if !assert.NoError(t, rerr) {
+		return
+	}
+
+	// party 2 sends the decommitment of party 1, as if a relay had mixed them up
+	decommitments := make(map[int]int)
+	for i, entry := range transcript {
+		if !entry.Outgoing && entry.Type == "binance.tsslib.eddsa.keygen.KGRound2Message2" {
+			decommitments[entry.FromIndex] = i
+		}
+	}
+	if !assert.Len(t, decommitments, 2) {
+		return
+	}
+	transcript[decommitments[2]].Wire = transcript[decommitments[1]].Wire
+
+	outCh = make(chan Message, len(pIDs)*len(pIDs))
+	replayed := newKeygenParties(pIDs, outCh, make(chan *keygen.LocalPartySaveData, 1), nil)[0]
+	_, err = ReplayTranscript(replayed, transcript, outCh)
+	if assert.NotNil(t, err, "the replay must fail where the message was mixed up") {
+		assert.Equal(t, []*PartyID{pIDs[2]}, err.Culprits())
+	}
+}
@@ -8,26 +8,148 @@ package tss
 
 import (
 	"errors"
+	"fmt"
+
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoregistry"
 	"google.golang.org/protobuf/types/known/anypb"
 )
 
-// Used externally to update a LocalParty with a valid ParsedMessage
+const (
+	// WireVersionLegacy is the wire format of older releases, which sent the bare protobuf Any of a message
+	WireVersionLegacy uint32 = 1
+	// WireVersion is the wire format of the messages sent by this release: a MessageWrapper that holds only
+	// the protocol version, the curve name of the sender, the signature of the sender if it has an origin key and
+	// the protobuf Any of the message, which may be compressed
+	WireVersion uint32 = 2
+)
+
+// the wire format versions that ParseWireMessage accepts, oldest first
+var supportedWireVersions = []uint32{WireVersionLegacy, WireVersion}
+
+// SupportedWireVersions returns the wire format versions that this release can parse, oldest first.
+// Nodes may exchange these lists to agree on a version with NegotiateWireVersion.
+func SupportedWireVersions() []uint32 {
+	return append([]uint32{}, supportedWireVersions...)
+}
+
+// IsSupportedWireVersion reports whether this release can parse messages in the wire format `version`
+func IsSupportedWireVersion(version uint32) bool {
+	for _, v := range supportedWireVersions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
+// NegotiateWireVersion returns the newest wire format version supported by both this release and a peer that
+// supports `peerVersions`
+func NegotiateWireVersion(peerVersions []uint32) (uint32, error) {
+	for i := len(supportedWireVersions) - 1; 0 <= i; i-- {
+		for _, v := range peerVersions {
+			if v == supportedWireVersions[i] {
+				return v, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("no common wire format version: the peer supports %v and this library supports %v",
+		peerVersions, supportedWireVersions)
+}
+
+// EncodeWireMessage encodes a message in the wire format `version`, e.g. for a peer that negotiated an older
+// version than WireVersion
+func EncodeWireMessage(msg Message, version uint32) ([]byte, error) {
+	wire := msg.WireMsg()
+	switch version {
+	case WireVersionLegacy:
+		return proto.Marshal(wire.Message)
+	case WireVersion:
+		out := &MessageWrapper{ProtocolVersion: version, Curve: wire.Curve, Signature: wire.Signature, Message: wire.Message}
+		if wire.Compression != "" {
+			bz, err := proto.Marshal(wire.Message)
+			if err != nil {
+				return nil, err
+			}
+			// e.g. the Paillier ciphertexts of a message may not compress at all, which is then sent as it is
+			if MinCompressedSize <= len(bz) {
+				compressed, err := compress(wire.Compression, bz)
+				if err != nil {
+					return nil, fmt.Errorf("EncodeWireMessage: %w", err)
+				}
+				if len(compressed) < len(bz) {
+					out.Compression, out.CompressedMessage, out.Message = wire.Compression, compressed, nil
+				}
+			}
+		}
+		return proto.Marshal(out)
+	default:
+		return nil, fmt.Errorf("EncodeWireMessage: unsupported wire format version %d", version)
+	}
+}
+
+// Used externally to update a LocalParty with a valid ParsedMessage.
+// It accepts a message of any protocol within the limits of GetMessageLimits; a party of a protocol parses its
+// messages with the ParseWireMessage of the MessageTypes of the protocol instead.
 func ParseWireMessage(wireBytes []byte, from *PartyID, isBroadcast bool) (ParsedMessage, error) {
+	return parseWireMessage(wireBytes, from, isBroadcast, nil)
+}
+
+func parseWireMessage(wireBytes []byte, from *PartyID, isBroadcast bool, types MessageTypes) (ParsedMessage, error) {
+	limits := messageLimits
+	if limits.MaxMessageSize < len(wireBytes) {
+		return nil, fmt.Errorf("ParseWireMessage: the message has %d bytes, more than the limit of %d",
+			len(wireBytes), limits.MaxMessageSize)
+	}
 	wire := new(MessageWrapper)
-	wire.Message = new(anypb.Any)
+	if err := proto.Unmarshal(wireBytes, wire); err != nil || wire.ProtocolVersion == 0 {
+		// a bare Any has no version field, so this is a message of an older release
+		wire.Reset()
+		wire.Message = new(anypb.Any)
+		if err := proto.Unmarshal(wireBytes, wire.Message); err != nil {
+			return nil, fmt.Errorf("ParseWireMessage: could not decode the message in any supported wire format %v: %w",
+				supportedWireVersions, err)
+		}
+		wire.ProtocolVersion = WireVersionLegacy
+	}
+	if !IsSupportedWireVersion(wire.ProtocolVersion) {
+		return nil, fmt.Errorf("ParseWireMessage: the message uses wire format version %d but this library supports versions %v; "+
+			"the parties must run compatible library versions", wire.ProtocolVersion, supportedWireVersions)
+	}
+	if wire.Compression != "" {
+		bz, err := decompress(wire.Compression, wire.CompressedMessage, limits.MaxMessageSize)
+		if err != nil {
+			return nil, fmt.Errorf("ParseWireMessage: could not decompress the message: %w", err)
+		}
+		wire.Message, wire.CompressedMessage = new(anypb.Any), nil
+		if err := proto.Unmarshal(bz, wire.Message); err != nil {
+			return nil, fmt.Errorf("ParseWireMessage: could not decode the decompressed message: %w", err)
+		}
+	}
+	if wire.Message == nil {
+		return nil, fmt.Errorf("ParseWireMessage: the message of wire format version %d has no content", wire.ProtocolVersion)
+	}
 	wire.From = from.MessageWrapper_PartyID
 	wire.IsBroadcast = isBroadcast
-	if err := proto.Unmarshal(wireBytes, wire.Message); err != nil {
-		return nil, err
-	}
-	return parseWrappedMessage(wire, from)
+	return parseWrappedMessage(wire, from, types, limits)
 }
 
-func parseWrappedMessage(wire *MessageWrapper, from *PartyID) (ParsedMessage, error) {
-	m, err := wire.Message.UnmarshalNew()
+func parseWrappedMessage(wire *MessageWrapper, from *PartyID, types MessageTypes, limits MessageLimits) (ParsedMessage, error) {
+	name := wire.Message.MessageName()
+	if types != nil && !types.Contains(name) {
+		return nil, fmt.Errorf("ParseWireMessage: the message %q is not a message of this protocol", name)
+	}
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(name)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("ParseWireMessage: could not decode the message of wire format version %d: %w", wire.ProtocolVersion, err)
+	}
+	fields := 0
+	if err := limits.checkFields(wire.Message.GetValue(), mt.Descriptor(), &fields); err != nil {
+		return nil, fmt.Errorf("ParseWireMessage: the message %q is rejected: %w", name, err)
+	}
+	m := mt.New().Interface()
+	if err := proto.Unmarshal(wire.Message.GetValue(), m); err != nil {
+		return nil, fmt.Errorf("ParseWireMessage: could not decode the message of wire format version %d: %w", wire.ProtocolVersion, err)
 	}
 	meta := MessageRouting{
 		From:        from,
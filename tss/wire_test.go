@@ -0,0 +1,193 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss_test
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/kisdex/mpc-lib/crypto"
+	cmt "github.com/kisdex/mpc-lib/crypto/commitments"
+	"github.com/kisdex/mpc-lib/crypto/schnorr"
+	"github.com/kisdex/mpc-lib/eddsa/keygen"
+	"github.com/kisdex/mpc-lib/eddsa/signing"
+	"github.com/kisdex/mpc-lib/test"
+	. "github.com/kisdex/mpc-lib/tss"
+)
+
+func TestWireVersions(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(2)
+	msg := signing.NewSignRound3Message(pIDs[0], big.NewInt(42))
+
+	bz, _, err := msg.WireBytes()
+	assert.NoError(t, err)
+	parsed, err := ParseWireMessage(bz, pIDs[0], true)
+	assert.NoError(t, err)
+	assert.Equal(t, WireVersion, parsed.WireMsg().GetProtocolVersion())
+	assert.True(t, proto.Equal(msg.Content(), parsed.Content()))
+
+	legacy, err := EncodeWireMessage(msg, WireVersionLegacy)
+	assert.NoError(t, err)
+	parsed, err = ParseWireMessage(legacy, pIDs[0], true)
+	assert.NoError(t, err, "messages of older releases must still be accepted")
+	assert.Equal(t, WireVersionLegacy, parsed.WireMsg().GetProtocolVersion())
+	assert.True(t, proto.Equal(msg.Content(), parsed.Content()))
+
+	future, err := proto.Marshal(&MessageWrapper{ProtocolVersion: WireVersion + 1, Message: msg.WireMsg().Message})
+	assert.NoError(t, err)
+	_, err = ParseWireMessage(future, pIDs[0], true)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "wire format version 3")
+	}
+}
+
+func TestNegotiateWireVersion(t *testing.T) {
+	v, err := NegotiateWireVersion([]uint32{WireVersionLegacy, WireVersion, WireVersion + 1})
+	assert.NoError(t, err)
+	assert.Equal(t, WireVersion, v)
+
+	v, err = NegotiateWireVersion([]uint32{WireVersionLegacy})
+	assert.NoError(t, err)
+	assert.Equal(t, WireVersionLegacy, v)
+
+	_, err = NegotiateWireVersion([]uint32{WireVersion + 1})
+	assert.Error(t, err)
+}
+
+func TestWireCompression(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(2)
+	params := NewParameters(Edwards(), NewPeerContext(pIDs), pIDs[0], len(pIDs), 1)
+	assert.Error(t, params.SetCompression("lz4"), "an unknown compression must be rejected")
+	assert.NoError(t, params.SetCompression(CompressionGzip))
+	out := make(chan Message, 2)
+
+	large := keygen.NewKGRound1Message(pIDs[0], new(big.Int).SetBytes(bytes.Repeat([]byte{0xab}, 4*MinCompressedSize)))
+	params.SendMessage(out, large)
+	plain, err := EncodeWireMessage(keygen.NewKGRound1Message(pIDs[0], new(big.Int).SetBytes(bytes.Repeat([]byte{0xab}, 4*MinCompressedSize))), WireVersion)
+	assert.NoError(t, err)
+	bz, _, err := (<-out).WireBytes()
+	assert.NoError(t, err)
+	assert.Less(t, len(bz), len(plain)/4, "a large message must be compressed")
+	parsed, err := ParseWireMessage(bz, pIDs[0], true)
+	if assert.NoError(t, err) {
+		assert.Equal(t, CompressionGzip, parsed.WireMsg().GetCompression())
+		assert.True(t, proto.Equal(large.Content(), parsed.Content()))
+	}
+
+	small := signing.NewSignRound3Message(pIDs[0], big.NewInt(42))
+	params.SendMessage(out, small)
+	bz, _, err = (<-out).WireBytes()
+	assert.NoError(t, err)
+	parsed, err = ParseWireMessage(bz, pIDs[0], true)
+	if assert.NoError(t, err) {
+		assert.Empty(t, parsed.WireMsg().GetCompression(), "a small message must be sent uncompressed")
+		assert.True(t, proto.Equal(small.Content(), parsed.Content()))
+	}
+
+	unknown, err := proto.Marshal(&MessageWrapper{ProtocolVersion: WireVersion, Compression: "lz4", CompressedMessage: []byte{1, 2, 3}})
+	assert.NoError(t, err)
+	_, err = ParseWireMessage(unknown, pIDs[0], true)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "lz4")
+	}
+
+	assert.Equal(t, CompressionGzip, NegotiateCompression([]string{"zstd", CompressionGzip}))
+	assert.Equal(t, "", NegotiateCompression([]string{"zstd"}), "peers without a common compression send uncompressed messages")
+	assert.Contains(t, SupportedCompressions(), CompressionGzip)
+}
+
+func FuzzParseWireMessage(f *testing.F) {
+	pIDs := GenerateTestPartyIDs(2)
+	msg := signing.NewSignRound3Message(pIDs[0], big.NewInt(42))
+	bz, _, err := msg.WireBytes()
+	if err != nil {
+		f.Fatal(err)
+	}
+	legacy, err := EncodeWireMessage(msg, WireVersionLegacy)
+	if err != nil {
+		f.Fatal(err)
+	}
+	test.FuzzWireMessages(f, Edwards(), [][]byte{bz, legacy, {}})
+}
+
+func TestMessageLimits(t *testing.T) {
+	defer func() { assert.NoError(t, SetMessageLimits(DefaultMessageLimits())) }()
+	pIDs := GenerateTestPartyIDs(2)
+	assert.Error(t, SetMessageLimits(MessageLimits{}), "the limits must be positive")
+
+	huge := keygen.NewKGRound1Message(pIDs[0], new(big.Int).SetBytes(bytes.Repeat([]byte{0xab}, DefaultMaxFieldSize+1)))
+	bz, _, err := huge.WireBytes()
+	assert.NoError(t, err)
+	_, err = ParseWireMessage(bz, pIDs[0], true)
+	if assert.Error(t, err, "a big.Int larger than the field limit must be rejected") {
+		assert.Contains(t, err.Error(), "more than the limit")
+	}
+
+	msg := keygen.NewKGRound1Message(pIDs[0], new(big.Int).SetBytes(bytes.Repeat([]byte{0xab}, 4096)))
+	bz, _, err = msg.WireBytes()
+	assert.NoError(t, err)
+	_, err = ParseWireMessage(bz, pIDs[0], true)
+	assert.NoError(t, err)
+
+	assert.NoError(t, SetMessageLimits(MessageLimits{MaxMessageSize: 1024, MaxFieldSize: DefaultMaxFieldSize, MaxFields: DefaultMaxFields}))
+	_, err = ParseWireMessage(bz, pIDs[0], true)
+	if assert.Error(t, err, "a message larger than the message limit must be rejected") {
+		assert.Contains(t, err.Error(), "more than the limit of 1024")
+	}
+
+	// a compressed message must not expand beyond the limit either
+	params := NewParameters(Edwards(), NewPeerContext(pIDs), pIDs[0], len(pIDs), 1)
+	assert.NoError(t, params.SetCompression(CompressionGzip))
+	out := make(chan Message, 1)
+	params.SendMessage(out, keygen.NewKGRound1Message(pIDs[0], new(big.Int).SetBytes(bytes.Repeat([]byte{0xab}, 4096))))
+	bz, _, err = (<-out).WireBytes()
+	assert.NoError(t, err)
+	assert.Less(t, len(bz), 1024)
+	_, err = ParseWireMessage(bz, pIDs[0], true)
+	assert.Error(t, err)
+
+	assert.NoError(t, SetMessageLimits(MessageLimits{MaxMessageSize: DefaultMaxMessageSize, MaxFieldSize: DefaultMaxFieldSize, MaxFields: 2}))
+	_, err = ParseWireMessage(bz, pIDs[0], true)
+	assert.NoError(t, err)
+	deCommitment := cmt.HashDeCommitment{big.NewInt(1), big.NewInt(2), big.NewInt(3)}
+	many, _, err := signing.NewSignRound2Message(pIDs[0], deCommitment, &schnorr.ZKProof{Alpha: crypto.ScalarBaseMult(Edwards(), big.NewInt(1)), T: big.NewInt(1)}).WireBytes()
+	assert.NoError(t, err)
+	_, err = ParseWireMessage(many, pIDs[0], true)
+	if assert.Error(t, err, "a message with more fields than the limit must be rejected") {
+		assert.Contains(t, err.Error(), "more than 2 fields")
+	}
+}
+
+func TestMessageTypes(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(2)
+	types := NewMessageTypes(&keygen.KGRound1Message{}, &keygen.KGRound2Message1{})
+	assert.True(t, types.Contains(proto.MessageName(&keygen.KGRound1Message{})))
+	assert.False(t, types.Contains(proto.MessageName(&signing.SignRound1Message{})))
+
+	bz, _, err := keygen.NewKGRound1Message(pIDs[0], big.NewInt(1)).WireBytes()
+	assert.NoError(t, err)
+	_, err = types.ParseWireMessage(bz, pIDs[0], true)
+	assert.NoError(t, err)
+
+	bz, _, err = signing.NewSignRound3Message(pIDs[0], big.NewInt(42)).WireBytes()
+	assert.NoError(t, err)
+	_, err = ParseWireMessage(bz, pIDs[0], true)
+	assert.NoError(t, err)
+	_, err = types.ParseWireMessage(bz, pIDs[0], true)
+	if assert.Error(t, err, "a message of another protocol must be rejected before it is decoded") {
+		assert.Contains(t, err.Error(), "SignRound3Message")
+	}
+
+	// a party of a protocol accepts only its messages
+	P := keygen.NewLocalParty(NewParameters(Edwards(), NewPeerContext(pIDs), pIDs[1], len(pIDs), 1), nil, nil)
+	_, tssErr := P.UpdateFromBytes(bz, pIDs[0], true)
+	assert.NotNil(t, tssErr)
+}